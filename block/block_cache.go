@@ -3,6 +3,7 @@ package block
 import (
 	"sync"
 
+	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -11,16 +12,19 @@ type BlockCache struct {
 	blocks     map[uint64]*types.Block
 	hashes     map[string]bool
 	daIncluded map[string]bool
-	mtx        *sync.RWMutex
+	// daLocations are locations of blocks on the DA layer, by block hash
+	daLocations map[string]store.DALocation
+	mtx         *sync.RWMutex
 }
 
 // NewBlockCache returns a new BlockCache struct
 func NewBlockCache() *BlockCache {
 	return &BlockCache{
-		blocks:     make(map[uint64]*types.Block),
-		hashes:     make(map[string]bool),
-		daIncluded: make(map[string]bool),
-		mtx:        new(sync.RWMutex),
+		blocks:      make(map[uint64]*types.Block),
+		hashes:      make(map[string]bool),
+		daIncluded:  make(map[string]bool),
+		daLocations: make(map[string]store.DALocation),
+		mtx:         new(sync.RWMutex),
 	}
 }
 
@@ -66,3 +70,16 @@ func (bc *BlockCache) setDAIncluded(hash string) {
 	defer bc.mtx.Unlock()
 	bc.daIncluded[hash] = true
 }
+
+func (bc *BlockCache) getDALocation(hash string) (store.DALocation, bool) {
+	bc.mtx.RLock()
+	defer bc.mtx.RUnlock()
+	loc, ok := bc.daLocations[hash]
+	return loc, ok
+}
+
+func (bc *BlockCache) setDALocation(hash string, loc store.DALocation) {
+	bc.mtx.Lock()
+	defer bc.mtx.Unlock()
+	bc.daLocations[hash] = loc
+}
@@ -2,7 +2,6 @@ package block
 
 import (
 	"context"
-	"encoding/hex"
 	"errors"
 	"fmt"
 
@@ -17,10 +16,14 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 
+	"github.com/rollkit/rollkit/clock"
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/crypto/bls"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/tracing"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -28,17 +31,23 @@ import (
 // go-header interface.  Contains a block store where synced blocks are stored.
 // Uses the go-header library for handling all P2P logic.
 type BlockSyncService struct {
-	conf       config.NodeConfig
-	genesis    *cmtypes.GenesisDoc
-	p2p        *p2p.Client
-	ex         *goheaderp2p.Exchange[*types.Block]
-	sub        *goheaderp2p.Subscriber[*types.Block]
-	p2pServer  *goheaderp2p.ExchangeServer[*types.Block]
-	blockStore *goheaderstore.Store[*types.Block]
+	conf    config.NodeConfig
+	genesis *cmtypes.GenesisDoc
+	p2p     *p2p.Client
+	// aggregatorKeys are BLS keys of aggregators, verified against keys carried by gossiped blocks
+	aggregatorKeys []bls.PubKey
+	ex             *goheaderp2p.Exchange[*types.Block]
+	sub            *validatingSubscriber[*types.Block]
+	p2pServer      *goheaderp2p.ExchangeServer[*types.Block]
+	blockStore     *goheaderstore.Store[*types.Block]
+	datastore      ds.Batching
 
 	syncer       *goheadersync.Syncer[*types.Block]
 	syncerStatus *SyncerStatus
 
+	// gossipedBlocks passes blocks received via P2P gossip to the block manager, without waiting for the syncer
+	gossipedBlocks chan *types.Block
+
 	logger log.Logger
 	ctx    context.Context
 }
@@ -57,19 +66,26 @@ func NewBlockSyncService(ctx context.Context, store ds.TxnDatastore, conf config
 	if !ok {
 		return nil, errors.New("failed to access the datastore")
 	}
-	ss, err := goheaderstore.NewStore[*types.Block](storeBatch, goheaderstore.WithStorePrefix("blockSync"))
+	ss, err := goheaderstore.NewStore[*types.Block](storeBatch, goheaderstore.WithStorePrefix(blockSyncPrefix))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize the block store: %w", err)
 	}
+	aggregatorKeys, err := parseAggregatorKeys(conf.AggregatorKeys)
+	if err != nil {
+		return nil, err
+	}
 
 	return &BlockSyncService{
-		conf:         conf,
-		genesis:      genesis,
-		p2p:          p2p,
-		ctx:          ctx,
-		blockStore:   ss,
-		logger:       logger,
-		syncerStatus: new(SyncerStatus),
+		conf:           conf,
+		genesis:        genesis,
+		p2p:            p2p,
+		aggregatorKeys: aggregatorKeys,
+		ctx:            ctx,
+		datastore:      storeBatch,
+		blockStore:     ss,
+		logger:         logger,
+		syncerStatus:   new(SyncerStatus),
+		gossipedBlocks: make(chan *types.Block, channelLength),
 	}, nil
 }
 
@@ -78,6 +94,41 @@ func (bSyncService *BlockSyncService) BlockStore() *goheaderstore.Store[*types.B
 	return bSyncService.blockStore
 }
 
+// EarliestHeight returns the height of the earliest block in the block store, or 0 if the store is empty.
+func (bSyncService *BlockSyncService) EarliestHeight(ctx context.Context) (uint64, error) {
+	return earliestSyncHeight(ctx, bSyncService.blockStore, bSyncService.datastore, blockSyncPrefix)
+}
+
+// Prune deletes blocks below retainHeight from the block store, and returns the number of pruned blocks.
+// The head of the store is always kept.
+func (bSyncService *BlockSyncService) Prune(ctx context.Context, retainHeight uint64) (uint64, error) {
+	return pruneSyncStore(ctx, bSyncService.blockStore, bSyncService.datastore, blockSyncPrefix, retainHeight)
+}
+
+// GossipedBlocks returns a channel of blocks received via P2P gossip, passed as soon as they are validated.
+// Blocks are dropped if the channel is full; they are still available in the block store.
+func (bSyncService *BlockSyncService) GossipedBlocks() <-chan *types.Block {
+	return bSyncService.gossipedBlocks
+}
+
+func (bSyncService *BlockSyncService) forwardGossipedBlocks(subscription header.Subscription[*types.Block]) {
+	defer subscription.Cancel()
+	for {
+		block, err := subscription.NextHeader(bSyncService.ctx)
+		if err != nil {
+			if bSyncService.ctx.Err() == nil {
+				bSyncService.logger.Error("failed to receive gossiped block", "error", err)
+			}
+			return
+		}
+		select {
+		case bSyncService.gossipedBlocks <- block:
+		default:
+			bSyncService.logger.Debug("dropping gossiped block", "height", block.Height())
+		}
+	}
+}
+
 func (bSyncService *BlockSyncService) initBlockStoreAndStartSyncer(ctx context.Context, initial *types.Block) error {
 	if initial == nil {
 		return fmt.Errorf("failed to initialize the blockstore and start syncer")
@@ -96,6 +147,9 @@ func (bSyncService *BlockSyncService) initBlockStoreAndStartSyncer(ctx context.C
 // Note: Only returns an error in case block store can't be initialized. Logs
 // error if there's one while broadcasting.
 func (bSyncService *BlockSyncService) WriteToBlockStoreAndBroadcast(ctx context.Context, block *types.Block) error {
+	ctx, span := tracing.Start(ctx, "BlockSyncService.Broadcast", attribute.Int64("height", int64(block.Height())))
+	defer span.End()
+
 	// For genesis block initialize the store and start the syncer
 	if int64(block.Height()) == bSyncService.genesis.InitialHeight {
 		if err := bSyncService.blockStore.Init(ctx, block); err != nil {
@@ -110,6 +164,7 @@ func (bSyncService *BlockSyncService) WriteToBlockStoreAndBroadcast(ctx context.
 	// Broadcast for subscribers
 	if err := bSyncService.sub.Broadcast(ctx, block); err != nil {
 		bSyncService.logger.Error("failed to broadcast block", "error", err)
+		span.RecordError(err)
 	}
 	return nil
 }
@@ -124,8 +179,7 @@ func (bSyncService *BlockSyncService) Start() error {
 	ps := bSyncService.p2p.PubSub()
 	chainIDBlock := bSyncService.genesis.ChainID + "-block"
 
-	var err error
-	bSyncService.sub, err = goheaderp2p.NewSubscriber[*types.Block](
+	sub, err := goheaderp2p.NewSubscriber[*types.Block](
 		ps,
 		pubsub.DefaultMsgIdFn,
 		goheaderp2p.WithSubscriberNetworkID(chainIDBlock),
@@ -133,13 +187,19 @@ func (bSyncService *BlockSyncService) Start() error {
 	if err != nil {
 		return err
 	}
+	bSyncService.sub, err = newValidatingSubscriber(sub, ps, chainIDBlock, bSyncService.validateBlock)
+	if err != nil {
+		return err
+	}
 
 	if err := bSyncService.sub.Start(bSyncService.ctx); err != nil {
 		return fmt.Errorf("error while starting subscriber: %w", err)
 	}
-	if _, err := bSyncService.sub.Subscribe(); err != nil {
+	subscription, err := bSyncService.sub.Subscribe()
+	if err != nil {
 		return fmt.Errorf("error while subscribing: %w", err)
 	}
+	go bSyncService.forwardGossipedBlocks(subscription)
 
 	if err := bSyncService.blockStore.Start(bSyncService.ctx); err != nil {
 		return fmt.Errorf("error while starting block store: %w", err)
@@ -166,6 +226,12 @@ func (bSyncService *BlockSyncService) Start() error {
 		return fmt.Errorf("error while starting exchange: %w", err)
 	}
 
+	// in headers-first sync mode, blocks are fetched by the block manager by height ranges, instead of syncing
+	// the complete block store
+	if bSyncService.conf.SyncMode == config.SyncModeHeadersFirst {
+		return nil
+	}
+
 	if bSyncService.syncer, err = newBlockSyncer(
 		bSyncService.ex,
 		bSyncService.blockStore,
@@ -182,32 +248,39 @@ func (bSyncService *BlockSyncService) Start() error {
 		return nil
 	}
 
-	// Look to see if trusted hash is passed, if not get the genesis block
-	var trustedBlock *types.Block
+	fetch := func(ctx context.Context, ex header.Exchange[*types.Block]) (*types.Block, error) {
+		return fetchInitial[*types.Block](ctx, ex, bSyncService.conf.TrustedHash, uint64(bSyncService.genesis.InitialHeight), "block")
+	}
 	// Try fetching the trusted block from peers if exists
 	if len(peerIDs) > 0 {
-		if bSyncService.conf.TrustedHash != "" {
-			trustedHashBytes, err := hex.DecodeString(bSyncService.conf.TrustedHash)
-			if err != nil {
-				return fmt.Errorf("failed to parse the trusted hash for initializing the blockstore: %w", err)
-			}
-
-			if trustedBlock, err = bSyncService.ex.Get(bSyncService.ctx, header.Hash(trustedHashBytes)); err != nil {
-				return fmt.Errorf("failed to fetch the trusted block for initializing the blockStore: %w", err)
-			}
-		} else {
-			// Try fetching the genesis block if available, otherwise fallback to blocks
-			if trustedBlock, err = bSyncService.ex.GetByHeight(bSyncService.ctx, uint64(bSyncService.genesis.InitialHeight)); err != nil {
-				// Full/light nodes have to wait for aggregator to publish the genesis block
-				// proposing aggregator can init the store and start the syncer when the first block is published
-				return fmt.Errorf("failed to fetch the genesis block: %w", err)
-			}
+		trustedBlock, err := fetch(bSyncService.ctx, bSyncService.ex)
+		if err != nil {
+			return err
 		}
 		return bSyncService.initBlockStoreAndStartSyncer(bSyncService.ctx, trustedBlock)
 	}
+	// aggregator initializes the store when the first block is published
+	if !bSyncService.conf.Aggregator {
+		newExchange := func(peers []peer.ID) (*goheaderp2p.Exchange[*types.Block], error) {
+			return newBlockP2PExchange(bSyncService.p2p.Host(), peers, networkIDBlock, chainIDBlock, bSyncService.p2p.ConnectionGater())
+		}
+		go initWhenConnected(bSyncService.ctx, bSyncService.p2p, bSyncService.isInitialized, newExchange, fetch,
+			bSyncService.initBlockStoreAndStartSyncer, bSyncService.logger)
+	}
 	return nil
 }
 
+// validateBlock checks if the block received from peers is valid and belongs to the chain.
+func (bSyncService *BlockSyncService) validateBlock(block *types.Block) error {
+	if err := validateHeaderTime(clock.Real.Now(), bSyncService.conf.MaxFutureTime, &block.SignedHeader); err != nil {
+		return err
+	}
+	if err := validateGossipedBlock(bSyncService.genesis, bSyncService.conf.CommitThreshold, bSyncService.aggregatorKeys, block); err != nil {
+		return err
+	}
+	return validateWithStoredLast(bSyncService.ctx, bSyncService.blockStore, uint64(bSyncService.genesis.InitialHeight), block, block.ValidateWithLast)
+}
+
 // Stop is a part of Service interface.
 func (bSyncService *BlockSyncService) Stop() error {
 	err := bSyncService.blockStore.Stop(bSyncService.ctx)
@@ -0,0 +1,98 @@
+//go:build byzantine
+
+package block
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ByzantineBehavior configures misbehavior of the aggregator. It's intended only for testing how fraud proofs,
+// evidence of equivocation, sync and light clients handle a malicious aggregator, and it's compiled only with
+// the byzantine build tag, so production binaries can't misbehave.
+type ByzantineBehavior struct {
+	// Equivocate makes the aggregator sign and gossip a conflicting block at every height after the initial one.
+	Equivocate bool
+	// WithholdData makes the aggregator skip submission of blocks to the DA layer, while still gossiping them.
+	WithholdData bool
+	// InvalidStateRoot makes the aggregator publish blocks with corrupted state roots: intermediate state roots,
+	// if they're enabled, or AppHash of the header otherwise.
+	InvalidStateRoot bool
+	// Censor makes the aggregator exclude transactions for which it returns true from produced blocks.
+	Censor func(tx types.Tx) bool
+}
+
+type byzantineBehavior = ByzantineBehavior
+
+// SetByzantineBehavior makes the aggregator misbehave as configured. It's intended only for testing,
+// and has to be called before the aggregator starts producing blocks.
+func (m *Manager) SetByzantineBehavior(b *ByzantineBehavior) {
+	m.byzantine = b
+}
+
+// censorTxs removes censored transactions from the block.
+func (b *ByzantineBehavior) censorTxs(block *types.Block) {
+	if b == nil || b.Censor == nil {
+		return
+	}
+	txs := block.Data.Txs[:0]
+	for _, tx := range block.Data.Txs {
+		if !b.Censor(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	block.Data.Txs = txs
+}
+
+// withholdData returns true if blocks shouldn't be submitted to the DA layer.
+func (b *ByzantineBehavior) withholdData() bool {
+	return b != nil && b.WithholdData
+}
+
+// corruptStateRoots corrupts the last intermediate state root of the block, or the AppHash of the header
+// if the block has no intermediate state roots. It returns true if the header was corrupted.
+func (b *ByzantineBehavior) corruptStateRoots(block *types.Block) bool {
+	if b == nil || !b.InvalidStateRoot {
+		return false
+	}
+	if isrs := block.Data.IntermediateStateRoots.RawRootsList; len(isrs) > 0 {
+		isrs[len(isrs)-1] = corrupt(isrs[len(isrs)-1])
+		return false
+	}
+	block.SignedHeader.AppHash = corrupt(block.SignedHeader.AppHash)
+	return true
+}
+
+func corrupt(root []byte) []byte {
+	corrupted := make([]byte, len(root))
+	copy(corrupted, root)
+	if len(corrupted) == 0 {
+		return []byte{0xff}
+	}
+	corrupted[0] ^= 0xff
+	return corrupted
+}
+
+// equivocate returns a block conflicting with the given block, signed by the aggregator.
+func (m *Manager) equivocate(ctx context.Context, block *types.Block) (*types.Block, error) {
+	if m.byzantine == nil || !m.byzantine.Equivocate || block.Height() <= uint64(m.genesis.InitialHeight) {
+		return nil, nil
+	}
+	bz, err := block.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	conflicting := new(types.Block)
+	if err := conflicting.UnmarshalBinary(bz); err != nil {
+		return nil, err
+	}
+	conflicting.SignedHeader.BaseHeader.Time++
+	commit, err := m.getCommit(ctx, conflicting.SignedHeader.Header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign conflicting block: %w", err)
+	}
+	conflicting.SignedHeader.Commit = *commit
+	return conflicting, nil
+}
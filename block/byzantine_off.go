@@ -0,0 +1,26 @@
+//go:build !byzantine
+
+package block
+
+import (
+	"context"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// byzantineBehavior never makes the aggregator misbehave; misbehavior is compiled only with the byzantine build tag.
+type byzantineBehavior struct{}
+
+func (b *byzantineBehavior) censorTxs(*types.Block) {}
+
+func (b *byzantineBehavior) withholdData() bool {
+	return false
+}
+
+func (b *byzantineBehavior) corruptStateRoots(*types.Block) bool {
+	return false
+}
+
+func (m *Manager) equivocate(context.Context, *types.Block) (*types.Block, error) {
+	return nil, nil
+}
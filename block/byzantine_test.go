@@ -0,0 +1,85 @@
+//go:build byzantine
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestByzantineCensorTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	block := types.GetRandomBlock(1, 4)
+	censored := block.Data.Txs[1]
+	var nilBehavior *ByzantineBehavior
+	nilBehavior.censorTxs(block)
+	assert.Len(block.Data.Txs, 4)
+
+	b := &ByzantineBehavior{Censor: func(tx types.Tx) bool { return bytes.Equal(tx, censored) }}
+	b.censorTxs(block)
+	assert.Len(block.Data.Txs, 3)
+	assert.NotContains(block.Data.Txs, censored)
+}
+
+func TestByzantineCorruptStateRoots(t *testing.T) {
+	assert := assert.New(t)
+	b := &ByzantineBehavior{InvalidStateRoot: true}
+
+	block := types.GetRandomBlock(1, 2)
+	appHash := append([]byte(nil), block.SignedHeader.AppHash...)
+	assert.False((&ByzantineBehavior{}).corruptStateRoots(block))
+	assert.True(b.corruptStateRoots(block))
+	assert.NotEqual(appHash, []byte(block.SignedHeader.AppHash))
+
+	block = types.GetRandomBlock(1, 2)
+	isr := types.GetRandomBytes(32)
+	block.Data.IntermediateStateRoots.RawRootsList = [][]byte{types.GetRandomBytes(32), isr}
+	appHash = append([]byte(nil), block.SignedHeader.AppHash...)
+	assert.False(b.corruptStateRoots(block))
+	assert.Equal(appHash, []byte(block.SignedHeader.AppHash))
+	assert.NotEqual(isr, block.Data.IntermediateStateRoots.RawRootsList[1])
+}
+
+func TestByzantineEquivocate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	m := &Manager{
+		signer:       signer.NewLocalSigner(key),
+		genesis:      &cmtypes.GenesisDoc{InitialHeight: 1},
+		lastStateMtx: new(sync.RWMutex),
+	}
+
+	block := types.GetRandomBlock(2, 2)
+	conflicting, err := m.equivocate(ctx, block)
+	require.NoError(err)
+	assert.Nil(conflicting)
+
+	m.SetByzantineBehavior(&ByzantineBehavior{Equivocate: true})
+	conflicting, err = m.equivocate(ctx, types.GetRandomBlock(1, 2))
+	require.NoError(err)
+	assert.Nil(conflicting)
+
+	conflicting, err = m.equivocate(ctx, block)
+	require.NoError(err)
+	require.NotNil(conflicting)
+	assert.Equal(block.Height(), conflicting.Height())
+	assert.Equal(block.Data.Txs, conflicting.Data.Txs)
+	assert.NotEqual(block.Hash(), conflicting.Hash())
+	assert.NotEqual(block.SignedHeader.Commit, conflicting.SignedHeader.Commit)
+}
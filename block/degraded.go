@@ -0,0 +1,77 @@
+package block
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rollkit/rollkit/da"
+)
+
+// ErrDAUnavailable is returned when blocks can't be submitted to DA layer, and the node is in degraded mode.
+var ErrDAUnavailable = errors.New("DA layer unavailable")
+
+// IsDADegraded returns true if the node is in degraded mode, because DA layer is unavailable. In degraded mode
+// blocks are still produced and gossiped (soft confirmations), but their submission to DA layer is queued until
+// the node reconnects to DA layer.
+func (m *Manager) IsDADegraded() bool {
+	return m.daDegraded.Load()
+}
+
+// enterDADegradedMode switches the node into degraded mode, after all attempts to submit blocks failed.
+func (m *Manager) enterDADegradedMode(reason string) {
+	if m.daDegraded.Swap(true) {
+		return
+	}
+	m.daReconnectBackoff = m.conf.DABlockTime
+	m.daReconnectAt = m.clock.Now().Add(m.daReconnectBackoff)
+	m.metrics.DADegraded.Set(1)
+	m.logger.Error("DA layer unavailable, entering degraded mode: blocks are gossiped, but not submitted to DA layer",
+		"error", reason, "pendingBlocks", m.pendingBlocks.numPendingBlocks())
+}
+
+// leaveDADegradedMode switches the node back into normal mode, after blocks were submitted to DA layer.
+func (m *Manager) leaveDADegradedMode() {
+	if !m.daDegraded.Swap(false) {
+		return
+	}
+	m.metrics.DADegraded.Set(0)
+	m.logger.Info("reconnected to DA layer, leaving degraded mode", "pendingBlocks", m.pendingBlocks.numPendingBlocks())
+}
+
+// daReconnectDue returns true if blocks should be submitted to DA layer. In degraded mode, reconnection is attempted
+// with exponential backoff (up to DAReconnectInterval), and only if DA layer reports to be healthy.
+func (m *Manager) daReconnectDue(ctx context.Context) bool {
+	if !m.daDegraded.Load() {
+		return true
+	}
+	now := m.clock.Now()
+	if now.Before(m.daReconnectAt) {
+		return false
+	}
+	m.daReconnectBackoff *= 2
+	if maxInterval := max(m.conf.DAReconnectInterval, m.conf.DABlockTime); m.daReconnectBackoff > maxInterval {
+		m.daReconnectBackoff = maxInterval
+	}
+	m.daReconnectAt = now.Add(m.daReconnectBackoff)
+	if checker, ok := m.dalc.(da.HealthChecker); ok {
+		if err := checker.CheckHealth(ctx); err != nil {
+			m.logger.Debug("DA layer is still unavailable", "error", err, "nextAttempt", m.daReconnectAt)
+			return false
+		}
+	}
+	return true
+}
+
+// pendingBlocksFull returns true if the number of blocks waiting for submission to DA layer reached
+// DAMaxPendingBlocks, and block production has to stop. It has to be called with blockMtx held.
+func (m *Manager) pendingBlocksFull() bool {
+	limit := m.conf.DAMaxPendingBlocks
+	full := limit > 0 && uint64(m.pendingBlocks.numPendingBlocks()) >= limit
+	if full && !m.pendingLimitReached {
+		m.logger.Error("too many blocks waiting for submission to DA layer, block production stopped", "limit", limit)
+	} else if !full && m.pendingLimitReached {
+		m.logger.Info("block production resumed", "pendingBlocks", m.pendingBlocks.numPendingBlocks())
+	}
+	m.pendingLimitReached = full
+	return full
+}
@@ -0,0 +1,104 @@
+package block
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// unavailableDA is a DA layer client, that can be brought down and up.
+type unavailableDA struct {
+	da.DataAvailabilityLayerClient
+	down      bool
+	unhealthy bool
+	submits   int
+}
+
+func (d *unavailableDA) SubmitBlocks(_ context.Context, _ []*types.Block) da.ResultSubmitBlocks {
+	d.submits++
+	if d.down {
+		return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: "connection refused"}}
+	}
+	return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: 1}}
+}
+
+func (d *unavailableDA) CheckHealth(context.Context) error {
+	if d.unhealthy {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestDADegradedMode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	c := &fakeClock{now: time.Unix(1700000000, 0)}
+	dalc := &unavailableDA{down: true, unhealthy: true}
+	m := &Manager{
+		conf: config.BlockManagerConfig{
+			DABlockTime:         time.Millisecond,
+			DAReconnectInterval: 4 * time.Millisecond,
+			DAMaxPendingBlocks:  3,
+		},
+		store:         store.New(ctx, kv),
+		dalc:          dalc,
+		clock:         c,
+		blockCache:    NewBlockCache(),
+		pendingBlocks: NewPendingBlocks(),
+		metrics:       NopMetrics(),
+		logger:        test.NewFileLogger(t),
+	}
+	m.pendingBlocks.addPendingBlock(types.GetRandomBlock(1, 1))
+	m.pendingBlocks.addPendingBlock(types.GetRandomBlock(2, 1))
+	assert.True(m.daReconnectDue(ctx))
+	assert.False(m.pendingBlocksFull())
+
+	// all attempts fail, blocks are kept
+	err = m.submitBlocksToDA(ctx)
+	assert.ErrorIs(err, ErrDAUnavailable)
+	assert.True(m.IsDADegraded())
+	assert.Equal(maxSubmitAttempts, dalc.submits)
+	assert.Equal(2, m.NumPendingBlocks())
+
+	// reconnection is attempted with backoff, if DA layer is healthy
+	assert.False(m.daReconnectDue(ctx))
+	c.now = c.now.Add(2 * time.Millisecond)
+	assert.False(m.daReconnectDue(ctx))
+	dalc.unhealthy = false
+	assert.False(m.daReconnectDue(ctx))
+	c.now = c.now.Add(2 * time.Millisecond)
+	assert.True(m.daReconnectDue(ctx))
+
+	// single attempt in degraded mode
+	err = m.submitBlocksToDA(ctx)
+	assert.ErrorIs(err, ErrDAUnavailable)
+	assert.Equal(maxSubmitAttempts+1, dalc.submits)
+
+	// queue is bounded
+	m.pendingBlocks.addPendingBlock(types.GetRandomBlock(3, 1))
+	assert.True(m.pendingBlocksFull())
+
+	// backoff is limited by DAReconnectInterval
+	c.now = c.now.Add(4 * time.Millisecond)
+	assert.True(m.daReconnectDue(ctx))
+	dalc.down = false
+	require.NoError(m.submitBlocksToDA(ctx))
+	assert.False(m.IsDADegraded())
+	assert.Zero(m.NumPendingBlocks())
+	assert.False(m.pendingBlocksFull())
+	assert.True(m.daReconnectDue(ctx))
+}
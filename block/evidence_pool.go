@@ -0,0 +1,91 @@
+package block
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// evidencePool keeps verified evidence of misbehavior of aggregators, until it's committed in a block.
+//
+// Hashes of committed evidence are kept until the evidence expires, to avoid committing it again.
+type evidencePool struct {
+	mtx       sync.Mutex
+	pending   map[string]*types.DuplicateHeaderEvidence
+	committed map[string]uint64
+}
+
+func newEvidencePool() *evidencePool {
+	return &evidencePool{
+		pending:   make(map[string]*types.DuplicateHeaderEvidence),
+		committed: make(map[string]uint64),
+	}
+}
+
+// add adds evidence to the pool, and returns false if it's already pending or committed.
+func (p *evidencePool) add(ev *types.DuplicateHeaderEvidence) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	hash := ev.Hash().String()
+	if _, ok := p.pending[hash]; ok {
+		return false
+	}
+	if _, ok := p.committed[hash]; ok {
+		return false
+	}
+	p.pending[hash] = ev
+	return true
+}
+
+// pendingEvidence returns pending evidence, oldest first, with total size of binary encodings up to maxBytes.
+func (p *evidencePool) pendingEvidence(maxBytes int64) []types.DuplicateHeaderEvidence {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	evidence := make([]types.DuplicateHeaderEvidence, 0, len(p.pending))
+	for _, ev := range p.pending {
+		evidence = append(evidence, *ev)
+	}
+	sort.Slice(evidence, func(i, j int) bool {
+		if evidence[i].Height() != evidence[j].Height() {
+			return evidence[i].Height() < evidence[j].Height()
+		}
+		return evidence[i].Hash().String() < evidence[j].Hash().String()
+	})
+	var size int64
+	for i := range evidence {
+		bz, err := evidence[i].MarshalBinary()
+		if err != nil || size+int64(len(bz)) > maxBytes {
+			return evidence[:i]
+		}
+		size += int64(len(bz))
+	}
+	return evidence
+}
+
+// markCommitted removes evidence committed in a block from pending evidence.
+func (p *evidencePool) markCommitted(evidence []types.DuplicateHeaderEvidence) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for i := range evidence {
+		hash := evidence[i].Hash().String()
+		delete(p.pending, hash)
+		p.committed[hash] = evidence[i].Height()
+	}
+}
+
+// prune removes evidence for heights lower than minHeight, that can't be committed anymore.
+func (p *evidencePool) prune(minHeight uint64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for hash, ev := range p.pending {
+		if ev.Height() < minHeight {
+			delete(p.pending, hash)
+		}
+	}
+	for hash, height := range p.committed {
+		if height < minHeight {
+			delete(p.committed, hash)
+		}
+	}
+}
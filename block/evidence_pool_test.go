@@ -0,0 +1,46 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestEvidencePool(t *testing.T) {
+	require := require.New(t)
+	pool := newEvidencePool()
+
+	ev1, err := types.GetRandomDuplicateHeaderEvidence()
+	require.NoError(err)
+	ev2, err := types.GetRandomDuplicateHeaderEvidence()
+	require.NoError(err)
+
+	require.True(pool.add(ev1))
+	require.False(pool.add(ev1), "duplicated evidence should not be added")
+	require.True(pool.add(ev2))
+	require.Len(pool.pendingEvidence(1<<20), 2)
+
+	// evidence exceeding max bytes is not returned
+	bz, err := ev1.MarshalBinary()
+	require.NoError(err)
+	require.Len(pool.pendingEvidence(int64(len(bz))), 1)
+	require.Empty(pool.pendingEvidence(0))
+
+	// committed evidence is not pending, and can't be added again
+	pool.markCommitted([]types.DuplicateHeaderEvidence{*ev1})
+	pending := pool.pendingEvidence(1 << 20)
+	require.Len(pending, 1)
+	require.Equal(ev2.Hash(), pending[0].Hash())
+	require.False(pool.add(ev1))
+
+	// expired evidence is pruned
+	minHeight := ev1.Height() + 1
+	if ev2.Height() >= minHeight {
+		minHeight = ev2.Height() + 1
+	}
+	pool.prune(minHeight)
+	require.Empty(pool.pendingEvidence(1 << 20))
+	require.True(pool.add(ev1))
+}
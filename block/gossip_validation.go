@@ -0,0 +1,218 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/celestiaorg/go-header"
+	goheaderp2p "github.com/celestiaorg/go-header/p2p"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	cmtypes "github.com/cometbft/cometbft/types"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/types"
+)
+
+var (
+	// ErrWrongChainID is returned when gossiped header (or block) belongs to different chain.
+	ErrWrongChainID = errors.New("wrong chain ID")
+	// ErrHeightBelowInitial is returned when height of gossiped header (or block) is below the initial height.
+	ErrHeightBelowInitial = errors.New("height below initial height")
+	// ErrUnsignedHeader is returned when gossiped header doesn't contain aggregator set required to verify signature.
+	ErrUnsignedHeader = errors.New("header without aggregator set")
+	// ErrHeaderFromFuture is returned when time of header (or block) is ahead of the clock of the node
+	// by more than the configured maximum.
+	ErrHeaderFromFuture = errors.New("header from the future")
+	// ErrAggregatorKeysMismatch is returned when BLS keys of aggregators carried by the header (or aggregated
+	// signature of its commit) don't match BLS keys configured for the chain.
+	ErrAggregatorKeysMismatch = errors.New("aggregator keys mismatch")
+)
+
+// validateGossipedHeader checks if gossiped header belongs to the chain described by genesis, and if it's proposed
+// and signed by a member of the aggregator set (and by aggregators with more than threshold of the voting power,
+// if the threshold is set). Aggregated signatures are verified only against the configured aggregator keys.
+//
+// Basic validity of the header is checked by ValidateBasic, called by go-header before this function.
+func validateGossipedHeader(genesis *cmtypes.GenesisDoc, threshold cmtmath.Fraction, aggregatorKeys []bls.PubKey, sh *types.SignedHeader) error {
+	if sh.ChainID() != genesis.ChainID {
+		return fmt.Errorf("%w: expected %q, got %q", ErrWrongChainID, genesis.ChainID, sh.ChainID())
+	}
+	if sh.Height() < uint64(genesis.InitialHeight) {
+		return fmt.Errorf("%w: %d < %d", ErrHeightBelowInitial, sh.Height(), genesis.InitialHeight)
+	}
+	// ValidateBasic skips signature verification if aggregator set is empty (based rollups)
+	if len(genesis.Validators) > 0 && (sh.Validators == nil || len(sh.Validators.Validators) == 0) {
+		return ErrUnsignedHeader
+	}
+	if err := verifyAggregatorKeys(aggregatorKeys, sh); err != nil {
+		return err
+	}
+	if err := sh.VerifyProposer(); err != nil {
+		return err
+	}
+	if err := verifyRecoveredProposer(genesis, sh); err != nil {
+		return err
+	}
+	return verifyCommitThreshold(threshold, sh)
+}
+
+// verifyAggregatorKeys checks that BLS keys of aggregators carried by the header are the keys configured for the
+// chain, so aggregated signatures are never verified against keys supplied by the sender of the header. Without
+// configured keys, headers can't carry aggregated signatures.
+func verifyAggregatorKeys(expected []bls.PubKey, sh *types.SignedHeader) error {
+	if len(expected) == 0 {
+		if len(sh.Commit.AggregatedSignature) > 0 || len(sh.AggregatorKeys) > 0 || len(sh.AggregatorKeysHash) > 0 {
+			return fmt.Errorf("%w: aggregated signature without configured BLS keys", ErrAggregatorKeysMismatch)
+		}
+		return nil
+	}
+	if len(sh.Commit.AggregatedSignature) == 0 {
+		return fmt.Errorf("%w: commit without aggregated signature", ErrAggregatorKeysMismatch)
+	}
+	if len(sh.AggregatorKeys) != len(expected) {
+		return fmt.Errorf("%w: got %d keys, expected %d", ErrAggregatorKeysMismatch, len(sh.AggregatorKeys), len(expected))
+	}
+	for i, key := range expected {
+		if !bytes.Equal(sh.AggregatorKeys[i], key) {
+			return fmt.Errorf("%w: key %d differs", ErrAggregatorKeysMismatch, i)
+		}
+	}
+	if !bytes.Equal(sh.AggregatorKeysHash, types.AggregatorKeysHash(expected)) {
+		return fmt.Errorf("%w: header commits to different keys", ErrAggregatorKeysMismatch)
+	}
+	return nil
+}
+
+// verifyCommitThreshold checks that aggregators with more than threshold of the voting power signed the header.
+// Aggregators don't co-sign blocks yet, so with zero threshold only the signature of the proposer (checked by
+// VerifyProposer) is required.
+func verifyCommitThreshold(threshold cmtmath.Fraction, sh *types.SignedHeader) error {
+	if threshold.Denominator == 0 {
+		return nil
+	}
+	return sh.VerifyCommit(threshold)
+}
+
+// verifyRecoveredProposer checks ProposerAddress of the header without aggregator set against public key recovered
+// from the signature, if the signature scheme of the chain allows it. Headers with aggregator set are verified
+// against the set instead.
+func verifyRecoveredProposer(genesis *cmtypes.GenesisDoc, sh *types.SignedHeader) error {
+	if sh.Validators != nil && len(sh.Validators.Validators) > 0 {
+		return nil
+	}
+	if _, err := sh.RecoverProposer(signer.SchemeFromGenesis(genesis)); err != nil && !errors.Is(err, signer.ErrNotRecoverable) {
+		return err
+	}
+	return nil
+}
+
+// validateHeaderTime checks that time of the header is not ahead of now by more than maxFutureTime.
+// Zero maxFutureTime disables the check.
+func validateHeaderTime(now time.Time, maxFutureTime time.Duration, sh *types.SignedHeader) error {
+	if maxFutureTime > 0 && sh.Time().After(now.Add(maxFutureTime)) {
+		return fmt.Errorf("%w: header time %s, local time %s, max future time %s",
+			ErrHeaderFromFuture, sh.Time().Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), maxFutureTime)
+	}
+	return nil
+}
+
+// validateGossipedBlock checks if gossiped block belongs to the chain described by genesis.
+//
+// Basic validity of the block (including data hash and header signature) is checked by ValidateBasic,
+// called by go-header before this function.
+func validateGossipedBlock(genesis *cmtypes.GenesisDoc, threshold cmtmath.Fraction, aggregatorKeys []bls.PubKey, block *types.Block) error {
+	return validateGossipedHeader(genesis, threshold, aggregatorKeys, &block.SignedHeader)
+}
+
+// validateWithStoredLast checks that gossiped header (or block) directly follows the previous one, with validate
+// (ValidateWithLast of the header or block), if the previous one is already in the local store of the sync service.
+// Headers (and blocks) above the height of the store are linked to the chain by the syncer, and blocks are validated
+// again before they're applied.
+func validateWithStoredLast[H header.Header[H]](ctx context.Context, store header.Store[H], initialHeight uint64, h H, validate func(last H) error) error {
+	if h.Height() <= initialHeight || !store.HasAt(ctx, h.Height()-1) {
+		return nil
+	}
+	last, err := store.GetByHeight(ctx, h.Height()-1)
+	if errors.Is(err, header.ErrNotFound) {
+		// pruned
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load last header: %w", err)
+	}
+	return validate(last)
+}
+
+// validatingSubscriber wraps go-header subscriber, to validate gossiped headers (or blocks) before they are
+// relayed to other peers.
+//
+// Validation is registered as pubsub topic validator at start, so messages are validated even if the syncer
+// is not running yet. When the syncer is started, its verification is chained after the validation.
+type validatingSubscriber[H header.Header[H]] struct {
+	*goheaderp2p.Subscriber[H]
+
+	ps       *pubsub.PubSub
+	topic    string
+	validate func(H) error
+
+	// verifier is the verification func of the syncer, used to deliver headers received outside of the topic
+	mtx      sync.RWMutex
+	verifier func(context.Context, H) error
+}
+
+func newValidatingSubscriber[H header.Header[H]](
+	sub *goheaderp2p.Subscriber[H],
+	ps *pubsub.PubSub,
+	networkID string,
+	validate func(H) error,
+) (*validatingSubscriber[H], error) {
+	vs := &validatingSubscriber[H]{
+		Subscriber: sub,
+		ps:         ps,
+		topic:      goheaderp2p.PubsubTopicID(networkID),
+		validate:   validate,
+	}
+	if err := sub.SetVerifier(func(_ context.Context, h H) error {
+		return validate(h)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to register gossip validator: %w", err)
+	}
+	return vs, nil
+}
+
+// SetVerifier replaces topic validator with given verification func, chained after the validation.
+func (vs *validatingSubscriber[H]) SetVerifier(val func(context.Context, H) error) error {
+	if err := vs.ps.UnregisterTopicValidator(vs.topic); err != nil {
+		return err
+	}
+	vs.mtx.Lock()
+	vs.verifier = val
+	vs.mtx.Unlock()
+	return vs.Subscriber.SetVerifier(func(ctx context.Context, h H) error {
+		if err := vs.validate(h); err != nil {
+			return err
+		}
+		return val(ctx, h)
+	})
+}
+
+// deliver validates the header received outside of the topic (e.g. in a batch), and passes it to the verifier of
+// the syncer, as if it was gossiped in the topic. Without the syncer, the header is only validated.
+func (vs *validatingSubscriber[H]) deliver(ctx context.Context, h H) error {
+	if err := vs.validate(h); err != nil {
+		return err
+	}
+	vs.mtx.RLock()
+	verifier := vs.verifier
+	vs.mtx.RUnlock()
+	if verifier == nil {
+		return nil
+	}
+	return verifier(ctx, h)
+}
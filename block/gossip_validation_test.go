@@ -0,0 +1,139 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	goheaderstore "github.com/celestiaorg/go-header/store"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	cmtypes "github.com/cometbft/cometbft/types"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestValidateGossipedHeader(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sh, _, err := types.GetRandomSignedHeader()
+	require.NoError(err)
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       types.TestChainID,
+		InitialHeight: 1,
+		Validators:    []cmtypes.GenesisValidator{{Address: sh.Validators.Proposer.Address, PubKey: sh.Validators.Proposer.PubKey, Power: 1}},
+	}
+	assert.NoError(validateGossipedHeader(genesis, types.DefaultCommitThreshold, nil, sh))
+
+	wrongChain := *genesis
+	wrongChain.ChainID = "other"
+	assert.ErrorIs(validateGossipedHeader(&wrongChain, types.DefaultCommitThreshold, nil, sh), ErrWrongChainID)
+
+	highInitial := *genesis
+	highInitial.InitialHeight = int64(sh.Height()) + 1
+	assert.ErrorIs(validateGossipedHeader(&highInitial, types.DefaultCommitThreshold, nil, sh), ErrHeightBelowInitial)
+
+	unsigned := *sh
+	unsigned.Validators = nil
+	assert.ErrorIs(validateGossipedHeader(genesis, types.DefaultCommitThreshold, nil, &unsigned), ErrUnsignedHeader)
+
+	// proposer of based rollup header is verified against the key recovered from the signature
+	based, _, err := types.NewGenerator(1, types.WithSignatureScheme(signer.SchemeSecp256k1)).SignedHeader()
+	require.NoError(err)
+	based.Validators = nil
+	basedGenesis := &cmtypes.GenesisDoc{ChainID: types.TestChainID, InitialHeight: 1, ConsensusParams: cmtypes.DefaultConsensusParams()}
+	basedGenesis.ConsensusParams.Validator.PubKeyTypes = []string{signer.SchemeSecp256k1}
+	assert.NoError(validateGossipedHeader(basedGenesis, types.DefaultCommitThreshold, nil, based))
+	based.ProposerAddress = sh.ProposerAddress
+	assert.ErrorIs(validateGossipedHeader(basedGenesis, types.DefaultCommitThreshold, nil, based), types.ErrUnauthorizedProposer)
+
+	// aggregators don't co-sign blocks yet, so without threshold the signature of the proposer is enough
+	proposerOnly, _, err := types.NewGenerator(2, types.WithNumValidators(3)).SignedHeader()
+	require.NoError(err)
+	for i, val := range proposerOnly.Validators.Validators {
+		if !bytes.Equal(val.Address, proposerOnly.ProposerAddress) {
+			proposerOnly.Commit.Signatures[i] = nil
+		}
+	}
+	multiGenesis := &cmtypes.GenesisDoc{ChainID: types.TestChainID, InitialHeight: 1}
+	for _, val := range proposerOnly.Validators.Validators {
+		multiGenesis.Validators = append(multiGenesis.Validators, cmtypes.GenesisValidator{Address: val.Address, PubKey: val.PubKey, Power: val.VotingPower})
+	}
+	require.NoError(proposerOnly.ValidateBasic())
+	assert.NoError(validateGossipedHeader(multiGenesis, cmtmath.Fraction{}, nil, proposerOnly))
+	assert.ErrorIs(validateGossipedHeader(multiGenesis, types.DefaultCommitThreshold, nil, proposerOnly), types.ErrInsufficientVotingPower)
+
+	block := types.GetRandomBlock(1, 1)
+	assert.NoError(validateGossipedBlock(&cmtypes.GenesisDoc{ChainID: types.TestChainID, InitialHeight: 1}, types.DefaultCommitThreshold, nil, block))
+	assert.ErrorIs(validateGossipedBlock(&wrongChain, types.DefaultCommitThreshold, nil, block), ErrWrongChainID)
+}
+
+func TestValidateHeaderTime(t *testing.T) {
+	sh, _, err := types.GetRandomSignedHeader()
+	require.NoError(t, err)
+	now := sh.Time()
+
+	assert.NoError(t, validateHeaderTime(now, 10*time.Second, sh))
+	assert.NoError(t, validateHeaderTime(now.Add(-10*time.Second), 10*time.Second, sh))
+	assert.ErrorIs(t, validateHeaderTime(now.Add(-11*time.Second), 10*time.Second, sh), ErrHeaderFromFuture)
+	// zero disables the limit
+	assert.NoError(t, validateHeaderTime(now.Add(-time.Hour), 0, sh))
+}
+
+func TestValidateGossipedWithStoredLast(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	g := types.NewGenerator(1)
+	sh, keys, err := g.SignedHeader()
+	require.NoError(err)
+	sh.BaseHeader.Height = 1
+	signHeader(t, sh, keys)
+	next, err := g.NextSignedHeader(sh, keys)
+	require.NoError(err)
+	unlinked := *next
+	unlinked.LastHeaderHash = g.Bytes(32)
+	signHeader(t, &unlinked, keys)
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       types.TestChainID,
+		InitialHeight: 1,
+		Validators:    []cmtypes.GenesisValidator{{Address: sh.Validators.Proposer.Address, PubKey: sh.Validators.Proposer.PubKey, Power: 1}},
+	}
+	datastore := dssync.MutexWrap(ds.NewMapDatastore())
+
+	headerStore, err := goheaderstore.NewStore[*types.SignedHeader](datastore, goheaderstore.WithStorePrefix(headerSyncPrefix))
+	require.NoError(err)
+	hSyncService := &HeaderSyncService{ctx: ctx, genesis: genesis, headerStore: headerStore}
+	// without the last header in the store, linking is left to the syncer
+	assert.NoError(hSyncService.validateHeader(&unlinked))
+	require.NoError(headerStore.Init(ctx, sh))
+	assert.NoError(hSyncService.validateHeader(next))
+	assert.ErrorIs(hSyncService.validateHeader(&unlinked), types.ErrLastHeaderHashMismatch)
+
+	newBlock := func(sh *types.SignedHeader) *types.Block {
+		block := &types.Block{SignedHeader: *sh}
+		block.SignedHeader.DataHash, err = block.DataHash(types.DefaultMerkleHasher)
+		require.NoError(err)
+		signHeader(t, &block.SignedHeader, keys)
+		return block
+	}
+	blockStore, err := goheaderstore.NewStore[*types.Block](datastore, goheaderstore.WithStorePrefix(blockSyncPrefix))
+	require.NoError(err)
+	bSyncService := &BlockSyncService{ctx: ctx, genesis: genesis, blockStore: blockStore}
+	last := newBlock(sh)
+	next, err = g.NextSignedHeader(&last.SignedHeader, keys)
+	require.NoError(err)
+	block := newBlock(next)
+	unlinkedBlock := newBlock(&unlinked)
+	assert.NoError(bSyncService.validateBlock(unlinkedBlock))
+	require.NoError(blockStore.Init(ctx, last))
+	assert.NoError(bSyncService.validateBlock(block))
+	assert.ErrorIs(bSyncService.validateBlock(unlinkedBlock), types.ErrLastHeaderHashMismatch)
+}
@@ -0,0 +1,137 @@
+package block
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/celestiaorg/go-header"
+
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/types"
+)
+
+// headerBatcher batches headers published by the aggregator in bursts. A header published after at least interval
+// since the last publication is published immediately. Otherwise it's delayed until the interval passes, and
+// published together with other delayed headers, up to size headers (and MaxHeaderBatchSize bytes) in a batch.
+type headerBatcher struct {
+	size     int
+	interval time.Duration
+	publish  func(types.HeaderBatch)
+
+	mtx          sync.Mutex
+	pending      types.HeaderBatch
+	pendingBytes int
+	last         time.Time
+	timer        *time.Timer
+}
+
+func newHeaderBatcher(size int, interval time.Duration, publish func(types.HeaderBatch)) *headerBatcher {
+	return &headerBatcher{size: size, interval: interval, publish: publish}
+}
+
+// add publishes the header, or adds it to the pending batch.
+func (b *headerBatcher) add(sh *types.SignedHeader) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := time.Now()
+	if len(b.pending) == 0 && now.Sub(b.last) >= b.interval {
+		b.last = now
+		b.publish(types.HeaderBatch{sh})
+		return
+	}
+
+	size := binary.MaxVarintLen64
+	if data, err := sh.MarshalBinary(); err == nil {
+		size += len(data)
+	}
+	if len(b.pending) > 0 && b.pendingBytes+size > types.MaxHeaderBatchSize {
+		b.flushLocked()
+	}
+	b.pending = append(b.pending, sh)
+	b.pendingBytes += size
+	if len(b.pending) >= b.size {
+		b.flushLocked()
+		return
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.last.Add(b.interval).Sub(now), b.flush)
+	}
+}
+
+// flush publishes the pending batch.
+func (b *headerBatcher) flush() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.flushLocked()
+}
+
+func (b *headerBatcher) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+	batch := b.pending
+	b.pending, b.pendingBytes = nil, 0
+	b.last = time.Now()
+	b.publish(batch)
+}
+
+// publishHeaders gossips a single header in the header topic (for compatibility with go-header subscribers), and
+// batches of headers in the header batch topic.
+func (hSyncService *HeaderSyncService) publishHeaders(batch types.HeaderBatch) {
+	if len(batch) == 1 {
+		if err := hSyncService.sub.Broadcast(hSyncService.ctx, batch[0]); err != nil {
+			hSyncService.logger.Error("failed to broadcast block header", "error", err)
+		}
+		return
+	}
+	data, err := batch.MarshalBinary()
+	if err != nil {
+		hSyncService.logger.Error("failed to marshal header batch", "error", err)
+		return
+	}
+	if err := hSyncService.p2p.GossipHeaderBatch(hSyncService.ctx, data); err != nil {
+		hSyncService.logger.Error("failed to gossip header batch", "from", batch[0].Height(),
+			"to", batch[len(batch)-1].Height(), "error", err)
+	}
+}
+
+// validateHeaderBatch is the validator of gossiped header batches. Headers of a valid batch are passed in order to
+// the syncer, like headers gossiped one by one. Batches with headers rejected by validation or by the syncer are
+// not relayed; headers already known to the syncer are skipped.
+func (hSyncService *HeaderSyncService) validateHeaderBatch(msg *p2p.GossipMessage) bool {
+	var batch types.HeaderBatch
+	if err := batch.UnmarshalBinary(msg.Data); err != nil {
+		hSyncService.logger.Debug("failed to unmarshal header batch", "from", msg.From, "error", err)
+		return false
+	}
+	if err := batch.ValidateBasic(); err != nil {
+		hSyncService.logger.Debug("invalid header batch", "from", msg.From, "error", err)
+		return false
+	}
+	// subscriber is created on start of the service, and the verifier is set on start of the syncer
+	started := hSyncService.syncerStatus.isStarted()
+	for _, sh := range batch {
+		var err error
+		if started {
+			err = hSyncService.sub.deliver(hSyncService.ctx, sh)
+		} else {
+			err = hSyncService.validateHeader(sh)
+		}
+		var verErr *header.VerifyError
+		if errors.As(err, &verErr) && verErr.SoftFailure {
+			continue
+		}
+		if err != nil {
+			hSyncService.logger.Debug("header of gossiped batch rejected", "height", sh.Height(), "from", msg.From, "error", err)
+			return false
+		}
+	}
+	return true
+}
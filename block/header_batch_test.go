@@ -0,0 +1,69 @@
+package block
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestHeaderBatcher(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	g := types.NewGenerator(1)
+	sh, keys, err := g.SignedHeader()
+	require.NoError(err)
+	headers := []*types.SignedHeader{sh}
+	for i := 0; i < 5; i++ {
+		sh, err = g.NextSignedHeader(sh, keys)
+		require.NoError(err)
+		headers = append(headers, sh)
+	}
+
+	var mtx sync.Mutex
+	var published []types.HeaderBatch
+	publish := func(batch types.HeaderBatch) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		published = append(published, batch)
+	}
+	numPublished := func() int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(published)
+	}
+
+	b := newHeaderBatcher(3, time.Hour, publish)
+	// first header is published immediately, following ones are batched up to the size
+	for _, sh := range headers[:4] {
+		b.add(sh)
+	}
+	require.Equal(2, numPublished())
+	assert.Equal(types.HeaderBatch{headers[0]}, published[0])
+	assert.Equal(types.HeaderBatch(headers[1:4]), published[1])
+
+	b.add(headers[4])
+	require.Equal(2, numPublished())
+	b.flush()
+	require.Equal(3, numPublished())
+	assert.Equal(types.HeaderBatch{headers[4]}, published[2])
+	b.flush()
+	assert.Equal(3, numPublished())
+
+	// pending headers are published after the interval
+	published = nil
+	b = newHeaderBatcher(3, 20*time.Millisecond, publish)
+	b.add(headers[0])
+	b.add(headers[1])
+	b.add(headers[2])
+	require.Equal(1, numPublished())
+	require.Eventually(func() bool { return numPublished() == 2 }, time.Second, 5*time.Millisecond)
+	mtx.Lock()
+	assert.Equal(types.HeaderBatch(headers[1:3]), published[1])
+	mtx.Unlock()
+}
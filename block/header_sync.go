@@ -2,9 +2,9 @@ package block
 
 import (
 	"context"
-	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/celestiaorg/go-header"
 	goheaderp2p "github.com/celestiaorg/go-header/p2p"
@@ -17,10 +17,14 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 
+	"github.com/rollkit/rollkit/clock"
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/crypto/bls"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/tracing"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -29,17 +33,23 @@ import (
 // Contains a header store where synced headers are stored.
 // Uses the go-header library for handling all P2P logic.
 type HeaderSyncService struct {
-	conf        config.NodeConfig
-	genesis     *cmtypes.GenesisDoc
-	p2p         *p2p.Client
-	ex          *goheaderp2p.Exchange[*types.SignedHeader]
-	sub         *goheaderp2p.Subscriber[*types.SignedHeader]
-	p2pServer   *goheaderp2p.ExchangeServer[*types.SignedHeader]
-	headerStore *goheaderstore.Store[*types.SignedHeader]
+	conf    config.NodeConfig
+	genesis *cmtypes.GenesisDoc
+	p2p     *p2p.Client
+	// aggregatorKeys are BLS keys of aggregators, verified against keys carried by gossiped headers
+	aggregatorKeys []bls.PubKey
+	ex             *goheaderp2p.Exchange[*types.SignedHeader]
+	sub            *validatingSubscriber[*types.SignedHeader]
+	p2pServer      *goheaderp2p.ExchangeServer[*types.SignedHeader]
+	headerStore    *goheaderstore.Store[*types.SignedHeader]
+	datastore      ds.Batching
 
 	syncer       *goheadersync.Syncer[*types.SignedHeader]
 	syncerStatus *SyncerStatus
 
+	// batcher is used by aggregator to gossip headers produced in bursts in batches, nil if batching is disabled
+	batcher *headerBatcher
+
 	logger log.Logger
 	ctx    context.Context
 }
@@ -58,20 +68,29 @@ func NewHeaderSyncService(ctx context.Context, store ds.TxnDatastore, conf confi
 	if !ok {
 		return nil, errors.New("failed to access the datastore")
 	}
-	ss, err := goheaderstore.NewStore[*types.SignedHeader](storeBatch, goheaderstore.WithStorePrefix("headerSync"))
+	ss, err := goheaderstore.NewStore[*types.SignedHeader](storeBatch, goheaderstore.WithStorePrefix(headerSyncPrefix))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize the header store: %w", err)
 	}
+	aggregatorKeys, err := parseAggregatorKeys(conf.AggregatorKeys)
+	if err != nil {
+		return nil, err
+	}
 
-	return &HeaderSyncService{
-		conf:         conf,
-		genesis:      genesis,
-		p2p:          p2p,
-		ctx:          ctx,
-		headerStore:  ss,
-		logger:       logger,
-		syncerStatus: new(SyncerStatus),
-	}, nil
+	hSyncService := &HeaderSyncService{
+		conf:           conf,
+		genesis:        genesis,
+		p2p:            p2p,
+		aggregatorKeys: aggregatorKeys,
+		ctx:            ctx,
+		datastore:      storeBatch,
+		headerStore:    ss,
+		logger:         logger,
+		syncerStatus:   new(SyncerStatus),
+	}
+	// validator has to be registered before the P2P client is started
+	p2p.SetHeaderBatchValidator(hSyncService.validateHeaderBatch)
+	return hSyncService, nil
 }
 
 // HeaderStore returns the headerstore of the HeaderSynceService
@@ -79,6 +98,40 @@ func (hSyncService *HeaderSyncService) HeaderStore() *goheaderstore.Store[*types
 	return hSyncService.headerStore
 }
 
+// EarliestHeight returns the height of the earliest header in the header store, or 0 if the store is empty.
+func (hSyncService *HeaderSyncService) EarliestHeight(ctx context.Context) (uint64, error) {
+	return earliestSyncHeight(ctx, hSyncService.headerStore, hSyncService.datastore, headerSyncPrefix)
+}
+
+// Prune deletes headers below retainHeight from the header store, and returns the number of pruned headers.
+// The head of the store is always kept.
+func (hSyncService *HeaderSyncService) Prune(ctx context.Context, retainHeight uint64) (uint64, error) {
+	return pruneSyncStore(ctx, hSyncService.headerStore, hSyncService.datastore, headerSyncPrefix, retainHeight)
+}
+
+// WaitSynced blocks until the syncer is started, and the complete header chain up to the head of the network is
+// verified and stored in the header store. It returns the height of the head.
+func (hSyncService *HeaderSyncService) WaitSynced(ctx context.Context) (uint64, error) {
+	ticker := time.NewTicker(initRetryInterval)
+	defer ticker.Stop()
+	for !hSyncService.syncerStatus.isStarted() {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	head, err := hSyncService.syncer.Head(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the network head: %w", err)
+	}
+	// header store blocks until the header is synced
+	if _, err := hSyncService.headerStore.GetByHeight(ctx, head.Height()); err != nil {
+		return 0, fmt.Errorf("failed to sync headers up to height %d: %w", head.Height(), err)
+	}
+	return head.Height(), nil
+}
+
 func (hSyncService *HeaderSyncService) initHeaderStoreAndStartSyncer(ctx context.Context, initial *types.SignedHeader) error {
 	if initial == nil {
 		return fmt.Errorf("failed to initialize the headerstore and start syncer")
@@ -95,6 +148,9 @@ func (hSyncService *HeaderSyncService) initHeaderStoreAndStartSyncer(ctx context
 // WriteToHeaderStoreAndBroadcast initializes header store if needed and broadcasts provided header.
 // Note: Only returns an error in case header store can't be initialized. Logs error if there's one while broadcasting.
 func (hSyncService *HeaderSyncService) WriteToHeaderStoreAndBroadcast(ctx context.Context, signedHeader *types.SignedHeader) error {
+	ctx, span := tracing.Start(ctx, "HeaderSyncService.Broadcast", attribute.Int64("height", int64(signedHeader.Height())))
+	defer span.End()
+
 	// For genesis header initialize the store and start the syncer
 	if int64(signedHeader.Height()) == hSyncService.genesis.InitialHeight {
 		if err := hSyncService.headerStore.Init(ctx, signedHeader); err != nil {
@@ -106,9 +162,15 @@ func (hSyncService *HeaderSyncService) WriteToHeaderStoreAndBroadcast(ctx contex
 		}
 	}
 
+	if hSyncService.batcher != nil {
+		hSyncService.batcher.add(signedHeader)
+		return nil
+	}
+
 	// Broadcast for subscribers
 	if err := hSyncService.sub.Broadcast(ctx, signedHeader); err != nil {
 		hSyncService.logger.Error("failed to broadcast block header", "error", err)
+		span.RecordError(err)
 	}
 	return nil
 }
@@ -122,8 +184,7 @@ func (hSyncService *HeaderSyncService) Start() error {
 	// have to do the initializations here to utilize the p2p node which is created on start
 	ps := hSyncService.p2p.PubSub()
 
-	var err error
-	hSyncService.sub, err = goheaderp2p.NewSubscriber[*types.SignedHeader](
+	sub, err := goheaderp2p.NewSubscriber[*types.SignedHeader](
 		ps,
 		pubsub.DefaultMsgIdFn,
 		goheaderp2p.WithSubscriberNetworkID(hSyncService.genesis.ChainID),
@@ -131,6 +192,14 @@ func (hSyncService *HeaderSyncService) Start() error {
 	if err != nil {
 		return err
 	}
+	hSyncService.sub, err = newValidatingSubscriber(sub, ps, hSyncService.genesis.ChainID, hSyncService.validateHeader)
+	if err != nil {
+		return err
+	}
+	if hSyncService.conf.Aggregator && hSyncService.conf.P2P.HeaderBatchSize > 1 {
+		hSyncService.batcher = newHeaderBatcher(hSyncService.conf.P2P.HeaderBatchSize,
+			hSyncService.conf.P2P.HeaderBatchInterval, hSyncService.publishHeaders)
+	}
 
 	if err := hSyncService.sub.Start(hSyncService.ctx); err != nil {
 		return fmt.Errorf("error while starting subscriber: %w", err)
@@ -178,35 +247,44 @@ func (hSyncService *HeaderSyncService) Start() error {
 		return nil
 	}
 
-	// Look to see if trusted hash is passed, if not get the genesis header
-	var trustedHeader *types.SignedHeader
+	fetch := func(ctx context.Context, ex header.Exchange[*types.SignedHeader]) (*types.SignedHeader, error) {
+		return fetchInitial[*types.SignedHeader](ctx, ex, hSyncService.conf.TrustedHash, uint64(hSyncService.genesis.InitialHeight), "header")
+	}
 	// Try fetching the trusted header from peers if exists
 	if len(peerIDs) > 0 {
-		if hSyncService.conf.TrustedHash != "" {
-			trustedHashBytes, err := hex.DecodeString(hSyncService.conf.TrustedHash)
-			if err != nil {
-				return fmt.Errorf("failed to parse the trusted hash for initializing the headerstore: %w", err)
-			}
-
-			if trustedHeader, err = hSyncService.ex.Get(hSyncService.ctx, header.Hash(trustedHashBytes)); err != nil {
-				return fmt.Errorf("failed to fetch the trusted header for initializing the headerstore: %w", err)
-			}
-		} else {
-			// Try fetching the genesis header if available, otherwise fallback to signed headers
-			if trustedHeader, err = hSyncService.ex.GetByHeight(hSyncService.ctx, uint64(hSyncService.genesis.InitialHeight)); err != nil {
-				// Full/light nodes have to wait for aggregator to publish the genesis header
-				// proposing aggregator can init the store and start the syncer when the first header is published
-				return fmt.Errorf("failed to fetch the genesis header: %w", err)
-			}
+		trustedHeader, err := fetch(hSyncService.ctx, hSyncService.ex)
+		if err != nil {
+			return err
 		}
 		return hSyncService.initHeaderStoreAndStartSyncer(hSyncService.ctx, trustedHeader)
-
+	}
+	// aggregator initializes the store when the first header is published
+	if !hSyncService.conf.Aggregator {
+		newExchange := func(peers []peer.ID) (*goheaderp2p.Exchange[*types.SignedHeader], error) {
+			return newP2PExchange(hSyncService.p2p.Host(), peers, network, hSyncService.genesis.ChainID, hSyncService.p2p.ConnectionGater())
+		}
+		go initWhenConnected(hSyncService.ctx, hSyncService.p2p, hSyncService.isInitialized, newExchange, fetch,
+			hSyncService.initHeaderStoreAndStartSyncer, hSyncService.logger)
 	}
 	return nil
 }
 
+// validateHeader checks if the header received from peers is valid and belongs to the chain.
+func (hSyncService *HeaderSyncService) validateHeader(sh *types.SignedHeader) error {
+	if err := validateHeaderTime(clock.Real.Now(), hSyncService.conf.MaxFutureTime, sh); err != nil {
+		return err
+	}
+	if err := validateGossipedHeader(hSyncService.genesis, hSyncService.conf.CommitThreshold, hSyncService.aggregatorKeys, sh); err != nil {
+		return err
+	}
+	return validateWithStoredLast(hSyncService.ctx, hSyncService.headerStore, uint64(hSyncService.genesis.InitialHeight), sh, sh.ValidateWithLast)
+}
+
 // Stop is a part of Service interface.
 func (hSyncService *HeaderSyncService) Stop() error {
+	if hSyncService.batcher != nil {
+		hSyncService.batcher.flush()
+	}
 	err := hSyncService.headerStore.Stop(hSyncService.ctx)
 	err = multierr.Append(err, hSyncService.p2pServer.Stop(hSyncService.ctx))
 	err = multierr.Append(err, hSyncService.ex.Stop(hSyncService.ctx))
@@ -0,0 +1,305 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	// maxBlockRangeSize limits the number of blocks fetched at once, with a single request to peers.
+	maxBlockRangeSize = 100
+	// maxPendingBlockRanges limits the number of block ranges fetched by HeadersFirstSyncLoop ahead of the store.
+	maxPendingBlockRanges = 4
+	// maxDAScanHeights limits the number of DA heights searched for blocks that peers didn't return.
+	maxDAScanHeights = 100
+	// lazyBlockCacheSize is the number of fetched blocks below the height of the store kept in memory.
+	lazyBlockCacheSize = 1000
+)
+
+// ErrBlockDataUnavailable is returned when data of the block can't be fetched, because its header is not synced
+// yet, or neither peers nor DA layer returned the block matching the header.
+var ErrBlockDataUnavailable = errors.New("block data not available")
+
+// HeaderGetter provides headers verified by the header sync service. It's implemented by the header store.
+type HeaderGetter interface {
+	// Height returns the height of the latest header.
+	Height() uint64
+	// GetByHeight returns the header at given height.
+	GetByHeight(ctx context.Context, height uint64) (*types.SignedHeader, error)
+}
+
+// BlockRangeFetcher requests blocks of the range [from, to] from peers, until blocks accepted by verify are received.
+type BlockRangeFetcher func(ctx context.Context, from, to uint64, verify func([]*types.Block) error) ([]*types.Block, error)
+
+// SetLazyBlockData enables fetching of block data missing in the store on demand: blocks are requested from peers
+// with fetch (if not nil), and then searched on DA layer, and they are verified against headers synced by the header
+// sync service.
+func (m *Manager) SetLazyBlockData(headers HeaderGetter, fetch BlockRangeFetcher) {
+	m.syncHeaders = headers
+	m.fetchPeerBlocks = fetch
+}
+
+// FetchBlockRange returns blocks of the range [from, to] (of up to maxBlockRangeSize blocks). Blocks missing in
+// the store are fetched from peers or DA layer and kept in memory, but they are not saved in the store.
+func (m *Manager) FetchBlockRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	blocks, err := m.fetchBlockRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blocks {
+		m.lazyBlocks.add(b)
+	}
+	return blocks, nil
+}
+
+func (m *Manager) fetchBlockRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	if from == 0 || from > to || to-from >= maxBlockRangeSize {
+		return nil, fmt.Errorf("invalid range of blocks [%d, %d]", from, to)
+	}
+	blocks := make([]*types.Block, to-from+1)
+	headers := make(map[uint64]*types.SignedHeader)
+	for h := from; h <= to; h++ {
+		if b, err := m.store.LoadBlock(h); err == nil {
+			blocks[h-from] = b
+			continue
+		}
+		if b, ok := m.lazyBlocks.get(h); ok {
+			blocks[h-from] = b
+			continue
+		}
+		// header store blocks until headers above its height are synced
+		if m.syncHeaders == nil || h > m.syncHeaders.Height() {
+			return nil, fmt.Errorf("%w: header %d is not synced", ErrBlockDataUnavailable, h)
+		}
+		header, err := m.syncHeaders.GetByHeight(ctx, h)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to get header %d: %w", ErrBlockDataUnavailable, h, err)
+		}
+		headers[h] = header
+	}
+	if len(headers) == 0 {
+		return blocks, nil
+	}
+
+	found := m.fetchMissingBlocks(ctx, headers)
+	for h := from; h <= to; h++ {
+		if blocks[h-from] != nil {
+			continue
+		}
+		b, ok := found[h]
+		if !ok {
+			return nil, fmt.Errorf("%w: block %d not found on peers and DA layer", ErrBlockDataUnavailable, h)
+		}
+		blocks[h-from] = b
+	}
+	return blocks, nil
+}
+
+// fetchMissingBlocks fetches blocks with given headers (by height), first from peers, and then from DA layer.
+// It returns blocks matching the headers, by height.
+func (m *Manager) fetchMissingBlocks(ctx context.Context, headers map[uint64]*types.SignedHeader) map[uint64]*types.Block {
+	from, to := heightRange(headers)
+
+	found := make(map[uint64]*types.Block, len(headers))
+	if m.fetchPeerBlocks != nil {
+		blocks, err := m.fetchPeerBlocks(ctx, from, to, func(blocks []*types.Block) error {
+			if uint64(len(blocks)) != to-from+1 {
+				return fmt.Errorf("expected %d blocks, got %d", to-from+1, len(blocks))
+			}
+			for i, b := range blocks {
+				if b.Height() != from+uint64(i) {
+					return fmt.Errorf("expected block %d, got %d", from+uint64(i), b.Height())
+				}
+				if i > 0 {
+					if err := b.ValidateWithLast(blocks[i-1]); err != nil {
+						return err
+					}
+				}
+				if header, ok := headers[b.Height()]; ok {
+					if err := m.verifyBlockData(b, header); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			for _, b := range blocks {
+				if _, ok := headers[b.Height()]; ok {
+					found[b.Height()] = b
+				}
+			}
+			return found
+		}
+		m.logger.Debug("failed to fetch blocks from peers", "from", from, "to", to, "error", err)
+	}
+
+	if err := m.retrieveBlocksFromDA(ctx, headers, found); err != nil {
+		m.logger.Debug("failed to retrieve blocks from DA layer", "from", from, "to", to, "error", err)
+	}
+	return found
+}
+
+// retrieveBlocksFromDA searches DA layer for blocks with given headers, starting at the DA height of the nearest
+// lower block with known location (or the DA start height), for at most maxDAScanHeights DA blocks. Blocks are
+// submitted in order of heights, so the search stops at the first block above the range. Found blocks are added
+// to found, and their locations are saved.
+func (m *Manager) retrieveBlocksFromDA(ctx context.Context, headers map[uint64]*types.SignedHeader, found map[uint64]*types.Block) error {
+	from, to := heightRange(headers)
+	daHeight := m.daScanStart(from)
+	for i := 0; i < maxDAScanHeights && len(found) < len(headers); i, daHeight = i+1, daHeight+1 {
+		res := m.retriever.RetrieveBlocks(ctx, daHeight)
+		if res.Code == da.StatusError {
+			return fmt.Errorf("failed to retrieve blocks from DA height %d: %s", daHeight, res.Message)
+		}
+		index := uint64(0)
+		for _, b := range res.Blocks {
+			if b.SignedHeader.ChainID() != m.genesis.ChainID {
+				continue
+			}
+			loc := store.DALocation{DAHeight: daHeight, Index: index}
+			index++
+			if b.Height() > to {
+				return nil
+			}
+			header, ok := headers[b.Height()]
+			if !ok || found[b.Height()] != nil || m.verifyBlockData(b, header) != nil {
+				continue
+			}
+			found[b.Height()] = b
+			if err := m.store.SaveDALocation(b.Height(), loc); err != nil {
+				m.logger.Error("failed to save DA location of block", "height", b.Height(), "daHeight", daHeight, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// daScanStart returns the DA height, from which blocks starting at given height are searched on DA layer.
+func (m *Manager) daScanStart(height uint64) uint64 {
+	for h := height - 1; h > 0 && height-h <= maxBlockRangeSize; h-- {
+		if loc, err := m.store.LoadDALocation(h); err == nil {
+			return loc.DAHeight
+		}
+	}
+	return m.conf.DAStartHeight
+}
+
+// heightRange returns the lowest and the highest height of the headers.
+func heightRange(headers map[uint64]*types.SignedHeader) (uint64, uint64) {
+	from, to := uint64(0), uint64(0)
+	for h := range headers {
+		if from == 0 || h < from {
+			from = h
+		}
+		if h > to {
+			to = h
+		}
+	}
+	return from, to
+}
+
+// verifyBlockData checks that the block is valid, and matches the header synced by the header sync service.
+func (m *Manager) verifyBlockData(b *types.Block, header *types.SignedHeader) error {
+	if err := b.ValidateBasic(m.dataHasher); err != nil {
+		return err
+	}
+	if hash := b.Hash(); !bytes.Equal(hash, header.Hash()) {
+		return fmt.Errorf("hash of block %d doesn't match header: %X != %X", b.Height(), hash, header.Hash())
+	}
+	return nil
+}
+
+// HeadersFirstSyncLoop is used instead of BlockStoreRetrieveLoop in headers-first sync mode. It fetches data of
+// blocks following the latest block in the store by ranges, up to the height of the header chain synced by
+// the header sync service, and passes the blocks to SyncLoop.
+func (m *Manager) HeadersFirstSyncLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.conf.BlockTime)
+	defer ticker.Stop()
+	next := uint64(0)
+	for {
+		height := m.store.Height()
+		if next <= height {
+			next = height + 1
+		}
+		if next > m.syncHeaders.Height() || next > height+maxPendingBlockRanges*maxBlockRangeSize {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			// blocks passed to SyncLoop were dropped, e.g. when the node was paused
+			if _, ok := m.blockCache.getBlock(height + 1); !ok && len(m.blockInCh) == 0 && m.store.Height() == height {
+				next = height + 1
+			}
+			continue
+		}
+
+		to := next + maxBlockRangeSize - 1
+		if head := m.syncHeaders.Height(); to > head {
+			to = head
+		}
+		blocks, err := m.fetchBlockRange(ctx, next, to)
+		if err != nil {
+			m.logger.Info("failed to fetch blocks", "from", next, "to", to, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			continue
+		}
+		daHeight := atomic.LoadUint64(&m.daHeight)
+		for _, b := range blocks {
+			m.logger.Debug("block fetched in headers-first sync", "blockHeight", b.Height(), "daHeight", daHeight)
+			select {
+			case m.blockInCh <- newBlockEvent{b, daHeight}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		next = to + 1
+	}
+}
+
+// lazyBlockCache keeps up to size blocks fetched on demand, evicting the oldest ones.
+type lazyBlockCache struct {
+	mtx    sync.Mutex
+	size   int
+	blocks map[uint64]*types.Block
+	order  []uint64
+}
+
+func newLazyBlockCache(size int) *lazyBlockCache {
+	return &lazyBlockCache{size: size, blocks: make(map[uint64]*types.Block)}
+}
+
+func (c *lazyBlockCache) get(height uint64) (*types.Block, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	b, ok := c.blocks[height]
+	return b, ok
+}
+
+func (c *lazyBlockCache) add(b *types.Block) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.blocks[b.Height()]; ok {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.blocks, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.blocks[b.Height()] = b
+	c.order = append(c.order, b.Height())
+}
@@ -0,0 +1,160 @@
+package block
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// headerMap is HeaderGetter of headers synced up to the highest height in the map.
+type headerMap map[uint64]*types.SignedHeader
+
+func (h headerMap) Height() uint64 {
+	return uint64(len(h))
+}
+
+func (h headerMap) GetByHeight(_ context.Context, height uint64) (*types.SignedHeader, error) {
+	header, ok := h[height]
+	if !ok {
+		return nil, errors.New("header not found")
+	}
+	return header, nil
+}
+
+// blockRetriever returns blocks by DA height.
+type blockRetriever map[uint64][]*types.Block
+
+func (r blockRetriever) RetrieveBlocks(_ context.Context, daHeight uint64) da.ResultRetrieveBlocks {
+	return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: daHeight}, Blocks: r[daHeight]}
+}
+
+// validBlocks returns n consecutive, validly signed blocks starting at height 1.
+func validBlocks(t *testing.T, n int) []*types.Block {
+	g := types.NewGenerator(1)
+	sh, keys, err := g.SignedHeader()
+	require.NoError(t, err)
+	sh.BaseHeader.Height = 1
+	var blocks []*types.Block
+	for i := 0; i < n; i++ {
+		block := g.Block(sh.Height(), 2)
+		block.SignedHeader = *sh
+		block.SignedHeader.DataHash, err = block.DataHash(types.DefaultMerkleHasher)
+		require.NoError(t, err)
+		signHeader(t, &block.SignedHeader, keys)
+		require.NoError(t, block.ValidateBasic(types.DefaultMerkleHasher))
+		blocks = append(blocks, block)
+		sh, err = g.NextSignedHeader(&block.SignedHeader, keys)
+		require.NoError(t, err)
+	}
+	return blocks
+}
+
+func TestFetchBlockRange(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	blocks := validBlocks(t, 6)
+	headers := make(headerMap)
+	for _, b := range blocks[:5] {
+		headers[b.Height()] = &b.SignedHeader
+	}
+	other := types.GetRandomBlock(2, 1)
+	other.SignedHeader.BaseHeader.ChainID = "other"
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	m := &Manager{
+		store:      store.New(ctx, kv),
+		genesis:    &cmtypes.GenesisDoc{ChainID: types.TestChainID},
+		dataHasher: types.DefaultMerkleHasher,
+		lazyBlocks: newLazyBlockCache(lazyBlockCacheSize),
+		logger:     test.NewFileLogger(t),
+		// blocks 2 and 3 are on DA layer, after a block of another chain
+		retriever: blockRetriever{
+			1: {blocks[0]},
+			2: {other, blocks[1]},
+			3: {blocks[2], blocks[5]},
+		},
+	}
+	require.NoError(m.store.SaveBlock(blocks[0], &blocks[0].SignedHeader.Commit))
+	m.store.SetHeight(1)
+
+	_, err := m.FetchBlockRange(ctx, 2, 3)
+	assert.ErrorIs(err, ErrBlockDataUnavailable, "lazy block data not enabled")
+
+	// peers return invalid blocks, so blocks are retrieved from DA layer
+	requested := 0
+	m.SetLazyBlockData(headers, func(ctx context.Context, from, to uint64, verify func([]*types.Block) error) ([]*types.Block, error) {
+		requested++
+		fetched := append([]*types.Block{}, blocks[from-1:to]...)
+		if requested == 1 {
+			fetched[0] = blocks[to]
+		}
+		if err := verify(fetched); err != nil {
+			return nil, err
+		}
+		return fetched, nil
+	})
+	fetched, err := m.FetchBlockRange(ctx, 1, 3)
+	require.NoError(err)
+	require.Len(fetched, 3)
+	for i, b := range fetched {
+		assert.Equal(blocks[i].Hash(), b.Hash())
+	}
+	loc, err := m.store.LoadDALocation(2)
+	require.NoError(err)
+	assert.Equal(store.DALocation{DAHeight: 2, Index: 0}, loc)
+	_, ok := m.lazyBlocks.get(3)
+	assert.True(ok)
+
+	// peers return valid blocks
+	fetched, err = m.FetchBlockRange(ctx, 3, 5)
+	require.NoError(err)
+	assert.Equal(2, requested)
+	assert.Equal(blocks[4].Hash(), fetched[2].Hash())
+
+	// header of block 6 is not synced
+	_, err = m.FetchBlockRange(ctx, 5, 6)
+	assert.ErrorIs(err, ErrBlockDataUnavailable)
+	_, err = m.FetchBlockRange(ctx, 1, maxBlockRangeSize+1)
+	assert.Error(err)
+
+	// blocks fetched from peers must be linked, including blocks not verified against headers (block 3 is cached)
+	m.lazyBlocks = newLazyBlockCache(lazyBlockCacheSize)
+	m.lazyBlocks.add(blocks[2])
+	unlinked := *blocks[2]
+	unlinked.SignedHeader.LastHeaderHash = blocks[0].Hash()
+	var verifyErr error
+	m.SetLazyBlockData(headers, func(ctx context.Context, from, to uint64, verify func([]*types.Block) error) ([]*types.Block, error) {
+		verifyErr = verify([]*types.Block{blocks[1], &unlinked, blocks[3]})
+		return nil, verifyErr
+	})
+	_, err = m.FetchBlockRange(ctx, 2, 4)
+	assert.ErrorIs(err, ErrBlockDataUnavailable)
+	assert.ErrorIs(verifyErr, types.ErrLastHeaderHashMismatch)
+
+	// block data can't be verified without hash function
+	assert.ErrorIs((&Manager{}).verifyBlockData(blocks[0], &blocks[0].SignedHeader), types.ErrNoHashFunction)
+}
+
+func TestLazyBlockCache(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newLazyBlockCache(2)
+	for h := uint64(1); h <= 3; h++ {
+		c.add(types.GetRandomBlock(h, 0))
+	}
+	_, ok := c.get(1)
+	assert.False(ok)
+	b, ok := c.get(3)
+	assert.True(ok)
+	assert.Equal(uint64(3), b.Height())
+}
@@ -0,0 +1,375 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	// maxInclusionLists limits the number of tracked inclusion lists.
+	maxInclusionLists = 1000
+	// maxInclusionListViolations limits the number of kept proofs of violations of inclusion lists.
+	maxInclusionListViolations = 100
+)
+
+// ErrTooManyInclusionLists is returned when inclusion list can't be tracked, because too many lists are pending.
+var ErrTooManyInclusionLists = errors.New("too many pending inclusion lists")
+
+// InclusionListStatus describes compliance of the sequencer with inclusion lists tracked by the node.
+type InclusionListStatus struct {
+	// Satisfied is the number of lists with all transactions included within the deadline.
+	Satisfied uint64 `json:"satisfied"`
+	// Violated is the number of lists with transactions missing after the deadline.
+	Violated uint64 `json:"violated"`
+	// SignedViolated is the number of violated lists signed by the proposer.
+	SignedViolated uint64 `json:"signed_violated"`
+	// Pending are lists waiting for inclusion of their transactions.
+	Pending []*types.InclusionList `json:"pending"`
+	// Violations are proofs of the latest violations of signed lists (if enabled).
+	Violations []*types.InclusionListViolation `json:"violations"`
+}
+
+// trackedInclusionList is an inclusion list with hashes of transactions not included yet.
+type trackedInclusionList struct {
+	list    *types.InclusionList
+	missing map[string]struct{}
+}
+
+// inclusionListTracker tracks inclusion lists until their transactions are included, or the deadline passes.
+type inclusionListTracker struct {
+	mtx        sync.Mutex
+	lists      map[string]*trackedInclusionList
+	violations []*types.InclusionListViolation
+	satisfied  uint64
+	violated   uint64
+	signed     uint64
+}
+
+func newInclusionListTracker() *inclusionListTracker {
+	return &inclusionListTracker{lists: make(map[string]*trackedInclusionList)}
+}
+
+// add starts tracking of the list, and returns false if it's already tracked, or all its transactions are in
+// included (hashes of transactions of blocks after the height of the list). Signed version of a tracked list
+// replaces the unsigned one.
+func (t *inclusionListTracker) add(list *types.InclusionList, included map[string]struct{}) (bool, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	hash := list.Hash().String()
+	if tracked, ok := t.lists[hash]; ok {
+		if tracked.list.Signed() || !list.Signed() {
+			return false, nil
+		}
+		tracked.list = list
+		return true, nil
+	}
+	if len(t.lists) >= maxInclusionLists {
+		return false, ErrTooManyInclusionLists
+	}
+	missing := make(map[string]struct{}, len(list.TxHashes))
+	for _, hash := range list.TxHashes {
+		if _, ok := included[string(hash)]; !ok {
+			missing[string(hash)] = struct{}{}
+		}
+	}
+	if len(missing) == 0 {
+		// transactions were included before the list was received, so it says nothing about the sequencer
+		return false, nil
+	}
+	t.lists[hash] = &trackedInclusionList{list: list, missing: missing}
+	return true, nil
+}
+
+// pendingTxHashes returns hashes of transactions of the lists that are not included yet.
+func (t *inclusionListTracker) pendingTxHashes() map[string]struct{} {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	hashes := make(map[string]struct{})
+	for _, tracked := range t.lists {
+		for hash := range tracked.missing {
+			hashes[hash] = struct{}{}
+		}
+	}
+	return hashes
+}
+
+// update marks transactions of the block at given height as included, and returns the number of lists satisfied
+// by the block, and lists violated by the block, i.e. lists with transactions still missing at their last height.
+func (t *inclusionListTracker) update(height uint64, txHashes [][]byte) (int, []*types.InclusionList) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	satisfied := 0
+	var violated []*types.InclusionList
+	for key, tracked := range t.lists {
+		if height <= tracked.list.Height {
+			continue
+		}
+		for _, hash := range txHashes {
+			delete(tracked.missing, string(hash))
+		}
+		switch {
+		case len(tracked.missing) == 0:
+			t.satisfied++
+			satisfied++
+			delete(t.lists, key)
+		case height >= tracked.list.LastHeight():
+			t.violated++
+			if tracked.list.Signed() {
+				t.signed++
+			}
+			violated = append(violated, tracked.list)
+			delete(t.lists, key)
+		}
+	}
+	return satisfied, violated
+}
+
+// addViolation keeps the proof of violation, dropping the oldest one if the limit is reached.
+func (t *inclusionListTracker) addViolation(v *types.InclusionListViolation) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if len(t.violations) >= maxInclusionListViolations {
+		t.violations = t.violations[1:]
+	}
+	t.violations = append(t.violations, v)
+}
+
+func (t *inclusionListTracker) numPending() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return len(t.lists)
+}
+
+func (t *inclusionListTracker) status() *InclusionListStatus {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	status := &InclusionListStatus{
+		Satisfied:      t.satisfied,
+		Violated:       t.violated,
+		SignedViolated: t.signed,
+		Pending:        make([]*types.InclusionList, 0, len(t.lists)),
+		Violations:     append([]*types.InclusionListViolation(nil), t.violations...),
+	}
+	for _, tracked := range t.lists {
+		status.Pending = append(status.Pending, tracked.list)
+	}
+	sort.Slice(status.Pending, func(i, j int) bool {
+		if status.Pending[i].Height != status.Pending[j].Height {
+			return status.Pending[i].Height < status.Pending[j].Height
+		}
+		return bytes.Compare(status.Pending[i].Hash(), status.Pending[j].Hash()) < 0
+	})
+	return status
+}
+
+// AddInclusionList starts tracking of compliance of the sequencer with the inclusion list. Signed lists have to be
+// signed by an aggregator of the current aggregator set. The aggregator signs unsigned lists of transactions it has
+// in the mempool, passes the signed list for gossiping, and returns it. Otherwise, nil is returned.
+func (m *Manager) AddInclusionList(ctx context.Context, list *types.InclusionList) (*types.InclusionList, error) {
+	if list.ChainID != m.genesis.ChainID {
+		return nil, fmt.Errorf("%w: chain ID %q, expected %q", types.ErrInvalidInclusionList, list.ChainID, m.genesis.ChainID)
+	}
+	if err := list.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	height := m.store.Height()
+	if list.LastHeight() <= height {
+		return nil, fmt.Errorf("%w: deadline passed at height %d", types.ErrInvalidInclusionList, list.LastHeight())
+	}
+	if list.Signed() {
+		if err := m.verifyInclusionListSignature(list); err != nil {
+			return nil, err
+		}
+	}
+	included, err := m.includedTxHashes(list.Height+1, height)
+	if err != nil {
+		return nil, err
+	}
+	if added, err := m.inclusionLists.add(list, included); err != nil || !added {
+		return nil, err
+	}
+	if list.Signed() {
+		return nil, nil
+	}
+	signed, err := m.signInclusionList(ctx, list, height)
+	if err != nil {
+		m.logger.Debug("inclusion list not signed", "hash", list.Hash(), "error", err)
+		return nil, nil
+	}
+	if _, err := m.inclusionLists.add(signed, included); err != nil {
+		return nil, err
+	}
+	select {
+	case m.InclusionListOutCh <- signed:
+	default:
+		m.logger.Error("failed to gossip inclusion list, channel full", "hash", signed.Hash())
+	}
+	return signed, nil
+}
+
+// InclusionListStatus returns compliance of the sequencer with inclusion lists tracked by the node.
+func (m *Manager) InclusionListStatus() *InclusionListStatus {
+	return m.inclusionLists.status()
+}
+
+// verifyInclusionListSignature checks that the list is signed by an aggregator of the current aggregator set.
+func (m *Manager) verifyInclusionListSignature(list *types.InclusionList) error {
+	m.lastStateMtx.RLock()
+	vals := m.lastState.Validators
+	m.lastStateMtx.RUnlock()
+	if vals == nil {
+		return fmt.Errorf("%w: no aggregator set", types.ErrInvalidInclusionList)
+	}
+	_, val := vals.GetByAddress(list.ProposerAddress)
+	if val == nil {
+		return fmt.Errorf("%w: signed by unknown aggregator %X", types.ErrInvalidInclusionList, []byte(list.ProposerAddress))
+	}
+	return list.Verify(val.PubKey)
+}
+
+// signInclusionList signs the list, if the node is the proposer, the deadline of the list starts after the latest
+// block, and all transactions of the list are in its mempool.
+func (m *Manager) signInclusionList(ctx context.Context, list *types.InclusionList, height uint64) (*types.InclusionList, error) {
+	if m.sequencer != nil || m.mempool == nil {
+		return nil, errors.New("transactions are not ordered by the aggregator")
+	}
+	if list.Height < height {
+		return nil, fmt.Errorf("list starts at height %d, before the latest block %d", list.Height, height)
+	}
+	isProposer, err := m.IsProposer()
+	if err != nil || !isProposer {
+		return nil, errors.New("node is not the block proposer")
+	}
+	// remote signers refuse to sign different messages for the same height
+	localSigner, ok := m.signer.(*signer.LocalSigner)
+	if !ok {
+		return nil, errors.New("inclusion lists require local signer")
+	}
+	txs := m.inclusionListTxs(list.TxHashes)
+	if len(txs) != len(list.TxHashes) {
+		return nil, fmt.Errorf("%d of %d transactions are not in the mempool", len(list.TxHashes)-len(txs), len(list.TxHashes))
+	}
+	signed := *list
+	signed.ProposerAddress, err = getAddress(localSigner.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	signed.Signature, err = localSigner.Sign(ctx, list.Height, signed.SignBytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign inclusion list: %w", err)
+	}
+	return &signed, nil
+}
+
+// includedTxHashes returns hashes of transactions of blocks from the given range, computed with the hasher of
+// DataHash.
+func (m *Manager) includedTxHashes(from, to uint64) (map[string]struct{}, error) {
+	hasher := m.dataHasher
+	hashes := make(map[string]struct{})
+	for h := from; h <= to; h++ {
+		b, err := m.store.LoadBlock(h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block %d: %w", h, err)
+		}
+		for _, tx := range b.Data.Txs {
+			hashes[string(hasher.Sum(tx))] = struct{}{}
+		}
+	}
+	return hashes, nil
+}
+
+// inclusionListTxs returns transactions from the mempool, with given hashes.
+func (m *Manager) inclusionListTxs(hashes []cmbytes.HexBytes) types.Txs {
+	wanted := make(map[string]struct{}, len(hashes))
+	for _, hash := range hashes {
+		wanted[string(hash)] = struct{}{}
+	}
+	return m.mempoolTxsByHash(wanted)
+}
+
+// mempoolTxsByHash returns transactions from the mempool, which hashes (computed with the hasher of DataHash) are
+// in the set.
+func (m *Manager) mempoolTxsByHash(hashes map[string]struct{}) types.Txs {
+	if len(hashes) == 0 || m.mempool == nil {
+		return nil
+	}
+	hasher := m.dataHasher
+	var txs types.Txs
+	for _, tx := range m.mempool.ListTxs(0, -1) {
+		if _, ok := hashes[string(hasher.Sum(tx))]; ok {
+			txs = append(txs, types.Tx(tx))
+		}
+	}
+	return txs
+}
+
+// withInclusionLists adds transactions of pending inclusion lists from the mempool to the block after priority
+// (pre-confirmed) transactions, up to maxBytes.
+func (m *Manager) withInclusionLists(priority types.Txs, maxBytes int64) types.Txs {
+	var size int64
+	seen := make(map[string]struct{}, len(priority))
+	for _, tx := range priority {
+		seen[string(tx)] = struct{}{}
+		size += int64(len(tx))
+	}
+	for _, tx := range m.mempoolTxsByHash(m.inclusionLists.pendingTxHashes()) {
+		if _, ok := seen[string(tx)]; ok {
+			continue
+		}
+		if size+int64(len(tx)) > maxBytes {
+			break
+		}
+		priority = append(priority, tx)
+		size += int64(len(tx))
+	}
+	return priority
+}
+
+// trackInclusionLists updates compliance with inclusion lists after the block is committed. Violations of lists
+// signed by the proposer are proven, if enabled.
+func (m *Manager) trackInclusionLists(block *types.Block) {
+	hasher := m.dataHasher
+	hashes := make([][]byte, len(block.Data.Txs))
+	for i, tx := range block.Data.Txs {
+		hashes[i] = hasher.Sum(tx)
+	}
+	satisfied, violated := m.inclusionLists.update(block.Height(), hashes)
+	m.metrics.InclusionLists.With("result", InclusionListSatisfied).Add(float64(satisfied))
+	for _, list := range violated {
+		m.metrics.InclusionLists.With("result", InclusionListViolated).Add(1)
+		m.logger.Error("inclusion list violated", "hash", list.Hash(), "height", list.Height,
+			"deadline", list.Deadline, "signed", list.Signed())
+		if !list.Signed() || !m.conf.InclusionListEvidence {
+			continue
+		}
+		v, err := m.proveInclusionListViolation(list, hasher)
+		if err != nil {
+			m.logger.Error("failed to prove violation of inclusion list", "hash", list.Hash(), "error", err)
+			continue
+		}
+		m.inclusionLists.addViolation(v)
+	}
+	m.metrics.PendingInclusionLists.Set(float64(m.inclusionLists.numPending()))
+}
+
+// proveInclusionListViolation creates proof of violation of the signed list from blocks within its deadline.
+func (m *Manager) proveInclusionListViolation(list *types.InclusionList, hasher types.MerkleHasher) (*types.InclusionListViolation, error) {
+	blocks := make([]*types.Block, 0, list.Deadline)
+	for h := list.Height + 1; h <= list.LastHeight(); h++ {
+		b, err := m.store.LoadBlock(h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block %d: %w", h, err)
+		}
+		blocks = append(blocks, b)
+	}
+	return types.NewInclusionListViolation(list, blocks, hasher)
+}
@@ -0,0 +1,112 @@
+package block
+
+import (
+	"testing"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func testInclusionList(height, deadline uint64, txs ...types.Tx) *types.InclusionList {
+	list := &types.InclusionList{ChainID: types.TestChainID, Height: height, Deadline: deadline}
+	for _, tx := range txs {
+		list.TxHashes = append(list.TxHashes, tx.Hash())
+	}
+	return list
+}
+
+func TestInclusionListTracker(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tr := newInclusionListTracker()
+	satisfiedList := testInclusionList(5, 2, types.Tx("tx1"), types.Tx("tx2"))
+	violatedList := testInclusionList(5, 3, types.Tx("tx1"), types.Tx("tx3"))
+
+	added, err := tr.add(satisfiedList, nil)
+	require.NoError(err)
+	assert.True(added)
+	added, err = tr.add(violatedList, nil)
+	require.NoError(err)
+	assert.True(added)
+	added, err = tr.add(satisfiedList, nil)
+	require.NoError(err)
+	assert.False(added)
+
+	// signed list replaces the unsigned one
+	signed := *violatedList
+	signed.ProposerAddress = cmbytes.HexBytes("proposer")
+	signed.Signature = types.Signature("signature")
+	added, err = tr.add(&signed, nil)
+	require.NoError(err)
+	assert.True(added)
+	assert.Equal(2, tr.numPending())
+
+	// transactions included before the list was received
+	added, err = tr.add(testInclusionList(5, 2, types.Tx("tx4")), map[string]struct{}{string(types.Tx("tx4").Hash()): {}})
+	require.NoError(err)
+	assert.False(added)
+
+	assert.Len(tr.pendingTxHashes(), 3)
+
+	// blocks at or below the height of the list are ignored
+	satisfied, violated := tr.update(5, [][]byte{types.Tx("tx2").Hash()})
+	assert.Zero(satisfied)
+	assert.Empty(violated)
+
+	satisfied, violated = tr.update(6, [][]byte{types.Tx("tx1").Hash()})
+	assert.Zero(satisfied)
+	assert.Empty(violated)
+	satisfied, violated = tr.update(7, [][]byte{types.Tx("tx2").Hash()})
+	assert.Equal(1, satisfied)
+	assert.Empty(violated)
+	satisfied, violated = tr.update(8, nil)
+	assert.Zero(satisfied)
+	assert.Equal([]*types.InclusionList{&signed}, violated)
+	assert.Zero(tr.numPending())
+
+	status := tr.status()
+	assert.EqualValues(1, status.Satisfied)
+	assert.EqualValues(1, status.Violated)
+	assert.EqualValues(1, status.SignedViolated)
+	assert.Empty(status.Pending)
+}
+
+func TestInclusionListTrackerStatus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tr := newInclusionListTracker()
+	lists := []*types.InclusionList{
+		testInclusionList(7, 1, types.Tx("tx1")),
+		testInclusionList(3, 1, types.Tx("tx2")),
+		testInclusionList(5, 1, types.Tx("tx3")),
+	}
+	for _, list := range lists {
+		_, err := tr.add(list, nil)
+		require.NoError(err)
+	}
+	status := tr.status()
+	require.Len(status.Pending, 3)
+	assert.Equal([]*types.InclusionList{lists[1], lists[2], lists[0]}, status.Pending)
+
+	for i := 0; i < maxInclusionListViolations+1; i++ {
+		tr.addViolation(&types.InclusionListViolation{List: types.InclusionList{Height: uint64(i)}})
+	}
+	status = tr.status()
+	require.Len(status.Violations, maxInclusionListViolations)
+	assert.EqualValues(1, status.Violations[0].List.Height)
+}
+
+func TestInclusionListTrackerLimit(t *testing.T) {
+	tr := newInclusionListTracker()
+	for i := 0; i < maxInclusionLists; i++ {
+		_, err := tr.add(testInclusionList(uint64(i), 1, types.Tx("tx")), nil)
+		require.NoError(t, err)
+	}
+	_, err := tr.add(testInclusionList(maxInclusionLists, 1, types.Tx("tx")), nil)
+	assert.ErrorIs(t, err, ErrTooManyInclusionLists)
+}
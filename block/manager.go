@@ -4,27 +4,36 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	goheaderstore "github.com/celestiaorg/go-header/store"
 	abci "github.com/cometbft/cometbft/abci/types"
-	cmcrypto "github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/crypto/merkle"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
 	"github.com/cometbft/cometbft/proxy"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 
+	"github.com/rollkit/rollkit/clock"
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/crypto/bls"
 	"github.com/rollkit/rollkit/da"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/sequencing"
+	"github.com/rollkit/rollkit/settlement"
+	"github.com/rollkit/rollkit/signer"
 	"github.com/rollkit/rollkit/state"
 	"github.com/rollkit/rollkit/store"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/tracing"
 	"github.com/rollkit/rollkit/types"
 )
 
@@ -34,19 +43,45 @@ const defaultDABlockTime = 15 * time.Second
 // defaultBlockTime is used only if BlockTime is not configured for manager
 const defaultBlockTime = 1 * time.Second
 
+// defaultEncryptedTxsWindow is used only if EncryptedTxsWindow is not configured for manager
+const defaultEncryptedTxsWindow = 100
+
 // maxSubmitAttempts defines how many times Rollkit will re-try to publish block to DA layer.
 // This is temporary solution. It will be removed in future versions.
 const maxSubmitAttempts = 30
 
+// maxSignAttempts defines how many times Rollkit will re-try to sign a block.
+const maxSignAttempts = 3
+
 // Applies to most channels, 100 is a large enough buffer to avoid blocking
 const channelLength = 100
 
 // Applies to the blockInCh, 10000 is a large enough number for blocks per DA block.
 const blockInChLength = 10000
 
+// maxVerifyBatch is the maximal number of blocks waiting in blockInCh, which commits are verified together
+// before the blocks are synced.
+const maxVerifyBatch = 256
+
+// maxDAIncludedScanHeights limits the number of the most recent blocks searched for the latest DA included block,
+// when the manager is created.
+const maxDAIncludedScanHeights = 1000
+
 // initialBackoff defines initial value for block submission backoff
 var initialBackoff = 100 * time.Millisecond
 
+// ErrEvidenceHeightUnknown is returned when verifying evidence for a height that is not synced by the node yet.
+var ErrEvidenceHeightUnknown = errors.New("evidence for height not synced by the node")
+
+// ErrBatchNotSequenced is returned when transactions of a synced block were not ordered by the sequencer.
+var ErrBatchNotSequenced = errors.New("block transactions were not ordered by the sequencer")
+
+// ErrUnauthorizedSequencer is returned when a synced block wasn't produced by a sequencer from the aggregator set.
+var ErrUnauthorizedSequencer = errors.New("block not produced by an authorized sequencer")
+
+// ErrDAHeightUnknown is returned when the DA block containing a block is not known to the node.
+var ErrDAHeightUnknown = errors.New("DA height of block unknown")
+
 type newBlockEvent struct {
 	block    *types.Block
 	daHeight uint64
@@ -61,19 +96,69 @@ type Manager struct {
 
 	conf    config.BlockManagerConfig
 	genesis *cmtypes.GenesisDoc
+	// dataHasher computes DataHash of blocks of the chain
+	dataHasher types.MerkleHasher
 
-	proposerKey crypto.PrivKey
+	signer signer.Signer
+	// aggregatorKeys are BLS public keys of aggregators; if set, signatures are aggregated into a single signature
+	aggregatorKeys []bls.PubKey
 
 	executor *state.BlockExecutor
+	// prover is optional, used to generate validity proofs of produced blocks
+	prover state.Prover
+	// extender is optional, used to get extensions of headers of produced blocks
+	extender state.HeaderExtender
+	// sequencer is optional, used to order transactions of produced blocks instead of the mempool,
+	// and to verify ordering of transactions of synced blocks
+	sequencer sequencing.Sequencer
+	// lastBatchHash is the hash of the last batch of transactions from the sequencer, included in a block
+	lastBatchHash []byte
 
 	dalc      da.DataAvailabilityLayerClient
 	retriever da.BlockRetriever
 	// daHeight is the height of the latest processed DA block
 	daHeight uint64
+	// daIncludedHeight is the height of the latest block in the store with known location on DA layer
+	daIncludedHeight atomic.Uint64
+
+	// settlement is optional, used to post commitments of blocks submitted to DA layer and to read their finality;
+	// sovereign rollups don't use it
+	settlement settlement.Client
+	// pendingCommitments keeps commitments of blocks submitted to DA layer until they're posted to settlement layer
+	pendingCommitments    []*settlement.Commitment
+	pendingCommitmentsMtx sync.Mutex
+	// settledHeight is the height of the latest block finalized on the settlement layer
+	settledHeight atomic.Uint64
+	// reportedDisputes are heights of disputed commitments already reported by SettlementLoop
+	reportedDisputes map[uint64]struct{}
+	// daCostReporter is optional, it feeds costs of DA submissions back to the application
+	daCostReporter state.DACostReporter
 
 	HeaderCh chan *types.SignedHeader
 	BlockCh  chan *types.Block
 
+	// FraudProofInCh is used to pass state fraud proofs received from other nodes to SyncLoop
+	FraudProofInCh chan *types.StateFraudProof
+	// FraudProofOutCh is used to pass state fraud proofs generated by this node for gossiping
+	FraudProofOutCh chan *types.StateFraudProof
+	// haltProof is the verified state fraud proof that caused the node to halt
+	haltProof atomic.Pointer[types.StateFraudProof]
+
+	// EvidenceInCh is used to pass evidence of aggregator equivocation received from other nodes to SyncLoop
+	EvidenceInCh chan *types.DuplicateHeaderEvidence
+	// EvidenceOutCh is used to pass evidence of aggregator equivocation detected by this node for gossiping
+	EvidenceOutCh chan *types.DuplicateHeaderEvidence
+	// InclusionListOutCh is used to pass inclusion lists signed by this node (as the proposer) for gossiping
+	InclusionListOutCh chan *types.InclusionList
+	// evidencePool keeps verified evidence until it's committed in a block
+	evidencePool *evidencePool
+	// preConfirmations keeps transactions pre-confirmed by the aggregator until they're committed in a block
+	preConfirmations *preConfirmationPool
+	// inclusionLists tracks compliance of the sequencer with inclusion lists submitted by full nodes
+	inclusionLists *inclusionListTracker
+	// mempool is used to find transactions of inclusion lists
+	mempool mempool.Mempool
+
 	blockInCh  chan newBlockEvent
 	blockStore *goheaderstore.Store[*types.Block]
 
@@ -93,6 +178,42 @@ type Manager struct {
 	doneBuildingBlock chan struct{}
 
 	pendingBlocks *PendingBlocks
+
+	// blockMtx serializes producing and syncing of blocks with administrative operations (e.g. Rollback)
+	blockMtx sync.Mutex
+	// halted disables producing and syncing of blocks
+	halted atomic.Bool
+	// daDegraded is set while DA layer is unavailable (see IsDADegraded)
+	daDegraded atomic.Bool
+	// daReconnectAt and daReconnectBackoff schedule attempts to reconnect to DA layer in degraded mode
+	daReconnectAt      time.Time
+	daReconnectBackoff time.Duration
+	// pendingLimitReached is set while block production is stopped, because of too many pending blocks
+	pendingLimitReached bool
+	// appWarm is set after the application is brought up to date with the state by WarmUp
+	appWarm atomic.Bool
+	// byzantine is set only in tests built with the byzantine tag, to make the aggregator misbehave
+	byzantine *byzantineBehavior
+
+	// clock is the source of time of produced blocks
+	clock clock.Clock
+
+	withholding *withholdingWatchdog
+
+	// snapshotDA and snapshotApp are set if application snapshots are published to (or restored from) DA layer
+	snapshotDA          da.BlobClient
+	snapshotApp         proxy.AppConnSnapshot
+	snapshotTrustedHash []byte
+	// lastSnapshotHeight is the height of the last snapshot published by SnapshotPublishLoop
+	lastSnapshotHeight uint64
+
+	// syncHeaders and fetchPeerBlocks are set if block data missing in the store is fetched on demand
+	// (see SetLazyBlockData); lazyBlocks keeps fetched blocks not saved in the store
+	syncHeaders     HeaderGetter
+	fetchPeerBlocks BlockRangeFetcher
+	lazyBlocks      *lazyBlockCache
+
+	metrics *Metrics
 }
 
 // getInitialState tries to load lastState from Store, and if it's not available it reads GenesisDoc.
@@ -104,19 +225,30 @@ func getInitialState(store store.Store, genesis *cmtypes.GenesisDoc) (types.Stat
 	return s, err
 }
 
-// NewManager creates new block Manager.
+// NewManager creates new block Manager. DataHash of blocks is computed with dataHasher (see
+// types.DataHasherFromGenesis); an error is returned if it has no hash function.
 func NewManager(
-	proposerKey crypto.PrivKey,
+	signer signer.Signer,
 	conf config.BlockManagerConfig,
 	genesis *cmtypes.GenesisDoc,
+	dataHasher types.MerkleHasher,
 	store store.Store,
 	mempool mempool.Mempool,
 	proxyApp proxy.AppConnConsensus,
+	isrProvider state.IntermediateStateRootProvider,
+	txValidator state.TxValidator,
+	prover state.Prover,
+	extender state.HeaderExtender,
 	dalc da.DataAvailabilityLayerClient,
 	eventBus *cmtypes.EventBus,
+	metrics *state.Metrics,
+	blockMetrics *Metrics,
 	logger log.Logger,
 	blockStore *goheaderstore.Store[*types.Block],
 ) (*Manager, error) {
+	if err := dataHasher.Validate(); err != nil {
+		return nil, err
+	}
 	s, err := getInitialState(store, genesis)
 	if err != nil {
 		return nil, err
@@ -125,10 +257,21 @@ func NewManager(
 		s.DAHeight = conf.DAStartHeight
 	}
 
-	proposerAddress, err := getAddress(proposerKey)
+	proposerAddress, err := getAddress(signer.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScheme(signer.PubKey(), genesis); err != nil {
+		return nil, err
+	}
+
+	aggregatorKeys, err := parseAggregatorKeys(conf.AggregatorKeys)
 	if err != nil {
 		return nil, err
 	}
+	if len(aggregatorKeys) > 0 && !isAggregateSigner(signer) {
+		return nil, errors.New("signer doesn't support BLS signatures required by aggregator keys")
+	}
 
 	if conf.DABlockTime == 0 {
 		logger.Info("Using default DA block time", "DABlockTime", defaultDABlockTime)
@@ -140,19 +283,45 @@ func NewManager(
 		conf.BlockTime = defaultBlockTime
 	}
 
-	exec := state.NewBlockExecutor(proposerAddress, conf.NamespaceID, genesis.ChainID, mempool, proxyApp, eventBus, logger)
-	if s.LastBlockHeight+1 == uint64(genesis.InitialHeight) {
+	exec, err := state.NewBlockExecutor(proposerAddress, conf.NamespaceID, genesis.ChainID, dataHasher, mempool, proxyApp, isrProvider, txValidator, eventBus, conf.ABCITimeout, metrics, logger)
+	if err != nil {
+		return nil, err
+	}
+	if conf.EncryptedTxsDelay > 0 {
+		committeeKey, err := state.ParseCommitteeKey(conf.EncryptedTxsKey)
+		if err != nil {
+			return nil, err
+		}
+		if conf.EncryptedTxsWindow == 0 {
+			conf.EncryptedTxsWindow = defaultEncryptedTxsWindow
+		}
+		exec.SetEncryptedTxs(&state.EncryptedTxs{
+			Key:                committeeKey,
+			Delay:              conf.EncryptedTxsDelay,
+			Window:             conf.EncryptedTxsWindow,
+			LoadBlock:          store.LoadBlock,
+			LoadBlockResponses: store.LoadBlockResponses,
+		})
+	}
+	// application state restored from a snapshot is not initialized with genesis
+	if s.LastBlockHeight+1 == uint64(genesis.InitialHeight) && conf.SnapshotDAHeight == 0 {
 		res, err := exec.InitChain(genesis)
 		if err != nil {
 			return nil, err
 		}
 
-		updateState(&s, res)
+		UpdateStateFromInitChain(&s, res)
 		if err := store.UpdateState(s); err != nil {
 			return nil, err
 		}
 	}
 
+	if blockMetrics == nil {
+		blockMetrics = NopMetrics()
+	}
+	blockMetrics.Height.Set(float64(s.LastBlockHeight))
+	blockMetrics.DAHeight.Set(float64(s.DAHeight))
+
 	var txsAvailableCh <-chan struct{}
 	if mempool != nil {
 		txsAvailableCh = mempool.TxsAvailable()
@@ -161,39 +330,102 @@ func NewManager(
 	}
 
 	agg := &Manager{
-		proposerKey: proposerKey,
-		conf:        conf,
-		genesis:     genesis,
-		lastState:   s,
-		store:       store,
-		executor:    exec,
-		dalc:        dalc,
-		retriever:   dalc.(da.BlockRetriever), // TODO(tzdybal): do it in more gentle way (after MVP)
-		daHeight:    s.DAHeight,
+		signer:         signer,
+		aggregatorKeys: aggregatorKeys,
+		conf:           conf,
+		genesis:        genesis,
+		dataHasher:     dataHasher,
+		lastState:      s,
+		store:          store,
+		executor:       exec,
+		prover:         prover,
+		extender:       extender,
+		dalc:           dalc,
+		retriever:      dalc.(da.BlockRetriever), // TODO(tzdybal): do it in more gentle way (after MVP)
+		daHeight:       s.DAHeight,
 		// channels are buffered to avoid blocking on input/output operations, buffer sizes are arbitrary
-		HeaderCh:          make(chan *types.SignedHeader, channelLength),
-		BlockCh:           make(chan *types.Block, channelLength),
-		blockInCh:         make(chan newBlockEvent, blockInChLength),
-		blockStoreCh:      make(chan struct{}, 1),
-		blockStore:        blockStore,
-		lastStateMtx:      new(sync.RWMutex),
-		blockCache:        NewBlockCache(),
-		retrieveCh:        make(chan struct{}, 1),
-		logger:            logger,
-		txsAvailable:      txsAvailableCh,
-		doneBuildingBlock: make(chan struct{}),
-		buildingBlock:     false,
-		pendingBlocks:     NewPendingBlocks(),
+		HeaderCh:           make(chan *types.SignedHeader, channelLength),
+		BlockCh:            make(chan *types.Block, channelLength),
+		FraudProofInCh:     make(chan *types.StateFraudProof, channelLength),
+		FraudProofOutCh:    make(chan *types.StateFraudProof, 1),
+		EvidenceInCh:       make(chan *types.DuplicateHeaderEvidence, channelLength),
+		EvidenceOutCh:      make(chan *types.DuplicateHeaderEvidence, channelLength),
+		InclusionListOutCh: make(chan *types.InclusionList, channelLength),
+		evidencePool:       newEvidencePool(),
+		preConfirmations:   newPreConfirmationPool(),
+		inclusionLists:     newInclusionListTracker(),
+		lazyBlocks:         newLazyBlockCache(lazyBlockCacheSize),
+		mempool:            mempool,
+		blockInCh:          make(chan newBlockEvent, blockInChLength),
+		blockStoreCh:       make(chan struct{}, 1),
+		blockStore:         blockStore,
+		lastStateMtx:       new(sync.RWMutex),
+		blockCache:         NewBlockCache(),
+		retrieveCh:         make(chan struct{}, 1),
+		logger:             logger,
+		txsAvailable:       txsAvailableCh,
+		doneBuildingBlock:  make(chan struct{}),
+		buildingBlock:      false,
+		pendingBlocks:      NewPendingBlocks(),
+		clock:              clock.Real,
+		withholding:        newWithholdingWatchdog(),
+		metrics:            blockMetrics,
 	}
+	agg.daIncludedHeight.Store(agg.lastDAIncludedHeight())
 	return agg, nil
 }
 
-func getAddress(key crypto.PrivKey) ([]byte, error) {
-	rawKey, err := key.GetPublic().Raw()
+// getAddress derives the proposer address of the key (see signer.Address).
+func getAddress(key crypto.PubKey) ([]byte, error) {
+	return signer.Address(key)
+}
+
+// checkScheme ensures that the key uses signature scheme of the chain, selected in genesis.
+func checkScheme(key crypto.PubKey, genesis *cmtypes.GenesisDoc) error {
+	pubKey, err := signer.CometPubKey(key)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if scheme := signer.SchemeFromGenesis(genesis); pubKey.Type() != scheme {
+		return fmt.Errorf("%w: signer key type %s, chain signature scheme %s", signer.ErrUnsupportedScheme, pubKey.Type(), scheme)
 	}
-	return cmcrypto.AddressHash(rawKey), nil
+	return nil
+}
+
+// parseAggregatorKeys decodes BLS public keys of aggregators, hex encoded with proofs of possession of their
+// private keys (<key>:<proof>). Proofs are verified, so aggregated signatures can't be forged with rogue keys.
+func parseAggregatorKeys(encoded []string) ([]bls.PubKey, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	keys := make([]bls.PubKey, len(encoded))
+	for i, e := range encoded {
+		encodedKey, encodedProof, found := strings.Cut(e, ":")
+		if !found {
+			return nil, fmt.Errorf("aggregator key %d: %w", i, bls.ErrInvalidProof)
+		}
+		key, err := hex.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode aggregator key %d: %w", i, err)
+		}
+		proof, err := hex.DecodeString(encodedProof)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode proof of possession of aggregator key %d: %w", i, err)
+		}
+		keys[i] = key
+		if err := keys[i].ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("invalid aggregator key %d: %w", i, err)
+		}
+		if err := keys[i].VerifyPossession(proof); err != nil {
+			return nil, fmt.Errorf("aggregator key %d: %w", i, err)
+		}
+	}
+	return keys, nil
+}
+
+func isAggregateSigner(s signer.Signer) bool {
+	_, ok := s.(signer.AggregateSigner)
+	return ok
 }
 
 // SetDALC is used to set DataAvailabilityLayerClient used by Manager.
@@ -202,6 +434,35 @@ func (m *Manager) SetDALC(dalc da.DataAvailabilityLayerClient) {
 	m.retriever = dalc.(da.BlockRetriever)
 }
 
+// SetClock sets the source of time of produced blocks, e.g. a virtual clock of a simulation.
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+	m.executor.SetClock(c)
+}
+
+// SetSettlement sets the settlement layer client used by Manager. Commitments of blocks submitted to DA layer
+// are posted to the settlement layer by SettlementLoop.
+func (m *Manager) SetSettlement(c settlement.Client) {
+	m.settlement = c
+}
+
+// SetDACostReporter sets the reporter of costs of DA submissions to the application, e.g. for fee market pricing.
+func (m *Manager) SetDACostReporter(r state.DACostReporter) {
+	m.daCostReporter = r
+}
+
+// SetSequencer sets the (shared) sequencer used by Manager. Produced blocks contain batches of transactions
+// ordered by the sequencer, instead of transactions reaped from the mempool.
+func (m *Manager) SetSequencer(seq sequencing.Sequencer) {
+	m.sequencer = seq
+	m.lastBatchHash = nil
+	if height := m.store.Height(); height > 0 {
+		if block, err := m.store.LoadBlock(height); err == nil && len(block.Data.Txs) > 0 {
+			m.lastBatchHash = sequencing.TxsHash(block.Data.Txs)
+		}
+	}
+}
+
 // GetStoreHeight returns the manager's store height
 func (m *Manager) GetStoreHeight() uint64 {
 	return m.store.Height()
@@ -212,6 +473,65 @@ func (m *Manager) IsDAIncluded(hash types.Hash) bool {
 	return m.blockCache.isDAIncluded(hash.String())
 }
 
+// GetDAHeight returns the height of DA block containing the block at given height.
+// DA heights are known for blocks submitted or retrieved from DA layer by this node.
+func (m *Manager) GetDAHeight(height uint64) (uint64, error) {
+	loc, err := m.store.LoadDALocation(height)
+	if err != nil {
+		return 0, fmt.Errorf("%w: height %d", ErrDAHeightUnknown, height)
+	}
+	return loc.DAHeight, nil
+}
+
+// DAIncludedHeight returns the height of the latest block in the store, which was submitted to or retrieved from
+// DA layer by this node. Blocks above it are soft confirmed only.
+func (m *Manager) DAIncludedHeight() uint64 {
+	// blocks could be rolled back since
+	return min(m.daIncludedHeight.Load(), m.store.Height())
+}
+
+// setDAIncludedHeight raises the height returned by DAIncludedHeight, after location of the block at given height
+// was saved in the store.
+func (m *Manager) setDAIncludedHeight(height uint64) {
+	for {
+		current := m.daIncludedHeight.Load()
+		if height <= current || m.daIncludedHeight.CompareAndSwap(current, height) {
+			return
+		}
+	}
+}
+
+// lastDAIncludedHeight searches the most recent blocks in the store for the latest block with known DA location.
+func (m *Manager) lastDAIncludedHeight() uint64 {
+	height := m.store.Height()
+	for h := height; h > 0 && height-h < maxDAIncludedScanHeights; h-- {
+		if _, err := m.store.LoadDALocation(h); err == nil {
+			return h
+		}
+	}
+	return 0
+}
+
+// saveDALocation records the location of the block on the DA layer. It's persisted in the store if the block
+// is already stored, or when the block is synced otherwise.
+func (m *Manager) saveDALocation(block *types.Block, loc store.DALocation) {
+	hash := block.Hash()
+	m.blockCache.setDALocation(hash.String(), loc)
+	height := uint64(block.Height())
+	if height > m.store.Height() {
+		return
+	}
+	stored, err := m.store.LoadBlock(height)
+	if err != nil || !bytes.Equal(stored.Hash(), hash) {
+		return
+	}
+	if err := m.store.SaveDALocation(height, loc); err != nil {
+		m.logger.Error("failed to save DA location of block", "height", height, "daHeight", loc.DAHeight, "error", err)
+		return
+	}
+	m.setDAIncludedHeight(height)
+}
+
 // AggregationLoop is responsible for aggregating transactions into rollup-blocks.
 func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
 	initialHeight := uint64(m.genesis.InitialHeight)
@@ -220,10 +540,10 @@ func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
 
 	// TODO(tzdybal): double-check when https://github.com/celestiaorg/rollmint/issues/699 is resolved
 	if height < initialHeight {
-		delay = time.Until(m.genesis.GenesisTime)
+		delay = m.genesis.GenesisTime.Sub(m.clock.Now())
 	} else {
 		lastBlockTime := m.getLastBlockTime()
-		delay = time.Until(lastBlockTime.Add(m.conf.BlockTime))
+		delay = lastBlockTime.Add(m.conf.BlockTime).Sub(m.clock.Now())
 	}
 
 	if delay > 0 {
@@ -240,7 +560,7 @@ func (m *Manager) AggregationLoop(ctx context.Context, lazy bool) {
 				return
 			case <-timer.C:
 			}
-			start := time.Now()
+			start := m.clock.Now()
 			err := m.publishBlock(ctx)
 			if err != nil {
 				m.logger.Error("error while publishing block", "error", err)
@@ -285,11 +605,12 @@ func (m *Manager) BlockSubmissionLoop(ctx context.Context) {
 			return
 		case <-timer.C:
 		}
-		if m.pendingBlocks.isEmpty() {
+		if m.pendingBlocks.isEmpty() || !m.daReconnectDue(ctx) {
 			continue
 		}
 		err := m.submitBlocksToDA(ctx)
-		if err != nil {
+		// unavailability of DA layer is reported once, when entering degraded mode
+		if err != nil && !errors.Is(err, ErrDAUnavailable) {
 			m.logger.Error("error while submitting block to DA", "error", err)
 		}
 	}
@@ -309,36 +630,115 @@ func (m *Manager) SyncLoop(ctx context.Context, cancel context.CancelFunc) {
 		case <-blockTicker.C:
 			m.sendNonBlockingSignalToBlockStoreCh()
 		case blockEvent := <-m.blockInCh:
-			block := blockEvent.block
-			daHeight := blockEvent.daHeight
-			blockHash := block.Hash().String()
-			blockHeight := uint64(block.Height())
-			m.logger.Debug("block body retrieved",
-				"height", blockHeight,
-				"daHeight", daHeight,
-				"hash", blockHash,
-			)
-			if m.blockCache.isSeen(blockHash) {
-				m.logger.Debug("block already seen", "height", blockHeight, "block hash", blockHash)
-				continue
+			events := m.pendingBlockEvents(blockEvent)
+			m.verifyCommits(events)
+			for _, event := range events {
+				err := m.processBlockEvent(ctx, event)
+				if proof := m.HaltProof(); proof != nil {
+					// node is stopped after the proof is gossiped
+					m.FraudProofOutCh <- proof
+					return
+				}
+				if err != nil {
+					m.logger.Info("failed to sync next block", "error", err)
+				}
 			}
-			m.blockCache.setBlock(blockHeight, block)
-
-			m.sendNonBlockingSignalToBlockStoreCh()
-			m.sendNonBlockingSignalToRetrieveCh()
-
-			err := m.trySyncNextBlock(ctx, daHeight)
-			if err != nil {
-				m.logger.Info("failed to sync next block", "error", err)
+		case proof := <-m.FraudProofInCh:
+			if err := m.processFraudProof(proof); err != nil {
+				m.logger.Info("rejected fraud proof", "height", proof.BlockHeight, "error", err)
 				continue
 			}
-			m.blockCache.setSeen(blockHash)
+			// received proof is already relayed by P2P gossip
+			cancel()
+			return
+		case ev := <-m.EvidenceInCh:
+			// received evidence is already verified and relayed by P2P gossip
+			m.addEvidence(ev, false)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// pendingBlockEvents returns the event, followed by events already waiting in blockInCh (up to maxVerifyBatch
+// events in total). During catch-up sync, many blocks retrieved from DA or P2P network are waiting to be synced.
+func (m *Manager) pendingBlockEvents(first newBlockEvent) []newBlockEvent {
+	events := []newBlockEvent{first}
+	for len(events) < maxVerifyBatch {
+		select {
+		case event := <-m.blockInCh:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+	return events
+}
+
+// verifyCommits verifies signatures of commits of multiple blocks across a pool of workers, so that
+// they are not verified one by one when blocks are synced. Invalid commits are not reported here; they are rejected
+// when the block is synced.
+func (m *Manager) verifyCommits(events []newBlockEvent) {
+	if len(events) < 2 {
+		return
+	}
+	headers := make([]*types.SignedHeader, 0, len(events))
+	for _, event := range events {
+		if !m.blockCache.isSeen(event.block.Hash().String()) {
+			headers = append(headers, &event.block.SignedHeader)
+		}
+	}
+	start := time.Now()
+	errs := types.VerifyCommits(headers, m.aggregatorKeys, 0)
+	invalid := 0
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, types.ErrNoAggregatorSet) {
+			invalid++
+		}
+	}
+	m.logger.Debug("verified commits of blocks", "blocks", len(headers), "invalid", invalid, "duration", time.Since(start))
+}
+
+// processBlockEvent caches the block retrieved from P2P or DA network, and tries to sync the next block.
+// Blocks conflicting with synced blocks are not cached, evidence of equivocation is created instead.
+func (m *Manager) processBlockEvent(ctx context.Context, blockEvent newBlockEvent) error {
+	block := blockEvent.block
+	daHeight := blockEvent.daHeight
+	blockHash := block.Hash().String()
+	blockHeight := uint64(block.Height())
+	m.logger.Debug("block body retrieved",
+		"height", blockHeight,
+		"daHeight", daHeight,
+		"hash", blockHash,
+	)
+	if m.blockCache.isSeen(blockHash) {
+		m.logger.Debug("block already seen", "height", blockHeight, "block hash", blockHash)
+		return nil
+	}
+	if ev := m.detectDuplicateHeader(block); ev != nil {
+		m.addEvidence(ev, true)
+		return nil
+	}
+	m.blockCache.setBlock(blockHeight, block)
+
+	m.sendNonBlockingSignalToBlockStoreCh()
+	m.sendNonBlockingSignalToRetrieveCh()
+
+	// the same block is delivered by gossip and by the block store, and the second event is skipped as seen, so
+	// all cached blocks that can be synced are synced, not only the next one
+	for {
+		height := m.store.Height()
+		if err := m.trySyncNextBlock(ctx, daHeight); err != nil {
+			return err
+		}
+		if m.store.Height() == height {
+			break
+		}
+	}
+	m.blockCache.setSeen(blockHash)
+	return nil
+}
+
 func (m *Manager) sendNonBlockingSignalToBlockStoreCh() {
 	select {
 	case m.blockStoreCh <- struct{}{}:
@@ -358,7 +758,13 @@ func (m *Manager) sendNonBlockingSignalToRetrieveCh() {
 // To be able to apply block and height h, we need to have its Commit. It is contained in block at height h+1.
 // If block at height h+1 is not available, value of last gossiped commit is checked.
 // If commit for block h is available, we proceed with sync process, and remove synced block from sync cache.
-func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
+func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) (err error) {
+	m.blockMtx.Lock()
+	defer m.blockMtx.Unlock()
+	if m.halted.Load() {
+		return nil
+	}
+
 	var commit *types.Commit
 	currentHeight := m.store.Height() // TODO(tzdybal): maybe store a copy in memory
 
@@ -367,6 +773,11 @@ func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
 		return nil
 	}
 
+	ctx, span := tracing.Start(ctx, "Manager.syncBlock",
+		attribute.Int64("height", int64(currentHeight+1)),
+		attribute.Int64("da_height", int64(daHeight)))
+	defer func() { tracing.End(span, err) }()
+
 	signedHeader := &b.SignedHeader
 	if signedHeader != nil {
 		commit = &b.SignedHeader.Commit
@@ -374,36 +785,84 @@ func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
 
 	if b != nil && commit != nil {
 		bHeight := uint64(b.Height())
+		if m.softConfirmationsHalted() && !m.blockCache.isDAIncluded(b.Hash().String()) {
+			m.logger.Debug("waiting for block on DA layer, soft confirmations halted due to data withholding", "height", bHeight)
+			return nil
+		}
 		m.logger.Info("Syncing block", "height", bHeight)
 		// Validate the received block before applying
+		if err := validateHeaderTime(m.clock.Now(), m.conf.MaxFutureTime, &b.SignedHeader); err != nil {
+			return fmt.Errorf("failed to validate block: %w", err)
+		}
 		if err := m.executor.Validate(m.lastState, b); err != nil {
 			return fmt.Errorf("failed to validate block: %w", err)
 		}
+		if bHeight > uint64(m.genesis.InitialHeight) {
+			lastBlock, err := m.store.LoadBlock(currentHeight)
+			if err != nil {
+				return fmt.Errorf("failed to load last block: %w", err)
+			}
+			if err := b.ValidateWithLast(lastBlock); err != nil {
+				return fmt.Errorf("failed to validate block: %w", err)
+			}
+		}
+		if err := m.verifySequencer(b); err != nil {
+			return err
+		}
+		if err := m.verifyBatch(ctx, b); err != nil {
+			return err
+		}
+		if err := verifyAggregatorKeys(m.aggregatorKeys, &b.SignedHeader); err != nil {
+			return fmt.Errorf("failed to verify commit: %w", err)
+		}
+		if err := verifyCommitThreshold(m.conf.CommitThreshold, &b.SignedHeader); err != nil {
+			return fmt.Errorf("failed to verify commit: %w", err)
+		}
+		if err := m.verifyBlockEvidence(b); err != nil {
+			return fmt.Errorf("failed to verify evidence: %w", err)
+		}
 		newState, responses, err := m.executor.ApplyBlock(ctx, m.lastState, b)
 		if err != nil {
+			m.handleFraudProof(err)
 			return fmt.Errorf("failed to ApplyBlock: %w", err)
 		}
 		err = m.store.SaveBlock(b, commit)
 		if err != nil {
 			return fmt.Errorf("failed to save block: %w", err)
 		}
-		_, _, err = m.executor.Commit(ctx, newState, b, responses)
+		appHash, _, err := m.executor.Commit(ctx, newState, b, responses)
 		if err != nil {
 			return fmt.Errorf("failed to Commit: %w", err)
 		}
+		newState.AppHash = appHash
 
 		err = m.store.SaveBlockResponses(uint64(bHeight), responses)
 		if err != nil {
 			return fmt.Errorf("failed to save block responses: %w", err)
 		}
+		if err := m.store.SaveAppHash(bHeight, appHash); err != nil {
+			return fmt.Errorf("failed to save app hash: %w", err)
+		}
 
 		// SaveValidators commits the DB tx
 		err = m.saveValidatorsToStore(bHeight)
 		if err != nil {
 			return err
 		}
+		err = m.saveConsensusParamsToStore(bHeight)
+		if err != nil {
+			return err
+		}
 
 		m.store.SetHeight(bHeight)
+		m.trackSoftConfirmed(bHeight, b.Hash().String())
+		if loc, ok := m.blockCache.getDALocation(b.Hash().String()); ok {
+			if err := m.store.SaveDALocation(bHeight, loc); err != nil {
+				m.logger.Error("failed to save DA location of block", "height", bHeight, "daHeight", loc.DAHeight, "error", err)
+			} else {
+				m.setDAIncludedHeight(bHeight)
+			}
+		}
 
 		if daHeight > newState.DAHeight {
 			newState.DAHeight = daHeight
@@ -412,6 +871,8 @@ func (m *Manager) trySyncNextBlock(ctx context.Context, daHeight uint64) error {
 		if err != nil {
 			m.logger.Error("failed to save updated state", "error", err)
 		}
+		m.commitEvidence(b)
+		m.trackInclusionLists(b)
 		m.blockCache.deleteBlock(currentHeight + 1)
 	}
 
@@ -444,6 +905,28 @@ func (m *Manager) BlockStoreRetrieveLoop(ctx context.Context) {
 	}
 }
 
+// GossipedBlockLoop passes blocks received via P2P gossip to the sync loop as soon as they arrive,
+// so they can be applied without waiting for BlockStoreRetrieveLoop.
+func (m *Manager) GossipedBlockLoop(ctx context.Context, blocks <-chan *types.Block) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case block := <-blocks:
+			if block.Height() <= m.store.Height() {
+				continue
+			}
+			daHeight := atomic.LoadUint64(&m.daHeight)
+			m.logger.Debug("block received via p2p gossip", "blockHeight", block.Height(), "daHeight", daHeight)
+			select {
+			case m.blockInCh <- newBlockEvent{block, daHeight}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
 func (m *Manager) getBlocksFromBlockStore(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, error) {
 	if startHeight > endHeight {
 		return nil, fmt.Errorf("startHeight (%d) is greater than endHeight (%d)", startHeight, endHeight)
@@ -487,7 +970,7 @@ func (m *Manager) RetrieveLoop(ctx context.Context) {
 		case blockFoundCh <- struct{}{}:
 		default:
 		}
-		atomic.AddUint64(&m.daHeight, 1)
+		m.metrics.DAHeight.Set(float64(atomic.AddUint64(&m.daHeight, 1)))
 	}
 }
 
@@ -506,9 +989,17 @@ func (m *Manager) processNextDABlock(ctx context.Context) error {
 				return nil
 			}
 			m.logger.Debug("retrieved potential blocks", "n", len(blockResp.Blocks), "daHeight", daHeight)
+			index := uint64(0)
 			for _, block := range blockResp.Blocks {
+				// blocks of other rollups sharing the namespace (or DA client) are skipped
+				if block.SignedHeader.ChainID() != m.genesis.ChainID {
+					m.logger.Debug("skipping block of another chain", "chainID", block.SignedHeader.ChainID(), "daHeight", daHeight)
+					continue
+				}
 				blockHash := block.Hash().String()
 				m.blockCache.setDAIncluded(blockHash)
+				m.saveDALocation(block, store.DALocation{DAHeight: daHeight, Index: index})
+				index++
 				m.logger.Info("block marked as DA included", "blockHeight", block.Height(), "blockHash", blockHash)
 				if !m.blockCache.isSeen(blockHash) {
 					m.blockInCh <- newBlockEvent{block, daHeight}
@@ -540,7 +1031,7 @@ func (m *Manager) fetchBlock(ctx context.Context, daHeight uint64) (da.ResultRet
 }
 
 func (m *Manager) getRemainingSleep(start time.Time) time.Duration {
-	publishingDuration := time.Since(start)
+	publishingDuration := m.clock.Now().Sub(start)
 	sleepDuration := m.conf.BlockTime - publishingDuration
 	if sleepDuration < 0 {
 		sleepDuration = 0
@@ -548,18 +1039,74 @@ func (m *Manager) getRemainingSleep(start time.Time) time.Duration {
 	return sleepDuration
 }
 
-func (m *Manager) getCommit(header types.Header) (*types.Commit, error) {
+func (m *Manager) getCommit(ctx context.Context, header types.Header) (*types.Commit, error) {
 	headerBytes, err := header.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	sign, err := m.proposerKey.Sign(headerBytes)
+	var signature []byte
+	backoff := initialBackoff
+	for attempt := 1; ; attempt++ {
+		signature, err = m.sign(ctx, header.Height(), headerBytes)
+		if err == nil {
+			break
+		}
+		if attempt == maxSignAttempts {
+			return nil, fmt.Errorf("failed to sign block after %d attempts: %w", attempt, err)
+		}
+		m.logger.Error("failed to sign block", "height", header.Height(), "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return m.newCommit(signature)
+}
+
+// newCommit creates commit with signature of the proposer. Signatures in the commit are ordered like aggregators
+// in the set, so signature of the proposer is placed at its index, and signatures of other aggregators are empty.
+//
+// If aggregators use BLS keys, signatures are aggregated into a single signature.
+func (m *Manager) newCommit(sign []byte) (*types.Commit, error) {
+	validators := m.getLastStateValidators()
+	if len(m.aggregatorKeys) > 0 {
+		if validators == nil || validators.Size() != len(m.aggregatorKeys) {
+			return nil, fmt.Errorf("number of aggregator keys (%d) doesn't match aggregator set size", len(m.aggregatorKeys))
+		}
+	} else if validators == nil || validators.Size() <= 1 {
+		return &types.Commit{Signatures: []types.Signature{sign}}, nil
+	}
+	address, err := getAddress(m.signer.PubKey())
 	if err != nil {
 		return nil, err
 	}
-	return &types.Commit{
-		Signatures: []types.Signature{sign},
-	}, nil
+	idx, _ := validators.GetByAddress(address)
+	if idx < 0 {
+		return nil, fmt.Errorf("signer %X is not in the aggregator set", address)
+	}
+	signatures := make([]types.Signature, validators.Size())
+	signatures[idx] = sign
+	if len(m.aggregatorKeys) > 0 {
+		return types.AggregateCommit(signatures)
+	}
+	return &types.Commit{Signatures: signatures}, nil
+}
+
+// sign makes a single attempt to sign the message, limited by SignerTimeout.
+// If aggregators use BLS keys, the message is signed with BLS key of the signer.
+func (m *Manager) sign(ctx context.Context, height uint64, msg []byte) ([]byte, error) {
+	sign := m.signer.Sign
+	if len(m.aggregatorKeys) > 0 {
+		sign = m.signer.(signer.AggregateSigner).SignBLS
+	}
+	if m.conf.SignerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.conf.SignerTimeout)
+		defer cancel()
+	}
+	return sign(ctx, height, msg)
 }
 
 // IsProposer returns whether or not the manager is a proposer
@@ -571,7 +1118,7 @@ func (m *Manager) IsProposer() (bool, error) {
 		return true, nil
 	}
 
-	signerPubBytes, err := m.proposerKey.GetPublic().Raw()
+	signerPubBytes, err := m.signer.PubKey().Raw()
 	if err != nil {
 		return false, err
 	}
@@ -579,13 +1126,21 @@ func (m *Manager) IsProposer() (bool, error) {
 	return bytes.Equal(m.lastState.Validators.Proposer.PubKey.Bytes(), signerPubBytes), nil
 }
 
-func (m *Manager) publishBlock(ctx context.Context) error {
+func (m *Manager) publishBlock(ctx context.Context) (err error) {
+	m.blockMtx.Lock()
+	defer m.blockMtx.Unlock()
+	if m.halted.Load() || m.pendingBlocksFull() {
+		return nil
+	}
+
 	var lastCommit *types.Commit
 	var lastHeaderHash types.Hash
-	var err error
 	height := m.store.Height()
 	newHeight := height + 1
 
+	ctx, span := tracing.Start(ctx, "Manager.publishBlock", attribute.Int64("height", int64(newHeight)))
+	defer func() { tracing.End(span, err) }()
+
 	isProposer, err := m.IsProposer()
 	if err != nil {
 		return fmt.Errorf("error while checking for proposer: %w", err)
@@ -620,58 +1175,79 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 		block = pendingBlock
 	} else {
 		m.logger.Info("Creating and publishing block", "height", newHeight)
-		block = m.createBlock(newHeight, lastCommit, lastHeaderHash)
+		block, err = m.createBlock(ctx, newHeight, lastCommit, lastHeaderHash)
+		if err != nil {
+			return err
+		}
 		m.logger.Debug("block info", "num_tx", len(block.Data.Txs))
 
-		block.SignedHeader.DataHash, err = block.Data.Hash()
+		block.SignedHeader.DataHash, err = block.DataHash(m.dataHasher)
 		if err != nil {
-			return nil
+			return err
 		}
 		block.SignedHeader.Header.NextAggregatorsHash = m.getNextAggregatorsHash()
-		commit, err = m.getCommit(block.SignedHeader.Header)
-		if err != nil {
-			return err
+		if len(m.aggregatorKeys) > 0 {
+			block.SignedHeader.Header.AggregatorKeysHash = types.AggregatorKeysHash(m.aggregatorKeys)
 		}
-
-		// set the commit to current block's signed header
-		block.SignedHeader.Commit = *commit
-
 		block.SignedHeader.Validators = m.getLastStateValidators()
+		block.SignedHeader.AggregatorKeys = m.aggregatorKeys
 
-		// SaveBlock commits the DB tx
-		err = m.store.SaveBlock(block, commit)
+		// the block is signed only once, when its header is final (after it's applied), as a remote signer
+		// refuses to sign a different header at the same height; the unsigned block is saved, so that it's
+		// proposed again after a crash
+		err = m.store.SaveBlock(block, &types.Commit{})
 		if err != nil {
 			return err
 		}
 	}
 
-	// Apply the block but DONT commit
+	// Apply the block but DONT commit, NextAggregatorsHash is updated by the executor
 	newState, responses, err := m.applyBlock(ctx, block)
 	if err != nil {
 		return err
 	}
 
+	if m.extender != nil {
+		block.SignedHeader.Header.Extensions, err = m.extender.Extensions(m.lastState, block)
+		if err != nil {
+			return fmt.Errorf("failed to get header extensions: %w", err)
+		}
+	}
+
+	var validityProof []byte
+	if m.prover != nil {
+		validityProof, err = m.prover.Prove(m.lastState, block)
+		if err != nil {
+			return fmt.Errorf("failed to generate validity proof: %w", err)
+		}
+		block.SignedHeader.Header.ValidityProofHash = types.ValidityProofHash(validityProof)
+	}
+
+	corruptedHeader := m.byzantine.corruptStateRoots(block)
+
 	// Before taking the hash, we need updated ISRs, hence after ApplyBlock
-	block.SignedHeader.Header.DataHash, err = block.Data.Hash()
+	block.SignedHeader.Header.DataHash, err = block.DataHash(m.dataHasher)
 	if err != nil {
 		return err
 	}
 
-	block.SignedHeader.Header.NextAggregatorsHash = newState.NextValidators.Hash()
-
-	commit, err = m.getCommit(block.SignedHeader.Header)
+	commit, err = m.getCommit(ctx, block.SignedHeader.Header)
 	if err != nil {
 		return err
 	}
+	commit.ValidityProof = validityProof
 
 	// set the commit to current block's signed header
 	block.SignedHeader.Commit = *commit
 
 	block.SignedHeader.Validators = m.getLastStateValidators()
+	block.SignedHeader.AggregatorKeys = m.aggregatorKeys
 
-	// Validate the created block before storing
-	if err := m.executor.Validate(m.lastState, block); err != nil {
-		return fmt.Errorf("failed to validate block: %w", err)
+	// Validate the created block before storing; block with corrupted header is invalid by design
+	if !corruptedHeader {
+		if err := m.executor.Validate(m.lastState, block); err != nil {
+			return fmt.Errorf("failed to validate block: %w", err)
+		}
 	}
 
 	blockHeight := block.Height()
@@ -689,24 +1265,34 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 
 	// Submit block to be published to the DA layer
 	m.pendingBlocks.addPendingBlock(block)
+	m.metrics.PendingBlocks.Add(1)
 
 	// Commit the new state and block which writes to disk on the proxy app
-	_, _, err = m.executor.Commit(ctx, newState, block, responses)
+	appHash, _, err := m.executor.Commit(ctx, newState, block, responses)
 	if err != nil {
 		return err
 	}
+	newState.AppHash = appHash
 
 	// SaveBlockResponses commits the DB tx
 	err = m.store.SaveBlockResponses(blockHeight, responses)
 	if err != nil {
 		return err
 	}
+	err = m.store.SaveAppHash(blockHeight, appHash)
+	if err != nil {
+		return err
+	}
 
 	// SaveValidators commits the DB tx
 	err = m.saveValidatorsToStore(blockHeight)
 	if err != nil {
 		return err
 	}
+	err = m.saveConsensusParamsToStore(blockHeight)
+	if err != nil {
+		return err
+	}
 
 	newState.DAHeight = atomic.LoadUint64(&m.daHeight)
 	// After this call m.lastState is the NEW state returned from ApplyBlock
@@ -715,6 +1301,12 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	m.commitEvidence(block)
+	m.trackInclusionLists(block)
+	m.preConfirmations.prune(blockHeight)
+	if m.sequencer != nil && len(block.Data.Txs) > 0 {
+		m.lastBatchHash = sequencing.TxsHash(block.Data.Txs)
+	}
 
 	// Check if the node has shutdown prior to publishing to channels
 	select {
@@ -729,20 +1321,61 @@ func (m *Manager) publishBlock(ctx context.Context) error {
 	// Publish block to channel so that block exchange service can broadcast
 	m.BlockCh <- block
 
+	if conflicting, err := m.equivocate(ctx, block); err != nil {
+		m.logger.Error("failed to create conflicting block", "height", blockHeight, "error", err)
+	} else if conflicting != nil {
+		m.BlockCh <- conflicting
+	}
+
 	m.logger.Debug("successfully proposed block", "proposer", hex.EncodeToString(block.SignedHeader.ProposerAddress), "height", blockHeight)
 
 	return nil
 }
 
-func (m *Manager) submitBlocksToDA(ctx context.Context) error {
+func (m *Manager) submitBlocksToDA(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "Manager.submitBlocksToDA")
+	defer func() { tracing.End(span, err) }()
+
+	if m.byzantine.withholdData() {
+		m.logger.Info("withholding blocks from DA layer", "blocks", len(m.pendingBlocks.getPendingBlocks()))
+		m.pendingBlocks.resetPendingBlocks()
+		m.metrics.PendingBlocks.Set(0)
+		return nil
+	}
+
+	// in degraded mode, a single attempt is made on every reconnection
+	attempts := maxSubmitAttempts
+	degraded := m.daDegraded.Load()
+	if degraded {
+		attempts = 1
+	}
+	var blocks []*types.Block
+	var lastErr string
 	submitted := false
 	backoff := initialBackoff
-	for attempt := 1; ctx.Err() == nil && !submitted && attempt <= maxSubmitAttempts; attempt++ {
-		res := m.dalc.SubmitBlocks(ctx, m.pendingBlocks.getPendingBlocks())
+	for attempt := 1; ctx.Err() == nil && !submitted && attempt <= attempts; attempt++ {
+		blocks = m.pendingBlocks.getPendingBlocks()
+		res := m.dalc.SubmitBlocks(ctx, blocks)
 		if res.Code == da.StatusSuccess {
 			m.logger.Info("successfully submitted Rollkit block to DA layer", "daHeight", res.DAHeight)
+			span.SetAttributes(
+				attribute.Int("blocks", len(blocks)),
+				attribute.Int64("da_height", int64(res.DAHeight)),
+				attribute.Int("attempts", attempt))
+			m.metrics.SubmittedBlocks.Add(float64(len(blocks)))
+			for i, block := range blocks {
+				m.saveDALocation(block, store.DALocation{DAHeight: res.DAHeight, Index: uint64(i)})
+			}
+			m.addPendingCommitments(blocks, res.DAHeight)
+			m.recordDACost(blocks, res)
 			submitted = true
 		} else {
+			lastErr = res.Message
+			m.metrics.FailedSubmissions.Add(1)
+			if degraded {
+				m.logger.Debug("DA layer submission failed", "error", res.Message)
+				continue
+			}
 			m.logger.Error("DA layer submission failed", "error", res.Message, "attempt", attempt)
 			time.Sleep(backoff)
 			backoff = m.exponentialBackoff(backoff)
@@ -750,9 +1383,144 @@ func (m *Manager) submitBlocksToDA(ctx context.Context) error {
 	}
 
 	if !submitted {
-		return fmt.Errorf("failed to submit block to DA layer after %d attempts", maxSubmitAttempts)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		m.enterDADegradedMode(lastErr)
+		return fmt.Errorf("%w: failed to submit block to DA layer after %d attempts: %s", ErrDAUnavailable, attempts, lastErr)
+	}
+	m.pendingBlocks.removeSubmitted(blocks)
+	m.metrics.PendingBlocks.Set(float64(m.pendingBlocks.numPendingBlocks()))
+	m.leaveDADegradedMode()
+	return nil
+}
+
+// recordDACost attributes the fee of the submission to submitted blocks, proportionally to their size, and
+// reports the cost of the submission in metrics and to the application.
+func (m *Manager) recordDACost(blocks []*types.Block, res da.ResultSubmitBlocks) {
+	if len(blocks) == 0 {
+		return
+	}
+	sizes := make([]uint64, len(blocks))
+	cost := state.DASubmissionCost{DAHeight: res.DAHeight, Fee: res.Fee, Heights: make([]uint64, len(blocks))}
+	for i, block := range blocks {
+		if blob, err := block.MarshalBinary(); err == nil {
+			sizes[i] = uint64(len(blob))
+		}
+		cost.BlobSize += sizes[i]
+		cost.Heights[i] = uint64(block.Height())
+	}
+
+	now := m.clock.Now()
+	var attributed uint64
+	for i, height := range cost.Heights {
+		var fee uint64
+		if i == len(blocks)-1 {
+			// rounding remainder is attributed to the last block, so shares add up to the fee
+			fee = res.Fee - attributed
+		} else if cost.BlobSize > 0 {
+			fee = res.Fee * sizes[i] / cost.BlobSize
+		}
+		attributed += fee
+		err := m.store.SaveDACost(height, store.DACost{DAHeight: res.DAHeight, Fee: fee, BlobSize: sizes[i], Time: now})
+		if err != nil {
+			m.logger.Error("failed to save DA cost of block", "height", height, "error", err)
+		}
+	}
+	m.metrics.DAFees.Add(float64(res.Fee))
+	m.metrics.DAFeePerBlock.Set(float64(res.Fee) / float64(len(blocks)))
+
+	if m.daCostReporter != nil {
+		if err := m.daCostReporter.ReportDACost(cost); err != nil {
+			m.logger.Error("failed to report DA cost to the application", "daHeight", res.DAHeight, "error", err)
+		}
+	}
+}
+
+// SettlementLoop is responsible for posting commitments of blocks to the settlement layer,
+// and tracking their finality and disputes.
+func (m *Manager) SettlementLoop(ctx context.Context) {
+	if m.settlement == nil {
+		return
+	}
+	timer := time.NewTicker(m.conf.DABlockTime)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if err := m.submitCommitments(ctx); err != nil {
+			m.logger.Error("error while submitting commitments to settlement layer", "error", err)
+		}
+		if err := m.checkSettlement(ctx); err != nil {
+			m.logger.Error("error while checking finality on settlement layer", "error", err)
+		}
+	}
+}
+
+// SettledHeight returns the height of the latest block finalized on the settlement layer.
+func (m *Manager) SettledHeight() uint64 {
+	return m.settledHeight.Load()
+}
+
+func (m *Manager) addPendingCommitments(blocks []*types.Block, daHeight uint64) {
+	if m.settlement == nil {
+		return
+	}
+	m.pendingCommitmentsMtx.Lock()
+	defer m.pendingCommitmentsMtx.Unlock()
+	for _, block := range blocks {
+		m.pendingCommitments = append(m.pendingCommitments, settlement.NewCommitment(block, daHeight))
+	}
+}
+
+// submitCommitments posts pending commitments to the settlement layer. Commitments are kept for the next
+// attempt if submission fails.
+func (m *Manager) submitCommitments(ctx context.Context) error {
+	m.pendingCommitmentsMtx.Lock()
+	commitments := m.pendingCommitments
+	m.pendingCommitmentsMtx.Unlock()
+	if len(commitments) == 0 {
+		return nil
+	}
+	if err := m.settlement.SubmitCommitments(ctx, commitments); err != nil {
+		return err
+	}
+	m.logger.Info("successfully submitted commitments to settlement layer", "from", commitments[0].Height, "to", commitments[len(commitments)-1].Height)
+
+	m.pendingCommitmentsMtx.Lock()
+	m.pendingCommitments = m.pendingCommitments[len(commitments):]
+	m.pendingCommitmentsMtx.Unlock()
+	return nil
+}
+
+// checkSettlement reads the finalized height and disputes of not finalized commitments from the settlement layer.
+func (m *Manager) checkSettlement(ctx context.Context) error {
+	finalized, err := m.settlement.FinalizedHeight(ctx)
+	if err != nil {
+		return err
+	}
+	if finalized > m.settledHeight.Load() {
+		m.settledHeight.Store(finalized)
+		m.metrics.SettledHeight.Set(float64(finalized))
+	}
+
+	disputes, err := m.settlement.Disputes(ctx, finalized+1)
+	if err != nil {
+		return err
+	}
+	if m.reportedDisputes == nil {
+		m.reportedDisputes = make(map[uint64]struct{})
+	}
+	for _, d := range disputes {
+		if _, ok := m.reportedDisputes[d.Height]; ok {
+			continue
+		}
+		m.reportedDisputes[d.Height] = struct{}{}
+		m.logger.Error("commitment disputed on settlement layer", "height", d.Height, "challenger", d.Challenger, "reason", d.Reason)
 	}
-	m.pendingBlocks.resetPendingBlocks()
 	return nil
 }
 
@@ -773,15 +1541,25 @@ func (m *Manager) updateState(s types.State) error {
 		return err
 	}
 	m.lastState = s
+	m.metrics.Height.Set(float64(s.LastBlockHeight))
 	return nil
 }
 
 func (m *Manager) saveValidatorsToStore(height uint64) error {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
+	if err := m.store.SaveNextValidators(height, m.lastState.NextValidators); err != nil {
+		return err
+	}
 	return m.store.SaveValidators(height, m.lastState.Validators)
 }
 
+func (m *Manager) saveConsensusParamsToStore(height uint64) error {
+	m.lastStateMtx.RLock()
+	defer m.lastStateMtx.RUnlock()
+	return m.store.SaveConsensusParams(height, m.lastState.ConsensusParams)
+}
+
 func (m *Manager) getLastStateValidators() *cmtypes.ValidatorSet {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
@@ -800,18 +1578,398 @@ func (m *Manager) getLastBlockTime() time.Time {
 	return m.lastState.LastBlockTime
 }
 
-func (m *Manager) createBlock(height uint64, lastCommit *types.Commit, lastHeaderHash types.Hash) *types.Block {
+func (m *Manager) createBlock(ctx context.Context, height uint64, lastCommit *types.Commit, lastHeaderHash types.Hash) (*types.Block, error) {
+	ctx, span := tracing.Start(ctx, "BlockExecutor.CreateBlock", attribute.Int64("height", int64(height)))
+	defer span.End()
+	var batch *sequencing.Batch
+	var err error
+	if m.sequencer != nil {
+		batch, err = m.sequencer.GetNextBatch(ctx, []byte(m.genesis.ChainID), m.lastBatchHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next batch from sequencer: %w", err)
+		}
+	}
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
-	return m.executor.CreateBlock(height, lastCommit, lastHeaderHash, m.lastState)
+	var block *types.Block
+	if batch != nil {
+		block = m.executor.CreateBlockWithTxs(height, lastCommit, lastHeaderHash, m.lastState, batch.Transactions)
+	} else {
+		block = m.executor.CreateBlock(height, lastCommit, lastHeaderHash, m.lastState)
+		// transactions of inclusion lists follow pre-confirmed transactions, so they don't change promised positions
+		priority := m.withInclusionLists(m.preConfirmations.seal(height), m.lastState.ConsensusParams.Block.MaxBytes)
+		if len(priority) > 0 {
+			block.Data.Txs = withPreConfirmed(priority, block.Data.Txs)
+		}
+	}
+	if params := m.lastState.ConsensusParams.Evidence; params != nil {
+		block.Data.Evidence.Evidence = m.evidencePool.pendingEvidence(params.MaxBytes)
+	}
+	m.byzantine.censorTxs(block)
+	// invalid transactions are dropped before the header is built
+	block.Data.Txs, err = m.executor.PreValidateTxs(ctx, block.Data.Txs)
+	if err != nil {
+		return nil, err
+	}
+	block.Data.Txs = m.executor.PrepareEncryptedTxs(height, block.Data.Txs)
+	// priority transactions and batches of the sequencer are not limited by gas when reaped
+	block.Data.Txs = m.executor.LimitBlockGas(m.lastState, block.Data.Txs)
+	span.SetAttributes(attribute.Int("txs", len(block.Data.Txs)))
+	return block, nil
+}
+
+// PreConfirm promises inclusion of the transaction in the lowest block that is not being built yet, and returns
+// the pre-confirmation signed by the proposer. The transaction should be already accepted by the mempool.
+func (m *Manager) PreConfirm(ctx context.Context, tx types.Tx) (*types.PreConfirmation, error) {
+	if m.sequencer != nil {
+		return nil, errors.New("pre-confirmations are not supported with shared sequencer")
+	}
+	isProposer, err := m.IsProposer()
+	if err != nil {
+		return nil, fmt.Errorf("error while checking for proposer: %w", err)
+	}
+	if !isProposer {
+		return nil, errors.New("node is not the block proposer")
+	}
+	// remote signers refuse to sign different messages for the same height
+	localSigner, ok := m.signer.(*signer.LocalSigner)
+	if !ok {
+		return nil, errors.New("pre-confirmations require local signer")
+	}
+	proposerAddress, err := getAddress(localSigner.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	m.lastStateMtx.RLock()
+	maxBytes := m.lastState.ConsensusParams.Block.MaxBytes
+	m.lastStateMtx.RUnlock()
+	height, position, err := m.preConfirmations.add(tx, m.store.Height()+1, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	pc := &types.PreConfirmation{
+		ChainID:         m.genesis.ChainID,
+		Height:          height,
+		TxHash:          tx.Hash(),
+		Position:        position,
+		ProposerAddress: proposerAddress,
+	}
+	pc.Signature, err = localSigner.Sign(ctx, height, pc.SignBytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign pre-confirmation: %w", err)
+	}
+	return pc, nil
+}
+
+// verifySequencer checks that the synced block was proposed and signed by a sequencer from the aggregator set of
+// the state: initialized from genesis validators (or InitChain response) and updated by the application with
+// validator updates. Blocks without aggregator set are accepted only if the set is empty (based rollups), and then
+// their proposer is verified only if its public key is recoverable from the signature (secp256k1).
+func (m *Manager) verifySequencer(block *types.Block) error {
+	validators := block.SignedHeader.Validators
+	if len(m.lastState.Validators.Validators) > 0 && (validators == nil || len(validators.Validators) == 0) {
+		return fmt.Errorf("%w: block without aggregator set", ErrUnauthorizedSequencer)
+	}
+	if err := block.SignedHeader.VerifyProposer(); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnauthorizedSequencer, err)
+	}
+	if err := verifyRecoveredProposer(m.genesis, &block.SignedHeader); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnauthorizedSequencer, err)
+	}
+	return nil
+}
+
+// verifyBatch checks that transactions of the synced block were ordered by the sequencer, if it's set.
+func (m *Manager) verifyBatch(ctx context.Context, block *types.Block) error {
+	if m.sequencer == nil || len(block.Data.Txs) == 0 {
+		return nil
+	}
+	ok, err := m.sequencer.VerifyBatch(ctx, []byte(m.genesis.ChainID), sequencing.TxsHash(block.Data.Txs))
+	if err != nil {
+		return fmt.Errorf("failed to verify batch: %w", err)
+	}
+	if !ok {
+		return ErrBatchNotSequenced
+	}
+	return nil
+}
+
+// HaltProof returns the state fraud proof that caused the node to halt, or nil if the chain is not marked as faulty.
+func (m *Manager) HaltProof() *types.StateFraudProof {
+	return m.haltProof.Load()
+}
+
+// HaltBlockProcessing stops producing and syncing of blocks, until ResumeBlockProcessing is called.
+func (m *Manager) HaltBlockProcessing() {
+	m.halted.Store(true)
+	m.logger.Info("block processing halted")
+}
+
+// ResumeBlockProcessing resumes producing and syncing of blocks stopped by HaltBlockProcessing.
+func (m *Manager) ResumeBlockProcessing() {
+	m.halted.Store(false)
+	m.logger.Info("block processing resumed")
+}
+
+// IsBlockProcessingHalted returns true if producing and syncing of blocks is stopped by HaltBlockProcessing.
+func (m *Manager) IsBlockProcessingHalted() bool {
+	return m.halted.Load()
+}
+
+// NumPendingBlocks returns the number of blocks waiting for submission to the DA layer.
+func (m *Manager) NumPendingBlocks() int {
+	return m.pendingBlocks.numPendingBlocks()
+}
+
+// ResubmitBlocks adds blocks from the height range [from, to] to the blocks pending submission to the DA layer.
+// It returns the number of added blocks.
+func (m *Manager) ResubmitBlocks(from, to uint64) (int, error) {
+	if from == 0 || from > to || to > m.store.Height() {
+		return 0, fmt.Errorf("invalid height range [%d, %d], store height is %d", from, to, m.store.Height())
+	}
+	blocks := make([]*types.Block, 0, to-from+1)
+	for height := from; height <= to; height++ {
+		block, err := m.store.LoadBlock(height)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load block at height %d: %w", height, err)
+		}
+		blocks = append(blocks, block)
+	}
+	for _, block := range blocks {
+		m.pendingBlocks.addPendingBlock(block)
+	}
+	m.metrics.PendingBlocks.Add(float64(len(blocks)))
+	m.logger.Info("resubmitting blocks to DA layer", "from", from, "to", to)
+	return len(blocks), nil
+}
+
+// Rollback reverts the state by one block, to the state after applying the block preceding the latest one,
+// and removes the latest block from the store. Block processing has to be halted by HaltBlockProcessing.
+//
+// Application state is not reverted - the application has to be rolled back separately before block
+// processing is resumed. It returns the height of the latest block after rollback.
+func (m *Manager) Rollback() (uint64, error) {
+	m.blockMtx.Lock()
+	defer m.blockMtx.Unlock()
+	if !m.halted.Load() {
+		return 0, errors.New("block processing has to be halted before rollback")
+	}
+
+	height := m.store.Height()
+	if height <= uint64(m.genesis.InitialHeight) {
+		return 0, fmt.Errorf("cannot roll back block at height %d", height)
+	}
+	block, err := m.store.LoadBlock(height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load block at height %d: %w", height, err)
+	}
+	prevBlock, err := m.store.LoadBlock(height - 1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load block at height %d: %w", height-1, err)
+	}
+	// validators and consensus params are stored at the height of the block they were used for
+	validators, err := m.store.LoadValidators(height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load validators at height %d: %w", height, err)
+	}
+	nextValidators, err := m.store.LoadNextValidators(height)
+	if err != nil {
+		// blocks stored by older versions have no next validator set, it's the same as the current one
+		// unless the header says otherwise
+		if !bytes.Equal(validators.Hash(), block.SignedHeader.NextAggregatorsHash) {
+			return 0, fmt.Errorf("failed to load next validators at height %d: %w", height, err)
+		}
+		nextValidators = validators.Copy()
+	}
+	params, err := m.store.LoadConsensusParams(height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load consensus params at height %d: %w", height, err)
+	}
+
+	m.lastStateMtx.RLock()
+	s := m.lastState
+	m.lastStateMtx.RUnlock()
+	s.LastBlockHeight = prevBlock.Height()
+	s.LastBlockID = cmtypes.BlockID{Hash: cmbytes.HexBytes(prevBlock.Hash())}
+	s.LastBlockTime = prevBlock.Time()
+	// header of the block contains the hashes of the state it was created on
+	s.AppHash = block.SignedHeader.AppHash
+	s.LastResultsHash = block.SignedHeader.LastResultsHash
+	s.Validators = validators
+	s.NextValidators = nextValidators
+	if lastValidators, err := m.store.LoadValidators(height - 1); err == nil {
+		s.LastValidators = lastValidators
+	}
+	s.ConsensusParams = params
+
+	if err := m.store.Rollback(height - 1); err != nil {
+		return 0, fmt.Errorf("failed to roll back store: %w", err)
+	}
+	if err := m.updateState(s); err != nil {
+		return 0, fmt.Errorf("failed to save rolled back state: %w", err)
+	}
+	m.pendingBlocks.removeBlocksAbove(height - 1)
+	m.metrics.PendingBlocks.Set(float64(m.pendingBlocks.numPendingBlocks()))
+	m.logger.Info("rolled back block", "height", height)
+	return height - 1, nil
+}
+
+// handleFraudProof persists state fraud proof generated while applying a block, if there is any, and halts the node.
+func (m *Manager) handleFraudProof(err error) {
+	var isrErr *state.ISRMismatchError
+	if !errors.As(err, &isrErr) || isrErr.FraudProof == nil {
+		return
+	}
+	proof := isrErr.FraudProof
+	m.logger.Error("invalid state transition detected", "height", proof.BlockHeight, "txIndex", proof.TxIndex)
+	m.halt(proof)
+}
+
+// VerifyFraudProof checks if state fraud proof proves an invalid state transition in a block known to the node.
+func (m *Manager) VerifyFraudProof(proof *types.StateFraudProof) error {
+	block, err := m.getDisputedBlock(proof.BlockHeight)
+	if err != nil {
+		return err
+	}
+	return m.executor.VerifyFraudProof(block, proof)
+}
+
+// processFraudProof verifies state fraud proof received from other node, and halts the node if it is valid.
+func (m *Manager) processFraudProof(proof *types.StateFraudProof) error {
+	if err := m.VerifyFraudProof(proof); err != nil {
+		return err
+	}
+	m.logger.Error("valid fraud proof received", "height", proof.BlockHeight, "txIndex", proof.TxIndex)
+	m.halt(proof)
+	return nil
+}
+
+// VerifyEvidence checks that evidence proves equivocation of aggregators of a block synced by the node, and that
+// the evidence is not expired. ErrEvidenceHeightUnknown is returned if the node hasn't synced the height yet.
+func (m *Manager) VerifyEvidence(ev *types.DuplicateHeaderEvidence) error {
+	return m.verifyEvidence(ev, m.store.Height())
+}
+
+// verifyEvidence verifies evidence at given height of the chain.
+func (m *Manager) verifyEvidence(ev *types.DuplicateHeaderEvidence, height uint64) error {
+	if err := ev.ValidateBasic(); err != nil {
+		return err
+	}
+	if maxAge := m.evidenceMaxAge(); maxAge > 0 && ev.Height()+maxAge < height {
+		return fmt.Errorf("%w: evidence for height %d expired at height %d", types.ErrInvalidEvidence, ev.Height(), ev.Height()+maxAge)
+	}
+	block, err := m.store.LoadBlock(ev.Height())
+	if err != nil {
+		return fmt.Errorf("%w: %d", ErrEvidenceHeightUnknown, ev.Height())
+	}
+	if ev.HeaderA.ChainID() != block.SignedHeader.ChainID() {
+		return fmt.Errorf("%w: chain ID mismatch", types.ErrInvalidEvidence)
+	}
+	if !bytes.Equal(ev.HeaderA.AggregatorsHash, block.SignedHeader.AggregatorsHash) {
+		return fmt.Errorf("%w: aggregator set of height %d mismatch", types.ErrInvalidEvidence, ev.Height())
+	}
+	return nil
+}
+
+// verifyBlockEvidence verifies evidence committed in the block.
+func (m *Manager) verifyBlockEvidence(block *types.Block) error {
+	for i := range block.Data.Evidence.Evidence {
+		ev := &block.Data.Evidence.Evidence[i]
+		if ev.Height() >= block.Height() {
+			return fmt.Errorf("%w: evidence for height %d in block %d", types.ErrInvalidEvidence, ev.Height(), block.Height())
+		}
+		if err := m.verifyEvidence(ev, block.Height()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detectDuplicateHeader returns evidence of equivocation, if the block conflicts with a block synced by the node.
+func (m *Manager) detectDuplicateHeader(block *types.Block) *types.DuplicateHeaderEvidence {
+	synced, err := m.store.LoadBlock(block.Height())
+	if err != nil || bytes.Equal(synced.Hash(), block.Hash()) {
+		return nil
+	}
+	ev, err := types.NewDuplicateHeaderEvidence(&synced.SignedHeader, &block.SignedHeader)
+	if err != nil {
+		m.logger.Debug("conflicting block is not evidence of equivocation", "height", block.Height(), "error", err)
+		return nil
+	}
+	if err := m.VerifyEvidence(ev); err != nil {
+		m.logger.Debug("invalid evidence of equivocation", "height", block.Height(), "error", err)
+		return nil
+	}
+	return ev
+}
+
+// addEvidence adds verified evidence to the pool, to be committed in the next block produced by the node.
+// Evidence detected by the node is passed for gossiping.
+func (m *Manager) addEvidence(ev *types.DuplicateHeaderEvidence, detected bool) {
+	if !m.evidencePool.add(ev) {
+		return
+	}
+	m.logger.Error("aggregator equivocation detected", "height", ev.Height(), "evidence", ev.Hash())
+	if detected {
+		select {
+		case m.EvidenceOutCh <- ev:
+		default:
+			m.logger.Error("failed to gossip evidence, channel full", "height", ev.Height())
+		}
+	}
+}
+
+// commitEvidence removes evidence committed in the block from the pool, and prunes expired evidence.
+func (m *Manager) commitEvidence(block *types.Block) {
+	m.evidencePool.markCommitted(block.Data.Evidence.Evidence)
+	if maxAge := m.evidenceMaxAge(); maxAge > 0 && block.Height() > maxAge {
+		m.evidencePool.prune(block.Height() - maxAge)
+	}
+}
+
+// evidenceMaxAge returns the number of blocks after which evidence expires, or 0 if evidence doesn't expire.
+func (m *Manager) evidenceMaxAge() uint64 {
+	m.lastStateMtx.RLock()
+	defer m.lastStateMtx.RUnlock()
+	params := m.lastState.ConsensusParams.Evidence
+	if params == nil || params.MaxAgeNumBlocks <= 0 {
+		return 0
+	}
+	return uint64(params.MaxAgeNumBlocks)
+}
+
+// getDisputedBlock returns block at given height either from store, or from sync cache.
+func (m *Manager) getDisputedBlock(height uint64) (*types.Block, error) {
+	if block, err := m.store.LoadBlock(height); err == nil {
+		return block, nil
+	}
+	block, ok := m.blockCache.getBlock(height)
+	if !ok {
+		return nil, fmt.Errorf("block at height %d is not available", height)
+	}
+	if err := block.ValidateBasic(m.dataHasher); err != nil {
+		return nil, fmt.Errorf("invalid block at height %d: %w", height, err)
+	}
+	return block, nil
+}
+
+// halt marks the chain as faulty. SyncLoop stops syncing after the proof is recorded.
+func (m *Manager) halt(proof *types.StateFraudProof) {
+	if err := m.store.SaveFraudProof(proof); err != nil {
+		m.logger.Error("failed to save fraud proof", "height", proof.BlockHeight, "error", err)
+	}
+	m.haltProof.CompareAndSwap(nil, proof)
+	m.logger.Error("chain is faulty, halting node", "height", proof.BlockHeight)
 }
 
 func (m *Manager) applyBlock(ctx context.Context, block *types.Block) (types.State, *cmstate.ABCIResponses, error) {
 	m.lastStateMtx.RLock()
 	defer m.lastStateMtx.RUnlock()
-	return m.executor.ApplyBlock(ctx, m.lastState, block)
+	return m.executor.ApplyProposedBlock(ctx, m.lastState, block)
 }
-func updateState(s *types.State, res *abci.ResponseInitChain) {
+
+// UpdateStateFromInitChain updates the genesis state with the response of the application to InitChain.
+func UpdateStateFromInitChain(s *types.State, res *abci.ResponseInitChain) {
 	// If the app did not return an app hash, we keep the one set from the genesis doc in
 	// the state. We don't set appHash since we don't want the genesis doc app hash
 	// recorded in the genesis block. We should probably just remove GenesisDoc.AppHash.
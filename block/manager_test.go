@@ -3,18 +3,29 @@ package block
 import (
 	"context"
 	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/cometbft/cometbft/crypto/ed25519"
 	"github.com/cometbft/cometbft/libs/log"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rollkit/rollkit/clock"
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/crypto/bls"
 	"github.com/rollkit/rollkit/da"
 	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/sequencing"
+	mocksettlement "github.com/rollkit/rollkit/settlement/mock"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/state"
 	"github.com/rollkit/rollkit/store"
 	test "github.com/rollkit/rollkit/test/log"
 	"github.com/rollkit/rollkit/types"
@@ -84,7 +95,7 @@ func TestInitialState(t *testing.T) {
 			defer func() {
 				require.NoError(t, dalc.Stop())
 			}()
-			agg, err := NewManager(key, conf, c.genesis, c.store, nil, nil, dalc, nil, logger, nil)
+			agg, err := NewManager(signer.NewLocalSigner(key), conf, c.genesis, types.DefaultMerkleHasher, c.store, nil, nil, nil, nil, nil, nil, dalc, nil, nil, nil, logger, nil)
 			assert.NoError(err)
 			assert.NotNil(agg)
 			agg.lastStateMtx.RLock()
@@ -94,6 +105,10 @@ func TestInitialState(t *testing.T) {
 			agg.lastStateMtx.RUnlock()
 		})
 	}
+
+	// zero value of hasher is rejected
+	_, err = NewManager(signer.NewLocalSigner(key), conf, genesis, types.MerkleHasher{}, emptyStore, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, test.NewFileLogger(t), nil)
+	assert.ErrorIs(t, err, types.ErrNoHashFunction)
 }
 
 func getMockDALC(logger log.Logger) da.DataAvailabilityLayerClient {
@@ -119,3 +134,353 @@ func TestIsDAIncluded(t *testing.T) {
 	m.blockCache.setDAIncluded(hash.String())
 	require.True(m.IsDAIncluded(hash))
 }
+
+func TestDAIncludedHeight(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	m := &Manager{
+		store:      store.New(context.Background(), kv),
+		blockCache: NewBlockCache(),
+		logger:     log.NewNopLogger(),
+	}
+	var blocks []*types.Block
+	for h := uint64(1); h <= 3; h++ {
+		block := types.GetRandomBlock(h, 1)
+		require.NoError(m.store.SaveBlock(block, &block.SignedHeader.Commit))
+		blocks = append(blocks, block)
+	}
+	m.store.SetHeight(3)
+	assert.Zero(m.DAIncludedHeight())
+
+	m.saveDALocation(blocks[1], store.DALocation{DAHeight: 5})
+	assert.EqualValues(2, m.DAIncludedHeight())
+	m.saveDALocation(blocks[0], store.DALocation{DAHeight: 5})
+	assert.EqualValues(2, m.DAIncludedHeight())
+	assert.EqualValues(2, m.lastDAIncludedHeight())
+
+	// blocks 2 and 3 rolled back
+	require.NoError(m.store.Rollback(1))
+	assert.EqualValues(1, m.DAIncludedHeight())
+}
+
+func TestHandleFraudProof(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	m := &Manager{
+		store:      store.New(ctx, kv),
+		blockCache: NewBlockCache(),
+		logger:     test.NewFileLogger(t),
+	}
+
+	// errors without fraud proof don't halt the node
+	m.handleFraudProof(errors.New("failed to apply block"))
+	assert.Nil(m.HaltProof())
+
+	// received proof for unknown block is rejected
+	proof := &types.StateFraudProof{BlockHeight: 1, Tx: []byte{1}, PreStateRoot: []byte{1}, CommittedPostStateRoot: []byte{2}}
+	assert.Error(m.processFraudProof(proof))
+	assert.Nil(m.HaltProof())
+
+	// locally generated proof halts the node and is persisted
+	m.handleFraudProof(fmt.Errorf("failed to apply block: %w", &state.ISRMismatchError{Index: 1, FraudProof: proof}))
+	assert.Equal(proof, m.HaltProof())
+	saved, err := m.store.LoadFraudProof(1)
+	require.NoError(err)
+	assert.Equal(proof, saved)
+}
+
+func TestRollback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	s := store.New(ctx, kv)
+	validators := types.GetRandomValidatorSet()
+	nextValidators := types.GetRandomValidatorSet()
+	blocks := make([]*types.Block, 0, 3)
+	for h := uint64(1); h <= 3; h++ {
+		block := types.GetRandomBlock(h, 1)
+		require.NoError(s.SaveBlock(block, &types.Commit{}))
+		require.NoError(s.SaveValidators(h, validators))
+		require.NoError(s.SaveNextValidators(h, nextValidators))
+		require.NoError(s.SaveConsensusParams(h, cmtypes.DefaultConsensusParams().ToProto()))
+		s.SetHeight(h)
+		blocks = append(blocks, block)
+	}
+	m := &Manager{
+		store:         s,
+		genesis:       &cmtypes.GenesisDoc{InitialHeight: 1},
+		lastState:     types.State{LastBlockHeight: 3, Validators: validators, NextValidators: validators, LastValidators: validators},
+		lastStateMtx:  new(sync.RWMutex),
+		pendingBlocks: NewPendingBlocks(),
+		logger:        test.NewFileLogger(t),
+		metrics:       NopMetrics(),
+	}
+	m.pendingBlocks.addPendingBlock(blocks[1])
+	m.pendingBlocks.addPendingBlock(blocks[2])
+
+	// block processing has to be halted
+	_, err := m.Rollback()
+	assert.Error(err)
+
+	m.HaltBlockProcessing()
+	height, err := m.Rollback()
+	require.NoError(err)
+	assert.Equal(uint64(2), height)
+	assert.Equal(uint64(2), s.Height())
+	assert.Equal(uint64(2), m.lastState.LastBlockHeight)
+	assert.Equal(blocks[2].SignedHeader.AppHash, m.lastState.AppHash)
+	assert.Equal(blocks[1].Time(), m.lastState.LastBlockTime)
+	assert.Equal(validators.Hash(), m.lastState.Validators.Hash())
+	assert.Equal(nextValidators.Hash(), m.lastState.NextValidators.Hash())
+	assert.Equal(1, m.NumPendingBlocks())
+	_, err = s.LoadBlock(3)
+	assert.Error(err)
+
+	n, err := m.ResubmitBlocks(1, 2)
+	require.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal(3, m.NumPendingBlocks())
+	_, err = m.ResubmitBlocks(2, 3)
+	assert.Error(err)
+
+	_, err = m.Rollback()
+	require.NoError(err)
+	// the first block can't be rolled back
+	_, err = m.Rollback()
+	assert.Error(err)
+}
+
+func TestGossipedBlockLoop(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	m := &Manager{
+		store:     store.New(ctx, kv),
+		blockInCh: make(chan newBlockEvent, 2),
+		daHeight:  7,
+		logger:    test.NewFileLogger(t),
+	}
+	m.store.SetHeight(1)
+
+	blocks := make(chan *types.Block, 2)
+	go m.GossipedBlockLoop(ctx, blocks)
+
+	// already applied blocks are skipped
+	blocks <- types.GetRandomBlock(1, 1)
+	blocks <- types.GetRandomBlock(2, 1)
+	select {
+	case event := <-m.blockInCh:
+		assert.Equal(uint64(2), event.block.Height())
+		assert.Equal(uint64(7), event.daHeight)
+	case <-time.After(time.Second):
+		t.Fatal("gossiped block not passed to the sync loop")
+	}
+}
+
+func TestSequencerBatches(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	m := &Manager{
+		store:   store.New(ctx, kv),
+		genesis: &cmtypes.GenesisDoc{ChainID: "test"},
+	}
+	block := types.GetRandomBlock(1, 2)
+	require.NoError(m.store.SaveBlock(block, &types.Commit{}))
+	m.store.SetHeight(1)
+
+	seq := sequencing.NewFIFOSequencer()
+	m.SetSequencer(seq)
+	assert.Equal(sequencing.TxsHash(block.Data.Txs), m.lastBatchHash)
+
+	// blocks without transactions don't need a batch
+	assert.NoError(m.verifyBatch(ctx, types.GetRandomBlock(2, 0)))
+	assert.ErrorIs(m.verifyBatch(ctx, types.GetRandomBlock(2, 1)), ErrBatchNotSequenced)
+
+	next := types.GetRandomBlock(2, 1)
+	require.NoError(seq.SubmitRollupTransaction(ctx, []byte("test"), next.Data.Txs[0]))
+	batch, err := seq.GetNextBatch(ctx, []byte("test"), m.lastBatchHash)
+	require.NoError(err)
+	assert.Equal(next.Data.Txs, batch.Transactions)
+	assert.NoError(m.verifyBatch(ctx, next))
+}
+
+func TestParseAggregatorKeys(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := bls.GenPrivKey()
+	require.NoError(err)
+	other, err := bls.GenPrivKey()
+	require.NoError(err)
+	entry := func(key bls.PrivKey, proof []byte) string {
+		return hex.EncodeToString(key.PubKey()) + ":" + hex.EncodeToString(proof)
+	}
+
+	keys, err := parseAggregatorKeys([]string{entry(key, key.ProvePossession()), entry(other, other.ProvePossession())})
+	require.NoError(err)
+	assert.Equal([]bls.PubKey{key.PubKey(), other.PubKey()}, keys)
+
+	// keys without valid proof of possession are rejected
+	_, err = parseAggregatorKeys([]string{hex.EncodeToString(key.PubKey())})
+	assert.ErrorIs(err, bls.ErrInvalidProof)
+	_, err = parseAggregatorKeys([]string{entry(key, other.ProvePossession())})
+	assert.ErrorIs(err, bls.ErrInvalidProof)
+	_, err = parseAggregatorKeys([]string{entry(key, key.Sign(key.PubKey()))})
+	assert.ErrorIs(err, bls.ErrInvalidProof)
+}
+
+func TestAggregatedCommit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	localSigner := signer.NewLocalSigner(key)
+	rawPubKey, err := key.GetPublic().Raw()
+	require.NoError(err)
+	other := ed25519.GenPrivKey()
+	valSet := cmtypes.NewValidatorSet([]*cmtypes.Validator{
+		cmtypes.NewValidator(ed25519.PubKey(rawPubKey), 1),
+		cmtypes.NewValidator(other.PubKey(), 1),
+	})
+
+	// aggregator keys are ordered like aggregators in the set
+	signerKey, err := localSigner.BLSPubKey()
+	require.NoError(err)
+	otherBLSKey, err := bls.PrivKeyFromSeed(other.Bytes())
+	require.NoError(err)
+	otherKey := otherBLSKey.PubKey()
+	aggregatorKeys := []bls.PubKey{signerKey, otherKey}
+	if idx, _ := valSet.GetByAddress(other.PubKey().Address()); idx == 0 {
+		aggregatorKeys = []bls.PubKey{otherKey, signerKey}
+	}
+
+	m := &Manager{
+		signer:         localSigner,
+		aggregatorKeys: aggregatorKeys,
+		lastState:      types.State{Validators: valSet},
+		lastStateMtx:   new(sync.RWMutex),
+	}
+	sh := &types.SignedHeader{Header: types.GetRandomHeader(), Validators: valSet, AggregatorKeys: aggregatorKeys}
+	sh.ProposerAddress = valSet.Proposer.Address
+	sh.AggregatorsHash = valSet.Hash()
+	sh.AggregatorKeysHash = types.AggregatorKeysHash(aggregatorKeys)
+	commit, err := m.getCommit(context.Background(), sh.Header)
+	require.NoError(err)
+	assert.Empty(commit.Signatures)
+	assert.NotEmpty(commit.AggregatedSignature)
+
+	sh.Commit = *commit
+	assert.NoError(sh.ValidateBasic())
+	// only the proposer signed the header
+	assert.ErrorIs(sh.VerifyCommit(types.DefaultCommitThreshold), types.ErrInsufficientVotingPower)
+
+	// aggregated signatures are verified only against the configured keys
+	assert.NoError(verifyAggregatorKeys(aggregatorKeys, sh))
+	assert.ErrorIs(verifyAggregatorKeys(nil, sh), ErrAggregatorKeysMismatch)
+	forged := *sh
+	forged.AggregatorKeys = []bls.PubKey{aggregatorKeys[1], aggregatorKeys[0]}
+	forged.AggregatorKeysHash = types.AggregatorKeysHash(forged.AggregatorKeys)
+	assert.ErrorIs(verifyAggregatorKeys(aggregatorKeys, &forged), ErrAggregatorKeysMismatch)
+	unaggregated := *sh
+	unaggregated.Commit = types.Commit{Signatures: []types.Signature{{1}}}
+	assert.ErrorIs(verifyAggregatorKeys(aggregatorKeys, &unaggregated), ErrAggregatorKeysMismatch)
+}
+
+func TestSettlement(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx := context.Background()
+	client := &mocksettlement.Client{}
+	require.NoError(client.Init([]byte("1h"), test.NewFileLogger(t)))
+	m := &Manager{
+		logger:  test.NewFileLogger(t),
+		metrics: NopMetrics(),
+	}
+	blocks := []*types.Block{types.GetRandomBlock(1, 1), types.GetRandomBlock(2, 1)}
+
+	// sovereign rollups don't keep commitments
+	m.addPendingCommitments(blocks, 5)
+	assert.Empty(m.pendingCommitments)
+
+	m.SetSettlement(client)
+	m.addPendingCommitments(blocks, 5)
+	require.NoError(m.submitCommitments(ctx))
+	assert.Empty(m.pendingCommitments)
+	commitment, err := client.Commitment(2)
+	require.NoError(err)
+	assert.Equal(blocks[1].Hash(), commitment.HeaderHash)
+	assert.Equal(uint64(5), commitment.DAHeight)
+
+	require.NoError(client.Dispute(2, "challenger", "invalid state transition"))
+	require.NoError(m.checkSettlement(ctx))
+	assert.Zero(m.SettledHeight())
+	assert.Contains(m.reportedDisputes, uint64(2))
+}
+
+// daCostRecorder is a DACostReporter recording reported costs.
+type daCostRecorder []state.DASubmissionCost
+
+func (r *daCostRecorder) ReportDACost(cost state.DASubmissionCost) error {
+	*r = append(*r, cost)
+	return nil
+}
+
+func TestRecordDACost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	m := &Manager{
+		store:   store.New(context.Background(), kv),
+		clock:   clock.Real,
+		metrics: NopMetrics(),
+		logger:  test.NewFileLogger(t),
+	}
+	reporter := &daCostRecorder{}
+	m.SetDACostReporter(reporter)
+
+	blocks := []*types.Block{types.GetRandomBlock(1, 1), types.GetRandomBlock(2, 10), types.GetRandomBlock(3, 0)}
+	m.recordDACost(blocks, da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: 7}, Fee: 1001})
+
+	var fees, sizes uint64
+	for i, block := range blocks {
+		cost, err := m.store.LoadDACost(uint64(block.Height()))
+		require.NoError(err)
+		assert.Equal(uint64(7), cost.DAHeight)
+		blob, err := block.MarshalBinary()
+		require.NoError(err)
+		assert.Equal(uint64(len(blob)), cost.BlobSize)
+		if i > 0 {
+			// larger block pays larger share of the fee
+			assert.Equal(sizes > cost.BlobSize, fees > cost.Fee)
+		}
+		fees, sizes = cost.Fee, cost.BlobSize
+	}
+	daily, err := m.store.LoadDailyDACost(time.Now())
+	require.NoError(err)
+	assert.Equal(uint64(1001), daily.Fee)
+	assert.Equal(uint64(3), daily.Blocks)
+
+	require.Len(*reporter, 1)
+	assert.Equal(uint64(1001), (*reporter)[0].Fee)
+	assert.Equal([]uint64{1, 2, 3}, (*reporter)[0].Heights)
+	assert.Equal(daily.BlobSize, (*reporter)[0].BlobSize)
+}
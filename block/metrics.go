@@ -0,0 +1,183 @@
+package block
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "block"
+)
+
+// Values of the "result" label of InclusionLists metric.
+const (
+	InclusionListSatisfied = "satisfied"
+	InclusionListViolated  = "violated"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Height of the latest block in the store.
+	Height metrics.Gauge
+
+	// Height of the next DA block to be retrieved.
+	DAHeight metrics.Gauge
+
+	// Number of blocks waiting for submission to the DA layer.
+	PendingBlocks metrics.Gauge
+
+	// Number of blocks successfully submitted to the DA layer.
+	SubmittedBlocks metrics.Counter
+
+	// Number of failed DA layer submission attempts.
+	FailedSubmissions metrics.Counter
+
+	// 1 if the DA layer is unavailable and the node is in degraded mode.
+	DADegraded metrics.Gauge
+
+	// Total fee paid for DA layer submissions, in the smallest unit of the DA layer token.
+	DAFees metrics.Counter
+
+	// Average fee paid per block in the latest DA layer submission.
+	DAFeePerBlock metrics.Gauge
+
+	// Height of the latest block finalized on the settlement layer.
+	SettledHeight metrics.Gauge
+
+	// Number of soft confirmed blocks that didn't appear on the DA layer within the withholding window.
+	WithheldBlocks metrics.Gauge
+
+	// Number of blocks flagged as withheld by the data withholding watchdog.
+	WithholdingAlerts metrics.Counter
+
+	// Number of inclusion lists satisfied or violated by the sequencer.
+	InclusionLists metrics.Counter
+
+	// Number of inclusion lists waiting for inclusion of their transactions.
+	PendingInclusionLists metrics.Gauge
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Height: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "height",
+			Help:      "Height of the latest block in the store.",
+		}, labels).With(labelsAndValues...),
+
+		DAHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "da_height",
+			Help:      "Height of the next DA block to be retrieved.",
+		}, labels).With(labelsAndValues...),
+
+		PendingBlocks: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pending_blocks",
+			Help:      "Number of blocks waiting for submission to the DA layer.",
+		}, labels).With(labelsAndValues...),
+
+		SubmittedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "submitted_blocks",
+			Help:      "Number of blocks successfully submitted to the DA layer.",
+		}, labels).With(labelsAndValues...),
+
+		FailedSubmissions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "failed_submissions",
+			Help:      "Number of failed DA layer submission attempts.",
+		}, labels).With(labelsAndValues...),
+
+		DADegraded: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "da_degraded",
+			Help:      "1 if the DA layer is unavailable and the node is in degraded mode.",
+		}, labels).With(labelsAndValues...),
+
+		DAFees: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "da_fees",
+			Help:      "Total fee paid for DA layer submissions, in the smallest unit of the DA layer token.",
+		}, labels).With(labelsAndValues...),
+
+		DAFeePerBlock: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "da_fee_per_block",
+			Help:      "Average fee paid per block in the latest DA layer submission.",
+		}, labels).With(labelsAndValues...),
+
+		SettledHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "settled_height",
+			Help:      "Height of the latest block finalized on the settlement layer.",
+		}, labels).With(labelsAndValues...),
+
+		WithheldBlocks: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "withheld_blocks",
+			Help:      "Number of soft confirmed blocks that didn't appear on the DA layer within the withholding window.",
+		}, labels).With(labelsAndValues...),
+
+		WithholdingAlerts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "withholding_alerts",
+			Help:      "Number of blocks flagged as withheld by the data withholding watchdog.",
+		}, labels).With(labelsAndValues...),
+
+		InclusionLists: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "inclusion_lists",
+			Help:      "Number of inclusion lists satisfied or violated by the sequencer.",
+		}, append(labels, "result")).With(labelsAndValues...),
+
+		PendingInclusionLists: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pending_inclusion_lists",
+			Help:      "Number of inclusion lists waiting for inclusion of their transactions.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Height:                discard.NewGauge(),
+		DAHeight:              discard.NewGauge(),
+		PendingBlocks:         discard.NewGauge(),
+		SubmittedBlocks:       discard.NewCounter(),
+		FailedSubmissions:     discard.NewCounter(),
+		DADegraded:            discard.NewGauge(),
+		DAFees:                discard.NewCounter(),
+		DAFeePerBlock:         discard.NewGauge(),
+		SettledHeight:         discard.NewGauge(),
+		WithheldBlocks:        discard.NewGauge(),
+		WithholdingAlerts:     discard.NewCounter(),
+		InclusionLists:        discard.NewCounter(),
+		PendingInclusionLists: discard.NewGauge(),
+	}
+}
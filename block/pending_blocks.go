@@ -42,3 +42,38 @@ func (pb *PendingBlocks) resetPendingBlocks() {
 	defer pb.mtx.Unlock()
 	pb.pendingBlocks = make([]*types.Block, 0)
 }
+
+// removeSubmitted removes the submitted blocks from pending blocks. Blocks added (or resubmitted) during
+// the submission are kept.
+func (pb *PendingBlocks) removeSubmitted(submitted []*types.Block) {
+	pb.mtx.Lock()
+	defer pb.mtx.Unlock()
+	done := make(map[*types.Block]struct{}, len(submitted))
+	for _, block := range submitted {
+		done[block] = struct{}{}
+	}
+	blocks := make([]*types.Block, 0, len(pb.pendingBlocks))
+	for _, block := range pb.pendingBlocks {
+		if _, ok := done[block]; !ok {
+			blocks = append(blocks, block)
+		}
+	}
+	pb.pendingBlocks = blocks
+}
+
+func (pb *PendingBlocks) numPendingBlocks() int {
+	return len(pb.getPendingBlocks())
+}
+
+// removeBlocksAbove removes pending blocks with height greater than given height.
+func (pb *PendingBlocks) removeBlocksAbove(height uint64) {
+	pb.mtx.Lock()
+	defer pb.mtx.Unlock()
+	blocks := make([]*types.Block, 0, len(pb.pendingBlocks))
+	for _, block := range pb.pendingBlocks {
+		if block.Height() <= height {
+			blocks = append(blocks, block)
+		}
+	}
+	pb.pendingBlocks = blocks
+}
@@ -0,0 +1,102 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ErrPreConfirmationsFull is returned when pre-confirmed transactions would exceed the maximal size of the block.
+var ErrPreConfirmationsFull = errors.New("block is full of pre-confirmed transactions")
+
+// preConfirmationPool keeps transactions pre-confirmed by the aggregator, by height of the block that includes them,
+// until the block is committed.
+type preConfirmationPool struct {
+	mtx sync.Mutex
+	// height is the lowest height of a block that is not being built yet
+	height uint64
+	txs    map[uint64]types.Txs
+	size   map[uint64]int64
+}
+
+func newPreConfirmationPool() *preConfirmationPool {
+	return &preConfirmationPool{
+		txs:  make(map[uint64]types.Txs),
+		size: make(map[uint64]int64),
+	}
+}
+
+// add pre-confirms the transaction for the lowest height of a block that is not being built yet (at least minHeight),
+// and returns the height and position of the transaction in the block. Total size of pre-confirmed transactions of
+// a block is limited by maxBytes.
+func (p *preConfirmationPool) add(tx types.Tx, minHeight uint64, maxBytes int64) (uint64, uint64, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.height < minHeight {
+		p.height = minHeight
+	}
+	txs := p.txs[p.height]
+	for i := range txs {
+		if string(txs[i]) == string(tx) {
+			return p.height, uint64(i), nil
+		}
+	}
+	if p.size[p.height]+int64(len(tx)) > maxBytes {
+		return 0, 0, fmt.Errorf("%w: height %d", ErrPreConfirmationsFull, p.height)
+	}
+	p.txs[p.height] = append(txs, tx)
+	p.size[p.height] += int64(len(tx))
+	return p.height, uint64(len(txs)), nil
+}
+
+// seal returns transactions pre-confirmed for the block at given height, and stops pre-confirming transactions
+// for this block.
+func (p *preConfirmationPool) seal(height uint64) types.Txs {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.height <= height {
+		p.height = height + 1
+	}
+	return p.txs[height]
+}
+
+// prune removes transactions pre-confirmed for blocks up to given height.
+func (p *preConfirmationPool) prune(height uint64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for h := range p.txs {
+		if h <= height {
+			delete(p.txs, h)
+			delete(p.size, h)
+		}
+	}
+}
+
+// withPreConfirmed returns pre-confirmed transactions followed by other transactions of the block, so pre-confirmed
+// transactions are at promised positions. Other transactions are dropped if the total size would exceed the size of
+// transactions of the block.
+func withPreConfirmed(preConfirmed types.Txs, txs types.Txs) types.Txs {
+	var maxBytes, size int64
+	seen := make(map[string]struct{}, len(preConfirmed))
+	for _, tx := range preConfirmed {
+		seen[string(tx)] = struct{}{}
+		size += int64(len(tx))
+	}
+	for _, tx := range txs {
+		maxBytes += int64(len(tx))
+	}
+	result := append(make(types.Txs, 0, len(preConfirmed)+len(txs)), preConfirmed...)
+	for _, tx := range txs {
+		if _, ok := seen[string(tx)]; ok {
+			continue
+		}
+		if size+int64(len(tx)) > maxBytes {
+			break
+		}
+		result = append(result, tx)
+		size += int64(len(tx))
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestPreConfirmationPool(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	p := newPreConfirmationPool()
+	height, position, err := p.add(types.Tx("tx1"), 5, 10)
+	require.NoError(err)
+	assert.Equal(uint64(5), height)
+	assert.Equal(uint64(0), position)
+	height, position, err = p.add(types.Tx("tx2"), 5, 10)
+	require.NoError(err)
+	assert.Equal(uint64(5), height)
+	assert.Equal(uint64(1), position)
+
+	// the same transaction has the same position
+	_, position, err = p.add(types.Tx("tx1"), 5, 10)
+	require.NoError(err)
+	assert.Equal(uint64(0), position)
+
+	_, _, err = p.add(types.Tx("tx3-too-big"), 5, 10)
+	assert.ErrorIs(err, ErrPreConfirmationsFull)
+
+	// after the block is sealed, transactions are pre-confirmed for the next block
+	assert.Equal(types.Txs{types.Tx("tx1"), types.Tx("tx2")}, p.seal(5))
+	height, position, err = p.add(types.Tx("tx3"), 5, 10)
+	require.NoError(err)
+	assert.Equal(uint64(6), height)
+	assert.Equal(uint64(0), position)
+
+	p.prune(5)
+	assert.Empty(p.seal(5))
+	assert.Equal(types.Txs{types.Tx("tx3")}, p.seal(6))
+}
+
+func TestWithPreConfirmed(t *testing.T) {
+	assert := assert.New(t)
+
+	preConfirmed := types.Txs{types.Tx("c"), types.Tx("a")}
+	txs := types.Txs{types.Tx("a"), types.Tx("b"), types.Tx("d")}
+	assert.Equal(types.Txs{types.Tx("c"), types.Tx("a"), types.Tx("b")}, withPreConfirmed(preConfirmed, txs))
+	assert.Equal(preConfirmed, withPreConfirmed(preConfirmed, nil))
+}
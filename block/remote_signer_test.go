@@ -0,0 +1,156 @@
+package block
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	llcfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/rollkit/rollkit/config"
+	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// heightProver "proves" blocks with their heights, so the header of a produced block changes after it's applied.
+type heightProver struct{}
+
+func (heightProver) Prove(_ types.State, block *types.Block) ([]byte, error) {
+	return []byte(fmt.Sprint(block.Height())), nil
+}
+
+// TestPublishBlockRemoteSigner checks that blocks are produced with a remote signer, which refuses to sign
+// a different header at the same height, when the header is updated after the block is applied.
+func TestPublishBlockRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	validatorKey := ed25519.GenPrivKey()
+	key, err := crypto.UnmarshalEd25519PrivateKey(validatorKey.Bytes())
+	require.NoError(err)
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:         "test",
+		GenesisTime:     time.Now(),
+		InitialHeight:   1,
+		ConsensusParams: cmtypes.DefaultConsensusParams(),
+		Validators: []cmtypes.GenesisValidator{{
+			Address: validatorKey.PubKey().Address(),
+			PubKey:  validatorKey.PubKey(),
+			Power:   100,
+			Name:    "aggregator",
+		}},
+	}
+
+	dir := t.TempDir()
+	writeSignerCerts(t, dir)
+	serverTLS, err := signer.ServerTLSConfig(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(err)
+	clientTLS, err := signer.ClientTLSConfig(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	listener := bufconn.Listen(1024 * 1024)
+	srv, err := signer.NewServer(key, genesis.ChainID, serverTLS, filepath.Join(dir, "signer_state.json"))
+	require.NoError(err)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+	remote, err := signer.NewRemoteSigner(ctx, "bufnet", genesis.ChainID, clientTLS,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }))
+	require.NoError(err)
+	t.Cleanup(func() { _ = remote.Close() })
+
+	logger := test.NewFileLogger(t)
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(kvstore.NewApplication()), proxy.NopMetrics())
+	require.NoError(proxyApp.Start())
+	t.Cleanup(func() { _ = proxyApp.Stop() })
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := store.New(ctx, kv)
+	mempool := mempoolv1.NewTxMempool(logger, llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
+	conf := config.BlockManagerConfig{BlockTime: time.Second, DABlockTime: time.Second}
+	m, err := NewManager(remote, conf, genesis, types.DefaultMerkleHasher, s, mempool, proxyApp.Consensus(),
+		nil, nil, heightProver{}, nil, getMockDALC(logger), nil, nil, nil, logger, nil)
+	require.NoError(err)
+
+	for height := uint64(1); height <= 3; height++ {
+		require.NoError(m.publishBlock(ctx))
+		require.Equal(height, s.Height())
+		block, err := s.LoadBlock(height)
+		require.NoError(err)
+		commit, err := s.LoadCommit(height)
+		require.NoError(err)
+		block.SignedHeader.Commit = *commit
+		assert.NoError(block.SignedHeader.ValidateBasic())
+		assert.Equal(types.ValidityProofHash([]byte(fmt.Sprint(height))), block.SignedHeader.ValidityProofHash)
+	}
+}
+
+// writeSignerCerts writes a CA, and server and client certificates signed by it, for mutual TLS with the signer.
+func writeSignerCerts(t *testing.T, dir string) {
+	t.Helper()
+	require := require.New(t)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(err)
+	writePEM := func(name, blockType string, der []byte) {
+		require.NoError(os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	}
+	writePEM("ca.crt", "CERTIFICATE", caDER)
+
+	for i, name := range []string{"server", "client"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"bufnet"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(err)
+		writePEM(name+".crt", "CERTIFICATE", der)
+		writePEM(name+".key", "EC PRIVATE KEY", keyDER)
+	}
+}
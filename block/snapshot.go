@@ -0,0 +1,330 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	// maxSnapshotBlobSize is the maximum size of a single blob with a part of snapshot chunk. Application chunks
+	// are usually bigger than the maximum size of DA blob, so they are split into parts.
+	maxSnapshotBlobSize = 1024 * 1024
+	// snapshotManifestVersion identifies the format of the snapshot manifest blob.
+	snapshotManifestVersion = "rollkit-snapshot/1"
+)
+
+var (
+	// ErrSnapshotNotFound is returned when there is no valid snapshot manifest at the configured DA height.
+	ErrSnapshotNotFound = errors.New("snapshot manifest not found")
+	// ErrInvalidSnapshot is returned when the snapshot manifest can't be verified, or the application rejects
+	// the snapshot.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+)
+
+// snapshotPart is a location of a part of snapshot chunk on DA layer. Parts are identified by their hashes, as
+// anyone can post blobs in the snapshot namespace.
+type snapshotPart struct {
+	DAHeight uint64 `json:"da_height"`
+	Hash     []byte `json:"hash"`
+}
+
+// snapshotManifest describes application snapshot published to DA layer. It's published after all parts of the
+// snapshot, and it's the entry point for restoring the snapshot.
+type snapshotManifest struct {
+	Version  string        `json:"version"`
+	Snapshot abci.Snapshot `json:"snapshot"`
+	// LastHeader is the header of the snapshot height, and Header is the header of the next block, committing
+	// app hash of the snapshot.
+	LastHeader []byte `json:"last_header"`
+	Header     []byte `json:"header"`
+	// DAHeight is the DA height of the block following the snapshot; syncing from DA layer starts there.
+	DAHeight uint64 `json:"da_height"`
+	// Chunks contains parts of every application chunk, in order.
+	Chunks [][]snapshotPart `json:"chunks"`
+}
+
+// SetSnapshots enables publishing of application snapshots to DA layer, and restoring application state from them.
+// Client has to operate in the snapshot namespace. Restored snapshots are trusted if the header committing
+// the snapshot has the trusted hash, or (if trusted hash is empty) if it's signed by aggregators from genesis.
+func (m *Manager) SetSnapshots(client da.BlobClient, app proxy.AppConnSnapshot, trustedHash []byte) {
+	m.snapshotDA = client
+	m.snapshotApp = app
+	m.snapshotTrustedHash = trustedHash
+}
+
+// SnapshotPublishLoop periodically publishes the latest application snapshot to DA layer, if it's at least
+// SnapshotInterval blocks newer than the previously published one.
+func (m *Manager) SnapshotPublishLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.conf.DABlockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := m.publishSnapshot(ctx); err != nil {
+			m.logger.Error("failed to publish snapshot to DA layer", "error", err)
+		}
+	}
+}
+
+// publishSnapshot publishes the latest application snapshot, once the block following the snapshot is included
+// in DA layer.
+func (m *Manager) publishSnapshot(ctx context.Context) error {
+	resp, err := m.snapshotApp.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		return err
+	}
+	var snapshot *abci.Snapshot
+	for _, s := range resp.Snapshots {
+		if s.Height >= m.lastSnapshotHeight+m.conf.SnapshotInterval && (snapshot == nil || s.Height > snapshot.Height) {
+			snapshot = s
+		}
+	}
+	if snapshot == nil {
+		return nil
+	}
+
+	// app hash of the snapshot is committed in the next block
+	daHeight, err := m.GetDAHeight(snapshot.Height + 1)
+	if err != nil {
+		// not submitted yet
+		return nil
+	}
+	manifest := snapshotManifest{Version: snapshotManifestVersion, Snapshot: *snapshot, DAHeight: daHeight}
+	if manifest.LastHeader, err = m.marshalStoredHeader(snapshot.Height); err != nil {
+		return err
+	}
+	if manifest.Header, err = m.marshalStoredHeader(snapshot.Height + 1); err != nil {
+		return err
+	}
+
+	for chunk := uint32(0); chunk < snapshot.Chunks; chunk++ {
+		resp, err := m.snapshotApp.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  chunk,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load chunk %d of snapshot at height %d: %w", chunk, snapshot.Height, err)
+		}
+		var parts []snapshotPart
+		for data := resp.Chunk; len(parts) == 0 || len(data) > 0; {
+			n := len(data)
+			if n > maxSnapshotBlobSize {
+				n = maxSnapshotBlobSize
+			}
+			res := m.snapshotDA.SubmitBlobs(ctx, [][]byte{data[:n]})
+			if res.Code != da.StatusSuccess {
+				return fmt.Errorf("failed to submit chunk %d of snapshot at height %d: %s", chunk, snapshot.Height, res.Message)
+			}
+			hash := sha256.Sum256(data[:n])
+			parts = append(parts, snapshotPart{DAHeight: res.DAHeight, Hash: hash[:]})
+			data = data[n:]
+		}
+		manifest.Chunks = append(manifest.Chunks, parts)
+	}
+
+	bz, err := json.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+	res := m.snapshotDA.SubmitBlobs(ctx, [][]byte{bz})
+	if res.Code != da.StatusSuccess {
+		return fmt.Errorf("failed to submit manifest of snapshot at height %d: %s", snapshot.Height, res.Message)
+	}
+	m.lastSnapshotHeight = snapshot.Height
+	header, err := m.store.LoadBlock(snapshot.Height + 1)
+	if err != nil {
+		return err
+	}
+	m.logger.Info("published snapshot to DA layer", "height", snapshot.Height, "chunks", snapshot.Chunks,
+		"manifestDAHeight", res.DAHeight, "trustedHash", header.Hash())
+	return nil
+}
+
+func (m *Manager) marshalStoredHeader(height uint64) ([]byte, error) {
+	block, err := m.store.LoadBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block %d: %w", height, err)
+	}
+	return block.SignedHeader.MarshalBinary()
+}
+
+// RestoreSnapshot restores application state from the snapshot with manifest at SnapshotDAHeight, if the store
+// is empty. Syncing continues from the block following the snapshot. Restored node doesn't have blocks below
+// the snapshot height.
+func (m *Manager) RestoreSnapshot(ctx context.Context) error {
+	if m.conf.SnapshotDAHeight == 0 || m.lastState.LastBlockHeight+1 != uint64(m.genesis.InitialHeight) {
+		return nil
+	}
+	res := m.snapshotDA.RetrieveBlobs(ctx, m.conf.SnapshotDAHeight)
+	if res.Code != da.StatusSuccess {
+		return fmt.Errorf("failed to retrieve snapshot manifest from DA height %d: %s", m.conf.SnapshotDAHeight, res.Message)
+	}
+	var (
+		manifest           snapshotManifest
+		lastHeader, header *types.SignedHeader
+		err                error
+	)
+	for _, blob := range res.Blobs {
+		// anyone can post blobs in the namespace, so invalid manifests are skipped
+		manifest = snapshotManifest{}
+		if err = json.Unmarshal(blob, &manifest); err != nil || manifest.Version != snapshotManifestVersion {
+			continue
+		}
+		if lastHeader, header, err = m.verifySnapshotManifest(&manifest); err == nil {
+			break
+		}
+		m.logger.Error("skipping invalid snapshot manifest", "daHeight", m.conf.SnapshotDAHeight, "error", err)
+	}
+	if header == nil || err != nil {
+		return fmt.Errorf("%w at DA height %d", ErrSnapshotNotFound, m.conf.SnapshotDAHeight)
+	}
+
+	blobs := make(map[uint64][][]byte)
+	err = m.applySnapshotChunks(&manifest.Snapshot, header, len(manifest.Chunks), func(i int) ([]byte, error) {
+		var chunk []byte
+		for _, part := range manifest.Chunks[i] {
+			data, err := m.retrieveSnapshotPart(ctx, blobs, part)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve chunk %d: %w", i, err)
+			}
+			chunk = append(chunk, data...)
+		}
+		return chunk, nil
+	})
+	if err != nil {
+		return err
+	}
+	return m.restoreSnapshotState(&manifest, lastHeader, header)
+}
+
+// applySnapshotChunks offers the snapshot committed by header to the application, and applies n chunks returned
+// by chunk, in order.
+func (m *Manager) applySnapshotChunks(snapshot *abci.Snapshot, header *types.SignedHeader, n int, chunk func(i int) ([]byte, error)) error {
+	m.logger.Info("restoring snapshot", "height", snapshot.Height, "chunks", snapshot.Chunks)
+	offer, err := m.snapshotApp.OfferSnapshotSync(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: header.AppHash})
+	if err != nil {
+		return err
+	}
+	if offer.Result != abci.ResponseOfferSnapshot_ACCEPT {
+		return fmt.Errorf("%w: application responded %s to snapshot offer", ErrInvalidSnapshot, offer.Result)
+	}
+	for i := 0; i < n; i++ {
+		data, err := chunk(i)
+		if err != nil {
+			return err
+		}
+		resp, err := m.snapshotApp.ApplySnapshotChunkSync(abci.RequestApplySnapshotChunk{Index: uint32(i), Chunk: data})
+		if err != nil {
+			return err
+		}
+		if resp.Result != abci.ResponseApplySnapshotChunk_ACCEPT {
+			return fmt.Errorf("%w: application responded %s to chunk %d", ErrInvalidSnapshot, resp.Result, i)
+		}
+	}
+	return nil
+}
+
+// restoreSnapshotState updates the state of the node to the height of the restored snapshot. Syncing continues
+// from the block following the snapshot, at the DA height from the manifest.
+func (m *Manager) restoreSnapshotState(manifest *snapshotManifest, lastHeader, header *types.SignedHeader) error {
+	snapshot := manifest.Snapshot
+	s := m.lastState
+	s.Version.Consensus.App = header.Version.App
+	s.LastBlockHeight = snapshot.Height
+	s.LastBlockID = cmtypes.BlockID{Hash: cmbytes.HexBytes(header.LastHeaderHash[:])}
+	s.LastBlockTime = lastHeader.Time()
+	s.AppHash = header.AppHash
+	s.LastResultsHash = header.LastResultsHash
+	s.DAHeight = manifest.DAHeight
+	if header.Validators != nil {
+		s.Validators = header.Validators.Copy()
+		s.NextValidators = header.Validators.Copy()
+	}
+	if lastHeader.Validators != nil {
+		s.LastValidators = lastHeader.Validators.Copy()
+	}
+	if err := m.store.SaveAppHash(snapshot.Height, header.AppHash); err != nil {
+		return fmt.Errorf("failed to save app hash: %w", err)
+	}
+	if err := m.updateState(s); err != nil {
+		return err
+	}
+	m.store.SetHeight(snapshot.Height)
+	atomic.StoreUint64(&m.daHeight, s.DAHeight)
+	m.metrics.DAHeight.Set(float64(s.DAHeight))
+	m.logger.Info("restored snapshot", "height", snapshot.Height, "appHash", header.AppHash, "daHeight", s.DAHeight)
+	return nil
+}
+
+// verifySnapshotManifest verifies headers in the manifest, and returns the header of the snapshot height and
+// the header committing app hash of the snapshot.
+func (m *Manager) verifySnapshotManifest(manifest *snapshotManifest) (*types.SignedHeader, *types.SignedHeader, error) {
+	lastHeader, header := new(types.SignedHeader), new(types.SignedHeader)
+	if err := lastHeader.UnmarshalBinary(manifest.LastHeader); err != nil {
+		return nil, nil, err
+	}
+	if err := header.UnmarshalBinary(manifest.Header); err != nil {
+		return nil, nil, err
+	}
+	if lastHeader.Height() != manifest.Snapshot.Height || header.Height() != manifest.Snapshot.Height+1 {
+		return nil, nil, fmt.Errorf("%w: headers don't match snapshot height %d", ErrInvalidSnapshot, manifest.Snapshot.Height)
+	}
+	if err := lastHeader.ValidateBasic(); err != nil {
+		return nil, nil, err
+	}
+	if err := header.ValidateBasic(); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(header.LastHeaderHash[:], lastHeader.Hash()) {
+		return nil, nil, fmt.Errorf("%w: headers are not consecutive", ErrInvalidSnapshot)
+	}
+	if len(m.snapshotTrustedHash) > 0 {
+		if !bytes.Equal(header.Hash(), m.snapshotTrustedHash) {
+			return nil, nil, fmt.Errorf("%w: header hash %X is not trusted", ErrInvalidSnapshot, header.Hash())
+		}
+	} else if m.lastState.Validators == nil || len(m.lastState.Validators.Validators) == 0 ||
+		!bytes.Equal(header.AggregatorsHash[:], m.lastState.Validators.Hash()) {
+		return nil, nil, fmt.Errorf("%w: header is not signed by aggregators from genesis, trusted hash is required", ErrInvalidSnapshot)
+	}
+	// consensus parameters are not part of the snapshot, so they have to be the same as in genesis
+	if !bytes.Equal(header.ConsensusHash[:], types.ConsensusParamsHash(m.lastState.ConsensusParams)) {
+		return nil, nil, fmt.Errorf("%w: consensus parameters changed since genesis", ErrInvalidSnapshot)
+	}
+	return lastHeader, header, nil
+}
+
+// retrieveSnapshotPart returns the blob with hash of the part, from DA height of the part. Blobs retrieved from
+// DA layer are cached in blobs.
+func (m *Manager) retrieveSnapshotPart(ctx context.Context, blobs map[uint64][][]byte, part snapshotPart) ([]byte, error) {
+	if _, ok := blobs[part.DAHeight]; !ok {
+		res := m.snapshotDA.RetrieveBlobs(ctx, part.DAHeight)
+		if res.Code != da.StatusSuccess {
+			return nil, fmt.Errorf("failed to retrieve blobs from DA height %d: %s", part.DAHeight, res.Message)
+		}
+		blobs[part.DAHeight] = res.Blobs
+	}
+	for _, blob := range blobs[part.DAHeight] {
+		if hash := sha256.Sum256(blob); bytes.Equal(hash[:], part.Hash) {
+			return blob, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: part %X not found at DA height %d", ErrInvalidSnapshot, part.Hash, part.DAHeight)
+}
@@ -0,0 +1,143 @@
+package block
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+// blobStore is in-memory BlobClient, submitting every batch of blobs at the next DA height.
+type blobStore struct {
+	height uint64
+	blobs  map[uint64][][]byte
+}
+
+func (s *blobStore) SubmitBlobs(_ context.Context, blobs [][]byte) da.ResultSubmitBlocks {
+	s.height++
+	s.blobs[s.height] = blobs
+	return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: s.height}}
+}
+
+func (s *blobStore) RetrieveBlobs(_ context.Context, height uint64) da.ResultRetrieveBlobs {
+	return da.ResultRetrieveBlobs{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: height}, Blobs: s.blobs[height]}
+}
+
+func signHeader(t *testing.T, sh *types.SignedHeader, keys []crypto.PrivKey) {
+	headerBytes, err := sh.Header.MarshalBinary()
+	require.NoError(t, err)
+	for i, key := range keys {
+		sh.Commit.Signatures[i], err = signer.NewLocalSigner(key).Sign(context.Background(), sh.Height(), headerBytes)
+		require.NoError(t, err)
+	}
+}
+
+func TestSnapshotPublishAndRestore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	params := cmtypes.DefaultConsensusParams().ToProto()
+	g := types.NewGenerator(1)
+	lastHeader, keys, err := g.SignedHeader()
+	require.NoError(err)
+	lastHeader.BaseHeader.Height = 10
+	lastHeader.ConsensusHash = types.ConsensusParamsHash(params)
+	signHeader(t, lastHeader, keys)
+	header, err := g.NextSignedHeader(lastHeader, keys)
+	require.NoError(err)
+	header.ConsensusHash = types.ConsensusParamsHash(params)
+	signHeader(t, header, keys)
+
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := store.New(ctx, kv)
+	for _, sh := range []*types.SignedHeader{lastHeader, header} {
+		require.NoError(s.SaveBlock(&types.Block{SignedHeader: *sh}, &types.Commit{}))
+	}
+	s.SetHeight(11)
+	require.NoError(s.SaveDALocation(11, store.DALocation{DAHeight: 5}))
+
+	// the first chunk doesn't fit into a single blob
+	chunks := [][]byte{make([]byte, maxSnapshotBlobSize+100), []byte("chunk")}
+	_, err = rand.Read(chunks[0])
+	require.NoError(err)
+	snapshot := &abci.Snapshot{Height: 10, Format: 1, Chunks: 2, Hash: []byte("hash")}
+
+	app := &mocks.Application{}
+	app.On("ListSnapshots", mock.Anything).Return(abci.ResponseListSnapshots{Snapshots: []*abci.Snapshot{snapshot}})
+	for i, chunk := range chunks {
+		app.On("LoadSnapshotChunk", abci.RequestLoadSnapshotChunk{Height: 10, Format: 1, Chunk: uint32(i)}).
+			Return(abci.ResponseLoadSnapshotChunk{Chunk: chunk})
+		app.On("ApplySnapshotChunk", abci.RequestApplySnapshotChunk{Index: uint32(i), Chunk: chunk}).
+			Return(abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}).Once()
+	}
+	app.On("OfferSnapshot", abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: header.AppHash}).
+		Return(abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}).Once()
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app), proxy.NopMetrics())
+	require.NoError(proxyApp.Start())
+	t.Cleanup(func() { _ = proxyApp.Stop() })
+
+	blobs := &blobStore{blobs: make(map[uint64][][]byte)}
+	publisher := &Manager{
+		conf:   config.BlockManagerConfig{SnapshotInterval: 10},
+		store:  s,
+		logger: test.NewFileLogger(t),
+	}
+	publisher.SetSnapshots(blobs, proxyApp.Snapshot(), nil)
+	require.NoError(publisher.publishSnapshot(ctx))
+	// 2 parts of the first chunk, 1 part of the second chunk, and the manifest
+	assert.Equal(uint64(4), blobs.height)
+	assert.Equal(uint64(10), publisher.lastSnapshotHeight)
+	// snapshot is published once
+	require.NoError(publisher.publishSnapshot(ctx))
+	assert.Equal(uint64(4), blobs.height)
+
+	newRestorer := func(trustedHash []byte) *Manager {
+		kv, err := store.NewDefaultInMemoryKVStore()
+		require.NoError(err)
+		m := &Manager{
+			conf:         config.BlockManagerConfig{SnapshotDAHeight: 4},
+			genesis:      &cmtypes.GenesisDoc{InitialHeight: 1},
+			store:        store.New(ctx, kv),
+			lastState:    types.State{InitialHeight: 1, ConsensusParams: params},
+			lastStateMtx: new(sync.RWMutex),
+			metrics:      NopMetrics(),
+			logger:       test.NewFileLogger(t),
+		}
+		m.SetSnapshots(blobs, proxyApp.Snapshot(), trustedHash)
+		return m
+	}
+
+	// header committing the snapshot is not trusted
+	assert.ErrorIs(newRestorer([]byte{1, 2, 3}).RestoreSnapshot(ctx), ErrSnapshotNotFound)
+
+	m := newRestorer(header.Hash())
+	require.NoError(m.RestoreSnapshot(ctx))
+	assert.Equal(uint64(10), m.lastState.LastBlockHeight)
+	assert.Equal(header.AppHash, m.lastState.AppHash)
+	assert.Equal(lastHeader.Time(), m.lastState.LastBlockTime)
+	assert.Equal(uint64(5), m.lastState.DAHeight)
+	assert.Equal(uint64(5), m.daHeight)
+	assert.Equal(uint64(10), m.store.Height())
+	app.AssertExpectations(t)
+
+	// state is restored only into empty store
+	require.NoError(m.RestoreSnapshot(ctx))
+}
@@ -0,0 +1,154 @@
+package block
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// stateExportVersion identifies the format of the manifest of state export stream.
+const stateExportVersion = "rollkit-state-export/1"
+
+// ErrSnapshotsUnavailable is returned when state is exported or imported without access to application snapshots.
+var ErrSnapshotsUnavailable = errors.New("application snapshots are not available")
+
+// SetStateExport enables export and import of the state of the node, with application snapshots served by app.
+// Imported state is trusted if the header committing the snapshot has the trusted hash, or (if trusted hash is
+// empty) if it's signed by aggregators from genesis.
+func (m *Manager) SetStateExport(app proxy.AppConnSnapshot, trustedHash []byte) {
+	m.snapshotApp = app
+	m.snapshotTrustedHash = trustedHash
+}
+
+// ExportState writes state export stream (see types.StateExportWriter) with the application snapshot at height,
+// or the latest snapshot if height is zero, to w. The manifest of the stream, like the manifest of snapshots
+// published to DA layer, contains the header of the snapshot height, the header of the next block committing app
+// hash of the snapshot, and the DA height of the next block. It's followed by all chunks of the snapshot.
+func (m *Manager) ExportState(ctx context.Context, w io.Writer, height uint64) error {
+	if m.snapshotApp == nil {
+		return ErrSnapshotsUnavailable
+	}
+	resp, err := m.snapshotApp.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		return err
+	}
+	var snapshot *abci.Snapshot
+	for _, s := range resp.Snapshots {
+		// app hash of the snapshot is committed in the next block, so it has to be in the store
+		if s.Height >= m.store.Height() || (height > 0 && s.Height != height) {
+			continue
+		}
+		if snapshot == nil || s.Height > snapshot.Height {
+			snapshot = s
+		}
+	}
+	if snapshot == nil {
+		return fmt.Errorf("%w: no snapshot at height %d", ErrSnapshotNotFound, height)
+	}
+
+	manifest := snapshotManifest{Version: stateExportVersion, Snapshot: *snapshot}
+	if manifest.DAHeight, err = m.GetDAHeight(snapshot.Height + 1); err != nil {
+		return fmt.Errorf("block %d following the snapshot is not included in DA layer: %w", snapshot.Height+1, err)
+	}
+	if manifest.LastHeader, err = m.marshalStoredHeader(snapshot.Height); err != nil {
+		return err
+	}
+	if manifest.Header, err = m.marshalStoredHeader(snapshot.Height + 1); err != nil {
+		return err
+	}
+	bz, err := json.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+
+	sw := types.NewStateExportWriter(w)
+	if err := sw.WriteChunk(types.StateExportManifest, bz); err != nil {
+		return err
+	}
+	for chunk := uint32(0); chunk < snapshot.Chunks; chunk++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := m.snapshotApp.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  chunk,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load chunk %d of snapshot at height %d: %w", chunk, snapshot.Height, err)
+		}
+		if err := sw.WriteChunk(types.StateExportAppChunk, resp.Chunk); err != nil {
+			return err
+		}
+	}
+	if err := sw.Close(); err != nil {
+		return err
+	}
+	m.logger.Info("exported state", "height", snapshot.Height, "chunks", snapshot.Chunks)
+	return nil
+}
+
+// ImportState restores application and node state from state export stream read from r, if the store is empty.
+// Headers in the manifest are verified like headers of snapshots published to DA layer, chunks are verified by
+// the application, and the state of the node is updated only if the whole stream is valid. Syncing continues from
+// the block following the snapshot.
+func (m *Manager) ImportState(ctx context.Context, r io.Reader) error {
+	if m.lastState.LastBlockHeight+1 != uint64(m.genesis.InitialHeight) {
+		m.logger.Info("store is not empty, skipping state import", "height", m.lastState.LastBlockHeight)
+		return nil
+	}
+	if m.snapshotApp == nil {
+		return ErrSnapshotsUnavailable
+	}
+	sr := types.NewStateExportReader(r)
+	kind, bz, err := sr.ReadChunk()
+	if err != nil {
+		return err
+	}
+	if kind != types.StateExportManifest {
+		return fmt.Errorf("%w: stream doesn't start with manifest", types.ErrInvalidStateExport)
+	}
+	var manifest snapshotManifest
+	if err := json.Unmarshal(bz, &manifest); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal manifest: %w", types.ErrInvalidStateExport, err)
+	}
+	if manifest.Version != stateExportVersion {
+		return fmt.Errorf("%w: unsupported version %q", types.ErrInvalidStateExport, manifest.Version)
+	}
+	lastHeader, header, err := m.verifySnapshotManifest(&manifest)
+	if err != nil {
+		return err
+	}
+
+	err = m.applySnapshotChunks(&manifest.Snapshot, header, int(manifest.Snapshot.Chunks), func(i int) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		kind, chunk, err := sr.ReadChunk()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %d: %w", i, err)
+		}
+		if kind != types.StateExportAppChunk {
+			return nil, fmt.Errorf("%w: expected chunk %d, got chunk of kind %d", types.ErrInvalidStateExport, i, kind)
+		}
+		return chunk, nil
+	})
+	if err != nil {
+		return err
+	}
+	// end of the stream verifies that no chunk was dropped or reordered
+	if _, _, err := sr.ReadChunk(); err != io.EOF {
+		if err == nil {
+			err = fmt.Errorf("%w: unexpected chunks after snapshot", types.ErrInvalidStateExport)
+		}
+		return err
+	}
+	return m.restoreSnapshotState(&manifest, lastHeader, header)
+}
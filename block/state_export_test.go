@@ -0,0 +1,110 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestStateExportAndImport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	params := cmtypes.DefaultConsensusParams().ToProto()
+	g := types.NewGenerator(1)
+	lastHeader, keys, err := g.SignedHeader()
+	require.NoError(err)
+	lastHeader.BaseHeader.Height = 10
+	lastHeader.ConsensusHash = types.ConsensusParamsHash(params)
+	signHeader(t, lastHeader, keys)
+	header, err := g.NextSignedHeader(lastHeader, keys)
+	require.NoError(err)
+	header.ConsensusHash = types.ConsensusParamsHash(params)
+	signHeader(t, header, keys)
+
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := store.New(ctx, kv)
+	for _, sh := range []*types.SignedHeader{lastHeader, header} {
+		require.NoError(s.SaveBlock(&types.Block{SignedHeader: *sh}, &types.Commit{}))
+	}
+	s.SetHeight(11)
+
+	chunks := [][]byte{[]byte("chunk 0"), []byte("chunk 1")}
+	snapshot := &abci.Snapshot{Height: 10, Format: 1, Chunks: 2, Hash: []byte("hash")}
+	app := &mocks.Application{}
+	app.On("ListSnapshots", mock.Anything).Return(abci.ResponseListSnapshots{Snapshots: []*abci.Snapshot{
+		snapshot,
+		// next block is not in the store
+		{Height: 11, Format: 1, Chunks: 1},
+	}})
+	for i, chunk := range chunks {
+		app.On("LoadSnapshotChunk", abci.RequestLoadSnapshotChunk{Height: 10, Format: 1, Chunk: uint32(i)}).
+			Return(abci.ResponseLoadSnapshotChunk{Chunk: chunk})
+		app.On("ApplySnapshotChunk", abci.RequestApplySnapshotChunk{Index: uint32(i), Chunk: chunk}).
+			Return(abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}).Once()
+	}
+	app.On("OfferSnapshot", abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: header.AppHash}).
+		Return(abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}).Once()
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app), proxy.NopMetrics())
+	require.NoError(proxyApp.Start())
+	t.Cleanup(func() { _ = proxyApp.Stop() })
+
+	exporter := &Manager{store: s, logger: test.NewFileLogger(t)}
+	var buf bytes.Buffer
+	assert.ErrorIs(exporter.ExportState(ctx, &buf, 0), ErrSnapshotsUnavailable)
+	exporter.SetStateExport(proxyApp.Snapshot(), nil)
+	assert.ErrorIs(exporter.ExportState(ctx, &buf, 11), ErrSnapshotNotFound)
+	// DA height of the block following the snapshot is unknown
+	assert.ErrorIs(exporter.ExportState(ctx, &buf, 0), ErrDAHeightUnknown)
+	require.NoError(s.SaveDALocation(11, store.DALocation{DAHeight: 5}))
+	buf.Reset()
+	require.NoError(exporter.ExportState(ctx, &buf, 0))
+	stream := buf.Bytes()
+
+	newImporter := func(trustedHash []byte) *Manager {
+		kv, err := store.NewDefaultInMemoryKVStore()
+		require.NoError(err)
+		m := &Manager{
+			genesis:      &cmtypes.GenesisDoc{InitialHeight: 1},
+			store:        store.New(ctx, kv),
+			lastState:    types.State{InitialHeight: 1, ConsensusParams: params},
+			lastStateMtx: new(sync.RWMutex),
+			metrics:      NopMetrics(),
+			logger:       test.NewFileLogger(t),
+		}
+		m.SetStateExport(proxyApp.Snapshot(), trustedHash)
+		return m
+	}
+
+	// header committing the snapshot is not trusted
+	assert.ErrorIs(newImporter([]byte{1, 2, 3}).ImportState(ctx, bytes.NewReader(stream)), ErrInvalidSnapshot)
+	// truncated stream is rejected before the application is offered the snapshot
+	truncated := stream[:len(stream)-1]
+	assert.ErrorIs(newImporter(header.Hash()).ImportState(ctx, bytes.NewReader(truncated[:10])), types.ErrInvalidStateExport)
+
+	m := newImporter(header.Hash())
+	require.NoError(m.ImportState(ctx, bytes.NewReader(stream)))
+	assert.Equal(uint64(10), m.lastState.LastBlockHeight)
+	assert.Equal(header.AppHash, m.lastState.AppHash)
+	assert.Equal(uint64(5), m.lastState.DAHeight)
+	assert.Equal(uint64(10), m.store.Height())
+	app.AssertExpectations(t)
+
+	// state is imported only into empty store
+	require.NoError(m.ImportState(ctx, bytes.NewReader(truncated)))
+}
@@ -0,0 +1,84 @@
+package block
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// Methods below perform single steps of Manager loops. They're intended for deterministic drivers, like the
+// discrete-event simulation in testutil/sim, that schedule the steps in virtual time instead of running the loops.
+// Steps must not be mixed with running loops.
+
+// ProduceBlock produces a single block, like an iteration of AggregationLoop. It returns blocks that would be
+// gossiped in P2P network (more than one block only if the aggregator equivocates).
+func (m *Manager) ProduceBlock(ctx context.Context) ([]*types.Block, error) {
+	err := m.publishBlock(ctx)
+	// published headers are gossiped together with blocks
+	for len(m.HeaderCh) > 0 {
+		<-m.HeaderCh
+	}
+	var blocks []*types.Block
+	for len(m.BlockCh) > 0 {
+		blocks = append(blocks, <-m.BlockCh)
+	}
+	return blocks, err
+}
+
+// SubmitBlocks submits pending blocks to DA layer, like an iteration of BlockSubmissionLoop.
+func (m *Manager) SubmitBlocks(ctx context.Context) error {
+	if m.pendingBlocks.isEmpty() {
+		return nil
+	}
+	return m.submitBlocksToDA(ctx)
+}
+
+// RetrieveNextDABlock retrieves blocks from the next DA height and syncs them, like an iteration of RetrieveLoop
+// followed by SyncLoop processing the retrieved blocks. The DA block has to be already produced.
+func (m *Manager) RetrieveNextDABlock(ctx context.Context) error {
+	if err := m.processNextDABlock(ctx); err != nil {
+		return err
+	}
+	m.metrics.DAHeight.Set(float64(atomic.AddUint64(&m.daHeight, 1)))
+	for len(m.blockInCh) > 0 {
+		if err := m.syncBlockEvent(ctx, <-m.blockInCh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceiveBlock syncs the block received via P2P gossip, like GossipedBlockLoop followed by SyncLoop processing
+// the block.
+func (m *Manager) ReceiveBlock(ctx context.Context, block *types.Block) error {
+	if block.Height() <= m.store.Height() {
+		return nil
+	}
+	return m.syncBlockEvent(ctx, newBlockEvent{block, atomic.LoadUint64(&m.daHeight)})
+}
+
+// DAHeight returns the height of the next DA block to be retrieved.
+func (m *Manager) DAHeight() uint64 {
+	return atomic.LoadUint64(&m.daHeight)
+}
+
+// syncBlockEvent processes the block event, and syncs all consecutive cached blocks afterwards; SyncLoop syncs
+// them on subsequent events instead.
+func (m *Manager) syncBlockEvent(ctx context.Context, blockEvent newBlockEvent) error {
+	if err := m.processBlockEvent(ctx, blockEvent); err != nil {
+		return err
+	}
+	for {
+		height := m.store.Height()
+		if _, ok := m.blockCache.getBlock(height + 1); !ok {
+			return nil
+		}
+		if err := m.trySyncNextBlock(ctx, blockEvent.daHeight); err != nil {
+			return err
+		}
+		if m.store.Height() == height {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package block
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/celestiaorg/go-header"
+	goheaderp2p "github.com/celestiaorg/go-header/p2p"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/rollkit/rollkit/p2p"
+)
+
+// initRetryInterval defines how often sync services retry fetching the initial header (or block) from peers,
+// if the node was started without peers.
+const initRetryInterval = 1 * time.Second
+
+// fetchInitial fetches the header (or block) with trusted hash, or the genesis one, from peers.
+func fetchInitial[H header.Header[H]](ctx context.Context, ex header.Exchange[H], trustedHash string, initialHeight uint64, kind string) (H, error) {
+	var zero H
+	if trustedHash != "" {
+		trustedHashBytes, err := hex.DecodeString(trustedHash)
+		if err != nil {
+			return zero, fmt.Errorf("failed to parse the trusted hash for initializing the %s store: %w", kind, err)
+		}
+		initial, err := ex.Get(ctx, header.Hash(trustedHashBytes))
+		if err != nil {
+			return zero, fmt.Errorf("failed to fetch the trusted %s for initializing the %s store: %w", kind, kind, err)
+		}
+		return initial, nil
+	}
+	// Full/light nodes have to wait for aggregator to publish the genesis header/block
+	// proposing aggregator can init the store and start the syncer when the first header/block is published
+	initial, err := ex.GetByHeight(ctx, initialHeight)
+	if err != nil {
+		return zero, fmt.Errorf("failed to fetch the genesis %s: %w", kind, err)
+	}
+	return initial, nil
+}
+
+// initWhenConnected waits until the node is connected to peers able to serve the initial header (or block),
+// and then initializes the store and starts the syncer with init. Exchange used by the syncer is created
+// with peers connected at startup, so a temporary exchange is used to request the initial header.
+//
+// It lets nodes started without peers catch up with the network over P2P.
+func initWhenConnected[H header.Header[H]](
+	ctx context.Context,
+	p2pClient *p2p.Client,
+	isInitialized func() bool,
+	newExchange func(peers []peer.ID) (*goheaderp2p.Exchange[H], error),
+	fetch func(context.Context, header.Exchange[H]) (H, error),
+	init func(context.Context, H) error,
+	logger log.Logger,
+) {
+	ticker := time.NewTicker(initRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if isInitialized() {
+			return
+		}
+		peerIDs := p2pClient.PeerIDs()
+		if len(peerIDs) == 0 {
+			continue
+		}
+		initial, err := fetchWithExchange(ctx, peerIDs, newExchange, fetch)
+		if err != nil {
+			logger.Debug("failed to fetch initial header from peers", "error", err)
+			continue
+		}
+		if err := init(ctx, initial); err != nil {
+			logger.Error("failed to initialize store and start syncer", "error", err)
+		}
+		return
+	}
+}
+
+func fetchWithExchange[H header.Header[H]](
+	ctx context.Context,
+	peerIDs []peer.ID,
+	newExchange func(peers []peer.ID) (*goheaderp2p.Exchange[H], error),
+	fetch func(context.Context, header.Exchange[H]) (H, error),
+) (H, error) {
+	var zero H
+	ex, err := newExchange(peerIDs)
+	if err != nil {
+		return zero, err
+	}
+	if err := ex.Start(ctx); err != nil {
+		return zero, err
+	}
+	defer func() { _ = ex.Stop(ctx) }()
+	return fetch(ctx, ex)
+}
@@ -0,0 +1,121 @@
+package block
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/celestiaorg/go-header"
+	goheaderstore "github.com/celestiaorg/go-header/store"
+	ds "github.com/ipfs/go-datastore"
+)
+
+const (
+	// headerSyncPrefix and blockSyncPrefix are datastore prefixes of go-header stores of header and block sync services.
+	headerSyncPrefix = "headerSync"
+	blockSyncPrefix  = "blockSync"
+
+	// syncPruneBatchSize is the maximal number of headers deleted from go-header store in a single batch.
+	syncPruneBatchSize = 1000
+)
+
+// earliestSyncKey returns the key of the earliest height kept in go-header store with given prefix, saved when
+// the store is pruned. It's outside of the namespace of the store.
+func earliestSyncKey(prefix string) ds.Key {
+	return ds.NewKey(prefix + "Earliest")
+}
+
+// earliestSyncHeight returns the height of the earliest header in go-header store with given prefix, or 0 if
+// the store is empty. Headers are kept in a contiguous range of heights ending at the head.
+func earliestSyncHeight[H header.Header[H]](ctx context.Context, store *goheaderstore.Store[H], datastore ds.Batching, prefix string) (uint64, error) {
+	bz, err := datastore.Get(ctx, earliestSyncKey(prefix))
+	switch {
+	case err == nil && len(bz) == 8:
+		return binary.BigEndian.Uint64(bz), nil
+	case err == nil:
+		return 0, errors.New("invalid earliest height length")
+	case !errors.Is(err, ds.ErrNotFound):
+		return 0, err
+	}
+
+	// store was never pruned, so the earliest header is found with binary search (headers above the head of the
+	// store are never requested, as go-header store waits for them)
+	head := store.Height()
+	if head == 0 {
+		return 0, nil
+	}
+	lo, hi := uint64(1), head
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		_, err := store.GetByHeight(ctx, mid)
+		switch {
+		case err == nil:
+			hi = mid
+		case errors.Is(err, header.ErrNotFound):
+			lo = mid + 1
+		default:
+			return 0, err
+		}
+	}
+	return lo, nil
+}
+
+// pruneSyncStore deletes headers below retainHeight from go-header store with given prefix, keeping at least the
+// head of the store. go-header store doesn't support deletion, so its height index and headers are deleted directly
+// from the datastore; headers cached in memory by the store may still be returned until they're evicted.
+// It returns the number of pruned headers.
+func pruneSyncStore[H header.Header[H]](ctx context.Context, store *goheaderstore.Store[H], datastore ds.Batching, prefix string, retainHeight uint64) (uint64, error) {
+	earliest, err := earliestSyncHeight(ctx, store, datastore, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find earliest header: %w", err)
+	}
+	if head := store.Height(); retainHeight > head {
+		retainHeight = head
+	}
+	if earliest == 0 || retainHeight <= earliest {
+		return 0, nil
+	}
+
+	namespace := ds.NewKey(prefix)
+	var pruned uint64
+	batch, err := datastore.Batch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// earliest height is saved with every batch, so the range of kept headers stays contiguous after a failure
+	commit := func(earliest uint64) error {
+		bz := make([]byte, 8)
+		binary.BigEndian.PutUint64(bz, earliest)
+		if err := batch.Put(ctx, earliestSyncKey(prefix), bz); err != nil {
+			return err
+		}
+		return batch.Commit(ctx)
+	}
+	for height := earliest; height < retainHeight; height++ {
+		heightKey := namespace.ChildString(strconv.FormatUint(height, 10))
+		hash, err := datastore.Get(ctx, heightKey)
+		if err != nil && !errors.Is(err, ds.ErrNotFound) {
+			return pruned, err
+		}
+		if err == nil {
+			if err := errors.Join(
+				batch.Delete(ctx, heightKey),
+				batch.Delete(ctx, namespace.ChildString(header.Hash(hash).String())),
+			); err != nil {
+				return pruned, err
+			}
+			pruned++
+		}
+		if (height-earliest+1)%syncPruneBatchSize == 0 {
+			if err := commit(height + 1); err != nil {
+				return pruned, err
+			}
+			if batch, err = datastore.Batch(ctx); err != nil {
+				return pruned, err
+			}
+		}
+	}
+	return pruned, commit(retainHeight)
+}
@@ -0,0 +1,73 @@
+package block
+
+import (
+	"context"
+	"testing"
+
+	"github.com/celestiaorg/go-header"
+	"github.com/celestiaorg/go-header/headertest"
+	goheaderstore "github.com/celestiaorg/go-header/store"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneSyncStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	datastore := dssync.MutexWrap(ds.NewMapDatastore())
+	newStore := func() *goheaderstore.Store[*headertest.DummyHeader] {
+		s, err := goheaderstore.NewStore[*headertest.DummyHeader](datastore, goheaderstore.WithStorePrefix(headerSyncPrefix))
+		require.NoError(err)
+		return s
+	}
+
+	s := newStore()
+	earliest, err := earliestSyncHeight(ctx, s, datastore, headerSyncPrefix)
+	require.NoError(err)
+	assert.Zero(earliest)
+	pruned, err := pruneSyncStore(ctx, s, datastore, headerSyncPrefix, 5)
+	require.NoError(err)
+	assert.Zero(pruned)
+
+	headers := headertest.NewTestSuite(t).GenDummyHeaders(10)
+	require.NoError(s.Init(ctx, headers[0]))
+	require.NoError(s.Start(ctx))
+	require.NoError(s.Append(ctx, headers[1:]...))
+	require.NoError(s.Stop(ctx))
+	earliest, err = earliestSyncHeight(ctx, s, datastore, headerSyncPrefix)
+	require.NoError(err)
+	assert.Equal(uint64(1), earliest)
+
+	pruned, err = pruneSyncStore(ctx, s, datastore, headerSyncPrefix, 4)
+	require.NoError(err)
+	assert.Equal(uint64(3), pruned)
+	earliest, err = earliestSyncHeight(ctx, s, datastore, headerSyncPrefix)
+	require.NoError(err)
+	assert.Equal(uint64(4), earliest)
+
+	// reopen the store, to bypass its caches
+	s = newStore()
+	head, err := s.Head(ctx)
+	require.NoError(err)
+	assert.Equal(uint64(10), head.Height())
+	_, err = s.GetByHeight(ctx, 3)
+	assert.ErrorIs(err, header.ErrNotFound)
+	h, err := s.GetByHeight(ctx, 4)
+	require.NoError(err)
+	assert.Equal(headers[3].Hash(), h.Hash())
+
+	// head is always kept
+	pruned, err = pruneSyncStore(ctx, s, datastore, headerSyncPrefix, 20)
+	require.NoError(err)
+	assert.Equal(uint64(6), pruned)
+	earliest, err = earliestSyncHeight(ctx, s, datastore, headerSyncPrefix)
+	require.NoError(err)
+	assert.Equal(uint64(10), earliest)
+	pruned, err = pruneSyncStore(ctx, s, datastore, headerSyncPrefix, 20)
+	require.NoError(err)
+	assert.Zero(pruned)
+}
@@ -0,0 +1,96 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/proxy"
+)
+
+// ErrAppAheadOfState is returned when the application committed blocks that are not in the state of the node, e.g.
+// after a crash between committing a block in the application and saving the state. Blocks can't be produced or
+// synced until the application is rolled back to the height of the state.
+var ErrAppAheadOfState = errors.New("application is ahead of the state")
+
+// ErrAppHashMismatch is returned when application state diverges from the replayed chain.
+var ErrAppHashMismatch = errors.New("app hash mismatch")
+
+// WarmUp brings the application up to date with the state of the node, before blocks are produced or synced. Blocks
+// missing in the application (e.g. not persisted before a crash) are replayed from the store, and app hashes are
+// verified after every block, so the node never builds on a diverged application state.
+func (m *Manager) WarmUp(ctx context.Context, app proxy.AppConnQuery) error {
+	m.blockMtx.Lock()
+	defer m.blockMtx.Unlock()
+
+	info, err := app.InfoSync(proxy.RequestInfo)
+	if err != nil {
+		return fmt.Errorf("failed to get application info: %w", err)
+	}
+	if info.LastBlockHeight < 0 {
+		return fmt.Errorf("invalid application height: %d", info.LastBlockHeight)
+	}
+	appHeight := uint64(info.LastBlockHeight)
+	m.lastStateMtx.RLock()
+	s := m.lastState
+	m.lastStateMtx.RUnlock()
+	m.logger.Info("application info", "height", appHeight, "appHash", info.LastBlockAppHash, "version", info.Version,
+		"stateHeight", s.LastBlockHeight)
+
+	switch {
+	case appHeight > s.LastBlockHeight:
+		return fmt.Errorf("%w: application height %d, state height %d", ErrAppAheadOfState, appHeight, s.LastBlockHeight)
+	case appHeight < s.LastBlockHeight:
+		if err := m.replayToApp(ctx, appHeight, info.LastBlockAppHash, s.LastBlockHeight); err != nil {
+			return err
+		}
+	case appHeight > 0 && !bytes.Equal(info.LastBlockAppHash, s.AppHash):
+		return fmt.Errorf("%w at height %d: application %X, state %X", ErrAppHashMismatch, appHeight, info.LastBlockAppHash, s.AppHash)
+	}
+	m.appWarm.Store(true)
+	return nil
+}
+
+// IsAppWarm returns true if the application was brought up to date with the state of the node by WarmUp.
+func (m *Manager) IsAppWarm() bool {
+	return m.appWarm.Load()
+}
+
+// replayToApp replays blocks following the application height up to (and including) height to.
+func (m *Manager) replayToApp(ctx context.Context, appHeight uint64, appHash []byte, to uint64) error {
+	from := appHeight + 1
+	if appHeight == 0 {
+		// application lost its entire state, so it's initialized with genesis again
+		res, err := m.executor.InitChain(m.genesis)
+		if err != nil {
+			return fmt.Errorf("failed to initialize chain: %w", err)
+		}
+		appHash = res.AppHash
+		if len(appHash) == 0 {
+			appHash = m.genesis.AppHash
+		}
+		from = uint64(m.genesis.InitialHeight)
+	}
+	m.logger.Info("replaying blocks missing in the application", "from", from, "to", to)
+	for height := from; height <= to; height++ {
+		block, err := m.store.LoadBlock(height)
+		if err != nil {
+			return fmt.Errorf("failed to load block %d to replay: %w", height, err)
+		}
+		if !bytes.Equal(block.SignedHeader.AppHash, appHash) {
+			return fmt.Errorf("%w before block %d: committed %X, application %X", ErrAppHashMismatch, height, block.SignedHeader.AppHash, appHash)
+		}
+		appHash, err = m.executor.ReplayBlock(ctx, block)
+		if err != nil {
+			return fmt.Errorf("failed to replay block %d: %w", height, err)
+		}
+		m.logger.Debug("replayed block", "height", height, "appHash", appHash)
+	}
+	m.lastStateMtx.RLock()
+	defer m.lastStateMtx.RUnlock()
+	if !bytes.Equal(m.lastState.AppHash, appHash) {
+		return fmt.Errorf("%w after block %d: state %X, application %X", ErrAppHashMismatch, to, m.lastState.AppHash, appHash)
+	}
+	return nil
+}
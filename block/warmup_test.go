@@ -0,0 +1,91 @@
+package block
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestWarmUp(t *testing.T) {
+	ctx := context.Background()
+
+	// chain of 3 blocks; app hash after block h is {h}
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(t, err)
+	s := store.New(ctx, kv)
+	for h := uint64(1); h <= 3; h++ {
+		block := types.GetRandomBlock(h, 1)
+		block.SignedHeader.AppHash = []byte{byte(h - 1)}
+		require.NoError(t, s.SaveBlock(block, &types.Commit{}))
+		s.SetHeight(h)
+	}
+
+	newManager := func(info abci.ResponseInfo) (*Manager, *mocks.Application, proxy.AppConns) {
+		app := &mocks.Application{}
+		app.On("Info", mock.Anything).Return(info)
+		app.On("BeginBlock", mock.Anything).Return(abci.ResponseBeginBlock{})
+		app.On("DeliverTx", mock.Anything).Return(abci.ResponseDeliverTx{})
+		app.On("EndBlock", mock.Anything).Return(abci.ResponseEndBlock{})
+		app.On("Commit", mock.Anything).Return(abci.ResponseCommit{Data: []byte{2}}).Once()
+		app.On("Commit", mock.Anything).Return(abci.ResponseCommit{Data: []byte{3}}).Once()
+		proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app), proxy.NopMetrics())
+		require.NoError(t, proxyApp.Start())
+		t.Cleanup(func() { _ = proxyApp.Stop() })
+
+		logger := test.NewFileLogger(t)
+		executor, err := state.NewBlockExecutor(nil, types.NamespaceID{}, "test", types.DefaultMerkleHasher, nil, proxyApp.Consensus(), nil, nil, nil, 0, nil, logger)
+		require.NoError(t, err)
+		m := &Manager{
+			genesis:      &cmtypes.GenesisDoc{ChainID: "test", InitialHeight: 1},
+			store:        s,
+			executor:     executor,
+			lastState:    types.State{LastBlockHeight: 3, AppHash: []byte{3}},
+			lastStateMtx: new(sync.RWMutex),
+			logger:       logger,
+		}
+		return m, app, proxyApp
+	}
+
+	t.Run("replay missing blocks", func(t *testing.T) {
+		m, app, proxyApp := newManager(abci.ResponseInfo{LastBlockHeight: 1, LastBlockAppHash: []byte{1}})
+		require.NoError(t, m.WarmUp(ctx, proxyApp.Query()))
+		assert.True(t, m.IsAppWarm())
+		app.AssertNumberOfCalls(t, "BeginBlock", 2)
+		app.AssertNumberOfCalls(t, "Commit", 2)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		m, app, proxyApp := newManager(abci.ResponseInfo{LastBlockHeight: 3, LastBlockAppHash: []byte{3}})
+		require.NoError(t, m.WarmUp(ctx, proxyApp.Query()))
+		assert.True(t, m.IsAppWarm())
+		app.AssertNotCalled(t, "BeginBlock", mock.Anything)
+	})
+
+	t.Run("diverged app hash", func(t *testing.T) {
+		m, _, proxyApp := newManager(abci.ResponseInfo{LastBlockHeight: 3, LastBlockAppHash: []byte{4}})
+		assert.ErrorIs(t, m.WarmUp(ctx, proxyApp.Query()), ErrAppHashMismatch)
+		assert.False(t, m.IsAppWarm())
+
+		m, _, proxyApp = newManager(abci.ResponseInfo{LastBlockHeight: 1, LastBlockAppHash: []byte{4}})
+		assert.ErrorIs(t, m.WarmUp(ctx, proxyApp.Query()), ErrAppHashMismatch)
+	})
+
+	t.Run("app ahead of state", func(t *testing.T) {
+		m, _, proxyApp := newManager(abci.ResponseInfo{LastBlockHeight: 4, LastBlockAppHash: []byte{4}})
+		assert.ErrorIs(t, m.WarmUp(ctx, proxyApp.Query()), ErrAppAheadOfState)
+		assert.False(t, m.IsAppWarm())
+	})
+}
@@ -0,0 +1,111 @@
+package block
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// softConfirmedBlock is a block synced from P2P network before it appeared on DA layer.
+type softConfirmedBlock struct {
+	hash string
+	seen time.Time
+}
+
+// withholdingWatchdog tracks soft confirmed blocks, and heights of blocks which data didn't appear on DA layer
+// within the configured window. Such blocks are evidence of data withholding: the sequencer gossiped the block,
+// but didn't publish it on DA layer, so it can't be verified (or disputed) by nodes syncing from DA.
+type withholdingWatchdog struct {
+	mtx      sync.Mutex
+	pending  map[uint64]softConfirmedBlock
+	withheld map[uint64]string
+}
+
+func newWithholdingWatchdog() *withholdingWatchdog {
+	return &withholdingWatchdog{
+		pending:  make(map[uint64]softConfirmedBlock),
+		withheld: make(map[uint64]string),
+	}
+}
+
+// trackSoftConfirmed starts tracking of the synced block, if the data withholding watchdog is enabled and the block
+// was not seen on DA layer yet.
+func (m *Manager) trackSoftConfirmed(height uint64, hash string) {
+	if m.conf.WithholdingWindow <= 0 || m.blockCache.isDAIncluded(hash) {
+		return
+	}
+	m.withholding.mtx.Lock()
+	defer m.withholding.mtx.Unlock()
+	m.withholding.pending[height] = softConfirmedBlock{hash: hash, seen: m.clock.Now()}
+}
+
+// WithholdingWatchdogLoop periodically checks if soft confirmed blocks appeared on DA layer within
+// the configured window (BlockManagerConfig.WithholdingWindow), and flags blocks that didn't.
+func (m *Manager) WithholdingWatchdogLoop(ctx context.Context) {
+	interval := m.conf.WithholdingWindow / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		m.checkWithholding()
+	}
+}
+
+// checkWithholding stops tracking blocks that appeared on DA layer, and flags blocks that are missing on DA layer
+// for longer than the window.
+func (m *Manager) checkWithholding() {
+	now := m.clock.Now()
+	w := m.withholding
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	for height, block := range w.pending {
+		if m.blockCache.isDAIncluded(block.hash) {
+			delete(w.pending, height)
+			if _, ok := w.withheld[height]; ok {
+				delete(w.withheld, height)
+				m.logger.Info("withheld block appeared on DA layer", "height", height, "hash", block.hash)
+			}
+			continue
+		}
+		if _, ok := w.withheld[height]; ok || now.Sub(block.seen) <= m.conf.WithholdingWindow {
+			continue
+		}
+		w.withheld[height] = block.hash
+		m.metrics.WithholdingAlerts.Add(1)
+		m.logger.Error("block data withheld: soft confirmed block didn't appear on DA layer",
+			"height", height, "hash", block.hash, "seen", block.seen, "window", m.conf.WithholdingWindow)
+	}
+	m.metrics.WithheldBlocks.Set(float64(len(w.withheld)))
+}
+
+// WithheldHeights returns heights of soft confirmed blocks that didn't appear on DA layer within the window,
+// in ascending order.
+func (m *Manager) WithheldHeights() []uint64 {
+	m.withholding.mtx.Lock()
+	defer m.withholding.mtx.Unlock()
+	heights := make([]uint64, 0, len(m.withholding.withheld))
+	for height := range m.withholding.withheld {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights
+}
+
+// softConfirmationsHalted returns true if syncing of blocks not seen on DA layer is halted, because data
+// withholding was detected.
+func (m *Manager) softConfirmationsHalted() bool {
+	if !m.conf.WithholdingHalt {
+		return false
+	}
+	m.withholding.mtx.Lock()
+	defer m.withholding.mtx.Unlock()
+	return len(m.withholding.withheld) > 0
+}
@@ -0,0 +1,56 @@
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rollkit/rollkit/config"
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestWithholdingWatchdog(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &fakeClock{now: time.Unix(1700000000, 0)}
+	m := &Manager{
+		conf:        config.BlockManagerConfig{WithholdingWindow: time.Minute, WithholdingHalt: true},
+		blockCache:  NewBlockCache(),
+		clock:       c,
+		withholding: newWithholdingWatchdog(),
+		metrics:     NopMetrics(),
+		logger:      test.NewFileLogger(t),
+	}
+
+	// blocks already seen on DA layer are not tracked
+	m.blockCache.setDAIncluded("a")
+	m.trackSoftConfirmed(1, "a")
+	m.trackSoftConfirmed(2, "b")
+	m.trackSoftConfirmed(3, "c")
+
+	c.now = c.now.Add(time.Minute)
+	m.checkWithholding()
+	assert.Empty(m.WithheldHeights())
+	assert.False(m.softConfirmationsHalted())
+
+	m.blockCache.setDAIncluded("b")
+	c.now = c.now.Add(time.Second)
+	m.checkWithholding()
+	assert.Equal([]uint64{3}, m.WithheldHeights())
+	assert.True(m.softConfirmationsHalted())
+
+	// withheld block published on DA layer later
+	m.blockCache.setDAIncluded("c")
+	m.checkWithholding()
+	assert.Empty(m.WithheldHeights())
+	assert.False(m.softConfirmationsHalted())
+}
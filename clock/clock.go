@@ -0,0 +1,21 @@
+// Package clock abstracts the source of time used by the node, so it can be replaced with a virtual time,
+// e.g. in deterministic simulations.
+package clock
+
+import "time"
+
+// Clock is a source of time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// Real is the Clock returning the system time.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+// Now returns the current system time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
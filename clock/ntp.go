@@ -0,0 +1,130 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+)
+
+const (
+	// ntpPacketSize is the size of SNTP request and response.
+	ntpPacketSize = 48
+	// ntpEpochOffset is the number of seconds between NTP epoch (1900) and Unix epoch (1970).
+	ntpEpochOffset = 2208988800
+	// ntpTimeout limits duration of a single NTP query.
+	ntpTimeout = 5 * time.Second
+	// driftCheckInterval defines how often DriftMonitor queries the NTP server.
+	driftCheckInterval = 10 * time.Minute
+)
+
+// ErrInvalidNTPResponse is returned when NTP server responds with a malformed packet, or refuses to serve time.
+var ErrInvalidNTPResponse = errors.New("invalid NTP response")
+
+// QueryOffset queries the NTP server (host with optional port, 123 by default) with SNTP protocol, and returns
+// the offset of the clock from time of the server. Positive offset means that the clock is behind.
+func QueryOffset(ctx context.Context, server string, c Clock) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+	ctx, cancel := context.WithTimeout(ctx, ntpTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close() //nolint:errcheck
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, ntpPacketSize)
+	// leap indicator 0, version 4, mode 3 (client)
+	req[0] = 0x23
+	sent := c.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, ntpPacketSize)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+	received := c.Now()
+	if n < ntpPacketSize {
+		return 0, fmt.Errorf("%w: %d bytes", ErrInvalidNTPResponse, n)
+	}
+	// mode 4 (server); stratum 0 is a "kiss-of-death" packet
+	if mode, stratum := resp[0]&0x7, resp[1]; mode != 4 || stratum == 0 {
+		return 0, fmt.Errorf("%w: mode %d, stratum %d", ErrInvalidNTPResponse, mode, stratum)
+	}
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// ntpTime decodes NTP timestamp: seconds since NTP epoch, and fraction of a second.
+func ntpTime(b []byte) time.Time {
+	sec := binary.BigEndian.Uint32(b[0:4])
+	frac := binary.BigEndian.Uint32(b[4:8])
+	nsec := (uint64(frac) * uint64(time.Second)) >> 32
+	return time.Unix(int64(sec)-ntpEpochOffset, int64(nsec))
+}
+
+// DriftMonitor periodically compares the clock with time of an NTP server, and warns when they drift apart
+// by more than the threshold. Drifting clock of the aggregator produces blocks with wrong timestamps, and drifting
+// clock of a full node rejects valid headers as coming from the future.
+type DriftMonitor struct {
+	server    string
+	threshold time.Duration
+	clock     Clock
+	logger    log.Logger
+	offset    atomic.Int64
+}
+
+// NewDriftMonitor creates DriftMonitor of the clock, using the NTP server.
+func NewDriftMonitor(server string, threshold time.Duration, c Clock, logger log.Logger) *DriftMonitor {
+	return &DriftMonitor{server: server, threshold: threshold, clock: c, logger: logger}
+}
+
+// Run checks the drift of the clock until context is canceled.
+func (m *DriftMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(driftCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check queries the NTP server once, and logs a warning if the drift exceeds the threshold.
+func (m *DriftMonitor) Check(ctx context.Context) {
+	offset, err := QueryOffset(ctx, m.server, m.clock)
+	if err != nil {
+		m.logger.Debug("failed to query NTP server", "server", m.server, "error", err)
+		return
+	}
+	m.offset.Store(int64(offset))
+	if offset > m.threshold || -offset > m.threshold {
+		m.logger.Error("clock drift exceeds threshold, synchronize the system clock",
+			"server", m.server, "offset", offset, "threshold", m.threshold)
+		return
+	}
+	m.logger.Debug("clock drift checked", "server", m.server, "offset", offset)
+}
+
+// Offset returns the offset of the clock from time of the NTP server, measured by the last successful check.
+func (m *DriftMonitor) Offset() time.Duration {
+	return time.Duration(m.offset.Load())
+}
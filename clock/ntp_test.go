@@ -0,0 +1,56 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startNTPServer starts SNTP server responding with time shifted by the offset.
+func startNTPServer(t *testing.T, offset time.Duration, stratum byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buf := make([]byte, ntpPacketSize)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			resp := make([]byte, ntpPacketSize)
+			resp[0] = 0x24 // version 4, mode 4 (server)
+			resp[1] = stratum
+			now := time.Now().Add(offset)
+			putNTPTime(resp[32:40], now)
+			putNTPTime(resp[40:48], now)
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[0:4], uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:8], uint32((uint64(t.Nanosecond())<<32)/uint64(time.Second)))
+}
+
+func TestQueryOffset(t *testing.T) {
+	ctx := context.Background()
+
+	offset, err := QueryOffset(ctx, startNTPServer(t, 5*time.Second, 1), Real)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(5*time.Second), float64(offset), float64(100*time.Millisecond))
+
+	offset, err = QueryOffset(ctx, startNTPServer(t, -3*time.Second, 1), Real)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(-3*time.Second), float64(offset), float64(100*time.Millisecond))
+
+	_, err = QueryOffset(ctx, startNTPServer(t, 0, 0), Real)
+	assert.ErrorIs(t, err, ErrInvalidNTPResponse)
+}
@@ -2,27 +2,135 @@ package config
 
 import (
 	"encoding/hex"
+	"fmt"
 	"time"
 
 	cmcfg "github.com/cometbft/cometbft/config"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/rollkit/rollkit/types"
 )
 
 const (
-	flagAggregator     = "rollkit.aggregator"
-	flagDALayer        = "rollkit.da_layer"
-	flagDAConfig       = "rollkit.da_config"
-	flagBlockTime      = "rollkit.block_time"
-	flagDABlockTime    = "rollkit.da_block_time"
-	flagDAStartHeight  = "rollkit.da_start_height"
-	flagNamespaceID    = "rollkit.namespace_id"
-	flagLight          = "rollkit.light"
-	flagTrustedHash    = "rollkit.trusted_hash"
-	flagLazyAggregator = "rollkit.lazy_aggregator"
+	flagAggregator       = "rollkit.aggregator"
+	flagDALayer          = "rollkit.da_layer"
+	flagDAConfig         = "rollkit.da_config"
+	flagBlockTime        = "rollkit.block_time"
+	flagDABlockTime      = "rollkit.da_block_time"
+	flagDAStartHeight    = "rollkit.da_start_height"
+	flagNamespaceID      = "rollkit.namespace_id"
+	flagLight            = "rollkit.light"
+	flagTrustedHash      = "rollkit.trusted_hash"
+	flagLazyAggregator   = "rollkit.lazy_aggregator"
+	flagISRs             = "rollkit.intermediate_state_roots"
+	flagValidityProofs   = "rollkit.validity_proofs"
+	flagHeaderExtensions = "rollkit.header_extensions"
+	flagDACostFeedback   = "rollkit.da_cost_feedback"
+	flagABCITimeout      = "rollkit.abci_timeout"
+	flagTxPreValidation  = "rollkit.tx_prevalidation"
+	flagMempoolNonce     = "rollkit.mempool_nonce"
+	flagMempoolRBF       = "rollkit.mempool_replace_bump"
+	flagMempoolPerSender = "rollkit.mempool_max_txs_per_sender"
+	flagMempoolCacheTTL  = "rollkit.mempool_cache_ttl"
+	flagMempoolBatch     = "rollkit.mempool_checktx_batch"
+	flagMempoolAllow     = "rollkit.mempool_sender_allowlist"
+	flagMempoolDeny      = "rollkit.mempool_sender_denylist"
+	flagReadyMaxLag      = "rollkit.ready_max_lag"
+	flagAdminToken       = "rollkit.admin_token"
+	flagRemoteSigner     = "rollkit.remote_signer"
+	flagSignerTimeout    = "rollkit.signer_timeout"
+	flagSignerTLSCert    = "rollkit.remote_signer_tls_cert"
+	flagSignerTLSKey     = "rollkit.remote_signer_tls_key"
+	flagSignerTLSCA      = "rollkit.remote_signer_tls_ca"
+	flagSequencer        = "rollkit.sequencer_address"
+	flagFCFSOrdering     = "rollkit.fcfs_ordering"
+	flagSettlementLayer  = "rollkit.settlement_layer"
+	flagSettlementConfig = "rollkit.settlement_config"
+	flagCommitThreshold  = "rollkit.commit_threshold"
+	flagAggregatorKeys   = "rollkit.bls_aggregator_keys"
+	flagEncryptedDelay   = "rollkit.encrypted_txs_delay"
+	flagEncryptedWindow  = "rollkit.encrypted_txs_window"
+	flagEncryptedKey     = "rollkit.encrypted_txs_key"
+	flagBanThreshold     = "rollkit.p2p_ban_threshold"
+	flagBanDuration      = "rollkit.p2p_ban_duration"
+	flagMaxInboundPeers  = "rollkit.p2p_max_inbound_peers"
+	flagMaxOutboundPeers = "rollkit.p2p_max_outbound_peers"
+	flagPeerGracePeriod  = "rollkit.p2p_peer_grace_period"
+	flagQUICListenAddr   = "rollkit.p2p_quic_listen_address"
+	flagWSListenAddr     = "rollkit.p2p_ws_listen_address"
+	flagNATPortMap       = "rollkit.p2p_nat_port_map"
+	flagNATService       = "rollkit.p2p_nat_service"
+	flagHolePunching     = "rollkit.p2p_hole_punching"
+	flagRelayService     = "rollkit.p2p_relay_service"
+	flagStaticRelays     = "rollkit.p2p_static_relays"
+	flagBlockBandwidth   = "rollkit.p2p_block_bandwidth"
+	flagHeaderBandwidth  = "rollkit.p2p_header_bandwidth"
+	flagTxBandwidth      = "rollkit.p2p_tx_bandwidth"
+	flagPeerBandwidth    = "rollkit.p2p_peer_bandwidth"
+	flagGossipFanout     = "rollkit.p2p_gossip_fanout"
+	flagGossipHeartbeat  = "rollkit.p2p_gossip_heartbeat"
+	flagHeaderBatchSize  = "rollkit.p2p_header_batch_size"
+	flagHeaderBatchIntvl = "rollkit.p2p_header_batch_interval"
+	flagNodeRole         = "rollkit.node_role"
+	flagRetainBlocks     = "rollkit.retain_blocks"
+	flagSyncMode         = "rollkit.sync_mode"
+	flagMaxFutureTime    = "rollkit.max_future_time"
+	flagNTPServer        = "rollkit.ntp_server"
+	flagMaxClockDrift    = "rollkit.max_clock_drift"
+	flagWithholdWindow   = "rollkit.withholding_window"
+	flagWithholdHalt     = "rollkit.withholding_halt"
+	flagSnapshotInterval = "rollkit.da_snapshot_interval"
+	flagSnapshotNS       = "rollkit.da_snapshot_namespace_id"
+	flagSnapshotDAHeight = "rollkit.da_snapshot_height"
+	flagStateImportFile  = "rollkit.state_import_file"
+	flagStateExport      = "rollkit.state_export_max_concurrent"
+	flagSharedNamespace  = "rollkit.da_shared_namespace"
+	flagDAMaxPending     = "rollkit.da_max_pending_blocks"
+	flagDAReconnect      = "rollkit.da_reconnect_interval"
+	flagTxRatePerIP      = "rollkit.tx_rate_per_ip"
+	flagTxRatePerSender  = "rollkit.tx_rate_per_sender"
+	flagTxRateBurst      = "rollkit.tx_rate_burst"
+	flagTxMaxBytes       = "rollkit.tx_max_bytes"
+	flagTxMinFee         = "rollkit.tx_min_fee"
+	flagTxFeeAttribute   = "rollkit.tx_fee_attribute"
+	flagTxCommitTimeout  = "rollkit.tx_commit_timeout"
+	flagEventSinks       = "rollkit.event_sinks"
+	flagBlockCacheSize   = "rollkit.block_cache_size"
+	flagInclusionListEv  = "rollkit.inclusion_list_evidence"
+	flagTracingEndpoint  = "rollkit.tracing_endpoint"
+	flagKeyringBackend   = "rollkit.keyring_backend"
+	flagKeyringDir       = "rollkit.keyring_dir"
+	flagTracingInsecure  = "rollkit.tracing_insecure"
+	flagTracingSampling  = "rollkit.tracing_sample_rate"
+)
+
+const (
+	// NodeRoleArchival is the role of node keeping the entire history of the chain, and serving it
+	// (and application snapshots) to peers.
+	NodeRoleArchival = "archival"
+	// NodeRolePruned is the role of node keeping only the most recent blocks, and fetching older blocks
+	// from archival peers when requested.
+	NodeRolePruned = "pruned"
+)
+
+const (
+	// SyncModeBlocks is the sync mode of node downloading and applying blocks one by one, from DA layer
+	// and P2P network.
+	SyncModeBlocks = "blocks"
+	// SyncModeHeadersFirst is the sync mode of node syncing and verifying the complete header chain first,
+	// and then fetching block data lazily by height ranges, from peers or DA layer.
+	SyncModeHeadersFirst = "headers_first"
+)
+
+const (
+	// LogFormatPlain is the format of human readable log lines.
+	LogFormatPlain = "plain"
+	// LogFormatJSON is the format of structured logs, with a JSON object per line.
+	LogFormatJSON = "json"
 )
 
 // NodeConfig stores Rollkit node configuration.
@@ -32,6 +140,15 @@ type NodeConfig struct {
 	DBPath  string
 	P2P     P2PConfig
 	RPC     RPCConfig
+	// Instrumentation enables Prometheus metrics, exported to the default Prometheus registry.
+	Instrumentation *cmcfg.InstrumentationConfig
+	// Mempool configures the transaction pool, including expiration of transactions (TTL).
+	Mempool *cmcfg.MempoolConfig
+	// LogLevel is the log level in CometBFT format, either a single level (e.g. "info") or log levels of modules
+	// (e.g. "p2p:error,block:debug,*:info"). Modules are block, da, p2p, rpc and store.
+	LogLevel string
+	// LogFormat is either LogFormatPlain or LogFormatJSON.
+	LogFormat string
 	// parameters below are Rollkit specific and read from config
 	Aggregator         bool `mapstructure:"aggregator"`
 	BlockManagerConfig `mapstructure:",squash"`
@@ -40,6 +157,100 @@ type NodeConfig struct {
 	Light              bool   `mapstructure:"light"`
 	HeaderConfig       `mapstructure:",squash"`
 	LazyAggregator     bool `mapstructure:"lazy_aggregator"`
+	// MempoolNonce enables ordering of mempool transactions by sender nonce. It's a composite key
+	// "<event type>.<attribute key>" of CheckTx event attribute containing the nonce, e.g. "tx.nonce".
+	MempoolNonce string `mapstructure:"mempool_nonce"`
+	// MempoolReplaceBump enables replace-by-fee in the mempool. It's the minimal priority increase (in percent)
+	// required to replace a transaction of the same sender (and nonce). Zero disables replacement.
+	MempoolReplaceBump uint64 `mapstructure:"mempool_replace_bump"`
+	// MempoolMaxTxsPerSender limits the number of mempool transactions of a single sender (with nonce ordering).
+	// Zero means no limit.
+	MempoolMaxTxsPerSender int `mapstructure:"mempool_max_txs_per_sender"`
+	// MempoolCacheTTL is the expiration time of entries in the cache of seen transactions. Zero disables expiration.
+	MempoolCacheTTL time.Duration `mapstructure:"mempool_cache_ttl"`
+	// MempoolCheckTxBatch enables batching of incoming transactions, with pipelined CheckTx calls to the application.
+	// It's the maximal number of transactions in a batch. Zero disables batching.
+	MempoolCheckTxBatch int `mapstructure:"mempool_checktx_batch"`
+	// MempoolSenderAllowlist restricts mempool transactions to the listed senders (as reported by CheckTx).
+	// Empty list allows all senders.
+	MempoolSenderAllowlist []string `mapstructure:"mempool_sender_allowlist"`
+	// MempoolSenderDenylist rejects mempool transactions of the listed senders (as reported by CheckTx).
+	MempoolSenderDenylist []string `mapstructure:"mempool_sender_denylist"`
+	// TxRatePerIP limits the rate (in transactions per second) of transactions broadcast via RPC from a single
+	// IP address. Zero disables the limit.
+	TxRatePerIP float64 `mapstructure:"tx_rate_per_ip"`
+	// TxRatePerSender limits the rate (in transactions per second) of transactions of a single sender (as reported
+	// by CheckTx) accepted by the mempool. Zero disables the limit.
+	TxRatePerSender float64 `mapstructure:"tx_rate_per_sender"`
+	// TxRateBurst is the number of transactions accepted in a burst above the rate limits. Zero means one second
+	// worth of transactions.
+	TxRateBurst int `mapstructure:"tx_rate_burst"`
+	// TxMaxBytes limits the size of transactions broadcast via RPC. Zero means the limit of the mempool.
+	TxMaxBytes int `mapstructure:"tx_max_bytes"`
+	// TxMinFee is the minimal fee of transactions accepted by the mempool. Zero disables the threshold.
+	TxMinFee uint64 `mapstructure:"tx_min_fee"`
+	// TxFeeAttribute is a composite key "<event type>.<attribute key>" of CheckTx event attribute containing
+	// the fee of transaction, e.g. "tx.fee". It's required by TxMinFee.
+	TxFeeAttribute string `mapstructure:"tx_fee_attribute"`
+	// TxCommitTimeout is the time broadcast_tx_commit waits for the transaction to be included in a block.
+	TxCommitTimeout time.Duration `mapstructure:"tx_commit_timeout"`
+	// ReadyMaxLag is the maximal number of blocks the node can lag behind the head of the network
+	// and still report readiness on the /ready endpoint.
+	ReadyMaxLag uint64 `mapstructure:"ready_max_lag"`
+	// AdminToken enables admin RPC methods, authorized with "Authorization: Bearer <token>" header.
+	// Empty token disables admin RPC.
+	AdminToken string `mapstructure:"admin_token"`
+	// RemoteSigner is the gRPC address of the remote signer used by the aggregator to sign blocks.
+	// Empty address means that blocks are signed with the local proposer key.
+	RemoteSigner string `mapstructure:"remote_signer"`
+	// RemoteSignerTLSCert and RemoteSignerTLSKey are the files (absolute, or relative to the root directory) with
+	// the client certificate and key authenticating the node to the remote signer. Connection to the remote signer
+	// requires mutual TLS.
+	RemoteSignerTLSCert string `mapstructure:"remote_signer_tls_cert"`
+	RemoteSignerTLSKey  string `mapstructure:"remote_signer_tls_key"`
+	// RemoteSignerTLSCA is the file (absolute, or relative to the root directory) with the CA certificate, that
+	// the certificate of the remote signer has to be signed by.
+	RemoteSignerTLSCA string `mapstructure:"remote_signer_tls_ca"`
+	// SequencerAddress is the gRPC address of the (shared) sequencer ordering rollup transactions.
+	// Empty address means that the aggregator orders transactions from its mempool.
+	SequencerAddress string `mapstructure:"sequencer_address"`
+	// FCFSOrdering enables first-come-first-served ordering: transactions are included in blocks strictly
+	// in order of arrival, attested in headers of produced blocks.
+	FCFSOrdering bool `mapstructure:"fcfs_ordering"`
+	// SettlementLayer is the name of the settlement layer client, used to post commitments of blocks
+	// and read their finality. Empty name means sovereign rollup.
+	SettlementLayer  string `mapstructure:"settlement_layer"`
+	SettlementConfig string `mapstructure:"settlement_config"`
+	// NodeRole is either NodeRoleArchival or NodeRolePruned.
+	NodeRole string `mapstructure:"node_role"`
+	// RetainBlocks is the number of the most recent blocks kept by pruned node.
+	RetainBlocks uint64 `mapstructure:"retain_blocks"`
+	// SyncMode is either SyncModeBlocks or SyncModeHeadersFirst.
+	SyncMode string `mapstructure:"sync_mode"`
+	// NTPServer is the NTP server used to detect drift of the system clock. Empty server disables detection.
+	NTPServer string `mapstructure:"ntp_server"`
+	// MaxClockDrift is the drift of the system clock from time of the NTP server, above which warnings are logged.
+	MaxClockDrift time.Duration `mapstructure:"max_clock_drift"`
+	// EventSinks are URLs of sinks receiving events of applied blocks (webhook, NATS or Kafka REST Proxy).
+	// Kafka wire protocol is not supported, so Kafka is reachable only through REST Proxy.
+	EventSinks []string `mapstructure:"event_sinks"`
+	// BlockCacheSize is the number of the latest blocks (and their commits) cached in memory for RPC requests.
+	// Zero disables the cache.
+	BlockCacheSize uint64 `mapstructure:"block_cache_size"`
+	// KeyringBackend enables loading of the node and proposer keys from the keyring with given backend (file or os),
+	// instead of using keys passed to the node constructor. Keys are generated on first use. The passphrase of
+	// the file keyring is read from the ROLLKIT_KEYRING_PASSPHRASE environment variable.
+	KeyringBackend string `mapstructure:"keyring_backend"`
+	// KeyringDir is the directory (absolute, or relative to the root directory) of the keyring.
+	KeyringDir string `mapstructure:"keyring_dir"`
+	// TracingEndpoint is the host:port of OTLP/gRPC collector receiving traces of the node. Empty endpoint
+	// disables tracing.
+	TracingEndpoint string `mapstructure:"tracing_endpoint"`
+	// TracingInsecure disables TLS of the connection to the OTLP collector.
+	TracingInsecure bool `mapstructure:"tracing_insecure"`
+	// TracingSampleRate is the fraction of traces that are sampled and exported, in range (0, 1]. Zero means that
+	// all traces are sampled.
+	TracingSampleRate float64 `mapstructure:"tracing_sample_rate"`
 }
 
 // HeaderConfig allows node to pass the initial trusted header hash to start the header exchange service
@@ -56,6 +267,84 @@ type BlockManagerConfig struct {
 	// DAStartHeight allows skipping first DAStartHeight-1 blocks when querying for blocks.
 	DAStartHeight uint64            `mapstructure:"da_start_height"`
 	NamespaceID   types.NamespaceID `mapstructure:"namespace_id"`
+	// IntermediateStateRoots enables computation and verification of intermediate state roots.
+	// Application has to support the ISR ABCI query.
+	IntermediateStateRoots bool `mapstructure:"intermediate_state_roots"`
+	// TxPreValidation enables parallel stateless validation of transactions before block execution.
+	// Application has to support the tx validation ABCI query.
+	TxPreValidation bool `mapstructure:"tx_prevalidation"`
+	// ValidityProofs enables generation of validity proofs for produced blocks.
+	// Application has to support the validity proof ABCI query.
+	ValidityProofs bool `mapstructure:"validity_proofs"`
+	// HeaderExtensions enables extensions of headers of produced blocks, provided by the application.
+	// Application has to support the header extensions ABCI query.
+	HeaderExtensions bool `mapstructure:"header_extensions"`
+	// DACostFeedback enables reporting of costs of DA submissions of the aggregator to the application,
+	// e.g. for fee market pricing. Application has to support the DA cost ABCI query.
+	DACostFeedback bool `mapstructure:"da_cost_feedback"`
+	// ABCITimeout limits duration of every call to the application made during block execution.
+	// Zero disables the limit.
+	ABCITimeout time.Duration `mapstructure:"abci_timeout"`
+	// SignerTimeout limits duration of a single attempt to sign a block. Zero disables the limit.
+	SignerTimeout time.Duration `mapstructure:"signer_timeout"`
+	// CommitThreshold is the fraction of the total voting power of the aggregator set, that has to be exceeded
+	// by aggregators signing a block. Aggregators don't co-sign blocks yet, so zero value (the default) requires
+	// only the signature of the proposer.
+	CommitThreshold cmtmath.Fraction `mapstructure:"commit_threshold"`
+	// AggregatorKeys are BLS public keys of aggregators, ordered like in the aggregator set. Every entry is a hex
+	// encoded public key and hex encoded proof of possession of its private key, separated by colon (see
+	// `keys show`). If set, aggregators sign blocks with BLS keys and their signatures are aggregated into a
+	// single signature.
+	AggregatorKeys []string `mapstructure:"bls_aggregator_keys"`
+	// EncryptedTxsDelay enables delayed execution of encrypted transactions. It's the number of blocks between
+	// the target height of encrypted transactions and the block executing them, in which the decryption committee
+	// releases the decryption key of the target height. Zero disables encrypted transactions. It has to be the same
+	// on all nodes of the chain.
+	EncryptedTxsDelay uint64 `mapstructure:"encrypted_txs_delay"`
+	// EncryptedTxsWindow is the number of blocks up to the target height, in which encrypted transactions can be
+	// included. Zero means the default window. It has to be the same on all nodes of the chain.
+	EncryptedTxsWindow uint64 `mapstructure:"encrypted_txs_window"`
+	// EncryptedTxsKey is the hex encoded BLS public key of the decryption committee, which derives decryption keys
+	// of heights with a threshold of shares of its private key. It's required by encrypted transactions, and has
+	// to be the same on all nodes of the chain.
+	EncryptedTxsKey string `mapstructure:"encrypted_txs_key"`
+	// MaxFutureTime is the maximal time, by which incoming headers (gossiped or synced) can be ahead of the clock
+	// of the node. Headers further in the future are rejected. Zero disables the limit.
+	MaxFutureTime time.Duration `mapstructure:"max_future_time"`
+	// WithholdingWindow enables detection of data withholding: blocks synced from P2P network (soft confirmed)
+	// that don't appear on DA layer within the window are flagged as withheld. Zero disables detection.
+	WithholdingWindow time.Duration `mapstructure:"withholding_window"`
+	// WithholdingHalt halts syncing of blocks not seen on DA layer (soft confirmations) while any block is flagged
+	// as withheld.
+	WithholdingHalt bool `mapstructure:"withholding_halt"`
+	// SnapshotInterval enables publishing of application snapshots to DA layer by the aggregator. It's the minimal
+	// number of blocks between published snapshots. Zero disables publishing.
+	SnapshotInterval uint64 `mapstructure:"da_snapshot_interval"`
+	// SnapshotNamespaceID is the namespace of application snapshots on DA layer.
+	SnapshotNamespaceID types.NamespaceID `mapstructure:"da_snapshot_namespace_id"`
+	// SnapshotDAHeight is the DA height of the snapshot manifest, that a node with empty store restores
+	// the application state from, instead of syncing from genesis. Zero disables restoring.
+	SnapshotDAHeight uint64 `mapstructure:"da_snapshot_height"`
+	// StateImportFile is the path (absolute, or relative to the root directory) of a file with state export stream
+	// (e.g. exported by another node over RPC), that a node with empty store imports the state from, instead of
+	// syncing from genesis. Empty disables import.
+	StateImportFile string `mapstructure:"state_import_file"`
+	// StateExportMaxConcurrent enables the /state_export endpoint of the RPC server, serving state export stream of
+	// the node. It's the maximal number of exports served concurrently, as every export reads the whole application
+	// snapshot. Zero disables the endpoint.
+	StateExportMaxConcurrent uint64 `mapstructure:"state_export_max_concurrent"`
+	// SharedNamespace enables sharing of the namespace of blocks with other types of blobs (like snapshot chunks
+	// and fraud proofs). Every blob is tagged with its type. It has to be the same on all nodes of the chain.
+	SharedNamespace bool `mapstructure:"da_shared_namespace"`
+	// DAMaxPendingBlocks limits the number of produced blocks waiting for submission to DA layer. When the DA layer
+	// is unavailable and the limit is reached, the aggregator stops producing blocks. Zero disables the limit.
+	DAMaxPendingBlocks uint64 `mapstructure:"da_max_pending_blocks"`
+	// DAReconnectInterval is the maximal interval between attempts to reconnect to DA layer, when it's unavailable.
+	// Zero means DABlockTime.
+	DAReconnectInterval time.Duration `mapstructure:"da_reconnect_interval"`
+	// InclusionListEvidence enables proving violations of inclusion lists signed by the proposer, with headers
+	// and hashes of transactions of blocks within the deadline of the list.
+	InclusionListEvidence bool `mapstructure:"inclusion_list_evidence"`
 }
 
 // GetNodeConfig translates Tendermint's configuration into Rollkit configuration.
@@ -66,9 +355,15 @@ func GetNodeConfig(nodeConf *NodeConfig, cmConf *cmcfg.Config) {
 	if cmConf != nil {
 		nodeConf.RootDir = cmConf.RootDir
 		nodeConf.DBPath = cmConf.DBPath
+		nodeConf.Instrumentation = cmConf.Instrumentation
+		nodeConf.Mempool = cmConf.Mempool
+		nodeConf.LogLevel = cmConf.LogLevel
+		nodeConf.LogFormat = cmConf.LogFormat
 		if cmConf.P2P != nil {
 			nodeConf.P2P.ListenAddress = cmConf.P2P.ListenAddress
 			nodeConf.P2P.Seeds = cmConf.P2P.Seeds
+			nodeConf.P2P.PersistentPeers = cmConf.P2P.PersistentPeers
+			nodeConf.P2P.PEX = cmConf.P2P.PexReactor
 		}
 		if cmConf.RPC != nil {
 			nodeConf.RPC.ListenAddress = cmConf.RPC.ListenAddress
@@ -91,8 +386,103 @@ func (nc *NodeConfig) GetViperConfig(v *viper.Viper) error {
 	nc.DAConfig = v.GetString(flagDAConfig)
 	nc.DAStartHeight = v.GetUint64(flagDAStartHeight)
 	nc.DABlockTime = v.GetDuration(flagDABlockTime)
+	nc.DAMaxPendingBlocks = v.GetUint64(flagDAMaxPending)
+	nc.DAReconnectInterval = v.GetDuration(flagDAReconnect)
 	nc.BlockTime = v.GetDuration(flagBlockTime)
 	nc.LazyAggregator = v.GetBool(flagLazyAggregator)
+	nc.MempoolNonce = v.GetString(flagMempoolNonce)
+	nc.MempoolReplaceBump = v.GetUint64(flagMempoolRBF)
+	nc.MempoolMaxTxsPerSender = v.GetInt(flagMempoolPerSender)
+	nc.MempoolCacheTTL = v.GetDuration(flagMempoolCacheTTL)
+	nc.MempoolCheckTxBatch = v.GetInt(flagMempoolBatch)
+	nc.MempoolSenderAllowlist = v.GetStringSlice(flagMempoolAllow)
+	nc.MempoolSenderDenylist = v.GetStringSlice(flagMempoolDeny)
+	nc.TxRatePerIP = v.GetFloat64(flagTxRatePerIP)
+	nc.TxRatePerSender = v.GetFloat64(flagTxRatePerSender)
+	nc.TxRateBurst = v.GetInt(flagTxRateBurst)
+	nc.TxMaxBytes = v.GetInt(flagTxMaxBytes)
+	nc.TxMinFee = v.GetUint64(flagTxMinFee)
+	nc.TxFeeAttribute = v.GetString(flagTxFeeAttribute)
+	nc.TxCommitTimeout = v.GetDuration(flagTxCommitTimeout)
+	nc.ReadyMaxLag = v.GetUint64(flagReadyMaxLag)
+	nc.AdminToken = v.GetString(flagAdminToken)
+	nc.RemoteSigner = v.GetString(flagRemoteSigner)
+	nc.SignerTimeout = v.GetDuration(flagSignerTimeout)
+	nc.RemoteSignerTLSCert = v.GetString(flagSignerTLSCert)
+	nc.RemoteSignerTLSKey = v.GetString(flagSignerTLSKey)
+	nc.RemoteSignerTLSCA = v.GetString(flagSignerTLSCA)
+	nc.SequencerAddress = v.GetString(flagSequencer)
+	nc.FCFSOrdering = v.GetBool(flagFCFSOrdering)
+	nc.SettlementLayer = v.GetString(flagSettlementLayer)
+	nc.SettlementConfig = v.GetString(flagSettlementConfig)
+	nc.NodeRole = v.GetString(flagNodeRole)
+	nc.RetainBlocks = v.GetUint64(flagRetainBlocks)
+	nc.SyncMode = v.GetString(flagSyncMode)
+	nc.MaxFutureTime = v.GetDuration(flagMaxFutureTime)
+	nc.WithholdingWindow = v.GetDuration(flagWithholdWindow)
+	nc.WithholdingHalt = v.GetBool(flagWithholdHalt)
+	nc.SnapshotInterval = v.GetUint64(flagSnapshotInterval)
+	nc.SnapshotDAHeight = v.GetUint64(flagSnapshotDAHeight)
+	nc.StateImportFile = v.GetString(flagStateImportFile)
+	nc.StateExportMaxConcurrent = v.GetUint64(flagStateExport)
+	nc.SharedNamespace = v.GetBool(flagSharedNamespace)
+	if snapshotNS := v.GetString(flagSnapshotNS); snapshotNS != "" {
+		bytes, err := hex.DecodeString(snapshotNS)
+		if err != nil {
+			return err
+		}
+		copy(nc.SnapshotNamespaceID[:], bytes)
+	}
+	nc.NTPServer = v.GetString(flagNTPServer)
+	nc.MaxClockDrift = v.GetDuration(flagMaxClockDrift)
+	nc.EventSinks = v.GetStringSlice(flagEventSinks)
+	nc.BlockCacheSize = v.GetUint64(flagBlockCacheSize)
+	nc.KeyringBackend = v.GetString(flagKeyringBackend)
+	nc.KeyringDir = v.GetString(flagKeyringDir)
+	nc.TracingEndpoint = v.GetString(flagTracingEndpoint)
+	nc.TracingInsecure = v.GetBool(flagTracingInsecure)
+	nc.TracingSampleRate = v.GetFloat64(flagTracingSampling)
+	nc.InclusionListEvidence = v.GetBool(flagInclusionListEv)
+	if s := v.GetString(flagCommitThreshold); s != "" {
+		threshold, err := cmtmath.ParseFraction(s)
+		if err != nil {
+			return fmt.Errorf("invalid commit threshold: %w", err)
+		}
+		if threshold.Numerator >= threshold.Denominator {
+			return fmt.Errorf("invalid commit threshold: %s is not lower than 1", threshold)
+		}
+		nc.CommitThreshold = threshold
+	}
+	nc.AggregatorKeys = v.GetStringSlice(flagAggregatorKeys)
+	nc.EncryptedTxsDelay = v.GetUint64(flagEncryptedDelay)
+	nc.EncryptedTxsWindow = v.GetUint64(flagEncryptedWindow)
+	nc.EncryptedTxsKey = v.GetString(flagEncryptedKey)
+	nc.P2P.BanThreshold = v.GetFloat64(flagBanThreshold)
+	nc.P2P.BanDuration = v.GetDuration(flagBanDuration)
+	nc.P2P.MaxInboundPeers = v.GetInt(flagMaxInboundPeers)
+	nc.P2P.MaxOutboundPeers = v.GetInt(flagMaxOutboundPeers)
+	nc.P2P.PeerGracePeriod = v.GetDuration(flagPeerGracePeriod)
+	nc.P2P.QUICListenAddress = v.GetString(flagQUICListenAddr)
+	nc.P2P.WebSocketListenAddress = v.GetString(flagWSListenAddr)
+	nc.P2P.NATPortMap = v.GetBool(flagNATPortMap)
+	nc.P2P.NATService = v.GetBool(flagNATService)
+	nc.P2P.HolePunching = v.GetBool(flagHolePunching)
+	nc.P2P.RelayService = v.GetBool(flagRelayService)
+	nc.P2P.StaticRelays = v.GetString(flagStaticRelays)
+	nc.P2P.BlockBandwidth = v.GetUint64(flagBlockBandwidth)
+	nc.P2P.HeaderBandwidth = v.GetUint64(flagHeaderBandwidth)
+	nc.P2P.TxBandwidth = v.GetUint64(flagTxBandwidth)
+	nc.P2P.PeerBandwidth = v.GetUint64(flagPeerBandwidth)
+	nc.P2P.GossipFanout = v.GetInt(flagGossipFanout)
+	nc.P2P.GossipHeartbeat = v.GetDuration(flagGossipHeartbeat)
+	nc.P2P.HeaderBatchSize = v.GetInt(flagHeaderBatchSize)
+	nc.P2P.HeaderBatchInterval = v.GetDuration(flagHeaderBatchIntvl)
+	nc.IntermediateStateRoots = v.GetBool(flagISRs)
+	nc.TxPreValidation = v.GetBool(flagTxPreValidation)
+	nc.ValidityProofs = v.GetBool(flagValidityProofs)
+	nc.HeaderExtensions = v.GetBool(flagHeaderExtensions)
+	nc.DACostFeedback = v.GetBool(flagDACostFeedback)
+	nc.ABCITimeout = v.GetDuration(flagABCITimeout)
 	nsID := v.GetString(flagNamespaceID)
 	nc.Light = v.GetBool(flagLight)
 	bytes, err := hex.DecodeString(nsID)
@@ -108,15 +498,103 @@ func (nc *NodeConfig) GetViperConfig(v *viper.Viper) error {
 //
 // This function is called in cosmos-sdk.
 func AddFlags(cmd *cobra.Command) {
-	def := DefaultNodeConfig
-	cmd.Flags().Bool(flagAggregator, def.Aggregator, "run node in aggregator mode")
-	cmd.Flags().Bool(flagLazyAggregator, def.LazyAggregator, "wait for transactions, don't build empty blocks")
-	cmd.Flags().String(flagDALayer, def.DALayer, "Data Availability Layer Client name (mock or grpc")
-	cmd.Flags().String(flagDAConfig, def.DAConfig, "Data Availability Layer Client config")
-	cmd.Flags().Duration(flagBlockTime, def.BlockTime, "block time (for aggregator mode)")
-	cmd.Flags().Duration(flagDABlockTime, def.DABlockTime, "DA chain block time (for syncing)")
-	cmd.Flags().Uint64(flagDAStartHeight, def.DAStartHeight, "starting DA block height (for syncing)")
-	cmd.Flags().BytesHex(flagNamespaceID, def.NamespaceID[:], "namespace identifies (8 bytes in hex)")
-	cmd.Flags().Bool(flagLight, def.Light, "run light client")
-	cmd.Flags().String(flagTrustedHash, def.TrustedHash, "initial trusted hash to start the header exchange service")
+	addFlags(cmd.Flags(), DefaultNodeConfig)
+}
+
+// addFlags adds Rollkit specific options to the flag set, with default values taken from the configuration.
+func addFlags(flags *pflag.FlagSet, def NodeConfig) {
+	threshold := ""
+	if def.CommitThreshold.Denominator != 0 {
+		threshold = def.CommitThreshold.String()
+	}
+	flags.Bool(flagAggregator, def.Aggregator, "run node in aggregator mode")
+	flags.Bool(flagLazyAggregator, def.LazyAggregator, "wait for transactions, don't build empty blocks")
+	flags.String(flagDALayer, def.DALayer, "Data Availability Layer Client name (mock or grpc")
+	flags.String(flagDAConfig, def.DAConfig, "Data Availability Layer Client config")
+	flags.Duration(flagBlockTime, def.BlockTime, "block time (for aggregator mode)")
+	flags.Duration(flagDABlockTime, def.DABlockTime, "DA chain block time (for syncing)")
+	flags.Uint64(flagDAStartHeight, def.DAStartHeight, "starting DA block height (for syncing)")
+	flags.Uint64(flagDAMaxPending, def.DAMaxPendingBlocks, "maximal number of blocks waiting for submission to DA layer, before the aggregator stops producing blocks (0 disables the limit)")
+	flags.Duration(flagDAReconnect, def.DAReconnectInterval, "maximal interval between attempts to reconnect to unavailable DA layer (0 means DA block time)")
+	flags.BytesHex(flagNamespaceID, def.NamespaceID[:], "namespace identifies (8 bytes in hex), must match namespace from genesis if defined there")
+	flags.Bool(flagLight, def.Light, "run light client")
+	flags.String(flagTrustedHash, def.TrustedHash, "initial trusted hash to start the header exchange service")
+	flags.Bool(flagISRs, def.IntermediateStateRoots, "compute and verify intermediate state roots (requires application support)")
+	flags.Bool(flagTxPreValidation, def.TxPreValidation, "validate transactions in parallel before block execution (requires application support)")
+	flags.Bool(flagValidityProofs, def.ValidityProofs, "generate validity proofs for produced blocks (requires application support)")
+	flags.Bool(flagHeaderExtensions, def.HeaderExtensions, "include extensions provided by the application in headers of produced blocks (requires application support)")
+	flags.Bool(flagDACostFeedback, def.DACostFeedback, "report fees paid for DA submissions to the application, e.g. for fee market pricing (requires application support)")
+	flags.Duration(flagABCITimeout, def.ABCITimeout, "timeout of a single call to the application during block execution (0 disables it)")
+	flags.String(flagMempoolNonce, def.MempoolNonce, "CheckTx event attribute with sender nonce used to order mempool transactions, e.g. tx.nonce (empty disables ordering)")
+	flags.Uint64(flagMempoolRBF, def.MempoolReplaceBump, "minimal priority increase in percent to replace mempool transaction of the same sender (0 disables replacement)")
+	flags.Int(flagMempoolPerSender, def.MempoolMaxTxsPerSender, "maximal number of mempool transactions of a single sender, with nonce ordering (0 means no limit)")
+	flags.Duration(flagMempoolCacheTTL, def.MempoolCacheTTL, "expiration time of entries in the cache of seen mempool transactions (0 disables expiration)")
+	flags.Int(flagMempoolBatch, def.MempoolCheckTxBatch, "maximal number of incoming transactions checked by the application in a single pipelined batch (0 disables batching)")
+	flags.StringSlice(flagMempoolAllow, def.MempoolSenderAllowlist, "comma-separated list of senders allowed to submit mempool transactions (empty allows all senders)")
+	flags.StringSlice(flagMempoolDeny, def.MempoolSenderDenylist, "comma-separated list of senders denied to submit mempool transactions")
+	flags.Float64(flagTxRatePerIP, def.TxRatePerIP, "maximal rate (transactions per second) of transactions broadcast via RPC from a single IP address (0 disables the limit)")
+	flags.Float64(flagTxRatePerSender, def.TxRatePerSender, "maximal rate (transactions per second) of transactions of a single sender accepted by the mempool (0 disables the limit)")
+	flags.Int(flagTxRateBurst, def.TxRateBurst, "number of transactions accepted in a burst above the rate limits (0 means one second worth of transactions)")
+	flags.Int(flagTxMaxBytes, def.TxMaxBytes, "maximal size of transactions broadcast via RPC (0 means the limit of the mempool)")
+	flags.Uint64(flagTxMinFee, def.TxMinFee, "minimal fee of transactions accepted by the mempool (0 disables the threshold)")
+	flags.String(flagTxFeeAttribute, def.TxFeeAttribute, "CheckTx event attribute with transaction fee, e.g. tx.fee (required by minimal fee)")
+	flags.Duration(flagTxCommitTimeout, def.TxCommitTimeout, "time broadcast_tx_commit waits for the transaction to be included in a block")
+	flags.Uint64(flagReadyMaxLag, def.ReadyMaxLag, "maximal number of blocks the node can lag behind the network head and still be ready")
+	flags.String(flagAdminToken, def.AdminToken, "bearer token authorizing admin RPC calls (empty disables admin RPC)")
+	flags.String(flagRemoteSigner, def.RemoteSigner, "gRPC address of the remote signer used to sign blocks (empty means local proposer key)")
+	flags.String(flagSignerTLSCert, def.RemoteSignerTLSCert, "client certificate file authenticating the node to the remote signer (mutual TLS)")
+	flags.String(flagSignerTLSKey, def.RemoteSignerTLSKey, "client key file authenticating the node to the remote signer (mutual TLS)")
+	flags.String(flagSignerTLSCA, def.RemoteSignerTLSCA, "CA certificate file verifying the certificate of the remote signer")
+	flags.Duration(flagSignerTimeout, def.SignerTimeout, "timeout of a single attempt to sign a block (0 disables it)")
+	flags.String(flagSequencer, def.SequencerAddress, "gRPC address of the shared sequencer ordering rollup transactions (empty means aggregator mempool)")
+	flags.Bool(flagFCFSOrdering, def.FCFSOrdering, "order transactions strictly by time of arrival and attest the ordering in headers of produced blocks")
+	flags.String(flagSettlementLayer, def.SettlementLayer, "Settlement Layer Client name (empty means sovereign rollup)")
+	flags.String(flagSettlementConfig, def.SettlementConfig, "Settlement Layer Client config")
+	flags.String(flagNodeRole, def.NodeRole, "role of the node: archival (keeps and serves entire history) or pruned (keeps recent blocks only)")
+	flags.Uint64(flagRetainBlocks, def.RetainBlocks, "number of the most recent blocks kept by pruned node")
+	flags.String(flagSyncMode, def.SyncMode, "sync mode of full node: blocks (applies blocks as they are downloaded) or headers_first (syncs header chain first, fetches block data lazily)")
+	flags.Duration(flagMaxFutureTime, def.MaxFutureTime, "maximal time by which incoming headers can be ahead of the node clock (0 disables the limit)")
+	flags.Duration(flagWithholdWindow, def.WithholdingWindow, "time within which blocks received from P2P network have to appear on DA layer, before they are flagged as withheld (0 disables detection)")
+	flags.Bool(flagWithholdHalt, def.WithholdingHalt, "halt syncing of blocks not seen on DA layer while data withholding is detected")
+	flags.Uint64(flagSnapshotInterval, def.SnapshotInterval, "minimal number of blocks between application snapshots published to DA layer by the aggregator (0 disables publishing)")
+	flags.BytesHex(flagSnapshotNS, def.SnapshotNamespaceID[:], "namespace of application snapshots on DA layer (8 bytes in hex)")
+	flags.Uint64(flagSnapshotDAHeight, def.SnapshotDAHeight, "DA height of the snapshot manifest to restore the application state from, if the store is empty (0 syncs from genesis)")
+	flags.String(flagStateImportFile, def.StateImportFile, "file with exported state to import, if the store is empty (empty syncs from genesis)")
+	flags.Uint64(flagStateExport, def.StateExportMaxConcurrent, "maximal number of state exports served concurrently from /state_export RPC endpoint (0 disables the endpoint)")
+	flags.Bool(flagSharedNamespace, def.SharedNamespace, "share the namespace of blocks with other types of blobs (snapshot chunks, fraud proofs), tagging every blob with its type")
+	flags.String(flagNTPServer, def.NTPServer, "NTP server used to detect drift of the system clock (empty disables detection)")
+	flags.Duration(flagMaxClockDrift, def.MaxClockDrift, "drift of the system clock from the NTP server time, above which warnings are logged")
+	flags.StringSlice(flagEventSinks, def.EventSinks, "comma-separated list of URLs receiving events of applied blocks: http(s)://... (webhook), nats://host:port/subject, kafka+http(s)://rest-proxy/topic (Kafka through REST Proxy only, brokers can't be used directly)")
+	flags.Uint64(flagBlockCacheSize, def.BlockCacheSize, "number of the latest blocks cached in memory for RPC requests (0 disables the cache)")
+	flags.String(flagKeyringBackend, def.KeyringBackend, "keyring backend (file or os) storing the node and proposer keys (empty means keys provided by the application)")
+	flags.String(flagKeyringDir, def.KeyringDir, "keyring directory, absolute or relative to the root directory")
+	flags.String(flagTracingEndpoint, def.TracingEndpoint, "host:port of OTLP/gRPC collector receiving traces (empty disables tracing)")
+	flags.Bool(flagTracingInsecure, def.TracingInsecure, "connect to OTLP collector without TLS")
+	flags.Float64(flagTracingSampling, def.TracingSampleRate, "fraction of traces sampled and exported to OTLP collector, in range (0, 1]")
+	flags.Bool(flagInclusionListEv, def.InclusionListEvidence, "prove violations of inclusion lists signed by the proposer")
+	flags.String(flagCommitThreshold, threshold, "fraction of the aggregator set voting power that has to be exceeded by signatures of a block, e.g. 2/3 (empty means only the proposer signature is required)")
+	flags.StringSlice(flagAggregatorKeys, def.AggregatorKeys, "comma-separated list of BLS public keys of aggregators with proofs of possession (<hex key>:<hex proof>), ordered like in the aggregator set (enables aggregated BLS signatures)")
+	flags.Uint64(flagEncryptedDelay, def.EncryptedTxsDelay, "number of blocks between the target height of encrypted transactions and their execution (0 disables encrypted transactions)")
+	flags.Uint64(flagEncryptedWindow, def.EncryptedTxsWindow, "number of blocks up to the target height, in which encrypted transactions can be included (0 means default)")
+	flags.String(flagEncryptedKey, def.EncryptedTxsKey, "hex encoded BLS public key of the decryption committee of encrypted transactions")
+	flags.Float64(flagBanThreshold, def.P2P.BanThreshold, "score of a peer relaying invalid messages, below which the peer is banned (0 disables banning)")
+	flags.Duration(flagBanDuration, def.P2P.BanDuration, "duration of the ban of peers relaying invalid messages")
+	flags.Int(flagMaxInboundPeers, def.P2P.MaxInboundPeers, "maximal number of inbound P2P peers (0 means no limit)")
+	flags.Int(flagMaxOutboundPeers, def.P2P.MaxOutboundPeers, "maximal number of outbound P2P peers (0 means no limit)")
+	flags.Duration(flagPeerGracePeriod, def.P2P.PeerGracePeriod, "duration after connecting, during which P2P connection is not pruned")
+	flags.String(flagQUICListenAddr, def.P2P.QUICListenAddress, "multiaddr to listen for P2P QUIC connections (empty disables QUIC)")
+	flags.String(flagWSListenAddr, def.P2P.WebSocketListenAddress, "multiaddr to listen for P2P WebSocket connections (empty disables WebSocket)")
+	flags.Bool(flagNATPortMap, def.P2P.NATPortMap, "open a port in the NAT device with UPnP or NAT-PMP")
+	flags.Bool(flagNATService, def.P2P.NATService, "serve AutoNAT, helping other peers to determine if they are publicly reachable")
+	flags.Bool(flagHolePunching, def.P2P.HolePunching, "enable hole punching for direct connections with peers behind NAT")
+	flags.Bool(flagRelayService, def.P2P.RelayService, "serve as a circuit relay for peers that are not publicly reachable")
+	flags.String(flagStaticRelays, def.P2P.StaticRelays, "comma separated list of relays used if the node is not publicly reachable (empty disables relay client)")
+	flags.Uint64(flagBlockBandwidth, def.P2P.BlockBandwidth, "bandwidth limit of gossiped blocks in bytes per second (0 means no limit)")
+	flags.Uint64(flagHeaderBandwidth, def.P2P.HeaderBandwidth, "bandwidth limit of gossiped headers in bytes per second (0 means no limit)")
+	flags.Uint64(flagTxBandwidth, def.P2P.TxBandwidth, "bandwidth limit of gossiped transactions in bytes per second (0 means no limit)")
+	flags.Uint64(flagPeerBandwidth, def.P2P.PeerBandwidth, "bandwidth limit of messages gossiped by a single peer in bytes per second (0 means no limit)")
+	flags.Int(flagGossipFanout, def.P2P.GossipFanout, "number of peers in gossipsub mesh of every topic (0 means gossipsub default)")
+	flags.Duration(flagGossipHeartbeat, def.P2P.GossipHeartbeat, "interval of gossipsub heartbeats (0 means gossipsub default)")
+	flags.Int(flagHeaderBatchSize, def.P2P.HeaderBatchSize, "maximal number of headers gossiped by aggregator in a single message (0 or 1 disables batching)")
+	flags.Duration(flagHeaderBatchIntvl, def.P2P.HeaderBatchInterval, "headers produced within this interval after the last gossiped message are batched")
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	cmcfg "github.com/cometbft/cometbft/config"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,9 +24,14 @@ func TestGetNodeConfig(t *testing.T) {
 	}{
 		{"empty", nil, NodeConfig{}},
 		{"Seeds", &cmcfg.Config{P2P: &cmcfg.P2PConfig{Seeds: "seeds"}}, NodeConfig{P2P: P2PConfig{Seeds: "seeds"}}},
+		{"PersistentPeers", &cmcfg.Config{P2P: &cmcfg.P2PConfig{PersistentPeers: "peers"}}, NodeConfig{P2P: P2PConfig{PersistentPeers: "peers"}}},
+		{"PEX", &cmcfg.Config{P2P: &cmcfg.P2PConfig{PexReactor: true}}, NodeConfig{P2P: P2PConfig{PEX: true}}},
 		{"ListenAddress", &cmcfg.Config{P2P: &cmcfg.P2PConfig{ListenAddress: "127.0.0.1:7676"}}, NodeConfig{P2P: P2PConfig{ListenAddress: "127.0.0.1:7676"}}},
 		{"RootDir", &cmcfg.Config{BaseConfig: cmcfg.BaseConfig{RootDir: "~/root"}}, NodeConfig{RootDir: "~/root"}},
 		{"DBPath", &cmcfg.Config{BaseConfig: cmcfg.BaseConfig{DBPath: "./database"}}, NodeConfig{DBPath: "./database"}},
+		{"Instrumentation", &cmcfg.Config{Instrumentation: &cmcfg.InstrumentationConfig{Prometheus: true}}, NodeConfig{Instrumentation: &cmcfg.InstrumentationConfig{Prometheus: true}}},
+		{"Mempool", &cmcfg.Config{Mempool: &cmcfg.MempoolConfig{TTLNumBlocks: 10}}, NodeConfig{Mempool: &cmcfg.MempoolConfig{TTLNumBlocks: 10}}},
+		{"Logging", &cmcfg.Config{BaseConfig: cmcfg.BaseConfig{LogLevel: "p2p:error,*:info", LogFormat: "json"}}, NodeConfig{LogLevel: "p2p:error,*:info", LogFormat: LogFormatJSON}},
 	}
 
 	for _, c := range cases {
@@ -51,6 +57,84 @@ func TestViperAndCobra(t *testing.T) {
 	assert.NoError(cmd.Flags().Set(flagDAConfig, `{"json":true}`))
 	assert.NoError(cmd.Flags().Set(flagBlockTime, "1234s"))
 	assert.NoError(cmd.Flags().Set(flagNamespaceID, "0102030405060708"))
+	assert.NoError(cmd.Flags().Set(flagISRs, "true"))
+	assert.NoError(cmd.Flags().Set(flagTxPreValidation, "true"))
+	assert.NoError(cmd.Flags().Set(flagValidityProofs, "true"))
+	assert.NoError(cmd.Flags().Set(flagHeaderExtensions, "true"))
+	assert.NoError(cmd.Flags().Set(flagDACostFeedback, "true"))
+	assert.NoError(cmd.Flags().Set(flagABCITimeout, "15s"))
+	assert.NoError(cmd.Flags().Set(flagMempoolNonce, "tx.nonce"))
+	assert.NoError(cmd.Flags().Set(flagMempoolRBF, "10"))
+	assert.NoError(cmd.Flags().Set(flagMempoolPerSender, "16"))
+	assert.NoError(cmd.Flags().Set(flagMempoolCacheTTL, "10m"))
+	assert.NoError(cmd.Flags().Set(flagMempoolBatch, "64"))
+	assert.NoError(cmd.Flags().Set(flagMempoolDeny, "mallory,trudy"))
+	assert.NoError(cmd.Flags().Set(flagReadyMaxLag, "5"))
+	assert.NoError(cmd.Flags().Set(flagAdminToken, "secret"))
+	assert.NoError(cmd.Flags().Set(flagRemoteSigner, "127.0.0.1:26659"))
+	assert.NoError(cmd.Flags().Set(flagSignerTimeout, "3s"))
+	assert.NoError(cmd.Flags().Set(flagSignerTLSCert, "signer/client.crt"))
+	assert.NoError(cmd.Flags().Set(flagSignerTLSKey, "signer/client.key"))
+	assert.NoError(cmd.Flags().Set(flagSignerTLSCA, "signer/ca.crt"))
+	assert.NoError(cmd.Flags().Set(flagSequencer, "127.0.0.1:26660"))
+	assert.NoError(cmd.Flags().Set(flagFCFSOrdering, "true"))
+	assert.NoError(cmd.Flags().Set(flagSettlementLayer, "mock"))
+	assert.NoError(cmd.Flags().Set(flagSettlementConfig, "1m"))
+	assert.NoError(cmd.Flags().Set(flagNodeRole, "pruned"))
+	assert.NoError(cmd.Flags().Set(flagRetainBlocks, "500"))
+	assert.NoError(cmd.Flags().Set(flagSyncMode, "headers_first"))
+	assert.NoError(cmd.Flags().Set(flagMaxFutureTime, "30s"))
+	assert.NoError(cmd.Flags().Set(flagNTPServer, "pool.ntp.org"))
+	assert.NoError(cmd.Flags().Set(flagMaxClockDrift, "2s"))
+	assert.NoError(cmd.Flags().Set(flagEventSinks, "http://localhost:8080/events,nats://localhost:4222/blocks"))
+	assert.NoError(cmd.Flags().Set(flagBlockCacheSize, "200"))
+	assert.NoError(cmd.Flags().Set(flagKeyringBackend, "os"))
+	assert.NoError(cmd.Flags().Set(flagKeyringDir, "/var/lib/rollkit/keyring"))
+	assert.NoError(cmd.Flags().Set(flagTracingEndpoint, "localhost:4317"))
+	assert.NoError(cmd.Flags().Set(flagTracingInsecure, "true"))
+	assert.NoError(cmd.Flags().Set(flagTracingSampling, "0.1"))
+	assert.NoError(cmd.Flags().Set(flagInclusionListEv, "true"))
+	assert.NoError(cmd.Flags().Set(flagWithholdWindow, "5m"))
+	assert.NoError(cmd.Flags().Set(flagWithholdHalt, "true"))
+	assert.NoError(cmd.Flags().Set(flagSnapshotInterval, "1000"))
+	assert.NoError(cmd.Flags().Set(flagSnapshotNS, "0102030405060708"))
+	assert.NoError(cmd.Flags().Set(flagSnapshotDAHeight, "1234"))
+	assert.NoError(cmd.Flags().Set(flagStateImportFile, "state.bin"))
+	assert.NoError(cmd.Flags().Set(flagStateExport, "2"))
+	assert.NoError(cmd.Flags().Set(flagSharedNamespace, "true"))
+	assert.NoError(cmd.Flags().Set(flagDAMaxPending, "50"))
+	assert.NoError(cmd.Flags().Set(flagDAReconnect, "30s"))
+	assert.NoError(cmd.Flags().Set(flagTxRatePerIP, "2.5"))
+	assert.NoError(cmd.Flags().Set(flagTxRatePerSender, "1"))
+	assert.NoError(cmd.Flags().Set(flagTxRateBurst, "5"))
+	assert.NoError(cmd.Flags().Set(flagTxMaxBytes, "4096"))
+	assert.NoError(cmd.Flags().Set(flagTxMinFee, "100"))
+	assert.NoError(cmd.Flags().Set(flagTxFeeAttribute, "tx.fee"))
+	assert.NoError(cmd.Flags().Set(flagTxCommitTimeout, "30s"))
+	assert.NoError(cmd.Flags().Set(flagCommitThreshold, "1/2"))
+	assert.NoError(cmd.Flags().Set(flagAggregatorKeys, "aa,bb"))
+	assert.NoError(cmd.Flags().Set(flagEncryptedDelay, "3"))
+	assert.NoError(cmd.Flags().Set(flagEncryptedWindow, "20"))
+	assert.NoError(cmd.Flags().Set(flagEncryptedKey, "aabb"))
+	assert.NoError(cmd.Flags().Set(flagBanThreshold, "-50"))
+	assert.NoError(cmd.Flags().Set(flagBanDuration, "1h"))
+	assert.NoError(cmd.Flags().Set(flagMaxInboundPeers, "20"))
+	assert.NoError(cmd.Flags().Set(flagMaxOutboundPeers, "5"))
+	assert.NoError(cmd.Flags().Set(flagPeerGracePeriod, "30s"))
+	assert.NoError(cmd.Flags().Set(flagQUICListenAddr, ""))
+	assert.NoError(cmd.Flags().Set(flagWSListenAddr, "/ip4/0.0.0.0/tcp/7677/ws"))
+	assert.NoError(cmd.Flags().Set(flagNATService, "true"))
+	assert.NoError(cmd.Flags().Set(flagHolePunching, "false"))
+	assert.NoError(cmd.Flags().Set(flagRelayService, "true"))
+	assert.NoError(cmd.Flags().Set(flagStaticRelays, "/ip4/1.2.3.4/tcp/7676/p2p/relay"))
+	assert.NoError(cmd.Flags().Set(flagBlockBandwidth, "4000000"))
+	assert.NoError(cmd.Flags().Set(flagHeaderBandwidth, "100000"))
+	assert.NoError(cmd.Flags().Set(flagTxBandwidth, "500000"))
+	assert.NoError(cmd.Flags().Set(flagPeerBandwidth, "1000000"))
+	assert.NoError(cmd.Flags().Set(flagGossipFanout, "8"))
+	assert.NoError(cmd.Flags().Set(flagGossipHeartbeat, "700ms"))
+	assert.NoError(cmd.Flags().Set(flagHeaderBatchSize, "10"))
+	assert.NoError(cmd.Flags().Set(flagHeaderBatchIntvl, "200ms"))
 
 	nc := DefaultNodeConfig
 	assert.NoError(nc.GetViperConfig(v))
@@ -58,6 +142,106 @@ func TestViperAndCobra(t *testing.T) {
 	assert.Equal(true, nc.Aggregator)
 	assert.Equal("foobar", nc.DALayer)
 	assert.Equal(`{"json":true}`, nc.DAConfig)
+	assert.True(nc.IntermediateStateRoots)
+	assert.True(nc.TxPreValidation)
+	assert.True(nc.ValidityProofs)
+	assert.True(nc.HeaderExtensions)
+	assert.True(nc.DACostFeedback)
+	assert.Equal(15*time.Second, nc.ABCITimeout)
+	assert.Equal("tx.nonce", nc.MempoolNonce)
+	assert.Equal(uint64(10), nc.MempoolReplaceBump)
+	assert.Equal(16, nc.MempoolMaxTxsPerSender)
+	assert.Equal(10*time.Minute, nc.MempoolCacheTTL)
+	assert.Equal(64, nc.MempoolCheckTxBatch)
+	assert.Empty(nc.MempoolSenderAllowlist)
+	assert.Equal([]string{"mallory", "trudy"}, nc.MempoolSenderDenylist)
+	assert.Equal(uint64(5), nc.ReadyMaxLag)
+	assert.Equal("secret", nc.AdminToken)
+	assert.Equal("127.0.0.1:26659", nc.RemoteSigner)
+	assert.Equal(3*time.Second, nc.SignerTimeout)
+	assert.Equal("signer/client.crt", nc.RemoteSignerTLSCert)
+	assert.Equal("signer/client.key", nc.RemoteSignerTLSKey)
+	assert.Equal("signer/ca.crt", nc.RemoteSignerTLSCA)
+	assert.Equal("127.0.0.1:26660", nc.SequencerAddress)
+	assert.True(nc.FCFSOrdering)
+	assert.Equal("mock", nc.SettlementLayer)
+	assert.Equal("1m", nc.SettlementConfig)
+	assert.Equal(NodeRolePruned, nc.NodeRole)
+	assert.Equal(uint64(500), nc.RetainBlocks)
+	assert.Equal(SyncModeHeadersFirst, nc.SyncMode)
+	assert.Equal(30*time.Second, nc.MaxFutureTime)
+	assert.Equal("pool.ntp.org", nc.NTPServer)
+	assert.Equal(2*time.Second, nc.MaxClockDrift)
+	assert.Equal([]string{"http://localhost:8080/events", "nats://localhost:4222/blocks"}, nc.EventSinks)
+	assert.Equal(uint64(200), nc.BlockCacheSize)
+	assert.Equal("os", nc.KeyringBackend)
+	assert.Equal("/var/lib/rollkit/keyring", nc.KeyringDir)
+	assert.Equal("localhost:4317", nc.TracingEndpoint)
+	assert.True(nc.TracingInsecure)
+	assert.Equal(0.1, nc.TracingSampleRate)
+	assert.True(nc.InclusionListEvidence)
+	assert.Equal(5*time.Minute, nc.WithholdingWindow)
+	assert.True(nc.WithholdingHalt)
+	assert.Equal(uint64(1000), nc.SnapshotInterval)
+	assert.Equal(types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8}, nc.SnapshotNamespaceID)
+	assert.Equal(uint64(1234), nc.SnapshotDAHeight)
+	assert.Equal("state.bin", nc.StateImportFile)
+	assert.Equal(uint64(2), nc.StateExportMaxConcurrent)
+	assert.True(nc.SharedNamespace)
+	assert.Equal(uint64(50), nc.DAMaxPendingBlocks)
+	assert.Equal(30*time.Second, nc.DAReconnectInterval)
+	assert.Equal(2.5, nc.TxRatePerIP)
+	assert.Equal(1.0, nc.TxRatePerSender)
+	assert.Equal(5, nc.TxRateBurst)
+	assert.Equal(4096, nc.TxMaxBytes)
+	assert.Equal(uint64(100), nc.TxMinFee)
+	assert.Equal("tx.fee", nc.TxFeeAttribute)
+	assert.Equal(30*time.Second, nc.TxCommitTimeout)
+	assert.Equal(cmtmath.Fraction{Numerator: 1, Denominator: 2}, nc.CommitThreshold)
+	assert.Equal([]string{"aa", "bb"}, nc.AggregatorKeys)
+	assert.Equal(uint64(3), nc.EncryptedTxsDelay)
+	assert.Equal(uint64(20), nc.EncryptedTxsWindow)
+	assert.Equal("aabb", nc.EncryptedTxsKey)
+	assert.Equal(-50.0, nc.P2P.BanThreshold)
+	assert.Equal(time.Hour, nc.P2P.BanDuration)
+	assert.Equal(20, nc.P2P.MaxInboundPeers)
+	assert.Equal(5, nc.P2P.MaxOutboundPeers)
+	assert.Equal(30*time.Second, nc.P2P.PeerGracePeriod)
+	assert.Empty(nc.P2P.QUICListenAddress)
+	assert.Equal("/ip4/0.0.0.0/tcp/7677/ws", nc.P2P.WebSocketListenAddress)
+	assert.False(nc.P2P.NATPortMap)
+	assert.True(nc.P2P.NATService)
+	assert.False(nc.P2P.HolePunching)
+	assert.True(nc.P2P.RelayService)
+	assert.Equal("/ip4/1.2.3.4/tcp/7676/p2p/relay", nc.P2P.StaticRelays)
+	assert.Equal(uint64(4000000), nc.P2P.BlockBandwidth)
+	assert.Equal(uint64(100000), nc.P2P.HeaderBandwidth)
+	assert.Equal(uint64(500000), nc.P2P.TxBandwidth)
+	assert.Equal(uint64(1000000), nc.P2P.PeerBandwidth)
+	assert.Equal(8, nc.P2P.GossipFanout)
+	assert.Equal(700*time.Millisecond, nc.P2P.GossipHeartbeat)
+	assert.Equal(10, nc.P2P.HeaderBatchSize)
+	assert.Equal(200*time.Millisecond, nc.P2P.HeaderBatchInterval)
 	assert.Equal(1234*time.Second, nc.BlockTime)
 	assert.Equal(types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8}, nc.NamespaceID)
 }
+
+func TestCommitThresholdFlagDefault(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// default value of the flag has to be accepted by the parser
+	for _, def := range []cmtmath.Fraction{{}, types.DefaultCommitThreshold} {
+		conf := DefaultNodeConfig
+		conf.CommitThreshold = def
+		cmd := &cobra.Command{}
+		addFlags(cmd.Flags(), conf)
+		v := viper.New()
+		assert.NoError(v.BindPFlags(cmd.Flags()))
+
+		nc := DefaultNodeConfig
+		assert.NoError(nc.GetViperConfig(v))
+		assert.Equal(def, nc.CommitThreshold)
+	}
+	assert.Zero(DefaultNodeConfig.CommitThreshold)
+}
@@ -9,6 +9,8 @@ import (
 const (
 	// DefaultListenAddress is a default listen address for P2P client.
 	DefaultListenAddress = "/ip4/0.0.0.0/tcp/7676"
+	// DefaultQUICListenAddress is a default listen address for QUIC transport of P2P client.
+	DefaultQUICListenAddress = "/ip4/0.0.0.0/udp/7676/quic-v1"
 	// Version is the current rollkit version and is used for checking RPC compatibility??
 	Version = "0.4.0"
 )
@@ -16,15 +18,28 @@ const (
 // DefaultNodeConfig keeps default values of NodeConfig
 var DefaultNodeConfig = NodeConfig{
 	P2P: P2PConfig{
-		ListenAddress: DefaultListenAddress,
-		Seeds:         "",
+		ListenAddress:     DefaultListenAddress,
+		QUICListenAddress: DefaultQUICListenAddress,
+		Seeds:             "",
+		PEX:               true,
+		HolePunching:      true,
+		MaxInboundPeers:   40,
+		MaxOutboundPeers:  10,
+		PeerGracePeriod:   1 * time.Minute,
+		BanThreshold:      -100,
+		BanDuration:       10 * time.Minute,
 	},
 	Aggregator:     false,
 	LazyAggregator: false,
 	BlockManagerConfig: BlockManagerConfig{
-		BlockTime:   1 * time.Second,
-		DABlockTime: 15 * time.Second,
-		NamespaceID: types.NamespaceID{},
+		BlockTime:           1 * time.Second,
+		DABlockTime:         15 * time.Second,
+		NamespaceID:         types.NamespaceID{},
+		ABCITimeout:         1 * time.Minute,
+		SignerTimeout:       5 * time.Second,
+		MaxFutureTime:       10 * time.Second,
+		DAMaxPendingBlocks:  1000,
+		DAReconnectInterval: 1 * time.Minute,
 	},
 	DALayer:  "newda",
 	DAConfig: "",
@@ -32,4 +47,13 @@ var DefaultNodeConfig = NodeConfig{
 	HeaderConfig: HeaderConfig{
 		TrustedHash: "",
 	},
+	TxCommitTimeout:   10 * time.Second,
+	ReadyMaxLag:       3,
+	NodeRole:          NodeRoleArchival,
+	RetainBlocks:      1000,
+	SyncMode:          SyncModeBlocks,
+	MaxClockDrift:     1 * time.Second,
+	BlockCacheSize:    100,
+	KeyringDir:        "keyring",
+	TracingSampleRate: 1,
 }
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// keyPrefix is the prefix of keys of all Rollkit specific options.
+	keyPrefix = "rollkit."
+	// EnvPrefix is the prefix of environment variables overriding Rollkit specific options, e.g. ROLLKIT_BLOCK_TIME
+	// overrides rollkit.block_time.
+	EnvPrefix = "ROLLKIT"
+)
+
+// sections of the generated configuration file, in order of appearance.
+var sections = []string{"Node", "Data Availability", "P2P", "Mempool", "Store", "RPC"}
+
+// sectionOf returns the section of the configuration file the option belongs to.
+func sectionOf(key string) string {
+	name := strings.TrimPrefix(key, keyPrefix)
+	switch {
+	case strings.HasPrefix(name, "da_"), name == "namespace_id":
+		return "Data Availability"
+	case strings.HasPrefix(name, "p2p_"):
+		return "P2P"
+	case strings.HasPrefix(name, "mempool_"):
+		return "Mempool"
+	case name == "node_role", name == "retain_blocks":
+		return "Store"
+	case name == "ready_max_lag", name == "admin_token":
+		return "RPC"
+	default:
+		return "Node"
+	}
+}
+
+// BindEnv makes Viper instance read Rollkit specific options from environment variables: option rollkit.<name>
+// is overridden by variable ROLLKIT_<NAME>. Lists are separated by spaces in environment variables.
+func BindEnv(v *viper.Viper) error {
+	for key := range knownKeys() {
+		if err := v.BindEnv(key, envName(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.TrimPrefix(key, keyPrefix))
+}
+
+// GenerateConfig returns TOML configuration file with all Rollkit specific options set to values from
+// the configuration, grouped into sections and documented. It's used to initialize configuration of a node.
+func GenerateConfig(nc NodeConfig) ([]byte, error) {
+	flags := pflag.NewFlagSet("rollkit", pflag.ContinueOnError)
+	addFlags(flags, nc)
+
+	bySection := make(map[string][]*pflag.Flag)
+	for _, f := range flagList(flags) {
+		section := sectionOf(f.Name)
+		bySection[section] = append(bySection[section], f)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Rollkit configuration.\n")
+	fmt.Fprintf(&buf, "# Options can be overridden with environment variables, e.g. %s overrides block_time.\n\n", envName(flagBlockTime))
+	fmt.Fprintf(&buf, "[rollkit]\n")
+	for _, section := range sections {
+		fmt.Fprintf(&buf, "\n##### %s #####\n", section)
+		for _, f := range bySection[section] {
+			value, err := tomlValue(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", f.Name, err)
+			}
+			fmt.Fprintf(&buf, "\n# %s\n%s = %s\n", f.Usage, strings.TrimPrefix(f.Name, keyPrefix), value)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteConfigFile writes configuration file generated by GenerateConfig. Existing file is not overwritten.
+func WriteConfigFile(path string, nc NodeConfig) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("configuration file %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	data, err := GenerateConfig(nc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Load reads Rollkit specific options from the configuration file (if path is not empty) and environment variables,
+// over the defaults. Unknown options are rejected, and the resulting configuration is validated.
+func Load(path string) (NodeConfig, error) {
+	v := viper.New()
+	flags := pflag.NewFlagSet("rollkit", pflag.ContinueOnError)
+	addFlags(flags, DefaultNodeConfig)
+	if err := v.BindPFlags(flags); err != nil {
+		return NodeConfig{}, err
+	}
+	if err := BindEnv(v); err != nil {
+		return NodeConfig{}, err
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return NodeConfig{}, fmt.Errorf("failed to read configuration file: %w", err)
+		}
+	}
+	if err := CheckUnknownKeys(v); err != nil {
+		return NodeConfig{}, err
+	}
+	nc := DefaultNodeConfig
+	if err := nc.GetViperConfig(v); err != nil {
+		return NodeConfig{}, err
+	}
+	return nc, nc.Validate()
+}
+
+// flagList returns all flags of the set, sorted by name.
+func flagList(flags *pflag.FlagSet) []*pflag.Flag {
+	var list []*pflag.Flag
+	flags.VisitAll(func(f *pflag.Flag) {
+		list = append(list, f)
+	})
+	return list
+}
+
+// tomlValue encodes value of the flag as TOML value.
+func tomlValue(f *pflag.Flag) (string, error) {
+	switch f.Value.Type() {
+	case "bool", "int", "uint64", "float64":
+		return f.Value.String(), nil
+	case "stringSlice":
+		slice, ok := f.Value.(pflag.SliceValue)
+		if !ok {
+			return "", fmt.Errorf("unexpected value of type %s", f.Value.Type())
+		}
+		quoted := make([]string, len(slice.GetSlice()))
+		for i, s := range slice.GetSlice() {
+			quoted[i] = strconv.Quote(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]", nil
+	default:
+		return strconv.Quote(f.Value.String()), nil
+	}
+}
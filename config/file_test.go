@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestGenerateAndLoad(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	nc := DefaultNodeConfig
+	nc.Aggregator = true
+	nc.BlockTime = 3 * time.Second
+	nc.NamespaceID = types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8}
+	nc.MempoolSenderDenylist = []string{"alice", "bob"}
+	nc.P2P.MaxInboundPeers = 7
+	nc.NodeRole = NodeRolePruned
+
+	path := filepath.Join(t.TempDir(), "rollkit.toml")
+	require.NoError(WriteConfigFile(path, nc))
+	assert.Error(WriteConfigFile(path, nc), "existing file is not overwritten")
+
+	data, err := os.ReadFile(path)
+	require.NoError(err)
+	for _, section := range sections {
+		assert.Contains(string(data), "##### "+section+" #####")
+	}
+
+	loaded, err := Load(path)
+	require.NoError(err)
+	assert.True(loaded.Aggregator)
+	assert.Equal(nc.BlockTime, loaded.BlockTime)
+	assert.Equal(nc.DABlockTime, loaded.DABlockTime)
+	assert.Equal(nc.NamespaceID, loaded.NamespaceID)
+	assert.Equal(nc.CommitThreshold, loaded.CommitThreshold)
+	assert.Equal(nc.MempoolSenderDenylist, loaded.MempoolSenderDenylist)
+	assert.Equal(nc.P2P.MaxInboundPeers, loaded.P2P.MaxInboundPeers)
+	assert.Equal(nc.P2P.BanDuration, loaded.P2P.BanDuration)
+	assert.Equal(nc.NodeRole, loaded.NodeRole)
+	assert.Equal(nc.RetainBlocks, loaded.RetainBlocks)
+	assert.Equal(nc.DALayer, loaded.DALayer)
+
+	// environment variables override the file
+	t.Setenv("ROLLKIT_BLOCK_TIME", "5s")
+	t.Setenv("ROLLKIT_P2P_MAX_INBOUND_PEERS", "3")
+	loaded, err = Load(path)
+	require.NoError(err)
+	assert.Equal(5*time.Second, loaded.BlockTime)
+	assert.Equal(3, loaded.P2P.MaxInboundPeers)
+
+	// defaults are used without the file
+	t.Setenv("ROLLKIT_BLOCK_TIME", "")
+	loaded, err = Load("")
+	require.NoError(err)
+	assert.Equal(DefaultNodeConfig.BlockTime, loaded.BlockTime)
+
+	require.NoError(os.WriteFile(path, []byte("[rollkit]\nblok_time = \"1s\"\n"), 0o600))
+	_, err = Load(path)
+	assert.ErrorIs(err, ErrUnknownKey)
+
+	require.NoError(os.WriteFile(path, []byte("[rollkit]\nlight = true\naggregator = true\n"), 0o600))
+	_, err = Load(path)
+	assert.ErrorIs(err, ErrInvalidConfig)
+}
@@ -1,9 +1,71 @@
 package config
 
+import "time"
+
 // P2PConfig stores configuration related to peer-to-peer networking.
 type P2PConfig struct {
 	ListenAddress string // Address to listen for incoming connections
 	Seeds         string // Comma separated list of seed nodes to connect to
 	BlockedPeers  string // Comma separated list of nodes to ignore
 	AllowedPeers  string // Comma separated list of nodes to whitelist
+
+	// QUICListenAddress is the multiaddr to listen for QUIC connections, e.g. /ip4/0.0.0.0/udp/7676/quic-v1.
+	// Empty disables QUIC transport.
+	QUICListenAddress string
+	// WebSocketListenAddress is the multiaddr to listen for WebSocket connections (used by browsers),
+	// e.g. /ip4/0.0.0.0/tcp/7677/ws. Empty disables WebSocket transport.
+	WebSocketListenAddress string
+
+	// PersistentPeers is a comma separated list of nodes to keep connection with. Connections are
+	// re-established (with backoff) when lost.
+	PersistentPeers string
+	// NATPortMap enables opening a port in the NAT device (with UPnP or NAT-PMP).
+	NATPortMap bool
+	// NATService enables AutoNAT service, helping other peers to determine if they are reachable.
+	NATService bool
+	// HolePunching enables direct connections with peers behind NAT, coordinated over relayed connections.
+	HolePunching bool
+	// RelayService enables serving as a circuit relay for peers that are not publicly reachable.
+	RelayService bool
+	// StaticRelays is a comma separated list of relays used by the node if it's not publicly reachable.
+	// Empty disables the relay client (AutoRelay).
+	StaticRelays string
+
+	// PEX enables peer exchange - requesting addresses of new peers from connected peers.
+	PEX bool
+
+	// MaxInboundPeers limits the number of peers that connected to the node. Zero means no limit.
+	MaxInboundPeers int
+	// MaxOutboundPeers limits the number of peers the node connected to. Zero means no limit.
+	MaxOutboundPeers int
+	// PeerGracePeriod is the duration after connecting, during which connection is not pruned when
+	// the number of peers exceeds the limits.
+	PeerGracePeriod time.Duration
+
+	// BanThreshold is the (negative) score of a peer relaying invalid messages, below which peer is
+	// disconnected and banned. Zero disables banning.
+	BanThreshold float64
+	// BanDuration is the duration of the ban of peers with score below BanThreshold.
+	BanDuration time.Duration
+
+	// BlockBandwidth, HeaderBandwidth and TxBandwidth limit bandwidth (in bytes per second) of gossiped blocks,
+	// headers and transactions. Messages above the limits are dropped. Zero means no limit.
+	BlockBandwidth  uint64
+	HeaderBandwidth uint64
+	TxBandwidth     uint64
+	// PeerBandwidth limits bandwidth (in bytes per second) of messages gossiped by a single peer, in all topics.
+	// Zero means no limit.
+	PeerBandwidth uint64
+
+	// GossipFanout is the number of peers in the gossipsub mesh of every topic, messages are forwarded to
+	// (gossipsub D parameter). Zero means the gossipsub default.
+	GossipFanout int
+	// GossipHeartbeat is the interval of gossipsub heartbeats, maintaining the meshes and announcing recent
+	// messages to peers outside of the mesh. Zero means the gossipsub default.
+	GossipHeartbeat time.Duration
+	// HeaderBatchSize is the maximal number of headers published by the aggregator in a single gossip message.
+	// Headers produced within HeaderBatchInterval after the last message are batched. Zero or one disables
+	// batching.
+	HeaderBatchSize     int
+	HeaderBatchInterval time.Duration
 }
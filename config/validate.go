@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/keyring"
+	"github.com/rollkit/rollkit/types"
+)
+
+var (
+	// ErrInvalidConfig is returned when configuration options are invalid or conflicting.
+	ErrInvalidConfig = errors.New("invalid configuration")
+	// ErrUnknownKey is returned when configuration contains a Rollkit specific key that is not a known option,
+	// e.g. a misspelled one.
+	ErrUnknownKey = errors.New("unknown configuration key")
+)
+
+// Validate checks that configuration options are valid and don't conflict with each other. Zero values of options
+// are valid, and mean defaults (or disabled features). All problems are reported in the returned error.
+func (nc *NodeConfig) Validate() error {
+	var err error
+	invalid := func(format string, args ...interface{}) {
+		err = multierr.Append(err, fmt.Errorf("%w: %s", ErrInvalidConfig, fmt.Sprintf(format, args...)))
+	}
+
+	// node
+	if nc.Aggregator && nc.Light {
+		invalid("light client can't be an aggregator")
+	}
+	if nc.LazyAggregator && !nc.Aggregator {
+		invalid("lazy aggregator requires aggregator mode")
+	}
+	if nc.BlockTime < 0 {
+		invalid("negative block time: %s", nc.BlockTime)
+	}
+	if nc.ABCITimeout < 0 || nc.SignerTimeout < 0 {
+		invalid("negative timeout")
+	}
+	if nc.RemoteSigner != "" && !nc.Aggregator {
+		invalid("remote signer requires aggregator mode")
+	}
+	if nc.RemoteSigner != "" && (nc.RemoteSignerTLSCert == "" || nc.RemoteSignerTLSKey == "" || nc.RemoteSignerTLSCA == "") {
+		invalid("remote signer requires TLS certificate, key and CA files")
+	}
+	if nc.RemoteSigner == "" && (nc.RemoteSignerTLSCert != "" || nc.RemoteSignerTLSKey != "" || nc.RemoteSignerTLSCA != "") {
+		invalid("remote signer TLS files require remote signer")
+	}
+	if nc.RemoteSigner != "" && len(nc.AggregatorKeys) > 0 {
+		invalid("remote signer doesn't make BLS signatures required by aggregator keys")
+	}
+	if nc.CommitThreshold.Denominator != 0 && nc.CommitThreshold.Numerator >= nc.CommitThreshold.Denominator {
+		invalid("commit threshold %s is not lower than 1", nc.CommitThreshold)
+	}
+	for _, entry := range nc.AggregatorKeys {
+		key, proof, found := strings.Cut(entry, ":")
+		_, keyErr := hex.DecodeString(key)
+		_, proofErr := hex.DecodeString(proof)
+		if !found || keyErr != nil || proofErr != nil {
+			invalid("aggregator key %q is not a hex encoded key and proof of possession", entry)
+		}
+	}
+	if nc.MaxFutureTime < 0 || nc.MaxClockDrift < 0 {
+		invalid("negative clock bound")
+	}
+	if nc.WithholdingWindow < 0 {
+		invalid("negative withholding window: %s", nc.WithholdingWindow)
+	}
+	if nc.WithholdingHalt && nc.WithholdingWindow == 0 {
+		invalid("halting on data withholding requires withholding window")
+	}
+	if (nc.SnapshotInterval > 0 || nc.SnapshotDAHeight > 0) && !nc.SharedNamespace && nc.SnapshotNamespaceID == nc.NamespaceID {
+		invalid("snapshots require namespace different from the namespace of blocks, or shared namespace")
+	}
+	if nc.StateImportFile != "" && nc.SnapshotDAHeight > 0 {
+		invalid("state import file and snapshot DA height are mutually exclusive")
+	}
+	if nc.SnapshotInterval > 0 && !nc.Aggregator {
+		invalid("publishing snapshots requires aggregator mode")
+	}
+	if nc.DACostFeedback && !nc.Aggregator {
+		invalid("DA cost feedback requires aggregator mode")
+	}
+	if (nc.EncryptedTxsWindow > 0 || nc.EncryptedTxsKey != "") && nc.EncryptedTxsDelay == 0 {
+		invalid("encrypted transactions window and key require encrypted transactions delay")
+	}
+	if nc.EncryptedTxsDelay > 0 {
+		if _, err := hex.DecodeString(nc.EncryptedTxsKey); err != nil || nc.EncryptedTxsKey == "" {
+			invalid("encrypted transactions require hex encoded key of the decryption committee")
+		}
+	}
+
+	switch nc.KeyringBackend {
+	case "", keyring.BackendFile, keyring.BackendOS:
+	default:
+		invalid("unsupported keyring backend: %s", nc.KeyringBackend)
+	}
+	if nc.TracingEndpoint != "" {
+		if _, _, splitErr := net.SplitHostPort(nc.TracingEndpoint); splitErr != nil {
+			invalid("tracing endpoint %q is not host:port", nc.TracingEndpoint)
+		}
+	}
+	if nc.TracingSampleRate < 0 || nc.TracingSampleRate > 1 {
+		invalid("tracing sample rate has to be between 0 and 1: %v", nc.TracingSampleRate)
+	}
+	switch nc.LogFormat {
+	case "", LogFormatPlain, LogFormatJSON:
+	default:
+		invalid("unknown log format: %s", nc.LogFormat)
+	}
+
+	// DA
+	if nc.DABlockTime < 0 {
+		invalid("negative DA block time: %s", nc.DABlockTime)
+	}
+	if nc.DAReconnectInterval < 0 {
+		invalid("negative DA reconnect interval: %s", nc.DAReconnectInterval)
+	}
+
+	// store
+	switch nc.NodeRole {
+	case "", NodeRoleArchival:
+	case NodeRolePruned:
+		if nc.RetainBlocks == 0 {
+			invalid("pruned node has to retain at least one block")
+		}
+	default:
+		invalid("unknown node role: %s", nc.NodeRole)
+	}
+
+	// sync
+	switch nc.SyncMode {
+	case "", SyncModeBlocks:
+	case SyncModeHeadersFirst:
+		if nc.Aggregator || nc.Light {
+			invalid("headers-first sync is supported only by full nodes")
+		}
+	default:
+		invalid("unknown sync mode: %s", nc.SyncMode)
+	}
+
+	// mempool
+	if nc.MempoolMaxTxsPerSender < 0 || nc.MempoolCheckTxBatch < 0 || nc.MempoolCacheTTL < 0 {
+		invalid("negative mempool limit")
+	}
+	if nc.MempoolMaxTxsPerSender > 0 && nc.MempoolNonce == "" {
+		invalid("mempool limit of transactions per sender requires nonce ordering")
+	}
+	if nc.TxRatePerIP < 0 || nc.TxRatePerSender < 0 || nc.TxRateBurst < 0 || nc.TxMaxBytes < 0 ||
+		nc.TxCommitTimeout < 0 {
+		invalid("negative transaction ingress limit")
+	}
+	if nc.TxMinFee > 0 && nc.TxFeeAttribute == "" {
+		invalid("minimal transaction fee requires fee attribute")
+	}
+	denied := make(map[string]bool, len(nc.MempoolSenderDenylist))
+	for _, sender := range nc.MempoolSenderDenylist {
+		denied[sender] = true
+	}
+	for _, sender := range nc.MempoolSenderAllowlist {
+		if denied[sender] {
+			invalid("sender %s is both allowed and denied", sender)
+		}
+	}
+	if nc.Mempool != nil {
+		if mErr := nc.Mempool.ValidateBasic(); mErr != nil {
+			invalid("mempool: %s", mErr)
+		}
+	}
+
+	// P2P
+	if nc.P2P.MaxInboundPeers < 0 || nc.P2P.MaxOutboundPeers < 0 {
+		invalid("negative P2P peer limit")
+	}
+	if nc.P2P.BanThreshold > 0 {
+		invalid("P2P ban threshold has to be negative: %v", nc.P2P.BanThreshold)
+	}
+	if nc.P2P.PeerGracePeriod < 0 || nc.P2P.BanDuration < 0 || nc.P2P.GossipHeartbeat < 0 || nc.P2P.HeaderBatchInterval < 0 {
+		invalid("negative P2P duration")
+	}
+	if nc.P2P.GossipFanout < 0 {
+		invalid("negative gossip fanout")
+	}
+	if nc.P2P.HeaderBatchSize < 0 || nc.P2P.HeaderBatchSize > types.MaxHeaderBatchHeaders {
+		invalid("header batch size has to be between 0 and %d: %d", types.MaxHeaderBatchHeaders, nc.P2P.HeaderBatchSize)
+	}
+
+	// RPC
+	if (nc.RPC.TLSCertFile == "") != (nc.RPC.TLSKeyFile == "") {
+		invalid("both TLS certificate and key files are required for HTTPS")
+	}
+	if nc.RPC.MaxOpenConnections < 0 {
+		invalid("negative RPC connection limit")
+	}
+	return err
+}
+
+// CheckUnknownKeys returns error if Viper instance contains a Rollkit specific key (prefixed with "rollkit.")
+// that is not a known option.
+func CheckUnknownKeys(v *viper.Viper) error {
+	known := knownKeys()
+	var err error
+	for _, key := range v.AllKeys() {
+		if strings.HasPrefix(key, keyPrefix) && !known[key] {
+			err = multierr.Append(err, fmt.Errorf("%w: %s", ErrUnknownKey, key))
+		}
+	}
+	return err
+}
+
+// knownKeys returns names of all Rollkit specific options.
+func knownKeys() map[string]bool {
+	flags := pflag.NewFlagSet("rollkit", pflag.ContinueOnError)
+	addFlags(flags, DefaultNodeConfig)
+	keys := make(map[string]bool)
+	for _, f := range flagList(flags) {
+		keys[f.Name] = true
+	}
+	return keys
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rollkit/rollkit/keyring"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	def := DefaultNodeConfig
+	assert.NoError(t, def.Validate())
+	assert.NoError(t, (&NodeConfig{}).Validate())
+	shared := DefaultNodeConfig
+	shared.SnapshotDAHeight, shared.SharedNamespace = 10, true
+	assert.NoError(t, shared.Validate())
+
+	cases := []struct {
+		name   string
+		modify func(nc *NodeConfig)
+	}{
+		{"light aggregator", func(nc *NodeConfig) { nc.Aggregator, nc.Light = true, true }},
+		{"lazy full node", func(nc *NodeConfig) { nc.LazyAggregator = true }},
+		{"negative block time", func(nc *NodeConfig) { nc.BlockTime = -time.Second }},
+		{"remote signer without TLS", func(nc *NodeConfig) { nc.Aggregator, nc.RemoteSigner = true, "127.0.0.1:26659" }},
+		{"TLS without remote signer", func(nc *NodeConfig) { nc.RemoteSignerTLSCA = "ca.pem" }},
+		{"remote signer with aggregator keys", func(nc *NodeConfig) {
+			nc.Aggregator, nc.RemoteSigner = true, "127.0.0.1:26659"
+			nc.RemoteSignerTLSCert, nc.RemoteSignerTLSKey, nc.RemoteSignerTLSCA = "cert.pem", "key.pem", "ca.pem"
+			nc.AggregatorKeys = []string{"aabb:ccdd"}
+		}},
+		{"log format", func(nc *NodeConfig) { nc.LogFormat = "xml" }},
+		{"keyring backend", func(nc *NodeConfig) { nc.KeyringBackend = keyring.BackendTest }},
+		{"tracing endpoint", func(nc *NodeConfig) { nc.TracingEndpoint = "localhost" }},
+		{"tracing sample rate", func(nc *NodeConfig) { nc.TracingEndpoint, nc.TracingSampleRate = "localhost:4317", 1.5 }},
+		{"negative DA block time", func(nc *NodeConfig) { nc.DABlockTime = -time.Second }},
+		{"negative DA reconnect interval", func(nc *NodeConfig) { nc.DAReconnectInterval = -time.Second }},
+		{"negative tx rate", func(nc *NodeConfig) { nc.TxRatePerIP = -1 }},
+		{"negative tx commit timeout", func(nc *NodeConfig) { nc.TxCommitTimeout = -time.Second }},
+		{"min fee without attribute", func(nc *NodeConfig) { nc.TxMinFee = 100 }},
+		{"commit threshold", func(nc *NodeConfig) { nc.CommitThreshold = cmtmath.Fraction{Numerator: 3, Denominator: 2} }},
+		{"aggregator key", func(nc *NodeConfig) { nc.AggregatorKeys = []string{"xyz"} }},
+		{"aggregator key without proof", func(nc *NodeConfig) { nc.AggregatorKeys = []string{"aabb"} }},
+		{"negative max future time", func(nc *NodeConfig) { nc.MaxFutureTime = -time.Second }},
+		{"withholding halt without window", func(nc *NodeConfig) { nc.WithholdingHalt = true }},
+		{"snapshots without namespace", func(nc *NodeConfig) { nc.SnapshotDAHeight = 10 }},
+		{"state import with snapshot", func(nc *NodeConfig) {
+			nc.StateImportFile, nc.SnapshotDAHeight, nc.SharedNamespace = "state.bin", 10, true
+		}},
+		{"snapshots by full node", func(nc *NodeConfig) {
+			nc.SnapshotInterval, nc.SnapshotNamespaceID = 100, types.NamespaceID{1}
+		}},
+		{"DA cost feedback by full node", func(nc *NodeConfig) { nc.DACostFeedback = true }},
+		{"encrypted window", func(nc *NodeConfig) { nc.EncryptedTxsWindow = 10 }},
+		{"encrypted without key", func(nc *NodeConfig) { nc.EncryptedTxsDelay = 2 }},
+		{"node role", func(nc *NodeConfig) { nc.NodeRole = "unknown" }},
+		{"pruned without blocks", func(nc *NodeConfig) { nc.NodeRole, nc.RetainBlocks = NodeRolePruned, 0 }},
+		{"sync mode", func(nc *NodeConfig) { nc.SyncMode = "unknown" }},
+		{"headers-first aggregator", func(nc *NodeConfig) { nc.Aggregator, nc.SyncMode = true, SyncModeHeadersFirst }},
+		{"sender limit without nonce", func(nc *NodeConfig) { nc.MempoolMaxTxsPerSender = 10 }},
+		{"allowed and denied", func(nc *NodeConfig) {
+			nc.MempoolSenderAllowlist, nc.MempoolSenderDenylist = []string{"a", "b"}, []string{"b"}
+		}},
+		{"ban threshold", func(nc *NodeConfig) { nc.P2P.BanThreshold = 1 }},
+		{"gossip fanout", func(nc *NodeConfig) { nc.P2P.GossipFanout = -1 }},
+		{"header batch size", func(nc *NodeConfig) { nc.P2P.HeaderBatchSize = types.MaxHeaderBatchHeaders + 1 }},
+		{"TLS", func(nc *NodeConfig) { nc.RPC.TLSCertFile = "cert.pem" }},
+	}
+	for _, c := range cases {
+		nc := DefaultNodeConfig
+		c.modify(&nc)
+		assert.ErrorIs(t, nc.Validate(), ErrInvalidConfig, c.name)
+	}
+}
+
+func TestCheckUnknownKeys(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	v := viper.New()
+	v.Set(flagBlockTime, "1s")
+	v.Set("p2p.laddr", "tcp://0.0.0.0:26656")
+	assert.NoError(CheckUnknownKeys(v))
+
+	v.Set("rollkit.blok_time", "1s")
+	assert.ErrorIs(CheckUnknownKeys(v), ErrUnknownKey)
+}
@@ -0,0 +1,240 @@
+// Package bls implements BLS signatures over the BLS12-381 pairing-friendly curve, following the proof of
+// possession scheme of the IETF draft (draft-irtf-cfrg-bls-signature-05), with the minimal-signature-size
+// ciphersuite BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_. Curve arithmetic, hashing to the curve and pairings
+// are provided by github.com/cloudflare/circl/ecc/bls12381.
+//
+// Signatures are points of G1 and public keys are points of G2, both in compressed encoding, so signatures are
+// short. Signatures of the same message made by different keys can be aggregated into a single signature,
+// verifiable against the aggregated public key of the signers.
+//
+// Aggregation of signatures of the same message is prone to rogue-key attacks, so every public key has to be
+// accompanied by a proof of possession of the private key (see PrivKey.ProvePossession), verified before the key
+// is used for verification of aggregated signatures.
+package bls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	bls12381 "github.com/cloudflare/circl/ecc/bls12381"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// PrivKeySize is the size of a private key in bytes.
+	PrivKeySize = bls12381.ScalarSize
+	// PubKeySize is the size of a public key (compressed G2 point) in bytes.
+	PubKeySize = bls12381.G2SizeCompressed
+	// SignatureSize is the size of a signature (compressed G1 point) in bytes.
+	SignatureSize = bls12381.G1SizeCompressed
+	// MinSeedSize is the minimal size of a seed of a private key.
+	MinSeedSize = 32
+
+	// sigDST is the domain separation tag of signatures of messages.
+	sigDST = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	// popDST is the domain separation tag of proofs of possession.
+	popDST = "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	// keyGenSalt is the initial salt of KeyGen.
+	keyGenSalt = "BLS-SIG-KEYGEN-SALT-"
+)
+
+var (
+	// ErrInvalidPubKey is returned when public key is not a valid point of G2.
+	ErrInvalidPubKey = errors.New("invalid BLS public key")
+	// ErrInvalidSignature is returned when signature is not a valid point of G1.
+	ErrInvalidSignature = errors.New("invalid BLS signature")
+	// ErrInvalidProof is returned when proof of possession of a public key is invalid.
+	ErrInvalidProof = errors.New("invalid BLS proof of possession")
+	// ErrShortSeed is returned when seed of a private key is shorter than MinSeedSize.
+	ErrShortSeed = errors.New("BLS key seed too short")
+	// ErrNothingToAggregate is returned when aggregating an empty list of signatures or public keys.
+	ErrNothingToAggregate = errors.New("nothing to aggregate")
+)
+
+// PrivKey is a BLS private key, a big-endian encoded scalar.
+type PrivKey []byte
+
+// PubKey is a BLS public key, a compressed point of G2.
+type PubKey []byte
+
+// GenPrivKey generates a new random private key.
+func GenPrivKey() (PrivKey, error) {
+	seed := make([]byte, MinSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return PrivKeyFromSeed(seed)
+}
+
+// PrivKeyFromSeed deterministically derives a private key from the seed (at least MinSeedSize bytes of secret
+// key material), using KeyGen of the IETF draft.
+func PrivKeyFromSeed(seed []byte) (PrivKey, error) {
+	if len(seed) < MinSeedSize {
+		return nil, fmt.Errorf("%w: expected at least %d bytes, got %d", ErrShortSeed, MinSeedSize, len(seed))
+	}
+	// KeyGen: IKM || I2OSP(0, 1), key_info || I2OSP(L, 2), L = 48
+	const l = 48
+	ikm := append(append(make([]byte, 0, len(seed)+1), seed...), 0)
+	info := binary.BigEndian.AppendUint16(nil, l)
+	salt := []byte(keyGenSalt)
+	okm := make([]byte, l)
+	var k bls12381.Scalar
+	for {
+		digest := sha256.Sum256(salt)
+		salt = digest[:]
+		if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, info), okm); err != nil {
+			return nil, err
+		}
+		k.SetBytes(okm)
+		if k.IsZero() == 0 {
+			return k.MarshalBinary()
+		}
+	}
+}
+
+// PubKey returns the public key corresponding to the private key.
+func (k PrivKey) PubKey() PubKey {
+	var pk bls12381.G2
+	pk.ScalarMult(k.scalar(), bls12381.G2Generator())
+	return pk.BytesCompressed()
+}
+
+// Sign signs the message.
+func (k PrivKey) Sign(msg []byte) []byte {
+	return k.sign(msg, sigDST)
+}
+
+// ProvePossession returns the proof of possession of the private key, a signature of the public key.
+func (k PrivKey) ProvePossession() []byte {
+	return k.sign(k.PubKey(), popDST)
+}
+
+func (k PrivKey) sign(msg []byte, dst string) []byte {
+	var s bls12381.G1
+	s.Hash(msg, []byte(dst))
+	s.ScalarMult(k.scalar(), &s)
+	return s.BytesCompressed()
+}
+
+func (k PrivKey) scalar() *bls12381.Scalar {
+	var s bls12381.Scalar
+	s.SetBytes(k)
+	return &s
+}
+
+// ValidateBasic checks that the public key is a canonically encoded point of G2 of prime order, other than the
+// point at infinity (KeyValidate of the IETF draft).
+func (k PubKey) ValidateBasic() error {
+	_, err := k.point()
+	return err
+}
+
+// VerifySignature returns true if sig is a valid signature of the message made by the key.
+func (k PubKey) VerifySignature(msg []byte, sig []byte) bool {
+	return k.verify(msg, sig, sigDST)
+}
+
+// VerifyPossession checks the proof of possession of the private key of the public key.
+func (k PubKey) VerifyPossession(proof []byte) error {
+	if !k.verify(k, proof, popDST) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+func (k PubKey) verify(msg []byte, sig []byte, dst string) bool {
+	pk, err := k.point()
+	if err != nil {
+		return false
+	}
+	s, err := signaturePoint(sig)
+	if err != nil {
+		return false
+	}
+	var h bls12381.G1
+	h.Hash(msg, []byte(dst))
+	// e(sig, g2) == e(H(msg), pk)
+	return bls12381.ProdPairFrac([]*bls12381.G1{s, &h}, []*bls12381.G2{bls12381.G2Generator(), pk}, []int{1, -1}).IsIdentity()
+}
+
+func (k PubKey) point() (*bls12381.G2, error) {
+	if len(k) != PubKeySize {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidPubKey, PubKeySize, len(k))
+	}
+	var pk bls12381.G2
+	// decoding checks that the point is in the prime order subgroup, re-encoding rejects non-canonical encodings
+	if err := pk.SetBytes(k); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPubKey, err)
+	}
+	if !bytes.Equal(pk.BytesCompressed(), k) {
+		return nil, fmt.Errorf("%w: non-canonical encoding", ErrInvalidPubKey)
+	}
+	if pk.IsIdentity() {
+		return nil, fmt.Errorf("%w: point at infinity", ErrInvalidPubKey)
+	}
+	return &pk, nil
+}
+
+func signaturePoint(sig []byte) (*bls12381.G1, error) {
+	if len(sig) != SignatureSize {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, SignatureSize, len(sig))
+	}
+	var s bls12381.G1
+	if err := s.SetBytes(sig); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !bytes.Equal(s.BytesCompressed(), sig) {
+		return nil, fmt.Errorf("%w: non-canonical encoding", ErrInvalidSignature)
+	}
+	return &s, nil
+}
+
+// AggregateSignatures aggregates signatures of the same message into a single signature.
+func AggregateSignatures(sigs ...[]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNothingToAggregate
+	}
+	var agg bls12381.G1
+	agg.SetIdentity()
+	for _, sig := range sigs {
+		s, err := signaturePoint(sig)
+		if err != nil {
+			return nil, err
+		}
+		agg.Add(&agg, s)
+	}
+	return agg.BytesCompressed(), nil
+}
+
+// AggregatePubKeys aggregates public keys into a single key, that verifies aggregated signatures made by the keys.
+// Keys must have verified proofs of possession.
+func AggregatePubKeys(keys ...PubKey) (PubKey, error) {
+	if len(keys) == 0 {
+		return nil, ErrNothingToAggregate
+	}
+	var agg bls12381.G2
+	agg.SetIdentity()
+	for _, key := range keys {
+		pk, err := key.point()
+		if err != nil {
+			return nil, err
+		}
+		agg.Add(&agg, pk)
+	}
+	return agg.BytesCompressed(), nil
+}
+
+// VerifyAggregateSignature returns true if sig is a valid aggregated signature of the message made by all keys
+// (FastAggregateVerify of the IETF draft). Keys must have verified proofs of possession.
+func VerifyAggregateSignature(keys []PubKey, msg []byte, sig []byte) bool {
+	agg, err := AggregatePubKeys(keys...)
+	if err != nil {
+		return false
+	}
+	return agg.VerifySignature(msg, sig)
+}
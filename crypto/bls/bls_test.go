@@ -0,0 +1,170 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := GenPrivKey()
+	require.NoError(err)
+	assert.Len(key, PrivKeySize)
+	pubKey := key.PubKey()
+	require.NoError(pubKey.ValidateBasic())
+	assert.Len(pubKey, PubKeySize)
+
+	msg := []byte("message")
+	sig := key.Sign(msg)
+	assert.Len(sig, SignatureSize)
+	assert.True(pubKey.VerifySignature(msg, sig))
+	assert.False(pubKey.VerifySignature([]byte("other message"), sig))
+
+	other, err := GenPrivKey()
+	require.NoError(err)
+	assert.False(other.PubKey().VerifySignature(msg, sig))
+
+	seed := bytes.Repeat([]byte{1}, MinSeedSize)
+	a, err := PrivKeyFromSeed(seed)
+	require.NoError(err)
+	b, err := PrivKeyFromSeed(seed)
+	require.NoError(err)
+	assert.Equal(a, b)
+	c, err := PrivKeyFromSeed(bytes.Repeat([]byte{2}, MinSeedSize))
+	require.NoError(err)
+	assert.NotEqual(a, c)
+	_, err = PrivKeyFromSeed([]byte("seed"))
+	assert.ErrorIs(err, ErrShortSeed)
+}
+
+func TestProofOfPossession(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := GenPrivKey()
+	require.NoError(err)
+	proof := key.ProvePossession()
+	assert.NoError(key.PubKey().VerifyPossession(proof))
+
+	// proof of possession is not a signature of the public key as a message
+	assert.ErrorIs(key.PubKey().VerifyPossession(key.Sign(key.PubKey())), ErrInvalidProof)
+	assert.False(key.PubKey().VerifySignature(key.PubKey(), proof))
+
+	other, err := GenPrivKey()
+	require.NoError(err)
+	assert.ErrorIs(other.PubKey().VerifyPossession(proof), ErrInvalidProof)
+}
+
+func TestAggregate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	msg := []byte("header")
+	keys := make([]PubKey, 4)
+	sigs := make([][]byte, 4)
+	for i := range keys {
+		key, err := PrivKeyFromSeed(bytes.Repeat([]byte{byte(i)}, MinSeedSize))
+		require.NoError(err)
+		keys[i] = key.PubKey()
+		sigs[i] = key.Sign(msg)
+	}
+
+	agg, err := AggregateSignatures(sigs...)
+	require.NoError(err)
+	assert.Len(agg, SignatureSize)
+	assert.True(VerifyAggregateSignature(keys, msg, agg))
+	assert.False(VerifyAggregateSignature(keys[:3], msg, agg))
+	assert.False(VerifyAggregateSignature(keys, []byte("other"), agg))
+
+	partial, err := AggregateSignatures(sigs[1], sigs[3])
+	require.NoError(err)
+	assert.True(VerifyAggregateSignature([]PubKey{keys[1], keys[3]}, msg, partial))
+	assert.False(VerifyAggregateSignature([]PubKey{keys[1], keys[2]}, msg, partial))
+
+	_, err = AggregateSignatures()
+	assert.ErrorIs(err, ErrNothingToAggregate)
+	_, err = AggregateSignatures(sigs[0], []byte{1, 2, 3})
+	assert.ErrorIs(err, ErrInvalidSignature)
+	_, err = AggregatePubKeys(keys[0], make(PubKey, PubKeySize))
+	assert.ErrorIs(err, ErrInvalidPubKey)
+}
+
+func TestInvalidEncoding(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := GenPrivKey()
+	require.NoError(err)
+
+	// point at infinity
+	infinity := make(PubKey, PubKeySize)
+	infinity[0] = 0xc0
+	assert.ErrorIs(infinity.ValidateBasic(), ErrInvalidPubKey)
+	// point at infinity with the sign flag set is not canonical
+	infinity[0] = 0xe0
+	assert.ErrorIs(infinity.ValidateBasic(), ErrInvalidPubKey)
+	assert.ErrorIs(key.PubKey()[1:].ValidateBasic(), ErrInvalidPubKey)
+
+	sig := key.Sign([]byte("message"))
+	sig[0] ^= 0x20
+	assert.False(key.PubKey().VerifySignature([]byte("message"), sig))
+}
+
+func TestThresholdSignature(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := GenPrivKey()
+	require.NoError(err)
+	shares, err := SplitPrivKey(key, 3, 5)
+	require.NoError(err)
+	require.Len(shares, 5)
+
+	msg := []byte("height")
+	sigs := map[int][]byte{}
+	for _, i := range []int{5, 2, 4} {
+		sigs[i] = shares[i-1].Sign(msg)
+	}
+	combined, err := CombineSignatures(sigs)
+	require.NoError(err)
+	assert.Equal(key.Sign(msg), combined)
+
+	delete(sigs, 4)
+	combined, err = CombineSignatures(sigs)
+	require.NoError(err)
+	assert.False(key.PubKey().VerifySignature(msg, combined))
+
+	_, err = SplitPrivKey(key, 6, 5)
+	assert.ErrorIs(err, ErrInvalidThreshold)
+	_, err = CombineSignatures(map[int][]byte{0: sigs[2]})
+	assert.ErrorIs(err, ErrInvalidShareIndex)
+}
+
+func TestEncapsulate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := GenPrivKey()
+	require.NoError(err)
+	id := []byte("height 42")
+	encapsulation, secret, err := Encapsulate(key.PubKey(), id)
+	require.NoError(err)
+	assert.Len(encapsulation, EncapsulationSize)
+	assert.Len(secret, 32)
+
+	decapsulated, err := Decapsulate(key.Sign(id), encapsulation)
+	require.NoError(err)
+	assert.Equal(secret, decapsulated)
+
+	other, err := Decapsulate(key.Sign([]byte("height 43")), encapsulation)
+	require.NoError(err)
+	assert.NotEqual(secret, other)
+
+	_, err = Decapsulate(key.Sign(id), encapsulation[1:])
+	assert.ErrorIs(err, ErrInvalidEncapsulation)
+}
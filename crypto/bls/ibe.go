@@ -0,0 +1,79 @@
+package bls
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// EncapsulationSize is the size of an encapsulation (compressed G2 point) in bytes.
+const EncapsulationSize = bls12381.G2SizeCompressed
+
+// kemDomain separates derivation of shared secrets from other uses of SHA-256.
+const kemDomain = "ROLLKIT-BLS12381-IBE-KEM"
+
+// ErrInvalidEncapsulation is returned when encapsulation is not a valid point of G2.
+var ErrInvalidEncapsulation = errors.New("invalid encapsulation")
+
+// Encapsulate returns a random shared secret of 32 bytes, that can be recovered from the encapsulation with the
+// identity key of id: the signature of id made by the private key of masterKey (see Decapsulate).
+//
+// It's a key encapsulation mechanism of Boneh-Franklin identity based encryption, with identity keys being BLS
+// signatures. Signatures of ids published in the future (e.g. heights of blocks, signed by a threshold committee
+// when they are reached) make it a timelock encryption scheme.
+func Encapsulate(masterKey PubKey, id []byte) (encapsulation []byte, secret []byte, err error) {
+	pk, err := masterKey.point()
+	if err != nil {
+		return nil, nil, err
+	}
+	var r bls12381.Scalar
+	if err := r.Random(rand.Reader); err != nil {
+		return nil, nil, err
+	}
+	var u bls12381.G2
+	u.ScalarMult(&r, bls12381.G2Generator())
+	encapsulation = u.BytesCompressed()
+
+	// e(H(id), masterKey)^r = e(s*H(id), r*g2)
+	var h bls12381.G1
+	h.Hash(id, []byte(sigDST))
+	g := bls12381.Pair(&h, pk)
+	g.Exp(g, &r)
+	secret, err = deriveSecret(g, encapsulation)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encapsulation, secret, nil
+}
+
+// Decapsulate recovers the shared secret from the encapsulation with the identity key (signature of the id made by
+// the master key). The identity key has to be verified by the caller, e.g. with VerifySignature of the master key.
+func Decapsulate(idKey []byte, encapsulation []byte) ([]byte, error) {
+	s, err := signaturePoint(idKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(encapsulation) != EncapsulationSize {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidEncapsulation, EncapsulationSize, len(encapsulation))
+	}
+	var u bls12381.G2
+	if err := u.SetBytes(encapsulation); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncapsulation, err)
+	}
+	return deriveSecret(bls12381.Pair(s, &u), encapsulation)
+}
+
+func deriveSecret(g *bls12381.Gt, encapsulation []byte) ([]byte, error) {
+	gt, err := g.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte(kemDomain))
+	h.Write(gt)
+	h.Write(encapsulation)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,89 @@
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+var (
+	// ErrInvalidThreshold is returned when threshold is zero or greater than the number of shares.
+	ErrInvalidThreshold = errors.New("invalid threshold")
+	// ErrInvalidShareIndex is returned when index of a share is zero or duplicated.
+	ErrInvalidShareIndex = errors.New("invalid share index")
+)
+
+// SplitPrivKey splits the private key into n shares (Shamir secret sharing by a trusted dealer), any threshold of
+// which can sign messages on behalf of the key: signatures made by shares are combined with CombineSignatures.
+// Shares are indexed from 1 to n, the share with index i is at position i-1 of the returned slice.
+func SplitPrivKey(key PrivKey, threshold, n int) ([]PrivKey, error) {
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidThreshold, threshold, n)
+	}
+	// random polynomial of degree threshold-1, with the key as the constant term
+	coeffs := make([]bls12381.Scalar, threshold)
+	coeffs[0] = *key.scalar()
+	for i := 1; i < threshold; i++ {
+		if err := coeffs[i].Random(rand.Reader); err != nil {
+			return nil, err
+		}
+	}
+	shares := make([]PrivKey, n)
+	for i := range shares {
+		var x, y bls12381.Scalar
+		x.SetUint64(uint64(i + 1))
+		// Horner's method
+		for j := threshold - 1; j >= 0; j-- {
+			y.Mul(&y, &x)
+			y.Add(&y, &coeffs[j])
+		}
+		share, err := y.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+	}
+	return shares, nil
+}
+
+// CombineSignatures combines signatures of the same message made by shares of a private key (see SplitPrivKey),
+// indexed by the index of the share, into the signature made by the private key. Signatures of at least threshold
+// shares are required, otherwise the result is not a valid signature. Signatures of shares are not verified.
+func CombineSignatures(sigs map[int][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNothingToAggregate
+	}
+	var combined bls12381.G1
+	combined.SetIdentity()
+	for i, sig := range sigs {
+		if i < 1 {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidShareIndex, i)
+		}
+		s, err := signaturePoint(sig)
+		if err != nil {
+			return nil, err
+		}
+		// Lagrange coefficient of share i at 0: prod j/(j-i) over other shares j
+		var num, den, xi bls12381.Scalar
+		num.SetOne()
+		den.SetOne()
+		xi.SetUint64(uint64(i))
+		for j := range sigs {
+			if j == i {
+				continue
+			}
+			var xj, diff bls12381.Scalar
+			xj.SetUint64(uint64(j))
+			diff.Sub(&xj, &xi)
+			num.Mul(&num, &xj)
+			den.Mul(&den, &diff)
+		}
+		den.Inv(&den)
+		num.Mul(&num, &den)
+		s.ScalarMult(&num, s)
+		combined.Add(&combined, s)
+	}
+	return combined.BytesCompressed(), nil
+}
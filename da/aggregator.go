@@ -0,0 +1,263 @@
+package da
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// aggregatedSubmitTimeout limits the time of a single submission of aggregated blobs.
+const aggregatedSubmitTimeout = time.Minute
+
+// NamespacedBlob is a blob submitted to DA layer in the given namespace.
+type NamespacedBlob struct {
+	NamespaceID types.NamespaceID
+	Data        []byte
+}
+
+// AggregationAccount accounts submissions of a single namespace made by BlobAggregator.
+type AggregationAccount struct {
+	// Submissions is the number of DA transactions including blobs of the namespace.
+	Submissions uint64
+	// Blobs is the number of submitted blobs of the namespace.
+	Blobs uint64
+	// Bytes is the total size of submitted blobs of the namespace.
+	Bytes uint64
+	// Fee is the share of the namespace in fees of DA transactions, proportional to the size of its blobs.
+	Fee uint64
+}
+
+// SubmitNamespacedFunc submits blobs of multiple namespaces to DA layer, in a single DA transaction.
+type SubmitNamespacedFunc func(ctx context.Context, blobs []NamespacedBlob) ResultSubmitBlocks
+
+// BlobAggregator combines submissions of clients of multiple rollups (each using its own namespace) into a single
+// DA transaction, to amortize the base fee of DA transactions. Submissions are collected for at most the aggregation
+// window, until every registered namespace has a pending submission, or until they reach the maximal size of blobs
+// in a DA transaction. Collected submissions exceeding the maximal size are split into multiple DA transactions,
+// without splitting a single submission. Fee of every DA transaction is split between namespaces proportionally to
+// the size of their blobs.
+type BlobAggregator struct {
+	submit  SubmitNamespacedFunc
+	window  time.Duration
+	maxSize uint64
+	logger  log.Logger
+
+	mtx        sync.Mutex
+	namespaces map[types.NamespaceID]bool
+	pending    []*aggregatedSubmission
+	timer      *time.Timer
+	// generation is incremented when pending submissions are taken, so that a timer firing late doesn't flush
+	// submissions collected after it was stopped
+	generation uint64
+	accounts   map[types.NamespaceID]*AggregationAccount
+}
+
+type aggregatedSubmission struct {
+	namespaceID types.NamespaceID
+	blobs       [][]byte
+	size        uint64
+	result      chan ResultSubmitBlocks
+}
+
+// NewBlobAggregator creates BlobAggregator submitting blobs with submit, collecting submissions for at most window.
+// maxSize is the maximal total size of blobs in a single DA transaction, zero means no limit.
+func NewBlobAggregator(submit SubmitNamespacedFunc, window time.Duration, maxSize uint64, logger log.Logger) *BlobAggregator {
+	return &BlobAggregator{
+		submit:     submit,
+		window:     window,
+		maxSize:    maxSize,
+		logger:     logger,
+		namespaces: make(map[types.NamespaceID]bool),
+		accounts:   make(map[types.NamespaceID]*AggregationAccount),
+	}
+}
+
+// Register registers the namespace of a client submitting blobs through the aggregator. Pending submissions are
+// submitted without waiting for the end of the window, as soon as every registered namespace has one.
+func (a *BlobAggregator) Register(namespaceID types.NamespaceID) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.namespaces[namespaceID] = true
+}
+
+// Submit queues blobs of the namespace, and waits until they are submitted together with blobs of other namespaces.
+// Fee of the result is the share of the namespace in the fee of the DA transaction.
+func (a *BlobAggregator) Submit(ctx context.Context, namespaceID types.NamespaceID, blobs [][]byte) ResultSubmitBlocks {
+	s := &aggregatedSubmission{
+		namespaceID: namespaceID,
+		blobs:       blobs,
+		result:      make(chan ResultSubmitBlocks, 1),
+	}
+	for _, b := range blobs {
+		s.size += uint64(len(b))
+	}
+
+	a.mtx.Lock()
+	a.pending = append(a.pending, s)
+	var batch []*aggregatedSubmission
+	if a.allNamespacesPending() || a.pendingFull() {
+		batch = a.takePending()
+	} else if a.timer == nil {
+		generation := a.generation
+		a.timer = time.AfterFunc(a.window, func() { a.flushPending(generation) })
+	}
+	a.mtx.Unlock()
+	if batch != nil {
+		a.flush(batch)
+	}
+
+	select {
+	case res := <-s.result:
+		return res
+	case <-ctx.Done():
+		// blobs that are being submitted may still be included in DA layer
+		a.cancel(s)
+		return ResultSubmitBlocks{BaseResult: BaseResult{Code: StatusError, Message: ctx.Err().Error()}}
+	}
+}
+
+// AggregationAccounts returns accounts of all namespaces that submitted blobs.
+func (a *BlobAggregator) AggregationAccounts() map[types.NamespaceID]AggregationAccount {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	accounts := make(map[types.NamespaceID]AggregationAccount, len(a.accounts))
+	for ns, acc := range a.accounts {
+		accounts[ns] = *acc
+	}
+	return accounts
+}
+
+// allNamespacesPending returns true if every registered namespace has a pending submission.
+func (a *BlobAggregator) allNamespacesPending() bool {
+	pending := make(map[types.NamespaceID]bool, len(a.pending))
+	for _, s := range a.pending {
+		pending[s.namespaceID] = true
+	}
+	return len(pending) >= len(a.namespaces)
+}
+
+// pendingFull returns true if pending submissions reached the maximal size of a DA transaction.
+func (a *BlobAggregator) pendingFull() bool {
+	if a.maxSize == 0 {
+		return false
+	}
+	var size uint64
+	for _, s := range a.pending {
+		size += s.size
+	}
+	return size >= a.maxSize
+}
+
+func (a *BlobAggregator) takePending() []*aggregatedSubmission {
+	batch := a.pending
+	a.pending = nil
+	a.generation++
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	return batch
+}
+
+func (a *BlobAggregator) flushPending(generation uint64) {
+	a.mtx.Lock()
+	if generation != a.generation || len(a.pending) == 0 {
+		a.mtx.Unlock()
+		return
+	}
+	batch := a.takePending()
+	a.mtx.Unlock()
+	a.flush(batch)
+}
+
+func (a *BlobAggregator) cancel(s *aggregatedSubmission) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for i, p := range a.pending {
+		if p == s {
+			a.pending = append(a.pending[:i], a.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// flush submits the batch in DA transactions of at most maxSize bytes of blobs, and delivers results to all
+// submissions of the batch.
+func (a *BlobAggregator) flush(batch []*aggregatedSubmission) {
+	for len(batch) > 0 {
+		n := a.chunkLen(batch)
+		a.submitChunk(batch[:n])
+		batch = batch[n:]
+	}
+}
+
+// chunkLen returns the number of submissions from the start of the batch fitting in a single DA transaction. A
+// submission exceeding maxSize on its own is submitted alone.
+func (a *BlobAggregator) chunkLen(batch []*aggregatedSubmission) int {
+	if a.maxSize == 0 {
+		return len(batch)
+	}
+	size := batch[0].size
+	n := 1
+	for ; n < len(batch) && size+batch[n].size <= a.maxSize; n++ {
+		size += batch[n].size
+	}
+	return n
+}
+
+// submitChunk submits the submissions in a single DA transaction, and delivers results to all of them.
+func (a *BlobAggregator) submitChunk(batch []*aggregatedSubmission) {
+	var blobs []NamespacedBlob
+	var total uint64
+	for _, s := range batch {
+		for _, b := range s.blobs {
+			blobs = append(blobs, NamespacedBlob{NamespaceID: s.namespaceID, Data: b})
+		}
+		total += s.size
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), aggregatedSubmitTimeout)
+	res := a.submit(ctx, blobs)
+	cancel()
+	if res.Code != StatusSuccess {
+		for _, s := range batch {
+			s.result <- ResultSubmitBlocks{BaseResult: res.BaseResult}
+		}
+		return
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	remaining := res.Fee
+	for i, s := range batch {
+		fee := remaining
+		if i < len(batch)-1 {
+			fee = feeShare(res.Fee, s.size, total, len(batch))
+			remaining -= fee
+		}
+		acc, ok := a.accounts[s.namespaceID]
+		if !ok {
+			acc = &AggregationAccount{}
+			a.accounts[s.namespaceID] = acc
+		}
+		acc.Submissions++
+		acc.Blobs += uint64(len(s.blobs))
+		acc.Bytes += s.size
+		acc.Fee += fee
+		s.result <- ResultSubmitBlocks{BaseResult: res.BaseResult, Fee: fee}
+	}
+	a.logger.Debug("submitted aggregated blobs", "daHeight", res.DAHeight, "submissions", len(batch),
+		"blobs", len(blobs), "fee", res.Fee)
+}
+
+// feeShare returns the share of a submission of given size in the fee. Submissions without data share it equally.
+func feeShare(fee, size, total uint64, n int) uint64 {
+	if total == 0 {
+		return fee / uint64(n)
+	}
+	// fee*size could overflow for huge fees, split it in two parts
+	return fee/total*size + fee%total*size/total
+}
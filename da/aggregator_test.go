@@ -0,0 +1,167 @@
+package da
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// multiNamespaceDA records submissions of blobs of multiple namespaces.
+type multiNamespaceDA struct {
+	mtx         sync.Mutex
+	submissions [][]NamespacedBlob
+	fail        bool
+}
+
+func (d *multiNamespaceDA) submit(_ context.Context, blobs []NamespacedBlob) ResultSubmitBlocks {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.fail {
+		return ResultSubmitBlocks{BaseResult: BaseResult{Code: StatusError, Message: "insufficient funds"}}
+	}
+	d.submissions = append(d.submissions, blobs)
+	return ResultSubmitBlocks{BaseResult: BaseResult{Code: StatusSuccess, DAHeight: uint64(len(d.submissions))}, Fee: 1000}
+}
+
+func (d *multiNamespaceDA) numSubmissions() int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return len(d.submissions)
+}
+
+func TestBlobAggregator(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	ns1, ns2 := types.NamespaceID{1}, types.NamespaceID{2}
+	dalc := &multiNamespaceDA{}
+	agg := NewBlobAggregator(dalc.submit, time.Hour, 0, test.NewFileLogger(t))
+	agg.Register(ns1)
+	agg.Register(ns2)
+
+	// submission waits for submission of the other namespace
+	results := make(chan ResultSubmitBlocks)
+	go func() {
+		results <- agg.Submit(ctx, ns1, [][]byte{make([]byte, 100), make([]byte, 200)})
+	}()
+	require.Eventually(func() bool {
+		agg.mtx.Lock()
+		defer agg.mtx.Unlock()
+		return len(agg.pending) == 1
+	}, time.Second, time.Millisecond)
+	assert.Zero(dalc.numSubmissions())
+
+	res2 := agg.Submit(ctx, ns2, [][]byte{make([]byte, 100)})
+	res1 := <-results
+	require.Equal(1, dalc.numSubmissions())
+	assert.Len(dalc.submissions[0], 3)
+	assert.Equal(ns1, dalc.submissions[0][0].NamespaceID)
+	assert.Equal(ns2, dalc.submissions[0][2].NamespaceID)
+
+	// fee is split proportionally to the size of blobs
+	assert.Equal(StatusSuccess, res1.Code)
+	assert.Equal(uint64(1), res1.DAHeight)
+	assert.Equal(uint64(750), res1.Fee)
+	assert.Equal(uint64(1), res2.DAHeight)
+	assert.Equal(uint64(250), res2.Fee)
+	assert.Equal(map[types.NamespaceID]AggregationAccount{
+		ns1: {Submissions: 1, Blobs: 2, Bytes: 300, Fee: 750},
+		ns2: {Submissions: 1, Blobs: 1, Bytes: 100, Fee: 250},
+	}, agg.AggregationAccounts())
+
+	// failures are returned to all submissions
+	dalc.fail = true
+	go func() {
+		results <- agg.Submit(ctx, ns1, [][]byte{{1}})
+	}()
+	res2 = agg.Submit(ctx, ns2, [][]byte{{2}})
+	res1 = <-results
+	assert.Equal(StatusError, res1.Code)
+	assert.Equal(StatusError, res2.Code)
+	assert.Equal(uint64(750), agg.AggregationAccounts()[ns1].Fee)
+
+	// cancelled submission is not submitted
+	dalc.fail = false
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	res1 = agg.Submit(cancelCtx, ns1, [][]byte{{1}})
+	assert.Equal(StatusError, res1.Code)
+	assert.Empty(agg.pending)
+}
+
+func TestBlobAggregatorWindow(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	ns1, ns2 := types.NamespaceID{1}, types.NamespaceID{2}
+	dalc := &multiNamespaceDA{}
+	agg := NewBlobAggregator(dalc.submit, 10*time.Millisecond, 0, test.NewFileLogger(t))
+	agg.Register(ns1)
+	agg.Register(ns2)
+
+	// after the window, submission doesn't wait for other namespaces
+	res := agg.Submit(ctx, ns1, [][]byte{{1}})
+	assert.Equal(StatusSuccess, res.Code)
+	assert.Equal(uint64(1000), res.Fee)
+	assert.Equal(1, dalc.numSubmissions())
+
+	res = agg.Submit(ctx, ns1, [][]byte{{1}})
+	assert.Equal(StatusSuccess, res.Code)
+	assert.Equal(2, dalc.numSubmissions())
+}
+
+func TestBlobAggregatorMaxSize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	ns1, ns2, ns3 := types.NamespaceID{1}, types.NamespaceID{2}, types.NamespaceID{3}
+	dalc := &multiNamespaceDA{}
+	agg := NewBlobAggregator(dalc.submit, time.Hour, 300, test.NewFileLogger(t))
+	agg.Register(ns1)
+	agg.Register(ns2)
+	agg.Register(ns3)
+
+	// submissions are split into DA transactions of at most 300 bytes of blobs
+	results := make(chan ResultSubmitBlocks, 2)
+	go func() {
+		results <- agg.Submit(ctx, ns1, [][]byte{make([]byte, 100), make([]byte, 100)})
+	}()
+	go func() {
+		results <- agg.Submit(ctx, ns2, [][]byte{make([]byte, 50)})
+	}()
+	require.Eventually(func() bool {
+		agg.mtx.Lock()
+		defer agg.mtx.Unlock()
+		return len(agg.pending) == 2
+	}, time.Second, time.Millisecond)
+	res3 := agg.Submit(ctx, ns3, [][]byte{make([]byte, 150)})
+	<-results
+	<-results
+	require.Equal(2, dalc.numSubmissions())
+	assert.Len(dalc.submissions[0], 3)
+	assert.Len(dalc.submissions[1], 1)
+	assert.Equal(ns3, dalc.submissions[1][0].NamespaceID)
+	assert.Equal(uint64(2), res3.DAHeight)
+	assert.Equal(uint64(1000), res3.Fee)
+
+	// submission exceeding the maximal size is flushed right away, and submitted alone
+	res := agg.Submit(ctx, ns1, [][]byte{make([]byte, 400)})
+	assert.Equal(StatusSuccess, res.Code)
+	require.Equal(3, dalc.numSubmissions())
+	assert.Len(dalc.submissions[2], 1)
+}
+
+func TestFeeShare(t *testing.T) {
+	assert.Equal(t, uint64(333), feeShare(1000, 1, 3, 2))
+	assert.Equal(t, uint64(500), feeShare(1000, 0, 0, 2))
+	assert.Equal(t, uint64(1<<62), feeShare(1<<63, 5, 10, 2))
+}
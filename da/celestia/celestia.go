@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
 	ds "github.com/ipfs/go-datastore"
 
 	openrpc "github.com/rollkit/celestia-openrpc"
@@ -18,20 +17,30 @@ import (
 	"github.com/rollkit/rollkit/da"
 	"github.com/rollkit/rollkit/third_party/log"
 	"github.com/rollkit/rollkit/types"
-	pb "github.com/rollkit/rollkit/types/pb/rollkit"
 )
 
 // DataAvailabilityLayerClient use celestia-node public API.
 type DataAvailabilityLayerClient struct {
 	rpc *openrpc.Client
+	// parent is the client sharing its connection with this client, nil if client has its own connection
+	parent *DataAvailabilityLayerClient
 
-	namespace openrpcns.Namespace
-	config    Config
-	logger    log.Logger
+	namespaceID types.NamespaceID
+	namespace   openrpcns.Namespace
+	config      Config
+	logger      log.Logger
+	// aggregator combines submissions of clients sharing the connection, nil if aggregation is disabled
+	aggregator *da.BlobAggregator
 }
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BlockRetriever = &DataAvailabilityLayerClient{}
+var _ da.HealthChecker = &DataAvailabilityLayerClient{}
+var _ da.DataRootRetriever = &DataAvailabilityLayerClient{}
+var _ da.ChainIDProvider = &DataAvailabilityLayerClient{}
+var _ da.BlobClient = &DataAvailabilityLayerClient{}
+var _ da.SharedClient = &DataAvailabilityLayerClient{}
+var _ da.AggregationAccounter = &DataAvailabilityLayerClient{}
 
 // Config stores Celestia DALC configuration parameters.
 type Config struct {
@@ -40,19 +49,39 @@ type Config struct {
 	Timeout   time.Duration `json:"timeout"`
 	Fee       int64         `json:"fee"`
 	GasLimit  uint64        `json:"gas_limit"`
+	// AggregationWindow enables aggregation of blobs of clients sharing the connection (see WithNamespace) into
+	// single PayForBlobs transactions. It's the maximal time a submission waits for submissions of other clients.
+	AggregationWindow time.Duration `json:"aggregation_window"`
+	// MaxBlobSize is the maximal total size of blobs in a single PayForBlobs transaction of aggregated blobs; larger
+	// batches are split into multiple transactions. Zero means defaultMaxBlobSize.
+	MaxBlobSize uint64 `json:"max_blob_size"`
 }
 
+// defaultMaxBlobSize keeps aggregated blobs well below the 2 MiB block size of Celestia, leaving room for share
+// padding and other transactions.
+const defaultMaxBlobSize = 1500 * 1024
+
 // Init initializes DataAvailabilityLayerClient instance.
 func (c *DataAvailabilityLayerClient) Init(namespaceID types.NamespaceID, config []byte, kvStore ds.Datastore, logger log.Logger) error {
-	namespace, err := share.NewBlobNamespaceV0(namespaceID[:])
+	namespace, err := appNamespace(namespaceID)
 	if err != nil {
 		return err
 	}
-	c.namespace = namespace.ToAppNamespace()
+	c.namespaceID = namespaceID
+	c.namespace = namespace
 	c.logger = logger
 
 	if len(config) > 0 {
-		return json.Unmarshal(config, &c.config)
+		if err := json.Unmarshal(config, &c.config); err != nil {
+			return err
+		}
+	}
+	if c.config.AggregationWindow > 0 {
+		maxSize := c.config.MaxBlobSize
+		if maxSize == 0 {
+			maxSize = defaultMaxBlobSize
+		}
+		c.aggregator = da.NewBlobAggregator(c.submitNamespaced, c.config.AggregationWindow, maxSize, logger)
 	}
 
 	return nil
@@ -72,11 +101,82 @@ func (c *DataAvailabilityLayerClient) Stop() error {
 	return nil
 }
 
+// WithNamespace returns client sharing connection to celestia-node with this client, submitting and retrieving
+// blocks in the given namespace. If aggregation is enabled, blobs submitted by such clients are combined into
+// single PayForBlobs transactions.
+func (c *DataAvailabilityLayerClient) WithNamespace(namespaceID types.NamespaceID) (da.DataAvailabilityLayerClient, error) {
+	namespace, err := appNamespace(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	if c.aggregator != nil {
+		c.aggregator.Register(namespaceID)
+	}
+	return &DataAvailabilityLayerClient{
+		parent:      c,
+		namespaceID: namespaceID,
+		namespace:   namespace,
+		config:      c.config,
+		logger:      c.logger,
+	}, nil
+}
+
+// AggregationAccounts returns accounts of namespaces of clients sharing the connection, if aggregation is enabled.
+func (c *DataAvailabilityLayerClient) AggregationAccounts() map[types.NamespaceID]da.AggregationAccount {
+	if c.parent != nil {
+		return c.parent.AggregationAccounts()
+	}
+	if c.aggregator == nil {
+		return nil
+	}
+	return c.aggregator.AggregationAccounts()
+}
+
+func appNamespace(namespaceID types.NamespaceID) (openrpcns.Namespace, error) {
+	namespace, err := share.NewBlobNamespaceV0(namespaceID[:])
+	if err != nil {
+		return openrpcns.Namespace{}, err
+	}
+	return namespace.ToAppNamespace(), nil
+}
+
+func (c *DataAvailabilityLayerClient) client() *openrpc.Client {
+	if c.parent != nil {
+		return c.parent.client()
+	}
+	return c.rpc
+}
+
+// CheckHealth checks if celestia-node is reachable, by querying its local head.
+func (c *DataAvailabilityLayerClient) CheckHealth(ctx context.Context) error {
+	_, err := c.client().Header.LocalHead(ctx)
+	return err
+}
+
+// DAChainID returns the chain ID of Celestia network, taken from the local head of celestia-node.
+func (c *DataAvailabilityLayerClient) DAChainID(ctx context.Context) (string, error) {
+	head, err := c.client().Header.LocalHead(ctx)
+	if err != nil {
+		return "", err
+	}
+	return head.ChainID(), nil
+}
+
+// DataRoot returns the data root of Celestia block at given height, taken from its header.
+func (c *DataAvailabilityLayerClient) DataRoot(ctx context.Context, dataLayerHeight uint64) ([]byte, error) {
+	header, err := c.client().Header.GetByHeight(ctx, dataLayerHeight)
+	if err != nil {
+		return nil, err
+	}
+	return header.DataHash, nil
+}
+
 // SubmitBlocks submits blocks to DA layer.
 func (c *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
-	blobs := make([]*blob.Blob, len(blocks))
-	for blockIndex, block := range blocks {
-		data, err := block.MarshalBinary()
+	data := make([][]byte, len(blocks))
+	for i, block := range blocks {
+		var err error
+		data[i], err = block.MarshalBinary()
 		if err != nil {
 			return da.ResultSubmitBlocks{
 				BaseResult: da.BaseResult{
@@ -85,42 +185,14 @@ func (c *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks [
 				},
 			}
 		}
-		blockBlob, err := blob.NewBlobV0(c.namespace.Bytes(), data)
-		if err != nil {
-			return da.ResultSubmitBlocks{
-				BaseResult: da.BaseResult{
-					Code:    da.StatusError,
-					Message: err.Error(),
-				},
-			}
-		}
-		blobs[blockIndex] = blockBlob
-	}
-
-	dataLayerHeight, err := c.rpc.Blob.Submit(ctx, blobs, openrpc.DefaultSubmitOptions())
-	if err != nil {
-		return da.ResultSubmitBlocks{
-			BaseResult: da.BaseResult{
-				Code:    da.StatusError,
-				Message: err.Error(),
-			},
-		}
-	}
-
-	c.logger.Debug("successfully submitted blobs", "daHeight", dataLayerHeight)
-
-	return da.ResultSubmitBlocks{
-		BaseResult: da.BaseResult{
-			Code:     da.StatusSuccess,
-			DAHeight: uint64(dataLayerHeight),
-		},
 	}
+	return c.SubmitBlobs(ctx, data)
 }
 
 // RetrieveBlocks gets a batch of blocks from DA layer.
 func (c *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBlocks {
 	c.logger.Debug("trying to retrieve blob using Blob.GetAll", "daHeight", dataLayerHeight, "namespace", hex.EncodeToString(c.namespace.Bytes()))
-	blobs, err := c.rpc.Blob.GetAll(ctx, dataLayerHeight, []share.Namespace{c.namespace.Bytes()})
+	blobs, err := c.client().Blob.GetAll(ctx, dataLayerHeight, []share.Namespace{c.namespace.Bytes()})
 	status := dataRequestErrorToStatus(err)
 	if status != da.StatusSuccess {
 		return da.ResultRetrieveBlocks{
@@ -131,24 +203,15 @@ func (c *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, dataLa
 		}
 	}
 
-	blocks := make([]*types.Block, len(blobs))
+	blocks := make([]*types.Block, 0, len(blobs))
 	for i, blob := range blobs {
-		var block pb.Block
-		err = proto.Unmarshal(blob.Data, &block)
-		if err != nil {
+		// anyone can post blobs in the namespace, so malformed blobs are skipped
+		block := new(types.Block)
+		if err := block.UnmarshalBinary(blob.Data); err != nil {
 			c.logger.Error("failed to unmarshal block", "daHeight", dataLayerHeight, "position", i, "error", err)
 			continue
 		}
-		blocks[i] = new(types.Block)
-		err := blocks[i].FromProto(&block)
-		if err != nil {
-			return da.ResultRetrieveBlocks{
-				BaseResult: da.BaseResult{
-					Code:    da.StatusError,
-					Message: err.Error(),
-				},
-			}
-		}
+		blocks = append(blocks, block)
 	}
 
 	return da.ResultRetrieveBlocks{
@@ -160,6 +223,68 @@ func (c *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, dataLa
 	}
 }
 
+// SubmitBlobs submits raw blobs in the namespace of the client to DA layer.
+func (c *DataAvailabilityLayerClient) SubmitBlobs(ctx context.Context, data [][]byte) da.ResultSubmitBlocks {
+	if c.parent != nil && c.parent.aggregator != nil {
+		return c.parent.aggregator.Submit(ctx, c.namespaceID, data)
+	}
+	blobs := make([]*blob.Blob, len(data))
+	for i, d := range data {
+		b, err := blob.NewBlobV0(c.namespace.Bytes(), d)
+		if err != nil {
+			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+		blobs[i] = b
+	}
+	return c.submit(ctx, blobs)
+}
+
+// submitNamespaced submits blobs of clients sharing the connection in a single PayForBlobs transaction.
+func (c *DataAvailabilityLayerClient) submitNamespaced(ctx context.Context, data []da.NamespacedBlob) da.ResultSubmitBlocks {
+	blobs := make([]*blob.Blob, len(data))
+	for i, d := range data {
+		namespace, err := appNamespace(d.NamespaceID)
+		if err != nil {
+			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+		b, err := blob.NewBlobV0(namespace.Bytes(), d.Data)
+		if err != nil {
+			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+		blobs[i] = b
+	}
+	return c.submit(ctx, blobs)
+}
+
+func (c *DataAvailabilityLayerClient) submit(ctx context.Context, blobs []*blob.Blob) da.ResultSubmitBlocks {
+	opts := openrpc.DefaultSubmitOptions()
+	if c.config.Fee > 0 {
+		opts.Fee = c.config.Fee
+		opts.GasLimit = c.config.GasLimit
+	}
+	fee := c.submissionFee(blobs)
+	dataLayerHeight, err := c.client().Blob.Submit(ctx, blobs, opts)
+	if err != nil {
+		return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	c.logger.Debug("successfully submitted blobs", "daHeight", dataLayerHeight, "fee", fee)
+	return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: uint64(dataLayerHeight)}, Fee: fee}
+}
+
+// RetrieveBlobs gets all blobs in the namespace of the client from DA layer.
+func (c *DataAvailabilityLayerClient) RetrieveBlobs(ctx context.Context, dataLayerHeight uint64) da.ResultRetrieveBlobs {
+	blobs, err := c.client().Blob.GetAll(ctx, dataLayerHeight, []share.Namespace{c.namespace.Bytes()})
+	status := dataRequestErrorToStatus(err)
+	if status != da.StatusSuccess {
+		return da.ResultRetrieveBlobs{BaseResult: da.BaseResult{Code: status, Message: err.Error()}}
+	}
+	data := make([][]byte, len(blobs))
+	for i, b := range blobs {
+		data[i] = b.Data
+	}
+	return da.ResultRetrieveBlobs{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: dataLayerHeight}, Blobs: data}
+}
+
 func dataRequestErrorToStatus(err error) da.StatusCode {
 	switch {
 	case err == nil,
@@ -41,3 +41,17 @@ func TestDataRequestErrorToStatus(t *testing.T) {
 		assert.Equal(tt.statusCode, dataRequestErrorToStatus(tt.err))
 	}
 }
+
+func TestEstimateFee(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(uint64(1), sharesNeeded(0))
+	assert.Equal(uint64(1), sharesNeeded(478))
+	assert.Equal(uint64(2), sharesNeeded(479))
+	assert.Equal(uint64(2), sharesNeeded(960))
+	assert.Equal(uint64(3), sharesNeeded(961))
+
+	// 75000 + 8*512 + 10*70 = 79796 gas
+	assert.Equal(uint64(159), estimateFee([]int{100}))
+	// 75000 + 8*512*(1+2) + 2*10*70 = 88688 gas
+	assert.Equal(uint64(177), estimateFee([]int{100, 900}))
+}
@@ -0,0 +1,57 @@
+package celestia
+
+import (
+	"github.com/rollkit/celestia-openrpc/types/blob"
+)
+
+// Parameters of Celestia used by celestia-node to estimate gas and fee of PayForBlobs transaction, when fee is not
+// configured.
+const (
+	// defaultGasPrice is the gas price (utia per unit of gas) used by celestia-node.
+	defaultGasPrice = 0.002
+	// pfbGasFixedCost is the gas consumed by PayForBlobs transaction regardless of its blobs.
+	pfbGasFixedCost = 75000
+	// gasPerBlobByte is the gas consumed per byte of shares occupied by blobs.
+	gasPerBlobByte = 8
+	// txSizeCostPerByte is the gas consumed per byte of the transaction.
+	txSizeCostPerByte = 10
+	// bytesPerBlobInfo is the size of information about a single blob in the transaction.
+	bytesPerBlobInfo = 70
+	// shareSize is the size of a share.
+	shareSize = 512
+	// firstShareContentSize and continuationShareContentSize are sizes of blob data in the first and subsequent
+	// shares of a blob.
+	firstShareContentSize        = 478
+	continuationShareContentSize = 482
+)
+
+// submissionFee returns the fee paid for submission of the blobs: configured fee if set, otherwise fee estimated by
+// celestia-node, in the same way as by estimateFee.
+func (c *DataAvailabilityLayerClient) submissionFee(blobs []*blob.Blob) uint64 {
+	if c.config.Fee > 0 {
+		return uint64(c.config.Fee)
+	}
+	sizes := make([]int, len(blobs))
+	for i, b := range blobs {
+		sizes[i] = len(b.Data)
+	}
+	return estimateFee(sizes)
+}
+
+// estimateFee returns the fee of PayForBlobs transaction with blobs of given sizes, with default gas price.
+func estimateFee(blobSizes []int) uint64 {
+	gas := uint64(pfbGasFixedCost)
+	for _, size := range blobSizes {
+		gas += gasPerBlobByte*shareSize*sharesNeeded(size) + txSizeCostPerByte*bytesPerBlobInfo
+	}
+	return uint64(defaultGasPrice * float64(gas))
+}
+
+// sharesNeeded returns the number of shares occupied by blob of given size.
+func sharesNeeded(size int) uint64 {
+	if size <= firstShareContentSize {
+		return 1
+	}
+	size -= firstShareContentSize
+	return 1 + uint64((size+continuationShareContentSize-1)/continuationShareContentSize)
+}
@@ -48,6 +48,9 @@ type BaseResult struct {
 // ResultSubmitBlocks contains information returned from DA layer after blocks submission.
 type ResultSubmitBlocks struct {
 	BaseResult
+	// Fee is the fee paid for the submission, in the smallest unit of the DA layer token (e.g. utia).
+	// It's zero if DA layer client doesn't know the fee.
+	Fee uint64
 	// Not sure if this needs to be bubbled up to other
 	// parts of Rollkit.
 	// Hash hash.Hash
@@ -69,6 +72,14 @@ type ResultRetrieveBlocks struct {
 	Blocks []*types.Block
 }
 
+// ResultRetrieveBlobs contains blobs returned from DA layer client.
+type ResultRetrieveBlobs struct {
+	BaseResult
+	// Blobs are the raw blobs in the namespace of the client, retrieved from Data Availability Layer.
+	// If Code is not equal to StatusSuccess, it has to be nil.
+	Blobs [][]byte
+}
+
 // DataAvailabilityLayerClient defines generic interface for DA layer block submission.
 // It also contains life-cycle methods.
 type DataAvailabilityLayerClient interface {
@@ -93,3 +104,51 @@ type BlockRetriever interface {
 	// RetrieveBlocks returns blocks at given data layer height from data availability layer.
 	RetrieveBlocks(ctx context.Context, dataLayerHeight uint64) ResultRetrieveBlocks
 }
+
+// HealthChecker is additional interface that can be implemented by Data Availability Layer Client that is able to
+// check if data availability layer is reachable.
+type HealthChecker interface {
+	// CheckHealth returns an error if data availability layer is not reachable.
+	CheckHealth(ctx context.Context) error
+}
+
+// DataRootRetriever is additional interface that can be implemented by Data Availability Layer Client that is able to
+// return data roots of DA blocks. This gives the ability to create data commitments verifiable by bridge contracts.
+type DataRootRetriever interface {
+	// DataRoot returns the root of all data included in the DA block at given height.
+	DataRoot(ctx context.Context, dataLayerHeight uint64) ([]byte, error)
+}
+
+// BlobClient is additional interface that can be implemented by Data Availability Layer Client that is able to
+// submit and retrieve arbitrary blobs in its namespace. This gives the ability to publish data other than blocks,
+// like application snapshots.
+type BlobClient interface {
+	// SubmitBlobs submits the blobs to DA layer, in a single DA block.
+	SubmitBlobs(ctx context.Context, blobs [][]byte) ResultSubmitBlocks
+	// RetrieveBlobs returns all blobs in the namespace of the client at given data layer height.
+	RetrieveBlobs(ctx context.Context, dataLayerHeight uint64) ResultRetrieveBlobs
+}
+
+// AggregationAccounter is additional interface that can be implemented by SharedClient, that combines submissions
+// of clients of multiple namespaces into single DA transactions (see BlobAggregator). This gives the ability to
+// account DA fees per rollup.
+type AggregationAccounter interface {
+	// AggregationAccounts returns accounts of all namespaces that submitted blobs.
+	AggregationAccounts() map[types.NamespaceID]AggregationAccount
+}
+
+// ChainIDProvider is additional interface that can be implemented by Data Availability Layer Client that is able to
+// return the chain ID of DA layer. This gives the ability to verify that the node is connected to DA chain from genesis.
+type ChainIDProvider interface {
+	// DAChainID returns the chain ID of DA layer the client is connected to.
+	DAChainID(ctx context.Context) (string, error)
+}
+
+// SharedClient is additional interface that can be implemented by Data Availability Layer Client that can be shared
+// by multiple rollups running in one process, each using its own namespace.
+type SharedClient interface {
+	// WithNamespace returns client sharing resources (like connection to DA node) with this client, submitting and
+	// retrieving blocks in the given namespace. Returned client is not started or stopped on its own, it's operational
+	// as long as this client is.
+	WithNamespace(namespaceID types.NamespaceID) (DataAvailabilityLayerClient, error)
+}
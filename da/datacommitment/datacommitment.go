@@ -0,0 +1,176 @@
+package datacommitment
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+
+	"github.com/rollkit/rollkit/da"
+)
+
+// MaxRange is the maximal number of DA blocks covered by a single data commitment.
+const MaxRange = 10000
+
+var (
+	// ErrInvalidRange is returned when the requested range of blocks is empty or too large.
+	ErrInvalidRange = errors.New("invalid range of blocks")
+	// ErrInvalidProof is returned when data root inclusion proof doesn't match the data commitment.
+	ErrInvalidProof = errors.New("invalid data root inclusion proof")
+)
+
+// DataRootTuple is a pair of DA block height and its data root. Data commitments are Merkle roots of tuples.
+type DataRootTuple struct {
+	Height   uint64           `json:"height"`
+	DataRoot cmbytes.HexBytes `json:"data_root"`
+}
+
+// Encode returns the ABI encoding of the tuple (uint256 height, bytes32 data root), used as a leaf of the data
+// commitment, so it can be verified by bridge contracts.
+func (t DataRootTuple) Encode() []byte {
+	leaf := make([]byte, 64)
+	binary.BigEndian.PutUint64(leaf[24:32], t.Height)
+	copy(leaf[32:], t.DataRoot)
+	return leaf
+}
+
+// DataCommitment is the Merkle root of data root tuples of DA blocks from Start (inclusive) to End (exclusive).
+type DataCommitment struct {
+	Start uint64           `json:"start"`
+	End   uint64           `json:"end"`
+	Root  cmbytes.HexBytes `json:"root"`
+}
+
+// DataRootInclusionProof proves that the DA block containing a rollup block is included in a data commitment.
+type DataRootInclusionProof struct {
+	// Height is the height of the rollup block.
+	Height     uint64         `json:"height"`
+	Tuple      DataRootTuple  `json:"tuple"`
+	Commitment DataCommitment `json:"commitment"`
+	Proof      merkle.Proof   `json:"proof"`
+}
+
+// Verify checks that the tuple is included in the data commitment with given root.
+func (p *DataRootInclusionProof) Verify(root []byte) error {
+	if !bytes.Equal(p.Commitment.Root, root) {
+		return fmt.Errorf("%w: expected root %X", ErrInvalidProof, root)
+	}
+	if p.Tuple.Height < p.Commitment.Start || p.Tuple.Height >= p.Commitment.End {
+		return fmt.Errorf("%w: DA height %d out of range [%d, %d)", ErrInvalidProof, p.Tuple.Height, p.Commitment.Start, p.Commitment.End)
+	}
+	if uint64(p.Proof.Index) != p.Tuple.Height-p.Commitment.Start || uint64(p.Proof.Total) != p.Commitment.End-p.Commitment.Start {
+		return fmt.Errorf("%w: proof doesn't match position of DA height %d", ErrInvalidProof, p.Tuple.Height)
+	}
+	if err := p.Proof.Verify(root, p.Tuple.Encode()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	return nil
+}
+
+// DAHeightFunc returns the height of DA block containing the rollup block at given height.
+type DAHeightFunc func(height uint64) (uint64, error)
+
+// Provider maps ranges of rollup blocks to data commitments of DA blocks containing them,
+// and creates proofs of inclusion of DA blocks in data commitments.
+type Provider struct {
+	retriever da.DataRootRetriever
+	daHeight  DAHeightFunc
+}
+
+// NewProvider creates Provider reading data roots with the retriever.
+func NewProvider(retriever da.DataRootRetriever, daHeight DAHeightFunc) *Provider {
+	return &Provider{retriever: retriever, daHeight: daHeight}
+}
+
+// DataCommitment returns the data commitment of DA blocks containing rollup blocks from `from` to `to` (inclusive).
+func (p *Provider) DataCommitment(ctx context.Context, from, to uint64) (*DataCommitment, error) {
+	start, end, err := p.daRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	commitment, _, err := p.commit(ctx, start, end)
+	return commitment, err
+}
+
+// DataRootInclusionProof returns proof of inclusion of the DA block containing rollup block at given height
+// in the data commitment of rollup blocks from `from` to `to` (inclusive).
+func (p *Provider) DataRootInclusionProof(ctx context.Context, height, from, to uint64) (*DataRootInclusionProof, error) {
+	if height < from || height > to {
+		return nil, fmt.Errorf("%w: height %d out of range [%d, %d]", ErrInvalidRange, height, from, to)
+	}
+	daHeight, err := p.daHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := p.daRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	commitment, tuples, err := p.commit(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	_, proofs := merkle.ProofsFromByteSlices(encodeTuples(tuples))
+	i := daHeight - start
+	return &DataRootInclusionProof{
+		Height:     height,
+		Tuple:      tuples[i],
+		Commitment: *commitment,
+		Proof:      *proofs[i],
+	}, nil
+}
+
+// daRange returns the range of DA blocks containing rollup blocks from `from` to `to` (inclusive).
+func (p *Provider) daRange(from, to uint64) (uint64, uint64, error) {
+	if from == 0 || from > to {
+		return 0, 0, fmt.Errorf("%w: [%d, %d]", ErrInvalidRange, from, to)
+	}
+	start, err := p.daHeight(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	last, err := p.daHeight(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if last < start {
+		start, last = last, start
+	}
+	if last-start+1 > MaxRange {
+		return 0, 0, fmt.Errorf("%w: %d DA blocks exceed limit of %d", ErrInvalidRange, last-start+1, MaxRange)
+	}
+	return start, last + 1, nil
+}
+
+func (p *Provider) commit(ctx context.Context, start, end uint64) (*DataCommitment, []DataRootTuple, error) {
+	tuples := make([]DataRootTuple, 0, end-start)
+	for h := start; h < end; h++ {
+		root, err := p.retriever.DataRoot(ctx, h)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get data root of DA block %d: %w", h, err)
+		}
+		tuples = append(tuples, DataRootTuple{Height: h, DataRoot: root})
+	}
+	return NewDataCommitment(start, tuples), tuples, nil
+}
+
+// NewDataCommitment creates the data commitment of consecutive tuples, starting at DA height start.
+func NewDataCommitment(start uint64, tuples []DataRootTuple) *DataCommitment {
+	return &DataCommitment{
+		Start: start,
+		End:   start + uint64(len(tuples)),
+		Root:  merkle.HashFromByteSlices(encodeTuples(tuples)),
+	}
+}
+
+func encodeTuples(tuples []DataRootTuple) [][]byte {
+	leaves := make([][]byte, len(tuples))
+	for i, t := range tuples {
+		leaves[i] = t.Encode()
+	}
+	return leaves
+}
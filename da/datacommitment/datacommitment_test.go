@@ -0,0 +1,73 @@
+package datacommitment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/da"
+)
+
+var errUnknownHeight = errors.New("unknown height")
+
+type dataRoots struct{}
+
+func (dataRoots) DataRoot(_ context.Context, height uint64) ([]byte, error) {
+	if height > 100 {
+		return nil, da.ErrDataNotFound
+	}
+	var h [8]byte
+	binary.BigEndian.PutUint64(h[:], height)
+	root := sha256.Sum256(h[:])
+	return root[:], nil
+}
+
+func TestDataCommitment(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	// rollup blocks 1-3 are in DA block 10, block 4 in DA block 12, block 5 in DA block 200
+	daHeights := map[uint64]uint64{1: 10, 2: 10, 3: 10, 4: 12, 5: 200}
+	p := NewProvider(dataRoots{}, func(height uint64) (uint64, error) {
+		daHeight, ok := daHeights[height]
+		if !ok {
+			return 0, errUnknownHeight
+		}
+		return daHeight, nil
+	})
+
+	commitment, err := p.DataCommitment(ctx, 1, 4)
+	require.NoError(err)
+	assert.Equal(uint64(10), commitment.Start)
+	assert.Equal(uint64(13), commitment.End)
+
+	for _, height := range []uint64{2, 4} {
+		proof, err := p.DataRootInclusionProof(ctx, height, 1, 4)
+		require.NoError(err)
+		assert.Equal(daHeights[height], proof.Tuple.Height)
+		assert.NoError(proof.Verify(commitment.Root))
+	}
+
+	proof, err := p.DataRootInclusionProof(ctx, 4, 1, 4)
+	require.NoError(err)
+	proof.Tuple.Height = 11
+	assert.ErrorIs(proof.Verify(commitment.Root), ErrInvalidProof)
+	proof.Tuple.Height = 12
+	proof.Tuple.DataRoot[0] ^= 1
+	assert.ErrorIs(proof.Verify(commitment.Root), ErrInvalidProof)
+
+	_, err = p.DataCommitment(ctx, 4, 1)
+	assert.ErrorIs(err, ErrInvalidRange)
+	_, err = p.DataRootInclusionProof(ctx, 5, 1, 4)
+	assert.ErrorIs(err, ErrInvalidRange)
+	_, err = p.DataCommitment(ctx, 1, 5)
+	assert.ErrorIs(err, da.ErrDataNotFound)
+	_, err = p.DataCommitment(ctx, 1, 6)
+	assert.ErrorIs(err, errUnknownHeight)
+}
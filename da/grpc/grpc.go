@@ -3,10 +3,12 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 
 	ds "github.com/ipfs/go-datastore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/rollkit/rollkit/da"
@@ -41,6 +43,7 @@ var DefaultConfig = Config{
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BlockRetriever = &DataAvailabilityLayerClient{}
+var _ da.HealthChecker = &DataAvailabilityLayerClient{}
 
 // Init sets the configuration options.
 func (d *DataAvailabilityLayerClient) Init(_ types.NamespaceID, config []byte, _ ds.Datastore, logger log.Logger) error {
@@ -75,6 +78,14 @@ func (d *DataAvailabilityLayerClient) Stop() error {
 	return d.conn.Close()
 }
 
+// CheckHealth checks the state of connection to gRPC server.
+func (d *DataAvailabilityLayerClient) CheckHealth(_ context.Context) error {
+	if state := d.conn.GetState(); state == connectivity.TransientFailure || state == connectivity.Shutdown {
+		return fmt.Errorf("connection to gRPC server is in %s state", state)
+	}
+	return nil
+}
+
 // SubmitBlocks proxies SubmitBlocks request to gRPC server.
 func (d *DataAvailabilityLayerClient) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
 	bps := make([]*rollkit.Block, len(blocks))
@@ -3,6 +3,8 @@ package mock
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"math/rand"
@@ -40,6 +42,9 @@ type config struct {
 
 var _ da.DataAvailabilityLayerClient = &DataAvailabilityLayerClient{}
 var _ da.BlockRetriever = &DataAvailabilityLayerClient{}
+var _ da.DataRootRetriever = &DataAvailabilityLayerClient{}
+var _ da.SharedClient = &DataAvailabilityLayerClient{}
+var _ da.BlobClient = &DataAvailabilityLayerClient{}
 
 // Init is called once to allow DA client to read configuration and initialize resources.
 func (m *DataAvailabilityLayerClient) Init(_ types.NamespaceID, config []byte, dalcKV ds.Datastore, logger log.Logger) error {
@@ -203,6 +208,56 @@ func (m *DataAvailabilityLayerClient) RetrieveBlocks(ctx context.Context, daHeig
 	return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess}, Blocks: blocks}
 }
 
+// SubmitBlobs stores raw blobs at the current DA height.
+func (m *DataAvailabilityLayerClient) SubmitBlobs(ctx context.Context, blobs [][]byte) da.ResultSubmitBlocks {
+	daHeight := atomic.LoadUint64(&m.daHeight)
+	for _, blob := range blobs {
+		hash := sha256.Sum256(blob)
+		err := m.dalcKV.Put(ctx, getBlobKey(daHeight, hash[:]), blob)
+		if err != nil {
+			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+	}
+	return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, Message: "OK", DAHeight: daHeight}}
+}
+
+// RetrieveBlobs returns raw blobs stored at given DA height.
+func (m *DataAvailabilityLayerClient) RetrieveBlobs(ctx context.Context, daHeight uint64) da.ResultRetrieveBlobs {
+	if daHeight >= atomic.LoadUint64(&m.daHeight) {
+		return da.ResultRetrieveBlobs{BaseResult: da.BaseResult{Code: da.StatusError, Message: "block not found"}}
+	}
+	results, err := store.PrefixEntries(ctx, m.dalcKV, getBlobPrefix(daHeight))
+	if err != nil {
+		return da.ResultRetrieveBlobs{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+	}
+	var blobs [][]byte
+	for result := range results.Next() {
+		blobs = append(blobs, result.Entry.Value)
+	}
+	return da.ResultRetrieveBlobs{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: daHeight}, Blobs: blobs}
+}
+
+// WithNamespace returns the same client, as mock doesn't separate namespaces; all rollups sharing the mock
+// retrieve blocks of each other.
+func (m *DataAvailabilityLayerClient) WithNamespace(types.NamespaceID) (da.DataAvailabilityLayerClient, error) {
+	return m, nil
+}
+
+// DataRoot returns the hash of the DA header at given height. Heights skipped by the mock have deterministic
+// data roots derived from the height.
+func (m *DataAvailabilityLayerClient) DataRoot(ctx context.Context, daHeight uint64) ([]byte, error) {
+	if daHeight >= atomic.LoadUint64(&m.daHeight) {
+		return nil, da.ErrDataNotFound
+	}
+	if dah := m.GetHeaderByHeight(daHeight); dah != nil {
+		return dah.Hash(), nil
+	}
+	var h [8]byte
+	binary.BigEndian.PutUint64(h[:], daHeight)
+	root := sha256.Sum256(h[:])
+	return root[:], nil
+}
+
 func getPrefix(daHeight uint64) string {
 	return store.GenerateKey([]interface{}{daHeight})
 }
@@ -211,6 +266,14 @@ func getKey(daHeight uint64, height uint64) ds.Key {
 	return ds.NewKey(store.GenerateKey([]interface{}{daHeight, height}))
 }
 
+func getBlobPrefix(daHeight uint64) string {
+	return store.GenerateKey([]interface{}{"blobs", daHeight})
+}
+
+func getBlobKey(daHeight uint64, hash []byte) ds.Key {
+	return ds.NewKey(store.GenerateKey([]interface{}{"blobs", daHeight, hex.EncodeToString(hash)}))
+}
+
 func (m *DataAvailabilityLayerClient) updateDAHeight() {
 	blockStep := rand.Uint64()%10 + 1 //nolint:gosec
 	atomic.AddUint64(&m.daHeight, blockStep)
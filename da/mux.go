@@ -0,0 +1,207 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// BlobType tags a blob submitted to a namespace shared by multiple types of data.
+type BlobType byte
+
+// Types of blobs in a shared namespace.
+const (
+	BlobTypeBlock BlobType = iota + 1
+	BlobTypeHeader
+	BlobTypeFraudProof
+	BlobTypeSnapshotChunk
+	BlobTypeForcedTx
+)
+
+// ErrInvalidEnvelope is returned when a blob is not wrapped in an envelope of a known blob type.
+var ErrInvalidEnvelope = errors.New("invalid blob envelope")
+
+// envelopeMagic starts every blob wrapped in an envelope. It's followed by a single byte of the blob type.
+var envelopeMagic = []byte("RKB")
+
+func (t BlobType) String() string {
+	switch t {
+	case BlobTypeBlock:
+		return "block"
+	case BlobTypeHeader:
+		return "header"
+	case BlobTypeFraudProof:
+		return "fraud_proof"
+	case BlobTypeSnapshotChunk:
+		return "snapshot_chunk"
+	case BlobTypeForcedTx:
+		return "forced_tx"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(t))
+	}
+}
+
+// SealBlob wraps data in an envelope tagged with the blob type.
+func SealBlob(t BlobType, data []byte) []byte {
+	blob := make([]byte, 0, len(envelopeMagic)+1+len(data))
+	blob = append(blob, envelopeMagic...)
+	blob = append(blob, byte(t))
+	return append(blob, data...)
+}
+
+// OpenBlob returns the type and the data of a blob wrapped in an envelope.
+func OpenBlob(blob []byte) (BlobType, []byte, error) {
+	if len(blob) <= len(envelopeMagic) || !bytes.HasPrefix(blob, envelopeMagic) {
+		return 0, nil, ErrInvalidEnvelope
+	}
+	t := BlobType(blob[len(envelopeMagic)])
+	if t < BlobTypeBlock || t > BlobTypeForcedTx {
+		return 0, nil, fmt.Errorf("%w: unknown blob type %d", ErrInvalidEnvelope, byte(t))
+	}
+	return t, blob[len(envelopeMagic)+1:], nil
+}
+
+// BlobHandler processes data of a blob retrieved from DA layer at given height.
+type BlobHandler func(daHeight uint64, data []byte)
+
+// Mux shares a single namespace of BlobClient between multiple types of data. Every blob is submitted in an
+// envelope tagged with its type, and retrieved blobs are demultiplexed by their types. Blobs without a valid envelope
+// (anyone can post blobs in the namespace) are skipped.
+type Mux struct {
+	client BlobClient
+	logger log.Logger
+
+	mtx      sync.RWMutex
+	handlers map[BlobType]BlobHandler
+}
+
+// NewMux creates Mux sharing the namespace of the client.
+func NewMux(client BlobClient, logger log.Logger) *Mux {
+	return &Mux{
+		client:   client,
+		logger:   logger,
+		handlers: make(map[BlobType]BlobHandler),
+	}
+}
+
+// Handle registers handler of blobs of given type found while retrieving blocks. Blocks are retrieved from every
+// DA height, so handlers are routed blobs that no subsystem retrieves on its own (like fraud proofs).
+func (m *Mux) Handle(t BlobType, handler BlobHandler) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.handlers[t] = handler
+}
+
+// Blobs returns BlobClient submitting and retrieving only blobs of given type.
+func (m *Mux) Blobs(t BlobType) BlobClient {
+	return &muxBlobClient{mux: m, blobType: t}
+}
+
+// Blocks returns DA layer client submitting and retrieving blocks as blobs of BlobTypeBlock. Life-cycle methods are
+// delegated to client.
+func (m *Mux) Blocks(client DataAvailabilityLayerClient) *MuxBlockClient {
+	return &MuxBlockClient{DataAvailabilityLayerClient: client, mux: m}
+}
+
+func (m *Mux) submit(ctx context.Context, t BlobType, data [][]byte) ResultSubmitBlocks {
+	blobs := make([][]byte, len(data))
+	for i, d := range data {
+		blobs[i] = SealBlob(t, d)
+	}
+	return m.client.SubmitBlobs(ctx, blobs)
+}
+
+// retrieve returns data of blobs of given type at DA height. If route is true, blobs of other types are passed to
+// their handlers.
+func (m *Mux) retrieve(ctx context.Context, t BlobType, daHeight uint64, route bool) ResultRetrieveBlobs {
+	res := m.client.RetrieveBlobs(ctx, daHeight)
+	if res.Code != StatusSuccess {
+		return res
+	}
+	data := make([][]byte, 0, len(res.Blobs))
+	for i, blob := range res.Blobs {
+		blobType, d, err := OpenBlob(blob)
+		if err != nil {
+			m.logger.Debug("skipping blob", "daHeight", daHeight, "position", i, "error", err)
+			continue
+		}
+		if blobType == t {
+			data = append(data, d)
+			continue
+		}
+		if !route {
+			continue
+		}
+		m.mtx.RLock()
+		handler, ok := m.handlers[blobType]
+		m.mtx.RUnlock()
+		if !ok {
+			m.logger.Debug("no handler of blob type", "daHeight", daHeight, "position", i, "type", blobType)
+			continue
+		}
+		handler(daHeight, d)
+	}
+	res.Blobs = data
+	return res
+}
+
+// muxBlobClient is BlobClient of a single blob type in a shared namespace.
+type muxBlobClient struct {
+	mux      *Mux
+	blobType BlobType
+}
+
+var _ BlobClient = &muxBlobClient{}
+
+func (c *muxBlobClient) SubmitBlobs(ctx context.Context, blobs [][]byte) ResultSubmitBlocks {
+	return c.mux.submit(ctx, c.blobType, blobs)
+}
+
+func (c *muxBlobClient) RetrieveBlobs(ctx context.Context, daHeight uint64) ResultRetrieveBlobs {
+	return c.mux.retrieve(ctx, c.blobType, daHeight, false)
+}
+
+// MuxBlockClient is DA layer client of blocks in a shared namespace.
+type MuxBlockClient struct {
+	DataAvailabilityLayerClient
+	mux *Mux
+}
+
+var _ DataAvailabilityLayerClient = &MuxBlockClient{}
+var _ BlockRetriever = &MuxBlockClient{}
+
+// SubmitBlocks submits blocks as blobs of BlobTypeBlock.
+func (c *MuxBlockClient) SubmitBlocks(ctx context.Context, blocks []*types.Block) ResultSubmitBlocks {
+	data := make([][]byte, len(blocks))
+	for i, block := range blocks {
+		var err error
+		data[i], err = block.MarshalBinary()
+		if err != nil {
+			return ResultSubmitBlocks{BaseResult: BaseResult{Code: StatusError, Message: err.Error()}}
+		}
+	}
+	return c.mux.submit(ctx, BlobTypeBlock, data)
+}
+
+// RetrieveBlocks returns blocks at given DA height. Blobs of other types are routed to their handlers.
+func (c *MuxBlockClient) RetrieveBlocks(ctx context.Context, daHeight uint64) ResultRetrieveBlocks {
+	res := c.mux.retrieve(ctx, BlobTypeBlock, daHeight, true)
+	if res.Code != StatusSuccess {
+		return ResultRetrieveBlocks{BaseResult: res.BaseResult}
+	}
+	blocks := make([]*types.Block, 0, len(res.Blobs))
+	for i, data := range res.Blobs {
+		block := new(types.Block)
+		if err := block.UnmarshalBinary(data); err != nil {
+			c.mux.logger.Error("failed to unmarshal block", "daHeight", daHeight, "position", i, "error", err)
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return ResultRetrieveBlocks{BaseResult: res.BaseResult, Blocks: blocks}
+}
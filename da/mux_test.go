@@ -0,0 +1,98 @@
+package da
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// memBlobClient is in-memory BlobClient, submitting every batch of blobs at the next DA height.
+type memBlobClient struct {
+	height uint64
+	blobs  map[uint64][][]byte
+}
+
+func (c *memBlobClient) SubmitBlobs(_ context.Context, blobs [][]byte) ResultSubmitBlocks {
+	c.height++
+	c.blobs[c.height] = blobs
+	return ResultSubmitBlocks{BaseResult: BaseResult{Code: StatusSuccess, DAHeight: c.height}}
+}
+
+func (c *memBlobClient) RetrieveBlobs(_ context.Context, height uint64) ResultRetrieveBlobs {
+	return ResultRetrieveBlobs{BaseResult: BaseResult{Code: StatusSuccess, DAHeight: height}, Blobs: c.blobs[height]}
+}
+
+func TestEnvelope(t *testing.T) {
+	for _, blobType := range []BlobType{BlobTypeBlock, BlobTypeHeader, BlobTypeFraudProof, BlobTypeSnapshotChunk, BlobTypeForcedTx} {
+		typ, data, err := OpenBlob(SealBlob(blobType, []byte("data")))
+		require.NoError(t, err)
+		assert.Equal(t, blobType, typ)
+		assert.Equal(t, []byte("data"), data)
+	}
+	typ, data, err := OpenBlob(SealBlob(BlobTypeHeader, nil))
+	require.NoError(t, err)
+	assert.Equal(t, BlobTypeHeader, typ)
+	assert.Empty(t, data)
+
+	for _, blob := range [][]byte{nil, []byte("RKB"), []byte("data"), SealBlob(0, []byte("data")), SealBlob(BlobTypeForcedTx+1, nil)} {
+		_, _, err := OpenBlob(blob)
+		assert.ErrorIs(t, err, ErrInvalidEnvelope)
+	}
+}
+
+func TestMux(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	client := &memBlobClient{blobs: make(map[uint64][][]byte)}
+	mux := NewMux(client, test.NewFileLogger(t))
+	var proofs []string
+	mux.Handle(BlobTypeFraudProof, func(daHeight uint64, data []byte) {
+		assert.Equal(uint64(1), daHeight)
+		proofs = append(proofs, string(data))
+	})
+	blocks := mux.Blocks(nil)
+	snapshots := mux.Blobs(BlobTypeSnapshotChunk)
+
+	// blobs of all types are submitted at the same DA height
+	block := types.GetRandomBlock(1, 2)
+	data, err := block.MarshalBinary()
+	require.NoError(err)
+	client.blobs[1] = [][]byte{
+		SealBlob(BlobTypeSnapshotChunk, []byte("chunk")),
+		SealBlob(BlobTypeBlock, data),
+		[]byte("garbage"),
+		SealBlob(BlobTypeFraudProof, []byte("proof")),
+		SealBlob(BlobTypeBlock, []byte("malformed block")),
+		SealBlob(BlobTypeForcedTx, []byte("tx")),
+	}
+	client.height = 1
+
+	res := blocks.RetrieveBlocks(ctx, 1)
+	require.Equal(StatusSuccess, res.Code)
+	require.Len(res.Blocks, 1)
+	assert.Equal(block.Hash(), res.Blocks[0].Hash())
+	assert.Equal([]string{"proof"}, proofs)
+
+	blobs := snapshots.RetrieveBlobs(ctx, 1)
+	require.Equal(StatusSuccess, blobs.Code)
+	assert.Equal([][]byte{[]byte("chunk")}, blobs.Blobs)
+	// blobs are routed to handlers only while retrieving blocks
+	assert.Len(proofs, 1)
+
+	// submitted blobs are tagged with their type
+	submitted := blocks.SubmitBlocks(ctx, []*types.Block{block})
+	require.Equal(StatusSuccess, submitted.Code)
+	assert.Equal([][]byte{SealBlob(BlobTypeBlock, data)}, client.blobs[2])
+	submitted = snapshots.SubmitBlobs(ctx, [][]byte{[]byte("chunk")})
+	require.Equal(StatusSuccess, submitted.Code)
+	assert.Equal([][]byte{SealBlob(BlobTypeSnapshotChunk, []byte("chunk"))}, client.blobs[3])
+	assert.Empty(blocks.RetrieveBlocks(ctx, 3).Blocks)
+	assert.Empty(snapshots.RetrieveBlobs(ctx, 2).Blobs)
+}
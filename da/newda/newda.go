@@ -5,9 +5,6 @@ import (
 	"encoding/binary"
 	"fmt"
 
-	pb "github.com/rollkit/rollkit/types/pb/rollkit"
-
-	"github.com/gogo/protobuf/proto"
 	ds "github.com/ipfs/go-datastore"
 
 	newda "github.com/rollkit/go-da"
@@ -22,6 +19,8 @@ type NewDA struct {
 	logger log.Logger
 }
 
+var _ da.SharedClient = &NewDA{}
+
 // Init is called once to allow DA client to read configuration and initialize resources.
 func (n *NewDA) Init(namespaceID types.NamespaceID, config []byte, kvStore ds.Datastore, logger log.Logger) error {
 	n.logger = logger
@@ -38,6 +37,12 @@ func (n *NewDA) Stop() error {
 	return nil
 }
 
+// WithNamespace returns the same client, as the DA interface doesn't separate namespaces; all rollups sharing
+// the client retrieve blocks of each other.
+func (n *NewDA) WithNamespace(types.NamespaceID) (da.DataAvailabilityLayerClient, error) {
+	return n, nil
+}
+
 // SubmitBlocks submits blocks to DA.
 func (n *NewDA) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
 	blobs := make([][]byte, len(blocks))
@@ -95,24 +100,15 @@ func (n *NewDA) RetrieveBlocks(ctx context.Context, dataLayerHeight uint64) da.R
 		}
 	}
 
-	blocks := make([]*types.Block, len(blobs))
+	blocks := make([]*types.Block, 0, len(blobs))
 	for i, blob := range blobs {
-		var block pb.Block
-		err = proto.Unmarshal(blob, &block)
-		if err != nil {
+		// anyone can post blobs in the namespace, so malformed blobs are skipped
+		block := new(types.Block)
+		if err := block.UnmarshalBinary(blob); err != nil {
 			n.logger.Error("failed to unmarshal block", "daHeight", dataLayerHeight, "position", i, "error", err)
 			continue
 		}
-		blocks[i] = new(types.Block)
-		err := blocks[i].FromProto(&block)
-		if err != nil {
-			return da.ResultRetrieveBlocks{
-				BaseResult: da.BaseResult{
-					Code:    da.StatusError,
-					Message: err.Error(),
-				},
-			}
-		}
+		blocks = append(blocks, block)
 	}
 
 	return da.ResultRetrieveBlocks{
@@ -0,0 +1,318 @@
+// Package events forwards events of applied blocks (header, transactions and ABCI results) from the event bus
+// of the node to external sinks, like webhooks, NATS or Kafka, so that external indexers can consume the chain
+// without polling RPC.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/log"
+	cmtypes "github.com/cometbft/cometbft/types"
+)
+
+const (
+	subscriber = "EventPublisher"
+	// queueSize is the number of block events buffered for every sink. Events are dropped if a sink falls behind
+	// by more blocks.
+	queueSize = 1000
+	// maxAttempts limits delivery attempts of a single event to a sink.
+	maxAttempts = 5
+	// retryBackoff is the delay before the first retry of a failed delivery, doubled with every retry.
+	retryBackoff = time.Second
+	// resubscribeBackoff is the delay before subscribing again after the event bus canceled subscriptions.
+	resubscribeBackoff = time.Second
+)
+
+// ErrUnknownSink is returned when sink URL has unsupported scheme.
+var ErrUnknownSink = errors.New("unknown event sink")
+
+// BlockEvent describes an applied block, with results of its execution.
+type BlockEvent struct {
+	ChainID          string                   `json:"chain_id"`
+	Height           int64                    `json:"height"`
+	Hash             cmbytes.HexBytes         `json:"hash"`
+	Header           cmtypes.Header           `json:"header"`
+	Txs              []cmtypes.Tx             `json:"txs"`
+	TxResults        []abci.ResponseDeliverTx `json:"tx_results"`
+	BeginBlockEvents []abci.Event             `json:"begin_block_events"`
+	EndBlockEvents   []abci.Event             `json:"end_block_events"`
+	ValidatorUpdates []abci.ValidatorUpdate   `json:"validator_updates"`
+}
+
+// Sink delivers block events to an external system. Events are delivered to a sink in order of heights, one at
+// a time; failed deliveries are retried.
+type Sink interface {
+	// Publish delivers the event. It returns after the event was accepted by the external system.
+	Publish(ctx context.Context, ev *BlockEvent) error
+	// Close releases resources of the sink.
+	Close() error
+}
+
+// NewSink creates a sink from its URL:
+//   - http://... or https://... POSTs events as JSON to the URL (webhook),
+//   - nats://[user:pass@]host:port/subject publishes events to the NATS subject,
+//   - kafka+http://host:port/topic (or kafka+https) produces events to the Kafka topic, through Kafka REST Proxy
+//     listening at host:port (Kafka wire protocol is not supported).
+func NewSink(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownSink, err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return NewWebhookSink(rawURL), nil
+	case "nats":
+		return NewNATSSink(u)
+	case "kafka+http", "kafka+https":
+		return NewKafkaSink(u)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSink, rawURL)
+	}
+}
+
+// Publisher subscribes to events of applied blocks on the event bus, and forwards them to sinks.
+type Publisher struct {
+	eventBus *cmtypes.EventBus
+	chainID  string
+	sinks    []Sink
+	logger   log.Logger
+
+	blocksSub cmtypes.Subscription
+	txsSub    cmtypes.Subscription
+}
+
+// NewPublisher creates Publisher forwarding events from the event bus to the sinks.
+func NewPublisher(eventBus *cmtypes.EventBus, chainID string, sinks []Sink, logger log.Logger) *Publisher {
+	return &Publisher{eventBus: eventBus, chainID: chainID, sinks: sinks, logger: logger}
+}
+
+// Start subscribes to events of new blocks and transactions.
+func (p *Publisher) Start(ctx context.Context) error {
+	var err error
+	p.blocksSub, err = p.eventBus.Subscribe(ctx, subscriber, cmtypes.EventQueryNewBlock, 100)
+	if err != nil {
+		return err
+	}
+	p.txsSub, err = p.eventBus.Subscribe(ctx, subscriber, cmtypes.EventQueryTx, 10000)
+	return err
+}
+
+// Run forwards block events to the sinks until context is canceled. Every sink has its own queue, so a slow
+// sink doesn't delay delivery to other sinks. If the event bus cancels subscriptions (e.g. because the publisher
+// fell behind), Run subscribes again; events of blocks applied in the meantime are lost.
+func (p *Publisher) Run(ctx context.Context) {
+	queues := make([]chan *BlockEvent, len(p.sinks))
+	for i, sink := range p.sinks {
+		queues[i] = make(chan *BlockEvent, queueSize)
+		go p.deliver(ctx, sink, queues[i])
+	}
+	publish := func(ev *BlockEvent) {
+		for i, queue := range queues {
+			select {
+			case queue <- ev:
+			default:
+				p.logger.Error("event sink queue is full, dropping block event", "sink", i, "height", ev.Height)
+			}
+		}
+	}
+	for {
+		err := p.forward(ctx, publish)
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Error("event subscription canceled, subscribing again; events of blocks applied in the meantime are lost", "error", err)
+		if !p.resubscribe(ctx) {
+			return
+		}
+	}
+}
+
+// forward assembles block events from events of new blocks and transactions, and passes them to publish. It returns
+// when context is canceled, or a subscription is canceled by the event bus.
+//
+// Transaction events of a block are published by the executor after the block event and before the next block
+// event, and subscriptions deliver them in order of publishing. Block event is complete once results of all its
+// transactions were received, or when a transaction event of a later block is received; results that are still
+// missing are left empty.
+func (p *Publisher) forward(ctx context.Context, publish func(*BlockEvent)) error {
+	var (
+		pending    *BlockEvent
+		received   []bool
+		missing    int
+		lastHeight int64
+	)
+	flush := func() {
+		if pending == nil {
+			return
+		}
+		if missing > 0 {
+			p.logger.Error("missing results of transactions in block event", "height", pending.Height, "missing", missing)
+		}
+		publish(pending)
+		pending = nil
+	}
+	start := func(data cmtypes.EventDataNewBlock) {
+		flush()
+		pending = &BlockEvent{
+			ChainID:          p.chainID,
+			Height:           data.Block.Height,
+			Hash:             data.Block.Hash(),
+			Header:           data.Block.Header,
+			Txs:              data.Block.Txs,
+			TxResults:        make([]abci.ResponseDeliverTx, len(data.Block.Txs)),
+			BeginBlockEvents: data.ResultBeginBlock.Events,
+			EndBlockEvents:   data.ResultEndBlock.Events,
+			ValidatorUpdates: data.ResultEndBlock.ValidatorUpdates,
+		}
+		received = make([]bool, len(data.Block.Txs))
+		missing = len(data.Block.Txs)
+		lastHeight = data.Block.Height
+		if missing == 0 {
+			flush()
+		}
+	}
+	addTx := func(txResult abci.TxResult) {
+		if pending == nil || txResult.Height != pending.Height || int(txResult.Index) >= len(received) || received[txResult.Index] {
+			p.logger.Debug("unexpected transaction event", "height", txResult.Height, "index", txResult.Index)
+			return
+		}
+		pending.TxResults[txResult.Index] = txResult.Result
+		received[txResult.Index] = true
+		missing--
+		if missing == 0 {
+			flush()
+		}
+	}
+	// handleTx adds the transaction result, starting blocks of later heights first
+	handleTx := func(txResult abci.TxResult) error {
+		for txResult.Height > lastHeight {
+			// events of the block were published before, so they're already delivered
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-p.blocksSub.Cancelled():
+				return fmt.Errorf("block events: %w", p.blocksSub.Err())
+			case msg := <-p.blocksSub.Out():
+				start(msg.Data().(cmtypes.EventDataNewBlock))
+			}
+		}
+		addTx(txResult)
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.blocksSub.Cancelled():
+			return fmt.Errorf("block events: %w", p.blocksSub.Err())
+		case <-p.txsSub.Cancelled():
+			return fmt.Errorf("transaction events: %w", p.txsSub.Err())
+		case msg := <-p.txsSub.Out():
+			if err := handleTx(msg.Data().(cmtypes.EventDataTx).TxResult); err != nil {
+				return err
+			}
+		case msg := <-p.blocksSub.Out():
+			next := msg.Data().(cmtypes.EventDataNewBlock)
+			// remaining transaction events of the pending block were published before, so they're already delivered
+			for drained := false; pending != nil && !drained && lastHeight < next.Block.Height; {
+				select {
+				case msg := <-p.txsSub.Out():
+					txResult := msg.Data().(cmtypes.EventDataTx).TxResult
+					if txResult.Height >= next.Block.Height {
+						start(next)
+						if err := handleTx(txResult); err != nil {
+							return err
+						}
+						continue
+					}
+					addTx(txResult)
+				default:
+					drained = true
+				}
+			}
+			if lastHeight < next.Block.Height {
+				start(next)
+			}
+		}
+	}
+}
+
+// resubscribe drops subscriptions and subscribes again after resubscribeBackoff, retrying until it succeeds. It
+// returns false if context is canceled.
+//
+// The delay also lets whoever canceled the subscriptions finish: UnsubscribeAll of the event bus forgets
+// subscriptions of the client only after they're canceled, including subscriptions made in the meantime.
+func (p *Publisher) resubscribe(ctx context.Context) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(resubscribeBackoff):
+		}
+		_ = p.eventBus.UnsubscribeAll(ctx, subscriber)
+		err := p.Start(ctx)
+		if err == nil {
+			return true
+		}
+		p.logger.Error("failed to subscribe to events", "error", err)
+	}
+}
+
+// deliver publishes events from the queue to the sink, retrying failed deliveries.
+func (p *Publisher) deliver(ctx context.Context, sink Sink, queue <-chan *BlockEvent) {
+	for {
+		var ev *BlockEvent
+		select {
+		case <-ctx.Done():
+			return
+		case ev = <-queue:
+		}
+		backoff := retryBackoff
+		for attempt := 1; ; attempt++ {
+			err := sink.Publish(ctx, ev)
+			if err == nil {
+				break
+			}
+			if attempt == maxAttempts {
+				p.logger.Error("failed to deliver block event, dropping it", "height", ev.Height, "error", err)
+				break
+			}
+			p.logger.Debug("failed to deliver block event, retrying", "height", ev.Height, "attempt", attempt, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+// Stop unsubscribes from the event bus and closes the sinks.
+func (p *Publisher) Stop() error {
+	var err error
+	if p.eventBus.IsRunning() {
+		err = p.eventBus.UnsubscribeAll(context.Background(), subscriber)
+	}
+	return errors.Join(err, closeSinks(p.sinks))
+}
+
+// NewSinks creates sinks from their URLs. Sinks created before an error are closed.
+func NewSinks(urls []string) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(urls))
+	for _, u := range urls {
+		sink, err := NewSink(u)
+		if err != nil {
+			_ = closeSinks(sinks)
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
@@ -0,0 +1,172 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// sinkTimeout limits duration of a single delivery to a sink.
+const sinkTimeout = 10 * time.Second
+
+// WebhookSink POSTs block events as JSON to the URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+var _ Sink = &WebhookSink{}
+
+// NewWebhookSink creates WebhookSink posting events to the URL.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: sinkTimeout}}
+}
+
+// Publish POSTs the event to the webhook. Delivery fails unless webhook responds with 2xx status.
+func (s *WebhookSink) Publish(ctx context.Context, ev *BlockEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.client, s.url, "application/json", body)
+}
+
+// Close implements Sink.
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// KafkaSink produces block events to a Kafka topic through Kafka REST Proxy (v2 API). It doesn't speak the Kafka
+// wire protocol, so brokers can't be used directly: a REST Proxy has to be deployed in front of the cluster. Height
+// of the block is used as the key of the record.
+type KafkaSink struct {
+	url    string
+	client *http.Client
+}
+
+var _ Sink = &KafkaSink{}
+
+// kafkaRecords is the body of produce request of Kafka REST Proxy.
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   int64       `json:"key"`
+	Value *BlockEvent `json:"value"`
+}
+
+// NewKafkaSink creates KafkaSink from URL like kafka+http://host:port/topic, where host:port is the address of
+// Kafka REST Proxy.
+func NewKafkaSink(u *url.URL) (*KafkaSink, error) {
+	topic := strings.Trim(u.Path, "/")
+	if topic == "" || strings.Contains(topic, "/") {
+		return nil, fmt.Errorf("%w: kafka URL must define single topic: %s", ErrUnknownSink, u.Redacted())
+	}
+	proxy := *u
+	proxy.Scheme = strings.TrimPrefix(u.Scheme, "kafka+")
+	proxy.Path = "/topics/" + topic
+	return &KafkaSink{url: proxy.String(), client: &http.Client{Timeout: sinkTimeout}}, nil
+}
+
+// Publish produces the event to the topic.
+func (s *KafkaSink) Publish(ctx context.Context, ev *BlockEvent) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: ev.Height, Value: ev}}})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.client, s.url, "application/vnd.kafka.json.v2+json", body)
+}
+
+// Close implements Sink.
+func (s *KafkaSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+func post(ctx context.Context, client *http.Client, url, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return nil
+}
+
+// NATSSink publishes block events as JSON to a NATS subject. Connection is maintained by the NATS client, which
+// reconnects after failures; an event published while reconnecting may be delivered more than once.
+type NATSSink struct {
+	subject string
+	conn    *nats.Conn
+}
+
+var _ Sink = &NATSSink{}
+
+// NewNATSSink creates NATSSink from URL like nats://[user:pass@]host[:port]/subject. Server doesn't have to be
+// reachable, connection is retried in the background.
+func NewNATSSink(u *url.URL) (*NATSSink, error) {
+	subject := strings.Trim(u.Path, "/")
+	if subject == "" || strings.ContainsAny(subject, "/ \t") {
+		return nil, fmt.Errorf("%w: NATS URL must define subject: %s", ErrUnknownSink, u.Redacted())
+	}
+	server := *u
+	server.Path = ""
+	conn, err := nats.Connect(server.String(),
+		nats.Name("rollkit"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NATS client for %s: %w", u.Redacted(), err)
+	}
+	return &NATSSink{subject: subject, conn: conn}, nil
+}
+
+// Publish publishes the event to the subject. It returns after the server acknowledged receiving the message.
+func (s *NATSSink) Publish(ctx context.Context, ev *BlockEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := s.conn.Publish(s.subject, body); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
+	defer cancel()
+	// PONG to the flush PING guarantees that server processed the message
+	return s.conn.FlushWithContext(ctx)
+}
+
+// Close closes connection to the server.
+func (s *NATSSink) Close() error {
+	s.conn.Close()
+	return nil
+}
+
+// closeSinks closes all the sinks, returning joined errors.
+func closeSinks(sinks []Sink) error {
+	var err error
+	for _, sink := range sinks {
+		err = errors.Join(err, sink.Close())
+	}
+	return err
+}
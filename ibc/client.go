@@ -0,0 +1,99 @@
+// Package ibc provides the pieces required by an IBC light client of a Rollkit chain: client and consensus
+// states, verification of headers, and proofs of aggregator (sequencer) sets served by full nodes.
+package ibc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+var (
+	// ErrChainIDMismatch is returned when the header belongs to a different chain than the client.
+	ErrChainIDMismatch = errors.New("chain ID mismatch")
+	// ErrNonIncreasingHeader is returned when the header is not newer than the trusted consensus state.
+	ErrNonIncreasingHeader = errors.New("header height or time not increasing")
+	// ErrTrustingPeriodExpired is returned when the trusted consensus state is outside the trusting period.
+	ErrTrustingPeriodExpired = errors.New("trusted consensus state outside trusting period")
+	// ErrAggregatorSetChanged is returned when the header is signed by an aggregator set different from the one
+	// trusted by the client. Headers of heights at which the set changed have to be verified first.
+	ErrAggregatorSetChanged = errors.New("aggregator set differs from the trusted one")
+)
+
+// ClientState describes a light client of a Rollkit chain.
+type ClientState struct {
+	ChainID string `json:"chain_id"`
+	// TrustingPeriod is the duration since the time of the latest trusted header, in which new headers can be
+	// verified. Zero disables expiration.
+	TrustingPeriod time.Duration `json:"trusting_period"`
+	// CommitThreshold is the fraction of the voting power of the aggregator set that has to be exceeded by
+	// signatures of headers. Zero value means types.DefaultCommitThreshold.
+	CommitThreshold cmtmath.Fraction `json:"commit_threshold"`
+	// LatestHeight is the height of the latest verified header.
+	LatestHeight uint64 `json:"latest_height"`
+}
+
+// ConsensusState is the state of the chain trusted by the client at some height.
+type ConsensusState struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Root is the application state root (AppHash) of the header, i.e. the root of the state after executing
+	// the previous block. Proofs of application state are verified against it.
+	Root cmbytes.HexBytes `json:"root"`
+	// NextAggregatorsHash is the hash of the aggregator set signing the next header.
+	NextAggregatorsHash cmbytes.HexBytes `json:"next_aggregators_hash"`
+}
+
+// NewConsensusState returns the consensus state of the verified header.
+func NewConsensusState(sh *types.SignedHeader) *ConsensusState {
+	return &ConsensusState{
+		Timestamp:           sh.Time(),
+		Root:                cmbytes.HexBytes(sh.AppHash),
+		NextAggregatorsHash: cmbytes.HexBytes(sh.NextAggregatorsHash),
+	}
+}
+
+// VerifyHeader verifies the header against the consensus state trusted at trustedHeight, at given time.
+//
+// Header has to be signed by the aggregator set trusted by the consensus state (sequencer sets change only
+// with validator updates of the application), so headers can be skipped as long as the set doesn't change.
+// If the set changed, ErrAggregatorSetChanged is returned and the header of the height at which it changed
+// has to be verified first.
+func (cs *ClientState) VerifyHeader(trusted *ConsensusState, trustedHeight uint64, sh *types.SignedHeader, now time.Time) error {
+	if sh.ChainID() != cs.ChainID {
+		return fmt.Errorf("%w: expected %q, got %q", ErrChainIDMismatch, cs.ChainID, sh.ChainID())
+	}
+	if cs.TrustingPeriod > 0 && !trusted.Timestamp.Add(cs.TrustingPeriod).After(now) {
+		return fmt.Errorf("%w: trusted at %s, now %s", ErrTrustingPeriodExpired, trusted.Timestamp, now)
+	}
+	if sh.Height() <= trustedHeight || !sh.Time().After(trusted.Timestamp) {
+		return fmt.Errorf("%w: trusted height %d, header height %d", ErrNonIncreasingHeader, trustedHeight, sh.Height())
+	}
+	if err := sh.ValidateBasic(); err != nil {
+		return err
+	}
+	if !bytes.Equal(sh.AggregatorsHash[:], trusted.NextAggregatorsHash) {
+		return fmt.Errorf("%w: expected %X, got %X", ErrAggregatorSetChanged, trusted.NextAggregatorsHash, sh.AggregatorsHash)
+	}
+	if err := sh.VerifyProposer(); err != nil {
+		return err
+	}
+	return sh.VerifyCommit(cs.CommitThreshold)
+}
+
+// Update verifies the header and returns the new consensus state. LatestHeight of the client is updated if
+// the header is newer.
+func (cs *ClientState) Update(trusted *ConsensusState, trustedHeight uint64, sh *types.SignedHeader, now time.Time) (*ConsensusState, error) {
+	if err := cs.VerifyHeader(trusted, trustedHeight, sh, now); err != nil {
+		return nil, err
+	}
+	if sh.Height() > cs.LatestHeight {
+		cs.LatestHeight = sh.Height()
+	}
+	return NewConsensusState(sh), nil
+}
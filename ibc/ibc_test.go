@@ -0,0 +1,155 @@
+package ibc
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestVerifyHeader(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	g := types.NewGenerator(1, types.WithNumValidators(3))
+	trusted, keys, err := g.SignedHeader()
+	require.NoError(err)
+	next, err := g.NextSignedHeader(trusted, keys)
+	require.NoError(err)
+
+	cs := &ClientState{ChainID: types.TestChainID, TrustingPeriod: time.Hour}
+	consensus := NewConsensusState(trusted)
+	now := types.GeneratorTime.Add(time.Minute)
+
+	updated, err := cs.Update(consensus, trusted.Height(), next, now)
+	require.NoError(err)
+	assert.Equal(next.Height(), cs.LatestHeight)
+	assert.Equal(next.Time(), updated.Timestamp)
+	assert.Equal([]byte(next.AppHash), []byte(updated.Root))
+
+	assert.ErrorIs(cs.VerifyHeader(updated, next.Height(), trusted, now), ErrNonIncreasingHeader)
+	assert.ErrorIs(cs.VerifyHeader(consensus, trusted.Height(), next, now.Add(time.Hour)), ErrTrustingPeriodExpired)
+
+	other := &ClientState{ChainID: "other"}
+	assert.ErrorIs(other.VerifyHeader(consensus, trusted.Height(), next, now), ErrChainIDMismatch)
+
+	// header signed by a different aggregator set
+	foreign, _, err := types.NewGenerator(2, types.WithTime(now)).SignedHeader()
+	require.NoError(err)
+	assert.ErrorIs(cs.VerifyHeader(consensus, 0, foreign, now), ErrAggregatorSetChanged)
+}
+
+func TestAggregatorSetProof(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sh, _, err := types.NewGenerator(3, types.WithNumValidators(4)).SignedHeader()
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := store.New(ctx, kv)
+	require.NoError(s.SaveBlock(&types.Block{SignedHeader: *sh}, &sh.Commit))
+
+	provider := NewProvider(s)
+	proof, err := provider.AggregatorSetProof(sh.Height())
+	require.NoError(err)
+	assert.NoError(proof.Verify(sh.AggregatorsHash))
+	assert.ErrorIs(proof.Verify(types.GetRandomBytes(32)), ErrInvalidAggregatorProof)
+	assert.NoError(VerifyAggregator(sh.AggregatorsHash, proof.Aggregators.Validators[1], proof.Proofs[1]))
+	assert.ErrorIs(VerifyAggregator(sh.AggregatorsHash, proof.Aggregators.Validators[1], proof.Proofs[2]), ErrInvalidAggregatorProof)
+
+	consensus, err := provider.ConsensusState(sh.Height())
+	require.NoError(err)
+	assert.Equal(NewConsensusState(sh), consensus)
+}
+
+// kvLeaf returns the leaf proven by simple Merkle value operation of the key.
+func kvLeaf(key, value []byte) []byte {
+	var leaf []byte
+	for _, bz := range [][]byte{key, tmhash.Sum(value)} {
+		leaf = binary.AppendUvarint(leaf, uint64(len(bz)))
+		leaf = append(leaf, bz...)
+	}
+	return leaf
+}
+
+func TestStateProof(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// multistore-like state: app hash commits to the root of "bank" store, which commits to the balance
+	value := []byte("1000stake")
+	storeRoot, storeProofs := merkle.ProofsFromByteSlices([][]byte{kvLeaf([]byte("balance"), value), kvLeaf([]byte("other"), []byte("x"))})
+	appHash, appProofs := merkle.ProofsFromByteSlices([][]byte{kvLeaf([]byte("acc"), []byte("y")), kvLeaf([]byte("bank"), storeRoot)})
+	ops := &cmcrypto.ProofOps{Ops: []cmcrypto.ProofOp{
+		merkle.NewValueOp([]byte("balance"), storeProofs[0]).ProofOp(),
+		merkle.NewValueOp([]byte("bank"), appProofs[1]).ProofOp(),
+	}}
+
+	// state after block 1 is committed to by header 2
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, err := store.NewDefaultInMemoryKVStore()
+	require.NoError(err)
+	s := store.New(ctx, kv)
+	for h := uint64(1); h <= 3; h++ {
+		block := types.GetRandomBlock(h, 1)
+		if h == 2 {
+			block.SignedHeader.AppHash = appHash
+		}
+		require.NoError(s.SaveBlock(block, &types.Commit{}))
+		s.SetHeight(h)
+	}
+
+	app := &mocks.Application{}
+	app.On("Query", mock.MatchedBy(func(req abci.RequestQuery) bool {
+		return req.Prove && req.Path == "/store/bank/key"
+	})).Return(func(req abci.RequestQuery) abci.ResponseQuery {
+		height := req.Height
+		if height == 0 {
+			height = 3
+		}
+		return abci.ResponseQuery{Key: req.Data, Value: value, ProofOps: ops, Height: height}
+	})
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app), proxy.NopMetrics())
+	require.NoError(proxyApp.Start())
+	defer func() { _ = proxyApp.Stop() }()
+
+	provider := NewProvider(s)
+	proof, err := provider.StateProof(proxyApp.Query(), "bank", []byte("balance"), 1)
+	require.NoError(err)
+	assert.Equal(uint64(1), proof.Height)
+	assert.Equal([]byte(appHash), []byte(proof.Root))
+	assert.NoError(proof.Verify(nil, proof.Root))
+
+	header, err := provider.SignedHeader(2)
+	require.NoError(err)
+	assert.NoError(proof.Verify(nil, NewConsensusState(header).Root))
+	assert.ErrorIs(proof.Verify(nil, types.GetRandomBytes(32)), ErrInvalidStateProof)
+
+	proof.Value = []byte("1000000stake")
+	assert.ErrorIs(proof.Verify(nil, appHash), ErrInvalidStateProof)
+	proof.Value = value
+	proof.Store = "staking"
+	assert.ErrorIs(proof.Verify(nil, appHash), ErrInvalidStateProof)
+
+	// state of the latest block isn't committed to by any header yet
+	_, err = provider.StateProof(proxyApp.Query(), "bank", []byte("balance"), 0)
+	assert.ErrorIs(err, ErrStateRootNotCommitted)
+}
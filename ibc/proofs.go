@@ -0,0 +1,111 @@
+package ibc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// ErrInvalidAggregatorProof is returned when the aggregator set doesn't match the proven hash.
+var ErrInvalidAggregatorProof = errors.New("invalid aggregator set proof")
+
+// AggregatorSetProof proves the aggregator set (sequencer set) of the header at given height: the hash of the set
+// is committed to by AggregatorsHash of the header, and every aggregator has a Merkle proof of membership.
+type AggregatorSetProof struct {
+	Height          uint64                `json:"height"`
+	AggregatorsHash cmbytes.HexBytes      `json:"aggregators_hash"`
+	Aggregators     *cmtypes.ValidatorSet `json:"aggregators"`
+	Proofs          []*merkle.Proof       `json:"proofs"`
+}
+
+// NewAggregatorSetProof creates proof of the aggregator set at given height.
+func NewAggregatorSetProof(height uint64, aggregators *cmtypes.ValidatorSet) *AggregatorSetProof {
+	root, proofs := merkle.ProofsFromByteSlices(aggregatorLeaves(aggregators))
+	return &AggregatorSetProof{
+		Height:          height,
+		AggregatorsHash: root,
+		Aggregators:     aggregators,
+		Proofs:          proofs,
+	}
+}
+
+// Verify checks the proof against AggregatorsHash of the header at the height of the proof.
+func (p *AggregatorSetProof) Verify(aggregatorsHash []byte) error {
+	if !bytes.Equal(p.AggregatorsHash, aggregatorsHash) || !bytes.Equal(p.Aggregators.Hash(), aggregatorsHash) {
+		return fmt.Errorf("%w: expected hash %X", ErrInvalidAggregatorProof, aggregatorsHash)
+	}
+	if len(p.Proofs) != len(p.Aggregators.Validators) {
+		return fmt.Errorf("%w: %d proofs for %d aggregators", ErrInvalidAggregatorProof, len(p.Proofs), len(p.Aggregators.Validators))
+	}
+	for i, val := range p.Aggregators.Validators {
+		if err := VerifyAggregator(aggregatorsHash, val, p.Proofs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyAggregator checks the Merkle proof of membership of a single aggregator in the set with given hash.
+func VerifyAggregator(aggregatorsHash []byte, val *cmtypes.Validator, proof *merkle.Proof) error {
+	if err := proof.Verify(aggregatorsHash, val.Bytes()); err != nil {
+		return fmt.Errorf("%w: aggregator %s: %w", ErrInvalidAggregatorProof, val.Address, err)
+	}
+	return nil
+}
+
+func aggregatorLeaves(aggregators *cmtypes.ValidatorSet) [][]byte {
+	leaves := make([][]byte, len(aggregators.Validators))
+	for i, val := range aggregators.Validators {
+		leaves[i] = val.Bytes()
+	}
+	return leaves
+}
+
+// Provider serves signed headers, consensus states and aggregator set proofs from the store of a full node,
+// e.g. for IBC relayers.
+type Provider struct {
+	store store.Store
+}
+
+// NewProvider creates Provider reading from the store.
+func NewProvider(store store.Store) *Provider {
+	return &Provider{store: store}
+}
+
+// SignedHeader returns the signed header (including the aggregator set) at given height.
+func (p *Provider) SignedHeader(height uint64) (*types.SignedHeader, error) {
+	block, err := p.store.LoadBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block %d: %w", height, err)
+	}
+	return &block.SignedHeader, nil
+}
+
+// ConsensusState returns the consensus state of the header at given height.
+func (p *Provider) ConsensusState(height uint64) (*ConsensusState, error) {
+	sh, err := p.SignedHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsensusState(sh), nil
+}
+
+// AggregatorSetProof returns the proof of the aggregator set of the header at given height.
+func (p *Provider) AggregatorSetProof(height uint64) (*AggregatorSetProof, error) {
+	sh, err := p.SignedHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	aggregators := sh.Validators
+	if aggregators == nil {
+		aggregators = cmtypes.NewValidatorSet(nil)
+	}
+	return NewAggregatorSetProof(height, aggregators), nil
+}
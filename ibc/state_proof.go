@@ -0,0 +1,125 @@
+package ibc
+
+import (
+	"errors"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+	"github.com/cometbft/cometbft/proxy"
+)
+
+var (
+	// ErrInvalidStateProof is returned when the proof of a key doesn't match the proven state root.
+	ErrInvalidStateProof = errors.New("invalid state proof")
+	// ErrStateRootNotCommitted is returned when the app hash of the state at given height isn't committed to by
+	// a header yet. AppHash of a header is the root of the state after the previous block, so the state of the
+	// latest block can't be proven until the next block is produced.
+	ErrStateRootNotCommitted = errors.New("state root not committed yet")
+)
+
+// StateProof proves the value (or absence) of a key in a store of the application state at given height. Proof
+// operations are provided by the application (e.g. IAVL or ICS-23 proofs of Cosmos SDK stores), and chained from
+// the value to Root.
+type StateProof struct {
+	// Height is the height of the proven state, i.e. of the last block executed.
+	Height uint64 `json:"height"`
+	// Root is the app hash of the state, committed to by AppHash of the header at Height+1.
+	Root     cmbytes.HexBytes   `json:"root"`
+	Store    string             `json:"store"`
+	Key      cmbytes.HexBytes   `json:"key"`
+	Value    cmbytes.HexBytes   `json:"value"`
+	ProofOps *cmcrypto.ProofOps `json:"proof_ops"`
+}
+
+// StoreKeyPath returns the Merkle key path of a key in a store of the application, i.e. the path of proof
+// operations from the app hash to the value.
+func StoreKeyPath(store string, key []byte) string {
+	return merkle.KeyPath{}.AppendKey([]byte(store), merkle.KeyEncodingURL).AppendKey(key, merkle.KeyEncodingHex).String()
+}
+
+// DefaultProofRuntime returns proof runtime decoding simple Merkle value operations. Decoders of application
+// specific operations (like ICS-23 commitment proofs) have to be registered by the verifier.
+func DefaultProofRuntime() *merkle.ProofRuntime {
+	return merkle.DefaultProofRuntime()
+}
+
+// NewStateProof creates proof of the key from the response of the application to a query with Prove set. Root has
+// to be the app hash committed to by the header following the height of the response.
+func NewStateProof(store string, res *abci.ResponseQuery, root []byte) (*StateProof, error) {
+	if res.IsErr() {
+		return nil, fmt.Errorf("query failed with code %d: %s", res.Code, res.Log)
+	}
+	if res.ProofOps == nil || len(res.ProofOps.Ops) == 0 {
+		return nil, fmt.Errorf("%w: no proof operations in query response", ErrInvalidStateProof)
+	}
+	if res.Height < 0 {
+		return nil, fmt.Errorf("invalid query height: %d", res.Height)
+	}
+	return &StateProof{
+		Height:   uint64(res.Height),
+		Root:     root,
+		Store:    store,
+		Key:      res.Key,
+		Value:    res.Value,
+		ProofOps: res.ProofOps,
+	}, nil
+}
+
+// Verify checks the proof of the value (or of absence of the key, if the value is empty) against the state root,
+// e.g. Root of a trusted consensus state of the header at Height+1. If prt is nil, DefaultProofRuntime is used.
+func (p *StateProof) Verify(prt *merkle.ProofRuntime, root []byte) error {
+	if prt == nil {
+		prt = DefaultProofRuntime()
+	}
+	keyPath := StoreKeyPath(p.Store, p.Key)
+	var err error
+	if len(p.Value) == 0 {
+		err = prt.VerifyAbsence(p.ProofOps, root, keyPath)
+	} else {
+		err = prt.VerifyValue(p.ProofOps, root, keyPath, p.Value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: key %X of store %q at height %d: %w", ErrInvalidStateProof, []byte(p.Key), p.Store, p.Height, err)
+	}
+	return nil
+}
+
+// StateRoot returns the app hash of the state at given height, committed to by the header of the next height.
+func (p *Provider) StateRoot(height uint64) ([]byte, error) {
+	if height >= p.store.Height() {
+		return nil, fmt.Errorf("%w: height %d, latest height %d", ErrStateRootNotCommitted, height, p.store.Height())
+	}
+	sh, err := p.SignedHeader(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	return sh.AppHash, nil
+}
+
+// StateProof queries the application for the proof of the key in the store at given height (0 means the latest
+// height of the application) and returns it with the state root it's proven against.
+func (p *Provider) StateProof(app proxy.AppConnQuery, store string, key []byte, height uint64) (*StateProof, error) {
+	res, err := app.QuerySync(abci.RequestQuery{
+		Path:   "/store/" + store + "/key",
+		Data:   key,
+		Height: int64(height),
+		Prove:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Height < 0 {
+		return nil, fmt.Errorf("invalid query height: %d", res.Height)
+	}
+	if len(res.Key) == 0 {
+		res.Key = key
+	}
+	root, err := p.StateRoot(uint64(res.Height))
+	if err != nil {
+		return nil, err
+	}
+	return NewStateProof(store, res, root)
+}
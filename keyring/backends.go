@@ -0,0 +1,224 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	oskeyring "github.com/99designs/keyring"
+)
+
+const (
+	keyFileExt = ".key"
+	// osServicePrefix prefixes the service name of keys stored in the OS keychain
+	osServicePrefix = "rollkit:"
+)
+
+// osBackends are the OS credential stores used by the OS keyring, in order of preference.
+var osBackends = []oskeyring.BackendType{
+	oskeyring.KeychainBackend,
+	oskeyring.WinCredBackend,
+	oskeyring.SecretServiceBackend,
+	oskeyring.KWalletBackend,
+}
+
+// NewFileKeyring returns a keyring storing every key in a separate file in given directory.
+// Keys are encrypted with passphrase.
+func NewFileKeyring(dir string, passphrase string) (Keyring, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required by file keyring")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create keyring directory: %w", err)
+	}
+	return &keyring{storage: &fileStorage{dir: dir, passphrase: passphrase}}, nil
+}
+
+// NewOSKeyring returns a keyring storing keys in the credential store of the operating system: macOS Keychain,
+// Windows Credential Manager, or Secret Service (e.g. GNOME Keyring) or KWallet on Linux. Keys of different nodes
+// are separated by the service name, derived from given directory.
+func NewOSKeyring(dir string) (Keyring, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve keyring directory: %w", err)
+	}
+	service := osServicePrefix + dir
+	kr, err := oskeyring.Open(oskeyring.Config{
+		AllowedBackends:          osBackends,
+		ServiceName:              service,
+		KeychainName:             "login",
+		KeychainTrustApplication: true,
+		KWalletAppID:             "rollkit",
+		KWalletFolder:            service,
+		LibSecretCollectionName:  "login",
+		WinCredPrefix:            service,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OS keyring: %w", err)
+	}
+	return &keyring{storage: &osStorage{kr: kr}}, nil
+}
+
+// NewTestKeyring returns a keyring storing unencrypted keys in memory. It should be used only for testing.
+func NewTestKeyring() Keyring {
+	return &keyring{storage: &memStorage{keys: make(map[string][]byte)}}
+}
+
+type fileStorage struct {
+	dir        string
+	passphrase string
+	mtx        sync.Mutex
+}
+
+func (fs *fileStorage) path(name string) string {
+	return filepath.Join(fs.dir, name+keyFileExt)
+}
+
+func (fs *fileStorage) read(name string) ([]byte, error) {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	armored, err := os.ReadFile(fs.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return decrypt(string(armored), fs.passphrase)
+}
+
+func (fs *fileStorage) write(name string, data []byte) error {
+	armored, err := encrypt(data, fs.passphrase)
+	if err != nil {
+		return err
+	}
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	// write to temporary file first, to never leave a partially written key
+	tmp := fs.path(name) + ".tmp"
+	if err := os.WriteFile(tmp, []byte(armored), 0o600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := os.Rename(tmp, fs.path(name)); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
+
+func (fs *fileStorage) remove(name string) error {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	err := os.Remove(fs.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return err
+}
+
+func (fs *fileStorage) names() ([]string, error) {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+	entries, err := os.ReadDir(fs.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring directory: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), keyFileExt); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+type memStorage struct {
+	keys map[string][]byte
+	mtx  sync.RWMutex
+}
+
+func (ms *memStorage) read(name string) ([]byte, error) {
+	ms.mtx.RLock()
+	defer ms.mtx.RUnlock()
+	data, ok := ms.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return data, nil
+}
+
+func (ms *memStorage) write(name string, data []byte) error {
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+	ms.keys[name] = data
+	return nil
+}
+
+func (ms *memStorage) remove(name string) error {
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+	if _, ok := ms.keys[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	delete(ms.keys, name)
+	return nil
+}
+
+func (ms *memStorage) names() ([]string, error) {
+	ms.mtx.RLock()
+	defer ms.mtx.RUnlock()
+	names := make([]string, 0, len(ms.keys))
+	for name := range ms.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+type osStorage struct {
+	kr oskeyring.Keyring
+}
+
+func (ks *osStorage) read(name string) ([]byte, error) {
+	item, err := ks.kr.Get(name)
+	if errors.Is(err, oskeyring.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key from OS keyring: %w", err)
+	}
+	return item.Data, nil
+}
+
+func (ks *osStorage) write(name string, data []byte) error {
+	err := ks.kr.Set(oskeyring.Item{
+		Key:         name,
+		Data:        data,
+		Label:       "Rollkit " + name + " key",
+		Description: "Rollkit private key",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write key to OS keyring: %w", err)
+	}
+	return nil
+}
+
+func (ks *osStorage) remove(name string) error {
+	err := ks.kr.Remove(name)
+	if errors.Is(err, oskeyring.ErrKeyNotFound) {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return err
+}
+
+func (ks *osStorage) names() ([]string, error) {
+	names, err := ks.kr.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys of OS keyring: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"os"
+
+	"github.com/rollkit/rollkit/keyring"
+)
+
+func main() {
+	if err := keyring.NewCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,188 @@
+package keyring
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cmcfg "github.com/cometbft/cometbft/config"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/spf13/cobra"
+
+	"github.com/rollkit/rollkit/signer"
+)
+
+const (
+	flagHome    = "home"
+	flagBackend = "keyring-backend"
+	flagDir     = "keyring-dir"
+)
+
+// NewCommand returns the keys command, managing keys of the keyring used by the node (see
+// NodeConfig.KeyringBackend). The passphrase of the file keyring is read from ROLLKIT_KEYRING_PASSPHRASE
+// environment variable, and passphrases of exported keys from the standard input.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the node and proposer keys stored in the keyring",
+	}
+	home, _ := os.UserHomeDir()
+	cmd.PersistentFlags().String(flagHome, filepath.Join(home, cmcfg.DefaultTendermintDir), "root directory of the node")
+	cmd.PersistentFlags().String(flagBackend, BackendFile, "keyring backend (file or os)")
+	cmd.PersistentFlags().String(flagDir, "keyring", "keyring directory, absolute or relative to the root directory")
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "create <name>",
+			Short: "Generate a new key",
+			Args:  cobra.ExactArgs(1),
+			RunE: withKeyring(func(cmd *cobra.Command, kr Keyring, args []string) error {
+				key, err := kr.Create(args[0])
+				if err != nil {
+					return err
+				}
+				return printKey(cmd, args[0], key)
+			}),
+		},
+		&cobra.Command{
+			Use:   "show <name>",
+			Short: "Show the public key, peer ID and BLS aggregator key of a key",
+			Args:  cobra.ExactArgs(1),
+			RunE: withKeyring(func(cmd *cobra.Command, kr Keyring, args []string) error {
+				key, err := kr.Get(args[0])
+				if err != nil {
+					return err
+				}
+				return printKey(cmd, args[0], key)
+			}),
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: "List names of keys",
+			Args:  cobra.NoArgs,
+			RunE: withKeyring(func(cmd *cobra.Command, kr Keyring, _ []string) error {
+				names, err := kr.List()
+				if err != nil {
+					return err
+				}
+				for _, name := range names {
+					fmt.Fprintln(cmd.OutOrStdout(), name)
+				}
+				return nil
+			}),
+		},
+		&cobra.Command{
+			Use:   "delete <name>",
+			Short: "Delete a key",
+			Args:  cobra.ExactArgs(1),
+			RunE: withKeyring(func(_ *cobra.Command, kr Keyring, args []string) error {
+				return kr.Delete(args[0])
+			}),
+		},
+		&cobra.Command{
+			Use:   "rotate <name>",
+			Short: "Replace a key with a new one, keeping the replaced key under <name>.previous",
+			Args:  cobra.ExactArgs(1),
+			RunE: withKeyring(func(cmd *cobra.Command, kr Keyring, args []string) error {
+				key, err := kr.Rotate(args[0])
+				if err != nil {
+					return err
+				}
+				return printKey(cmd, args[0], key)
+			}),
+		},
+		&cobra.Command{
+			Use:   "export <name>",
+			Short: "Print a key in ASCII armor, encrypted with the passphrase read from the standard input",
+			Args:  cobra.ExactArgs(1),
+			RunE: withKeyring(func(cmd *cobra.Command, kr Keyring, args []string) error {
+				passphrase, err := readPassphrase(cmd)
+				if err != nil {
+					return err
+				}
+				armored, err := kr.Export(args[0], passphrase)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), armored)
+				return nil
+			}),
+		},
+		&cobra.Command{
+			Use:   "import <name> <file>",
+			Short: "Import a key exported to the file, decrypted with the passphrase read from the standard input",
+			Args:  cobra.ExactArgs(2),
+			RunE: withKeyring(func(cmd *cobra.Command, kr Keyring, args []string) error {
+				armored, err := os.ReadFile(args[1])
+				if err != nil {
+					return err
+				}
+				passphrase, err := readPassphrase(cmd)
+				if err != nil {
+					return err
+				}
+				return kr.Import(args[0], string(armored), passphrase)
+			}),
+		},
+	)
+	return cmd
+}
+
+// withKeyring opens the keyring configured by command flags, and passes it to run.
+func withKeyring(run func(cmd *cobra.Command, kr Keyring, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		home, _ := flags.GetString(flagHome)
+		backend, _ := flags.GetString(flagBackend)
+		dir, _ := flags.GetString(flagDir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(home, dir)
+		}
+		kr, err := New(backend, dir, os.Getenv(PassphraseEnv))
+		if err != nil {
+			return err
+		}
+		return run(cmd, kr, args)
+	}
+}
+
+func printKey(cmd *cobra.Command, name string, key crypto.PrivKey) error {
+	pubKey, err := key.GetPublic().Raw()
+	if err != nil {
+		return err
+	}
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	// BLS key derived from the key, with proof of possession, as configured in rollkit.bls_aggregator_keys
+	s := signer.NewLocalSigner(key)
+	blsPubKey, err := s.BLSPubKey()
+	if err != nil {
+		return err
+	}
+	proof, err := s.BLSProofOfPossession()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "name: %s\npublic key: %s\npeer ID: %s\nBLS aggregator key: %s:%s\n",
+		name, hex.EncodeToString(pubKey), id, hex.EncodeToString(blsPubKey), hex.EncodeToString(proof))
+	return nil
+}
+
+// readPassphrase reads the first line of the standard input of the command.
+func readPassphrase(cmd *cobra.Command) (string, error) {
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	passphrase := strings.TrimRight(line, "\r\n")
+	if err != nil && passphrase == "" {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if passphrase == "" {
+		return "", errors.New("empty passphrase")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,255 @@
+package keyring
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/cometbft/cometbft/crypto/armor"
+	"github.com/cometbft/cometbft/crypto/xsalsa20symmetric"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// NodeKeyName is the name of the key used to identify the node in P2P network.
+	NodeKeyName = "node"
+	// ProposerKeyName is the name of the key used by the aggregator to sign blocks.
+	ProposerKeyName = "proposer"
+
+	// BackendFile stores keys in files, encrypted with a passphrase.
+	BackendFile = "file"
+	// BackendOS stores keys in the credential store of the operating system.
+	BackendOS = "os"
+	// BackendTest stores keys in memory. It should be used only for testing.
+	BackendTest = "test"
+
+	armorType = "ROLLKIT PRIVATE KEY"
+	// previousSuffix is appended to the name of the key replaced by Rotate.
+	previousSuffix = ".previous"
+
+	// scrypt parameters used to derive encryption key from passphrase
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	saltLength   = 16
+	secretLength = 32
+)
+
+// PassphraseEnv is the environment variable with the passphrase of the file keyring.
+const PassphraseEnv = "ROLLKIT_KEYRING_PASSPHRASE"
+
+var (
+	// ErrKeyNotFound is returned when there is no key with given name in the keyring.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyExists is returned when key with given name already exists in the keyring.
+	ErrKeyExists = errors.New("key already exists")
+
+	errInvalidName = errors.New("invalid key name: only letters, digits, '_', '-' and '.' are allowed")
+
+	validName = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+)
+
+// Keyring stores private keys of the node, identified by names.
+type Keyring interface {
+	// Create generates a new ed25519 key and saves it under given name.
+	Create(name string) (crypto.PrivKey, error)
+	// Get returns the key saved under given name, or ErrKeyNotFound.
+	Get(name string) (crypto.PrivKey, error)
+	// Delete removes the key saved under given name.
+	Delete(name string) error
+	// List returns names of all keys in the keyring.
+	List() ([]string, error)
+	// Import saves the key exported with Export under given name.
+	Import(name string, armored string, passphrase string) error
+	// Export returns the key saved under given name, armored and encrypted with passphrase.
+	Export(name string, passphrase string) (string, error)
+	// Rotate replaces the key saved under given name with a newly generated one.
+	// The replaced key is kept under the name with ".previous" suffix.
+	Rotate(name string) (crypto.PrivKey, error)
+}
+
+// New creates a keyring with given backend. Directory is used by the file and OS backends, and passphrase only by
+// the file backend.
+func New(backend string, dir string, passphrase string) (Keyring, error) {
+	switch backend {
+	case BackendFile:
+		return NewFileKeyring(dir, passphrase)
+	case BackendOS:
+		return NewOSKeyring(dir)
+	case BackendTest:
+		return NewTestKeyring(), nil
+	default:
+		return nil, fmt.Errorf("unsupported keyring backend: %s", backend)
+	}
+}
+
+// LoadOrCreate returns the key saved under given name, generating a new one if it doesn't exist.
+func LoadOrCreate(kr Keyring, name string) (crypto.PrivKey, error) {
+	key, err := kr.Get(name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return kr.Create(name)
+	}
+	return key, err
+}
+
+// storage is implemented by keyring backends, to share key management logic.
+type storage interface {
+	read(name string) ([]byte, error)
+	write(name string, data []byte) error
+	remove(name string) error
+	names() ([]string, error)
+}
+
+// keyring implements key management on top of a storage backend.
+type keyring struct {
+	storage
+}
+
+var _ Keyring = &keyring{}
+
+func (k *keyring) Create(name string) (crypto.PrivKey, error) {
+	if _, err := k.Get(name); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyExists, name)
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	if err := k.save(name, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (k *keyring) Get(name string) (crypto.PrivKey, error) {
+	if !validName.MatchString(name) {
+		return nil, errInvalidName
+	}
+	data, err := k.read(name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.UnmarshalPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key %s: %w", name, err)
+	}
+	return key, nil
+}
+
+func (k *keyring) Delete(name string) error {
+	if !validName.MatchString(name) {
+		return errInvalidName
+	}
+	return k.remove(name)
+}
+
+func (k *keyring) List() ([]string, error) {
+	return k.names()
+}
+
+func (k *keyring) Import(name string, armored string, passphrase string) error {
+	if _, err := k.Get(name); err == nil {
+		return fmt.Errorf("%w: %s", ErrKeyExists, name)
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	data, err := decrypt(armored, passphrase)
+	if err != nil {
+		return err
+	}
+	key, err := crypto.UnmarshalPrivateKey(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal key: %w", err)
+	}
+	return k.save(name, key)
+}
+
+func (k *keyring) Export(name string, passphrase string) (string, error) {
+	key, err := k.Get(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal key: %w", err)
+	}
+	return encrypt(data, passphrase)
+}
+
+func (k *keyring) Rotate(name string) (crypto.PrivKey, error) {
+	previous, err := k.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	if err := k.save(name+previousSuffix, previous); err != nil {
+		return nil, err
+	}
+	if err := k.save(name, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (k *keyring) save(name string, key crypto.PrivKey) error {
+	if !validName.MatchString(name) {
+		return errInvalidName
+	}
+	data, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal key: %w", err)
+	}
+	return k.write(name, data)
+}
+
+// encrypt encrypts data with a secret derived from passphrase, and returns it in ASCII armor.
+func encrypt(data []byte, passphrase string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	secret, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, secretLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive secret: %w", err)
+	}
+	headers := map[string]string{
+		"kdf":  "scrypt",
+		"salt": hex.EncodeToString(salt),
+	}
+	return armor.EncodeArmor(armorType, headers, xsalsa20symmetric.EncryptSymmetric(data, secret)), nil
+}
+
+// decrypt decrypts data encrypted with encrypt.
+func decrypt(armored string, passphrase string) ([]byte, error) {
+	blockType, headers, ciphertext, err := armor.DecodeArmor(armored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode armor: %w", err)
+	}
+	if blockType != armorType {
+		return nil, fmt.Errorf("unrecognized armor type %q, expected %q", blockType, armorType)
+	}
+	if headers["kdf"] != "scrypt" {
+		return nil, fmt.Errorf("unrecognized KDF %q", headers["kdf"])
+	}
+	salt, err := hex.DecodeString(headers["salt"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode salt: %w", err)
+	}
+	secret, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, secretLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive secret: %w", err)
+	}
+	data, err := xsalsa20symmetric.DecryptSymmetric(ciphertext, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key (invalid passphrase?): %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,103 @@
+package keyring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	fileKeyring, err := NewFileKeyring(t.TempDir(), "passphrase")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name    string
+		keyring Keyring
+	}{
+		{"file", fileKeyring},
+		{"test", NewTestKeyring()},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			kr := c.keyring
+
+			_, err := kr.Get(ProposerKeyName)
+			assert.ErrorIs(err, ErrKeyNotFound)
+			_, err = kr.Create("../escape")
+			assert.Error(err)
+
+			key, err := kr.Create(ProposerKeyName)
+			require.NoError(err)
+			_, err = kr.Create(ProposerKeyName)
+			assert.ErrorIs(err, ErrKeyExists)
+
+			loaded, err := kr.Get(ProposerKeyName)
+			require.NoError(err)
+			assert.True(key.Equals(loaded))
+
+			nodeKey, err := LoadOrCreate(kr, NodeKeyName)
+			require.NoError(err)
+			loaded, err = LoadOrCreate(kr, NodeKeyName)
+			require.NoError(err)
+			assert.True(nodeKey.Equals(loaded))
+
+			// export and import
+			armored, err := kr.Export(ProposerKeyName, "export passphrase")
+			require.NoError(err)
+			assert.Error(kr.Import("imported", armored, "wrong passphrase"))
+			require.NoError(kr.Import("imported", armored, "export passphrase"))
+			loaded, err = kr.Get("imported")
+			require.NoError(err)
+			assert.True(key.Equals(loaded))
+
+			// rotation keeps the previous key
+			rotated, err := kr.Rotate(ProposerKeyName)
+			require.NoError(err)
+			assert.False(key.Equals(rotated))
+			loaded, err = kr.Get(ProposerKeyName)
+			require.NoError(err)
+			assert.True(rotated.Equals(loaded))
+			loaded, err = kr.Get(ProposerKeyName + previousSuffix)
+			require.NoError(err)
+			assert.True(key.Equals(loaded))
+
+			names, err := kr.List()
+			require.NoError(err)
+			assert.ElementsMatch([]string{NodeKeyName, ProposerKeyName, ProposerKeyName + previousSuffix, "imported"}, names)
+
+			require.NoError(kr.Delete("imported"))
+			assert.ErrorIs(kr.Delete("imported"), ErrKeyNotFound)
+		})
+	}
+}
+
+func TestFileKeyringPassphrase(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir := t.TempDir()
+	_, err := NewFileKeyring(dir, "")
+	assert.Error(err)
+
+	kr, err := New(BackendFile, dir, "passphrase")
+	require.NoError(err)
+	key, err := kr.Create(ProposerKeyName)
+	require.NoError(err)
+
+	kr, err = New(BackendFile, dir, "other passphrase")
+	require.NoError(err)
+	_, err = kr.Get(ProposerKeyName)
+	assert.Error(err)
+
+	kr, err = New(BackendFile, dir, "passphrase")
+	require.NoError(err)
+	loaded, err := kr.Get(ProposerKeyName)
+	require.NoError(err)
+	assert.True(key.Equals(loaded))
+
+	_, err = New("os", dir, "passphrase")
+	assert.Error(err)
+}
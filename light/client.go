@@ -0,0 +1,296 @@
+// Package light provides verification of a Rollkit chain for light clients embedded in external programs, like
+// wallets and bridges. Starting from a trusted header, Client verifies headers provided by an untrusted source
+// (a full node over RPC or peers over P2P), verifies inclusion of blocks in the DA layer, and verifies proofs of
+// inclusion of transactions in blocks.
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/ibc"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// maxTrustedHeaders limits the number of verified headers kept by Client. When it's exceeded, the lowest header
+// is dropped (the latest one is always kept).
+const maxTrustedHeaders = 1000
+
+var (
+	// ErrHeaderHashMismatch is returned when a header doesn't match the hash committed to by a trusted header.
+	ErrHeaderHashMismatch = errors.New("header hash mismatch")
+	// ErrNotDAIncluded is returned when the block isn't found at its location on the DA layer.
+	ErrNotDAIncluded = errors.New("block not included in DA layer")
+	// ErrInvalidTxProof is returned when the proof of inclusion of a transaction doesn't match the trusted header.
+	ErrInvalidTxProof = errors.New("invalid tx proof")
+)
+
+// Config describes the chain verified by Client.
+type Config struct {
+	ChainID string
+	// TrustingPeriod is the duration since the time of the latest trusted header, in which new headers can be
+	// verified. Zero disables expiration.
+	TrustingPeriod time.Duration
+	// CommitThreshold is the fraction of the voting power of the aggregator set that has to be exceeded by
+	// signatures of headers. Zero value means types.DefaultCommitThreshold.
+	CommitThreshold cmtmath.Fraction
+	// DataHash is the name of the hash function of DataHash of the chain, taken from its genesis (see
+	// types.DataHasherFromGenesis). Empty name means types.DataHashSHA256.
+	DataHash string
+}
+
+// Client is a light client of a Rollkit chain. It's safe for concurrent use.
+type Client struct {
+	state  ibc.ClientState
+	source HeaderSource
+
+	locator DALocator
+	da      da.BlockRetriever
+	hasher  types.MerkleHasher
+
+	mtx        sync.Mutex
+	latest     *types.SignedHeader
+	trusted    map[uint64]*types.SignedHeader
+	daIncluded map[uint64]store.DALocation
+	now        func() time.Time
+}
+
+// NewClient returns a client trusting the header, and verifying headers provided by the source.
+// The trusted header has to be obtained out of band, e.g. from genesis or from a trusted full node.
+func NewClient(conf Config, trusted *types.SignedHeader, source HeaderSource) (*Client, error) {
+	if trusted == nil {
+		return nil, errors.New("trusted header cannot be nil")
+	}
+	if trusted.ChainID() != conf.ChainID {
+		return nil, fmt.Errorf("%w: expected %q, got %q", ibc.ErrChainIDMismatch, conf.ChainID, trusted.ChainID())
+	}
+	if err := trusted.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid trusted header: %w", err)
+	}
+	hasher, err := types.GetDataHasher(conf.DataHash)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		state: ibc.ClientState{
+			ChainID:         conf.ChainID,
+			TrustingPeriod:  conf.TrustingPeriod,
+			CommitThreshold: conf.CommitThreshold,
+			LatestHeight:    trusted.Height(),
+		},
+		source:     source,
+		hasher:     hasher,
+		latest:     trusted,
+		trusted:    map[uint64]*types.SignedHeader{trusted.Height(): trusted},
+		daIncluded: make(map[uint64]store.DALocation),
+		now:        time.Now,
+	}, nil
+}
+
+// SetDA enables verification of DA inclusion of blocks. Locations of blocks are provided by the locator, and
+// blocks are retrieved from the DA layer by the retriever.
+func (c *Client) SetDA(locator DALocator, retriever da.BlockRetriever) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.locator = locator
+	c.da = retriever
+}
+
+// LatestTrusted returns the latest verified header.
+func (c *Client) LatestTrusted() *types.SignedHeader {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.latest
+}
+
+// Update verifies the latest header of the source, and returns it.
+func (c *Client) Update(ctx context.Context) (*types.SignedHeader, error) {
+	head, err := c.source.Head(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if head.Height() <= c.latest.Height() {
+		return c.latest, nil
+	}
+	if err := c.verifyForward(ctx, c.latest, head); err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+// VerifyHeaderAtHeight returns the verified header at given height. Headers above the latest trusted header are
+// verified against it (with bisection, if the aggregator set changed), headers below it are verified against
+// the nearest trusted headers.
+func (c *Client) VerifyHeaderAtHeight(ctx context.Context, height uint64) (*types.SignedHeader, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.verifyHeight(ctx, height)
+}
+
+// VerifyDAInclusion verifies that the block at given height is included in the DA layer, and returns its location.
+// SetDA has to be called first.
+func (c *Client) VerifyDAInclusion(ctx context.Context, height uint64) (store.DALocation, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if loc, ok := c.daIncluded[height]; ok {
+		return loc, nil
+	}
+	if c.locator == nil || c.da == nil {
+		return store.DALocation{}, errors.New("DA verification is not enabled")
+	}
+	sh, err := c.verifyHeight(ctx, height)
+	if err != nil {
+		return store.DALocation{}, err
+	}
+	loc, err := c.locator.DALocation(ctx, height)
+	if err != nil {
+		return store.DALocation{}, err
+	}
+	res := c.da.RetrieveBlocks(ctx, loc.DAHeight)
+	if res.Code != da.StatusSuccess {
+		return store.DALocation{}, fmt.Errorf("failed to retrieve blocks at DA height %d: %s", loc.DAHeight, res.Message)
+	}
+	hash := sh.Hash()
+	for _, block := range res.Blocks {
+		if block.Height() == height && bytes.Equal(block.Hash(), hash) {
+			c.daIncluded[height] = loc
+			return loc, nil
+		}
+	}
+	return store.DALocation{}, fmt.Errorf("%w: height %d, DA height %d", ErrNotDAIncluded, height, loc.DAHeight)
+}
+
+// IsDAIncluded returns true if DA inclusion of the block at given height was verified.
+func (c *Client) IsDAIncluded(height uint64) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, ok := c.daIncluded[height]
+	return ok
+}
+
+// VerifyTx verifies the proof of inclusion of a transaction (e.g. returned by `tx_proof` RPC method of full
+// nodes) against the verified header of the block containing it.
+func (c *Client) VerifyTx(ctx context.Context, proof *types.TxInclusionProof) error {
+	if proof.Height <= 0 {
+		return fmt.Errorf("%w: invalid height %d", ErrInvalidTxProof, proof.Height)
+	}
+	if !bytes.Equal(proof.Hash, proof.Proof.Data.Hash()) {
+		return fmt.Errorf("%w: hash %X doesn't match the proven transaction", ErrInvalidTxProof, []byte(proof.Hash))
+	}
+	sh, err := c.VerifyHeaderAtHeight(ctx, uint64(proof.Height))
+	if err != nil {
+		return err
+	}
+	if err := proof.Proof.ValidateWith(sh.DataHash, c.hasher); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTxProof, err)
+	}
+	return nil
+}
+
+func (c *Client) verifyHeight(ctx context.Context, height uint64) (*types.SignedHeader, error) {
+	if sh, ok := c.trusted[height]; ok {
+		return sh, nil
+	}
+	sh, err := c.source.SignedHeader(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header %d: %w", height, err)
+	}
+	if sh.Height() != height {
+		return nil, fmt.Errorf("expected header %d, got %d", height, sh.Height())
+	}
+
+	// the nearest trusted headers below and above the height
+	var below, above *types.SignedHeader
+	for h, trusted := range c.trusted {
+		if h < height && (below == nil || h > below.Height()) {
+			below = trusted
+		}
+		if h > height && (above == nil || h < above.Height()) {
+			above = trusted
+		}
+	}
+	if below != nil {
+		err = c.verifyForward(ctx, below, sh)
+		if err == nil || above == nil {
+			return sh, err
+		}
+	}
+	return sh, c.verifyBackward(ctx, above, sh)
+}
+
+// verifyForward verifies the untrusted header against the trusted header of a lower height. If the aggregator set
+// changed in between, the header in the middle is verified first.
+func (c *Client) verifyForward(ctx context.Context, trusted, untrusted *types.SignedHeader) error {
+	err := c.state.VerifyHeader(ibc.NewConsensusState(trusted), trusted.Height(), untrusted, c.now())
+	if errors.Is(err, ibc.ErrAggregatorSetChanged) && untrusted.Height() > trusted.Height()+1 {
+		pivot, perr := c.source.SignedHeader(ctx, trusted.Height()+(untrusted.Height()-trusted.Height())/2)
+		if perr != nil {
+			return fmt.Errorf("failed to get header for bisection: %w", perr)
+		}
+		if err = c.verifyForward(ctx, trusted, pivot); err == nil {
+			err = c.verifyForward(ctx, pivot, untrusted)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	c.trust(untrusted)
+	return nil
+}
+
+// verifyBackward verifies the untrusted header against the trusted header of a higher height, following hashes of
+// previous headers.
+func (c *Client) verifyBackward(ctx context.Context, trusted, untrusted *types.SignedHeader) error {
+	for trusted.Height()-1 > untrusted.Height() {
+		prev, err := c.source.SignedHeader(ctx, trusted.Height()-1)
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", trusted.Height()-1, err)
+		}
+		if err := verifyPrevious(trusted, prev); err != nil {
+			return err
+		}
+		trusted = prev
+	}
+	if err := verifyPrevious(trusted, untrusted); err != nil {
+		return err
+	}
+	c.trust(untrusted)
+	return nil
+}
+
+func verifyPrevious(trusted, prev *types.SignedHeader) error {
+	if prev.Height()+1 != trusted.Height() || !bytes.Equal(trusted.LastHeaderHash, prev.Hash()) {
+		return fmt.Errorf("%w: header %d doesn't match header %d", ErrHeaderHashMismatch, prev.Height(), trusted.Height())
+	}
+	return nil
+}
+
+// trust saves the verified header.
+func (c *Client) trust(sh *types.SignedHeader) {
+	c.trusted[sh.Height()] = sh
+	if sh.Height() > c.latest.Height() {
+		c.latest = sh
+		c.state.LatestHeight = sh.Height()
+	}
+	if len(c.trusted) <= maxTrustedHeaders {
+		return
+	}
+	lowest := c.latest.Height()
+	for h := range c.trusted {
+		if h < lowest {
+			lowest = h
+		}
+	}
+	delete(c.trusted, lowest)
+}
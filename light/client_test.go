@@ -0,0 +1,206 @@
+package light
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/ibc"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+type testSource struct {
+	headers   map[uint64]*types.SignedHeader
+	head      uint64
+	locations map[uint64]store.DALocation
+}
+
+func (s *testSource) Head(context.Context) (*types.SignedHeader, error) {
+	return s.SignedHeader(context.Background(), s.head)
+}
+
+func (s *testSource) SignedHeader(_ context.Context, height uint64) (*types.SignedHeader, error) {
+	sh, ok := s.headers[height]
+	if !ok {
+		return nil, fmt.Errorf("header %d not found", height)
+	}
+	return sh, nil
+}
+
+func (s *testSource) DALocation(_ context.Context, height uint64) (store.DALocation, error) {
+	loc, ok := s.locations[height]
+	if !ok {
+		return loc, fmt.Errorf("location of block %d not found", height)
+	}
+	return loc, nil
+}
+
+type testDA map[uint64][]*types.Block
+
+func (d testDA) RetrieveBlocks(_ context.Context, daHeight uint64) da.ResultRetrieveBlocks {
+	return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess}, Blocks: d[daHeight]}
+}
+
+func sign(t *testing.T, sh *types.SignedHeader, keys []crypto.PrivKey) {
+	bz, err := sh.Header.MarshalBinary()
+	require.NoError(t, err)
+	sh.Commit = types.Commit{Signatures: make([]types.Signature, len(keys))}
+	for i, key := range keys {
+		sh.Commit.Signatures[i], err = signer.NewLocalSigner(key).Sign(context.Background(), sh.Height(), bz)
+		require.NoError(t, err)
+	}
+}
+
+// newChain returns blocks at heights [1, n] with 2 transactions each. Aggregator set changes after block changeAt.
+func newChain(t *testing.T, n, changeAt uint64) map[uint64]*types.Block {
+	require := require.New(t)
+	g := types.NewGenerator(1, types.WithNumValidators(2))
+	setB, keysB, err := g.ValidatorSet()
+	require.NoError(err)
+
+	blocks := make(map[uint64]*types.Block)
+	var prev *types.SignedHeader
+	var keys []crypto.PrivKey
+	for h := uint64(1); h <= n; h++ {
+		var sh *types.SignedHeader
+		if prev == nil {
+			sh, keys, err = g.SignedHeader()
+			require.NoError(err)
+			sh.BaseHeader.Height = 1
+		} else {
+			sh, err = g.NextSignedHeader(prev, keys)
+			require.NoError(err)
+		}
+		if h == changeAt+1 {
+			sh.Validators = setB
+			sh.ProposerAddress = setB.Proposer.Address
+			sh.AggregatorsHash = setB.Hash()
+			keys = keysB
+		}
+		if h == changeAt {
+			sh.NextAggregatorsHash = setB.Hash()
+		}
+		data := types.Data{Txs: types.Txs{g.Tx(), g.Tx()}}
+		sh.DataHash, err = data.Hash()
+		require.NoError(err)
+		sign(t, sh, keys)
+		blocks[h] = &types.Block{SignedHeader: *sh, Data: data}
+		prev = sh
+	}
+	return blocks
+}
+
+func newTestSource(blocks map[uint64]*types.Block) *testSource {
+	source := &testSource{headers: make(map[uint64]*types.SignedHeader), locations: make(map[uint64]store.DALocation)}
+	for h, block := range blocks {
+		source.headers[h] = &block.SignedHeader
+		if h > source.head {
+			source.head = h
+		}
+	}
+	return source
+}
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	blocks := newChain(t, 10, 6)
+	source := newTestSource(blocks)
+	conf := Config{ChainID: types.TestChainID, TrustingPeriod: time.Hour}
+	now := types.GeneratorTime.Add(time.Minute)
+
+	_, err := NewClient(Config{ChainID: "other"}, source.headers[1], source)
+	assert.ErrorIs(err, ibc.ErrChainIDMismatch)
+	_, err = NewClient(Config{ChainID: types.TestChainID, DataHash: "md5"}, source.headers[1], source)
+	assert.ErrorIs(err, types.ErrUnknownDataHash)
+
+	client, err := NewClient(conf, source.headers[1], source)
+	require.NoError(err)
+	client.now = func() time.Time { return now }
+
+	t.Run("update across aggregator set change", func(t *testing.T) {
+		head, err := client.Update(ctx)
+		require.NoError(err)
+		assert.Equal(uint64(10), head.Height())
+		assert.Equal(uint64(10), client.LatestTrusted().Height())
+	})
+
+	t.Run("headers below the latest", func(t *testing.T) {
+		sh, err := client.VerifyHeaderAtHeight(ctx, 3)
+		require.NoError(err)
+		assert.Equal(blocks[3].Hash(), sh.Hash())
+
+		// only the latest header is trusted, headers are verified backwards
+		backward, err := NewClient(conf, source.headers[10], source)
+		require.NoError(err)
+		backward.now = client.now
+		sh, err = backward.VerifyHeaderAtHeight(ctx, 4)
+		require.NoError(err)
+		assert.Equal(blocks[4].Hash(), sh.Hash())
+
+		// tampered header in between
+		tampered := newTestSource(blocks)
+		forged := *tampered.headers[8]
+		forged.AppHash = types.GetRandomBytes(32)
+		tampered.headers[8] = &forged
+		backward, err = NewClient(conf, source.headers[10], tampered)
+		require.NoError(err)
+		_, err = backward.VerifyHeaderAtHeight(ctx, 4)
+		assert.ErrorIs(err, ErrHeaderHashMismatch)
+	})
+
+	t.Run("expired trusting period", func(t *testing.T) {
+		expired, err := NewClient(conf, source.headers[1], source)
+		require.NoError(err)
+		expired.now = func() time.Time { return now.Add(time.Hour) }
+		_, err = expired.Update(ctx)
+		assert.ErrorIs(err, ibc.ErrTrustingPeriodExpired)
+	})
+
+	t.Run("tx proofs", func(t *testing.T) {
+		txProof, err := blocks[3].Data.TxProof(1)
+		require.NoError(err)
+		proof := &types.TxInclusionProof{
+			Hash:     blocks[3].Data.Txs[1].Hash(),
+			Height:   3,
+			Index:    1,
+			DataHash: txProof.RootHash,
+			Proof:    txProof,
+		}
+		assert.NoError(client.VerifyTx(ctx, proof))
+
+		wrongHeight := *proof
+		wrongHeight.Height = 4
+		assert.ErrorIs(client.VerifyTx(ctx, &wrongHeight), ErrInvalidTxProof)
+		wrongHash := *proof
+		wrongHash.Hash = blocks[3].Data.Txs[0].Hash()
+		assert.ErrorIs(client.VerifyTx(ctx, &wrongHash), ErrInvalidTxProof)
+	})
+
+	t.Run("DA inclusion", func(t *testing.T) {
+		_, err := client.VerifyDAInclusion(ctx, 3)
+		assert.Error(err)
+
+		source.locations[3] = store.DALocation{DAHeight: 5}
+		source.locations[4] = store.DALocation{DAHeight: 5}
+		client.SetDA(source, testDA{5: {blocks[2], blocks[3]}})
+		loc, err := client.VerifyDAInclusion(ctx, 3)
+		require.NoError(err)
+		assert.Equal(uint64(5), loc.DAHeight)
+		assert.True(client.IsDAIncluded(3))
+
+		_, err = client.VerifyDAInclusion(ctx, 4)
+		assert.ErrorIs(err, ErrNotDAIncluded)
+		assert.False(client.IsDAIncluded(4))
+	})
+}
@@ -0,0 +1,111 @@
+package light
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/celestiaorg/go-header"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// HeaderSource provides signed headers of the chain, e.g. a full node over RPC or peers over P2P.
+// Headers returned by the source are not trusted, they're verified by the Client.
+type HeaderSource interface {
+	// Head returns the latest header known to the source.
+	Head(ctx context.Context) (*types.SignedHeader, error)
+	// SignedHeader returns the header at given height.
+	SignedHeader(ctx context.Context, height uint64) (*types.SignedHeader, error)
+}
+
+// DALocator provides locations of blocks on the DA layer, e.g. a full node over RPC. Locations are not trusted,
+// inclusion is verified by retrieving blocks from the DA layer.
+type DALocator interface {
+	// DALocation returns the location of the block at given height on the DA layer.
+	DALocation(ctx context.Context, height uint64) (store.DALocation, error)
+}
+
+// P2PSource is a HeaderSource reading headers from a go-header getter, e.g. go-header P2P exchange requesting
+// headers from peers, or the store of a header sync service.
+type P2PSource struct {
+	getter header.Getter[*types.SignedHeader]
+}
+
+var _ HeaderSource = &P2PSource{}
+
+// NewP2PSource returns a HeaderSource reading headers from the getter.
+func NewP2PSource(getter header.Getter[*types.SignedHeader]) *P2PSource {
+	return &P2PSource{getter: getter}
+}
+
+// Head returns the latest header known to the getter.
+func (s *P2PSource) Head(ctx context.Context) (*types.SignedHeader, error) {
+	return s.getter.Head(ctx)
+}
+
+// SignedHeader returns the header at given height.
+func (s *P2PSource) SignedHeader(ctx context.Context, height uint64) (*types.SignedHeader, error) {
+	return s.getter.GetByHeight(ctx, height)
+}
+
+// RPCSource is a HeaderSource and a DALocator calling JSON-RPC methods of a full node.
+type RPCSource struct {
+	client *jsonrpcclient.Client
+}
+
+var (
+	_ HeaderSource = &RPCSource{}
+	_ DALocator    = &RPCSource{}
+)
+
+// NewRPCSource returns a source calling JSON-RPC methods of the full node at remote address,
+// e.g. "http://localhost:26657".
+func NewRPCSource(remote string) (*RPCSource, error) {
+	client, err := jsonrpcclient.New(remote)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCSource{client: client}, nil
+}
+
+// Head returns the header of the latest block of the full node.
+func (s *RPCSource) Head(ctx context.Context) (*types.SignedHeader, error) {
+	status := new(ctypes.ResultStatus)
+	if _, err := s.client.Call(ctx, "status", map[string]interface{}{}, status); err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	if status.SyncInfo.LatestBlockHeight <= 0 {
+		return nil, header.ErrNoHead
+	}
+	return s.SignedHeader(ctx, uint64(status.SyncInfo.LatestBlockHeight))
+}
+
+// SignedHeader returns the header at given height.
+func (s *RPCSource) SignedHeader(ctx context.Context, height uint64) (*types.SignedHeader, error) {
+	sh := new(types.SignedHeader)
+	if _, err := s.client.Call(ctx, "signed_header", map[string]interface{}{"height": height}, sh); err != nil {
+		return nil, fmt.Errorf("failed to get header %d: %w", height, err)
+	}
+	return sh, nil
+}
+
+// DALocation returns the location of the block at given height on the DA layer.
+func (s *RPCSource) DALocation(ctx context.Context, height uint64) (store.DALocation, error) {
+	var loc store.DALocation
+	if _, err := s.client.Call(ctx, "da_location", map[string]interface{}{"height": height}, &loc); err != nil {
+		return loc, fmt.Errorf("failed to get DA location of block %d: %w", height, err)
+	}
+	return loc, nil
+}
+
+// TxProof returns the proof of inclusion of the transaction with given hash, to be verified with Client.VerifyTx.
+func (s *RPCSource) TxProof(ctx context.Context, hash []byte) (*types.TxInclusionProof, error) {
+	proof := new(types.TxInclusionProof)
+	if _, err := s.client.Call(ctx, "tx_proof", map[string]interface{}{"hash": hash}, proof); err != nil {
+		return nil, fmt.Errorf("failed to get proof of tx %X: %w", hash, err)
+	}
+	return proof, nil
+}
@@ -2,8 +2,8 @@ package mempool
 
 import (
 	"container/list"
-
 	"sync"
+	"time"
 
 	"github.com/cometbft/cometbft/types"
 )
@@ -32,19 +32,30 @@ type TxCache interface {
 var _ TxCache = (*LRUTxCache)(nil)
 
 // LRUTxCache maintains a thread-safe LRU cache of raw transactions. The cache
-// only stores the hash of the raw transaction.
+// only stores the hash of the raw transaction. Optionally, entries expire after
+// a given time-to-live, so that a transaction can be checked again.
 type LRUTxCache struct {
 	mtx      sync.Mutex
 	size     int
+	ttl      time.Duration
 	cacheMap map[types.TxKey]*list.Element
 	list     *list.List
+	pushedAt map[types.TxKey]time.Time // only used if ttl > 0
 }
 
 func NewLRUTxCache(cacheSize int) *LRUTxCache {
+	return NewLRUTxCacheWithTTL(cacheSize, 0)
+}
+
+// NewLRUTxCacheWithTTL creates a LRU cache with entries expiring ttl after
+// they were pushed. Zero ttl disables expiration.
+func NewLRUTxCacheWithTTL(cacheSize int, ttl time.Duration) *LRUTxCache {
 	return &LRUTxCache{
 		size:     cacheSize,
+		ttl:      ttl,
 		cacheMap: make(map[types.TxKey]*list.Element, cacheSize),
 		list:     list.New(),
+		pushedAt: make(map[types.TxKey]time.Time),
 	}
 }
 
@@ -59,6 +70,7 @@ func (c *LRUTxCache) Reset() {
 	defer c.mtx.Unlock()
 
 	c.cacheMap = make(map[types.TxKey]*list.Element, c.size)
+	c.pushedAt = make(map[types.TxKey]time.Time)
 	c.list.Init()
 }
 
@@ -71,7 +83,12 @@ func (c *LRUTxCache) Push(tx types.Tx) bool {
 	moved, ok := c.cacheMap[key]
 	if ok {
 		c.list.MoveToBack(moved)
-		return false
+		if !c.expired(key) {
+			return false
+		}
+		// expired entry is treated as a new one
+		c.pushedAt[key] = time.Now()
+		return true
 	}
 
 	if c.list.Len() >= c.size {
@@ -79,12 +96,16 @@ func (c *LRUTxCache) Push(tx types.Tx) bool {
 		if front != nil {
 			frontKey := front.Value.(types.TxKey)
 			delete(c.cacheMap, frontKey)
+			delete(c.pushedAt, frontKey)
 			c.list.Remove(front)
 		}
 	}
 
 	e := c.list.PushBack(key)
 	c.cacheMap[key] = e
+	if c.ttl > 0 {
+		c.pushedAt[key] = time.Now()
+	}
 
 	return true
 }
@@ -96,6 +117,7 @@ func (c *LRUTxCache) Remove(tx types.Tx) {
 	key := tx.Key()
 	e := c.cacheMap[key]
 	delete(c.cacheMap, key)
+	delete(c.pushedAt, key)
 
 	if e != nil {
 		c.list.Remove(e)
@@ -106,8 +128,15 @@ func (c *LRUTxCache) Has(tx types.Tx) bool {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	_, ok := c.cacheMap[tx.Key()]
-	return ok
+	key := tx.Key()
+	_, ok := c.cacheMap[key]
+	return ok && !c.expired(key)
+}
+
+// expired reports whether the entry was pushed more than ttl ago.
+// The caller must hold c.mtx.
+func (c *LRUTxCache) expired(key types.TxKey) bool {
+	return c.ttl > 0 && time.Since(c.pushedAt[key]) > c.ttl
 }
 
 // NopTxCache defines a no-op raw transaction cache.
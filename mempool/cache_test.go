@@ -3,6 +3,7 @@ package mempool
 import (
 	"crypto/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +34,20 @@ func TestCacheRemove(t *testing.T) {
 		require.Equal(t, numTxs-(i+1), cache.list.Len())
 	}
 }
+
+func TestCacheTTL(t *testing.T) {
+	cache := NewLRUTxCacheWithTTL(100, 100*time.Millisecond)
+	tx := []byte("tx")
+
+	require.True(t, cache.Push(tx))
+	require.False(t, cache.Push(tx))
+	require.True(t, cache.Has(tx))
+
+	time.Sleep(150 * time.Millisecond)
+	require.False(t, cache.Has(tx))
+
+	// expired transaction is pushed again
+	require.True(t, cache.Push(tx))
+	require.True(t, cache.Has(tx))
+	require.Equal(t, 1, cache.list.Len())
+}
@@ -0,0 +1,146 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/types"
+)
+
+// ErrTxFiltered is returned if transaction is rejected by a TxFilter.
+var ErrTxFiltered = errors.New("transaction rejected by filter")
+
+// ErrTxThrottled is returned (wrapped together with ErrTxFiltered) if transaction is rejected, because of a rate
+// limit. Unlike other rejected transactions, it can be resubmitted later.
+var ErrTxThrottled = errors.New("transaction throttled")
+
+// TxFilter is a policy deciding which transactions are accepted by the mempool
+// (e.g. in permissioned rollups). Rejected transactions never enter the mempool.
+//
+// Returned errors should wrap ErrTxFiltered.
+type TxFilter interface {
+	// FilterTx is called with raw transaction, before it's sent to the application.
+	FilterTx(tx types.Tx) error
+
+	// FilterSender is called with the sender assigned by the application in
+	// CheckTx response. Sender is empty if the application doesn't report it.
+	FilterSender(sender string) error
+}
+
+// SenderListFilter accepts transactions based on lists of senders.
+//
+// If allowlist is not empty, only transactions of allowed senders are accepted
+// (so transactions without sender are rejected). Transactions of denied senders
+// are always rejected.
+type SenderListFilter struct {
+	allow map[string]struct{}
+	deny  map[string]struct{}
+}
+
+var _ TxFilter = (*SenderListFilter)(nil)
+
+// NewSenderListFilter creates new instance of SenderListFilter.
+func NewSenderListFilter(allow, deny []string) *SenderListFilter {
+	f := &SenderListFilter{
+		allow: make(map[string]struct{}, len(allow)),
+		deny:  make(map[string]struct{}, len(deny)),
+	}
+	for _, s := range allow {
+		f.allow[s] = struct{}{}
+	}
+	for _, s := range deny {
+		f.deny[s] = struct{}{}
+	}
+	return f
+}
+
+// FilterTx accepts all transactions, as sender is not known before CheckTx.
+func (f *SenderListFilter) FilterTx(tx types.Tx) error {
+	return nil
+}
+
+// FilterSender rejects denied senders, and senders not on allowlist (if it's not empty).
+func (f *SenderListFilter) FilterSender(sender string) error {
+	if _, ok := f.deny[sender]; ok {
+		return fmt.Errorf("%w: sender %q is denied", ErrTxFiltered, sender)
+	}
+	if _, ok := f.allow[sender]; len(f.allow) > 0 && !ok {
+		return fmt.Errorf("%w: sender %q is not allowed", ErrTxFiltered, sender)
+	}
+	return nil
+}
+
+// TxPredicateFilter accepts raw transactions for which the predicate returns true.
+type TxPredicateFilter func(tx types.Tx) bool
+
+var _ TxFilter = TxPredicateFilter(nil)
+
+// FilterTx rejects transaction if predicate returns false.
+func (f TxPredicateFilter) FilterTx(tx types.Tx) error {
+	if !f(tx) {
+		return fmt.Errorf("%w: tx %X", ErrTxFiltered, tx.Hash())
+	}
+	return nil
+}
+
+// FilterSender accepts all senders.
+func (f TxPredicateFilter) FilterSender(sender string) error {
+	return nil
+}
+
+// SenderRateFilter limits the rate of transactions of a single sender, protecting the mempool from spam.
+// Transactions without sender are not limited.
+type SenderRateFilter struct {
+	limiter *RateLimiter
+	metrics *Metrics
+}
+
+var _ TxFilter = (*SenderRateFilter)(nil)
+
+// NewSenderRateFilter creates new instance of SenderRateFilter, accepting rate transactions per second of a single
+// sender, with bursts of up to burst transactions (see NewRateLimiter). If metrics is nil, no metrics are recorded.
+func NewSenderRateFilter(rate float64, burst int, metrics *Metrics) *SenderRateFilter {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	return &SenderRateFilter{limiter: NewRateLimiter(rate, burst), metrics: metrics}
+}
+
+// FilterTx accepts all transactions, as sender is not known before CheckTx.
+func (f *SenderRateFilter) FilterTx(tx types.Tx) error {
+	return nil
+}
+
+// FilterSender rejects the transaction if its sender exceeded the rate limit.
+func (f *SenderRateFilter) FilterSender(sender string) error {
+	if sender == "" || f.limiter.Allow(sender) {
+		return nil
+	}
+	f.metrics.ThrottledTxs.With("reason", ThrottledSenderRate).Add(1)
+	return fmt.Errorf("%w: %w: sender %q exceeded the rate limit", ErrTxFiltered, ErrTxThrottled, sender)
+}
+
+// TxFilters combines multiple filters: transaction is accepted if it's accepted by all of them.
+type TxFilters []TxFilter
+
+var _ TxFilter = TxFilters(nil)
+
+// FilterTx rejects transaction rejected by any of the filters.
+func (fs TxFilters) FilterTx(tx types.Tx) error {
+	for _, f := range fs {
+		if err := f.FilterTx(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilterSender rejects sender rejected by any of the filters.
+func (fs TxFilters) FilterSender(sender string) error {
+	for _, f := range fs {
+		if err := f.FilterSender(sender); err != nil {
+			return err
+		}
+	}
+	return nil
+}
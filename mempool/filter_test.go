@@ -0,0 +1,71 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderListFilter(t *testing.T) {
+	cases := []struct {
+		name        string
+		allow, deny []string
+		sender      string
+		accepted    bool
+	}{
+		{"no lists", nil, nil, "alice", true},
+		{"no lists, no sender", nil, nil, "", true},
+		{"allowed", []string{"alice"}, nil, "alice", true},
+		{"not allowed", []string{"alice"}, nil, "bob", false},
+		{"allowlist, no sender", []string{"alice"}, nil, "", false},
+		{"denied", nil, []string{"bob"}, "bob", false},
+		{"not denied", nil, []string{"bob"}, "alice", true},
+		{"allowed and denied", []string{"bob"}, []string{"bob"}, "bob", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := NewSenderListFilter(c.allow, c.deny)
+			assert.NoError(t, f.FilterTx(types.Tx("tx")))
+			err := f.FilterSender(c.sender)
+			if c.accepted {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrTxFiltered)
+			}
+		})
+	}
+}
+
+func TestTxPredicateFilter(t *testing.T) {
+	f := TxPredicateFilter(func(tx types.Tx) bool { return len(tx) < 4 })
+	assert.NoError(t, f.FilterTx(types.Tx("tx")))
+	assert.ErrorIs(t, f.FilterTx(types.Tx("long tx")), ErrTxFiltered)
+	assert.NoError(t, f.FilterSender("alice"))
+}
+
+func TestSenderRateFilter(t *testing.T) {
+	f := NewSenderRateFilter(1, 2, nil)
+	now := time.Unix(1700000000, 0)
+	f.limiter.now = func() time.Time { return now }
+
+	assert.NoError(t, f.FilterSender("alice"))
+	assert.NoError(t, f.FilterSender("alice"))
+	err := f.FilterSender("alice")
+	assert.ErrorIs(t, err, ErrTxFiltered)
+	assert.ErrorIs(t, err, ErrTxThrottled)
+
+	// transactions without sender are not limited
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, f.FilterSender(""))
+	}
+
+	// filters are combined
+	fs := TxFilters{NewSenderListFilter(nil, []string{"mallory"}), f}
+	assert.ErrorIs(t, fs.FilterSender("mallory"), ErrTxFiltered)
+	assert.ErrorIs(t, fs.FilterSender("alice"), ErrTxThrottled)
+	now = now.Add(time.Second)
+	assert.NoError(t, fs.FilterSender("alice"))
+}
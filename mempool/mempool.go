@@ -35,6 +35,11 @@ type Mempool interface {
 	// (~ all available transactions).
 	ReapMaxTxs(max int) types.Txs
 
+	// ListTxs returns up to limit transactions starting at offset, in the same
+	// order as ReapMaxTxs. It's used for inspection (e.g. pagination of
+	// unconfirmed transactions) and doesn't remove transactions from the mempool.
+	ListTxs(offset, limit int) types.Txs
+
 	// Lock locks the mempool. The consensus must be able to hold lock to safely
 	// update.
 	Lock()
@@ -128,6 +133,22 @@ func PostCheckMaxGas(maxGas int64) PostCheckFunc {
 	}
 }
 
+// Codespace is the codespace of CheckTx response codes set by the mempool.
+const Codespace = "mempool"
+
+// CodeTxUnderpriced is the CheckTx response code of a transaction rejected
+// because its priority is too low to replace the existing transaction of the
+// same sender (and nonce).
+const CodeTxUnderpriced uint32 = 1
+
+// CodeTxFiltered is the CheckTx response code of a transaction rejected by the
+// mempool TxFilter, because of its sender.
+const CodeTxFiltered uint32 = 2
+
+// CodeTxFeeTooLow is the CheckTx response code of a transaction rejected
+// because its fee is lower than the minimal fee of the mempool.
+const CodeTxFeeTooLow uint32 = 3
+
 // ErrTxInCache is returned to the client if we saw tx earlier
 var ErrTxInCache = errors.New("tx already exists in cache")
 
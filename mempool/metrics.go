@@ -13,6 +13,15 @@ const (
 	MetricsSubsystem = "mempool"
 )
 
+// Reasons of rejection of throttled transactions, used as "reason" label of
+// ThrottledTxs metric.
+const (
+	ThrottledIPRate     = "ip_rate"
+	ThrottledSenderRate = "sender_rate"
+	ThrottledTxSize     = "tx_size"
+	ThrottledMinFee     = "min_fee"
+)
+
 // Metrics contains metrics exposed by this package.
 // see MetricsProvider for descriptions.
 type Metrics struct {
@@ -39,6 +48,10 @@ type Metrics struct {
 
 	// Number of times transactions are rechecked in the mempool.
 	RecheckTimes metrics.Counter
+
+	// Number of transactions rejected by ingress limits (rate limits per IP
+	// address and per sender, size limit and minimal fee), labeled by reason.
+	ThrottledTxs metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -92,6 +105,13 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "recheck_times",
 			Help:      "Number of times transactions are rechecked in the mempool.",
 		}, labels).With(labelsAndValues...),
+
+		ThrottledTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "throttled_txs",
+			Help:      "Number of transactions rejected by ingress limits.",
+		}, append(labels, "reason")).With(labelsAndValues...),
 	}
 }
 
@@ -104,5 +124,6 @@ func NopMetrics() *Metrics {
 		RejectedTxs:  discard.NewCounter(),
 		EvictedTxs:   discard.NewCounter(),
 		RecheckTimes: discard.NewCounter(),
+		ThrottledTxs: discard.NewCounter(),
 	}
 }
@@ -0,0 +1,86 @@
+package mempool
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+// maxRateLimitedKeys limits the number of buckets kept by RateLimiter. When it's reached, the bucket of the least
+// recently used key is dropped, so the key gets a full bucket again.
+const maxRateLimitedKeys = 10000
+
+// txBucket is a token bucket of transactions, refilled at rate transactions per second, up to burst transactions.
+type txBucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter limits the rate of transactions per key, e.g. per IP address or per sender. It's safe for
+// concurrent use.
+type RateLimiter struct {
+	rate    float64
+	burst   float64
+	maxKeys int
+
+	mtx     sync.Mutex
+	buckets map[string]*list.Element
+	// lru orders buckets from the most recently used
+	lru *list.List
+	now func() time.Time
+}
+
+// NewRateLimiter returns a limiter accepting rate transactions per second per key, with bursts of up to burst
+// transactions. Zero burst means one second worth of transactions (at least one).
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	b := float64(burst)
+	if burst <= 0 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &RateLimiter{
+		rate:    rate,
+		burst:   b,
+		maxKeys: maxRateLimitedKeys,
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// Allow returns true and accounts the transaction, if the key didn't exceed the limit.
+func (l *RateLimiter) Allow(key string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := l.now()
+	e, ok := l.buckets[key]
+	if ok {
+		l.lru.MoveToFront(e)
+	} else {
+		for len(l.buckets) >= l.maxKeys {
+			l.evict()
+		}
+		e = l.lru.PushFront(&txBucket{key: key, tokens: l.burst, last: now})
+		l.buckets[key] = e
+	}
+	b := e.Value.(*txBucket)
+	l.refill(b, now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (l *RateLimiter) refill(b *txBucket, now time.Time) {
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+}
+
+// evict drops the bucket of the least recently used key.
+func (l *RateLimiter) evict() {
+	e := l.lru.Back()
+	l.lru.Remove(e)
+	delete(l.buckets, e.Value.(*txBucket).key)
+}
@@ -0,0 +1,64 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1700000000, 0)
+	l := NewRateLimiter(2, 0)
+	l.now = func() time.Time { return now }
+
+	// burst of one second worth of transactions
+	assert.True(l.Allow("alice"))
+	assert.True(l.Allow("alice"))
+	assert.False(l.Allow("alice"))
+	assert.True(l.Allow("bob"))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(l.Allow("alice"))
+	assert.False(l.Allow("alice"))
+
+	// bucket doesn't exceed the burst
+	now = now.Add(time.Hour)
+	assert.True(l.Allow("alice"))
+	assert.True(l.Allow("alice"))
+	assert.False(l.Allow("alice"))
+
+	// fractional rate allows at least one transaction
+	l = NewRateLimiter(0.1, 0)
+	l.now = func() time.Time { return now }
+	assert.True(l.Allow("alice"))
+	assert.False(l.Allow("alice"))
+	now = now.Add(10 * time.Second)
+	assert.True(l.Allow("alice"))
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1700000000, 0)
+	l := NewRateLimiter(1, 1)
+	l.now = func() time.Time { return now }
+	l.maxKeys = 2
+
+	assert.True(l.Allow("alice"))
+	assert.True(l.Allow("bob"))
+	// alice is used more recently than bob
+	assert.False(l.Allow("alice"))
+
+	// bucket of bob is dropped
+	assert.True(l.Allow("carol"))
+	assert.Len(l.buckets, 2)
+	assert.False(l.Allow("alice"))
+	assert.False(l.Allow("carol"))
+	assert.True(l.Allow("bob"))
+
+	// bucket of alice was dropped for bob
+	assert.True(l.Allow("alice"))
+}
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	abcicli "github.com/cometbft/cometbft/abci/client"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/mempool"
+)
+
+// ErrBatcherStopped is returned by Batcher.CheckTx if the batcher is not running anymore.
+var ErrBatcherStopped = errors.New("checktx batcher stopped")
+
+// CheckTxRequest is a single transaction submitted to CheckTxBatch.
+type CheckTxRequest struct {
+	Tx       types.Tx
+	Callback func(*abci.Response)
+	TxInfo   mempool.TxInfo
+	// ReceivedAt is the time of arrival of the transaction; zero means the time of the CheckTxBatch call
+	ReceivedAt time.Time
+}
+
+// CheckTxBatch works like CheckTx for multiple transactions, but pipelines
+// the CheckTx calls: all requests are sent to the application before waiting
+// for the responses, with a single flush of the connection. It returns an
+// error for each request, with the same semantics as CheckTx.
+func (txmp *TxMempool) CheckTxBatch(reqs []CheckTxRequest) []error {
+	now := time.Now().UTC()
+	errs := make([]error, len(reqs))
+	heights := make([]uint64, len(reqs))
+	reqRes := make([]*abcicli.ReqRes, len(reqs))
+	overridden := make([]*abci.ResponseCheckTx, len(reqs))
+	// CheckTx responses are collected per request (see ReqRes), but ABCI clients require a global callback.
+	txmp.proxyAppConn.SetResponseCallback(func(*abci.Request, *abci.Response) {})
+	for i, req := range reqs {
+		heights[i], errs[i] = txmp.precheckTx(req.Tx, req.TxInfo)
+		if errs[i] != nil {
+			continue
+		}
+		var appTx types.Tx
+		if appTx, overridden[i] = txmp.overrideCheckTx(req.Tx); overridden[i] == nil {
+			reqRes[i] = txmp.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: appTx})
+		}
+	}
+
+	// responses are ordered, so all of them are available after flush; ReqRes.Wait
+	// can't be used, as ReqRes of local clients is never marked done
+	flushErr := txmp.proxyAppConn.FlushSync()
+	for i, req := range reqs {
+		rsp := overridden[i]
+		if reqRes[i] == nil && rsp == nil {
+			continue
+		}
+		if rsp == nil && flushErr == nil {
+			rsp = reqRes[i].Response.GetCheckTx()
+		}
+		if rsp == nil {
+			txmp.cache.Remove(req.Tx)
+			errs[i] = flushErr
+			if errs[i] == nil {
+				errs[i] = fmt.Errorf("unexpected response to CheckTx: %v", reqRes[i].Response)
+			}
+			continue
+		}
+		received := req.ReceivedAt
+		if received.IsZero() {
+			received = now
+		}
+		txmp.handleCheckTxResponse(req.Tx, heights[i], received, rsp, req.Callback, req.TxInfo)
+	}
+	return errs
+}
+
+// Batcher groups transactions submitted concurrently with CheckTx, and checks
+// them with CheckTxBatch, to raise ingest throughput with out-of-process
+// applications. Batches are formed from requests waiting while the previous
+// batch is processed, so batching doesn't add latency.
+//
+// Batcher implements mempool.Mempool; all methods except CheckTx are handled
+// by the underlying TxMempool.
+type Batcher struct {
+	*TxMempool
+
+	maxSize int
+	reqs    chan batchRequest
+	done    chan struct{}
+}
+
+type batchRequest struct {
+	CheckTxRequest
+	errCh chan error
+}
+
+var _ mempool.Mempool = (*Batcher)(nil)
+
+// NewBatcher creates new Batcher checking up to maxSize transactions at once.
+// Run has to be called to process transactions.
+func NewBatcher(txmp *TxMempool, maxSize int) *Batcher {
+	return &Batcher{
+		TxMempool: txmp,
+		maxSize:   maxSize,
+		reqs:      make(chan batchRequest, maxSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// CheckTx queues the transaction for the next batch, and waits until it's
+// checked. Semantics of CheckTx are the same as of TxMempool.CheckTx.
+func (b *Batcher) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo mempool.TxInfo) error {
+	req := batchRequest{
+		CheckTxRequest: CheckTxRequest{Tx: tx, Callback: cb, TxInfo: txInfo, ReceivedAt: time.Now().UTC()},
+		errCh:          make(chan error, 1),
+	}
+	select {
+	case b.reqs <- req:
+	case <-b.done:
+		return ErrBatcherStopped
+	}
+	select {
+	case err := <-req.errCh:
+		return err
+	case <-b.done:
+		return ErrBatcherStopped
+	}
+}
+
+// Run processes batches of transactions until ctx is canceled.
+func (b *Batcher) Run(ctx context.Context) {
+	defer close(b.done)
+	for {
+		var batch []batchRequest
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-b.reqs:
+			batch = append(batch, req)
+		}
+		// take all requests waiting for the batch
+	collect:
+		for len(batch) < b.maxSize {
+			select {
+			case req := <-b.reqs:
+				batch = append(batch, req)
+			default:
+				break collect
+			}
+		}
+
+		reqs := make([]CheckTxRequest, len(batch))
+		for i := range batch {
+			reqs[i] = batch[i].CheckTxRequest
+		}
+		for i, err := range b.CheckTxBatch(reqs) {
+			batch[i].errCh <- err
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/mempool"
+)
+
+func TestTxMempool_CheckTxBatch(t *testing.T) {
+	txmp := setup(t, 100)
+
+	var responses []*abci.ResponseCheckTx
+	cb := func(r *abci.Response) { responses = append(responses, r.GetCheckTx()) }
+	reqs := []CheckTxRequest{
+		{Tx: types.Tx("alice=a0=100"), Callback: cb},
+		{Tx: types.Tx("invalid"), Callback: cb},
+		{Tx: types.Tx("alice=a0=100"), Callback: cb},
+		{Tx: types.Tx("bob=b0=200"), Callback: cb},
+	}
+	errs := txmp.CheckTxBatch(reqs)
+	require.Len(t, errs, len(reqs))
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.ErrorIs(t, errs[2], mempool.ErrTxInCache)
+	require.NoError(t, errs[3])
+
+	require.Len(t, responses, 3)
+	require.Equal(t, abci.CodeTypeOK, responses[0].Code)
+	require.NotEqual(t, abci.CodeTypeOK, responses[1].Code)
+	require.Equal(t, abci.CodeTypeOK, responses[2].Code)
+	require.Equal(t, types.Txs{types.Tx("bob=b0=200"), types.Tx("alice=a0=100")}, txmp.ReapMaxTxs(-1))
+}
+
+func TestBatcher(t *testing.T) {
+	batcher := NewBatcher(setup(t, 1000), 16)
+	ctx, cancel := context.WithCancel(context.Background())
+	go batcher.Run(ctx)
+
+	const numTxs = 100
+	var wg sync.WaitGroup
+	for i := 0; i < numTxs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			require.NoError(t, batcher.CheckTx(types.Tx(fmt.Sprintf("sender-%d=key=%d", i, i)), nil, mempool.TxInfo{}))
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, numTxs, batcher.Size())
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return batcher.CheckTx(types.Tx("late=key=1"), nil, mempool.TxInfo{}) == ErrBatcherStopped
+	}, time.Second, 10*time.Millisecond)
+}
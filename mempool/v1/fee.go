@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+)
+
+// FeeFunc extracts the fee of a transaction from its CheckTx response. It
+// reports false if the transaction has no fee.
+type FeeFunc func(checkTxRes *abci.ResponseCheckTx) (uint64, bool)
+
+// WithMinFee rejects transactions with fee lower than minFee, and
+// transactions without fee. The fee is extracted from CheckTx response by f.
+func WithMinFee(f FeeFunc, minFee uint64) TxMempoolOption {
+	return func(txmp *TxMempool) {
+		txmp.feeFunc = f
+		txmp.minFee = minFee
+	}
+}
+
+// EventFee returns a FeeFunc reading the fee from the attribute attrKey of the
+// first event of type eventType in the CheckTx response. The value is an
+// integer amount, optionally followed by a denomination (e.g. "100stake"),
+// which is ignored.
+func EventFee(eventType, attrKey string) FeeFunc {
+	return func(checkTxRes *abci.ResponseCheckTx) (uint64, bool) {
+		for _, event := range checkTxRes.Events {
+			if event.Type != eventType {
+				continue
+			}
+			for _, attr := range event.Attributes {
+				if attr.Key == attrKey {
+					i := 0
+					for i < len(attr.Value) && attr.Value[i] >= '0' && attr.Value[i] <= '9' {
+						i++
+					}
+					fee, err := strconv.ParseUint(attr.Value[:i], 10, 64)
+					return fee, err == nil
+				}
+			}
+		}
+		return 0, false
+	}
+}
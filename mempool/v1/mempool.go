@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sort"
@@ -52,11 +55,21 @@ type TxMempool struct {
 	txsAvailable         chan struct{} // one value sent per height when mempool is not empty
 	preCheck             mempool.PreCheckFunc
 	postCheck            mempool.PostCheckFunc
-	height               uint64 // the latest height passed to Update
+	nonceFunc            NonceFunc
+	feeFunc              FeeFunc
+	minFee               uint64 // minimal fee of transactions, used with feeFunc
+	txFilter             mempool.TxFilter
+	checkTxOverride      CheckTxOverride
+	replaceBump          uint64        // minimal priority increase (in percent) to replace a transaction, 0 disables replacement
+	maxTxsPerSender      int           // maximal number of transactions of a sender, 0 means no limit
+	fcfs                 bool          // order transactions by time of arrival only
+	height               uint64        // the latest height passed to Update
+	recheckDone          chan struct{} // closed when recheck after the latest Update is complete
 
 	txs        *clist.CList // valid transactions (passed CheckTx)
 	txByKey    map[types.TxKey]*clist.CElement
-	txBySender map[string]*clist.CElement // for sender != ""
+	txBySender map[string]*clist.CElement // for sender != "", keyed by sender and nonce if ordered by nonce
+	senderTxs  map[string]int             // number of transactions of sender, for sender != ""
 }
 
 // NewTxMempool constructs a new, empty priority mempool at the specified
@@ -80,6 +93,7 @@ func NewTxMempool(
 		height:       height,
 		txByKey:      make(map[types.TxKey]*clist.CElement),
 		txBySender:   make(map[string]*clist.CElement),
+		senderTxs:    make(map[string]int),
 	}
 	if cfg.CacheSize > 0 {
 		txmp.cache = mempool.NewLRUTxCache(cfg.CacheSize)
@@ -106,6 +120,51 @@ func WithPostCheck(f mempool.PostCheckFunc) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.postCheck = f }
 }
 
+// WithCacheTTL sets expiration of entries of the cache of seen transactions.
+// Duplicates are dropped without calling the application only within ttl, so
+// stale entries don't reject resubmitted transactions forever. It has no
+// effect if the cache is disabled (CacheSize is zero).
+func WithCacheTTL(ttl time.Duration) TxMempoolOption {
+	return func(txmp *TxMempool) {
+		if txmp.config.CacheSize > 0 {
+			txmp.cache = mempool.NewLRUTxCacheWithTTL(txmp.config.CacheSize, ttl)
+		}
+	}
+}
+
+// WithMaxTxsPerSender limits the number of transactions of a single sender in
+// the mempool. It's only effective with nonce ordering, as otherwise a single
+// transaction per sender is allowed.
+func WithMaxTxsPerSender(max int) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.maxTxsPerSender = max }
+}
+
+// WithTxFilter sets a policy filtering transactions accepted by the mempool.
+// Unlike PreCheck and PostCheck hooks, the filter is not replaced by Update.
+func WithTxFilter(f mempool.TxFilter) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.txFilter = f }
+}
+
+// CheckTxOverride returns the CheckTx response for transactions that can't be
+// checked by the application (e.g. malformed encrypted transactions), or the
+// transaction checked by the application instead of tx (e.g. the fee
+// transaction of an encrypted transaction). For other transactions it returns
+// tx and nil response.
+type CheckTxOverride func(tx types.Tx) (types.Tx, *abci.ResponseCheckTx)
+
+// WithCheckTxOverride sets a function answering CheckTx (and recheck) of some
+// transactions instead of the application.
+func WithCheckTxOverride(f CheckTxOverride) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.checkTxOverride = f }
+}
+
+// WithFCFSOrdering enables first-come-first-served ordering of transactions:
+// transactions are reaped strictly in order of arrival, regardless of their
+// priority and nonce.
+func WithFCFSOrdering() TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.fcfs = true }
+}
+
 // WithMetrics sets the mempool's metrics collector.
 func WithMetrics(metrics *mempool.Metrics) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.metrics = metrics }
@@ -177,44 +236,8 @@ func (txmp *TxMempool) TxsAvailable() <-chan struct{} { return txmp.txsAvailable
 // the size of tx, and adds tx instead. If no such transactions exist, tx is
 // discarded.
 func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo mempool.TxInfo) error {
-
-	// During the initial phase of CheckTx, we do not need to modify any state.
-	// A transaction will not actually be added to the mempool until it survives
-	// a call to the ABCI CheckTx method and size constraint checks.
-	height, err := func() (uint64, error) {
-		txmp.mtx.RLock()
-		defer txmp.mtx.RUnlock()
-
-		// Reject transactions in excess of the configured maximum transaction size.
-		if len(tx) > txmp.config.MaxTxBytes {
-			return 0, mempool.ErrTxTooLarge{Max: txmp.config.MaxTxBytes, Actual: len(tx)}
-		}
-
-		// If a precheck hook is defined, call it before invoking the application.
-		if txmp.preCheck != nil {
-			if err := txmp.preCheck(tx); err != nil {
-				return 0, mempool.ErrPreCheck{Reason: err}
-			}
-		}
-
-		// Early exit if the proxy connection has an error.
-		if err := txmp.proxyAppConn.Error(); err != nil {
-			return 0, err
-		}
-
-		txKey := tx.Key()
-
-		// Check for the transaction in the cache.
-		if !txmp.cache.Push(tx) {
-			// If the cached transaction is also in the pool, record its sender.
-			if elt, ok := txmp.txByKey[txKey]; ok {
-				w := elt.Value.(*WrappedTx)
-				w.SetPeer(txInfo.SenderID)
-			}
-			return 0, mempool.ErrTxInCache
-		}
-		return txmp.height, nil
-	}()
+	received := time.Now().UTC()
+	height, err := txmp.precheckTx(tx, txInfo)
 	if err != nil {
 		return err
 	}
@@ -226,15 +249,87 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 	// even an "async" call invokes its callback immediately which will make
 	// the callback deadlock trying to acquire the same lock. This isn't a
 	// problem with out-of-process calls, but this has to work for both.
-	rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: tx})
-	if err != nil {
-		txmp.cache.Remove(tx)
-		return err
+	appTx, rsp := txmp.overrideCheckTx(tx)
+	if rsp == nil {
+		rsp, err = txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: appTx})
+		if err != nil {
+			txmp.cache.Remove(tx)
+			return err
+		}
+	}
+	txmp.handleCheckTxResponse(tx, height, received, rsp, cb, txInfo)
+	return nil
+}
+
+// overrideCheckTx returns the response of the CheckTx override to tx, or nil
+// response and the transaction that should be checked by the application.
+func (txmp *TxMempool) overrideCheckTx(tx types.Tx) (types.Tx, *abci.ResponseCheckTx) {
+	if txmp.checkTxOverride == nil {
+		return tx, nil
+	}
+	return txmp.checkTxOverride(tx)
+}
+
+// precheckTx validates tx before it's sent to the application, and records it
+// in the cache. It returns the current height of the mempool.
+//
+// During the initial phase of CheckTx, we do not need to modify any state.
+// A transaction will not actually be added to the mempool until it survives
+// a call to the ABCI CheckTx method and size constraint checks.
+func (txmp *TxMempool) precheckTx(tx types.Tx, txInfo mempool.TxInfo) (uint64, error) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	// Reject transactions in excess of the configured maximum transaction size.
+	if len(tx) > txmp.config.MaxTxBytes {
+		return 0, mempool.ErrTxTooLarge{Max: txmp.config.MaxTxBytes, Actual: len(tx)}
+	}
+
+	// If a precheck hook is defined, call it before invoking the application.
+	if txmp.preCheck != nil {
+		if err := txmp.preCheck(tx); err != nil {
+			return 0, mempool.ErrPreCheck{Reason: err}
+		}
+	}
+
+	if txmp.txFilter != nil {
+		if err := txmp.txFilter.FilterTx(tx); err != nil {
+			return 0, err
+		}
+	}
+
+	// Early exit if the proxy connection has an error.
+	if err := txmp.proxyAppConn.Error(); err != nil {
+		return 0, err
+	}
+
+	txKey := tx.Key()
+
+	// Check for the transaction in the cache. Transaction already in the
+	// pool is never checked again, even if its cache entry expired.
+	if !txmp.cache.Push(tx) {
+		// If the cached transaction is also in the pool, record its sender.
+		if elt, ok := txmp.txByKey[txKey]; ok {
+			w := elt.Value.(*WrappedTx)
+			w.SetPeer(txInfo.SenderID)
+		}
+		return 0, mempool.ErrTxInCache
+	}
+	if elt, ok := txmp.txByKey[txKey]; ok {
+		w := elt.Value.(*WrappedTx)
+		w.SetPeer(txInfo.SenderID)
+		return 0, mempool.ErrTxInCache
 	}
+	return txmp.height, nil
+}
+
+// handleCheckTxResponse handles the response of the application to the
+// initial CheckTx of tx received at given time, and reports it to cb (if not nil).
+func (txmp *TxMempool) handleCheckTxResponse(tx types.Tx, height uint64, received time.Time, rsp *abci.ResponseCheckTx, cb func(*abci.Response), txInfo mempool.TxInfo) {
 	wtx := &WrappedTx{
 		tx:        tx,
 		hash:      tx.Key(),
-		timestamp: time.Now().UTC(),
+		timestamp: received,
 		height:    height,
 	}
 	wtx.SetPeer(txInfo.SenderID)
@@ -242,7 +337,6 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 	if cb != nil {
 		cb(&abci.Response{Value: &abci.Response_CheckTx{CheckTx: rsp}})
 	}
-	return nil
 }
 
 // RemoveTxByKey removes the transaction with the specified key from the
@@ -260,7 +354,7 @@ func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 	if elt, ok := txmp.txByKey[key]; ok {
 		w := elt.Value.(*WrappedTx)
 		delete(txmp.txByKey, key)
-		delete(txmp.txBySender, w.sender)
+		txmp.removeSender(w)
 		txmp.txs.Remove(elt)
 		elt.DetachPrev()
 		elt.DetachNext()
@@ -275,13 +369,25 @@ func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 func (txmp *TxMempool) removeTxByElement(elt *clist.CElement) {
 	w := elt.Value.(*WrappedTx)
 	delete(txmp.txByKey, w.tx.Key())
-	delete(txmp.txBySender, w.sender)
+	txmp.removeSender(w)
 	txmp.txs.Remove(elt)
 	elt.DetachPrev()
 	elt.DetachNext()
 	atomic.AddInt64(&txmp.txsBytes, -w.Size())
 }
 
+// removeSender removes the specified transaction from the sender indexes.
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) removeSender(w *WrappedTx) {
+	if w.sender == "" {
+		return
+	}
+	delete(txmp.txBySender, w.senderKey())
+	if txmp.senderTxs[w.sender]--; txmp.senderTxs[w.sender] <= 0 {
+		delete(txmp.senderTxs, w.sender)
+	}
+}
+
 // Flush purges the contents of the mempool and the cache, leaving both empty.
 // The current height is not modified by this operation.
 func (txmp *TxMempool) Flush() {
@@ -305,8 +411,15 @@ func (txmp *TxMempool) Flush() {
 
 // allEntriesSorted returns a slice of all the transactions currently in the
 // mempool, sorted in nonincreasing order by priority with ties broken by
-// increasing order of arrival time.
+// increasing order of arrival time. If nonce ordering is enabled, transactions
+// of each sender are additionally ordered by nonce. If FCFS ordering is
+// enabled, transactions are sorted by arrival time only.
+//
+// If a recheck is in progress, it waits until the recheck is complete, so that
+// transactions invalidated by the latest block are never returned.
 func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
+	txmp.waitForRecheck()
+
 	txmp.mtx.RLock()
 	defer txmp.mtx.RUnlock()
 
@@ -314,12 +427,24 @@ func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 	for _, tx := range txmp.txByKey {
 		all = append(all, tx.Value.(*WrappedTx))
 	}
+	if txmp.fcfs {
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].timestamp.Equal(all[j].timestamp) {
+				return bytes.Compare(all[i].hash[:], all[j].hash[:]) < 0
+			}
+			return all[i].timestamp.Before(all[j].timestamp)
+		})
+		return all
+	}
 	sort.Slice(all, func(i, j int) bool {
 		if all[i].priority == all[j].priority {
 			return all[i].timestamp.Before(all[j].timestamp)
 		}
 		return all[i].priority > all[j].priority // N.B. higher priorities first
 	})
+	if txmp.nonceFunc != nil {
+		return orderByNonce(all)
+	}
 	return all
 }
 
@@ -337,12 +462,20 @@ func (txmp *TxMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	var totalGas, totalBytes int64
 
 	var keep []types.Tx
+	skipped := make(map[string]bool) // senders with skipped nonce-ordered transactions
 	for _, w := range txmp.allEntriesSorted() {
+		// Transactions following a skipped nonce would fail, so skip them too.
+		if w.hasNonce && skipped[w.sender] {
+			continue
+		}
 		// N.B. When computing byte size, we need to include the overhead for
 		// encoding as protobuf to send to the application. This actually overestimates it
 		// as we add the proto overhead to each transaction
 		txBytes := types.ComputeProtoSizeForTxs([]types.Tx{w.tx})
 		if (maxGas >= 0 && totalGas+w.gasWanted > maxGas) || (maxBytes >= 0 && totalBytes+txBytes > maxBytes) {
+			if w.hasNonce {
+				skipped[w.sender] = true
+			}
 			continue
 		}
 		totalBytes += txBytes
@@ -352,6 +485,30 @@ func (txmp *TxMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	return keep
 }
 
+// ReceiveTime returns the time of arrival of the transaction, if it's in the
+// mempool.
+func (txmp *TxMempool) ReceiveTime(tx types.Tx) (time.Time, bool) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+	elt, ok := txmp.txByKey[tx.Key()]
+	if !ok {
+		return time.Time{}, false
+	}
+	return elt.Value.(*WrappedTx).timestamp, true
+}
+
+// GasWanted returns the gas wanted by the transaction according to its CheckTx
+// response, if it's in the mempool.
+func (txmp *TxMempool) GasWanted(tx types.Tx) (int64, bool) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+	elt, ok := txmp.txByKey[tx.Key()]
+	if !ok {
+		return 0, false
+	}
+	return elt.Value.(*WrappedTx).gasWanted, true
+}
+
 // TxsWaitChan returns a channel that is closed when there is at least one
 // transaction available to be gossiped.
 func (txmp *TxMempool) TxsWaitChan() <-chan struct{} { return txmp.txs.WaitChan() }
@@ -380,6 +537,28 @@ func (txmp *TxMempool) ReapMaxTxs(max int) types.Txs {
 	return keep
 }
 
+// ListTxs returns up to limit transactions from the mempool, skipping the
+// first offset transactions. The results are ordered like in ReapMaxTxs.
+// Listing transactions does not remove them from the mempool.
+//
+// If limit < 0, all transactions following offset are returned.
+func (txmp *TxMempool) ListTxs(offset, limit int) types.Txs {
+	all := txmp.allEntriesSorted()
+	if offset < 0 || offset >= len(all) {
+		return nil
+	}
+	all = all[offset:]
+	if limit >= 0 && limit < len(all) {
+		all = all[:limit]
+	}
+
+	txs := make(types.Txs, len(all))
+	for i, w := range all {
+		txs[i] = w.tx
+	}
+	return txs
+}
+
 // Update removes all the given transactions from the mempool and the cache,
 // and updates the current block height. The blockTxs and deliverTxResponses
 // must have the same length with each response corresponding to the tx at the
@@ -499,29 +678,118 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 		return
 	}
 
+	if txmp.txFilter != nil {
+		if err := txmp.txFilter.FilterSender(checkTxRes.Sender); err != nil {
+			txmp.logger.Debug(
+				"rejected valid incoming transaction; sender filtered",
+				"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+				"sender", checkTxRes.Sender,
+				"err", err,
+			)
+			checkTxRes.Code = mempool.CodeTxFiltered
+			checkTxRes.Codespace = mempool.Codespace
+			checkTxRes.Log = err.Error()
+			checkTxRes.MempoolError = err.Error()
+			txmp.metrics.RejectedTxs.Add(1)
+			// throttled transactions can be resubmitted later
+			if errors.Is(err, mempool.ErrTxThrottled) {
+				txmp.cache.Remove(wtx.tx)
+			}
+			return
+		}
+	}
+
+	if txmp.feeFunc != nil {
+		if fee, ok := txmp.feeFunc(checkTxRes); !ok || fee < txmp.minFee {
+			txmp.logger.Debug(
+				"rejected valid incoming transaction; fee too low",
+				"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+				"fee", fee,
+				"min_fee", txmp.minFee,
+			)
+			checkTxRes.Code = mempool.CodeTxFeeTooLow
+			checkTxRes.Codespace = mempool.Codespace
+			checkTxRes.Log = fmt.Sprintf("fee %d is lower than the minimal fee %d", fee, txmp.minFee)
+			checkTxRes.MempoolError = checkTxRes.Log
+			txmp.metrics.RejectedTxs.Add(1)
+			txmp.metrics.ThrottledTxs.With("reason", mempool.ThrottledMinFee).Add(1)
+			return
+		}
+	}
+
 	priority := checkTxRes.Priority
 	sender := checkTxRes.Sender
+	wtx.SetSender(sender)
+	if txmp.nonceFunc != nil && sender != "" {
+		if nonce, ok := txmp.nonceFunc(checkTxRes); ok {
+			wtx.SetNonce(nonce)
+		}
+	}
 
 	// Disallow multiple concurrent transactions from the same sender assigned
-	// by the ABCI application. As a special case, an empty sender is not
+	// by the ABCI application (or with the same sender and nonce, if ordered by
+	// nonce), unless replacement is enabled and the new transaction has
+	// sufficiently higher priority. As a special case, an empty sender is not
 	// restricted.
 	if sender != "" {
-		elt, ok := txmp.txBySender[sender]
-		if ok {
+		elt, ok := txmp.txBySender[wtx.senderKey()]
+		if ok && txmp.replaceBump > 0 {
+			w := elt.Value.(*WrappedTx)
+			if !canReplace(w.priority, priority, txmp.replaceBump) {
+				txmp.cache.Remove(wtx.tx)
+				txmp.logger.Debug(
+					"rejected valid incoming transaction; priority too low to replace tx of sender",
+					"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+					"old_tx", fmt.Sprintf("%X", w.tx.Hash()),
+					"sender", wtx.senderKey(),
+				)
+				checkTxRes.Code = mempool.CodeTxUnderpriced
+				checkTxRes.Codespace = mempool.Codespace
+				checkTxRes.Log = fmt.Sprintf("priority %d too low to replace tx of sender %q with priority %d, minimal increase is %d%%",
+					priority, wtx.senderKey(), w.priority, txmp.replaceBump)
+				checkTxRes.MempoolError = checkTxRes.Log
+				txmp.metrics.RejectedTxs.Add(1)
+				return
+			}
+
+			txmp.logger.Debug(
+				"replacing existing transaction of sender",
+				"old_tx", fmt.Sprintf("%X", w.tx.Hash()),
+				"new_tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+				"sender", wtx.senderKey(),
+			)
+			txmp.removeTxByElement(elt)
+			txmp.cache.Remove(w.tx)
+			txmp.metrics.EvictedTxs.Add(1)
+		} else if ok {
 			w := elt.Value.(*WrappedTx)
 			txmp.logger.Debug(
 				"rejected valid incoming transaction; tx already exists for sender",
 				"tx", fmt.Sprintf("%X", w.tx.Hash()),
-				"sender", sender,
+				"sender", wtx.senderKey(),
 			)
 			checkTxRes.MempoolError =
 				fmt.Sprintf("rejected valid incoming transaction; tx already exists for sender %q (%X)",
-					sender, w.tx.Hash())
+					wtx.senderKey(), w.tx.Hash())
 			txmp.metrics.RejectedTxs.Add(1)
 			return
 		}
 	}
 
+	if txmp.maxTxsPerSender > 0 && sender != "" && txmp.senderTxs[sender] >= txmp.maxTxsPerSender {
+		txmp.cache.Remove(wtx.tx)
+		txmp.logger.Debug(
+			"rejected valid incoming transaction; too many transactions of sender",
+			"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+			"sender", sender,
+		)
+		checkTxRes.MempoolError =
+			fmt.Sprintf("rejected valid incoming transaction; sender %q reached the limit of %d transactions (%X)",
+				sender, txmp.maxTxsPerSender, wtx.tx.Hash())
+		txmp.metrics.RejectedTxs.Add(1)
+		return
+	}
+
 	// At this point the application has ruled the transaction valid, but the
 	// mempool might be full. If so, find the lowest-priority items with lower
 	// priority than the application assigned to this new one, and evict as many
@@ -562,14 +830,18 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 		)
 
 		// Sort lowest priority items first so they will be evicted first.  Break
-		// ties in favor of newer items (to maintain FIFO semantics in a group).
+		// ties in favor of older items, and then by hash, so that eviction is
+		// deterministic.
 		sort.Slice(victims, func(i, j int) bool {
 			iw := victims[i].Value.(*WrappedTx)
 			jw := victims[j].Value.(*WrappedTx)
-			if iw.Priority() == jw.Priority() {
-				return iw.timestamp.After(jw.timestamp)
+			if iw.Priority() != jw.Priority() {
+				return iw.Priority() < jw.Priority()
 			}
-			return iw.Priority() < jw.Priority()
+			if !iw.timestamp.Equal(jw.timestamp) {
+				return iw.timestamp.Before(jw.timestamp)
+			}
+			return bytes.Compare(iw.hash[:], jw.hash[:]) < 0
 		})
 
 		// Evict as many of the victims as necessary to make room.
@@ -597,7 +869,6 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 
 	wtx.SetGasWanted(checkTxRes.GasWanted)
 	wtx.SetPriority(priority)
-	wtx.SetSender(sender)
 	txmp.insertTx(wtx)
 
 	txmp.metrics.TxSizeBytes.Observe(float64(wtx.Size()))
@@ -616,7 +887,8 @@ func (txmp *TxMempool) insertTx(wtx *WrappedTx) {
 	elt := txmp.txs.PushBack(wtx)
 	txmp.txByKey[wtx.tx.Key()] = elt
 	if s := wtx.Sender(); s != "" {
-		txmp.txBySender[s] = elt
+		txmp.txBySender[wtx.senderKey()] = elt
+		txmp.senderTxs[s]++
 	}
 
 	atomic.AddInt64(&txmp.txsBytes, wtx.Size())
@@ -692,15 +964,24 @@ func (txmp *TxMempool) recheckTransactions() {
 
 	// Issue CheckTx calls for each remaining transaction, and when all the
 	// rechecks are complete signal watchers that transactions may be available.
+	done := make(chan struct{})
+	txmp.recheckDone = done
 	go func() {
+		defer close(done)
+
 		g, start := taskgroup.New(nil).Limit(2 * runtime.NumCPU())
 
 		for _, wtx := range wtxs {
 			wtx := wtx
 			start(func() error {
+				appTx, rsp := txmp.overrideCheckTx(wtx.tx)
+				if rsp != nil {
+					txmp.handleRecheckResult(wtx.tx, rsp)
+					return nil
+				}
 				// The response for this CheckTx is handled by the default recheckTxCallback.
 				rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{
-					Tx:   wtx.tx,
+					Tx:   appTx,
 					Type: abci.CheckTxType_Recheck,
 				})
 				if err != nil {
@@ -741,6 +1022,48 @@ func (txmp *TxMempool) canAddTx(wtx *WrappedTx) error {
 	return nil
 }
 
+// PurgeExpiredTxs removes all transactions from the mempool that have exceeded
+// their time-to-live as of the latest height passed to Update.
+func (txmp *TxMempool) PurgeExpiredTxs() {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+
+	txmp.purgeExpiredTxs(txmp.height)
+	txmp.metrics.Size.Set(float64(txmp.Size()))
+}
+
+// ExpirationLoop periodically purges transactions that have exceeded the
+// time-based limit, so they don't occupy the mempool until the next Update.
+// It returns immediately if TTLDuration is not configured.
+func (txmp *TxMempool) ExpirationLoop(ctx context.Context) {
+	if txmp.config.TTLDuration <= 0 {
+		return
+	}
+
+	// transactions live at most 1.25 * TTLDuration
+	ticker := time.NewTicker(txmp.config.TTLDuration / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			txmp.PurgeExpiredTxs()
+		}
+	}
+}
+
+// waitForRecheck blocks until the recheck started by the latest Update (if
+// any) is complete.
+func (txmp *TxMempool) waitForRecheck() {
+	txmp.mtx.RLock()
+	done := txmp.recheckDone
+	txmp.mtx.RUnlock()
+	if done != nil {
+		<-done
+	}
+}
+
 // purgeExpiredTxs removes all transactions from the mempool that have exceeded
 // their respective height or time-based limits as of the given blockHeight.
 // Transactions removed by this operation are not removed from the cache.
@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
 	"sync"
@@ -139,17 +141,17 @@ func TestTxMempool_Eviction(t *testing.T) {
 	mustCheckTx(t, txmp, "key6=0005=1")
 	require.False(t, txExists("key6=0005=1"))
 
-	// A new transaction with higher priority should evict key5, which is the
-	// newest of the two transactions with lowest priority.
+	// A new transaction with higher priority should evict key4, which is the
+	// oldest of the two transactions with lowest priority.
 	mustCheckTx(t, txmp, "key7=0006=7")
 	require.True(t, txExists("key7=0006=7"))  // new transaction added
-	require.False(t, txExists("key5=0004=3")) // newest low-priority tx evicted
-	require.True(t, txExists("key4=0003=3"))  // older low-priority tx retained
+	require.False(t, txExists("key4=0003=3")) // oldest low-priority tx evicted
+	require.True(t, txExists("key5=0004=3"))  // newer low-priority tx retained
 
 	// Another new transaction evicts the other low-priority element.
 	mustCheckTx(t, txmp, "key8=0007=20")
 	require.True(t, txExists("key8=0007=20"))
-	require.False(t, txExists("key4=0003=3"))
+	require.False(t, txExists("key5=0004=3"))
 
 	// Now the lowest-priority tx is 5, so that should be the next to go.
 	mustCheckTx(t, txmp, "key9=0008=9")
@@ -350,6 +352,215 @@ func TestTxMempool_CheckTxSameSender(t *testing.T) {
 	require.Equal(t, 1, txmp.Size())
 }
 
+func TestTxMempool_ListTxs(t *testing.T) {
+	txmp := setup(t, 0)
+	tTxs := checkTxs(t, txmp, 10, 0)
+	require.Equal(t, len(tTxs), txmp.Size())
+
+	all := txmp.ReapMaxTxs(-1)
+	require.Equal(t, all, txmp.ListTxs(0, -1))
+	require.Equal(t, all[:3], txmp.ListTxs(0, 3))
+	require.Equal(t, all[3:6], txmp.ListTxs(3, 3))
+	require.Equal(t, all[9:], txmp.ListTxs(9, 3))
+	require.Empty(t, txmp.ListTxs(10, 3))
+	require.Empty(t, txmp.ListTxs(-1, 3))
+}
+
+func TestTxMempool_NonceOrdering(t *testing.T) {
+	txmp := setup(t, 100, WithNonceOrdering(EventNonce("tx", "nonce")))
+
+	// later nonce with the highest priority pulls the earlier nonces forward
+	a0 := "alice=a0-with-a-key-long-enough-to-not-fit=50=0"
+	mustCheckTx(t, txmp, "alice=a2=300=2")
+	mustCheckTx(t, txmp, "bob=b0=200=0")
+	mustCheckTx(t, txmp, "alice=a1=100=1")
+	mustCheckTx(t, txmp, a0)
+	mustCheckTx(t, txmp, "carol=c0=150")
+	require.Equal(t, 5, txmp.Size())
+
+	// duplicate sender and nonce is rejected
+	mustCheckTx(t, txmp, "alice=a1x=100=1")
+	require.Equal(t, 5, txmp.Size())
+
+	require.Equal(t, types.Txs{
+		types.Tx(a0),
+		types.Tx("bob=b0=200=0"),
+		types.Tx("carol=c0=150"),
+		types.Tx("alice=a1=100=1"),
+		types.Tx("alice=a2=300=2"),
+	}, txmp.ReapMaxTxs(-1))
+
+	// transactions following a nonce that doesn't fit are not reaped
+	maxBytes := types.ComputeProtoSizeForTxs([]types.Tx{types.Tx("bob=b0=200=0"), types.Tx("carol=c0=150")})
+	require.Equal(t, types.Txs{
+		types.Tx("bob=b0=200=0"),
+		types.Tx("carol=c0=150"),
+	}, txmp.ReapMaxBytesMaxGas(maxBytes, -1))
+}
+
+func TestTxMempool_FCFSOrdering(t *testing.T) {
+	txmp := setup(t, 100, WithNonceOrdering(EventNonce("tx", "nonce")), WithFCFSOrdering())
+
+	// priority and nonce are ignored
+	for _, tx := range []string{"alice=a1=100=1", "bob=b0=300=0", "alice=a0=200=0"} {
+		mustCheckTx(t, txmp, tx)
+		time.Sleep(time.Millisecond) // avoid equal receive times
+	}
+	require.Equal(t, types.Txs{
+		types.Tx("alice=a1=100=1"),
+		types.Tx("bob=b0=300=0"),
+		types.Tx("alice=a0=200=0"),
+	}, txmp.ReapMaxTxs(-1))
+
+	first, ok := txmp.ReceiveTime(types.Tx("alice=a1=100=1"))
+	require.True(t, ok)
+	last, ok := txmp.ReceiveTime(types.Tx("alice=a0=200=0"))
+	require.True(t, ok)
+	require.False(t, last.Before(first))
+	_, ok = txmp.ReceiveTime(types.Tx("carol=c0=100"))
+	require.False(t, ok)
+}
+
+func TestTxMempool_ReplaceByFee(t *testing.T) {
+	txmp := setup(t, 100, WithNonceOrdering(EventNonce("tx", "nonce")), WithReplaceByFee(10))
+
+	checkTx := func(tx string) *abci.ResponseCheckTx {
+		resCh := make(chan *abci.ResponseCheckTx, 1)
+		require.NoError(t, txmp.CheckTx(types.Tx(tx), func(r *abci.Response) {
+			resCh <- r.GetCheckTx()
+		}, mempool.TxInfo{}))
+		return <-resCh
+	}
+
+	require.Equal(t, abci.CodeTypeOK, checkTx("alice=a1=100=1").Code)
+	require.Equal(t, abci.CodeTypeOK, checkTx("alice=a2=100=2").Code)
+
+	// insufficient priority bump
+	res := checkTx("alice=a1b=109=1")
+	require.Equal(t, mempool.CodeTxUnderpriced, res.Code)
+	require.Equal(t, mempool.Codespace, res.Codespace)
+	require.Equal(t, types.Txs{types.Tx("alice=a1=100=1"), types.Tx("alice=a2=100=2")}, txmp.ReapMaxTxs(-1))
+
+	// replacement
+	require.Equal(t, abci.CodeTypeOK, checkTx("alice=a1c=110=1").Code)
+	require.Equal(t, 2, txmp.Size())
+	require.Equal(t, types.Txs{types.Tx("alice=a1c=110=1"), types.Tx("alice=a2=100=2")}, txmp.ReapMaxTxs(-1))
+	require.False(t, txmp.cache.Has(types.Tx("alice=a1=100=1")))
+}
+
+func TestTxMempool_MaxTxsPerSender(t *testing.T) {
+	txmp := setup(t, 100, WithNonceOrdering(EventNonce("tx", "nonce")), WithMaxTxsPerSender(2))
+
+	mustCheckTx(t, txmp, "alice=a0=100=0")
+	mustCheckTx(t, txmp, "alice=a1=100=1")
+	mustCheckTx(t, txmp, "alice=a2=100=2")
+	mustCheckTx(t, txmp, "bob=b0=100=0")
+	require.Equal(t, 3, txmp.Size())
+	require.False(t, txmp.cache.Has(types.Tx("alice=a2=100=2")))
+
+	// limit applies to transactions currently in the mempool
+	require.NoError(t, txmp.RemoveTxByKey(types.Tx("alice=a0=100=0").Key()))
+	mustCheckTx(t, txmp, "alice=a2=100=2")
+	require.Equal(t, 3, txmp.Size())
+	require.Equal(t, 2, txmp.senderTxs["alice"])
+}
+
+func TestTxMempool_TxFilter(t *testing.T) {
+	txmp := setup(t, 100, WithTxFilter(mempool.NewSenderListFilter(nil, []string{"mallory"})))
+
+	resCh := make(chan *abci.ResponseCheckTx, 1)
+	cb := func(r *abci.Response) { resCh <- r.GetCheckTx() }
+
+	require.NoError(t, txmp.CheckTx(types.Tx("alice=a0=100"), cb, mempool.TxInfo{}))
+	require.Equal(t, abci.CodeTypeOK, (<-resCh).Code)
+
+	require.NoError(t, txmp.CheckTx(types.Tx("mallory=m0=100"), cb, mempool.TxInfo{}))
+	res := <-resCh
+	require.Equal(t, mempool.CodeTxFiltered, res.Code)
+	require.Equal(t, mempool.Codespace, res.Codespace)
+	require.Equal(t, types.Txs{types.Tx("alice=a0=100")}, txmp.ReapMaxTxs(-1))
+
+	// repeated transactions of filtered senders are dropped without CheckTx
+	require.ErrorIs(t, txmp.CheckTx(types.Tx("mallory=m0=100"), nil, mempool.TxInfo{}), mempool.ErrTxInCache)
+
+	// raw transactions are filtered before CheckTx
+	txmp = setup(t, 100, WithTxFilter(mempool.TxPredicateFilter(func(tx types.Tx) bool { return len(tx) < 20 })))
+	require.ErrorIs(t, txmp.CheckTx(types.Tx("alice=a0-long-key=100"), nil, mempool.TxInfo{}), mempool.ErrTxFiltered)
+	require.Equal(t, 0, txmp.Size())
+}
+
+func TestTxMempool_SenderRateFilter(t *testing.T) {
+	txmp := setup(t, 100, WithTxFilter(mempool.NewSenderRateFilter(0.001, 1, nil)))
+
+	resCh := make(chan *abci.ResponseCheckTx, 1)
+	cb := func(r *abci.Response) { resCh <- r.GetCheckTx() }
+
+	require.NoError(t, txmp.CheckTx(types.Tx("alice=a0=100"), cb, mempool.TxInfo{}))
+	require.Equal(t, abci.CodeTypeOK, (<-resCh).Code)
+	require.NoError(t, txmp.CheckTx(types.Tx("alice=a1=100"), cb, mempool.TxInfo{}))
+	require.Equal(t, mempool.CodeTxFiltered, (<-resCh).Code)
+
+	// throttled transactions can be resubmitted
+	require.False(t, txmp.cache.Has(types.Tx("alice=a1=100")))
+	require.NoError(t, txmp.CheckTx(types.Tx("bob=b0=100"), cb, mempool.TxInfo{}))
+	require.Equal(t, abci.CodeTypeOK, (<-resCh).Code)
+}
+
+func TestTxMempool_MinFee(t *testing.T) {
+	// the test application reports the nonce in events, it's used as the fee
+	txmp := setup(t, 100, WithMinFee(EventFee("tx", "nonce"), 10))
+
+	resCh := make(chan *abci.ResponseCheckTx, 1)
+	cb := func(r *abci.Response) { resCh <- r.GetCheckTx() }
+
+	require.NoError(t, txmp.CheckTx(types.Tx("alice=a0=100=10"), cb, mempool.TxInfo{}))
+	require.Equal(t, abci.CodeTypeOK, (<-resCh).Code)
+	require.NoError(t, txmp.CheckTx(types.Tx("bob=b0=100=9"), cb, mempool.TxInfo{}))
+	res := <-resCh
+	require.Equal(t, mempool.CodeTxFeeTooLow, res.Code)
+	require.Equal(t, mempool.Codespace, res.Codespace)
+	require.NoError(t, txmp.CheckTx(types.Tx("carol=c0=100"), cb, mempool.TxInfo{}))
+	require.Equal(t, mempool.CodeTxFeeTooLow, (<-resCh).Code)
+	require.Equal(t, types.Txs{types.Tx("alice=a0=100=10")}, txmp.ReapMaxTxs(-1))
+}
+
+func TestEventFee(t *testing.T) {
+	f := EventFee("tx", "fee")
+	res := func(value string) *abci.ResponseCheckTx {
+		return &abci.ResponseCheckTx{Events: []abci.Event{{Type: "tx", Attributes: []abci.EventAttribute{{Key: "fee", Value: value}}}}}
+	}
+
+	fee, ok := f(res("100stake"))
+	require.True(t, ok)
+	require.Equal(t, uint64(100), fee)
+	fee, ok = f(res("42"))
+	require.True(t, ok)
+	require.Equal(t, uint64(42), fee)
+	_, ok = f(res("stake"))
+	require.False(t, ok)
+	_, ok = f(&abci.ResponseCheckTx{})
+	require.False(t, ok)
+}
+
+func TestCanReplace(t *testing.T) {
+	cases := []struct {
+		old, new int64
+		bump     uint64
+		expected bool
+	}{
+		{100, 100, 0, false},
+		{100, 101, 0, true},
+		{100, 109, 10, false},
+		{100, 110, 10, true},
+		{-100, -90, 10, true},
+		{0, 1, 10, true},
+		{math.MaxInt64 - 1, math.MaxInt64, 10, false},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, canReplace(c.old, c.new, c.bump), "%d -> %d (%d%%)", c.old, c.new, c.bump)
+	}
+}
+
 func TestTxMempool_ConcurrentTxs(t *testing.T) {
 	txmp := setup(t, 100)
 	rng := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
@@ -462,6 +673,54 @@ func TestTxMempool_ExpiredTxs_Timestamp(t *testing.T) {
 	}
 }
 
+func TestTxMempool_Recheck(t *testing.T) {
+	txmp := setup(t, 500)
+	require.True(t, txmp.config.Recheck)
+
+	tTxs := checkTxs(t, txmp, 100, 0)
+	require.Equal(t, len(tTxs), txmp.Size())
+
+	// after the block, low-priority transactions become invalid
+	postCheck := func(tx types.Tx, res *abci.ResponseCheckTx) error {
+		if res.Priority < 5000 {
+			return errors.New("priority too low")
+		}
+		return nil
+	}
+	txmp.Lock()
+	require.NoError(t, txmp.Update(1, nil, nil, nil, postCheck))
+	txmp.Unlock()
+
+	// reaping waits for the recheck, so invalid transactions are never reaped
+	valid := 0
+	for _, tx := range tTxs {
+		if tx.priority >= 5000 {
+			valid++
+		}
+	}
+	reaped := txmp.ReapMaxTxs(-1)
+	require.Len(t, reaped, valid)
+	require.Equal(t, valid, txmp.Size())
+}
+
+func TestTxMempool_ExpirationLoop(t *testing.T) {
+	txmp := setup(t, 5000)
+	txmp.config.TTLDuration = 200 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go txmp.ExpirationLoop(ctx)
+
+	added := checkTxs(t, txmp, 10, 0)
+	require.Equal(t, len(added), txmp.Size())
+
+	// transactions are purged without any call to Update
+	require.Eventually(t, func() bool { return txmp.Size() == 0 }, time.Second, 10*time.Millisecond)
+	for _, tx := range added {
+		require.False(t, txmp.cache.Has(tx.tx))
+	}
+}
+
 func TestTxMempool_ExpiredTxs_NumBlocks(t *testing.T) {
 	txmp := setup(t, 500)
 	txmp.height = 100
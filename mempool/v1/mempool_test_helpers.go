@@ -36,10 +36,16 @@ func (app *application) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
 	var (
 		priority int64
 		sender   string
+		events   []abci.Event
 	)
 
-	// infer the priority from the raw transaction value (sender=key=value)
+	// infer the priority from the raw transaction value (sender=key=value),
+	// optionally followed by the nonce (sender=key=value=nonce)
 	parts := bytes.Split(req.Tx, []byte("="))
+	if len(parts) == 4 {
+		events = []abci.Event{{Type: "tx", Attributes: []abci.EventAttribute{{Key: "nonce", Value: string(parts[3])}}}}
+		parts = parts[:3]
+	}
 	if len(parts) == 3 {
 		v, err := strconv.ParseInt(string(parts[2]), 10, 64)
 		if err != nil {
@@ -63,6 +69,7 @@ func (app *application) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
 	return abci.ResponseCheckTx{
 		Priority:  priority,
 		Sender:    sender,
+		Events:    events,
 		Code:      abci.CodeTypeOK,
 		GasWanted: 1,
 	}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"sort"
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+)
+
+// NonceFunc extracts the application-assigned nonce (sequence number) of a
+// transaction from its CheckTx response. It reports false if the transaction
+// has no nonce.
+type NonceFunc func(checkTxRes *abci.ResponseCheckTx) (uint64, bool)
+
+// WithNonceOrdering enables nonce-aware ordering of transactions. Transactions
+// of the same sender with different nonces are accepted concurrently (only one
+// transaction per sender and nonce is allowed), and reaped in increasing order
+// of nonces, so that later transactions of a sender are never included before
+// the earlier ones.
+//
+// Only transactions with non-empty sender are ordered by nonce.
+func WithNonceOrdering(f NonceFunc) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.nonceFunc = f }
+}
+
+// EventNonce returns a NonceFunc reading the nonce from the attribute attrKey
+// of the first event of type eventType in the CheckTx response.
+func EventNonce(eventType, attrKey string) NonceFunc {
+	return func(checkTxRes *abci.ResponseCheckTx) (uint64, bool) {
+		for _, event := range checkTxRes.Events {
+			if event.Type != eventType {
+				continue
+			}
+			for _, attr := range event.Attributes {
+				if attr.Key == attrKey {
+					nonce, err := strconv.ParseUint(attr.Value, 10, 64)
+					return nonce, err == nil
+				}
+			}
+		}
+		return 0, false
+	}
+}
+
+// orderByNonce reorders transactions sorted by priority, so that transactions
+// of each sender are in increasing order of nonces. Transactions of a sender
+// keep the positions occupied by the sender in the input, so a high-priority
+// transaction moves transactions with lower nonces forward.
+func orderByNonce(sorted []*WrappedTx) []*WrappedTx {
+	queues := make(map[string][]*WrappedTx)
+	for _, w := range sorted {
+		if w.hasNonce {
+			queues[w.sender] = append(queues[w.sender], w)
+		}
+	}
+	for _, q := range queues {
+		sort.Slice(q, func(i, j int) bool { return q[i].nonce < q[j].nonce })
+	}
+
+	ordered := make([]*WrappedTx, len(sorted))
+	next := make(map[string]int, len(queues))
+	for i, w := range sorted {
+		if !w.hasNonce {
+			ordered[i] = w
+			continue
+		}
+		ordered[i] = queues[w.sender][next[w.sender]]
+		next[w.sender]++
+	}
+	return ordered
+}
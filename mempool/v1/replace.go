@@ -0,0 +1,24 @@
+package v1
+
+import "math"
+
+// WithReplaceByFee enables replacement of a transaction by a new transaction
+// of the same sender (and nonce, if ordered by nonce), if priority of the new
+// transaction is higher by at least bumpPercent percent. Transactions with
+// insufficient priority are rejected with mempool.CodeTxUnderpriced.
+//
+// Zero bumpPercent disables replacement.
+func WithReplaceByFee(bumpPercent uint64) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.replaceBump = bumpPercent }
+}
+
+// canReplace reports whether newPriority is higher than oldPriority by at
+// least bumpPercent percent.
+func canReplace(oldPriority, newPriority int64, bumpPercent uint64) bool {
+	if newPriority <= oldPriority {
+		return false
+	}
+	// float64 avoids overflows of large priorities
+	minBump := math.Abs(float64(oldPriority)) * float64(bumpPercent) / 100
+	return float64(newPriority)-float64(oldPriority) >= minBump
+}
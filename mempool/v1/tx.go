@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,12 +14,14 @@ type WrappedTx struct {
 	tx        types.Tx    // the original transaction data
 	hash      types.TxKey // the transaction hash
 	height    uint64      // height when this transaction was initially checked (for expiry)
-	timestamp time.Time   // time when transaction was entered (for TTL)
+	timestamp time.Time   // time when transaction was received (for TTL and FCFS ordering)
 
 	mtx       sync.Mutex
 	gasWanted int64           // app: gas required to execute this transaction
 	priority  int64           // app: priority value for this transaction
 	sender    string          // app: assigned sender label
+	nonce     uint64          // app: assigned nonce, if hasNonce is set
+	hasNonce  bool            // app: whether the transaction is ordered by nonce
 	peers     map[uint16]bool // peer IDs who have sent us this transaction
 }
 
@@ -72,6 +75,32 @@ func (w *WrappedTx) Sender() string {
 	return w.sender
 }
 
+// SetNonce sets the application-assigned nonce of w.
+func (w *WrappedTx) SetNonce(nonce uint64) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.nonce = nonce
+	w.hasNonce = true
+}
+
+// Nonce reports the application-assigned nonce of w, and whether it was set.
+func (w *WrappedTx) Nonce() (uint64, bool) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.nonce, w.hasNonce
+}
+
+// senderKey returns the key of w in the sender index: the sender, extended
+// with nonce for transactions ordered by nonce.
+func (w *WrappedTx) senderKey() string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if !w.hasNonce {
+		return w.sender
+	}
+	return w.sender + "/" + strconv.FormatUint(w.nonce, 10)
+}
+
 // SetPriority sets the application-assigned priority of w.
 func (w *WrappedTx) SetPriority(p int64) {
 	w.mtx.Lock()
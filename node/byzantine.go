@@ -0,0 +1,11 @@
+//go:build byzantine
+
+package node
+
+import "github.com/rollkit/rollkit/block"
+
+// SetByzantineBehavior makes the aggregator misbehave as configured. It's intended only for testing,
+// and has to be called before the node is started.
+func (n *FullNode) SetByzantineBehavior(b *block.ByzantineBehavior) {
+	n.blockManager.SetByzantineBehavior(b)
+}
@@ -19,9 +19,12 @@ func TestGetNodeKey(t *testing.T) {
 	valid := p2p.NodeKey{
 		PrivKey: privKey,
 	}
-	invalid := p2p.NodeKey{
+	validSecp256k1 := p2p.NodeKey{
 		PrivKey: secp256k1.GenPrivKey(),
 	}
+	invalid := p2p.NodeKey{
+		PrivKey: unsupportedPrivKey{secp256k1.GenPrivKey()},
+	}
 
 	cases := []struct {
 		name         string
@@ -33,6 +36,7 @@ func TestGetNodeKey(t *testing.T) {
 		{"empty", &p2p.NodeKey{}, pb.KeyType(-1), errNilKey},
 		{"invalid", &invalid, pb.KeyType(-1), errUnsupportedKeyType},
 		{"valid", &valid, pb.KeyType_Ed25519, nil},
+		{"valid secp256k1", &validSecp256k1, pb.KeyType_Secp256k1, nil},
 	}
 
 	for _, c := range cases {
@@ -50,3 +54,12 @@ func TestGetNodeKey(t *testing.T) {
 		})
 	}
 }
+
+// unsupportedPrivKey is a private key of a type not supported by libp2p.
+type unsupportedPrivKey struct {
+	secp256k1.PrivKey
+}
+
+func (unsupportedPrivKey) Type() string {
+	return "unsupported"
+}
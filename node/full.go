@@ -3,13 +3,22 @@ package node
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	ds "github.com/ipfs/go-datastore"
 	ktds "github.com/ipfs/go-datastore/keytransform"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/multierr"
 
 	abci "github.com/cometbft/cometbft/abci/types"
@@ -24,14 +33,23 @@ import (
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/da"
 	"github.com/rollkit/rollkit/da/registry"
+	"github.com/rollkit/rollkit/events"
 	"github.com/rollkit/rollkit/mempool"
 	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/ordering"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/sequencing"
+	"github.com/rollkit/rollkit/settlement"
+	settlementregistry "github.com/rollkit/rollkit/settlement/registry"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/state"
 	"github.com/rollkit/rollkit/state/indexer"
 	blockidxkv "github.com/rollkit/rollkit/state/indexer/block/kv"
 	"github.com/rollkit/rollkit/state/txindex"
 	"github.com/rollkit/rollkit/state/txindex/kv"
 	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/supervisor"
+	"github.com/rollkit/rollkit/types"
 )
 
 // prefixes used in KV store to separate main node data from DALC data
@@ -41,10 +59,16 @@ var (
 	indexerPrefix = "2" // indexPrefix uses "i", so using "0-2" to avoid clash
 )
 
+// ErrDAParamsMismatch is returned when DA layer configuration of the node doesn't match DA parameters from genesis.
+var ErrDAParamsMismatch = errors.New("DA parameters don't match genesis")
+
 const (
 	// genesisChunkSize is the maximum size, in bytes, of each
 	// chunk in the genesis structure for the chunked API
 	genesisChunkSize = 16 * 1024 * 1024 // 16 MiB
+
+	// remoteSignerConnectTimeout is the maximum time to wait for remote signer during node initialization
+	remoteSignerConnectTimeout = 1 * time.Minute
 )
 
 var _ Node = &FullNode{}
@@ -57,6 +81,8 @@ type FullNode struct {
 	genesis *cmtypes.GenesisDoc
 	// cache of chunked genesis data.
 	genChunks []string
+	// dataHasher computes DataHash of blocks of the chain, as defined in genesis
+	dataHasher types.MerkleHasher
 
 	nodeConfig config.NodeConfig
 
@@ -69,14 +95,36 @@ type FullNode struct {
 	// TODO(tzdybal): consider extracting "mempool reactor"
 	Mempool      mempool.Mempool
 	mempoolIDs   *mempoolIDs
+	txIngress    *txIngress // limits transactions broadcast via RPC
+	asyncTxs     chan asyncTx
 	Store        store.Store
 	blockManager *block.Manager
+	signer       signer.Signer
+	// sequencer is optional, it orders transactions instead of the aggregator
+	sequencer *sequencing.RemoteSequencer
+	// sharedDALC is set if DA layer client is shared with other rollups, and is not started or stopped by the node
+	sharedDALC bool
+	// daChainID is the chain ID of DA layer from genesis, empty if genesis doesn't restrict it
+	daChainID string
+	// settlement is optional, sovereign rollups don't use settlement layer
+	settlement settlement.Client
+	// eventPublisher is optional, it forwards events of applied blocks to external sinks
+	eventPublisher *events.Publisher
 
 	// Preserves cometBFT compatibility
 	TxIndexer      txindex.TxIndexer
 	BlockIndexer   indexer.BlockIndexer
 	IndexerService *txindex.IndexerService
 
+	prometheusSrv *http.Server
+	tracer        *sdktrace.TracerProvider
+
+	// supervisor starts and stops components and loops of the node
+	supervisor *supervisor.Supervisor
+
+	// levelLogger allows changing log levels (of all modules or a single module) at runtime
+	levelLogger *levelLogger
+
 	// keep context here only because of API compatibility
 	// - it's used in `OnStart` (defined in service.Service interface)
 	ctx    context.Context
@@ -93,7 +141,41 @@ func newFullNode(
 	genesis *cmtypes.GenesisDoc,
 	logger log.Logger,
 ) (*FullNode, error) {
-	proxyApp, err := initProxyApp(clientCreator, logger)
+	return newSharedFullNode(ctx, nodeConfig, p2pKey, signingKey, clientCreator, genesis, nil, logger)
+}
+
+// newSharedFullNode creates a new Rollkit full node, using components shared with nodes of other rollups
+// running in the same process, if shared is not nil.
+func newSharedFullNode(
+	ctx context.Context,
+	nodeConfig config.NodeConfig,
+	p2pKey crypto.PrivKey,
+	signingKey crypto.PrivKey,
+	clientCreator proxy.ClientCreator,
+	genesis *cmtypes.GenesisDoc,
+	shared *sharedComponents,
+	logger log.Logger,
+) (*FullNode, error) {
+	if err := nodeConfig.Validate(); err != nil {
+		return nil, err
+	}
+	daChainID, err := applyGenesisDAParams(&nodeConfig, genesis)
+	if err != nil {
+		return nil, err
+	}
+	dataHasher, err := types.DataHasherFromGenesis(genesis)
+	if err != nil {
+		return nil, err
+	}
+
+	levelLogger, err := newNodeLogger(nodeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger = levelLogger
+	metrics := newNodeMetrics(nodeConfig.Instrumentation, genesis.ChainID, "full")
+
+	proxyApp, err := initProxyApp(clientCreator, logger, metrics.proxy)
 	if err != nil {
 		return nil, err
 	}
@@ -108,16 +190,35 @@ func newFullNode(
 		return nil, err
 	}
 
-	dalcKV := newPrefixKV(baseKV, dalcPrefix)
-	dalc, err := initDALC(nodeConfig, dalcKV, logger)
+	var dalc da.DataAvailabilityLayerClient
+	if shared != nil && shared.dalc != nil {
+		dalc, err = shared.dalc.WithNamespace(nodeConfig.NamespaceID)
+	} else {
+		dalc, err = initDALC(nodeConfig, newPrefixKV(baseKV, dalcPrefix), logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	settlementClient, err := initSettlement(nodeConfig, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	p2pClient, err := p2p.NewClient(nodeConfig.P2P, p2pKey, genesis.ChainID, baseKV, logger.With("module", "p2p"))
+	var p2pClient *p2p.Client
+	if shared != nil && shared.p2p != nil {
+		p2pClient = p2p.NewSharedClient(shared.p2p, genesis.ChainID, logger.With("module", "p2p"), metrics.p2p)
+	} else {
+		p2pClient, err = p2p.NewClient(nodeConfig.P2P, p2pKey, genesis.ChainID, baseKV, logger.With("module", "p2p"), metrics.p2p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	genesisHash, err := types.GenesisHash(genesis)
 	if err != nil {
 		return nil, err
 	}
+	p2pClient.SetGenesisHash(genesisHash)
 
 	mainKV := newPrefixKV(baseKV, mainPrefix)
 	headerSyncService, err := initHeaderSyncService(ctx, mainKV, nodeConfig, genesis, p2pClient, logger)
@@ -130,13 +231,76 @@ func newFullNode(
 		return nil, err
 	}
 
-	mempool := initMempool(logger, proxyApp)
+	mempool, err := initMempool(logger, proxyApp, nodeConfig, metrics.mempool)
+	if err != nil {
+		return nil, err
+	}
+
+	blockSigner, err := initSigner(ctx, signingKey, nodeConfig, genesis, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// with shared namespace, blocks are submitted and retrieved in envelopes, like other types of blobs
+	var daMux *da.Mux
+	blockDALC := dalc
+	if nodeConfig.SharedNamespace {
+		blobClient, ok := dalc.(da.BlobClient)
+		if !ok {
+			return nil, errors.New("data availability layer client doesn't support shared namespace")
+		}
+		daMux = da.NewMux(blobClient, logger.With("module", "da_mux"))
+		blockDALC = daMux.Blocks(dalc)
+	}
+
+	store := initStore(ctx, mainKV, nodeConfig)
+	blockManager, err := initBlockManager(blockSigner, nodeConfig, genesis, dataHasher, store, mempool, proxyApp, blockDALC, eventBus, logger, blockSyncService, metrics)
+	if err != nil {
+		return nil, err
+	}
+	if daMux != nil {
+		daMux.Handle(da.BlobTypeFraudProof, newDAFraudProofHandler(blockManager, logger))
+	}
 
-	store := store.New(ctx, mainKV)
-	blockManager, err := initBlockManager(signingKey, nodeConfig, genesis, store, mempool, proxyApp, dalc, eventBus, logger, blockSyncService)
+	sequencer, err := initSequencer(nodeConfig, logger)
 	if err != nil {
 		return nil, err
 	}
+	if sequencer != nil {
+		blockManager.SetSequencer(sequencer)
+	}
+	if settlementClient != nil {
+		blockManager.SetSettlement(settlementClient)
+	}
+	if nodeConfig.DACostFeedback {
+		blockManager.SetDACostReporter(state.NewABCIDACostReporter(proxyApp.Query()))
+	}
+	trustedHash, err := hex.DecodeString(nodeConfig.TrustedHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the trusted hash: %w", err)
+	}
+	blockManager.SetStateExport(proxyApp.Snapshot(), trustedHash)
+	if nodeConfig.SnapshotInterval > 0 || nodeConfig.SnapshotDAHeight > 0 {
+		var snapshotDALC da.BlobClient
+		if daMux != nil {
+			snapshotDALC = daMux.Blobs(da.BlobTypeSnapshotChunk)
+		} else {
+			snapshotDALC, err = initSnapshotDALC(dalc, nodeConfig.SnapshotNamespaceID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		blockManager.SetSnapshots(snapshotDALC, proxyApp.Snapshot(), trustedHash)
+	}
+
+	var eventPublisher *events.Publisher
+	if len(nodeConfig.EventSinks) > 0 {
+		sinks, err := events.NewSinks(nodeConfig.EventSinks)
+		if err != nil {
+			return nil, err
+		}
+		eventPublisher = events.NewPublisher(eventBus, genesis.ChainID, sinks, logger.With("module", "event_sinks"))
+	}
 
 	indexerKV := newPrefixKV(baseKV, indexerPrefix)
 	indexerService, txIndexer, blockIndexer, err := createAndStartIndexerService(ctx, nodeConfig, indexerKV, eventBus, logger)
@@ -150,30 +314,50 @@ func newFullNode(
 		proxyApp:       proxyApp,
 		eventBus:       eventBus,
 		genesis:        genesis,
+		dataHasher:     dataHasher,
 		nodeConfig:     nodeConfig,
 		p2pClient:      p2pClient,
 		blockManager:   blockManager,
+		signer:         blockSigner,
+		sequencer:      sequencer,
 		dalc:           dalc,
+		sharedDALC:     shared != nil && shared.dalc != nil,
+		daChainID:      daChainID,
+		settlement:     settlementClient,
+		eventPublisher: eventPublisher,
 		Mempool:        mempool,
 		mempoolIDs:     newMempoolIDs(),
+		txIngress:      newTxIngress(nodeConfig, metrics.mempool),
+		asyncTxs:       make(chan asyncTx, asyncTxQueueSize),
 		Store:          store,
 		TxIndexer:      txIndexer,
 		IndexerService: indexerService,
 		BlockIndexer:   blockIndexer,
 		hSyncService:   headerSyncService,
 		bSyncService:   blockSyncService,
+		levelLogger:    levelLogger,
+		supervisor:     supervisor.New(logger.With("module", "supervisor")),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
 
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
 	node.p2pClient.SetTxValidator(node.newTxValidator())
+	node.p2pClient.SetFraudProofValidator(node.newFraudProofValidator())
+	node.p2pClient.SetEvidenceValidator(node.newEvidenceValidator())
+	node.p2pClient.SetInclusionListValidator(node.newInclusionListValidator())
+	if !node.isPruned() {
+		node.p2pClient.SetHistoryHandler(node.serveHistory)
+	}
+	if node.lazyBlockData() {
+		blockManager.SetLazyBlockData(headerSyncService.HeaderStore(), node.fetchBlockRange)
+	}
 
 	return node, nil
 }
 
-func initProxyApp(clientCreator proxy.ClientCreator, logger log.Logger) (proxy.AppConns, error) {
-	proxyApp := proxy.NewAppConns(clientCreator, proxy.NopMetrics())
+func initProxyApp(clientCreator proxy.ClientCreator, logger log.Logger, metrics *proxy.Metrics) (proxy.AppConns, error) {
+	proxyApp := proxy.NewAppConns(clientCreator, metrics)
 	proxyApp.SetLogger(logger.With("module", "proxy"))
 	if err := proxyApp.Start(); err != nil {
 		return nil, fmt.Errorf("error while starting proxy app connections: %v", err)
@@ -193,12 +377,21 @@ func initEventBus(logger log.Logger) (*cmtypes.EventBus, error) {
 // initBaseKV initializes the base key-value store.
 func initBaseKV(nodeConfig config.NodeConfig, logger log.Logger) (ds.TxnDatastore, error) {
 	if nodeConfig.RootDir == "" && nodeConfig.DBPath == "" { // this is used for testing
-		logger.Info("WARNING: working in in-memory mode")
+		logger.With("module", "store").Info("WARNING: working in in-memory mode")
 		return store.NewDefaultInMemoryKVStore()
 	}
 	return store.NewDefaultKVStore(nodeConfig.RootDir, nodeConfig.DBPath, "rollkit")
 }
 
+// initStore initializes the main store, caching the latest blocks if enabled.
+func initStore(ctx context.Context, mainKV ds.TxnDatastore, nodeConfig config.NodeConfig) store.Store {
+	s := store.New(ctx, mainKV)
+	if nodeConfig.BlockCacheSize > 0 {
+		return store.NewCachedStore(s, nodeConfig.BlockCacheSize)
+	}
+	return s
+}
+
 func initDALC(nodeConfig config.NodeConfig, dalcKV ds.TxnDatastore, logger log.Logger) (da.DataAvailabilityLayerClient, error) {
 	dalc := registry.GetClient(nodeConfig.DALayer)
 	if dalc == nil {
@@ -211,10 +404,150 @@ func initDALC(nodeConfig config.NodeConfig, dalcKV ds.TxnDatastore, logger log.L
 	return dalc, nil
 }
 
-func initMempool(logger log.Logger, proxyApp proxy.AppConns) *mempoolv1.TxMempool {
-	mempool := mempoolv1.NewTxMempool(logger, llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
-	mempool.EnableTxsAvailable()
-	return mempool
+// OpenStore opens the store of a node that isn't running, for offline tools like chain replay. The returned key-value
+// store has to be closed by the caller.
+func OpenStore(ctx context.Context, nodeConfig config.NodeConfig, logger log.Logger) (store.Store, ds.TxnDatastore, error) {
+	baseKV, err := initBaseKV(nodeConfig, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store.New(ctx, newPrefixKV(baseKV, mainPrefix)), baseKV, nil
+}
+
+// OpenDALC initializes the DA layer client of a node that isn't running, for offline tools like chain replay. baseKV is
+// the key-value store returned by OpenStore. The client has to be started by the caller.
+func OpenDALC(nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, baseKV ds.TxnDatastore, logger log.Logger) (da.DataAvailabilityLayerClient, error) {
+	if _, err := applyGenesisDAParams(&nodeConfig, genesis); err != nil {
+		return nil, err
+	}
+	return initDALC(nodeConfig, newPrefixKV(baseKV, dalcPrefix), logger)
+}
+
+// initSnapshotDALC returns DA layer client submitting and retrieving application snapshots in the snapshot namespace.
+func initSnapshotDALC(dalc da.DataAvailabilityLayerClient, namespaceID types.NamespaceID) (da.BlobClient, error) {
+	shared, ok := dalc.(da.SharedClient)
+	if !ok {
+		return nil, errors.New("data availability layer client doesn't support snapshot namespace")
+	}
+	client, err := shared.WithNamespace(namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	blobClient, ok := client.(da.BlobClient)
+	if !ok {
+		return nil, errors.New("data availability layer client doesn't support snapshots")
+	}
+	return blobClient, nil
+}
+
+// applyGenesisDAParams sets the namespace of the node to the namespace from genesis, and returns DA chain ID from
+// genesis. Namespace configured explicitly must match genesis, otherwise the node would silently sync nothing.
+func applyGenesisDAParams(nodeConfig *config.NodeConfig, genesis *cmtypes.GenesisDoc) (string, error) {
+	params, err := types.DAParamsFromGenesis(genesis)
+	if err != nil || params == nil {
+		return "", err
+	}
+	if nodeConfig.NamespaceID != (types.NamespaceID{}) && nodeConfig.NamespaceID != params.NamespaceID {
+		return "", fmt.Errorf("%w: configured namespace %X, genesis namespace %X", ErrDAParamsMismatch,
+			nodeConfig.NamespaceID, params.NamespaceID)
+	}
+	nodeConfig.NamespaceID = params.NamespaceID
+	return params.DAChainID, nil
+}
+
+// checkDAChainID verifies that DA layer client is connected to DA chain from genesis. The check is skipped if genesis
+// doesn't define DA chain ID, or DA layer client can't report it.
+func (n *FullNode) checkDAChainID(ctx context.Context) error {
+	provider, ok := n.dalc.(da.ChainIDProvider)
+	if n.daChainID == "" || !ok {
+		return nil
+	}
+	chainID, err := provider.DAChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get DA chain ID: %w", err)
+	}
+	if chainID != n.daChainID {
+		return fmt.Errorf("%w: DA layer chain ID %q, genesis DA chain ID %q", ErrDAParamsMismatch, chainID, n.daChainID)
+	}
+	return nil
+}
+
+func initSettlement(nodeConfig config.NodeConfig, logger log.Logger) (settlement.Client, error) {
+	if nodeConfig.SettlementLayer == "" {
+		return nil, nil
+	}
+	client := settlementregistry.GetClient(nodeConfig.SettlementLayer)
+	if client == nil {
+		return nil, fmt.Errorf("error while getting settlement layer client named '%s'", nodeConfig.SettlementLayer)
+	}
+	err := client.Init([]byte(nodeConfig.SettlementConfig), logger.With("module", "settlement_client"))
+	if err != nil {
+		return nil, fmt.Errorf("error while initializing settlement layer client: %w", err)
+	}
+	return client, nil
+}
+
+func initMempool(logger log.Logger, proxyApp proxy.AppConns, nodeConfig config.NodeConfig, metrics *mempool.Metrics) (mempool.Mempool, error) {
+	options := []mempoolv1.TxMempoolOption{mempoolv1.WithMetrics(metrics)}
+	if nodeConfig.MempoolNonce != "" {
+		i := strings.LastIndex(nodeConfig.MempoolNonce, ".")
+		if i <= 0 || i == len(nodeConfig.MempoolNonce)-1 {
+			return nil, fmt.Errorf("invalid mempool nonce attribute %q, expected <event type>.<attribute key>", nodeConfig.MempoolNonce)
+		}
+		options = append(options, mempoolv1.WithNonceOrdering(mempoolv1.EventNonce(nodeConfig.MempoolNonce[:i], nodeConfig.MempoolNonce[i+1:])))
+	}
+	if nodeConfig.FCFSOrdering {
+		if nodeConfig.MempoolNonce != "" || nodeConfig.SequencerAddress != "" {
+			return nil, errors.New("FCFS ordering can't be combined with nonce ordering or shared sequencer")
+		}
+		options = append(options, mempoolv1.WithFCFSOrdering())
+	}
+	if nodeConfig.EncryptedTxsDelay > 0 {
+		committeeKey, err := state.ParseCommitteeKey(nodeConfig.EncryptedTxsKey)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, mempoolv1.WithCheckTxOverride(func(tx cmtypes.Tx) (cmtypes.Tx, *abci.ResponseCheckTx) {
+			appTx, rsp := state.CheckEncryptedTx(committeeKey, types.Tx(tx))
+			return cmtypes.Tx(appTx), rsp
+		}))
+	}
+	if nodeConfig.MempoolReplaceBump > 0 {
+		options = append(options, mempoolv1.WithReplaceByFee(nodeConfig.MempoolReplaceBump))
+	}
+	if nodeConfig.MempoolMaxTxsPerSender > 0 {
+		options = append(options, mempoolv1.WithMaxTxsPerSender(nodeConfig.MempoolMaxTxsPerSender))
+	}
+	if nodeConfig.MempoolCacheTTL > 0 {
+		options = append(options, mempoolv1.WithCacheTTL(nodeConfig.MempoolCacheTTL))
+	}
+	if nodeConfig.TxMinFee > 0 {
+		i := strings.LastIndex(nodeConfig.TxFeeAttribute, ".")
+		if i <= 0 || i == len(nodeConfig.TxFeeAttribute)-1 {
+			return nil, fmt.Errorf("invalid transaction fee attribute %q, expected <event type>.<attribute key>", nodeConfig.TxFeeAttribute)
+		}
+		options = append(options, mempoolv1.WithMinFee(mempoolv1.EventFee(nodeConfig.TxFeeAttribute[:i], nodeConfig.TxFeeAttribute[i+1:]), nodeConfig.TxMinFee))
+	}
+	var filters mempool.TxFilters
+	if len(nodeConfig.MempoolSenderAllowlist) > 0 || len(nodeConfig.MempoolSenderDenylist) > 0 {
+		filters = append(filters, mempool.NewSenderListFilter(nodeConfig.MempoolSenderAllowlist, nodeConfig.MempoolSenderDenylist))
+	}
+	if nodeConfig.TxRatePerSender > 0 {
+		filters = append(filters, mempool.NewSenderRateFilter(nodeConfig.TxRatePerSender, nodeConfig.TxRateBurst, metrics))
+	}
+	if len(filters) > 0 {
+		options = append(options, mempoolv1.WithTxFilter(filters))
+	}
+	mempoolConfig := nodeConfig.Mempool
+	if mempoolConfig == nil {
+		mempoolConfig = llcfg.DefaultMempoolConfig()
+	}
+	txMempool := mempoolv1.NewTxMempool(logger, mempoolConfig, proxyApp.Mempool(), 0, options...)
+	txMempool.EnableTxsAvailable()
+	if nodeConfig.MempoolCheckTxBatch > 0 {
+		return mempoolv1.NewBatcher(txMempool, nodeConfig.MempoolCheckTxBatch), nil
+	}
+	return txMempool, nil
 }
 
 func initHeaderSyncService(ctx context.Context, mainKV ds.TxnDatastore, nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, p2pClient *p2p.Client, logger log.Logger) (*block.HeaderSyncService, error) {
@@ -233,8 +566,73 @@ func initBlockSyncService(ctx context.Context, mainKV ds.TxnDatastore, nodeConfi
 	return blockSyncService, nil
 }
 
-func initBlockManager(signingKey crypto.PrivKey, nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, store store.Store, mempool mempool.Mempool, proxyApp proxy.AppConns, dalc da.DataAvailabilityLayerClient, eventBus *cmtypes.EventBus, logger log.Logger, blockSyncService *block.BlockSyncService) (*block.Manager, error) {
-	blockManager, err := block.NewManager(signingKey, nodeConfig.BlockManagerConfig, genesis, store, mempool, proxyApp.Consensus(), dalc, eventBus, logger.With("module", "BlockManager"), blockSyncService.BlockStore())
+func initSigner(ctx context.Context, signingKey crypto.PrivKey, nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, logger log.Logger) (signer.Signer, error) {
+	if nodeConfig.RemoteSigner == "" {
+		localSigner := signer.NewLocalSigner(signingKey)
+		if nodeConfig.Aggregator {
+			if blsPubKey, err := localSigner.BLSPubKey(); err == nil {
+				logger.Info("aggregator BLS public key", "key", hex.EncodeToString(blsPubKey))
+			}
+		}
+		return localSigner, nil
+	}
+	rootify := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(nodeConfig.RootDir, path)
+	}
+	tlsConf, err := signer.ClientTLSConfig(rootify(nodeConfig.RemoteSignerTLSCert), rootify(nodeConfig.RemoteSignerTLSKey), rootify(nodeConfig.RemoteSignerTLSCA))
+	if err != nil {
+		return nil, fmt.Errorf("error while loading TLS configuration of remote signer: %w", err)
+	}
+	logger.Info("connecting to remote signer", "address", nodeConfig.RemoteSigner)
+	ctx, cancel := context.WithTimeout(ctx, remoteSignerConnectTimeout)
+	defer cancel()
+	remoteSigner, err := signer.NewRemoteSigner(ctx, nodeConfig.RemoteSigner, genesis.ChainID, tlsConf)
+	if err != nil {
+		return nil, fmt.Errorf("error while initializing remote signer: %w", err)
+	}
+	return remoteSigner, nil
+}
+
+func initSequencer(nodeConfig config.NodeConfig, logger log.Logger) (*sequencing.RemoteSequencer, error) {
+	if nodeConfig.SequencerAddress == "" {
+		return nil, nil
+	}
+	logger.Info("using shared sequencer", "address", nodeConfig.SequencerAddress)
+	sequencer, err := sequencing.NewRemoteSequencer(nodeConfig.SequencerAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error while initializing sequencer client: %w", err)
+	}
+	return sequencer, nil
+}
+
+func initBlockManager(blockSigner signer.Signer, nodeConfig config.NodeConfig, genesis *cmtypes.GenesisDoc, dataHasher types.MerkleHasher, store store.Store, mempool mempool.Mempool, proxyApp proxy.AppConns, dalc da.DataAvailabilityLayerClient, eventBus *cmtypes.EventBus, logger log.Logger, blockSyncService *block.BlockSyncService, metrics *nodeMetrics) (*block.Manager, error) {
+	var isrProvider state.IntermediateStateRootProvider
+	if nodeConfig.IntermediateStateRoots {
+		isrProvider = state.NewABCIIntermediateStateRootProvider(proxyApp.Query())
+	}
+	var txValidator state.TxValidator
+	if nodeConfig.TxPreValidation {
+		txValidator = state.NewABCITxValidator(proxyApp.Query())
+	}
+	var prover state.Prover
+	if nodeConfig.ValidityProofs {
+		prover = state.NewABCIProver(proxyApp.Query())
+	}
+	var extender state.HeaderExtender
+	if nodeConfig.HeaderExtensions {
+		extender = state.NewABCIHeaderExtender(proxyApp.Query())
+	}
+	if nodeConfig.FCFSOrdering {
+		if mp, ok := mempool.(interface {
+			ReceiveTime(tx cmtypes.Tx) (time.Time, bool)
+		}); ok {
+			extender = ordering.NewExtender(mp.ReceiveTime, extender)
+		}
+	}
+	blockManager, err := block.NewManager(blockSigner, nodeConfig.BlockManagerConfig, genesis, dataHasher, store, mempool, proxyApp.Consensus(), isrProvider, txValidator, prover, extender, dalc, eventBus, metrics.state, metrics.block, logger.With("module", "BlockManager"), blockSyncService.BlockStore())
 	if err != nil {
 		return nil, fmt.Errorf("error while initializing BlockManager: %w", err)
 	}
@@ -302,6 +700,21 @@ func (n *FullNode) blockPublishLoop(ctx context.Context) {
 	}
 }
 
+// SetDALC replaces the data availability layer client of the node with a client shared with other nodes, e.g. by
+// nodes of an in-process test network. The client is owned by the caller, it's not started or stopped by the node.
+// It has to be called before the node is started.
+func (n *FullNode) SetDALC(dalc da.DataAvailabilityLayerClient) {
+	n.dalc = dalc
+	n.sharedDALC = true
+	n.blockManager.SetDALC(dalc)
+}
+
+// SetP2PHost sets the libp2p host used by the node instead of listening on configured addresses, e.g. an in-memory
+// host of an in-process test network. It has to be called before the node is started.
+func (n *FullNode) SetP2PHost(h host.Host) {
+	n.p2pClient.SetHost(h)
+}
+
 // Cancel calls the underlying context's cancel function.
 func (n *FullNode) Cancel() {
 	n.cancel()
@@ -309,35 +722,54 @@ func (n *FullNode) Cancel() {
 
 // OnStart is a part of Service interface.
 func (n *FullNode) OnStart() error {
-	n.Logger.Info("starting P2P client")
-	err := n.p2pClient.Start(n.ctx)
+	n.prometheusSrv = startPrometheusServer(n.nodeConfig.Instrumentation, n.Logger)
+	tracer, err := startTracing(n.ctx, tracingConfig(n.nodeConfig), n.genesis.ChainID, "full", n.Logger)
 	if err != nil {
-		return fmt.Errorf("error while starting P2P client: %w", err)
+		return err
 	}
+	n.tracer = tracer
 
-	if err = n.hSyncService.Start(); err != nil {
-		return fmt.Errorf("error while starting header sync service: %w", err)
+	if n.nodeConfig.Aggregator {
+		n.Logger.Info("working in aggregator mode", "block time", n.nodeConfig.BlockTime)
 	}
-
-	if err = n.bSyncService.Start(); err != nil {
-		return fmt.Errorf("error while starting block sync service: %w", err)
+	if err := n.addServices(); err != nil {
+		return err
 	}
+	return n.supervisor.Start(n.ctx)
+}
 
-	if err = n.dalc.Start(); err != nil {
-		return fmt.Errorf("error while starting data availability layer client: %w", err)
+// fraudProofPublishLoop gossips state fraud proof generated by block manager, and stops the node afterwards.
+func (n *FullNode) fraudProofPublishLoop(ctx context.Context) {
+	select {
+	case proof := <-n.blockManager.FraudProofOutCh:
+		data, err := proof.MarshalBinary()
+		if err == nil {
+			err = n.p2pClient.GossipFraudProof(ctx, data)
+		}
+		if err != nil {
+			n.Logger.Error("failed to gossip fraud proof", "height", proof.BlockHeight, "error", err)
+		}
+		n.cancel()
+	case <-ctx.Done():
 	}
+}
 
-	if n.nodeConfig.Aggregator {
-		n.Logger.Info("working in aggregator mode", "block time", n.nodeConfig.BlockTime)
-		go n.blockManager.AggregationLoop(n.ctx, n.nodeConfig.LazyAggregator)
-		go n.blockManager.BlockSubmissionLoop(n.ctx)
-		go n.headerPublishLoop(n.ctx)
-		go n.blockPublishLoop(n.ctx)
-	}
-	go n.blockManager.RetrieveLoop(n.ctx)
-	go n.blockManager.BlockStoreRetrieveLoop(n.ctx)
-	go n.blockManager.SyncLoop(n.ctx, n.cancel)
-	return nil
+// evidencePublishLoop gossips evidence of aggregator equivocation detected by block manager.
+func (n *FullNode) evidencePublishLoop(ctx context.Context) {
+	for {
+		select {
+		case ev := <-n.blockManager.EvidenceOutCh:
+			data, err := ev.MarshalBinary()
+			if err == nil {
+				err = n.p2pClient.GossipEvidence(ctx, data)
+			}
+			if err != nil {
+				n.Logger.Error("failed to gossip evidence", "height", ev.Height(), "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 // GetGenesis returns entire genesis doc.
@@ -358,13 +790,93 @@ func (n *FullNode) GetGenesisChunks() ([]string, error) {
 func (n *FullNode) OnStop() {
 	n.Logger.Info("halting full node...")
 	n.cancel()
-	err := n.dalc.Stop()
-	err = multierr.Append(err, n.p2pClient.Close())
-	err = multierr.Append(err, n.hSyncService.Stop())
-	err = multierr.Append(err, n.bSyncService.Stop())
+	err := n.supervisor.Stop()
+	if n.prometheusSrv != nil {
+		err = multierr.Append(err, n.prometheusSrv.Shutdown(context.Background()))
+	}
+	if n.tracer != nil {
+		err = multierr.Append(err, n.tracer.Shutdown(context.Background()))
+	}
+	if remoteSigner, ok := n.signer.(*signer.RemoteSigner); ok {
+		err = multierr.Append(err, remoteSigner.Close())
+	}
+	if n.sequencer != nil {
+		err = multierr.Append(err, n.sequencer.Close())
+	}
 	n.Logger.Error("errors while stopping node:", "errors", err)
 }
 
+// MetricsConfig returns Prometheus namespace and labels used by node metrics, or ok == false
+// if Prometheus metrics are disabled.
+func (n *FullNode) MetricsConfig() (namespace string, labelsAndValues []string, ok bool) {
+	return metricsConfig(n.nodeConfig.Instrumentation, n.genesis.ChainID, "full")
+}
+
+// ExportState writes the state of the node, with the application snapshot at height (or the latest snapshot, if
+// height is zero), as state export stream to w. The stream can be imported by other nodes with StateImportFile.
+func (n *FullNode) ExportState(ctx context.Context, w io.Writer, height uint64) error {
+	return n.blockManager.ExportState(ctx, w, height)
+}
+
+// StateExportMaxConcurrent returns the maximal number of state exports served concurrently over RPC, or zero if
+// serving state exports is disabled.
+func (n *FullNode) StateExportMaxConcurrent() uint64 {
+	return n.nodeConfig.StateExportMaxConcurrent
+}
+
+// importState imports the state of the node from StateImportFile (relative to the root directory), if the store
+// is empty.
+func (n *FullNode) importState(ctx context.Context) error {
+	path := n.nodeConfig.StateImportFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(n.nodeConfig.RootDir, path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open state import file: %w", err)
+	}
+	defer f.Close()
+	return n.blockManager.ImportState(ctx, f)
+}
+
+// Ready returns an error if node is not ready to serve traffic, i.e. it's not running, the application
+// is not responding or not up to date with the state, data availability layer is not reachable or node lags
+// more than ReadyMaxLag blocks behind the head of the network.
+func (n *FullNode) Ready(ctx context.Context) error {
+	if !n.IsRunning() {
+		return errors.New("node is not running")
+	}
+	if proof := n.blockManager.HaltProof(); proof != nil {
+		return fmt.Errorf("node halted: received state fraud proof for height %d", proof.BlockHeight)
+	}
+	if err := n.supervisor.Err(); err != nil {
+		return fmt.Errorf("node halted: %w", err)
+	}
+	if _, err := n.proxyApp.Query().InfoSync(proxy.RequestInfo); err != nil {
+		return fmt.Errorf("application is not responding: %w", err)
+	}
+	if !n.blockManager.IsAppWarm() {
+		return errors.New("application is catching up with the state of the node")
+	}
+	if heights := n.blockManager.WithheldHeights(); len(heights) > 0 {
+		return fmt.Errorf("data withholding detected: blocks at heights %v didn't appear on DA layer", heights)
+	}
+	if checker, ok := n.dalc.(da.HealthChecker); ok {
+		if err := checker.CheckHealth(ctx); err != nil {
+			return fmt.Errorf("data availability layer is not reachable: %w", err)
+		}
+	}
+	// header store is not initialized until the first header is produced or received
+	head, err := n.hSyncService.HeaderStore().Head(ctx)
+	if err != nil {
+		return nil
+	}
+	if height := n.Store.Height(); head.Height() > height+n.nodeConfig.ReadyMaxLag {
+		return fmt.Errorf("node is syncing: height %d, network head %d", height, head.Height())
+	}
+	return nil
+}
+
 // OnReset is a part of Service interface.
 func (n *FullNode) OnReset() error {
 	panic("OnReset - not implemented!")
@@ -380,6 +892,18 @@ func (n *FullNode) GetLogger() log.Logger {
 	return n.Logger
 }
 
+// Supervisor returns the supervisor managing components and loops of the node. Applications embedding the node
+// can add their own services (depending on services of the node, e.g. ServiceBlockSync) before the node is started,
+// and subscribe to lifecycle events of all services.
+func (n *FullNode) Supervisor() *supervisor.Supervisor {
+	return n.supervisor
+}
+
+// ModuleLogger returns logger of the module, with log level adjustable at runtime (see FullClient.SetLogLevel).
+func (n *FullNode) ModuleLogger(module string) log.Logger {
+	return n.levelLogger.With("module", module)
+}
+
 // EventBus gives access to Node's event bus.
 func (n *FullNode) EventBus() *cmtypes.EventBus {
 	return n.eventBus
@@ -411,6 +935,8 @@ func (n *FullNode) newTxValidator() p2p.GossipValidator {
 			return false
 		case errors.Is(err, mempool.ErrPreCheck{}):
 			return false
+		case err != nil:
+			return false
 		default:
 		}
 		res := <-checkTxResCh
@@ -420,6 +946,82 @@ func (n *FullNode) newTxValidator() p2p.GossipValidator {
 	}
 }
 
+// newFraudProofValidator creates a pubsub validator that verifies gossiped state fraud proofs.
+// Valid proofs are relayed and passed to the block manager, which halts the node.
+// Nodes unable to re-execute transactions relay proofs that pass basic validation.
+func (n *FullNode) newFraudProofValidator() p2p.GossipValidator {
+	return func(m *p2p.GossipMessage) bool {
+		n.Logger.Debug("fraud proof received", "bytes", len(m.Data))
+		var proof types.StateFraudProof
+		if err := proof.UnmarshalBinary(m.Data); err != nil {
+			return false
+		}
+		if err := proof.ValidateBasic(); err != nil {
+			return false
+		}
+		err := n.blockManager.VerifyFraudProof(&proof)
+		if errors.Is(err, state.ErrFraudProofVerificationUnsupported) {
+			return true
+		}
+		if err != nil {
+			n.Logger.Info("invalid fraud proof received", "height", proof.BlockHeight, "error", err)
+			return false
+		}
+		select {
+		case n.blockManager.FraudProofInCh <- &proof:
+		default:
+		}
+		return true
+	}
+}
+
+// newDAFraudProofHandler creates a handler of state fraud proofs found in the shared namespace on DA layer. Proofs
+// passing basic validation are passed to the block manager, which verifies them and halts the node.
+func newDAFraudProofHandler(blockManager *block.Manager, logger log.Logger) da.BlobHandler {
+	return func(daHeight uint64, data []byte) {
+		var proof types.StateFraudProof
+		if err := proof.UnmarshalBinary(data); err != nil {
+			logger.Debug("malformed fraud proof on DA layer", "daHeight", daHeight, "error", err)
+			return
+		}
+		if err := proof.ValidateBasic(); err != nil {
+			logger.Debug("invalid fraud proof on DA layer", "daHeight", daHeight, "error", err)
+			return
+		}
+		logger.Info("fraud proof found on DA layer", "daHeight", daHeight, "height", proof.BlockHeight)
+		select {
+		case blockManager.FraudProofInCh <- &proof:
+		default:
+		}
+	}
+}
+
+// newEvidenceValidator creates a pubsub validator that verifies gossiped evidence of aggregator equivocation.
+// Valid evidence is relayed and passed to the block manager, to be committed in a block.
+// Evidence for heights not synced by the node yet is relayed if it passes basic validation.
+func (n *FullNode) newEvidenceValidator() p2p.GossipValidator {
+	return func(m *p2p.GossipMessage) bool {
+		n.Logger.Debug("evidence received", "bytes", len(m.Data))
+		var ev types.DuplicateHeaderEvidence
+		if err := ev.UnmarshalBinary(m.Data); err != nil {
+			return false
+		}
+		err := n.blockManager.VerifyEvidence(&ev)
+		if errors.Is(err, block.ErrEvidenceHeightUnknown) {
+			return true
+		}
+		if err != nil {
+			n.Logger.Info("invalid evidence received", "height", ev.Height(), "error", err)
+			return false
+		}
+		select {
+		case n.blockManager.EvidenceInCh <- &ev:
+		default:
+		}
+		return true
+	}
+}
+
 func newPrefixKV(kvStore ds.Datastore, prefix string) ds.TxnDatastore {
 	return (ktds.Wrap(kvStore, ktds.PrefixTransform{Prefix: ds.NewKey(prefix)}).Children()[0]).(ds.TxnDatastore)
 }
@@ -19,9 +19,17 @@ import (
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/cometbft/cometbft/version"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.opentelemetry.io/otel/attribute"
 
 	rconfig "github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/da/datacommitment"
+	"github.com/rollkit/rollkit/ibc"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/tracing"
 	"github.com/rollkit/rollkit/types"
 	abciconv "github.com/rollkit/rollkit/types/abci"
 )
@@ -37,6 +45,8 @@ const (
 var (
 	// ErrConsensusStateNotAvailable is returned because Rollkit doesn't use Tendermint consensus.
 	ErrConsensusStateNotAvailable = errors.New("consensus state not available in Rollkit")
+	// ErrHeightNotAvailable is returned when the block at requested height was pruned.
+	ErrHeightNotAvailable = errors.New("height is not available")
 )
 
 var _ rpcclient.Client = &FullClient{}
@@ -53,9 +63,13 @@ type FullClient struct {
 
 // NewFullClient returns Client working with given node.
 func NewFullClient(node *FullNode) *FullClient {
+	conf := config.DefaultRPCConfig()
+	if node.nodeConfig.TxCommitTimeout > 0 {
+		conf.TimeoutBroadcastTxCommit = node.nodeConfig.TxCommitTimeout
+	}
 	return &FullClient{
 		EventBus: node.EventBus(),
-		config:   config.DefaultRPCConfig(),
+		config:   conf,
 		node:     node,
 	}
 }
@@ -83,7 +97,17 @@ func (c *FullClient) ABCIQuery(ctx context.Context, path string, data cmbytes.He
 }
 
 // ABCIQueryWithOptions queries for data from application.
+//
+// Height selects historical state of the application (0 means the latest state). If Prove is set, the response is
+// returned only if the app hash of the state at the height of the response is known to the node, so the proof can be
+// verified against the app hash returned by AppHash (or AppHash of the header of the next height).
 func (c *FullClient) ABCIQueryWithOptions(ctx context.Context, path string, data cmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	if opts.Height < 0 {
+		return nil, fmt.Errorf("height must be non-negative, got %d", opts.Height)
+	}
+	if latest := c.node.Store.Height(); uint64(opts.Height) > latest {
+		return nil, fmt.Errorf("height %d must be less than or equal to the current blockchain height %d", opts.Height, latest)
+	}
 	resQuery, err := c.appClient().Query().QuerySync(abci.RequestQuery{
 		Path:   path,
 		Data:   data,
@@ -94,16 +118,49 @@ func (c *FullClient) ABCIQueryWithOptions(ctx context.Context, path string, data
 		return nil, err
 	}
 	c.Logger.Debug("ABCIQuery", "path", path, "data", data, "result", resQuery)
+	if opts.Prove && resQuery.IsOK() {
+		if resQuery.Height < 0 {
+			return nil, fmt.Errorf("invalid query height: %d", resQuery.Height)
+		}
+		if _, err := c.node.Store.LoadAppHash(uint64(resQuery.Height)); err != nil {
+			return nil, fmt.Errorf("app hash of height %d unknown, proof can't be verified: %w", resQuery.Height, err)
+		}
+	}
 	return &ctypes.ResultABCIQuery{Response: *resQuery}, nil
 }
 
+// ResultAppHash is the app hash of the application state after executing the block at given height, returned by
+// AppHash.
+type ResultAppHash struct {
+	Height  int64            `json:"height"`
+	AppHash cmbytes.HexBytes `json:"app_hash"`
+}
+
+// AppHash returns the app hash of the application state after executing the block at given height (by default, the
+// latest block). Proofs returned by ABCIQueryWithOptions are verified against it. App hashes are kept when blocks
+// are pruned.
+func (c *FullClient) AppHash(ctx context.Context, height *int64) (*ResultAppHash, error) {
+	h := c.normalizeHeight(height)
+	appHash, err := c.node.Store.LoadAppHash(h)
+	if err != nil {
+		return nil, fmt.Errorf("app hash of height %d unknown: %w", h, err)
+	}
+	return &ResultAppHash{Height: int64(h), AppHash: appHash}, nil
+}
+
 // BroadcastTxCommit returns with the responses from CheckTx and DeliverTx.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_commit
-func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (_ *ctypes.ResultBroadcastTxCommit, err error) {
+	ctx, span := tracing.Start(ctx, "FullClient.BroadcastTxCommit", attribute.String("tx", fmt.Sprintf("%X", tx.Hash())))
+	defer func() { tracing.End(span, err) }()
+
+	if err = c.node.txIngress.check(ctx, tx); err != nil {
+		return nil, err
+	}
+
 	// This implementation corresponds to Tendermints implementation from rpc/core/mempool.go.
-	// ctx.RemoteAddr godoc: If neither HTTPReq nor WSConn is set, an empty string is returned.
-	// This code is a local client, so we can assume that subscriber is ""
-	subscriber := "" //ctx.RemoteAddr()
+	// Subscriber is the address of remote client, or "" for local clients.
+	subscriber, _ := ctx.Value(remoteAddrKey{}).(string)
 
 	if c.EventBus.NumClients() >= c.config.MaxSubscriptionClients {
 		return nil, fmt.Errorf("max_subscription_clients %d reached", c.config.MaxSubscriptionClients)
@@ -129,9 +186,9 @@ func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*cty
 
 	// add to mempool and wait for CheckTx result
 	checkTxResCh := make(chan *abci.Response, 1)
-	err = c.node.Mempool.CheckTx(tx, func(res *abci.Response) {
+	err = c.checkTx(ctx, tx, func(res *abci.Response) {
 		checkTxResCh <- res
-	}, mempool.TxInfo{})
+	})
 	if err != nil {
 		c.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return nil, fmt.Errorf("error on broadcastTxCommit: %v", err)
@@ -146,13 +203,14 @@ func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*cty
 		}, nil
 	}
 
-	// broadcast tx
-	err = c.node.p2pClient.GossipTx(ctx, tx)
+	err = c.relayTx(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("tx added to local mempool but failure to broadcast: %w", err)
+		return nil, err
 	}
 
 	// Wait for the tx to be included in a block or timeout.
+	timer := time.NewTimer(c.config.TimeoutBroadcastTxCommit)
+	defer timer.Stop()
 	select {
 	case msg := <-deliverTxSub.Out(): // The tx was included in a block.
 		deliverTxRes := msg.Data().(cmtypes.EventDataTx)
@@ -176,7 +234,7 @@ func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*cty
 			DeliverTx: abci.ResponseDeliverTx{},
 			Hash:      tx.Hash(),
 		}, err
-	case <-time.After(c.config.TimeoutBroadcastTxCommit):
+	case <-timer.C:
 		err = errors.New("timed out waiting for tx to be included in a block")
 		c.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return &ctypes.ResultBroadcastTxCommit{
@@ -184,21 +242,32 @@ func (c *FullClient) BroadcastTxCommit(ctx context.Context, tx cmtypes.Tx) (*cty
 			DeliverTx: abci.ResponseDeliverTx{},
 			Hash:      tx.Hash(),
 		}, err
+	case <-ctx.Done():
+		return &ctypes.ResultBroadcastTxCommit{
+			CheckTx:   *checkTxRes,
+			DeliverTx: abci.ResponseDeliverTx{},
+			Hash:      tx.Hash(),
+		}, ctx.Err()
 	}
 }
 
 // BroadcastTxAsync returns right away, with no response. Does not wait for
-// CheckTx nor DeliverTx results.
+// CheckTx nor DeliverTx results. Transaction is checked in the background, and
+// gossiped only if it's accepted by the mempool.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_async
-func (c *FullClient) BroadcastTxAsync(ctx context.Context, tx cmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
-	err := c.node.Mempool.CheckTx(tx, nil, mempool.TxInfo{})
-	if err != nil {
+func (c *FullClient) BroadcastTxAsync(ctx context.Context, tx cmtypes.Tx) (_ *ctypes.ResultBroadcastTx, err error) {
+	ctx, span := tracing.Start(ctx, "FullClient.BroadcastTxAsync", attribute.String("tx", fmt.Sprintf("%X", tx.Hash())))
+	defer func() { tracing.End(span, err) }()
+
+	if err = c.node.txIngress.check(ctx, tx); err != nil {
 		return nil, err
 	}
-	// gossipTx optimistically
-	err = c.node.p2pClient.GossipTx(ctx, tx)
-	if err != nil {
-		return nil, fmt.Errorf("tx added to local mempool but failed to gossip: %w", err)
+
+	select {
+	case c.node.asyncTxs <- asyncTx{ctx: context.WithoutCancel(ctx), tx: tx}:
+	default:
+		err = ErrTxQueueFull
+		return nil, err
 	}
 	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
 }
@@ -206,29 +275,28 @@ func (c *FullClient) BroadcastTxAsync(ctx context.Context, tx cmtypes.Tx) (*ctyp
 // BroadcastTxSync returns with the response from CheckTx. Does not wait for
 // DeliverTx result.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_sync
-func (c *FullClient) BroadcastTxSync(ctx context.Context, tx cmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+func (c *FullClient) BroadcastTxSync(ctx context.Context, tx cmtypes.Tx) (_ *ctypes.ResultBroadcastTx, err error) {
+	ctx, span := tracing.Start(ctx, "FullClient.BroadcastTxSync", attribute.String("tx", fmt.Sprintf("%X", tx.Hash())))
+	defer func() { tracing.End(span, err) }()
+
+	if err = c.node.txIngress.check(ctx, tx); err != nil {
+		return nil, err
+	}
+
 	resCh := make(chan *abci.Response, 1)
-	err := c.node.Mempool.CheckTx(tx, func(res *abci.Response) {
+	err = c.checkTx(ctx, tx, func(res *abci.Response) {
 		resCh <- res
-	}, mempool.TxInfo{})
+	})
 	if err != nil {
 		return nil, err
 	}
 	res := <-resCh
 	r := res.GetCheckTx()
 
-	// gossip the transaction if it's in the mempool.
-	// Note: we have to do this here because, unlike the tendermint mempool reactor, there
-	// is no routine that gossips transactions after they enter the pool
 	if r.Code == abci.CodeTypeOK {
-		err = c.node.p2pClient.GossipTx(ctx, tx)
+		err = c.relayTx(ctx, tx)
 		if err != nil {
-			// the transaction must be removed from the mempool if it cannot be gossiped.
-			// if this does not occur, then the user will not be able to try again using
-			// this node, as the CheckTx call above will return an error indicating that
-			// the tx is already in the mempool
-			_ = c.node.Mempool.RemoveTxByKey(tx.Key())
-			return nil, fmt.Errorf("failed to gossip tx: %w", err)
+			return nil, err
 		}
 	}
 
@@ -241,6 +309,80 @@ func (c *FullClient) BroadcastTxSync(ctx context.Context, tx cmtypes.Tx) (*ctype
 	}, nil
 }
 
+// BroadcastTxPreConfirm adds the transaction to the mempool like BroadcastTxSync and, if it's accepted, returns
+// the pre-confirmation of the aggregator, promising inclusion of the transaction at given height and position.
+func (c *FullClient) BroadcastTxPreConfirm(ctx context.Context, tx cmtypes.Tx) (*ResultBroadcastTxPreConfirm, error) {
+	if !c.node.nodeConfig.Aggregator {
+		return nil, errors.New("pre-confirmations are served only by the aggregator")
+	}
+	if c.node.nodeConfig.FCFSOrdering {
+		return nil, errors.New("pre-confirmations are not supported with FCFS ordering")
+	}
+	res, err := c.BroadcastTxSync(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &ResultBroadcastTxPreConfirm{ResultBroadcastTx: *res}
+	if res.Code != abci.CodeTypeOK {
+		return result, nil
+	}
+	result.PreConfirmation, err = c.node.blockManager.PreConfirm(ctx, types.Tx(tx))
+	if err != nil {
+		return nil, fmt.Errorf("tx added to mempool but failed to pre-confirm: %w", err)
+	}
+	return result, nil
+}
+
+// ResultBroadcastTxPreConfirm is the result of BroadcastTxPreConfirm. PreConfirmation is set only if the
+// transaction was accepted by the mempool.
+type ResultBroadcastTxPreConfirm struct {
+	ctypes.ResultBroadcastTx
+	PreConfirmation *types.PreConfirmation `json:"pre_confirmation,omitempty"`
+}
+
+// relayTx gossips the transaction accepted by the mempool, and submits it to the shared sequencer.
+// Note: we have to do this here because, unlike the tendermint mempool reactor, there
+// is no routine that gossips transactions after they enter the pool.
+func (c *FullClient) relayTx(ctx context.Context, tx cmtypes.Tx) error {
+	err := c.node.p2pClient.GossipTx(ctx, tx)
+	if err != nil {
+		// the transaction must be removed from the mempool if it cannot be gossiped.
+		// if this does not occur, then the user will not be able to try again using
+		// this node, as the CheckTx call will return an error indicating that
+		// the tx is already in the mempool
+		_ = c.node.Mempool.RemoveTxByKey(tx.Key())
+		return fmt.Errorf("failed to gossip tx: %w", err)
+	}
+	return c.submitToSequencer(ctx, tx)
+}
+
+// submitToSequencer submits the transaction accepted by the mempool to the shared sequencer, if it's configured.
+func (c *FullClient) submitToSequencer(ctx context.Context, tx cmtypes.Tx) error {
+	if c.node.sequencer == nil {
+		return nil
+	}
+	err := c.node.sequencer.SubmitRollupTransaction(ctx, []byte(c.node.genesis.ChainID), types.Tx(tx))
+	if err != nil {
+		return fmt.Errorf("tx added to local mempool but failed to submit to sequencer: %w", err)
+	}
+	return nil
+}
+
+// checkTx adds transaction to the mempool, recording a span for the CheckTx call.
+func (c *FullClient) checkTx(ctx context.Context, tx cmtypes.Tx, cb func(*abci.Response)) error {
+	_, span := tracing.Start(ctx, "Mempool.CheckTx")
+	err := c.node.Mempool.CheckTx(tx, func(res *abci.Response) {
+		if r := res.GetCheckTx(); r != nil {
+			span.SetAttributes(attribute.Int64("code", int64(r.Code)))
+		}
+		if cb != nil {
+			cb(res)
+		}
+	}, mempool.TxInfo{})
+	tracing.End(span, err)
+	return err
+}
+
 // Subscribe subscribe given subscriber to a query.
 func (c *FullClient) Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (out <-chan ctypes.ResultEvent, err error) {
 	q, err := cmquery.New(query)
@@ -313,9 +455,9 @@ func (c *FullClient) GenesisChunked(context context.Context, id uint) (*ctypes.R
 func (c *FullClient) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 	const limit int64 = 20
 
-	// Currently blocks are not pruned and are synced linearly so the base height is 0
+	// blocks are synced linearly and pruned from the bottom, so the base height is the earliest block in the store
 	minHeight, maxHeight, err := filterMinMax(
-		0,
+		int64(c.node.Store.EarliestHeight()),
 		int64(c.node.Store.Height()),
 		minHeight,
 		maxHeight,
@@ -380,35 +522,25 @@ func (c *FullClient) ConsensusState(ctx context.Context) (*ctypes.ResultConsensu
 }
 
 // ConsensusParams returns consensus params at given height.
-//
-// Currently, consensus params changes are not supported and this method returns params as defined in genesis.
 func (c *FullClient) ConsensusParams(ctx context.Context, height *int64) (*ctypes.ResultConsensusParams, error) {
-	// TODO(tzdybal): implement consensus params handling: https://github.com/rollkit/rollkit/issues/291
-	params := c.node.GetGenesis().ConsensusParams
+	blockHeight := c.normalizeHeight(height)
+	params, err := c.node.Store.LoadConsensusParams(blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load consensus params for height %d: %w", blockHeight, err)
+	}
 	return &ctypes.ResultConsensusParams{
-		BlockHeight: int64(c.normalizeHeight(height)),
-		ConsensusParams: cmtypes.ConsensusParams{
-			Block: cmtypes.BlockParams{
-				MaxBytes: params.Block.MaxBytes,
-				MaxGas:   params.Block.MaxGas,
-			},
-			Evidence: cmtypes.EvidenceParams{
-				MaxAgeNumBlocks: params.Evidence.MaxAgeNumBlocks,
-				MaxAgeDuration:  params.Evidence.MaxAgeDuration,
-				MaxBytes:        params.Evidence.MaxBytes,
-			},
-			Validator: cmtypes.ValidatorParams{
-				PubKeyTypes: params.Validator.PubKeyTypes,
-			},
-			Version: cmtypes.VersionParams{
-				App: params.Version.App,
-			},
-		},
+		BlockHeight:     int64(blockHeight),
+		ConsensusParams: cmtypes.ConsensusParamsFromProto(params),
 	}, nil
 }
 
 // Health endpoint returns empty value. It can be used to monitor service availability.
+//
+// Error is returned if the node was halted because of invalid state transition.
 func (c *FullClient) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
+	if proof := c.HaltProof(ctx); proof != nil {
+		return nil, fmt.Errorf("chain is faulty: invalid state transition at height %d", proof.BlockHeight)
+	}
 	return &ctypes.ResultHealth{}, nil
 }
 
@@ -417,7 +549,7 @@ func (c *FullClient) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
 // If height is nil, it returns information about last known block.
 func (c *FullClient) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
 	heightValue := c.normalizeHeight(height)
-	block, err := c.node.Store.LoadBlock(heightValue)
+	block, err := c.node.loadBlock(ctx, heightValue)
 	if err != nil {
 		return nil, err
 	}
@@ -469,6 +601,9 @@ func (c *FullClient) BlockResults(ctx context.Context, height *int64) (*ctypes.R
 	} else {
 		h = uint64(*height)
 	}
+	if earliest := c.node.Store.EarliestHeight(); h < earliest {
+		return nil, fmt.Errorf("%w: height %d, lowest height is %d", ErrHeightNotAvailable, h, earliest)
+	}
 	resp, err := c.node.Store.LoadBlockResponses(h)
 	if err != nil {
 		return nil, err
@@ -487,13 +622,17 @@ func (c *FullClient) BlockResults(ctx context.Context, height *int64) (*ctypes.R
 // Commit returns signed header (aka commit) at given height.
 func (c *FullClient) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
 	heightValue := c.normalizeHeight(height)
-	com, err := c.node.Store.LoadCommit(heightValue)
+	b, err := c.node.loadBlock(ctx, heightValue)
 	if err != nil {
 		return nil, err
 	}
-	b, err := c.node.Store.LoadBlock(heightValue)
+	com, err := c.node.Store.LoadCommit(heightValue)
 	if err != nil {
-		return nil, err
+		if !c.node.lazyBlockData() {
+			return nil, err
+		}
+		// commit of block missing in the store is taken from the fetched block
+		com = &b.SignedHeader.Commit
 	}
 	commit := com.ToABCICommit(heightValue, b.Hash())
 	block, err := abciconv.ToABCIBlock(b)
@@ -545,12 +684,9 @@ func (c *FullClient) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.R
 
 	var proof cmtypes.TxProof
 	if prove {
-		block, _ := c.node.Store.LoadBlock(uint64(height))
-		blockProof := block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
-		proof = cmtypes.TxProof{
-			RootHash: blockProof.RootHash,
-			Data:     cmtypes.Tx(blockProof.Data),
-			Proof:    blockProof.Proof,
+		proof, err = c.abciTxProof(height, index)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -613,10 +749,12 @@ func (c *FullClient) TxSearch(ctx context.Context, query string, prove bool, pag
 		r := results[i]
 
 		var proof cmtypes.TxProof
-		/*if prove {
-			block := nil                               //env.BlockStore.LoadBlock(r.Height)
-			proof = block.Data.Txs.Proof(int(r.Index)) // XXX: overflow on 32-bit machines
-		}*/
+		if prove {
+			proof, err = c.abciTxProof(r.Height, r.Index)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		apiResults = append(apiResults, &ctypes.ResultTx{
 			Hash:     cmtypes.Tx(r.Tx).Hash(),
@@ -631,6 +769,198 @@ func (c *FullClient) TxSearch(ctx context.Context, query string, prove bool, pag
 	return &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
 }
 
+// TxProof returns Merkle proof of inclusion of transaction identified by its hash in the block,
+// verifiable against DataHash of the block header.
+func (c *FullClient) TxProof(ctx context.Context, hash []byte) (*TxInclusionProof, error) {
+	res, err := c.node.TxIndexer.Get(hash)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("tx (%X) not found", hash)
+	}
+	proof, err := c.txProof(res.Height, res.Index)
+	if err != nil {
+		return nil, err
+	}
+	return &TxInclusionProof{
+		Hash:     hash,
+		Height:   res.Height,
+		Index:    res.Index,
+		DataHash: proof.RootHash,
+		Proof:    proof,
+	}, nil
+}
+
+// SignedHeader returns the signed header (including the aggregator set) at given height. Unlike Commit, it returns
+// Rollkit header, which is required to verify signatures of aggregators, e.g. by IBC light clients.
+func (c *FullClient) SignedHeader(ctx context.Context, height *int64) (*types.SignedHeader, error) {
+	return ibc.NewProvider(c.node.Store).SignedHeader(c.normalizeHeight(height))
+}
+
+// ValidatorsWithProof returns the aggregator set of the header at given height, with Merkle proofs of membership
+// of every aggregator in the set committed to by AggregatorsHash of the header.
+func (c *FullClient) ValidatorsWithProof(ctx context.Context, height *int64) (*ibc.AggregatorSetProof, error) {
+	return ibc.NewProvider(c.node.Store).AggregatorSetProof(c.normalizeHeight(height))
+}
+
+// StateProof returns the value of the key in the store of the application at given height, with the Merkle proof
+// from the value to the app hash committed to by the header of the next height. If height is not set, the state of
+// the block preceding the latest one (the latest state committed to by a header) is proven.
+func (c *FullClient) StateProof(ctx context.Context, store string, key []byte, height *int64) (*ibc.StateProof, error) {
+	var h uint64
+	if height != nil {
+		h = uint64(*height)
+	} else if latest := c.node.Store.Height(); latest > 0 {
+		h = latest - 1
+	}
+	return ibc.NewProvider(c.node.Store).StateProof(c.appClient().Query(), store, key, h)
+}
+
+// DataCommitment returns the data commitment of DA blocks containing rollup blocks from `from` to `to` (inclusive),
+// e.g. for bridge contracts verifying that rollup data was posted to DA layer.
+func (c *FullClient) DataCommitment(ctx context.Context, from, to uint64) (*datacommitment.DataCommitment, error) {
+	p, err := c.dataCommitmentProvider()
+	if err != nil {
+		return nil, err
+	}
+	return p.DataCommitment(ctx, from, to)
+}
+
+// DataRootInclusionProof returns proof of inclusion of the DA block containing rollup block at given height
+// in the data commitment of rollup blocks from `from` to `to` (inclusive).
+func (c *FullClient) DataRootInclusionProof(ctx context.Context, height, from, to uint64) (*datacommitment.DataRootInclusionProof, error) {
+	p, err := c.dataCommitmentProvider()
+	if err != nil {
+		return nil, err
+	}
+	return p.DataRootInclusionProof(ctx, height, from, to)
+}
+
+func (c *FullClient) dataCommitmentProvider() (*datacommitment.Provider, error) {
+	retriever, ok := c.node.dalc.(da.DataRootRetriever)
+	if !ok {
+		return nil, errors.New("data availability layer client doesn't provide data roots")
+	}
+	return datacommitment.NewProvider(retriever, c.node.blockManager.GetDAHeight), nil
+}
+
+// ResultDALocation is the location of a block on the DA layer, returned by DALocation.
+type ResultDALocation struct {
+	Height   int64  `json:"height"`
+	DAHeight uint64 `json:"da_height"`
+	Index    uint64 `json:"index"`
+}
+
+// ResultDABlockHeights are heights of blocks included in a DA block, returned by DABlockHeights.
+type ResultDABlockHeights struct {
+	DAHeight uint64  `json:"da_height"`
+	Heights  []int64 `json:"heights"`
+}
+
+// DALocation returns the location of block at given height on the DA layer: height of the DA block and index
+// of the blob among blobs of the chain. Locations are known for blocks submitted or retrieved by this node.
+func (c *FullClient) DALocation(ctx context.Context, height *int64) (*ResultDALocation, error) {
+	h := c.normalizeHeight(height)
+	loc, err := c.node.Store.LoadDALocation(h)
+	if err != nil {
+		return nil, fmt.Errorf("DA location of block %d unknown: %w", h, err)
+	}
+	return &ResultDALocation{Height: int64(h), DAHeight: loc.DAHeight, Index: loc.Index}, nil
+}
+
+// DABlockHeights returns heights of blocks included in DA block at given height, ordered by blob index.
+func (c *FullClient) DABlockHeights(ctx context.Context, daHeight uint64) (*ResultDABlockHeights, error) {
+	heights, err := c.node.Store.LoadHeightsByDAHeight(daHeight)
+	if err != nil {
+		return nil, err
+	}
+	res := &ResultDABlockHeights{DAHeight: daHeight, Heights: make([]int64, len(heights))}
+	for i, h := range heights {
+		res.Heights[i] = int64(h)
+	}
+	return res, nil
+}
+
+const (
+	// maxDACostBlocks limits the number of blocks returned by DACosts.
+	maxDACostBlocks = 100
+	// defaultDACostDays and maxDACostDays are the default and maximal number of days returned by DACosts.
+	defaultDACostDays = 7
+	maxDACostDays     = 90
+)
+
+// ResultBlockDACost is the cost of DA submission attributed to a block, returned by DACosts.
+type ResultBlockDACost struct {
+	Height int64 `json:"height"`
+	store.DACost
+}
+
+// ResultDACosts are costs of DA submissions of blocks and days, returned by DACosts.
+type ResultDACosts struct {
+	Blocks []ResultBlockDACost `json:"blocks"`
+	Daily  []store.DailyDACost `json:"daily"`
+}
+
+// DACosts returns costs of DA submissions attributed to blocks from minHeight to maxHeight (the most recent 100
+// blocks at most, like BlockchainInfo), and daily totals of the given number of the most recent days (UTC).
+// Costs are known for blocks submitted to the DA layer by this node.
+func (c *FullClient) DACosts(ctx context.Context, minHeight, maxHeight int64, days int) (*ResultDACosts, error) {
+	if days < 0 || days > maxDACostDays {
+		return nil, fmt.Errorf("number of days must be between 0 and %d", maxDACostDays)
+	}
+	if days == 0 {
+		days = defaultDACostDays
+	}
+	res := &ResultDACosts{Blocks: []ResultBlockDACost{}, Daily: make([]store.DailyDACost, 0, days)}
+	if height := int64(c.node.Store.Height()); height > 0 {
+		from, to, err := filterMinMax(0, height, minHeight, maxHeight, maxDACostBlocks)
+		if err != nil {
+			return nil, err
+		}
+		for h := to; h >= from; h-- {
+			cost, err := c.node.Store.LoadDACost(uint64(h))
+			if err != nil {
+				// block wasn't submitted by this node
+				continue
+			}
+			res.Blocks = append(res.Blocks, ResultBlockDACost{Height: h, DACost: cost})
+		}
+	}
+	now := time.Now()
+	for i := 0; i < days; i++ {
+		daily, err := c.node.Store.LoadDailyDACost(now.AddDate(0, 0, -i))
+		if err != nil {
+			return nil, err
+		}
+		res.Daily = append(res.Daily, daily)
+	}
+	return res, nil
+}
+
+// TxInclusionProof is a proof of inclusion of a transaction in the block, returned by TxProof.
+type TxInclusionProof = types.TxInclusionProof
+
+func (c *FullClient) txProof(height int64, index uint32) (types.TxProof, error) {
+	block, err := c.node.Store.LoadBlock(uint64(height))
+	if err != nil {
+		return types.TxProof{}, fmt.Errorf("failed to load block %d: %w", height, err)
+	}
+	return block.Data.TxProofWith(int(index), c.node.dataHasher)
+}
+
+func (c *FullClient) abciTxProof(height int64, index uint32) (cmtypes.TxProof, error) {
+	proof, err := c.txProof(height, index)
+	if err != nil {
+		return cmtypes.TxProof{}, err
+	}
+	return cmtypes.TxProof{
+		RootHash: proof.RootHash,
+		Data:     cmtypes.Tx(proof.Data),
+		Proof:    proof.Proof,
+	}, nil
+}
+
 // BlockSearch defines a method to search for a paginated set of blocks by
 // BeginBlock and EndBlock event search criteria.
 func (c *FullClient) BlockSearch(ctx context.Context, query string, page, perPage *int, orderBy string) (*ctypes.ResultBlockSearch, error) {
@@ -671,9 +1001,13 @@ func (c *FullClient) BlockSearch(ctx context.Context, query string, page, perPag
 	skipCount := validateSkipCount(pageVal, perPageVal)
 	pageSize := cmmath.MinInt(perPageVal, totalCount-skipCount)
 
-	// Fetch the blocks
+	// Fetch the blocks; like in CometBFT, pruned blocks are skipped (but counted)
+	earliest := c.node.Store.EarliestHeight()
 	blocks := make([]*ctypes.ResultBlock, 0, pageSize)
 	for i := skipCount; i < skipCount+pageSize; i++ {
+		if uint64(results[i]) < earliest {
+			continue
+		}
 		b, err := c.node.Store.LoadBlock(uint64(results[i]))
 		if err != nil {
 			return nil, err
@@ -685,7 +1019,7 @@ func (c *FullClient) BlockSearch(ctx context.Context, query string, page, perPag
 		blocks = append(blocks, &ctypes.ResultBlock{
 			Block: block,
 			BlockID: cmtypes.BlockID{
-				Hash: block.Hash(),
+				Hash: cmbytes.HexBytes(b.Hash()),
 			},
 		})
 	}
@@ -700,7 +1034,7 @@ func (c *FullClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
 		return nil, fmt.Errorf("failed to find latest block: %w", err)
 	}
 
-	initial, err := c.node.Store.LoadBlock(uint64(c.node.GetGenesis().InitialHeight))
+	initial, err := c.node.Store.LoadBlock(c.node.Store.EarliestHeight())
 	if err != nil {
 		return nil, fmt.Errorf("failed to find earliest block: %w", err)
 	}
@@ -759,6 +1093,55 @@ func (c *FullClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
 	return result, nil
 }
 
+// ResultRollkitStatus describes rollkit specific state of the node, reported by status next to CometBFT status.
+type ResultRollkitStatus struct {
+	Aggregator     bool `json:"aggregator"`
+	LazyAggregator bool `json:"lazy_aggregator"`
+	// DALayer is the name of the DA layer client. DADegraded is set while DA layer is unavailable.
+	DALayer    string `json:"da_layer"`
+	DADegraded bool   `json:"da_degraded"`
+	// DAHeight is the height of the latest DA block processed by the node.
+	DAHeight uint64 `json:"da_height"`
+	// SoftHeight is the height of the latest block in the store, and DAIncludedHeight is the height of the latest
+	// block known to be included in DA layer. Blocks between them are soft confirmed only.
+	SoftHeight       int64 `json:"soft_height"`
+	DAIncludedHeight int64 `json:"da_included_height"`
+	// SequencerAddress is the address of the current proposer of blocks.
+	SequencerAddress cmbytes.HexBytes `json:"sequencer_address"`
+	NodeRole         string           `json:"node_role"`
+	// RetainBlocks is the number of the most recent blocks kept by pruned node, zero for archival node.
+	RetainBlocks uint64 `json:"retain_blocks"`
+	SyncMode     string `json:"sync_mode"`
+}
+
+// RollkitStatus returns rollkit specific state of the node: aggregator mode, DA layer client and its health,
+// heights of the latest soft confirmed and DA included blocks, sequencer address and pruning settings.
+func (c *FullClient) RollkitStatus(ctx context.Context) (*ResultRollkitStatus, error) {
+	conf := c.node.nodeConfig
+	res := &ResultRollkitStatus{
+		Aggregator:       conf.Aggregator,
+		LazyAggregator:   conf.Aggregator && conf.LazyAggregator,
+		DALayer:          conf.DALayer,
+		DADegraded:       c.node.blockManager.IsDADegraded(),
+		DAHeight:         c.node.blockManager.DAHeight(),
+		SoftHeight:       int64(c.node.Store.Height()),
+		DAIncludedHeight: int64(c.node.blockManager.DAIncludedHeight()),
+		NodeRole:         conf.NodeRole,
+		SyncMode:         conf.SyncMode,
+	}
+	if c.node.isPruned() {
+		res.RetainBlocks = conf.RetainBlocks
+	}
+	state, err := c.node.Store.LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the last saved state: %w", err)
+	}
+	if state.Validators != nil && state.Validators.Proposer != nil {
+		res.SequencerAddress = cmbytes.HexBytes(state.Validators.Proposer.Address)
+	}
+	return res, nil
+}
+
 // BroadcastEvidence is not yet implemented.
 func (c *FullClient) BroadcastEvidence(ctx context.Context, evidence cmtypes.Evidence) (*ctypes.ResultBroadcastEvidence, error) {
 	return &ctypes.ResultBroadcastEvidence{
@@ -789,6 +1172,23 @@ func (c *FullClient) UnconfirmedTxs(ctx context.Context, limitPtr *int) (*ctypes
 		Txs:        txs}, nil
 }
 
+// UnconfirmedTxsPage returns a page of transactions in mempool, in the order they would be included in a block.
+func (c *FullClient) UnconfirmedTxsPage(ctx context.Context, pagePtr, perPagePtr *int) (*ctypes.ResultUnconfirmedTxs, error) {
+	total := c.node.Mempool.Size()
+	perPage := validatePerPage(perPagePtr)
+	page, err := validatePage(pagePtr, perPage, total)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := c.node.Mempool.ListTxs((page-1)*perPage, perPage)
+	return &ctypes.ResultUnconfirmedTxs{
+		Count:      len(txs),
+		Total:      total,
+		TotalBytes: c.node.Mempool.SizeBytes(),
+		Txs:        txs}, nil
+}
+
 // CheckTx executes a new transaction against the application to determine its validity.
 //
 // If valid, the tx is automatically added to the mempool.
@@ -832,6 +1232,114 @@ func (c *FullClient) HeaderByHash(ctx context.Context, hash cmbytes.HexBytes) (*
 	return &ctypes.ResultHeader{Header: &blockMeta.Header}, nil
 }
 
+// PeerScores returns scores of peers penalized for relaying invalid messages, lowest first.
+func (c *FullClient) PeerScores(ctx context.Context) ([]p2p.PeerScore, error) {
+	return c.node.p2pClient.PeerScores(), nil
+}
+
+// NetPeers returns details of connections with P2P peers, including direction and scores.
+func (c *FullClient) NetPeers(ctx context.Context) ([]p2p.PeerInfo, error) {
+	return c.node.p2pClient.PeerInfos(), nil
+}
+
+// FraudProof returns state fraud proof generated for the block at given height.
+func (c *FullClient) FraudProof(ctx context.Context, height *int64) (*types.StateFraudProof, error) {
+	if height == nil {
+		return nil, errors.New("height is required")
+	}
+	return c.node.Store.LoadFraudProof(uint64(*height))
+}
+
+// HaltProof returns the valid state fraud proof that caused the node to halt, or nil if the chain is not faulty.
+func (c *FullClient) HaltProof(ctx context.Context) *types.StateFraudProof {
+	return c.node.blockManager.HaltProof()
+}
+
+// DebugState is a snapshot of internal state of the full node, returned by DumpState.
+type DebugState struct {
+	State                 types.State            `json:"state"`
+	StoreHeight           uint64                 `json:"store_height"`
+	PendingBlocks         int                    `json:"pending_blocks"`
+	BlockProcessingHalted bool                   `json:"block_processing_halted"`
+	MempoolSize           int                    `json:"mempool_size"`
+	Peers                 int                    `json:"peers"`
+	HaltProof             *types.StateFraudProof `json:"halt_proof,omitempty"`
+}
+
+// AdminToken returns the token authorizing admin RPC calls. Empty token disables admin RPC.
+func (c *FullClient) AdminToken() string {
+	return c.node.nodeConfig.AdminToken
+}
+
+// Rollback reverts the state of the node by one block. Block processing has to be halted first.
+// It returns the height of the latest block after rollback.
+func (c *FullClient) Rollback(ctx context.Context) (uint64, error) {
+	return c.node.blockManager.Rollback()
+}
+
+// PruneBlocks deletes blocks below retainHeight from the store and from the stores of header and block sync
+// services. It returns the number of pruned blocks.
+func (c *FullClient) PruneBlocks(ctx context.Context, retainHeight uint64) (uint64, error) {
+	return c.node.pruneBlocks(ctx, retainHeight)
+}
+
+// ResubmitBlocks submits blocks from the height range [from, to] to the DA layer again.
+// It returns the number of blocks scheduled for submission.
+func (c *FullClient) ResubmitBlocks(ctx context.Context, from, to uint64) (int, error) {
+	if !c.node.nodeConfig.Aggregator {
+		return 0, errors.New("blocks are submitted to the DA layer only in aggregator mode")
+	}
+	return c.node.blockManager.ResubmitBlocks(from, to)
+}
+
+// HaltBlockProcessing stops producing and syncing of blocks.
+func (c *FullClient) HaltBlockProcessing(ctx context.Context) error {
+	c.node.blockManager.HaltBlockProcessing()
+	return nil
+}
+
+// ResumeBlockProcessing resumes producing and syncing of blocks stopped by HaltBlockProcessing.
+func (c *FullClient) ResumeBlockProcessing(ctx context.Context) error {
+	c.node.blockManager.ResumeBlockProcessing()
+	return nil
+}
+
+// SetLogLevel changes log level of the module (block, da, p2p, rpc or store) to one of "debug", "info", "error"
+// or "none". Empty module changes log level of all modules.
+func (c *FullClient) SetLogLevel(ctx context.Context, module, level string) error {
+	return c.node.levelLogger.SetModuleLevel(module, level)
+}
+
+// DumpState returns a snapshot of internal state of the node, for debugging.
+func (c *FullClient) DumpState(ctx context.Context) (*DebugState, error) {
+	state, err := c.node.Store.LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the last saved state: %w", err)
+	}
+	return &DebugState{
+		State:                 state,
+		StoreHeight:           c.node.Store.Height(),
+		PendingBlocks:         c.node.blockManager.NumPendingBlocks(),
+		BlockProcessingHalted: c.node.blockManager.IsBlockProcessingHalted(),
+		MempoolSize:           c.node.Mempool.Size(),
+		Peers:                 len(c.node.p2pClient.Peers()),
+		HaltProof:             c.node.blockManager.HaltProof(),
+	}, nil
+}
+
+// ClearPeerScores forgets the persisted score and ban of the peer, or of all peers if id is empty.
+// It returns the number of forgotten peers.
+func (c *FullClient) ClearPeerScores(ctx context.Context, id string) (int, error) {
+	var pid peer.ID
+	if id != "" {
+		var err error
+		if pid, err = peer.Decode(id); err != nil {
+			return 0, fmt.Errorf("invalid peer ID: %w", err)
+		}
+	}
+	return c.node.p2pClient.ClearPeerScores(pid), nil
+}
+
 func (c *FullClient) eventsRoutine(sub cmtypes.Subscription, subscriber string, q cmpubsub.Query, outc chan<- ctypes.ResultEvent) {
 	defer close(outc)
 	for {
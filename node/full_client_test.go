@@ -21,6 +21,7 @@ import (
 	"github.com/cometbft/cometbft/libs/bytes"
 	"github.com/cometbft/cometbft/p2p"
 	"github.com/cometbft/cometbft/proxy"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/cometbft/cometbft/version"
 	"github.com/libp2p/go-libp2p/core/crypto"
@@ -42,6 +43,7 @@ var expectedInfo = abci.ResponseInfo{
 }
 
 const (
+	Info       = "Info"
 	InitChain  = "InitChain"
 	CheckTx    = "CheckTx"
 	BeginBlock = "BeginBlock"
@@ -76,6 +78,7 @@ func getRPC(t *testing.T) (*mocks.Application, *FullClient) {
 	require := require.New(t)
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(expectedInfo).Maybe()
 	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	signingKey, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	ctx := context.Background()
@@ -89,6 +92,14 @@ func getRPC(t *testing.T) (*mocks.Application, *FullClient) {
 	return app, rpc
 }
 
+// waitForUnconfirmedTxs waits until transactions broadcast with BroadcastTxAsync are added to the mempool.
+func waitForUnconfirmedTxs(t *testing.T, rpc *FullClient, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return rpc.node.Mempool.Size() >= n
+	}, time.Second, 10*time.Millisecond)
+}
+
 // From state/indexer/block/kv/kv_test
 func indexBlocks(t *testing.T, rpc *FullClient, heights []int64) {
 	t.Helper()
@@ -175,6 +186,7 @@ func TestGenesisChunked(t *testing.T) {
 
 	mockApp := &mocks.Application{}
 	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	privKey, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	signingKey, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	n, _ := newFullNode(context.Background(), config.NodeConfig{DALayer: "newda"}, privKey, signingKey, proxy.NewLocalClientCreator(mockApp), genDoc, test.NewFileLogger(t))
@@ -224,6 +236,40 @@ func TestBroadcastTxAsync(t *testing.T) {
 	assert.Empty(res.Log)
 	assert.Empty(res.Codespace)
 	assert.NotEmpty(res.Hash)
+	waitForUnconfirmedTxs(t, rpc, 1)
+	mockApp.AssertExpectations(t)
+}
+
+func TestBroadcastTxAsyncRejected(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rejectedTx := []byte("rejected")
+	acceptedTx := []byte("accepted")
+
+	mockApp, rpc := getRPC(t)
+	mockApp.On(CheckTx, abci.RequestCheckTx{Tx: rejectedTx}).Return(abci.ResponseCheckTx{Code: 1, Log: "invalid"})
+	mockApp.On(CheckTx, abci.RequestCheckTx{Tx: acceptedTx}).Return(abci.ResponseCheckTx{})
+
+	err := rpc.node.Start()
+	require.NoError(err)
+	defer func() {
+		assert.NoError(rpc.node.Stop())
+	}()
+
+	// result of CheckTx is not returned
+	res, err := rpc.BroadcastTxAsync(context.Background(), rejectedTx)
+	require.NoError(err)
+	assert.Empty(res.Code)
+	assert.Equal(bytes.HexBytes(cmtypes.Tx(rejectedTx).Hash()), res.Hash)
+
+	// transactions are checked in order, so the rejected one was checked before the accepted one
+	_, err = rpc.BroadcastTxAsync(context.Background(), acceptedTx)
+	require.NoError(err)
+	waitForUnconfirmedTxs(t, rpc, 1)
+	txs, err := rpc.UnconfirmedTxs(context.Background(), nil)
+	require.NoError(err)
+	assert.Equal([]cmtypes.Tx{acceptedTx}, txs.Txs)
 	mockApp.AssertExpectations(t)
 }
 
@@ -437,6 +483,32 @@ func TestBlockSearch(t *testing.T) {
 		})
 
 	}
+
+	t.Run("pagination and order", func(t *testing.T) {
+		page, perPage := 2, 4
+		result, err := rpc.BlockSearch(context.Background(), "block.height >= 1", &page, &perPage, "desc")
+		require.NoError(err)
+		assert.Equal(10, result.TotalCount)
+		require.Len(result.Blocks, 4)
+		for i, b := range result.Blocks {
+			assert.EqualValues(6-i, b.Block.Height)
+			byHash, err := rpc.BlockByHash(context.Background(), b.BlockID.Hash)
+			require.NoError(err)
+			assert.Equal(b.Block.Height, byHash.Block.Height)
+		}
+
+		// first page by default
+		result, err = rpc.BlockSearch(context.Background(), "block.height >= 1", nil, &perPage, "")
+		require.NoError(err)
+		require.Len(result.Blocks, 4)
+		assert.EqualValues(1, result.Blocks[0].Block.Height)
+
+		page = 4
+		_, err = rpc.BlockSearch(context.Background(), "block.height >= 1", &page, &perPage, "")
+		assert.Error(err)
+		_, err = rpc.BlockSearch(context.Background(), "block.height >= 1", nil, nil, "random")
+		assert.Error(err)
+	})
 }
 
 func TestGetBlockByHash(t *testing.T) {
@@ -481,6 +553,7 @@ func TestTx(t *testing.T) {
 
 	mockApp := &mocks.Application{}
 	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	genesisValidators, signingKey := getGenesisValidatorSetWithSigner()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -523,6 +596,16 @@ func TestTx(t *testing.T) {
 	assert.EqualValues(tx1, resTx.Tx)
 	assert.EqualValues(res.Hash, resTx.Hash)
 
+	block, err := rpc.node.Store.LoadBlock(uint64(resTx.Height))
+	require.NoError(err)
+	assert.NoError(resTx.Proof.Validate(block.SignedHeader.DataHash))
+
+	proof, err := rpc.TxProof(ctx, res.Hash)
+	require.NoError(err)
+	assert.Equal(resTx.Height, proof.Height)
+	assert.EqualValues(block.SignedHeader.DataHash, proof.DataHash)
+	assert.NoError(proof.Proof.Validate(block.SignedHeader.DataHash))
+
 	tx2 := cmtypes.Tx("tx2")
 	assert.Panics(func() {
 		resTx, errTx := rpc.Tx(ctx, tx2.Hash(), true)
@@ -567,6 +650,7 @@ func TestUnconfirmedTxs(t *testing.T) {
 				assert.NoError(err)
 				assert.NotNil(res)
 			}
+			waitForUnconfirmedTxs(t, rpc, len(c.txs))
 
 			numRes, err := rpc.NumUnconfirmedTxs(context.Background())
 			assert.NoError(err)
@@ -611,6 +695,7 @@ func TestUnconfirmedTxsLimit(t *testing.T) {
 	res, err = rpc.BroadcastTxAsync(context.Background(), tx2)
 	assert.NoError(err)
 	assert.NotNil(res)
+	waitForUnconfirmedTxs(t, rpc, 2)
 
 	limit := 1
 	txRes, err := rpc.UnconfirmedTxs(context.Background(), &limit)
@@ -624,6 +709,42 @@ func TestUnconfirmedTxsLimit(t *testing.T) {
 	assert.NotContains(txRes.Txs, tx2)
 }
 
+func TestUnconfirmedTxsPage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mockApp, rpc := getRPC(t)
+	mockApp.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
+	mockApp.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
+
+	err := rpc.node.Start()
+	require.NoError(err)
+	defer func() {
+		require.NoError(rpc.node.Stop())
+	}()
+
+	txs := []cmtypes.Tx{cmtypes.Tx("tx1"), cmtypes.Tx("tx2"), cmtypes.Tx("tx3")}
+	totalBytes := 0
+	for _, tx := range txs {
+		_, err := rpc.BroadcastTxAsync(context.Background(), tx)
+		require.NoError(err)
+		totalBytes += len(tx)
+	}
+	waitForUnconfirmedTxs(t, rpc, len(txs))
+
+	page, perPage := 2, 2
+	txRes, err := rpc.UnconfirmedTxsPage(context.Background(), &page, &perPage)
+	require.NoError(err)
+	assert.EqualValues(1, txRes.Count)
+	assert.EqualValues(3, txRes.Total)
+	assert.EqualValues(totalBytes, txRes.TotalBytes)
+	assert.Equal([]cmtypes.Tx{txs[2]}, txRes.Txs)
+
+	page = 3
+	_, err = rpc.UnconfirmedTxsPage(context.Background(), &page, &perPage)
+	assert.Error(err)
+}
+
 func TestConsensusState(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -830,6 +951,7 @@ func checkValSetLatest(rpc *FullClient, assert *assert.Assertions, lastBlockHeig
 func createApp(require *require.Assertions, vKeyToRemove cmcrypto.PrivKey, wg *sync.WaitGroup) *mocks.Application {
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(Commit, mock.Anything).Return(abci.ResponseCommit{})
@@ -912,6 +1034,7 @@ func TestMempool2Nodes(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, abci.RequestCheckTx{Tx: []byte("bad")}).Return(abci.ResponseCheckTx{Code: 1})
 	app.On(CheckTx, abci.RequestCheckTx{Tx: []byte("good")}).Return(abci.ResponseCheckTx{Code: 0})
 	key1, _, _ := crypto.GenerateEd25519Key(crand.Reader)
@@ -936,8 +1059,9 @@ func TestMempool2Nodes(t *testing.T) {
 		P2P: config.P2PConfig{
 			ListenAddress: "/ip4/127.0.0.1/tcp/9001",
 		},
+		// txs are broadcast between blocks, so they're in the mempool of node2 before the next block is synced
 		BlockManagerConfig: config.BlockManagerConfig{
-			BlockTime: 1 * time.Second,
+			BlockTime: 2 * time.Second,
 		},
 	}, key1, signingKey1, proxy.NewLocalClientCreator(app), &cmtypes.GenesisDoc{ChainID: "test"}, test.NewFileLogger(t))
 	require.NoError(err)
@@ -1002,6 +1126,7 @@ func TestStatus(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	key, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 	signingKey, _, _ := crypto.GenerateEd25519Key(crand.Reader)
 
@@ -1053,7 +1178,7 @@ func TestStatus(t *testing.T) {
 	require.NoError(err)
 	err = node.Store.SaveValidators(2, validatorSet)
 	require.NoError(err)
-	err = node.Store.UpdateState(types.State{LastValidators: validatorSet, NextValidators: validatorSet, Validators: validatorSet})
+	err = node.Store.UpdateState(types.State{LastBlockHeight: 2, LastValidators: validatorSet, NextValidators: validatorSet, Validators: validatorSet})
 	assert.NoError(err)
 
 	rpc := NewFullClient(node)
@@ -1115,6 +1240,17 @@ func TestStatus(t *testing.T) {
 	rawKey, err := key.GetPublic().Raw()
 	assert.NoError(err)
 	assert.Equal(p2p.ID(hex.EncodeToString(cmcrypto.AddressHash(rawKey))), resp.NodeInfo.DefaultNodeID)
+
+	rollkitStatus, err := rpc.RollkitStatus(context.Background())
+	require.NoError(err)
+	assert.True(rollkitStatus.Aggregator)
+	assert.False(rollkitStatus.LazyAggregator)
+	assert.Equal("newda", rollkitStatus.DALayer)
+	assert.False(rollkitStatus.DADegraded)
+	assert.GreaterOrEqual(rollkitStatus.SoftHeight, int64(2))
+	assert.LessOrEqual(rollkitStatus.DAIncludedHeight, rollkitStatus.SoftHeight)
+	assert.NotEmpty(rollkitStatus.SequencerAddress)
+	assert.Zero(rollkitStatus.RetainBlocks)
 }
 
 func TestFutureGenesisTime(t *testing.T) {
@@ -1127,6 +1263,7 @@ func TestFutureGenesisTime(t *testing.T) {
 	wg.Add(1)
 	mockApp := &mocks.Application{}
 	mockApp.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	mockApp.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	mockApp.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{}).Run(func(_ mock.Arguments) {
 		beginBlockTime = time.Now()
 		wg.Done()
@@ -1213,3 +1350,75 @@ func TestNetInfo(t *testing.T) {
 	assert.True(netInfo.Listening)
 	assert.Equal(0, len(netInfo.Peers))
 }
+
+func TestDACosts(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	_, rpc := getRPC(t)
+
+	now := time.Now()
+	for h := uint64(1); h <= 3; h++ {
+		require.NoError(rpc.node.Store.SaveBlock(types.GetRandomBlock(h, 1), &types.Commit{}))
+		rpc.node.Store.SetHeight(h)
+	}
+	// block 3 was synced from DA, not submitted by the node
+	require.NoError(rpc.node.Store.SaveDACost(1, store.DACost{DAHeight: 5, Fee: 100, BlobSize: 1000, Time: now}))
+	require.NoError(rpc.node.Store.SaveDACost(2, store.DACost{DAHeight: 6, Fee: 50, BlobSize: 500, Time: now}))
+
+	res, err := rpc.DACosts(context.Background(), 0, 0, 0)
+	require.NoError(err)
+	require.Len(res.Blocks, 2)
+	assert.Equal(int64(2), res.Blocks[0].Height)
+	assert.Equal(uint64(50), res.Blocks[0].Fee)
+	assert.Equal(int64(1), res.Blocks[1].Height)
+	assert.Equal(uint64(6), res.Blocks[0].DAHeight)
+	require.Len(res.Daily, defaultDACostDays)
+	assert.Equal(store.DailyDACost{Date: now.UTC().Format(time.DateOnly), Fee: 150, Blocks: 2, BlobSize: 1500}, res.Daily[0])
+	assert.Zero(res.Daily[1].Fee)
+
+	res, err = rpc.DACosts(context.Background(), 2, 3, 1)
+	require.NoError(err)
+	require.Len(res.Blocks, 1)
+	assert.Equal(int64(2), res.Blocks[0].Height)
+	assert.Len(res.Daily, 1)
+
+	_, err = rpc.DACosts(context.Background(), 0, 0, maxDACostDays+1)
+	assert.Error(err)
+}
+
+func TestABCIQueryWithOptions(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	mockApp, rpc := getRPC(t)
+	ctx := context.Background()
+
+	for h := uint64(1); h <= 3; h++ {
+		require.NoError(rpc.node.Store.SaveBlock(types.GetRandomBlock(h, 1), &types.Commit{}))
+		rpc.node.Store.SetHeight(h)
+	}
+	// app hash of block 1 is unknown, e.g. the node was started from a snapshot of height 2
+	require.NoError(rpc.node.Store.SaveAppHash(2, []byte{2}))
+	require.NoError(rpc.node.Store.SaveAppHash(3, []byte{3}))
+	mockApp.On("Query", mock.Anything).Return(func(req abci.RequestQuery) abci.ResponseQuery {
+		return abci.ResponseQuery{Key: req.Data, Value: []byte("value"), Height: req.Height}
+	})
+
+	res, err := rpc.ABCIQueryWithOptions(ctx, "/store/bank/key", []byte("key"), rpcclient.ABCIQueryOptions{Height: 2, Prove: true})
+	require.NoError(err)
+	assert.Equal(int64(2), res.Response.Height)
+	appHash, err := rpc.AppHash(ctx, &res.Response.Height)
+	require.NoError(err)
+	assert.Equal([]byte{2}, []byte(appHash.AppHash))
+	latest, err := rpc.AppHash(ctx, nil)
+	require.NoError(err)
+	assert.Equal(int64(3), latest.Height)
+
+	_, err = rpc.ABCIQueryWithOptions(ctx, "/store/bank/key", []byte("key"), rpcclient.ABCIQueryOptions{Height: 1, Prove: true})
+	assert.Error(err)
+	_, err = rpc.ABCIQueryWithOptions(ctx, "/store/bank/key", []byte("key"), rpcclient.ABCIQueryOptions{Height: 1})
+	assert.NoError(err)
+	_, err = rpc.ABCIQueryWithOptions(ctx, "/store/bank/key", []byte("key"), rpcclient.ABCIQueryOptions{Height: 4})
+	assert.Error(err)
+	_, err = rpc.ABCIQueryWithOptions(ctx, "/store/bank/key", []byte("key"), rpcclient.ABCIQueryOptions{Height: -1})
+	assert.Error(err)
+}
@@ -38,6 +38,7 @@ func TestAggregatorMode(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{})
@@ -134,6 +135,7 @@ func TestLazyAggregator(t *testing.T) {
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{})
@@ -629,6 +631,7 @@ func createNode(ctx context.Context, n int, aggregator bool, isLight bool, keys
 
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
 	app.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{})
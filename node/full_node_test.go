@@ -9,15 +9,20 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	abci "github.com/cometbft/cometbft/abci/types"
+	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	testutils "github.com/celestiaorg/utils/test"
 
+	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/supervisor"
 	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
 )
 
 // TestStartup checks if the node starts and stops without any errors
@@ -27,6 +32,38 @@ func TestStartup(t *testing.T) {
 	defer cleanUpNode(node, t)
 }
 
+func TestSupervisor(t *testing.T) {
+	ctx := context.Background()
+	node := setupTestNode(ctx, t, "full").(*FullNode)
+
+	started := false
+	require.NoError(t, node.Supervisor().Add(supervisor.Service{
+		Name:      "app",
+		DependsOn: []string{ServiceBlockSync},
+		Start: func(context.Context) error {
+			started = true
+			return nil
+		},
+	}))
+	events := node.Supervisor().Subscribe(100)
+
+	require.NoError(t, node.Start())
+	assert.True(t, started)
+	states := node.Supervisor().States()
+	for _, name := range []string{ServiceP2P, ServiceHeaderSync, ServiceBlockSync, ServiceDA, "sync", "app"} {
+		assert.Equal(t, supervisor.StateRunning, states[name], name)
+	}
+
+	cleanUpNode(node, t)
+	stopped := 0
+	for ev := range events {
+		if ev.State == supervisor.StateStopped {
+			stopped++
+		}
+	}
+	assert.Equal(t, len(states), stopped)
+}
+
 func TestMempoolDirectly(t *testing.T) {
 	ctx := context.Background()
 
@@ -43,6 +80,7 @@ func TestMempoolDirectly(t *testing.T) {
 func setupMockApplication() *mocks.Application {
 	app := &mocks.Application{}
 	app.On(InitChain, mock.Anything).Return(abci.ResponseInitChain{})
+	app.On(Info, mock.Anything).Return(abci.ResponseInfo{}).Maybe()
 	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
 	return app
 }
@@ -83,3 +121,32 @@ func verifyMempoolSize(node *FullNode, assert *assert.Assertions) {
 		return fmt.Errorf("expected size %v, got size %v", expectedSize, actualSize)
 	}))
 }
+
+func TestApplyGenesisDAParams(t *testing.T) {
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:  "test",
+		AppState: []byte(`{"rollkit": {"da_namespace_id": "0102030405060708", "da_chain_id": "mocha-4"}}`),
+	}
+	namespace := types.NamespaceID{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// namespace is taken from genesis
+	conf := config.NodeConfig{}
+	daChainID, err := applyGenesisDAParams(&conf, genesis)
+	require.NoError(t, err)
+	assert.Equal(t, namespace, conf.NamespaceID)
+	assert.Equal(t, "mocha-4", daChainID)
+
+	// namespace configured explicitly must match genesis
+	conf = config.NodeConfig{BlockManagerConfig: config.BlockManagerConfig{NamespaceID: namespace}}
+	_, err = applyGenesisDAParams(&conf, genesis)
+	assert.NoError(t, err)
+	conf.NamespaceID = types.NamespaceID{8, 7, 6, 5, 4, 3, 2, 1}
+	_, err = applyGenesisDAParams(&conf, genesis)
+	assert.ErrorIs(t, err, ErrDAParamsMismatch)
+
+	// configured namespace is used if genesis doesn't define it
+	daChainID, err = applyGenesisDAParams(&conf, &cmtypes.GenesisDoc{ChainID: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, types.NamespaceID{8, 7, 6, 5, 4, 3, 2, 1}, conf.NamespaceID)
+	assert.Empty(t, daChainID)
+}
@@ -0,0 +1,211 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	// pruneInterval defines how often pruned node deletes blocks outside of the retained window.
+	pruneInterval = 1 * time.Minute
+	// maxHistoryBlocks limits the number of blocks served in response to a single history request.
+	maxHistoryBlocks = 100
+)
+
+// isPruned returns true if the node keeps only the most recent blocks.
+func (n *FullNode) isPruned() bool {
+	return n.nodeConfig.NodeRole == config.NodeRolePruned
+}
+
+// serveHistory serves blocks and application snapshots to peers. It's used by archival nodes.
+func (n *FullNode) serveHistory(ctx context.Context, req *p2p.HistoryRequest) ([][]byte, error) {
+	switch req.Type {
+	case p2p.HistoryBlocks:
+		if req.From == 0 || req.From > req.To || req.To-req.From >= maxHistoryBlocks {
+			return nil, fmt.Errorf("invalid range of blocks [%d, %d]", req.From, req.To)
+		}
+		data := make([][]byte, 0, req.To-req.From+1)
+		for h := req.From; h <= req.To; h++ {
+			block, err := n.Store.LoadBlock(h)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load block %d: %w", h, err)
+			}
+			bz, err := block.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, bz)
+		}
+		return data, nil
+	case p2p.HistorySnapshots:
+		resp, err := n.proxyApp.Snapshot().ListSnapshotsSync(abci.RequestListSnapshots{})
+		if err != nil {
+			return nil, err
+		}
+		data := make([][]byte, 0, len(resp.Snapshots))
+		for _, snapshot := range resp.Snapshots {
+			bz, err := snapshot.Marshal()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, bz)
+		}
+		return data, nil
+	case p2p.HistorySnapshotChunk:
+		resp, err := n.proxyApp.Snapshot().LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+			Height: req.Height,
+			Format: req.Format,
+			Chunk:  req.Chunk,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{resp.Chunk}, nil
+	default:
+		return nil, fmt.Errorf("unknown history request type: %s", req.Type)
+	}
+}
+
+// pruneLoop deletes blocks outside of the window of RetainBlocks most recent blocks. It's used by pruned nodes.
+func (n *FullNode) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	logger := n.Logger.With("module", "store")
+	retainHeight := uint64(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			height := n.Store.Height()
+			if height <= n.nodeConfig.RetainBlocks || height-n.nodeConfig.RetainBlocks+1 <= retainHeight {
+				continue
+			}
+			retainHeight = height - n.nodeConfig.RetainBlocks + 1
+			pruned, err := n.pruneBlocks(ctx, retainHeight)
+			if err != nil {
+				logger.Error("failed to prune blocks", "retainHeight", retainHeight, "error", err)
+				continue
+			}
+			logger.Debug("pruned blocks", "retainHeight", retainHeight, "pruned", pruned)
+		}
+	}
+}
+
+// pruneBlocks deletes blocks below retainHeight from the store, and headers and blocks below retainHeight from
+// the stores of header and block sync services, so all of them keep the same window of blocks.
+// It returns the number of blocks pruned from the store.
+func (n *FullNode) pruneBlocks(ctx context.Context, retainHeight uint64) (uint64, error) {
+	pruned, err := n.Store.PruneBlocks(retainHeight)
+	if err != nil {
+		return pruned, err
+	}
+	if _, err := n.hSyncService.Prune(ctx, retainHeight); err != nil {
+		return pruned, fmt.Errorf("failed to prune header store: %w", err)
+	}
+	if _, err := n.bSyncService.Prune(ctx, retainHeight); err != nil {
+		return pruned, fmt.Errorf("failed to prune block store: %w", err)
+	}
+	return pruned, nil
+}
+
+// headersFirst returns true if the node syncs the header chain first, and fetches block data lazily.
+func (n *FullNode) headersFirst() bool {
+	return n.nodeConfig.SyncMode == config.SyncModeHeadersFirst
+}
+
+// headersFirstSyncLoop waits until the complete header chain up to the head of the network is synced, and then
+// fetches data of blocks by ranges, passing them to the block manager.
+func (n *FullNode) headersFirstSyncLoop(ctx context.Context) {
+	height, err := n.hSyncService.WaitSynced(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			n.Logger.Error("failed to sync header chain", "error", err)
+		}
+		return
+	}
+	n.Logger.Info("header chain synced", "height", height)
+	n.blockManager.HeadersFirstSyncLoop(ctx)
+}
+
+// lazyBlockData returns true if the node fetches blocks missing in the store on demand: pruned node fetches pruned
+// blocks, and node in headers-first sync mode fetches blocks not synced yet.
+func (n *FullNode) lazyBlockData() bool {
+	return n.isPruned() || n.headersFirst()
+}
+
+// earliestHeight returns the lowest height of blocks served by the node. Blocks missing in the store are fetched
+// from peers (or DA layer) by pruned nodes and nodes in headers-first sync mode, as long as their headers are kept
+// in the header store.
+func (n *FullNode) earliestHeight(ctx context.Context) (uint64, error) {
+	earliest := n.Store.EarliestHeight()
+	if !n.lazyBlockData() {
+		return earliest, nil
+	}
+	headerEarliest, err := n.hSyncService.EarliestHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find earliest header: %w", err)
+	}
+	if headerEarliest != 0 && (earliest == 0 || headerEarliest < earliest) {
+		earliest = headerEarliest
+	}
+	return earliest, nil
+}
+
+// checkHeightAvailable returns error if block at given height was pruned and can't be served by the node.
+func (n *FullNode) checkHeightAvailable(ctx context.Context, height uint64) error {
+	earliest, err := n.earliestHeight(ctx)
+	if err != nil {
+		return err
+	}
+	if height < earliest {
+		return fmt.Errorf("%w: height %d, lowest height is %d", ErrHeightNotAvailable, height, earliest)
+	}
+	return nil
+}
+
+// loadBlock returns block at given height. Pruned node requests blocks missing in the store from archival peers.
+// Node in headers-first sync mode also requests blocks above the height of the store, up to the height of
+// the header store. Fetched blocks are verified against headers synced from the network.
+func (n *FullNode) loadBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	if err := n.checkHeightAvailable(ctx, height); err != nil {
+		return nil, err
+	}
+	block, err := n.Store.LoadBlock(height)
+	if err == nil || !n.lazyBlockData() || (height > n.Store.Height() && !n.headersFirst()) {
+		return block, err
+	}
+	blocks, err := n.blockManager.FetchBlockRange(ctx, height, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block %d: %w", height, err)
+	}
+	return blocks[0], nil
+}
+
+// fetchBlockRange requests blocks of the range [from, to] from archival peers, until blocks accepted by verify
+// are received. Peers sending invalid blocks are penalized.
+func (n *FullNode) fetchBlockRange(ctx context.Context, from, to uint64, verify func([]*types.Block) error) ([]*types.Block, error) {
+	var blocks []*types.Block
+	req := &p2p.HistoryRequest{Type: p2p.HistoryBlocks, From: from, To: to}
+	_, err := n.p2pClient.RequestHistoryFromPeers(ctx, req, func(data [][]byte) error {
+		blocks = make([]*types.Block, len(data))
+		for i := range data {
+			blocks[i] = new(types.Block)
+			if err := blocks[i].UnmarshalBinary(data[i]); err != nil {
+				return err
+			}
+		}
+		return verify(blocks)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/p2p"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+func newRoleTestNode(t *testing.T, role string, retainBlocks uint64) (*FullNode, error) {
+	conf := config.NodeConfig{DALayer: "newda", NodeRole: role, RetainBlocks: retainBlocks}
+	return newFullNode(context.Background(), conf, generateSingleKey(), generateSingleKey(),
+		proxy.NewLocalClientCreator(setupMockApplication()), &cmtypes.GenesisDoc{ChainID: "test"}, test.NewFileLogger(t))
+}
+
+func TestNodeRoles(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := newRoleTestNode(t, "unknown", 0)
+	assert.Error(err)
+	_, err = newRoleTestNode(t, config.NodeRolePruned, 0)
+	assert.Error(err)
+
+	n, err := newRoleTestNode(t, config.NodeRolePruned, 10)
+	assert.NoError(err)
+	assert.True(n.isPruned())
+	n, err = newRoleTestNode(t, "", 0)
+	assert.NoError(err)
+	assert.False(n.isPruned())
+}
+
+func TestServeHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	n, err := newRoleTestNode(t, config.NodeRoleArchival, 0)
+	require.NoError(err)
+	var blocks []*types.Block
+	for h := uint64(1); h <= 3; h++ {
+		block := types.GetRandomBlock(h, 2)
+		require.NoError(n.Store.SaveBlock(block, &block.SignedHeader.Commit))
+		n.Store.SetHeight(h)
+		blocks = append(blocks, block)
+	}
+
+	data, err := n.serveHistory(ctx, &p2p.HistoryRequest{Type: p2p.HistoryBlocks, From: 2, To: 3})
+	require.NoError(err)
+	require.Len(data, 2)
+	for i, bz := range data {
+		var block types.Block
+		require.NoError(block.UnmarshalBinary(bz))
+		assert.Equal(blocks[i+1].Hash(), block.Hash())
+	}
+
+	_, err = n.serveHistory(ctx, &p2p.HistoryRequest{Type: p2p.HistoryBlocks, From: 3, To: 2})
+	assert.Error(err)
+	_, err = n.serveHistory(ctx, &p2p.HistoryRequest{Type: p2p.HistoryBlocks, From: 1, To: maxHistoryBlocks + 1})
+	assert.Error(err)
+	_, err = n.serveHistory(ctx, &p2p.HistoryRequest{Type: p2p.HistoryBlocks, From: 3, To: 4})
+	assert.Error(err)
+	_, err = n.serveHistory(ctx, &p2p.HistoryRequest{Type: "unknown"})
+	assert.Error(err)
+}
+
+func TestPruneBlocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	n, err := newRoleTestNode(t, config.NodeRoleArchival, 0)
+	require.NoError(err)
+	for h := uint64(1); h <= 5; h++ {
+		block := types.GetRandomBlock(h, 1)
+		require.NoError(n.Store.SaveBlock(block, &block.SignedHeader.Commit))
+		n.Store.SetHeight(h)
+	}
+	earliest, err := n.earliestHeight(ctx)
+	require.NoError(err)
+	assert.Equal(uint64(1), earliest)
+
+	pruned, err := n.pruneBlocks(ctx, 3)
+	require.NoError(err)
+	assert.Equal(uint64(2), pruned)
+	earliest, err = n.earliestHeight(ctx)
+	require.NoError(err)
+	assert.Equal(uint64(3), earliest)
+
+	_, err = n.loadBlock(ctx, 2)
+	assert.ErrorIs(err, ErrHeightNotAvailable)
+	block, err := n.loadBlock(ctx, 3)
+	require.NoError(err)
+	assert.Equal(uint64(3), block.Height())
+}
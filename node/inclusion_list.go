@@ -0,0 +1,95 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/types"
+)
+
+// defaultInclusionListDeadline is the deadline of inclusion lists broadcast without explicit deadline.
+const defaultInclusionListDeadline = 10
+
+// ResultBroadcastInclusionList is the result of BroadcastInclusionList. List is signed only if the node is
+// the aggregator, and it has all transactions of the list in its mempool.
+type ResultBroadcastInclusionList struct {
+	Hash cmbytes.HexBytes     `json:"hash"`
+	List *types.InclusionList `json:"list"`
+}
+
+// BroadcastInclusionList submits inclusion list of transactions with given hashes, that the sequencer has to
+// include within deadline blocks after the latest block of the node (defaultInclusionListDeadline if zero).
+// The list is tracked by the node and gossiped to other nodes, including the aggregator.
+func (c *FullClient) BroadcastInclusionList(ctx context.Context, txHashes []cmbytes.HexBytes, deadline uint64) (*ResultBroadcastInclusionList, error) {
+	if deadline == 0 {
+		deadline = defaultInclusionListDeadline
+	}
+	list := &types.InclusionList{
+		ChainID:  c.node.genesis.ChainID,
+		Height:   c.node.Store.Height(),
+		Deadline: deadline,
+		TxHashes: txHashes,
+	}
+	signed, err := c.node.blockManager.AddInclusionList(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	data, err := list.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.node.p2pClient.GossipInclusionList(ctx, data); err != nil {
+		return nil, fmt.Errorf("failed to gossip inclusion list: %w", err)
+	}
+	if signed != nil {
+		list = signed
+	}
+	return &ResultBroadcastInclusionList{Hash: cmbytes.HexBytes(list.Hash()), List: list}, nil
+}
+
+// InclusionListStatus describes compliance of the sequencer with inclusion lists, returned by InclusionLists.
+type InclusionListStatus = block.InclusionListStatus
+
+// InclusionLists returns compliance of the sequencer with inclusion lists tracked by the node.
+func (c *FullClient) InclusionLists(ctx context.Context) (*InclusionListStatus, error) {
+	return c.node.blockManager.InclusionListStatus(), nil
+}
+
+// inclusionListPublishLoop gossips inclusion lists signed by the node as the proposer.
+func (n *FullNode) inclusionListPublishLoop(ctx context.Context) {
+	for {
+		select {
+		case list := <-n.blockManager.InclusionListOutCh:
+			data, err := list.MarshalBinary()
+			if err == nil {
+				err = n.p2pClient.GossipInclusionList(ctx, data)
+			}
+			if err != nil {
+				n.Logger.Error("failed to gossip inclusion list", "hash", list.Hash(), "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// newInclusionListValidator creates a pubsub validator that passes gossiped inclusion lists to the block manager.
+// Valid lists are relayed.
+func (n *FullNode) newInclusionListValidator() p2p.GossipValidator {
+	return func(m *p2p.GossipMessage) bool {
+		n.Logger.Debug("inclusion list received", "bytes", len(m.Data))
+		var list types.InclusionList
+		if err := list.UnmarshalBinary(m.Data); err != nil {
+			return false
+		}
+		if _, err := n.blockManager.AddInclusionList(n.ctx, &list); err != nil {
+			n.Logger.Info("invalid inclusion list received", "hash", list.Hash(), "error", err)
+			return false
+		}
+		return true
+	}
+}
@@ -1,9 +1,15 @@
 package node
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
 
+	llcfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/libs/service"
 	proxy "github.com/cometbft/cometbft/proxy"
@@ -11,16 +17,24 @@ import (
 	cmtypes "github.com/cometbft/cometbft/types"
 	ds "github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/multierr"
 
 	"github.com/rollkit/rollkit/block"
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/state"
 	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/tracing"
+	"github.com/rollkit/rollkit/types"
 )
 
 var _ Node = &LightNode{}
 
+// fraudProofHeaderTimeout limits the time the light node waits for the header of the block disputed by a state fraud
+// proof to be synced.
+const fraudProofHeaderTimeout = 5 * time.Second
+
 // LightNode is a rollup node that only needs the header service
 type LightNode struct {
 	service.BaseService
@@ -30,6 +44,19 @@ type LightNode struct {
 	proxyApp proxy.AppConns
 
 	hSyncService *block.HeaderSyncService
+	// transitionVerifier re-executes transactions from state fraud proofs using the application
+	transitionVerifier state.StateTransitionVerifier
+	// dataHasher computes DataHash of blocks of the chain, as defined in genesis
+	dataHasher types.MerkleHasher
+
+	instrumentation *llcfg.InstrumentationConfig
+	chainID         string
+	prometheusSrv   *http.Server
+	tracing         tracing.Config
+	tracer          *sdktrace.TracerProvider
+
+	// fraudProof is the first verified state fraud proof received by the node
+	fraudProof atomic.Pointer[types.StateFraudProof]
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -50,21 +77,40 @@ func newLightNode(
 	genesis *cmtypes.GenesisDoc,
 	logger log.Logger,
 ) (*LightNode, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	nodeLogger, err := newNodeLogger(conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger = nodeLogger
+	metrics := newNodeMetrics(conf.Instrumentation, genesis.ChainID, "light")
+	dataHasher, err := types.DataHasherFromGenesis(genesis)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
-	proxyApp := proxy.NewAppConns(clientCreator, proxy.NopMetrics())
+	proxyApp := proxy.NewAppConns(clientCreator, metrics.proxy)
 	proxyApp.SetLogger(logger.With("module", "proxy"))
 	if err := proxyApp.Start(); err != nil {
 		return nil, fmt.Errorf("error while starting proxy app connections: %v", err)
 	}
 
-	datastore, err := openDatastore(conf, logger)
+	datastore, err := openDatastore(conf, logger.With("module", "store"))
 	if err != nil {
 		return nil, err
 	}
-	client, err := p2p.NewClient(conf.P2P, p2pKey, genesis.ChainID, datastore, logger.With("module", "p2p"))
+	client, err := p2p.NewClient(conf.P2P, p2pKey, genesis.ChainID, datastore, logger.With("module", "p2p"), metrics.p2p)
 	if err != nil {
 		return nil, err
 	}
+	genesisHash, err := types.GenesisHash(genesis)
+	if err != nil {
+		return nil, err
+	}
+	client.SetGenesisHash(genesisHash)
 
 	headerSyncService, err := block.NewHeaderSyncService(ctx, datastore, conf, genesis, client, logger.With("module", "HeaderSyncService"))
 	if err != nil {
@@ -74,14 +120,21 @@ func newLightNode(
 	ctx, cancel := context.WithCancel(ctx)
 
 	node := &LightNode{
-		P2P:          client,
-		proxyApp:     proxyApp,
-		hSyncService: headerSyncService,
-		cancel:       cancel,
-		ctx:          ctx,
+		P2P:                client,
+		proxyApp:           proxyApp,
+		hSyncService:       headerSyncService,
+		transitionVerifier: state.NewABCIIntermediateStateRootProvider(proxyApp.Query()),
+		instrumentation:    conf.Instrumentation,
+		tracing:            tracingConfig(conf),
+		chainID:            genesis.ChainID,
+		dataHasher:         dataHasher,
+		cancel:             cancel,
+		ctx:                ctx,
 	}
 
 	node.P2P.SetTxValidator(node.falseValidator())
+	node.P2P.SetFraudProofValidator(node.newFraudProofValidator())
+	node.P2P.SetEvidenceValidator(node.newEvidenceValidator())
 
 	node.BaseService = *service.NewBaseService(logger, "LightNode", node)
 
@@ -103,6 +156,13 @@ func (n *LightNode) Cancel() {
 
 // OnStart starts the P2P and HeaderSync services
 func (ln *LightNode) OnStart() error {
+	ln.prometheusSrv = startPrometheusServer(ln.instrumentation, ln.Logger)
+	tracer, err := startTracing(ln.ctx, ln.tracing, ln.chainID, "light", ln.Logger)
+	if err != nil {
+		return err
+	}
+	ln.tracer = tracer
+
 	if err := ln.P2P.Start(ln.ctx); err != nil {
 		return err
 	}
@@ -120,9 +180,105 @@ func (ln *LightNode) OnStop() {
 	ln.cancel()
 	err := ln.P2P.Close()
 	err = multierr.Append(err, ln.hSyncService.Stop())
+	if ln.prometheusSrv != nil {
+		err = multierr.Append(err, ln.prometheusSrv.Shutdown(context.Background()))
+	}
+	if ln.tracer != nil {
+		err = multierr.Append(err, ln.tracer.Shutdown(context.Background()))
+	}
 	ln.Logger.Error("errors while stopping node:", "errors", err)
 }
 
+// MetricsConfig returns Prometheus namespace and labels used by node metrics, or ok == false
+// if Prometheus metrics are disabled.
+func (ln *LightNode) MetricsConfig() (namespace string, labelsAndValues []string, ok bool) {
+	return metricsConfig(ln.instrumentation, ln.chainID, "light")
+}
+
+// Ready returns an error if node is not ready to serve traffic, i.e. it's not running, the application
+// is not responding or a state fraud proof was received.
+func (ln *LightNode) Ready(_ context.Context) error {
+	if !ln.IsRunning() {
+		return errors.New("node is not running")
+	}
+	if proof := ln.FraudProof(); proof != nil {
+		return fmt.Errorf("chain may be faulty: received state fraud proof for height %d", proof.BlockHeight)
+	}
+	if _, err := ln.proxyApp.Query().InfoSync(proxy.RequestInfo); err != nil {
+		return fmt.Errorf("application is not responding: %w", err)
+	}
+	return nil
+}
+
+// newFraudProofValidator creates a pubsub validator that verifies gossiped state fraud proofs. Proofs are verified
+// against the trusted header of the disputed block (see verifyFraudProof), so only proofs of transitions committed
+// by the aggregator are relayed; the sending peer is penalized for invalid proofs. The first verified proof is
+// recorded and reported by the Health endpoint.
+func (ln *LightNode) newFraudProofValidator() p2p.GossipValidator {
+	return func(m *p2p.GossipMessage) bool {
+		var proof types.StateFraudProof
+		if err := proof.UnmarshalBinary(m.Data); err != nil {
+			return false
+		}
+		verified, err := ln.verifyFraudProof(&proof)
+		if err != nil {
+			ln.Logger.Info("invalid fraud proof received", "height", proof.BlockHeight, "peer", m.ReceivedFrom, "error", err)
+			return false
+		}
+		if verified && ln.fraudProof.CompareAndSwap(nil, &proof) {
+			ln.Logger.Error("received state fraud proof", "height", proof.BlockHeight)
+		}
+		return true
+	}
+}
+
+// verifyFraudProof returns an error if the state fraud proof is invalid. The disputed transaction and state roots
+// have to be included in DataHash of the synced (trusted) header of the block, and re-execution of the transaction
+// with witnesses by the application has to yield a state root different than the committed one.
+//
+// If the application is not able to re-execute transactions, verified is false: the proof is relayed to nodes able to
+// verify it, but it's not recorded.
+func (ln *LightNode) verifyFraudProof(proof *types.StateFraudProof) (verified bool, err error) {
+	if err := proof.ValidateBasic(); err != nil {
+		return false, err
+	}
+	ctx, cancel := context.WithTimeout(ln.ctx, fraudProofHeaderTimeout)
+	defer cancel()
+	header, err := ln.hSyncService.HeaderStore().GetByHeight(ctx, proof.BlockHeight)
+	if err != nil {
+		return false, fmt.Errorf("failed to load header: %w", err)
+	}
+	if err := proof.VerifyInclusion(header.DataHash, ln.dataHasher); err != nil {
+		return false, err
+	}
+	postStateRoot, err := ln.transitionVerifier.ExecuteWithWitnesses(proof)
+	if err != nil {
+		ln.Logger.Info("unable to verify state fraud proof", "height", proof.BlockHeight, "error", err)
+		return false, nil
+	}
+	if bytes.Equal(postStateRoot, proof.CommittedPostStateRoot) {
+		return false, fmt.Errorf("%w: committed state transition is valid", state.ErrInvalidFraudProof)
+	}
+	return true, nil
+}
+
+// newEvidenceValidator creates a pubsub validator that relays evidence of aggregator equivocation passing
+// basic validation.
+func (ln *LightNode) newEvidenceValidator() p2p.GossipValidator {
+	return func(m *p2p.GossipMessage) bool {
+		var ev types.DuplicateHeaderEvidence
+		if err := ev.UnmarshalBinary(m.Data); err != nil {
+			return false
+		}
+		return ev.ValidateBasic() == nil
+	}
+}
+
+// FraudProof returns the first verified state fraud proof received by the node, or nil.
+func (ln *LightNode) FraudProof() *types.StateFraudProof {
+	return ln.fraudProof.Load()
+}
+
 // Dummy validator that always returns a callback function with boolean `false`
 func (ln *LightNode) falseValidator() p2p.GossipValidator {
 	return func(*p2p.GossipMessage) bool {
@@ -2,11 +2,16 @@ package node
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/celestiaorg/go-header"
 	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cometbft/cometbft/types"
+
+	rtypes "github.com/rollkit/rollkit/types"
+	abciconv "github.com/rollkit/rollkit/types/abci"
 )
 
 var _ rpcclient.Client = &LightClient{}
@@ -107,8 +112,12 @@ func (c *LightClient) ConsensusParams(ctx context.Context, height *int64) (*ctyp
 }
 
 // Health endpoint returns empty value. It can be used to monitor service availability.
+// An error is returned if the node received a state fraud proof.
 func (c *LightClient) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
-	panic("Not implemented")
+	if proof := c.node.FraudProof(); proof != nil {
+		return nil, fmt.Errorf("chain may be faulty: received state fraud proof for height %d", proof.BlockHeight)
+	}
+	return &ctypes.ResultHealth{}, nil
 }
 
 // Block method returns BlockID and block itself for given height.
@@ -129,8 +138,19 @@ func (c *LightClient) BlockResults(ctx context.Context, height *int64) (*ctypes.
 }
 
 // Commit returns signed header (aka commit) at given height.
+//
+// If height is nil, it returns the commit of the latest synced header.
 func (c *LightClient) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
-	panic("Not implemented")
+	sh, err := c.signedHeader(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	abciHeader, err := abciconv.ToABCIHeader(&sh.Header)
+	if err != nil {
+		return nil, err
+	}
+	commit := sh.Commit.ToABCICommit(sh.Height(), sh.Hash())
+	return ctypes.NewResultCommit(&abciHeader, commit, true), nil
 }
 
 // Validators returns paginated list of validators at given height.
@@ -181,12 +201,50 @@ func (c *LightClient) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultC
 	panic("Not implemented")
 }
 
-// Header satisfies the client interface but is not implemented
+// Header returns the synced header at given height.
+//
+// If height is nil, it returns the latest synced header.
 func (c *LightClient) Header(ctx context.Context, height *int64) (*ctypes.ResultHeader, error) {
-	panic("Not implemented")
+	sh, err := c.signedHeader(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return toResultHeader(sh)
 }
 
-// HeaderByHash satisfies the client interface but is not implemented
+// HeaderByHash returns the synced header with given hash.
 func (c *LightClient) HeaderByHash(ctx context.Context, hash cmbytes.HexBytes) (*ctypes.ResultHeader, error) {
-	panic("Not implemented")
+	sh, err := c.node.hSyncService.HeaderStore().Get(ctx, header.Hash(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load header: %w", err)
+	}
+	return toResultHeader(sh)
+}
+
+// signedHeader loads the signed header at given height from the header store, or the head of the store if height is nil.
+func (c *LightClient) signedHeader(ctx context.Context, height *int64) (*rtypes.SignedHeader, error) {
+	store := c.node.hSyncService.HeaderStore()
+	var (
+		sh  *rtypes.SignedHeader
+		err error
+	)
+	if height == nil || *height == 0 {
+		sh, err = store.Head(ctx)
+	} else if *height < 0 {
+		return nil, fmt.Errorf("invalid height: %d", *height)
+	} else {
+		sh, err = store.GetByHeight(ctx, uint64(*height))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load header: %w", err)
+	}
+	return sh, nil
+}
+
+func toResultHeader(sh *rtypes.SignedHeader) (*ctypes.ResultHeader, error) {
+	abciHeader, err := abciconv.ToABCIHeader(&sh.Header)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultHeader{Header: &abciHeader}, nil
 }
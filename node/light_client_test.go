@@ -4,8 +4,12 @@ import (
 	"context"
 	"testing"
 
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
 )
 
 // TestLightClient_Panics tests that all methods of LightClient and ensures that
@@ -107,12 +111,6 @@ func TestLightClient_Panics(t *testing.T) {
 				_, _ = ln.GetClient().CheckTx(ctx, []byte{})
 			},
 		},
-		{
-			name: "Commit",
-			fn: func() {
-				_, _ = ln.GetClient().Commit(ctx, nil)
-			},
-		},
 		{
 			name: "ConsensusParams",
 			fn: func() {
@@ -143,24 +141,6 @@ func TestLightClient_Panics(t *testing.T) {
 				_, _ = ln.GetClient().GenesisChunked(ctx, 0)
 			},
 		},
-		{
-			name: "Header",
-			fn: func() {
-				_, _ = ln.GetClient().Header(ctx, nil)
-			},
-		},
-		{
-			name: "HeaderByHash",
-			fn: func() {
-				_, _ = ln.GetClient().HeaderByHash(ctx, []byte{})
-			},
-		},
-		{
-			name: "Health",
-			fn: func() {
-				_, _ = ln.GetClient().Health(ctx)
-			},
-		},
 		{
 			name: "NetInfo",
 			fn: func() {
@@ -217,3 +197,57 @@ func TestLightClient_Panics(t *testing.T) {
 		})
 	}
 }
+
+func TestLightClient_Headers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ln := initializeAndStartLightNode(ctx, t)
+	defer cleanUpNode(ln, t)
+	client := ln.GetClient()
+
+	// header store is empty before sync
+	_, err := client.Header(ctx, nil)
+	assert.Error(t, err)
+
+	sh, _, err := types.GetRandomSignedHeader()
+	require.NoError(t, err)
+	require.NoError(t, ln.hSyncService.HeaderStore().Init(ctx, sh))
+	height := int64(sh.Height())
+
+	latest, err := client.Header(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, height, latest.Header.Height)
+
+	byHeight, err := client.Header(ctx, &height)
+	require.NoError(t, err)
+	assert.Equal(t, latest, byHeight)
+
+	byHash, err := client.HeaderByHash(ctx, cmbytes.HexBytes(sh.Hash()))
+	require.NoError(t, err)
+	assert.Equal(t, latest, byHash)
+
+	commit, err := client.Commit(ctx, &height)
+	require.NoError(t, err)
+	assert.True(t, commit.CanonicalCommit)
+	assert.Equal(t, height, commit.Commit.Height)
+	assert.EqualValues(t, sh.Hash(), commit.Commit.BlockID.Hash)
+
+	invalid := int64(-1)
+	_, err = client.Header(ctx, &invalid)
+	assert.Error(t, err)
+}
+
+func TestLightClient_Health(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ln := initializeAndStartLightNode(ctx, t)
+	defer cleanUpNode(ln, t)
+	client := ln.GetClient()
+
+	_, err := client.Health(ctx)
+	assert.NoError(t, err)
+
+	ln.fraudProof.Store(&types.StateFraudProof{BlockHeight: 10})
+	_, err = client.Health(ctx)
+	assert.ErrorContains(t, err, "height 10")
+}
@@ -0,0 +1,192 @@
+package node
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cometbft/cometbft/libs/log"
+
+	"github.com/rollkit/rollkit/config"
+)
+
+const (
+	levelDebug int32 = iota
+	levelInfo
+	levelError
+	levelNone
+)
+
+var logLevels = map[string]int32{
+	"debug": levelDebug,
+	"info":  levelInfo,
+	"error": levelError,
+	"none":  levelNone,
+}
+
+// logModules maps values of "module" key of loggers to modules, which log levels can be changed separately.
+// Other values of "module" key are modules on their own.
+var logModules = map[string]string{
+	"BlockManager":      "block",
+	"HeaderSyncService": "block",
+	"BlockSyncService":  "block",
+	"da_client":         "da",
+	"txindex":           "store",
+}
+
+// moduleLevels keeps the default log level, and log levels of modules overriding it.
+type moduleLevels struct {
+	def     atomic.Int32
+	mtx     sync.RWMutex
+	modules map[string]int32
+}
+
+func (ml *moduleLevels) get(module string) int32 {
+	if module != "" {
+		ml.mtx.RLock()
+		lvl, ok := ml.modules[module]
+		ml.mtx.RUnlock()
+		if ok {
+			return lvl
+		}
+	}
+	return ml.def.Load()
+}
+
+// levelLogger filters messages of the underlying logger by log level, which can be changed at runtime,
+// for all modules or for a single module. Loggers created with With share the log levels of their parent,
+// and belong to the module set with "module" key (or the module of their parent).
+type levelLogger struct {
+	next   log.Logger
+	levels *moduleLevels
+	module string
+}
+
+var _ log.Logger = &levelLogger{}
+
+func newLevelLogger(next log.Logger) *levelLogger {
+	return &levelLogger{next: next, levels: &moduleLevels{modules: make(map[string]int32)}}
+}
+
+// newNodeLogger creates the logger of the node with format and log levels from the configuration.
+// JSON format replaces the given logger with a logger writing JSON lines to standard output.
+func newNodeLogger(conf config.NodeConfig, logger log.Logger) (*levelLogger, error) {
+	if conf.LogFormat == config.LogFormatJSON {
+		logger = log.NewTMJSONLogger(log.NewSyncWriter(os.Stdout))
+	}
+	l := newLevelLogger(logger)
+	if conf.LogLevel != "" {
+		if err := l.SetLevels(conf.LogLevel); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// SetLevel changes the log level of all modules to one of "debug", "info", "error" or "none".
+// Messages are filtered on top of the filtering done by the underlying logger.
+func (l *levelLogger) SetLevel(level string) error {
+	return l.SetModuleLevel("", level)
+}
+
+// SetModuleLevel changes the log level of the module (e.g. block, da, p2p, rpc or store). Empty module or "*"
+// changes the default level and resets levels of all modules.
+func (l *levelLogger) SetModuleLevel(module, level string) error {
+	lvl, ok := logLevels[strings.ToLower(level)]
+	if !ok {
+		return fmt.Errorf("invalid log level %q, expected one of: debug, info, error, none", level)
+	}
+	l.levels.mtx.Lock()
+	defer l.levels.mtx.Unlock()
+	if module == "" || module == "*" {
+		l.levels.def.Store(lvl)
+		l.levels.modules = make(map[string]int32)
+		return nil
+	}
+	l.levels.modules[moduleName(module)] = lvl
+	return nil
+}
+
+// SetLevels changes log levels as specified in CometBFT format: comma separated list of "<module>:<level>" pairs,
+// with "*" module meaning the default level, e.g. "p2p:error,block:debug,*:info". Single level sets
+// the default level.
+func (l *levelLogger) SetLevels(spec string) error {
+	if !strings.Contains(spec, ":") {
+		return l.SetLevel(spec)
+	}
+	pairs := strings.Split(spec, ",")
+	// default level goes first, as it resets levels of modules
+	for _, pair := range pairs {
+		if module, level, _ := strings.Cut(strings.TrimSpace(pair), ":"); module == "*" {
+			if err := l.SetLevel(level); err != nil {
+				return err
+			}
+		}
+	}
+	for _, pair := range pairs {
+		module, level, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || module == "" {
+			return fmt.Errorf("invalid log level %q, expected <module>:<level>", pair)
+		}
+		if module == "*" {
+			continue
+		}
+		if err := l.SetModuleLevel(module, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Levels returns the default log level (with "*" key) and log levels of modules.
+func (l *levelLogger) Levels() map[string]string {
+	names := make(map[int32]string, len(logLevels))
+	for name, lvl := range logLevels {
+		names[lvl] = name
+	}
+	l.levels.mtx.RLock()
+	defer l.levels.mtx.RUnlock()
+	levels := map[string]string{"*": names[l.levels.def.Load()]}
+	for module, lvl := range l.levels.modules {
+		levels[module] = names[lvl]
+	}
+	return levels
+}
+
+func (l *levelLogger) Debug(msg string, keyvals ...interface{}) {
+	if l.levels.get(l.module) <= levelDebug {
+		l.next.Debug(msg, keyvals...)
+	}
+}
+
+func (l *levelLogger) Info(msg string, keyvals ...interface{}) {
+	if l.levels.get(l.module) <= levelInfo {
+		l.next.Info(msg, keyvals...)
+	}
+}
+
+func (l *levelLogger) Error(msg string, keyvals ...interface{}) {
+	if l.levels.get(l.module) <= levelError {
+		l.next.Error(msg, keyvals...)
+	}
+}
+
+func (l *levelLogger) With(keyvals ...interface{}) log.Logger {
+	module := l.module
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if key, ok := keyvals[i].(string); ok && key == "module" {
+			module = moduleName(fmt.Sprint(keyvals[i+1]))
+		}
+	}
+	return &levelLogger{next: l.next.With(keyvals...), levels: l.levels, module: module}
+}
+
+// moduleName returns the module of the value of "module" key.
+func moduleName(value string) string {
+	if module, ok := logModules[value]; ok {
+		return module
+	}
+	return strings.ToLower(value)
+}
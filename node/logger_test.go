@@ -0,0 +1,44 @@
+package node
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLevelLogger(log.NewTMLogger(log.NewSyncWriter(&buf)))
+	blockLogger := logger.With("module", "BlockManager")
+	p2pLogger := logger.With("module", "p2p")
+	logged := func(l log.Logger, msg string) bool {
+		buf.Reset()
+		l.Debug(msg)
+		return buf.Len() > 0
+	}
+
+	assert.True(t, logged(blockLogger, "block"))
+	assert.True(t, logged(p2pLogger, "p2p"))
+
+	require.NoError(t, logger.SetLevels("block:debug,*:info"))
+	assert.True(t, logged(blockLogger, "block"))
+	assert.False(t, logged(p2pLogger, "p2p"))
+	assert.False(t, logged(logger, "node"))
+	assert.Equal(t, map[string]string{"*": "info", "block": "debug"}, logger.Levels())
+
+	require.NoError(t, logger.SetModuleLevel("p2p", "debug"))
+	assert.True(t, logged(p2pLogger, "p2p"))
+	assert.True(t, logged(p2pLogger.With("peer", "a"), "p2p"))
+
+	// changing the default level resets levels of modules
+	require.NoError(t, logger.SetLevel("error"))
+	assert.False(t, logged(blockLogger, "block"))
+	assert.False(t, logged(p2pLogger, "p2p"))
+
+	assert.Error(t, logger.SetLevel("verbose"))
+	assert.Error(t, logger.SetLevels("p2p"))
+	assert.Error(t, logger.SetLevels("p2p:debug,:info"))
+}
@@ -0,0 +1,120 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	llcfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/tracing"
+)
+
+// nodeMetrics contains metrics of all node components.
+type nodeMetrics struct {
+	block   *block.Metrics
+	state   *state.Metrics
+	mempool *mempool.Metrics
+	p2p     *p2p.Metrics
+	proxy   *proxy.Metrics
+}
+
+// newNodeMetrics returns Prometheus metrics labeled with chain ID and node type if
+// Prometheus is enabled in conf, and no-op metrics otherwise.
+func newNodeMetrics(conf *llcfg.InstrumentationConfig, chainID string, nodeType string) *nodeMetrics {
+	if conf == nil || !conf.Prometheus {
+		return &nodeMetrics{
+			block:   block.NopMetrics(),
+			state:   state.NopMetrics(),
+			mempool: mempool.NopMetrics(),
+			p2p:     p2p.NopMetrics(),
+			proxy:   proxy.NopMetrics(),
+		}
+	}
+	labels := metricsLabels(chainID, nodeType)
+	return &nodeMetrics{
+		block:   block.PrometheusMetrics(conf.Namespace, labels...),
+		state:   state.PrometheusMetrics(conf.Namespace, labels...),
+		mempool: mempool.PrometheusMetrics(conf.Namespace, labels...),
+		p2p:     p2p.PrometheusMetrics(conf.Namespace, labels...),
+		proxy:   proxy.PrometheusMetrics(conf.Namespace, labels...),
+	}
+}
+
+func metricsLabels(chainID string, nodeType string) []string {
+	return []string{"chain_id", chainID, "node_type", nodeType}
+}
+
+// metricsConfig returns Prometheus namespace and labels used by node metrics, or ok == false
+// if Prometheus is disabled.
+func metricsConfig(conf *llcfg.InstrumentationConfig, chainID string, nodeType string) (namespace string, labelsAndValues []string, ok bool) {
+	if conf == nil || !conf.Prometheus {
+		return "", nil, false
+	}
+	return conf.Namespace, metricsLabels(chainID, nodeType), true
+}
+
+// startPrometheusServer starts HTTP server exposing metrics from the default
+// Prometheus registry on /metrics endpoint. It returns nil if Prometheus is
+// disabled or the listen address is not set.
+func startPrometheusServer(conf *llcfg.InstrumentationConfig, logger log.Logger) *http.Server {
+	if conf == nil || !conf.Prometheus || conf.PrometheusListenAddr == "" {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer, promhttp.HandlerFor(
+			prometheus.DefaultGatherer,
+			promhttp.HandlerOpts{MaxRequestsInFlight: conf.MaxOpenConnections},
+		),
+	))
+	srv := &http.Server{
+		Addr:              conf.PrometheusListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Info("serving Prometheus metrics", "listen address", conf.PrometheusListenAddr)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Prometheus HTTP server ListenAndServe", "err", err)
+		}
+	}()
+	return srv
+}
+
+// tracingConfig returns configuration of the OTLP exporter of traces.
+func tracingConfig(conf config.NodeConfig) tracing.Config {
+	sampleRate := conf.TracingSampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+	return tracing.Config{
+		Endpoint:   conf.TracingEndpoint,
+		Insecure:   conf.TracingInsecure,
+		SampleRate: sampleRate,
+	}
+}
+
+// startTracing registers a global tracer provider exporting traces over OTLP, labeled with chain ID and node type.
+// It returns nil if the OTLP endpoint is not set.
+func startTracing(ctx context.Context, conf tracing.Config, chainID string, nodeType string, logger log.Logger) (*sdktrace.TracerProvider, error) {
+	provider, err := tracing.NewOTLPProvider(ctx, conf, chainID, nodeType)
+	if err != nil || provider == nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(provider)
+	logger.Info("exporting traces over OTLP", "endpoint", conf.Endpoint, "sample rate", conf.SampleRate)
+	return provider, nil
+}
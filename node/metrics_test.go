@@ -0,0 +1,55 @@
+package node
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	llcfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusServer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	assert.Nil(startPrometheusServer(nil, log.TestingLogger()))
+	assert.Nil(startPrometheusServer(&llcfg.InstrumentationConfig{Prometheus: false, PrometheusListenAddr: ":0"}, log.TestingLogger()))
+
+	// find a free port for the server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	addr := listener.Addr().String()
+	require.NoError(listener.Close())
+
+	conf := &llcfg.InstrumentationConfig{Prometheus: true, PrometheusListenAddr: addr, Namespace: "rollkit_test"}
+	metrics := newNodeMetrics(conf, "test-chain", "full")
+	metrics.block.Height.Set(42)
+
+	srv := startPrometheusServer(conf, log.TestingLogger())
+	require.NotNil(srv)
+	defer func() { _ = srv.Close() }()
+
+	var body []byte
+	require.Eventually(func() bool {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		body, err = io.ReadAll(resp.Body)
+		return err == nil && resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(string(body), `rollkit_test_block_height{chain_id="test-chain",node_type="full"} 42`)
+
+	namespace, labels, ok := metricsConfig(conf, "test-chain", "light")
+	assert.True(ok)
+	assert.Equal("rollkit_test", namespace)
+	assert.Equal([]string{"chain_id", "test-chain", "node_type", "light"}, labels)
+	_, _, ok = metricsConfig(nil, "test-chain", "light")
+	assert.False(ok)
+}
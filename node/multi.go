@@ -0,0 +1,185 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	proxy "github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/p2p"
+	"github.com/rollkit/rollkit/store"
+)
+
+// sharedComponents are components shared by full nodes of multiple rollups running in one process.
+type sharedComponents struct {
+	// dalc is the DA layer client, scoped to the namespace of every rollup
+	dalc da.SharedClient
+	// p2p is the P2P client owning the libp2p host, nil until the first node is created
+	p2p *p2p.Client
+}
+
+// RollupConfig configures a single rollup run by MultiNode.
+type RollupConfig struct {
+	// Config of the rollup node. Every rollup needs its own store (RootDir or DBPath), namespace and RPC address.
+	// P2P and DA layer configuration of the first rollup is used by all rollups.
+	Config        config.NodeConfig
+	SigningKey    crypto.PrivKey
+	ClientCreator proxy.ClientCreator
+	Genesis       *cmtypes.GenesisDoc
+}
+
+// MultiNode runs full nodes of multiple independent rollups in one process, e.g. for operators hosting many small
+// rollups. Every rollup has its own store, block manager, namespace and application connection. The rollups share
+// the DA layer client and the P2P host.
+type MultiNode struct {
+	service.BaseService
+
+	dalc  da.DataAvailabilityLayerClient
+	nodes []*FullNode
+}
+
+// NewMultiNode creates full nodes of the rollups. Nodes are started in order of rollups and stopped in reverse order.
+func NewMultiNode(ctx context.Context, p2pKey crypto.PrivKey, rollups []RollupConfig, logger log.Logger) (*MultiNode, error) {
+	if err := validateRollups(rollups); err != nil {
+		return nil, err
+	}
+
+	// DA layer client is not bound to store of any rollup
+	dalcKV, err := store.NewDefaultInMemoryKVStore()
+	if err != nil {
+		return nil, err
+	}
+	dalc, err := initDALC(rollups[0].Config, dalcKV, logger)
+	if err != nil {
+		return nil, err
+	}
+	sharedDALC, ok := dalc.(da.SharedClient)
+	if !ok {
+		return nil, fmt.Errorf("data availability layer client '%s' can't be shared by multiple rollups", rollups[0].Config.DALayer)
+	}
+
+	shared := &sharedComponents{dalc: sharedDALC}
+	nodes := make([]*FullNode, len(rollups))
+	for i, r := range rollups {
+		r.Config.P2P = rollups[0].Config.P2P
+		nodes[i], err = newSharedFullNode(ctx, r.Config, p2pKey, r.SigningKey, r.ClientCreator, r.Genesis, shared, logger.With("chain_id", r.Genesis.ChainID))
+		if err != nil {
+			return nil, fmt.Errorf("error while creating node of rollup %s: %w", r.Genesis.ChainID, err)
+		}
+		if shared.p2p == nil {
+			shared.p2p = nodes[i].p2pClient
+		}
+	}
+
+	n := &MultiNode{
+		dalc:  dalc,
+		nodes: nodes,
+	}
+	n.BaseService = *service.NewBaseService(logger, "MultiNode", n)
+	return n, nil
+}
+
+func validateRollups(rollups []RollupConfig) error {
+	if len(rollups) == 0 {
+		return errors.New("no rollups configured")
+	}
+	chainIDs := make(map[string]bool)
+	namespaces := make(map[string]bool)
+	stores := make(map[string]bool)
+	prometheus := false
+	for _, r := range rollups {
+		if r.Config.Light {
+			return fmt.Errorf("rollup %s: light nodes are not supported", r.Genesis.ChainID)
+		}
+		if chainIDs[r.Genesis.ChainID] {
+			return fmt.Errorf("duplicate rollup %s", r.Genesis.ChainID)
+		}
+		chainIDs[r.Genesis.ChainID] = true
+		// namespace can be defined in genesis instead of node configuration
+		conf := r.Config
+		if _, err := applyGenesisDAParams(&conf, r.Genesis); err != nil {
+			return fmt.Errorf("rollup %s: %w", r.Genesis.ChainID, err)
+		}
+		namespace := string(conf.NamespaceID[:])
+		if namespaces[namespace] {
+			return fmt.Errorf("rollup %s: namespace %X used by another rollup", r.Genesis.ChainID, conf.NamespaceID)
+		}
+		namespaces[namespace] = true
+		// empty RootDir and DBPath mean in-memory store
+		if path := r.Config.RootDir + "/" + r.Config.DBPath; path != "/" {
+			if stores[path] {
+				return fmt.Errorf("rollup %s: store %s used by another rollup", r.Genesis.ChainID, path)
+			}
+			stores[path] = true
+		}
+		if r.Config.Instrumentation != nil && r.Config.Instrumentation.Prometheus {
+			if prometheus {
+				return fmt.Errorf("rollup %s: Prometheus metrics can be enabled for a single rollup only", r.Genesis.ChainID)
+			}
+			prometheus = true
+		}
+	}
+	return nil
+}
+
+// Nodes returns full nodes of the rollups, in order of configuration.
+func (n *MultiNode) Nodes() []*FullNode {
+	return n.nodes
+}
+
+// DAAccounts returns accounts of submissions of rollups to DA layer (by chain ID), if the DA layer client combines
+// submissions of multiple rollups into single DA transactions (e.g. "aggregation_window" of Celestia client).
+// It returns nil, if submissions are not aggregated.
+func (n *MultiNode) DAAccounts() map[string]da.AggregationAccount {
+	accounter, ok := n.dalc.(da.AggregationAccounter)
+	if !ok {
+		return nil
+	}
+	accounts := accounter.AggregationAccounts()
+	if accounts == nil {
+		return nil
+	}
+	byChainID := make(map[string]da.AggregationAccount, len(n.nodes))
+	for _, node := range n.nodes {
+		byChainID[node.genesis.ChainID] = accounts[node.nodeConfig.NamespaceID]
+	}
+	return byChainID
+}
+
+// OnStart starts the shared DA layer client and nodes of all rollups.
+func (n *MultiNode) OnStart() error {
+	if err := n.dalc.Start(); err != nil {
+		return fmt.Errorf("error while starting data availability layer client: %w", err)
+	}
+	for i, node := range n.nodes {
+		if err := node.Start(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				_ = n.nodes[j].Stop()
+			}
+			return fmt.Errorf("error while starting node of rollup %s: %w", node.genesis.ChainID, err)
+		}
+	}
+	return nil
+}
+
+// OnStop stops nodes of all rollups and the shared DA layer client.
+func (n *MultiNode) OnStop() {
+	var err error
+	for i := len(n.nodes) - 1; i >= 0; i-- {
+		if n.nodes[i].IsRunning() {
+			err = multierr.Append(err, n.nodes[i].Stop())
+		}
+	}
+	err = multierr.Append(err, n.dalc.Stop())
+	if err != nil {
+		n.Logger.Error("errors while stopping multi-rollup node:", "errors", err)
+	}
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/config"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+func getRollupConfig(chainID string, namespaceID types.NamespaceID) RollupConfig {
+	conf := config.NodeConfig{DALayer: "newda"}
+	conf.NamespaceID = namespaceID
+	return RollupConfig{
+		Config:        conf,
+		SigningKey:    generateSingleKey(),
+		ClientCreator: proxy.NewLocalClientCreator(setupMockApplication()),
+		Genesis:       &cmtypes.GenesisDoc{ChainID: chainID},
+	}
+}
+
+func TestMultiNode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	logger := test.NewFileLogger(t)
+
+	_, err := NewMultiNode(ctx, generateSingleKey(), nil, logger)
+	assert.Error(err)
+	_, err = NewMultiNode(ctx, generateSingleKey(), []RollupConfig{
+		getRollupConfig("rollup-1", types.NamespaceID{1}),
+		getRollupConfig("rollup-2", types.NamespaceID{1}),
+	}, logger)
+	assert.Error(err)
+	_, err = NewMultiNode(ctx, generateSingleKey(), []RollupConfig{
+		getRollupConfig("rollup-1", types.NamespaceID{1}),
+		getRollupConfig("rollup-1", types.NamespaceID{2}),
+	}, logger)
+	assert.Error(err)
+
+	n, err := NewMultiNode(ctx, generateSingleKey(), []RollupConfig{
+		getRollupConfig("rollup-1", types.NamespaceID{1}),
+		getRollupConfig("rollup-2", types.NamespaceID{2}),
+	}, logger)
+	require.NoError(err)
+	require.Len(n.Nodes(), 2)
+	first, second := n.Nodes()[0], n.Nodes()[1]
+	assert.Equal("rollup-2", second.GetGenesis().ChainID)
+	assert.NotEqual(first.Store, second.Store)
+	// mock DA layer client doesn't aggregate submissions
+	assert.Nil(n.DAAccounts())
+
+	require.NoError(n.Start())
+	assert.True(first.IsRunning())
+	assert.True(second.IsRunning())
+	// rollups share the P2P host
+	assert.Equal(first.p2pClient.Host().ID(), second.p2pClient.Host().ID())
+
+	require.NoError(n.Stop())
+	assert.False(first.IsRunning())
+	assert.False(second.IsRunning())
+}
@@ -2,6 +2,9 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/libp2p/go-libp2p/core/crypto"
 
@@ -11,6 +14,7 @@ import (
 	cmtypes "github.com/cometbft/cometbft/types"
 
 	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/keyring"
 )
 
 // Node is the interface for a rollup node
@@ -32,6 +36,10 @@ func NewNode(
 	genesis *cmtypes.GenesisDoc,
 	logger log.Logger,
 ) (Node, error) {
+	p2pKey, signingKey, err := loadKeys(conf, p2pKey, signingKey, logger)
+	if err != nil {
+		return nil, err
+	}
 	if !conf.Light {
 		return newFullNode(
 			ctx,
@@ -53,3 +61,31 @@ func NewNode(
 		)
 	}
 }
+
+// loadKeys returns the node and proposer keys from the keyring, if it's configured, and given keys otherwise.
+// The proposer key is loaded only by aggregators.
+func loadKeys(conf config.NodeConfig, p2pKey, signingKey crypto.PrivKey, logger log.Logger) (crypto.PrivKey, crypto.PrivKey, error) {
+	if conf.KeyringBackend == "" {
+		return p2pKey, signingKey, nil
+	}
+	dir := conf.KeyringDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(conf.RootDir, dir)
+	}
+	kr, err := keyring.New(conf.KeyringBackend, dir, os.Getenv(keyring.PassphraseEnv))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open keyring: %w", err)
+	}
+	p2pKey, err = keyring.LoadOrCreate(kr, keyring.NodeKeyName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load node key: %w", err)
+	}
+	if conf.Aggregator {
+		signingKey, err = keyring.LoadOrCreate(kr, keyring.ProposerKeyName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load proposer key: %w", err)
+		}
+	}
+	logger.Info("loaded keys from keyring", "backend", conf.KeyringBackend, "dir", dir)
+	return p2pKey, signingKey, nil
+}
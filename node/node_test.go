@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"testing"
 
+	abci "github.com/cometbft/cometbft/abci/types"
 	proxy "github.com/cometbft/cometbft/proxy"
 	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/rollkit/rollkit/config"
@@ -76,3 +78,21 @@ func TestNewNode(t *testing.T) {
 	fn := initializeAndStartFullNode(ctx, t)
 	cleanUpNode(fn, t)
 }
+
+func TestReady(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	app := setupMockApplication()
+	app.On("Info", mock.Anything).Return(abci.ResponseInfo{})
+	key, signingKey := generateSingleKey(), generateSingleKey()
+	node, err := newFullNode(ctx, config.NodeConfig{DALayer: "newda"}, key, signingKey, proxy.NewLocalClientCreator(app), &cmtypes.GenesisDoc{ChainID: types.TestChainID}, test.NewFileLogger(t))
+	require.NoError(err)
+	require.NotNil(node)
+
+	assert.EqualError(node.Ready(ctx), "node is not running")
+	require.NoError(node.Start())
+	defer cleanUpNode(node, t)
+	assert.NoError(node.Ready(ctx))
+}
@@ -0,0 +1,290 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/rollkit/rollkit/clock"
+	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/supervisor"
+)
+
+// Names of services of full node, managed by its supervisor. Services added by applications embedding the node
+// can depend on them.
+const (
+	ServiceP2P        = "p2p"
+	ServiceHeaderSync = "header_sync"
+	ServiceBlockSync  = "block_sync"
+	ServiceDA         = "da"
+	ServiceSettlement = "settlement"
+	// ServiceSnapshotRestore restores application state from a snapshot published to DA layer, before blocks
+	// are synced. It's added only if the node is configured to restore a snapshot.
+	ServiceSnapshotRestore = "snapshot_restore"
+	// ServiceStateImport imports application and node state from a file with exported state, before blocks are
+	// synced. It's added only if the node is configured to import state.
+	ServiceStateImport = "state_import"
+	// ServiceAppWarmUp brings the application up to date with the state of the node (replaying blocks missing in
+	// the application) before blocks are produced or synced.
+	ServiceAppWarmUp = "app_warmup"
+	// ServiceEventSinks forwards events of applied blocks to external sinks. It's added only if sinks are
+	// configured.
+	ServiceEventSinks = "event_sinks"
+)
+
+// restartOnFailure is the restart policy of loops that don't modify the state of the node, and can be safely
+// restarted after a failure.
+var restartOnFailure = supervisor.RestartPolicy{MaxRestarts: 5, Backoff: time.Second}
+
+// loop adapts a loop function running until context is canceled to Run function of a service.
+func loop(fn func(ctx context.Context)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}
+}
+
+// addServices adds components and loops of the node to its supervisor. It's called when the node is started,
+// after all components were set up.
+func (n *FullNode) addServices() error {
+	blockDeps := []string{ServiceDA, ServiceHeaderSync, ServiceBlockSync, ServiceAppWarmUp}
+	retrieveDeps := []string{ServiceDA}
+	storeRetrieveDeps := []string{ServiceBlockSync}
+	var warmUpDeps []string
+	restore := ""
+	if n.nodeConfig.SnapshotDAHeight > 0 {
+		restore = ServiceSnapshotRestore
+	} else if n.nodeConfig.StateImportFile != "" {
+		restore = ServiceStateImport
+	}
+	if restore != "" {
+		// blocks are synced after application state is restored from the snapshot
+		blockDeps = append(blockDeps, restore)
+		retrieveDeps = append(retrieveDeps, restore)
+		storeRetrieveDeps = append(storeRetrieveDeps, restore)
+		warmUpDeps = append(warmUpDeps, restore)
+	}
+	if n.eventPublisher != nil {
+		// publisher subscribes to events before blocks are applied, so no block is missed
+		blockDeps = append(blockDeps, ServiceEventSinks)
+	}
+	services := []supervisor.Service{
+		{
+			Name:  ServiceP2P,
+			Start: n.p2pClient.Start,
+			Stop:  n.p2pClient.Close,
+		},
+		{
+			Name:      ServiceHeaderSync,
+			DependsOn: []string{ServiceP2P},
+			Start:     func(context.Context) error { return n.hSyncService.Start() },
+			Stop:      n.hSyncService.Stop,
+		},
+		{
+			Name:      ServiceBlockSync,
+			DependsOn: []string{ServiceP2P},
+			Start:     func(context.Context) error { return n.bSyncService.Start() },
+			Stop:      n.bSyncService.Stop,
+		},
+	}
+
+	daService := supervisor.Service{Name: ServiceDA, Start: n.checkDAChainID}
+	if !n.sharedDALC {
+		daService.Start = func(ctx context.Context) error {
+			if err := n.dalc.Start(); err != nil {
+				return err
+			}
+			return n.checkDAChainID(ctx)
+		}
+		daService.Stop = n.dalc.Stop
+	}
+	services = append(services, daService)
+	if n.nodeConfig.SnapshotDAHeight > 0 {
+		services = append(services, supervisor.Service{
+			Name:      ServiceSnapshotRestore,
+			DependsOn: []string{ServiceDA},
+			Start:     n.blockManager.RestoreSnapshot,
+		})
+	}
+	if n.nodeConfig.StateImportFile != "" {
+		services = append(services, supervisor.Service{
+			Name:  ServiceStateImport,
+			Start: n.importState,
+		})
+	}
+
+	services = append(services, supervisor.Service{
+		Name:      ServiceAppWarmUp,
+		DependsOn: warmUpDeps,
+		Start: func(ctx context.Context) error {
+			return n.blockManager.WarmUp(ctx, n.proxyApp.Query())
+		},
+	})
+
+	if n.eventPublisher != nil {
+		services = append(services, supervisor.Service{
+			Name:  ServiceEventSinks,
+			Start: n.eventPublisher.Start,
+			Run:   loop(n.eventPublisher.Run),
+			Stop:  n.eventPublisher.Stop,
+		})
+	}
+
+	if n.settlement != nil {
+		services = append(services,
+			supervisor.Service{
+				Name:  ServiceSettlement,
+				Start: func(context.Context) error { return n.settlement.Start() },
+				Stop:  n.settlement.Stop,
+			},
+			supervisor.Service{
+				Name:      "settlement_loop",
+				DependsOn: append([]string{ServiceSettlement}, blockDeps...),
+				Run:       loop(n.blockManager.SettlementLoop),
+			},
+		)
+	}
+
+	if n.nodeConfig.Aggregator {
+		services = append(services,
+			supervisor.Service{
+				Name:      "aggregation",
+				DependsOn: blockDeps,
+				Run: loop(func(ctx context.Context) {
+					n.blockManager.AggregationLoop(ctx, n.nodeConfig.LazyAggregator)
+				}),
+			},
+			supervisor.Service{
+				Name:      "block_submission",
+				DependsOn: []string{ServiceDA},
+				Run:       loop(n.blockManager.BlockSubmissionLoop),
+				Restart:   restartOnFailure,
+			},
+			supervisor.Service{
+				Name:      "header_publish",
+				DependsOn: []string{ServiceHeaderSync},
+				Run:       loop(n.headerPublishLoop),
+				Restart:   restartOnFailure,
+			},
+			supervisor.Service{
+				Name:      "block_publish",
+				DependsOn: []string{ServiceBlockSync},
+				Run:       loop(n.blockPublishLoop),
+				Restart:   restartOnFailure,
+			},
+		)
+		if n.nodeConfig.SnapshotInterval > 0 {
+			services = append(services, supervisor.Service{
+				Name:      "snapshot_publish",
+				DependsOn: []string{ServiceDA},
+				Run:       loop(n.blockManager.SnapshotPublishLoop),
+				Restart:   restartOnFailure,
+			})
+		}
+	} else {
+		services = append(services, supervisor.Service{
+			Name:      "gossiped_blocks",
+			DependsOn: blockDeps,
+			Run: loop(func(ctx context.Context) {
+				n.blockManager.GossipedBlockLoop(ctx, n.bSyncService.GossipedBlocks())
+			}),
+		})
+		if n.nodeConfig.WithholdingWindow > 0 {
+			services = append(services, supervisor.Service{
+				Name:    "withholding_watchdog",
+				Run:     loop(n.blockManager.WithholdingWatchdogLoop),
+				Restart: restartOnFailure,
+			})
+		}
+	}
+
+	services = append(services,
+		supervisor.Service{
+			Name:      "da_retrieve",
+			DependsOn: retrieveDeps,
+			Run:       loop(n.blockManager.RetrieveLoop),
+			Restart:   restartOnFailure,
+		},
+		supervisor.Service{
+			Name:      "sync",
+			DependsOn: blockDeps,
+			Run: loop(func(ctx context.Context) {
+				n.blockManager.SyncLoop(ctx, n.cancel)
+			}),
+		},
+		supervisor.Service{
+			Name:      "async_txs",
+			DependsOn: []string{ServiceP2P},
+			Run:       loop(n.asyncTxLoop),
+			Restart:   restartOnFailure,
+		},
+		supervisor.Service{
+			Name:      "fraud_proof_publish",
+			DependsOn: []string{ServiceP2P},
+			Run:       loop(n.fraudProofPublishLoop),
+		},
+		supervisor.Service{
+			Name:      "evidence_publish",
+			DependsOn: []string{ServiceP2P},
+			Run:       loop(n.evidencePublishLoop),
+			Restart:   restartOnFailure,
+		},
+		supervisor.Service{
+			Name:      "inclusion_list_publish",
+			DependsOn: []string{ServiceP2P},
+			Run:       loop(n.inclusionListPublishLoop),
+			Restart:   restartOnFailure,
+		},
+	)
+
+	if n.headersFirst() {
+		services = append(services, supervisor.Service{
+			Name:      "headers_first_sync",
+			DependsOn: append(storeRetrieveDeps, ServiceHeaderSync),
+			Run:       loop(n.headersFirstSyncLoop),
+			Restart:   restartOnFailure,
+		})
+	} else {
+		services = append(services, supervisor.Service{
+			Name:      "block_store_retrieve",
+			DependsOn: storeRetrieveDeps,
+			Run:       loop(n.blockManager.BlockStoreRetrieveLoop),
+			Restart:   restartOnFailure,
+		})
+	}
+
+	if n.isPruned() {
+		services = append(services, supervisor.Service{
+			Name:    "prune",
+			Run:     loop(n.pruneLoop),
+			Restart: restartOnFailure,
+		})
+	}
+
+	if n.nodeConfig.NTPServer != "" {
+		monitor := clock.NewDriftMonitor(n.nodeConfig.NTPServer, n.nodeConfig.MaxClockDrift, clock.Real, n.Logger.With("module", "clock"))
+		services = append(services, supervisor.Service{
+			Name:    "clock_drift",
+			Run:     loop(monitor.Run),
+			Restart: restartOnFailure,
+		})
+	}
+
+	switch mempool := n.Mempool.(type) {
+	case *mempoolv1.Batcher:
+		services = append(services,
+			supervisor.Service{Name: "mempool_batcher", Run: loop(mempool.Run)},
+			supervisor.Service{Name: "mempool_expiration", Run: loop(mempool.ExpirationLoop), Restart: restartOnFailure},
+		)
+	case *mempoolv1.TxMempool:
+		services = append(services,
+			supervisor.Service{Name: "mempool_expiration", Run: loop(mempool.ExpirationLoop), Restart: restartOnFailure},
+		)
+	}
+
+	for _, svc := range services {
+		if err := n.supervisor.Add(svc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
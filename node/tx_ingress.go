@@ -0,0 +1,107 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/mempool"
+)
+
+// asyncTxQueueSize is the number of transactions broadcast with broadcast_tx_async, waiting for CheckTx.
+const asyncTxQueueSize = 1000
+
+// ErrTxQueueFull is returned by BroadcastTxAsync, when too many transactions are waiting for CheckTx.
+var ErrTxQueueFull = errors.New("queue of async transactions is full")
+
+type remoteAddrKey struct{}
+
+// ContextWithRemoteAddr returns a copy of ctx carrying the address of the remote client (e.g. of RPC request).
+// Transactions broadcast with such context are subject to the rate limit per IP address.
+func ContextWithRemoteAddr(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, remoteAddrKey{}, addr)
+}
+
+// txIngress protects the mempool from transactions broadcast via RPC: it limits their size, and their rate
+// per IP address of the client. Rate per sender and minimal fee are enforced by the mempool.
+type txIngress struct {
+	maxTxBytes int
+	ipLimiter  *mempool.RateLimiter
+	metrics    *mempool.Metrics
+}
+
+func newTxIngress(conf config.NodeConfig, metrics *mempool.Metrics) *txIngress {
+	t := &txIngress{maxTxBytes: conf.TxMaxBytes, metrics: metrics}
+	if conf.TxRatePerIP > 0 {
+		t.ipLimiter = mempool.NewRateLimiter(conf.TxRatePerIP, conf.TxRateBurst)
+	}
+	return t
+}
+
+// check returns an error if the transaction exceeds the size limit, or the client exceeded the rate limit.
+// Transactions of local clients (without remote address in ctx) are not rate limited.
+func (t *txIngress) check(ctx context.Context, tx cmtypes.Tx) error {
+	if t.maxTxBytes > 0 && len(tx) > t.maxTxBytes {
+		t.metrics.ThrottledTxs.With("reason", mempool.ThrottledTxSize).Add(1)
+		return mempool.ErrTxTooLarge{Max: t.maxTxBytes, Actual: len(tx)}
+	}
+	addr, _ := ctx.Value(remoteAddrKey{}).(string)
+	if t.ipLimiter == nil || addr == "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if !t.ipLimiter.Allow(host) {
+		t.metrics.ThrottledTxs.With("reason", mempool.ThrottledIPRate).Add(1)
+		return fmt.Errorf("%w: IP address %s exceeded the rate limit", mempool.ErrTxThrottled, host)
+	}
+	return nil
+}
+
+// asyncTx is a transaction broadcast with BroadcastTxAsync, waiting for CheckTx. ctx is the context of the
+// broadcast (without cancellation), carrying its tracing span and the address of the client.
+type asyncTx struct {
+	ctx context.Context
+	tx  cmtypes.Tx
+}
+
+// asyncTxLoop checks transactions broadcast with BroadcastTxAsync in order of arrival, and relays those accepted
+// by the mempool. As nobody waits for the result, failures are only logged.
+func (n *FullNode) asyncTxLoop(ctx context.Context) {
+	c := NewFullClient(n)
+	for {
+		select {
+		case atx := <-n.asyncTxs:
+			txCtx, cancel := context.WithCancel(atx.ctx)
+			stop := context.AfterFunc(ctx, cancel)
+			if err := c.checkAsyncTx(txCtx, atx.tx); err != nil {
+				n.Logger.Debug("async transaction not added to mempool", "hash", atx.tx.Hash(), "error", err)
+			}
+			stop()
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *FullClient) checkAsyncTx(ctx context.Context, tx cmtypes.Tx) error {
+	resCh := make(chan *abci.Response, 1)
+	err := c.checkTx(ctx, tx, func(res *abci.Response) {
+		resCh <- res
+	})
+	if err != nil {
+		return err
+	}
+	if r := (<-resCh).GetCheckTx(); r.Code != abci.CodeTypeOK {
+		return fmt.Errorf("CheckTx failed with code %d: %s", r.Code, r.Log)
+	}
+	return c.relayTx(ctx, tx)
+}
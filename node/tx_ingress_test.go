@@ -0,0 +1,32 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/mempool"
+)
+
+func TestTxIngress(t *testing.T) {
+	assert := assert.New(t)
+
+	ingress := newTxIngress(config.NodeConfig{TxRatePerIP: 0.001, TxRateBurst: 2, TxMaxBytes: 10}, mempool.NopMetrics())
+	tx := cmtypes.Tx("tx")
+	local := context.Background()
+	remote := ContextWithRemoteAddr(local, "10.0.0.1:5000")
+
+	assert.ErrorAs(ingress.check(local, cmtypes.Tx("too large transaction")), &mempool.ErrTxTooLarge{})
+
+	// rate is limited per IP address, regardless of port
+	assert.NoError(ingress.check(remote, tx))
+	assert.NoError(ingress.check(ContextWithRemoteAddr(local, "10.0.0.1:6000"), tx))
+	assert.ErrorIs(ingress.check(remote, tx), mempool.ErrTxThrottled)
+	assert.NoError(ingress.check(ContextWithRemoteAddr(local, "10.0.0.2:5000"), tx))
+
+	// local clients are not limited
+	assert.NoError(ingress.check(local, tx))
+}
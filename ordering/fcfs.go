@@ -0,0 +1,227 @@
+package ordering
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/types"
+)
+
+// ExtensionType is the type of the header extension carrying the FCFS ordering attestation.
+const ExtensionType = "rollkit/fcfs"
+
+// receiptsRetention is the number of the latest blocks, for which Extender keeps receipts.
+const receiptsRetention = 1000
+
+var (
+	// ErrNotFCFS is returned when transactions are not ordered by time of arrival.
+	ErrNotFCFS = errors.New("transactions are not ordered by time of arrival")
+	// ErrInvalidAttestation is returned when receipts don't match the ordering attestation.
+	ErrInvalidAttestation = errors.New("invalid ordering attestation")
+)
+
+// Receipt records time of arrival of the transaction at the aggregator.
+type Receipt struct {
+	TxHash     []byte    `json:"tx_hash"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+func (r *Receipt) leaf() []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, r.TxHash...), uint64(r.ReceivedAt.UnixNano()))
+}
+
+// Attestation commits to receipts of all transactions of the block, in order of transactions. It's created only
+// for blocks ordered by time of arrival (first-come-first-served), so transactions can't be reordered by the
+// aggregator, e.g. to front-run users.
+type Attestation struct {
+	// Root is the Merkle root of receipts.
+	Root []byte
+	// FirstReceived and LastReceived are receive times of the first and the last transaction of the block.
+	FirstReceived time.Time
+	LastReceived  time.Time
+}
+
+// NewAttestation creates attestation of given receipts. ErrNotFCFS is returned if receipts are not ordered
+// by receive time.
+func NewAttestation(receipts []Receipt) (*Attestation, error) {
+	if len(receipts) == 0 {
+		return nil, errors.New("no receipts to attest")
+	}
+	for i := 1; i < len(receipts); i++ {
+		if receipts[i].ReceivedAt.Before(receipts[i-1].ReceivedAt) {
+			return nil, fmt.Errorf("%w: transaction %d received before transaction %d", ErrNotFCFS, i, i-1)
+		}
+	}
+	return &Attestation{
+		Root:          receiptsRoot(receipts),
+		FirstReceived: receipts[0].ReceivedAt,
+		LastReceived:  receipts[len(receipts)-1].ReceivedAt,
+	}, nil
+}
+
+// MarshalBinary encodes the attestation as the header extension data.
+func (a *Attestation) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, len(a.Root)+16)
+	buf = append(buf, a.Root...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.FirstReceived.UnixNano()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.LastReceived.UnixNano()))
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the attestation from the header extension data.
+func (a *Attestation) UnmarshalBinary(data []byte) error {
+	if len(data) != tmhash.Size+16 {
+		return fmt.Errorf("%w: unexpected size %d", ErrInvalidAttestation, len(data))
+	}
+	a.Root = append([]byte{}, data[:tmhash.Size]...)
+	a.FirstReceived = time.Unix(0, int64(binary.BigEndian.Uint64(data[tmhash.Size:]))).UTC()
+	a.LastReceived = time.Unix(0, int64(binary.BigEndian.Uint64(data[tmhash.Size+8:]))).UTC()
+	return nil
+}
+
+// FromHeader returns the ordering attestation committed in the header, or false if the header has none.
+func FromHeader(header *types.Header) (*Attestation, bool, error) {
+	data, ok := header.Extension(ExtensionType)
+	if !ok {
+		return nil, false, nil
+	}
+	a := &Attestation{}
+	if err := a.UnmarshalBinary(data); err != nil {
+		return nil, true, err
+	}
+	return a, true, nil
+}
+
+// Verify checks that receipts (published by the aggregator) match transactions of the block and the attestation,
+// and that transactions are ordered by time of arrival.
+func (a *Attestation) Verify(txs types.Txs, receipts []Receipt) error {
+	if len(txs) != len(receipts) {
+		return fmt.Errorf("%w: %d transactions, %d receipts", ErrInvalidAttestation, len(txs), len(receipts))
+	}
+	for i := range txs {
+		if !bytes.Equal(txs[i].Hash(), receipts[i].TxHash) {
+			return fmt.Errorf("%w: receipt %d doesn't match the transaction", ErrInvalidAttestation, i)
+		}
+	}
+	expected, err := NewAttestation(receipts)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(a.Root, expected.Root) || !a.FirstReceived.Equal(expected.FirstReceived) || !a.LastReceived.Equal(expected.LastReceived) {
+		return fmt.Errorf("%w: receipts don't match the attestation", ErrInvalidAttestation)
+	}
+	return nil
+}
+
+// VerifyReceipt checks the Merkle proof of the receipt of a single transaction against the attestation.
+func (a *Attestation) VerifyReceipt(receipt Receipt, proof *merkle.Proof) error {
+	if receipt.ReceivedAt.Before(a.FirstReceived) || receipt.ReceivedAt.After(a.LastReceived) {
+		return fmt.Errorf("%w: receive time out of range of the block", ErrInvalidAttestation)
+	}
+	if err := proof.Verify(a.Root, receipt.leaf()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAttestation, err)
+	}
+	return nil
+}
+
+// ProveReceipt returns the Merkle proof of i-th receipt, verifiable with Attestation.VerifyReceipt.
+func ProveReceipt(receipts []Receipt, i int) (*merkle.Proof, error) {
+	if i < 0 || i >= len(receipts) {
+		return nil, fmt.Errorf("receipt index %d out of range [0, %d)", i, len(receipts))
+	}
+	_, proofs := merkle.ProofsFromByteSlices(leaves(receipts))
+	return proofs[i], nil
+}
+
+func receiptsRoot(receipts []Receipt) []byte {
+	return merkle.HashFromByteSlices(leaves(receipts))
+}
+
+func leaves(receipts []Receipt) [][]byte {
+	l := make([][]byte, len(receipts))
+	for i := range receipts {
+		l[i] = receipts[i].leaf()
+	}
+	return l
+}
+
+// ReceiveTimeFunc returns the time of arrival of the transaction at the node, e.g. TxMempool.ReceiveTime.
+type ReceiveTimeFunc func(tx cmtypes.Tx) (time.Time, bool)
+
+// Extender adds the FCFS ordering attestation to headers of produced blocks, with receive times of transactions
+// recorded at ingress. Extensions of the wrapped extender (if any) are preserved.
+//
+// Receipts of the latest blocks are kept in memory, so they can be published for verification.
+// Blocks with transactions of unknown receive time (e.g. block re-created after restart) are not attested.
+type Extender struct {
+	receiveTime ReceiveTimeFunc
+	next        state.HeaderExtender
+
+	mtx      sync.Mutex
+	receipts map[uint64][]Receipt
+}
+
+var _ state.HeaderExtender = &Extender{}
+
+// NewExtender creates Extender using receive times returned by receiveTime, wrapping next extender (may be nil).
+func NewExtender(receiveTime ReceiveTimeFunc, next state.HeaderExtender) *Extender {
+	return &Extender{
+		receiveTime: receiveTime,
+		next:        next,
+		receipts:    make(map[uint64][]Receipt),
+	}
+}
+
+// Extensions returns extensions of the wrapped extender, followed by the ordering attestation of the block.
+// ErrNotFCFS is returned if transactions of the block are not ordered by time of arrival.
+func (e *Extender) Extensions(s types.State, block *types.Block) ([]types.HeaderExtension, error) {
+	var extensions []types.HeaderExtension
+	if e.next != nil {
+		var err error
+		extensions, err = e.next.Extensions(s, block)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(block.Data.Txs) == 0 {
+		return extensions, nil
+	}
+	receipts := make([]Receipt, len(block.Data.Txs))
+	for i, tx := range block.Data.Txs {
+		receivedAt, ok := e.receiveTime(cmtypes.Tx(tx))
+		if !ok {
+			return extensions, nil
+		}
+		receipts[i] = Receipt{TxHash: tx.Hash(), ReceivedAt: receivedAt}
+	}
+	attestation, err := NewAttestation(receipts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := attestation.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	e.mtx.Lock()
+	e.receipts[block.Height()] = receipts
+	delete(e.receipts, block.Height()-receiptsRetention)
+	e.mtx.Unlock()
+	return append(extensions, types.HeaderExtension{Type: ExtensionType, Data: data}), nil
+}
+
+// Receipts returns receipts of transactions of the block at given height, if they're still kept.
+func (e *Extender) Receipts(height uint64) ([]Receipt, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	receipts, ok := e.receipts[height]
+	return receipts, ok
+}
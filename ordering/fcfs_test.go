@@ -0,0 +1,97 @@
+package ordering
+
+import (
+	"testing"
+	"time"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+type mockExtender struct{}
+
+func (mockExtender) Extensions(types.State, *types.Block) ([]types.HeaderExtension, error) {
+	return []types.HeaderExtension{{Type: "app", Data: []byte{1}}}, nil
+}
+
+func receiveTimes(block *types.Block, start time.Time) map[string]time.Time {
+	times := make(map[string]time.Time)
+	for i, tx := range block.Data.Txs {
+		times[string(tx)] = start.Add(time.Duration(i) * time.Millisecond)
+	}
+	return times
+}
+
+func TestExtender(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	block := types.GetRandomBlock(1, 5)
+	start := time.Unix(1700000000, 0).UTC()
+	times := receiveTimes(block, start)
+	e := NewExtender(func(tx cmtypes.Tx) (time.Time, bool) {
+		ts, ok := times[string(tx)]
+		return ts, ok
+	}, mockExtender{})
+
+	extensions, err := e.Extensions(types.State{}, block)
+	require.NoError(err)
+	require.Len(extensions, 2)
+	assert.Equal("app", extensions[0].Type)
+	block.SignedHeader.Header.Extensions = extensions
+
+	attestation, ok, err := FromHeader(&block.SignedHeader.Header)
+	require.NoError(err)
+	require.True(ok)
+	assert.Equal(start, attestation.FirstReceived)
+	assert.Equal(start.Add(4*time.Millisecond), attestation.LastReceived)
+
+	receipts, ok := e.Receipts(1)
+	require.True(ok)
+	assert.NoError(attestation.Verify(block.Data.Txs, receipts))
+
+	proof, err := ProveReceipt(receipts, 2)
+	require.NoError(err)
+	assert.NoError(attestation.VerifyReceipt(receipts[2], proof))
+	forged := Receipt{TxHash: receipts[2].TxHash, ReceivedAt: receipts[2].ReceivedAt.Add(time.Nanosecond)}
+	assert.ErrorIs(attestation.VerifyReceipt(forged, proof), ErrInvalidAttestation)
+
+	// reordered transactions don't match receipts
+	txs := append(types.Txs{}, block.Data.Txs...)
+	txs[0], txs[1] = txs[1], txs[0]
+	assert.ErrorIs(attestation.Verify(txs, receipts), ErrInvalidAttestation)
+
+	// transactions out of order of arrival are rejected
+	times[string(block.Data.Txs[0])] = start.Add(time.Second)
+	_, err = e.Extensions(types.State{}, block)
+	assert.ErrorIs(err, ErrNotFCFS)
+
+	// unknown receive time - block is not attested
+	delete(times, string(block.Data.Txs[0]))
+	extensions, err = e.Extensions(types.State{}, block)
+	require.NoError(err)
+	assert.Len(extensions, 1)
+}
+
+func TestAttestationMarshaling(t *testing.T) {
+	receipts := []Receipt{
+		{TxHash: types.GetRandomTx().Hash(), ReceivedAt: time.Unix(1, 5).UTC()},
+		{TxHash: types.GetRandomTx().Hash(), ReceivedAt: time.Unix(2, 7).UTC()},
+	}
+	attestation, err := NewAttestation(receipts)
+	require.NoError(t, err)
+	data, err := attestation.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, 48)
+
+	decoded := &Attestation{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, attestation, decoded)
+	assert.ErrorIs(t, decoded.UnmarshalBinary(data[:40]), ErrInvalidAttestation)
+
+	_, err = NewAttestation([]Receipt{receipts[1], receipts[0]})
+	assert.ErrorIs(t, err, ErrNotFCFS)
+}
@@ -16,23 +16,34 @@ import (
 	"github.com/libp2p/go-libp2p/core/crypto"
 	cdiscovery "github.com/libp2p/go-libp2p/core/discovery"
 	"github.com/libp2p/go-libp2p/core/host"
+	libp2pmetrics "github.com/libp2p/go-libp2p/core/metrics"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
 	discovery "github.com/libp2p/go-libp2p/p2p/discovery/routing"
 	discutil "github.com/libp2p/go-libp2p/p2p/discovery/util"
 	routedhost "github.com/libp2p/go-libp2p/p2p/host/routed"
 	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	quic "github.com/libp2p/go-libp2p/p2p/transport/quic"
+	"github.com/libp2p/go-libp2p/p2p/transport/tcp"
+	"github.com/libp2p/go-libp2p/p2p/transport/websocket"
 	"github.com/multiformats/go-multiaddr"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 
+	goheaderp2p "github.com/celestiaorg/go-header/p2p"
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 
 	"github.com/rollkit/rollkit/config"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/tracing"
 )
 
 // TODO(tzdybal): refactor to configuration parameters
 const (
+	// bandwidthReportInterval defines how often bandwidth usage is reported in metrics.
+	bandwidthReportInterval = 10 * time.Second
+
 	// reAdvertisePeriod defines a period after which P2P client re-attempt advertising namespace in DHT.
 	reAdvertisePeriod = 1 * time.Hour
 
@@ -58,41 +69,85 @@ type Client struct {
 	disc  *discovery.RoutingDiscovery
 	gater *conngater.BasicConnectionGater
 	ps    *pubsub.PubSub
+	// datastore persists the connection gater and scores of peers
+	datastore datastore.Datastore
+
+	scorer    *peerScorer
+	limiter   *connLimiter
+	bandwidth *libp2pmetrics.BandwidthCounter
+	// throttle limits bandwidth of gossiped messages, it's shared with clients using the same gossipsub
+	throttle *gossipThrottle
 
 	txGossiper  *Gossiper
 	txValidator GossipValidator
 
+	fraudProofGossiper  *Gossiper
+	fraudProofValidator GossipValidator
+
+	evidenceGossiper  *Gossiper
+	evidenceValidator GossipValidator
+
+	inclusionListGossiper  *Gossiper
+	inclusionListValidator GossipValidator
+
+	headerBatchGossiper  *Gossiper
+	headerBatchValidator GossipValidator
+
+	// historyHandler is optional, used to serve historical data to peers
+	historyHandler HistoryHandler
+
+	// genesisHash is optional, used in handshakes with peers to reject peers of a different network
+	genesisHash []byte
+	mismatched  mismatchedPeers
+
+	// base is the client sharing its host, gossipsub and DHT with this client, nil if client has its own host
+	base *Client
+
 	// cancel is used to cancel context passed to libp2p functions
 	// it's required because of discovery.Advertise call
 	cancel context.CancelFunc
 
-	logger log.Logger
+	logger  log.Logger
+	metrics *Metrics
 }
 
 // NewClient creates new Client object.
 //
 // Basic checks on parameters are done, and default parameters are provided for unset-configuration
 // TODO(tzdybal): consider passing entire config, not just P2P config, to reduce number of arguments
-func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, ds datastore.Datastore, logger log.Logger) (*Client, error) {
+func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, ds datastore.Datastore, logger log.Logger, metrics *Metrics) (*Client, error) {
 	if privKey == nil {
 		return nil, errNoPrivKey
 	}
 	if conf.ListenAddress == "" {
 		conf.ListenAddress = config.DefaultListenAddress
 	}
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
 
 	gater, err := conngater.NewBasicConnectionGater(ds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection gater: %w", err)
 	}
 
-	return &Client{
-		conf:    conf,
-		gater:   gater,
-		privKey: privKey,
-		chainID: chainID,
-		logger:  logger,
-	}, nil
+	c := &Client{
+		conf:      conf,
+		gater:     gater,
+		datastore: ds,
+		scorer:    newPeerScorer(conf.BanThreshold, conf.BanDuration, logger),
+		limiter:   &connLimiter{maxInbound: conf.MaxInboundPeers, maxOutbound: conf.MaxOutboundPeers},
+		bandwidth: libp2pmetrics.NewBandwidthCounter(),
+		privKey:   privKey,
+		chainID:   chainID,
+		logger:    logger,
+		metrics:   metrics,
+	}
+	c.scorer.setPenalty(c.getTxTopic(), txPenalty)
+	c.scorer.setPenalty(c.getFraudProofTopic(), fraudProofPenalty)
+	c.scorer.setPenalty(c.getEvidenceTopic(), evidencePenalty)
+	c.scorer.setPenalty(c.getInclusionListTopic(), inclusionListPenalty)
+	return c, nil
 }
 
 // Start establish Client's P2P connectivity.
@@ -106,6 +161,12 @@ func (c *Client) Start(ctx context.Context) error {
 	// create new, cancelable context
 	ctx, c.cancel = context.WithCancel(ctx)
 	c.logger.Debug("starting P2P client")
+	if c.base != nil {
+		return c.startShared(ctx)
+	}
+	if c.host != nil {
+		return c.startWithHost(ctx, c.host)
+	}
 	host, err := c.listen(ctx)
 	if err != nil {
 		return err
@@ -119,6 +180,33 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 		c.logger.Info("listening on", "address", fmt.Sprintf("%s/p2p/%s", a, c.host.ID()))
 	}
 
+	updatePeers := func(n network.Network, _ network.Conn) {
+		c.metrics.Peers.Set(float64(len(n.Peers())))
+	}
+	c.host.Network().Notify(&network.NotifyBundle{
+		ConnectedF:    updatePeers,
+		DisconnectedF: updatePeers,
+	})
+
+	c.scorer.disconnect = func(id peer.ID) {
+		if err := c.host.Network().ClosePeer(id); err != nil {
+			c.logger.Error("failed to disconnect banned peer", "peer", id, "error", err)
+		}
+	}
+	c.scorer.updateScore = func(id peer.ID, score float64) {
+		if score == 0 {
+			c.host.ConnManager().UntagPeer(id, scoreTag)
+			return
+		}
+		c.host.ConnManager().TagPeer(id, scoreTag, int(score))
+	}
+	if err := c.scorer.load(ctx, c.datastore); err != nil {
+		c.logger.Error("failed to load persisted peer scores", "error", err)
+	}
+	go c.scorer.run(ctx)
+	c.limiter.setHost(h)
+	go c.reportBandwidth(ctx)
+
 	c.logger.Debug("blocking blacklisted peers", "blacklist", c.conf.BlockedPeers)
 	if err := c.setupBlockedPeers(c.parseAddrInfoList(c.conf.BlockedPeers)); err != nil {
 		return err
@@ -133,6 +221,12 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 	if err := c.setupGossiping(ctx); err != nil {
 		return err
 	}
+	c.setupHistory()
+
+	c.logger.Debug("setting up handshakes")
+	if err := c.setupHandshake(ctx); err != nil {
+		return err
+	}
 
 	c.logger.Debug("setting up DHT")
 	if err := c.setupDHT(ctx); err != nil {
@@ -144,22 +238,48 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 		return err
 	}
 
+	for _, p := range c.parseAddrInfoList(c.conf.PersistentPeers) {
+		go c.maintainPersistentPeer(ctx, p)
+	}
+
+	if c.conf.PEX {
+		c.logger.Debug("setting up peer exchange")
+		c.setupPeerExchange(ctx)
+	}
+
 	return nil
 }
 
 // Close gently stops Client.
 func (c *Client) Close() error {
 	c.cancel()
+	if c.base != nil {
+		return c.closeGossipers()
+	}
 
+	c.scorer.persistAll()
 	return multierr.Combine(
-		c.txGossiper.Close(),
+		c.closeGossipers(),
 		c.dht.Close(),
 		c.host.Close(),
 	)
 }
 
+func (c *Client) closeGossipers() error {
+	return multierr.Combine(
+		c.txGossiper.Close(),
+		c.fraudProofGossiper.Close(),
+		c.evidenceGossiper.Close(),
+		c.inclusionListGossiper.Close(),
+		c.headerBatchGossiper.Close(),
+	)
+}
+
 // GossipTx sends the transaction to the P2P network.
-func (c *Client) GossipTx(ctx context.Context, tx []byte) error {
+func (c *Client) GossipTx(ctx context.Context, tx []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "Client.GossipTx", attribute.Int("size", len(tx)))
+	defer func() { tracing.End(span, err) }()
+
 	c.logger.Debug("Gossiping TX", "len", len(tx))
 	return c.txGossiper.Publish(ctx, tx)
 }
@@ -169,11 +289,88 @@ func (c *Client) SetTxValidator(val GossipValidator) {
 	c.txValidator = val
 }
 
+// GossipFraudProof sends the encoded state fraud proof to the P2P network.
+func (c *Client) GossipFraudProof(ctx context.Context, proof []byte) error {
+	c.logger.Debug("Gossiping fraud proof", "len", len(proof))
+	return c.fraudProofGossiper.Publish(ctx, proof)
+}
+
+// SetFraudProofValidator sets the callback function, that will be invoked during fraud proof gossiping.
+//
+// Duplicated fraud proofs and proofs exceeding per-peer rate limit are rejected before invoking the validator.
+func (c *Client) SetFraudProofValidator(val GossipValidator) {
+	c.fraudProofValidator = val
+}
+
+// GossipEvidence sends the encoded evidence of aggregator equivocation to the P2P network.
+func (c *Client) GossipEvidence(ctx context.Context, evidence []byte) error {
+	c.logger.Debug("Gossiping evidence", "len", len(evidence))
+	return c.evidenceGossiper.Publish(ctx, evidence)
+}
+
+// SetEvidenceValidator sets the callback function, that will be invoked during evidence gossiping.
+//
+// Like fraud proofs, duplicated evidence and evidence exceeding per-peer rate limit is rejected before invoking
+// the validator.
+func (c *Client) SetEvidenceValidator(val GossipValidator) {
+	c.evidenceValidator = val
+}
+
+// GossipInclusionList sends the encoded inclusion list to the P2P network.
+func (c *Client) GossipInclusionList(ctx context.Context, list []byte) error {
+	c.logger.Debug("Gossiping inclusion list", "len", len(list))
+	return c.inclusionListGossiper.Publish(ctx, list)
+}
+
+// SetInclusionListValidator sets the callback function, that will be invoked during inclusion list gossiping.
+//
+// Like fraud proofs, duplicated lists and lists exceeding per-peer rate limit are rejected before invoking
+// the validator.
+func (c *Client) SetInclusionListValidator(val GossipValidator) {
+	c.inclusionListValidator = val
+}
+
+// GossipHeaderBatch sends the encoded batch of consecutive headers to the P2P network.
+func (c *Client) GossipHeaderBatch(ctx context.Context, batch []byte) error {
+	c.logger.Debug("Gossiping header batch", "len", len(batch))
+	return c.headerBatchGossiper.Publish(ctx, batch)
+}
+
+// SetHeaderBatchValidator sets the callback function, that will be invoked during header batch gossiping.
+//
+// The validator is expected to pass headers of the batch to the header sync service.
+func (c *Client) SetHeaderBatchValidator(val GossipValidator) {
+	c.headerBatchValidator = val
+}
+
+// PenalizePeer decreases score of the peer that sent invalid data. Peers with score below the configured
+// threshold are disconnected and temporarily banned.
+func (c *Client) PenalizePeer(id peer.ID, penalty float64, reason string) {
+	c.scorer.penalize(id, penalty, reason)
+}
+
+// PeerScores returns scores of peers penalized for relaying invalid messages, lowest first.
+func (c *Client) PeerScores() []PeerScore {
+	return c.scorer.peerScores()
+}
+
+// ClearPeerScores forgets the score and ban of the peer, or of all peers if id is empty, also in the datastore.
+// It returns the number of forgotten peers.
+func (c *Client) ClearPeerScores(id peer.ID) int {
+	return c.scorer.clear(id)
+}
+
 // Addrs returns listen addresses of Client.
 func (c *Client) Addrs() []multiaddr.Multiaddr {
 	return c.host.Addrs()
 }
 
+// SetHost sets the libp2p host used by the client instead of listening on configured addresses, e.g. an in-memory
+// host of mocknet. It has to be called before the client is started.
+func (c *Client) SetHost(h host.Host) {
+	c.host = h
+}
+
 // Host returns the libp2p node in a peer-to-peer network
 func (c *Client) Host() host.Host {
 	return c.host
@@ -216,6 +413,48 @@ func (c *Client) PeerIDs() []peer.ID {
 	return peerIDs
 }
 
+// PeerInfo describes connection with a peer.
+type PeerInfo struct {
+	ID peer.ID `json:"id"`
+	// Addrs are the remote addresses of connections with the peer.
+	Addrs []string `json:"addrs"`
+	// Direction is "inbound" if peer connected to the node, or "outbound" if node connected to the peer.
+	Direction string        `json:"direction"`
+	Duration  time.Duration `json:"duration"`
+	Score     float64       `json:"score"`
+	Protocols []protocol.ID `json:"protocols,omitempty"`
+}
+
+// PeerInfos returns details of connections with peers, including their scores.
+func (c *Client) PeerInfos() []PeerInfo {
+	scores := make(map[peer.ID]float64)
+	for _, s := range c.scorer.peerScores() {
+		scores[s.ID] = s.Score
+	}
+	peers := c.host.Network().Peers()
+	res := make([]PeerInfo, 0, len(peers))
+	for _, id := range peers {
+		conns := c.host.Network().ConnsToPeer(id)
+		if len(conns) == 0 {
+			continue
+		}
+		info := PeerInfo{
+			ID:        id,
+			Direction: strings.ToLower(conns[0].Stat().Direction.String()),
+			Duration:  time.Since(conns[0].Stat().Opened),
+			Score:     scores[id],
+		}
+		for _, conn := range conns {
+			info.Addrs = append(info.Addrs, conn.RemoteMultiaddr().String())
+		}
+		if protocols, err := c.host.Peerstore().GetProtocols(id); err == nil {
+			info.Protocols = protocols
+		}
+		res = append(res, info)
+	}
+	return res
+}
+
 // Peers returns list of peers connected to Client.
 func (c *Client) Peers() []PeerConnection {
 	conns := c.host.Network().Conns()
@@ -245,8 +484,69 @@ func (c *Client) listen(ctx context.Context) (host.Host, error) {
 	if err != nil {
 		return nil, err
 	}
+	listenAddrs := []multiaddr.Multiaddr{maddr}
+	transports := []libp2p.Option{libp2p.Transport(tcp.NewTCPTransport)}
+
+	if c.conf.QUICListenAddress != "" {
+		quicAddr, err := multiaddr.NewMultiaddr(c.conf.QUICListenAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUIC listen address: %w", err)
+		}
+		listenAddrs = append(listenAddrs, quicAddr)
+		transports = append(transports, libp2p.Transport(quic.NewTransport))
+	}
+	if c.conf.WebSocketListenAddress != "" {
+		wsAddr, err := multiaddr.NewMultiaddr(c.conf.WebSocketListenAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WebSocket listen address: %w", err)
+		}
+		listenAddrs = append(listenAddrs, wsAddr)
+		transports = append(transports, libp2p.Transport(websocket.New))
+	}
+
+	gater := &limitGater{
+		ConnectionGater: &banGater{BasicConnectionGater: c.gater, scorer: c.scorer},
+		limiter:         c.limiter,
+	}
+	opts := []libp2p.Option{
+		libp2p.ListenAddrs(listenAddrs...),
+		libp2p.Identity(c.privKey),
+		libp2p.ConnectionGater(gater),
+		libp2p.BandwidthReporter(c.bandwidth),
+	}
+	opts = append(opts, transports...)
+	opts = append(opts, c.natOptions()...)
 
-	return libp2p.New(libp2p.ListenAddrs(maddr), libp2p.Identity(c.privKey), libp2p.ConnectionGater(c.gater))
+	cm, err := newConnManager(c.conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection manager: %w", err)
+	}
+	if cm != nil {
+		opts = append(opts, libp2p.ConnectionManager(cm))
+	}
+
+	return libp2p.New(opts...)
+}
+
+// natOptions returns libp2p options for NAT traversal and circuit relays, enabled in config.
+func (c *Client) natOptions() []libp2p.Option {
+	var opts []libp2p.Option
+	if c.conf.NATPortMap {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	if c.conf.NATService {
+		opts = append(opts, libp2p.EnableNATService())
+	}
+	if c.conf.HolePunching {
+		opts = append(opts, libp2p.EnableHolePunching())
+	}
+	if c.conf.RelayService {
+		opts = append(opts, libp2p.EnableRelayService())
+	}
+	if relays := c.parseAddrInfoList(c.conf.StaticRelays); len(relays) > 0 {
+		opts = append(opts, libp2p.EnableAutoRelayWithStaticRelays(relays))
+	}
+	return opts
 }
 
 func (c *Client) setupDHT(ctx context.Context) error {
@@ -345,23 +645,104 @@ func (c *Client) findPeers(ctx context.Context) error {
 func (c *Client) tryConnect(ctx context.Context, peer peer.AddrInfo) {
 	err := c.host.Connect(ctx, peer)
 	if err != nil && ctx.Err() == nil {
+		c.metrics.ConnectionFailures.Add(1)
 		c.logger.Error("failed to connect to peer", "peer", peer, "error", err)
 	}
 }
 
+// reportBandwidth periodically updates metrics with bandwidth used by libp2p host.
+func (c *Client) reportBandwidth(ctx context.Context) {
+	ticker := time.NewTicker(bandwidthReportInterval)
+	defer ticker.Stop()
+	var last libp2pmetrics.Stats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		totals := c.bandwidth.GetBandwidthTotals()
+		c.metrics.BytesReceived.Add(float64(totals.TotalIn - last.TotalIn))
+		c.metrics.BytesSent.Add(float64(totals.TotalOut - last.TotalOut))
+		last = totals
+	}
+}
+
 func (c *Client) setupGossiping(ctx context.Context) error {
 	var err error
-	c.ps, err = pubsub.NewGossipSub(ctx, c.host)
+	c.throttle = newGossipThrottle(c.host.ID(), c.conf.PeerBandwidth, c.metrics)
+	c.ps, err = pubsub.NewGossipSub(ctx, c.host,
+		pubsub.WithRawTracer(c.scorer),
+		pubsub.WithRawTracer(metricsTracer{metrics: c.metrics}),
+		pubsub.WithDefaultValidator(c.throttle.validate),
+		pubsub.WithGossipSubParams(gossipSubParams(c.conf)),
+	)
 	if err != nil {
 		return err
 	}
+	return c.setupGossipers(ctx)
+}
+
+// gossipSubParams returns default gossipsub parameters, with mesh degree (fan-out) and heartbeat interval
+// overridden by configuration. Bounds of the mesh degree are scaled proportionally to the defaults.
+func gossipSubParams(conf config.P2PConfig) pubsub.GossipSubParams {
+	params := pubsub.DefaultGossipSubParams()
+	if d := conf.GossipFanout; d > 0 {
+		params.D = d
+		params.Dlo = max(1, d*5/6)
+		params.Dhi = 2 * d
+		params.Dscore = min(params.Dscore, params.Dlo)
+		// gossipsub requires Dout < Dlo and Dout <= D/2
+		params.Dout = min(params.Dout, d/2, params.Dlo-1)
+		params.Dlazy = d
+	}
+	if conf.GossipHeartbeat > 0 {
+		params.HeartbeatInterval = conf.GossipHeartbeat
+	}
+	return params
+}
+
+func (c *Client) setupGossipers(ctx context.Context) error {
+	c.throttle.setTopicRate(c.getTxTopic(), c.conf.TxBandwidth)
+	c.throttle.setTopicRate(c.getHeaderTopic(), c.conf.HeaderBandwidth)
+	c.throttle.setTopicRate(c.getHeaderBatchTopic(), c.conf.HeaderBandwidth)
+	c.throttle.setTopicRate(c.getBlockTopic(), c.conf.BlockBandwidth)
 
-	c.txGossiper, err = NewGossiper(c.host, c.ps, c.getTxTopic(), c.logger, WithValidator(c.txValidator))
+	var err error
+	c.txGossiper, err = NewGossiper(c.host, c.ps, c.getTxTopic(), c.logger, WithValidator(c.rejectMismatched(c.txValidator)))
 	if err != nil {
 		return err
 	}
 	go c.txGossiper.ProcessMessages(ctx)
 
+	c.fraudProofGossiper, err = NewGossiper(c.host, c.ps, c.getFraudProofTopic(), c.logger,
+		WithValidator(c.rejectMismatched(newFraudProofFilter(c.fraudProofValidator).validate)))
+	if err != nil {
+		return err
+	}
+	go c.fraudProofGossiper.ProcessMessages(ctx)
+
+	c.evidenceGossiper, err = NewGossiper(c.host, c.ps, c.getEvidenceTopic(), c.logger,
+		WithValidator(c.rejectMismatched(newFraudProofFilter(c.evidenceValidator).validate)))
+	if err != nil {
+		return err
+	}
+	go c.evidenceGossiper.ProcessMessages(ctx)
+
+	c.inclusionListGossiper, err = NewGossiper(c.host, c.ps, c.getInclusionListTopic(), c.logger,
+		WithValidator(c.rejectMismatched(newFraudProofFilter(c.inclusionListValidator).validate)))
+	if err != nil {
+		return err
+	}
+	go c.inclusionListGossiper.ProcessMessages(ctx)
+
+	c.headerBatchGossiper, err = NewGossiper(c.host, c.ps, c.getHeaderBatchTopic(), c.logger,
+		WithValidator(c.rejectMismatched(c.headerBatchValidator)))
+	if err != nil {
+		return err
+	}
+	go c.headerBatchGossiper.ProcessMessages(ctx)
+
 	return nil
 }
 
@@ -399,3 +780,29 @@ func (c *Client) getNamespace() string {
 func (c *Client) getTxTopic() string {
 	return c.getNamespace() + txTopicSuffix
 }
+
+// getHeaderTopic returns pubsub topic of header gossiping, joined by go-header subscriber of header sync service.
+func (c *Client) getHeaderTopic() string {
+	return goheaderp2p.PubsubTopicID(c.getNamespace())
+}
+
+// getBlockTopic returns pubsub topic of block gossiping, joined by go-header subscriber of block sync service.
+func (c *Client) getBlockTopic() string {
+	return goheaderp2p.PubsubTopicID(c.getNamespace() + "-block")
+}
+
+func (c *Client) getFraudProofTopic() string {
+	return c.getNamespace() + fraudProofTopicSuffix
+}
+
+func (c *Client) getEvidenceTopic() string {
+	return c.getNamespace() + evidenceTopicSuffix
+}
+
+func (c *Client) getInclusionListTopic() string {
+	return c.getNamespace() + inclusionListTopicSuffix
+}
+
+func (c *Client) getHeaderBatchTopic() string {
+	return c.getNamespace() + headerBatchTopicSuffix
+}
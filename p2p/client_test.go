@@ -10,8 +10,10 @@ import (
 	"github.com/ipfs/go-datastore"
 	dssync "github.com/ipfs/go-datastore/sync"
 	"github.com/ipfs/go-log"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,7 +47,7 @@ func TestClientStartup(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.desc, func(t *testing.T) {
 			client, err := NewClient(testCase.p2pconf, privKey, "TestChain",
-				dssync.MutexWrap(datastore.NewMapDatastore()), test.NewFileLoggerCustom(t, test.TempLogFileName(t, testCase.desc)))
+				dssync.MutexWrap(datastore.NewMapDatastore()), test.NewFileLoggerCustom(t, test.TempLogFileName(t, testCase.desc)), NopMetrics())
 			assert.NoError(err)
 			assert.NotNil(client)
 
@@ -58,6 +60,37 @@ func TestClientStartup(t *testing.T) {
 	}
 }
 
+func TestSharedClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	logger := test.NewFileLogger(t)
+	base, err := NewClient(config.P2PConfig{}, privKey, "TestChain", dssync.MutexWrap(datastore.NewMapDatastore()), logger, NopMetrics())
+	require.NoError(err)
+	shared := NewSharedClient(base, "OtherChain", logger, NopMetrics())
+
+	// base client has to be started first
+	assert.Error(shared.Start(context.Background()))
+
+	require.NoError(base.Start(context.Background()))
+	defer func() {
+		_ = base.Close()
+	}()
+	shared = NewSharedClient(base, "OtherChain", logger, NopMetrics())
+	require.NoError(shared.Start(context.Background()))
+
+	assert.Equal(base.Host().ID(), shared.Host().ID())
+	assert.Equal(base.PubSub(), shared.PubSub())
+	assert.NotEqual(base.getTxTopic(), shared.getTxTopic())
+	assert.NoError(shared.GossipTx(context.Background(), []byte("tx")))
+
+	// closing shared client doesn't close the host
+	require.NoError(shared.Close())
+	assert.NotEmpty(base.Addrs())
+	assert.NoError(base.GossipTx(context.Background(), []byte("tx")))
+}
+
 func TestBootstrapping(t *testing.T) {
 	_ = log.SetLogLevel("dht", "INFO")
 	//log.SetDebugLogging()
@@ -194,7 +227,7 @@ func TestSeedStringParsing(t *testing.T) {
 			require := require.New(t)
 			logger := &test.MockLogger{}
 			client, err := NewClient(config.P2PConfig{}, privKey, "TestNetwork",
-				dssync.MutexWrap(datastore.NewMapDatastore()), logger)
+				dssync.MutexWrap(datastore.NewMapDatastore()), logger, NopMetrics())
 			require.NoError(err)
 			require.NotNil(client)
 			actual := client.parseAddrInfoList(c.input)
@@ -205,3 +238,70 @@ func TestSeedStringParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestPeerInfos(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mnet, err := mocknet.FullMeshLinked(3)
+	require.NoError(err)
+	hosts := mnet.Hosts()
+
+	logger := test.NewFileLogger(t)
+	client := &Client{host: hosts[0], logger: logger, scorer: newPeerScorer(0, time.Hour, logger)}
+	client.scorer.penalize(hosts[2].ID(), 10, "test")
+
+	_, err = mnet.ConnectPeers(hosts[0].ID(), hosts[1].ID())
+	require.NoError(err)
+	_, err = mnet.ConnectPeers(hosts[2].ID(), hosts[0].ID())
+	require.NoError(err)
+
+	infos := make(map[peer.ID]PeerInfo)
+	for _, info := range client.PeerInfos() {
+		infos[info.ID] = info
+	}
+	require.Len(infos, 2)
+	assert.Equal("outbound", infos[hosts[1].ID()].Direction)
+	assert.Equal(0.0, infos[hosts[1].ID()].Score)
+	assert.NotEmpty(infos[hosts[1].ID()].Addrs)
+	assert.Equal("inbound", infos[hosts[2].ID()].Direction)
+	assert.Equal(-10.0, infos[hosts[2].ID()].Score)
+}
+
+func TestNATOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	logger := &test.MockLogger{}
+	client := &Client{logger: logger}
+	assert.Empty(client.natOptions())
+
+	client.conf = config.P2PConfig{
+		NATPortMap:   true,
+		NATService:   true,
+		HolePunching: true,
+		RelayService: true,
+		StaticRelays: "/ip4/127.0.0.1/tcp/7676/p2p/12D3KooWM1NFkZozoatQi3JvFE57eBaX56mNgBA68Lk5MTPxBE4U,invalid",
+	}
+	assert.Len(client.natOptions(), 5)
+	assert.Len(logger.ErrLines, 1)
+}
+
+func TestGossipSubParams(t *testing.T) {
+	assert := assert.New(t)
+
+	defaults := pubsub.DefaultGossipSubParams()
+	assert.Equal(defaults, gossipSubParams(config.P2PConfig{}))
+
+	params := gossipSubParams(config.P2PConfig{GossipFanout: 12, GossipHeartbeat: 500 * time.Millisecond})
+	assert.Equal(12, params.D)
+	assert.Equal(10, params.Dlo)
+	assert.Equal(24, params.Dhi)
+	assert.Equal(defaults.Dout, params.Dout)
+	assert.Equal(500*time.Millisecond, params.HeartbeatInterval)
+
+	// degree bounds remain consistent for the smallest fan-out
+	params = gossipSubParams(config.P2PConfig{GossipFanout: 1})
+	assert.Equal(1, params.Dlo)
+	assert.Zero(params.Dout)
+	assert.LessOrEqual(params.Dscore, params.Dlo)
+}
@@ -0,0 +1,87 @@
+package p2p
+
+import (
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	basicconnmgr "github.com/libp2p/go-libp2p/p2p/net/connmgr"
+
+	"github.com/rollkit/rollkit/config"
+)
+
+// scoreTag is used to tag peers with their score in connection manager, so peers with lowest scores are pruned first.
+const scoreTag = "rollkit-score"
+
+// newConnManager creates connection manager pruning connections when the number of peers exceeds the sum of
+// inbound and outbound peer limits. Nil is returned if peers are not limited.
+func newConnManager(conf config.P2PConfig) (*basicconnmgr.BasicConnMgr, error) {
+	high := conf.MaxInboundPeers + conf.MaxOutboundPeers
+	if high == 0 {
+		return nil, nil
+	}
+	// prune connections down to 80% of limit, to avoid pruning on every new connection
+	low := high * 4 / 5
+	return basicconnmgr.NewConnManager(low, high, basicconnmgr.WithGracePeriod(conf.PeerGracePeriod))
+}
+
+// connLimiter limits the number of inbound and outbound peers. Zero limit means no limit.
+//
+// Protected peers (for example persistent peers) and peers that are already connected are always allowed.
+type connLimiter struct {
+	maxInbound  int
+	maxOutbound int
+
+	// host is set when client is started; connections are not limited before
+	host atomic.Value
+}
+
+func (l *connLimiter) setHost(h host.Host) {
+	l.host.Store(h)
+}
+
+// allow returns true if connection with the peer in given direction doesn't exceed limits.
+func (l *connLimiter) allow(dir network.Direction, p peer.ID) bool {
+	h, ok := l.host.Load().(host.Host)
+	if !ok {
+		return true
+	}
+	limit := l.maxOutbound
+	if dir == network.DirInbound {
+		limit = l.maxInbound
+	}
+	if limit == 0 || h.Network().Connectedness(p) == network.Connected || h.ConnManager().IsProtected(p, "") {
+		return true
+	}
+	return countPeers(h.Network(), dir) < limit
+}
+
+// countPeers returns the number of peers with first connection in given direction.
+func countPeers(n network.Network, dir network.Direction) int {
+	count := 0
+	for _, id := range n.Peers() {
+		conns := n.ConnsToPeer(id)
+		if len(conns) > 0 && conns[0].Stat().Direction == dir {
+			count++
+		}
+	}
+	return count
+}
+
+// limitGater extends connection gater with limits of inbound and outbound peers.
+type limitGater struct {
+	connmgr.ConnectionGater
+	limiter *connLimiter
+}
+
+// InterceptPeerDial rejects dialing new peers when outbound peer limit is reached.
+func (g *limitGater) InterceptPeerDial(p peer.ID) bool {
+	return g.limiter.allow(network.DirOutbound, p) && g.ConnectionGater.InterceptPeerDial(p)
+}
+
+// InterceptSecured rejects connections with new peers when peer limit (in given direction) is reached.
+func (g *limitGater) InterceptSecured(dir network.Direction, p peer.ID, addrs network.ConnMultiaddrs) bool {
+	return g.limiter.allow(dir, p) && g.ConnectionGater.InterceptSecured(dir, p, addrs)
+}
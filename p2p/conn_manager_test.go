@@ -0,0 +1,38 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnLimiter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mnet, err := mocknet.FullMeshLinked(4)
+	require.NoError(err)
+	hosts := mnet.Hosts()
+
+	limiter := &connLimiter{maxInbound: 1, maxOutbound: 1}
+	// connections are not limited before host is set
+	assert.True(limiter.allow(network.DirInbound, hosts[1].ID()))
+	limiter.setHost(hosts[0])
+
+	_, err = mnet.ConnectPeers(hosts[1].ID(), hosts[0].ID())
+	require.NoError(err)
+	assert.True(limiter.allow(network.DirInbound, hosts[1].ID()), "connected peer should be allowed")
+	assert.False(limiter.allow(network.DirInbound, hosts[2].ID()))
+	assert.True(limiter.allow(network.DirOutbound, hosts[2].ID()))
+
+	_, err = mnet.ConnectPeers(hosts[0].ID(), hosts[3].ID())
+	require.NoError(err)
+	assert.False(limiter.allow(network.DirOutbound, hosts[2].ID()))
+
+	// zero limit disables limiting
+	limiter.maxInbound = 0
+	assert.True(limiter.allow(network.DirInbound, hosts[2].ID()))
+}
@@ -0,0 +1,91 @@
+package p2p
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// fraudProofTopicSuffix is added after namespace to create pubsub topic for fraud proof gossiping.
+	fraudProofTopicSuffix = "-fraud-proof"
+
+	// evidenceTopicSuffix is added after namespace to create pubsub topic for evidence gossiping.
+	evidenceTopicSuffix = "-evidence"
+
+	// inclusionListTopicSuffix is added after namespace to create pubsub topic for inclusion list gossiping.
+	inclusionListTopicSuffix = "-inclusion-list"
+
+	// headerBatchTopicSuffix is added after namespace to create pubsub topic for gossiping batches of headers.
+	headerBatchTopicSuffix = "-header-batch"
+
+	// fraudProofRateLimit is the number of fraud proofs accepted from a single peer in fraudProofRateWindow.
+	fraudProofRateLimit  = 10
+	fraudProofRateWindow = 1 * time.Minute
+
+	// maxSeenFraudProofs limits the number of fraud proof hashes kept for deduplication.
+	maxSeenFraudProofs = 1000
+)
+
+type peerRate struct {
+	windowStart time.Time
+	count       int
+}
+
+// fraudProofFilter wraps fraud proof validator with deduplication and per-peer rate limiting.
+//
+// Messages rejected by filter or validator are not relayed to other peers.
+type fraudProofFilter struct {
+	validator GossipValidator
+
+	mtx   sync.Mutex
+	seen  map[[sha256.Size]byte]struct{}
+	peers map[peer.ID]*peerRate
+	now   func() time.Time
+}
+
+func newFraudProofFilter(validator GossipValidator) *fraudProofFilter {
+	return &fraudProofFilter{
+		validator: validator,
+		seen:      make(map[[sha256.Size]byte]struct{}),
+		peers:     make(map[peer.ID]*peerRate),
+		now:       time.Now,
+	}
+}
+
+func (f *fraudProofFilter) validate(m *GossipMessage) bool {
+	if !f.accept(m) || f.validator == nil {
+		return false
+	}
+	return f.validator(m)
+}
+
+// accept returns true if message wasn't seen before and its sender didn't exceed the rate limit.
+func (f *fraudProofFilter) accept(m *GossipMessage) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	hash := sha256.Sum256(m.Data)
+	if _, ok := f.seen[hash]; ok {
+		return false
+	}
+
+	now := f.now()
+	rate, ok := f.peers[m.From]
+	if !ok || now.Sub(rate.windowStart) >= fraudProofRateWindow {
+		rate = &peerRate{windowStart: now}
+		f.peers[m.From] = rate
+	}
+	if rate.count >= fraudProofRateLimit {
+		return false
+	}
+	rate.count++
+
+	if len(f.seen) >= maxSeenFraudProofs {
+		f.seen = make(map[[sha256.Size]byte]struct{})
+	}
+	f.seen[hash] = struct{}{}
+	return true
+}
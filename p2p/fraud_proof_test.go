@@ -0,0 +1,44 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFraudProofFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	validated := 0
+	filter := newFraudProofFilter(func(m *GossipMessage) bool {
+		validated++
+		return string(m.Data) != "invalid"
+	})
+	now := time.Now()
+	filter.now = func() time.Time { return now }
+
+	// valid proof is accepted once
+	assert.True(filter.validate(&GossipMessage{Data: []byte("proof"), From: peer.ID("a")}))
+	assert.False(filter.validate(&GossipMessage{Data: []byte("proof"), From: peer.ID("b")}))
+	assert.Equal(1, validated)
+
+	// invalid proof is not relayed, and not validated again
+	assert.False(filter.validate(&GossipMessage{Data: []byte("invalid"), From: peer.ID("a")}))
+	assert.False(filter.validate(&GossipMessage{Data: []byte("invalid"), From: peer.ID("a")}))
+	assert.Equal(2, validated)
+
+	// peer exceeding the rate limit is ignored until the window passes
+	for i := 2; i < fraudProofRateLimit; i++ {
+		assert.True(filter.validate(&GossipMessage{Data: []byte{byte(i)}, From: peer.ID("a")}))
+	}
+	assert.False(filter.validate(&GossipMessage{Data: []byte("other"), From: peer.ID("a")}))
+	assert.True(filter.validate(&GossipMessage{Data: []byte("other"), From: peer.ID("b")}))
+
+	now = now.Add(fraudProofRateWindow)
+	assert.True(filter.validate(&GossipMessage{Data: []byte("another"), From: peer.ID("a")}))
+
+	// messages are rejected without validator
+	assert.False(newFraudProofFilter(nil).validate(&GossipMessage{Data: []byte("proof"), From: peer.ID("a")}))
+}
@@ -16,6 +16,8 @@ import (
 type GossipMessage struct {
 	Data []byte
 	From peer.ID
+	// ReceivedFrom is the peer that relayed the message.
+	ReceivedFrom peer.ID
 }
 
 // GossipValidator is a callback function type.
@@ -106,10 +108,11 @@ func (g *Gossiper) ProcessMessages(ctx context.Context) {
 }
 
 func wrapValidator(validator GossipValidator) pubsub.Validator {
-	return func(_ context.Context, _ peer.ID, msg *pubsub.Message) bool {
+	return func(_ context.Context, receivedFrom peer.ID, msg *pubsub.Message) bool {
 		return validator(&GossipMessage{
-			Data: msg.Data,
-			From: msg.GetFrom(),
+			Data:         msg.Data,
+			From:         msg.GetFrom(),
+			ReceivedFrom: receivedFrom,
 		})
 	}
 }
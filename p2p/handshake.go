@@ -0,0 +1,201 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// handshakeProtocolSuffix is added after namespace to create ID of handshake protocol.
+	handshakeProtocolSuffix = "/handshake/1.0.0"
+
+	// handshakeTimeout limits duration of a single handshake.
+	handshakeTimeout = 10 * time.Second
+	// handshakeMaxSize limits the size of a handshake read from a peer.
+	handshakeMaxSize = 1024
+)
+
+// handshake describes the network of a node, exchanged with peers after connecting.
+type handshake struct {
+	ChainID     string `json:"chain_id"`
+	GenesisHash []byte `json:"genesis_hash"`
+}
+
+// mismatchedPeers keeps peers that belong to a different network (chain ID or genesis hash) than the client.
+type mismatchedPeers struct {
+	mtx   sync.Mutex
+	peers map[peer.ID]string
+}
+
+func (mp *mismatchedPeers) add(id peer.ID, reason string) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+	if mp.peers == nil {
+		mp.peers = make(map[peer.ID]string)
+	}
+	mp.peers[id] = reason
+}
+
+func (mp *mismatchedPeers) contains(id peer.ID) bool {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+	_, ok := mp.peers[id]
+	return ok
+}
+
+// SetGenesisHash enables handshakes with peers: chain ID and genesis hash of the client are exchanged with peers
+// after connecting, and peers of a different network are rejected. Messages gossiped or relayed by such peers
+// are rejected, and if the client doesn't share its host with other chains, the peers are disconnected.
+// It has to be called before the client is started.
+func (c *Client) SetGenesisHash(hash []byte) {
+	c.genesisHash = hash
+}
+
+// IsMismatchedPeer returns true if handshake with the peer revealed that it belongs to a different network.
+func (c *Client) IsMismatchedPeer(id peer.ID) bool {
+	return c.mismatched.contains(id)
+}
+
+// setupHandshake starts serving handshakes, and makes handshakes with connected peers and peers connecting later,
+// if genesis hash is set.
+func (c *Client) setupHandshake(ctx context.Context) error {
+	if c.genesisHash == nil {
+		return nil
+	}
+	c.host.SetStreamHandler(c.getHandshakeProtocol(), c.handleHandshake)
+
+	sub, err := c.host.EventBus().Subscribe(new(event.EvtPeerIdentificationCompleted))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to peer identification events: %w", err)
+	}
+	go func() {
+		defer sub.Close() //nolint:errcheck
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-sub.Out():
+				if !ok {
+					return
+				}
+				go c.handshakePeer(ctx, ev.(event.EvtPeerIdentificationCompleted).Peer)
+			}
+		}
+	}()
+	for _, id := range c.host.Network().Peers() {
+		go c.handshakePeer(ctx, id)
+	}
+	return nil
+}
+
+// handshakePeer makes handshake with the peer, if it supports handshake protocol of the chain. Peers already known
+// to belong to a different network are rejected again without a handshake.
+func (c *Client) handshakePeer(ctx context.Context, id peer.ID) {
+	if c.mismatched.contains(id) {
+		c.rejectPeer(id)
+		return
+	}
+	if protocols, err := c.host.Peerstore().SupportsProtocols(id, c.getHandshakeProtocol()); err != nil || len(protocols) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	s, err := c.host.NewStream(ctx, id, c.getHandshakeProtocol())
+	if err != nil {
+		c.logger.Debug("failed to open handshake stream", "peer", id, "error", err)
+		return
+	}
+	defer s.Close() //nolint:errcheck
+	_ = s.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	if err := json.NewEncoder(s).Encode(c.ownHandshake()); err != nil {
+		c.logger.Debug("failed to send handshake", "peer", id, "error", err)
+		_ = s.Reset()
+		return
+	}
+	var remote handshake
+	if err := json.NewDecoder(io.LimitReader(s, handshakeMaxSize)).Decode(&remote); err != nil {
+		c.logger.Debug("failed to read handshake", "peer", id, "error", err)
+		_ = s.Reset()
+		return
+	}
+	c.checkHandshake(id, &remote)
+}
+
+func (c *Client) handleHandshake(s network.Stream) {
+	defer s.Close() //nolint:errcheck
+	_ = s.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	id := s.Conn().RemotePeer()
+	var remote handshake
+	if err := json.NewDecoder(io.LimitReader(s, handshakeMaxSize)).Decode(&remote); err != nil {
+		c.logger.Debug("failed to read handshake", "peer", id, "error", err)
+		_ = s.Reset()
+		return
+	}
+	if err := json.NewEncoder(s).Encode(c.ownHandshake()); err != nil {
+		c.logger.Debug("failed to send handshake", "peer", id, "error", err)
+		_ = s.Reset()
+		return
+	}
+	c.checkHandshake(id, &remote)
+}
+
+func (c *Client) ownHandshake() *handshake {
+	return &handshake{ChainID: c.chainID, GenesisHash: c.genesisHash}
+}
+
+// checkHandshake compares the network of the peer with the network of the client, and rejects the peer
+// if they don't match.
+func (c *Client) checkHandshake(id peer.ID, remote *handshake) {
+	var reason string
+	switch {
+	case remote.ChainID != c.chainID:
+		reason = fmt.Sprintf("chain ID mismatch: expected %q, got %q", c.chainID, remote.ChainID)
+	case !bytes.Equal(remote.GenesisHash, c.genesisHash):
+		reason = fmt.Sprintf("genesis hash mismatch: expected %s, got %s",
+			hex.EncodeToString(c.genesisHash), hex.EncodeToString(remote.GenesisHash))
+	default:
+		return
+	}
+	c.logger.Info("rejecting peer of another network", "peer", id, "reason", reason)
+	c.mismatched.add(id, reason)
+	c.rejectPeer(id)
+}
+
+// rejectPeer disconnects the peer of a different network, unless the host is shared with other chains
+// (the peer may belong to the network of another chain).
+func (c *Client) rejectPeer(id peer.ID) {
+	if c.base != nil {
+		return
+	}
+	if err := c.host.Network().ClosePeer(id); err != nil {
+		c.logger.Error("failed to disconnect peer of another network", "peer", id, "error", err)
+	}
+}
+
+// rejectMismatched returns validator rejecting messages published or relayed by peers of a different network,
+// before passing them to the validator.
+func (c *Client) rejectMismatched(val GossipValidator) GossipValidator {
+	return func(msg *GossipMessage) bool {
+		if c.mismatched.contains(msg.From) || c.mismatched.contains(msg.ReceivedFrom) {
+			return false
+		}
+		return val == nil || val(msg)
+	}
+}
+
+func (c *Client) getHandshakeProtocol() protocol.ID {
+	return protocol.ID("/" + c.getNamespace() + handshakeProtocolSuffix)
+}
@@ -0,0 +1,50 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestHandshake(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clients := startTestNetwork(ctx, t, 3, map[int]hostDescr{
+		0: {chainID: "ORU"},
+		1: {conns: []int{0}, chainID: "ORU"},
+		2: {conns: []int{0}, chainID: "ORU"},
+	}, make([]GossipValidator, 3), test.NewFileLogger(t))
+	clients.WaitForDHT()
+
+	clients[0].SetGenesisHash([]byte{1})
+	clients[1].SetGenesisHash([]byte{1})
+	clients[2].SetGenesisHash([]byte{2})
+	for _, c := range clients {
+		require.NoError(c.setupHandshake(ctx))
+	}
+
+	matching, mismatched := clients[1].host.ID(), clients[2].host.ID()
+	assert.Eventually(func() bool {
+		clients[0].handshakePeer(ctx, mismatched)
+		return clients[0].IsMismatchedPeer(mismatched)
+	}, 5*time.Second, 50*time.Millisecond)
+	clients[0].handshakePeer(ctx, matching)
+	assert.False(clients[0].IsMismatchedPeer(matching))
+
+	// peer of another network is disconnected, and its messages are rejected
+	assert.Eventually(func() bool {
+		return len(clients[0].host.Network().ConnsToPeer(mismatched)) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	validate := clients[0].rejectMismatched(nil)
+	assert.True(validate(&GossipMessage{Data: []byte("tx"), From: matching, ReceivedFrom: matching}))
+	assert.False(validate(&GossipMessage{Data: []byte("tx"), From: mismatched, ReceivedFrom: matching}))
+	assert.False(validate(&GossipMessage{Data: []byte("tx"), From: matching, ReceivedFrom: mismatched}))
+}
@@ -0,0 +1,154 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// historyProtocolSuffix is added after namespace to create ID of history protocol.
+	historyProtocolSuffix = "/history/1.0.0"
+
+	// historyTimeout limits duration of a single history request.
+	historyTimeout = 30 * time.Second
+	// historyMaxRequestSize limits the size of a request read from a peer.
+	historyMaxRequestSize = 1024
+	// historyMaxResponseSize limits the size of a response read from a peer.
+	historyMaxResponseSize = 128 * 1024 * 1024
+)
+
+// Types of data served by history protocol.
+const (
+	// HistoryBlocks requests marshaled blocks from From to To (inclusive).
+	HistoryBlocks = "blocks"
+	// HistorySnapshots requests marshaled ABCI snapshots of application state.
+	HistorySnapshots = "snapshots"
+	// HistorySnapshotChunk requests a chunk of ABCI snapshot at Height, in Format.
+	HistorySnapshotChunk = "snapshot_chunk"
+)
+
+// errHistoryNotServed is returned when the peer doesn't serve history.
+var errHistoryNotServed = errors.New("peer doesn't serve history")
+
+// HistoryRequest is a request of historical data, sent to archival peers.
+type HistoryRequest struct {
+	Type   string `json:"type"`
+	From   uint64 `json:"from,omitempty"`
+	To     uint64 `json:"to,omitempty"`
+	Height uint64 `json:"height,omitempty"`
+	Format uint32 `json:"format,omitempty"`
+	Chunk  uint32 `json:"chunk,omitempty"`
+}
+
+// historyResponse contains requested data, or error message if request failed.
+type historyResponse struct {
+	Data  [][]byte `json:"data,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// HistoryHandler returns historical data requested by a peer.
+type HistoryHandler func(ctx context.Context, req *HistoryRequest) ([][]byte, error)
+
+// SetHistoryHandler enables serving of historical data to peers, e.g. by archival nodes.
+// It has to be called before the client is started.
+func (c *Client) SetHistoryHandler(handler HistoryHandler) {
+	c.historyHandler = handler
+}
+
+// setupHistory starts serving historical data, if the handler is set.
+func (c *Client) setupHistory() {
+	if c.historyHandler != nil {
+		c.host.SetStreamHandler(c.getHistoryProtocol(), c.handleHistory)
+	}
+}
+
+func (c *Client) handleHistory(s network.Stream) {
+	defer s.Close() //nolint:errcheck
+	_ = s.SetDeadline(time.Now().Add(historyTimeout))
+
+	var req HistoryRequest
+	if err := json.NewDecoder(io.LimitReader(s, historyMaxRequestSize)).Decode(&req); err != nil {
+		c.logger.Debug("failed to read history request", "peer", s.Conn().RemotePeer(), "error", err)
+		_ = s.Reset()
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+	defer cancel()
+	var resp historyResponse
+	data, err := c.historyHandler(ctx, &req)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Data = data
+	}
+	if err := json.NewEncoder(s).Encode(&resp); err != nil {
+		c.logger.Debug("failed to send history", "peer", s.Conn().RemotePeer(), "error", err)
+		_ = s.Reset()
+	}
+}
+
+// RequestHistory requests historical data from given peer.
+func (c *Client) RequestHistory(ctx context.Context, id peer.ID, req *HistoryRequest) ([][]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, historyTimeout)
+	defer cancel()
+	s, err := c.host.NewStream(ctx, id, c.getHistoryProtocol())
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close() //nolint:errcheck
+	_ = s.SetDeadline(time.Now().Add(historyTimeout))
+
+	if err := json.NewEncoder(s).Encode(req); err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	var resp historyResponse
+	if err := json.NewDecoder(io.LimitReader(s, historyMaxResponseSize)).Decode(&resp); err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Data, nil
+}
+
+// HistoryPeers returns connected peers serving historical data.
+func (c *Client) HistoryPeers() []peer.ID {
+	var peers []peer.ID
+	for _, id := range c.host.Network().Peers() {
+		if protocols, err := c.host.Peerstore().SupportsProtocols(id, c.getHistoryProtocol()); err == nil && len(protocols) > 0 {
+			peers = append(peers, id)
+		}
+	}
+	return peers
+}
+
+// RequestHistoryFromPeers requests historical data from connected peers serving it, until the first peer responds
+// with data accepted by verify.
+func (c *Client) RequestHistoryFromPeers(ctx context.Context, req *HistoryRequest, verify func([][]byte) error) ([][]byte, error) {
+	err := errHistoryNotServed
+	for _, id := range shuffle(c.HistoryPeers()) {
+		var data [][]byte
+		data, err = c.RequestHistory(ctx, id, req)
+		if err == nil {
+			if err = verify(data); err == nil {
+				return data, nil
+			}
+			c.PenalizePeer(id, historyPenalty, "invalid history: "+err.Error())
+		}
+		c.logger.Debug("failed to request history", "peer", id, "type", req.Type, "error", err)
+	}
+	return nil, err
+}
+
+func (c *Client) getHistoryProtocol() protocol.ID {
+	return protocol.ID("/" + c.getNamespace() + historyProtocolSuffix)
+}
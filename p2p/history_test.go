@@ -0,0 +1,47 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestHistory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		1: {conns: []int{0}},
+	}, make([]GossipValidator, 2), test.NewFileLogger(t))
+	clients.WaitForDHT()
+
+	// peer without history handler doesn't serve history
+	_, err := clients[1].RequestHistory(ctx, clients[0].host.ID(), &HistoryRequest{Type: HistoryBlocks, From: 1, To: 2})
+	assert.Error(err)
+
+	clients[0].SetHistoryHandler(func(_ context.Context, req *HistoryRequest) ([][]byte, error) {
+		if req.Type != HistoryBlocks {
+			return nil, errors.New("unsupported request")
+		}
+		var data [][]byte
+		for h := req.From; h <= req.To; h++ {
+			data = append(data, []byte{byte(h)})
+		}
+		return data, nil
+	})
+	clients[0].setupHistory()
+
+	data, err := clients[1].RequestHistory(ctx, clients[0].host.ID(), &HistoryRequest{Type: HistoryBlocks, From: 1, To: 2})
+	require.NoError(err)
+	assert.Equal([][]byte{{1}, {2}}, data)
+
+	_, err = clients[1].RequestHistory(ctx, clients[0].host.ID(), &HistoryRequest{Type: HistorySnapshots})
+	assert.EqualError(err, "unsupported request")
+}
@@ -0,0 +1,111 @@
+package p2p
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "p2p"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of connected peers.
+	Peers metrics.Gauge
+
+	// Number of gossiped messages received, per topic.
+	MessagesReceived metrics.Counter
+
+	// Number of gossiped messages rejected by validators, per topic.
+	MessagesRejected metrics.Counter
+
+	// Number of gossiped messages ignored because of bandwidth limits, per topic.
+	MessagesThrottled metrics.Counter
+
+	// Number of bytes received from peers.
+	BytesReceived metrics.Counter
+
+	// Number of bytes sent to peers.
+	BytesSent metrics.Counter
+
+	// Number of failed attempts to connect to peers (including failed handshakes).
+	ConnectionFailures metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Peers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peers",
+			Help:      "Number of connected peers.",
+		}, labels).With(labelsAndValues...),
+
+		MessagesReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "messages_received",
+			Help:      "Number of gossiped messages received, per topic.",
+		}, append(labels, "topic")).With(labelsAndValues...),
+
+		MessagesRejected: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "messages_rejected",
+			Help:      "Number of gossiped messages rejected by validators, per topic.",
+		}, append(labels, "topic")).With(labelsAndValues...),
+
+		MessagesThrottled: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "messages_throttled",
+			Help:      "Number of gossiped messages ignored because of bandwidth limits, per topic.",
+		}, append(labels, "topic")).With(labelsAndValues...),
+
+		BytesReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bytes_received",
+			Help:      "Number of bytes received from peers.",
+		}, labels).With(labelsAndValues...),
+
+		BytesSent: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bytes_sent",
+			Help:      "Number of bytes sent to peers.",
+		}, labels).With(labelsAndValues...),
+
+		ConnectionFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "connection_failures",
+			Help:      "Number of failed attempts to connect to peers (including failed handshakes).",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Peers:              discard.NewGauge(),
+		MessagesReceived:   discard.NewCounter(),
+		MessagesRejected:   discard.NewCounter(),
+		MessagesThrottled:  discard.NewCounter(),
+		BytesReceived:      discard.NewCounter(),
+		BytesSent:          discard.NewCounter(),
+		ConnectionFailures: discard.NewCounter(),
+	}
+}
@@ -0,0 +1,169 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// peerScoresKey is the datastore key, under which scores and bans of peers are persisted.
+var peerScoresKey = datastore.NewKey("/p2p/peer_scores")
+
+// persistedScore is the score of a peer saved in the datastore, so known-bad peers are remembered across restarts.
+type persistedScore struct {
+	Score       float64   `json:"score"`
+	BannedUntil time.Time `json:"banned_until,omitempty"`
+	// UpdatedAt is used to decay the score for the time the node was down.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// load restores scores and bans persisted in the datastore, and enables their persistence. Scores decay for the time
+// elapsed since they were saved, and expired entries (with expired ban and negligible score) are deleted.
+func (s *peerScorer) load(ctx context.Context, ds datastore.Datastore) error {
+	results, err := ds.Query(ctx, dsq.Query{Prefix: peerScoresKey.String()})
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	s.mtx.Lock()
+	s.ds = ds
+	now := s.now()
+	loaded := make(map[peer.ID]float64)
+	var expired []datastore.Key
+	for result := range results.Next() {
+		if result.Error != nil {
+			s.mtx.Unlock()
+			return result.Error
+		}
+		key := datastore.NewKey(result.Key)
+		id, err := peer.Decode(key.BaseNamespace())
+		var ps persistedScore
+		if err == nil {
+			err = json.Unmarshal(result.Value, &ps)
+		}
+		if err != nil {
+			s.logger.Error("skipping invalid persisted peer score", "key", result.Key, "error", err)
+			expired = append(expired, key)
+			continue
+		}
+		if elapsed := now.Sub(ps.UpdatedAt); elapsed > 0 {
+			ps.Score *= math.Pow(scoreDecay, float64(elapsed/scoreDecayInterval))
+		}
+		banned := now.Before(ps.BannedUntil)
+		if !banned && math.Abs(ps.Score) < minScore {
+			expired = append(expired, key)
+			continue
+		}
+		s.scores[id] = ps.Score
+		if banned {
+			s.bans[id] = ps.BannedUntil
+		}
+		loaded[id] = ps.Score
+	}
+	s.mtx.Unlock()
+
+	for id, score := range loaded {
+		s.updateScore(id, score)
+	}
+	for _, key := range expired {
+		if err := ds.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	s.logger.Info("loaded persisted peer scores", "peers", len(loaded), "expired", len(expired))
+	return nil
+}
+
+// persist saves scores and bans of given peers in the datastore, or deletes them if peers are forgotten.
+// It does nothing until persisted scores are loaded.
+func (s *peerScorer) persist(ids ...peer.ID) {
+	s.mtx.Lock()
+	ds := s.ds
+	entries := make(map[peer.ID]*persistedScore, len(ids))
+	for _, id := range ids {
+		score, scored := s.scores[id]
+		until, banned := s.bans[id]
+		if !scored && !banned {
+			entries[id] = nil
+			continue
+		}
+		entries[id] = &persistedScore{Score: score, BannedUntil: until, UpdatedAt: s.now()}
+	}
+	s.mtx.Unlock()
+	if ds == nil {
+		return
+	}
+
+	ctx := context.Background()
+	for id, ps := range entries {
+		key := peerScoresKey.ChildString(id.String())
+		var err error
+		if ps == nil {
+			err = ds.Delete(ctx, key)
+		} else {
+			var value []byte
+			if value, err = json.Marshal(ps); err == nil {
+				err = ds.Put(ctx, key, value)
+			}
+		}
+		if err != nil {
+			s.logger.Error("failed to persist peer score", "peer", id, "error", err)
+		}
+	}
+}
+
+// persistAll saves scores and bans of all known peers in the datastore.
+func (s *peerScorer) persistAll() {
+	s.persist(s.knownPeers()...)
+}
+
+// knownPeers returns peers with non-zero score or active ban.
+func (s *peerScorer) knownPeers() []peer.ID {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	ids := make([]peer.ID, 0, len(s.scores))
+	for id := range s.scores {
+		ids = append(ids, id)
+	}
+	for id := range s.bans {
+		if _, ok := s.scores[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// clear forgets score and ban of the peer (or of all peers, if id is empty), also in the datastore. It returns
+// the number of forgotten peers.
+func (s *peerScorer) clear(id peer.ID) int {
+	ids := []peer.ID{id}
+	if id == "" {
+		ids = s.knownPeers()
+	}
+	s.mtx.Lock()
+	cleared := make([]peer.ID, 0, len(ids))
+	for _, id := range ids {
+		_, scored := s.scores[id]
+		_, banned := s.bans[id]
+		if !scored && !banned {
+			continue
+		}
+		delete(s.scores, id)
+		delete(s.bans, id)
+		cleared = append(cleared, id)
+	}
+	s.mtx.Unlock()
+
+	for _, id := range cleared {
+		s.updateScore(id, 0)
+		s.logger.Info("peer score cleared", "peer", id)
+	}
+	s.persist(cleared...)
+	return len(cleared)
+}
@@ -0,0 +1,214 @@
+package p2p
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+const (
+	// txPenalty is subtracted from score of a peer relaying transaction rejected by the mempool.
+	// It's low, because transaction valid at the time of relaying may be rejected later.
+	txPenalty = 5
+	// fraudProofPenalty is subtracted from score of a peer relaying invalid (or excessive) fraud proof.
+	fraudProofPenalty = 20
+	// evidencePenalty is subtracted from score of a peer relaying invalid (or excessive) evidence.
+	evidencePenalty = 20
+	// inclusionListPenalty is subtracted from score of a peer relaying invalid (or excessive) inclusion list.
+	inclusionListPenalty = 20
+	// historyPenalty is subtracted from score of a peer serving historical data that fails verification.
+	historyPenalty = 50
+	// defaultPenalty is subtracted from score of a peer relaying invalid message in other topics (headers, blocks).
+	defaultPenalty = 50
+
+	// scoreDecayInterval defines how often scores decay and expired bans are lifted.
+	scoreDecayInterval = 1 * time.Minute
+	// scoreDecay is the factor applied to scores every scoreDecayInterval, so peers recover from penalties over time.
+	scoreDecay = 0.9
+	// minScore is the absolute value of a score, below which peer is forgotten.
+	minScore = 0.1
+)
+
+// PeerScore describes reputation of a peer, based on messages received from it.
+type PeerScore struct {
+	ID          peer.ID    `json:"id"`
+	Score       float64    `json:"score"`
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
+}
+
+// peerScorer keeps scores of peers relaying invalid messages, and bans peers with score below the threshold.
+//
+// It's registered as pubsub tracer, to be notified about messages rejected by topic validators.
+type peerScorer struct {
+	nopTracer
+
+	threshold   float64
+	banDuration time.Duration
+	penalties   map[string]float64
+
+	mtx    sync.Mutex
+	scores map[peer.ID]float64
+	bans   map[peer.ID]time.Time
+	now    func() time.Time
+	// ds persists scores and bans across restarts, nil until persisted scores are loaded
+	ds datastore.Datastore
+
+	// disconnect closes connections to banned peer
+	disconnect func(peer.ID)
+	// updateScore is notified about changes of peer scores
+	updateScore func(peer.ID, float64)
+	logger      log.Logger
+}
+
+var _ pubsub.RawTracer = &peerScorer{}
+
+func newPeerScorer(threshold float64, banDuration time.Duration, logger log.Logger) *peerScorer {
+	return &peerScorer{
+		threshold:   threshold,
+		banDuration: banDuration,
+		penalties:   make(map[string]float64),
+		scores:      make(map[peer.ID]float64),
+		bans:        make(map[peer.ID]time.Time),
+		now:         time.Now,
+		disconnect:  func(peer.ID) {},
+		updateScore: func(peer.ID, float64) {},
+		logger:      logger,
+	}
+}
+
+// setPenalty sets penalty for invalid messages in given topic.
+func (s *peerScorer) setPenalty(topic string, penalty float64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.penalties[topic] = penalty
+}
+
+// penalize subtracts penalty from the score of the peer. Peer is banned if its score drops below the threshold.
+// Zero threshold disables banning.
+func (s *peerScorer) penalize(id peer.ID, penalty float64, reason string) {
+	s.mtx.Lock()
+	s.scores[id] -= penalty
+	score := s.scores[id]
+	ban := s.threshold != 0 && score < s.threshold && !s.isBannedLocked(id)
+	if ban {
+		s.bans[id] = s.now().Add(s.banDuration)
+	}
+	s.mtx.Unlock()
+
+	s.updateScore(id, score)
+	s.logger.Debug("peer penalized", "peer", id, "reason", reason, "score", score)
+	if ban {
+		s.logger.Info("banning peer", "peer", id, "score", score, "duration", s.banDuration)
+		s.disconnect(id)
+		// other scores are persisted on decay
+		s.persist(id)
+	}
+}
+
+// isBanned returns true if peer is currently banned.
+func (s *peerScorer) isBanned(id peer.ID) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.isBannedLocked(id)
+}
+
+func (s *peerScorer) isBannedLocked(id peer.ID) bool {
+	until, ok := s.bans[id]
+	return ok && s.now().Before(until)
+}
+
+// peerScores returns scores of all peers with non-zero score or active ban, sorted by score.
+func (s *peerScorer) peerScores() []PeerScore {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	res := make([]PeerScore, 0, len(s.scores))
+	for id, score := range s.scores {
+		ps := PeerScore{ID: id, Score: score}
+		if s.isBannedLocked(id) {
+			until := s.bans[id]
+			ps.BannedUntil = &until
+		}
+		res = append(res, ps)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Score < res[j].Score
+	})
+	return res
+}
+
+// decay reduces penalties of all peers and lifts expired bans. Updated scores are persisted.
+func (s *peerScorer) decay() {
+	s.mtx.Lock()
+	var forgotten []peer.ID
+	for id, until := range s.bans {
+		if !s.now().Before(until) {
+			delete(s.bans, id)
+			s.logger.Info("peer ban expired", "peer", id)
+		}
+	}
+	for id, score := range s.scores {
+		score *= scoreDecay
+		if score > -minScore && score < minScore && !s.isBannedLocked(id) {
+			delete(s.scores, id)
+			s.updateScore(id, 0)
+			forgotten = append(forgotten, id)
+			continue
+		}
+		s.scores[id] = score
+		s.updateScore(id, score)
+	}
+	s.mtx.Unlock()
+	s.persist(append(forgotten, s.knownPeers()...)...)
+}
+
+func (s *peerScorer) run(ctx context.Context) {
+	ticker := time.NewTicker(scoreDecayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.decay()
+		}
+	}
+}
+
+// RejectMessage penalizes peer that relayed message rejected by topic validator.
+func (s *peerScorer) RejectMessage(msg *pubsub.Message, reason string) {
+	if reason != pubsub.RejectValidationFailed && reason != pubsub.RejectInvalidSignature {
+		return
+	}
+	s.mtx.Lock()
+	penalty, ok := s.penalties[msg.GetTopic()]
+	s.mtx.Unlock()
+	if !ok {
+		penalty = defaultPenalty
+	}
+	s.penalize(msg.ReceivedFrom, penalty, reason+" in topic "+msg.GetTopic())
+}
+
+// banGater extends connection gater with rejection of connections with banned peers.
+type banGater struct {
+	*conngater.BasicConnectionGater
+	scorer *peerScorer
+}
+
+// InterceptPeerDial rejects dialing banned peers.
+func (g *banGater) InterceptPeerDial(p peer.ID) bool {
+	return !g.scorer.isBanned(p) && g.BasicConnectionGater.InterceptPeerDial(p)
+}
+
+// InterceptSecured rejects connections with banned peers.
+func (g *banGater) InterceptSecured(dir network.Direction, p peer.ID, addrs network.ConnMultiaddrs) bool {
+	return !g.scorer.isBanned(p) && g.BasicConnectionGater.InterceptSecured(dir, p, addrs)
+}
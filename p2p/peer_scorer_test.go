@@ -0,0 +1,141 @@
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestPeerScorer(t *testing.T) {
+	assert := assert.New(t)
+
+	scorer := newPeerScorer(-100, time.Hour, test.NewFileLogger(t))
+	now := time.Now()
+	scorer.now = func() time.Time { return now }
+	var disconnected []peer.ID
+	scorer.disconnect = func(id peer.ID) { disconnected = append(disconnected, id) }
+	scorer.setPenalty("tx", txPenalty)
+
+	message := func(topic string, from peer.ID) *pubsub.Message {
+		return &pubsub.Message{Message: &pb.Message{Topic: &topic}, ReceivedFrom: from}
+	}
+
+	// ignored messages are not penalized
+	scorer.RejectMessage(message("tx", "a"), pubsub.RejectValidationIgnored)
+	assert.Empty(scorer.peerScores())
+
+	scorer.RejectMessage(message("tx", "a"), pubsub.RejectValidationFailed)
+	scorer.RejectMessage(message("headers", "b"), pubsub.RejectValidationFailed)
+	scorer.RejectMessage(message("headers", "b"), pubsub.RejectValidationFailed)
+	assert.False(scorer.isBanned("a"))
+	assert.False(scorer.isBanned("b"))
+	assert.Empty(disconnected)
+
+	// peer with score below the threshold is banned and disconnected
+	scorer.RejectMessage(message("headers", "b"), pubsub.RejectValidationFailed)
+	assert.True(scorer.isBanned("b"))
+	assert.Equal([]peer.ID{"b"}, disconnected)
+
+	scores := scorer.peerScores()
+	assert.Len(scores, 2)
+	assert.Equal(peer.ID("b"), scores[0].ID)
+	assert.Equal(-3.0*defaultPenalty, scores[0].Score)
+	assert.NotNil(scores[0].BannedUntil)
+	assert.Equal(peer.ID("a"), scores[1].ID)
+	assert.Equal(-1.0*txPenalty, scores[1].Score)
+	assert.Nil(scores[1].BannedUntil)
+
+	// scores decay and bans expire
+	now = now.Add(time.Hour)
+	scorer.decay()
+	assert.False(scorer.isBanned("b"))
+	scores = scorer.peerScores()
+	assert.Equal(-3.0*defaultPenalty*scoreDecay, scores[0].Score)
+	for i := 0; i < 100; i++ {
+		scorer.decay()
+	}
+	assert.Empty(scorer.peerScores())
+
+	// zero threshold disables banning
+	scorer = newPeerScorer(0, time.Hour, test.NewFileLogger(t))
+	for i := 0; i < 10; i++ {
+		scorer.penalize("c", defaultPenalty, "test")
+	}
+	assert.False(scorer.isBanned("c"))
+}
+
+func TestPeerScorerPersistence(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	ds := datastore.NewMapDatastore()
+	ids := make([]peer.ID, 2)
+	for i := range ids {
+		privKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		ids[i], err = peer.IDFromPrivateKey(privKey)
+		require.NoError(err)
+	}
+	bad, suspicious := ids[0], ids[1]
+
+	start := time.Now()
+	newScorer := func(now time.Time) *peerScorer {
+		scorer := newPeerScorer(-100, time.Hour, test.NewFileLogger(t))
+		scorer.now = func() time.Time { return now }
+		require.NoError(scorer.load(ctx, ds))
+		return scorer
+	}
+	persisted := func() int {
+		results, err := ds.Query(ctx, dsq.Query{Prefix: peerScoresKey.String()})
+		require.NoError(err)
+		entries, err := results.Rest()
+		require.NoError(err)
+		return len(entries)
+	}
+
+	scorer := newScorer(start)
+	for i := 0; i < 3; i++ {
+		scorer.penalize(bad, defaultPenalty, "test")
+	}
+	// bans are persisted immediately
+	assert.Equal(1, persisted())
+	scorer.penalize(suspicious, txPenalty, "test")
+	scorer.persistAll()
+	assert.Equal(2, persisted())
+
+	// ban survives restart, and scores decay for the downtime
+	restarted := newScorer(start.Add(10 * time.Minute))
+	assert.True(restarted.isBanned(bad))
+	assert.False(restarted.isBanned(suspicious))
+	scores := restarted.peerScores()
+	require.Len(scores, 2)
+	assert.InDelta(-3.0*defaultPenalty*math.Pow(scoreDecay, 10), scores[0].Score, 1e-9)
+	assert.InDelta(-1.0*txPenalty*math.Pow(scoreDecay, 10), scores[1].Score, 1e-9)
+
+	// expired entries are deleted
+	assert.Empty(newScorer(start.Add(3 * time.Hour)).peerScores())
+	assert.Equal(0, persisted())
+
+	restarted.persistAll()
+	assert.Equal(2, persisted())
+	assert.Equal(1, restarted.clear(bad))
+	assert.False(restarted.isBanned(bad))
+	assert.Equal(1, persisted())
+	assert.Equal(0, restarted.clear(bad))
+	assert.Equal(1, restarted.clear(""))
+	assert.Empty(restarted.peerScores())
+	assert.Equal(0, persisted())
+}
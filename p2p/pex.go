@@ -0,0 +1,164 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// pexProtocolSuffix is added after namespace to create ID of peer exchange protocol.
+	pexProtocolSuffix = "/pex/1.0.0"
+
+	// pexInterval defines how often peers are requested from connected peers.
+	pexInterval = 30 * time.Second
+	// pexRequestPeers is the number of connected peers asked for their peers in every round.
+	pexRequestPeers = 3
+	// pexMaxPeers limits the number of peers returned in a single response.
+	pexMaxPeers = 32
+	// pexTimeout limits duration of a single peer exchange.
+	pexTimeout = 10 * time.Second
+	// pexMaxResponseSize limits the size of a response read from a peer.
+	pexMaxResponseSize = 64 * 1024
+
+	// persistentPeerCheckInterval defines how often connection with persistent peers is checked.
+	persistentPeerCheckInterval = 5 * time.Second
+	// persistentPeerMaxBackoff is the maximal delay between attempts to reconnect with persistent peer.
+	persistentPeerMaxBackoff = 5 * time.Minute
+	// persistentPeerTag is used to protect connections with persistent peers from being pruned.
+	persistentPeerTag = "rollkit-persistent"
+)
+
+// setupPeerExchange starts serving peers to other nodes and requesting peers from them.
+func (c *Client) setupPeerExchange(ctx context.Context) {
+	c.host.SetStreamHandler(c.getPEXProtocol(), c.handlePEX)
+	go c.peerExchangeLoop(ctx)
+}
+
+// handlePEX responds with addresses of connected peers, excluding the requester.
+func (c *Client) handlePEX(s network.Stream) {
+	defer s.Close() //nolint:errcheck
+	_ = s.SetDeadline(time.Now().Add(pexTimeout))
+
+	peers := make([]peer.AddrInfo, 0, pexMaxPeers)
+	for _, id := range shuffle(c.host.Network().Peers()) {
+		if len(peers) == pexMaxPeers {
+			break
+		}
+		if id == s.Conn().RemotePeer() {
+			continue
+		}
+		info := c.host.Peerstore().PeerInfo(id)
+		if len(info.Addrs) > 0 {
+			peers = append(peers, info)
+		}
+	}
+	if err := json.NewEncoder(s).Encode(peers); err != nil {
+		c.logger.Debug("failed to send peers", "peer", s.Conn().RemotePeer(), "error", err)
+		_ = s.Reset()
+	}
+}
+
+// requestPeers asks given peer for addresses of its peers.
+func (c *Client) requestPeers(ctx context.Context, id peer.ID) ([]peer.AddrInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, pexTimeout)
+	defer cancel()
+	s, err := c.host.NewStream(ctx, id, c.getPEXProtocol())
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close() //nolint:errcheck
+	_ = s.SetDeadline(time.Now().Add(pexTimeout))
+
+	var peers []peer.AddrInfo
+	if err := json.NewDecoder(io.LimitReader(s, pexMaxResponseSize)).Decode(&peers); err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	if len(peers) > pexMaxPeers {
+		peers = peers[:pexMaxPeers]
+	}
+	return peers, nil
+}
+
+func (c *Client) peerExchangeLoop(ctx context.Context) {
+	ticker := time.NewTicker(pexInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		connected := c.host.Network().Peers()
+		if len(connected) >= peerLimit {
+			continue
+		}
+		for i, id := range shuffle(connected) {
+			if i == pexRequestPeers {
+				break
+			}
+			peers, err := c.requestPeers(ctx, id)
+			if err != nil {
+				c.logger.Debug("failed to request peers", "peer", id, "error", err)
+				continue
+			}
+			for _, p := range peers {
+				if p.ID == c.host.ID() || c.scorer.isBanned(p.ID) || c.host.Network().Connectedness(p.ID) == network.Connected {
+					continue
+				}
+				go c.tryConnect(ctx, p)
+			}
+		}
+	}
+}
+
+// maintainPersistentPeer keeps connection with the peer, reconnecting with exponential backoff.
+func (c *Client) maintainPersistentPeer(ctx context.Context, p peer.AddrInfo) {
+	c.host.ConnManager().Protect(p.ID, persistentPeerTag)
+	backoff := persistentPeerCheckInterval
+	for {
+		wait := persistentPeerCheckInterval
+		if c.host.Network().Connectedness(p.ID) != network.Connected {
+			if err := c.host.Connect(ctx, p); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				c.metrics.ConnectionFailures.Add(1)
+				c.logger.Info("failed to connect to persistent peer", "peer", p.ID, "retry in", backoff, "error", err)
+				wait = backoff
+				backoff *= 2
+				if backoff > persistentPeerMaxBackoff {
+					backoff = persistentPeerMaxBackoff
+				}
+			} else {
+				c.logger.Info("connected to persistent peer", "peer", p.ID)
+				backoff = persistentPeerCheckInterval
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) getPEXProtocol() protocol.ID {
+	return protocol.ID("/" + c.getNamespace() + pexProtocolSuffix)
+}
+
+func shuffle(peers []peer.ID) []peer.ID {
+	shuffled := make([]peer.ID, len(peers))
+	copy(shuffled, peers)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
@@ -0,0 +1,54 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestPeerExchange(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mnet, err := mocknet.FullMeshLinked(3)
+	require.NoError(err)
+	hosts := mnet.Hosts()
+
+	logger := test.NewFileLogger(t)
+	clients := make([]*Client, len(hosts))
+	for i, h := range hosts {
+		clients[i] = &Client{host: h, chainID: "pex", logger: logger, scorer: newPeerScorer(0, time.Hour, logger)}
+	}
+	hosts[1].SetStreamHandler(clients[1].getPEXProtocol(), clients[1].handlePEX)
+
+	_, err = mnet.ConnectPeers(hosts[0].ID(), hosts[1].ID())
+	require.NoError(err)
+	_, err = mnet.ConnectPeers(hosts[1].ID(), hosts[2].ID())
+	require.NoError(err)
+	// mocknet doesn't add addresses of connected peers to the peerstore, identify does it asynchronously
+	hosts[1].Peerstore().AddAddrs(hosts[2].ID(), hosts[2].Addrs(), peerstore.PermanentAddrTTL)
+
+	peers, err := clients[0].requestPeers(ctx, hosts[1].ID())
+	require.NoError(err)
+	ids := make([]peer.ID, 0, len(peers))
+	for _, p := range peers {
+		ids = append(ids, p.ID)
+		assert.NotEmpty(p.Addrs)
+	}
+	assert.Equal([]peer.ID{hosts[2].ID()}, ids)
+
+	// peers not supporting peer exchange return an error
+	_, err = clients[1].requestPeers(ctx, hosts[2].ID())
+	assert.Error(err)
+}
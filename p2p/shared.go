@@ -0,0 +1,65 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+// NewSharedClient creates Client of another rollup (chain), sharing libp2p host, gossipsub, DHT, connection gater
+// and peer scores with the base client, so that multiple rollups can run in one process with a single P2P identity.
+// Gossip topics and discovery namespace of the client are derived from its chain ID.
+//
+// The base client has to be started before, and closed after, all clients sharing it.
+func NewSharedClient(base *Client, chainID string, logger log.Logger, metrics *Metrics) *Client {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	c := &Client{
+		conf:      base.conf,
+		gater:     base.gater,
+		scorer:    base.scorer,
+		limiter:   base.limiter,
+		bandwidth: base.bandwidth,
+		privKey:   base.privKey,
+		chainID:   chainID,
+		base:      base,
+		logger:    logger,
+		metrics:   metrics,
+	}
+	c.scorer.setPenalty(c.getTxTopic(), txPenalty)
+	c.scorer.setPenalty(c.getFraudProofTopic(), fraudProofPenalty)
+	c.scorer.setPenalty(c.getEvidenceTopic(), evidencePenalty)
+	c.scorer.setPenalty(c.getInclusionListTopic(), inclusionListPenalty)
+	return c
+}
+
+// startShared sets up gossiping and peer discovery of the rollup, using host of the base client.
+func (c *Client) startShared(ctx context.Context) error {
+	if c.base.host == nil || c.base.disc == nil {
+		return errors.New("base P2P client is not started")
+	}
+	c.host = c.base.host
+	c.dht = c.base.dht
+	c.ps = c.base.ps
+	c.disc = c.base.disc
+	c.throttle = c.base.throttle
+
+	c.logger.Debug("setting up gossiping")
+	if err := c.setupGossipers(ctx); err != nil {
+		return err
+	}
+	c.setupHistory()
+
+	c.logger.Debug("setting up handshakes")
+	if err := c.setupHandshake(ctx); err != nil {
+		return err
+	}
+
+	c.logger.Debug("setting up active peer discovery")
+	if err := c.advertise(ctx); err != nil {
+		return err
+	}
+	return c.findPeers(ctx)
+}
@@ -0,0 +1,141 @@
+package p2p
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// maxThrottledPeers limits the number of per-peer buckets kept by gossipThrottle. When it's reached, buckets of
+// peers that didn't send anything for a while (i.e. full buckets) are dropped.
+const maxThrottledPeers = 1000
+
+// byteBucket is a token bucket of bytes, refilled at rate bytes per second, up to a second worth of bytes.
+// Message is accepted if the bucket isn't empty, so messages larger than the bucket still pass, at the average rate.
+type byteBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newByteBucket(rate uint64, now time.Time) *byteBucket {
+	return &byteBucket{rate: float64(rate), tokens: float64(rate), last: now}
+}
+
+func (b *byteBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+func (b *byteBucket) empty() bool {
+	return b.tokens <= 0
+}
+
+func (b *byteBucket) full() bool {
+	return b.tokens >= b.rate
+}
+
+// gossipThrottle limits bandwidth of gossiped messages per topic (e.g. blocks vs headers vs transactions) and per
+// relaying peer, so a single misbehaving peer or a flood of large blocks can't starve other traffic. Messages above
+// the limits are ignored: they are neither delivered nor relayed, but (unlike invalid messages) don't penalize
+// the peer. Messages published by the node itself are never throttled.
+type gossipThrottle struct {
+	self     peer.ID
+	peerRate uint64
+	metrics  *Metrics
+
+	mtx        sync.Mutex
+	topicRates map[string]uint64
+	topics     map[string]*byteBucket
+	peers      map[peer.ID]*byteBucket
+	now        func() time.Time
+}
+
+func newGossipThrottle(self peer.ID, peerRate uint64, metrics *Metrics) *gossipThrottle {
+	return &gossipThrottle{
+		self:       self,
+		peerRate:   peerRate,
+		metrics:    metrics,
+		topicRates: make(map[string]uint64),
+		topics:     make(map[string]*byteBucket),
+		peers:      make(map[peer.ID]*byteBucket),
+		now:        time.Now,
+	}
+}
+
+// setTopicRate limits bandwidth of messages in the topic to rate bytes per second. Zero disables the limit.
+func (t *gossipThrottle) setTopicRate(topic string, rate uint64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.topics, topic)
+	if rate == 0 {
+		delete(t.topicRates, topic)
+		return
+	}
+	t.topicRates[topic] = rate
+}
+
+// validate implements pubsub.ValidatorEx, it's registered as default validator of all topics.
+func (t *gossipThrottle) validate(_ context.Context, from peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	if from == t.self || t.accept(from, msg.GetTopic(), len(msg.Data)) {
+		return pubsub.ValidationAccept
+	}
+	t.metrics.MessagesThrottled.With("topic", msg.GetTopic()).Add(1)
+	return pubsub.ValidationIgnore
+}
+
+// accept returns true if neither the peer nor the topic exceeded their limits, and accounts size of the message.
+func (t *gossipThrottle) accept(from peer.ID, topic string, size int) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	now := t.now()
+
+	var peerBucket, topicBucket *byteBucket
+	if t.peerRate > 0 {
+		peerBucket = t.peers[from]
+		if peerBucket == nil {
+			if len(t.peers) >= maxThrottledPeers {
+				t.prunePeers(now)
+			}
+			peerBucket = newByteBucket(t.peerRate, now)
+			t.peers[from] = peerBucket
+		}
+		peerBucket.refill(now)
+		if peerBucket.empty() {
+			return false
+		}
+	}
+	if rate, ok := t.topicRates[topic]; ok {
+		topicBucket = t.topics[topic]
+		if topicBucket == nil {
+			topicBucket = newByteBucket(rate, now)
+			t.topics[topic] = topicBucket
+		}
+		topicBucket.refill(now)
+		if topicBucket.empty() {
+			return false
+		}
+	}
+
+	// bandwidth is accounted only for accepted messages, so a flooding peer doesn't exhaust limits of topics
+	if peerBucket != nil {
+		peerBucket.tokens -= float64(size)
+	}
+	if topicBucket != nil {
+		topicBucket.tokens -= float64(size)
+	}
+	return true
+}
+
+func (t *gossipThrottle) prunePeers(now time.Time) {
+	for id, b := range t.peers {
+		b.refill(now)
+		if b.full() {
+			delete(t.peers, id)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGossipThrottle(t *testing.T) {
+	assert := assert.New(t)
+
+	throttle := newGossipThrottle(peer.ID("self"), 1000, NopMetrics())
+	throttle.setTopicRate("blocks", 3000)
+	throttle.setTopicRate("txs", 0)
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+
+	// peer exceeding its limit is throttled, other peers are not
+	assert.True(throttle.accept(peer.ID("a"), "txs", 600))
+	assert.True(throttle.accept(peer.ID("a"), "txs", 600))
+	assert.False(throttle.accept(peer.ID("a"), "txs", 1))
+	assert.True(throttle.accept(peer.ID("b"), "txs", 600))
+
+	// message larger than the limit passes, at the average rate
+	now = now.Add(time.Second)
+	assert.True(throttle.accept(peer.ID("c"), "blocks", 2500))
+	assert.False(throttle.accept(peer.ID("c"), "blocks", 1))
+	now = now.Add(time.Second)
+	assert.False(throttle.accept(peer.ID("c"), "blocks", 1))
+	now = now.Add(time.Second)
+	assert.True(throttle.accept(peer.ID("c"), "blocks", 1))
+
+	// topic limit is shared by all peers, and not consumed by throttled peers
+	assert.True(throttle.accept(peer.ID("c"), "blocks", 1000))
+	assert.False(throttle.accept(peer.ID("c"), "blocks", 1000))
+	assert.True(throttle.accept(peer.ID("d"), "blocks", 1000))
+	assert.True(throttle.accept(peer.ID("e"), "blocks", 1000))
+	assert.False(throttle.accept(peer.ID("f"), "blocks", 1))
+	assert.True(throttle.accept(peer.ID("f"), "txs", 1))
+
+	// messages published by the node itself are never throttled
+	topic := "blocks"
+	msg := &pubsub.Message{Message: &pb.Message{Data: make([]byte, 10000), Topic: &topic}}
+	assert.Equal(pubsub.ValidationIgnore, throttle.validate(context.Background(), peer.ID("h"), msg))
+	assert.Equal(pubsub.ValidationAccept, throttle.validate(context.Background(), peer.ID("self"), msg))
+}
@@ -0,0 +1,73 @@
+package p2p
+
+import (
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// nopTracer implements pubsub.RawTracer with no-op methods. It's embedded by tracers interested only in some events.
+type nopTracer struct{}
+
+var _ pubsub.RawTracer = nopTracer{}
+
+// AddPeer implements pubsub.RawTracer.
+func (nopTracer) AddPeer(peer.ID, protocol.ID) {}
+
+// RemovePeer implements pubsub.RawTracer.
+func (nopTracer) RemovePeer(peer.ID) {}
+
+// Join implements pubsub.RawTracer.
+func (nopTracer) Join(string) {}
+
+// Leave implements pubsub.RawTracer.
+func (nopTracer) Leave(string) {}
+
+// Graft implements pubsub.RawTracer.
+func (nopTracer) Graft(peer.ID, string) {}
+
+// Prune implements pubsub.RawTracer.
+func (nopTracer) Prune(peer.ID, string) {}
+
+// ValidateMessage implements pubsub.RawTracer.
+func (nopTracer) ValidateMessage(*pubsub.Message) {}
+
+// DeliverMessage implements pubsub.RawTracer.
+func (nopTracer) DeliverMessage(*pubsub.Message) {}
+
+// RejectMessage implements pubsub.RawTracer.
+func (nopTracer) RejectMessage(*pubsub.Message, string) {}
+
+// DuplicateMessage implements pubsub.RawTracer.
+func (nopTracer) DuplicateMessage(*pubsub.Message) {}
+
+// ThrottlePeer implements pubsub.RawTracer.
+func (nopTracer) ThrottlePeer(peer.ID) {}
+
+// RecvRPC implements pubsub.RawTracer.
+func (nopTracer) RecvRPC(*pubsub.RPC) {}
+
+// SendRPC implements pubsub.RawTracer.
+func (nopTracer) SendRPC(*pubsub.RPC, peer.ID) {}
+
+// DropRPC implements pubsub.RawTracer.
+func (nopTracer) DropRPC(*pubsub.RPC, peer.ID) {}
+
+// UndeliverableMessage implements pubsub.RawTracer.
+func (nopTracer) UndeliverableMessage(*pubsub.Message) {}
+
+// metricsTracer counts received and rejected gossiped messages per topic.
+type metricsTracer struct {
+	nopTracer
+	metrics *Metrics
+}
+
+// ValidateMessage is called for every received message, before validation.
+func (t metricsTracer) ValidateMessage(msg *pubsub.Message) {
+	t.metrics.MessagesReceived.With("topic", msg.GetTopic()).Add(1)
+}
+
+// RejectMessage is called for every message rejected by validators (or dropped before validation).
+func (t metricsTracer) RejectMessage(msg *pubsub.Message, _ string) {
+	t.metrics.MessagesRejected.With("topic", msg.GetTopic()).Add(1)
+}
@@ -4,4 +4,7 @@ set -eo pipefail
 
 buf generate --path="./proto/dalc" --template="buf.gen.yaml" --config="buf.yaml"
 buf generate --path="./proto/rollkit" --template="buf.gen.yaml" --config="buf.yaml"
+buf generate --path="./proto/rpc" --template="buf.gen.yaml" --config="buf.yaml"
+buf generate --path="./proto/signer" --template="buf.gen.yaml" --config="buf.yaml"
+buf generate --path="./proto/sequencing" --template="buf.gen.yaml" --config="buf.yaml"
 buf generate --path="./proto/tendermint/abci" --template="buf.gen.yaml" --config="buf.yaml"
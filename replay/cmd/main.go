@@ -0,0 +1,13 @@
+package main
+
+import (
+	"os"
+
+	"github.com/rollkit/rollkit/replay"
+)
+
+func main() {
+	if err := replay.NewCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
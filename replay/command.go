@@ -0,0 +1,121 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cmcfg "github.com/cometbft/cometbft/config"
+	cmlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/types"
+)
+
+const (
+	flagHome        = "home"
+	flagProxyApp    = "proxy_app"
+	flagABCI        = "abci"
+	flagToHeight    = "to_height"
+	flagDAEndHeight = "da_end_height"
+)
+
+// NewCommand returns the replay command. It re-executes the chain of a stopped node, with genesis and data in the home
+// directory, against a fresh instance of the ABCI application listening at the proxy_app address. Blocks are read from
+// the local store, or retrieved from DA heights [rollkit.da_start_height, da_end_height] if da_end_height is set; the DA
+// layer is configured with Rollkit flags (see config.AddFlags).
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Re-execute the chain against a fresh application instance, verifying app hashes",
+		Args:  cobra.NoArgs,
+		RunE:  runReplay,
+	}
+	home, _ := os.UserHomeDir()
+	cmd.Flags().String(flagHome, filepath.Join(home, cmcfg.DefaultTendermintDir), "directory with the config and data of the node")
+	cmd.Flags().String(flagProxyApp, "tcp://127.0.0.1:26658", "address of the ABCI application")
+	cmd.Flags().String(flagABCI, "socket", "transport of the ABCI application (socket or grpc)")
+	cmd.Flags().Uint64(flagToHeight, 0, "height of the last replayed block (0 means the height of the store)")
+	cmd.Flags().Uint64(flagDAEndHeight, 0, "last DA height to retrieve blocks from (0 means blocks are read from the local store)")
+	config.AddFlags(cmd)
+	return cmd
+}
+
+func runReplay(cmd *cobra.Command, _ []string) error {
+	v := viper.New()
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return err
+	}
+	nodeConfig := config.DefaultNodeConfig
+	if err := nodeConfig.GetViperConfig(v); err != nil {
+		return err
+	}
+	cmConfig := cmcfg.DefaultConfig().SetRoot(v.GetString(flagHome))
+	config.GetNodeConfig(&nodeConfig, cmConfig)
+	genesis, err := cmtypes.GenesisDocFromFile(cmConfig.GenesisFile())
+	if err != nil {
+		return fmt.Errorf("failed to read genesis: %w", err)
+	}
+	dataHasher, err := types.DataHasherFromGenesis(genesis)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	logger := cmlog.NewTMLogger(cmlog.NewSyncWriter(cmd.ErrOrStderr()))
+	blockStore, kv, err := node.OpenStore(ctx, nodeConfig, logger)
+	if err != nil {
+		return fmt.Errorf("failed to open the store: %w", err)
+	}
+	defer func() { _ = kv.Close() }()
+
+	var source BlockSource = NewStoreBlockSource(blockStore)
+	if daEndHeight := v.GetUint64(flagDAEndHeight); daEndHeight > 0 {
+		dalc, err := node.OpenDALC(nodeConfig, genesis, kv, logger.With("module", "da_client"))
+		if err != nil {
+			return err
+		}
+		retriever, ok := dalc.(da.BlockRetriever)
+		if !ok {
+			return errors.New("data availability layer client doesn't support block retrieval")
+		}
+		if err := dalc.Start(); err != nil {
+			return fmt.Errorf("failed to start data availability layer client: %w", err)
+		}
+		defer func() { _ = dalc.Stop() }()
+		source = NewDABlockSource(retriever, nodeConfig.DAStartHeight, daEndHeight)
+	}
+
+	proxyApp := proxy.NewAppConns(proxy.NewRemoteClientCreator(v.GetString(flagProxyApp), v.GetString(flagABCI), true), proxy.NopMetrics())
+	proxyApp.SetLogger(logger.With("module", "proxy"))
+	if err := proxyApp.Start(); err != nil {
+		return fmt.Errorf("failed to connect to the application: %w", err)
+	}
+	defer func() { _ = proxyApp.Stop() }()
+
+	to := v.GetUint64(flagToHeight)
+	if to == 0 {
+		to = blockStore.Height()
+	}
+	replayer, err := NewReplayer(genesis, dataHasher, source, proxyApp, logger.With("module", "replay"))
+	if err != nil {
+		return err
+	}
+	s, err := replayer.InitChain()
+	if err != nil {
+		return err
+	}
+	s, err = replayer.Replay(ctx, s, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "replayed blocks up to height %d, app hash %X\n", s.LastBlockHeight, s.AppHash)
+	return nil
+}
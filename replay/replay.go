@@ -0,0 +1,171 @@
+// Package replay re-executes the chain from the local store or from the DA layer against a fresh application
+// instance, verifying app hashes at each height.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	llcfg "github.com/cometbft/cometbft/config"
+	cmlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/da"
+	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// BlockSource provides blocks for chain replay.
+type BlockSource interface {
+	// GetBlock returns block at given height.
+	GetBlock(ctx context.Context, height uint64) (*types.Block, error)
+}
+
+// StoreBlockSource reads blocks from the local store.
+type StoreBlockSource struct {
+	store store.Store
+}
+
+var _ BlockSource = &StoreBlockSource{}
+
+// NewStoreBlockSource creates new instance of StoreBlockSource.
+func NewStoreBlockSource(store store.Store) *StoreBlockSource {
+	return &StoreBlockSource{store: store}
+}
+
+// GetBlock loads block at given height from the store.
+func (s *StoreBlockSource) GetBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	return s.store.LoadBlock(height)
+}
+
+// DABlockSource reads blocks from the data availability layer.
+//
+// Blocks are retrieved sequentially from DA heights in the range [startDAHeight, endDAHeight],
+// so they have to be requested in increasing order of height.
+type DABlockSource struct {
+	retriever   da.BlockRetriever
+	daHeight    uint64
+	endDAHeight uint64
+	blocks      map[uint64]*types.Block
+}
+
+var _ BlockSource = &DABlockSource{}
+
+// NewDABlockSource creates new instance of DABlockSource.
+func NewDABlockSource(retriever da.BlockRetriever, startDAHeight, endDAHeight uint64) *DABlockSource {
+	return &DABlockSource{
+		retriever:   retriever,
+		daHeight:    startDAHeight,
+		endDAHeight: endDAHeight,
+		blocks:      make(map[uint64]*types.Block),
+	}
+}
+
+// GetBlock returns block at given height, retrieving subsequent DA heights until the block is found.
+func (s *DABlockSource) GetBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	for {
+		if b, ok := s.blocks[height]; ok {
+			delete(s.blocks, height)
+			return b, nil
+		}
+		if s.daHeight > s.endDAHeight {
+			return nil, fmt.Errorf("block %d not found in DA up to height %d", height, s.endDAHeight)
+		}
+		res := s.retriever.RetrieveBlocks(ctx, s.daHeight)
+		switch res.Code {
+		case da.StatusSuccess:
+			for _, b := range res.Blocks {
+				// blocks below requested height were already replayed
+				if b.Height() >= height {
+					s.blocks[b.Height()] = b
+				}
+			}
+		case da.StatusNotFound:
+		default:
+			return nil, fmt.Errorf("failed to retrieve blocks from DA height %d: %s", s.daHeight, res.Message)
+		}
+		s.daHeight++
+	}
+}
+
+// Replayer re-executes the chain against an application, verifying app hashes at each height.
+//
+// Application has to be a fresh instance (for replay from genesis), or has to be at the height of the state
+// replay is started from. Replayer is useful for audits and for debugging non-deterministic applications.
+type Replayer struct {
+	genesis  *cmtypes.GenesisDoc
+	source   BlockSource
+	executor *state.BlockExecutor
+	logger   log.Logger
+}
+
+// NewReplayer creates new Replayer applying blocks from source using connections to the application. dataHasher is the
+// hash function of DataHash of the chain (see types.DataHasherFromGenesis); an error is returned if it has no hash
+// function.
+func NewReplayer(genesis *cmtypes.GenesisDoc, dataHasher types.MerkleHasher, source BlockSource, proxyApp proxy.AppConns, logger log.Logger) (*Replayer, error) {
+	// transactions are never added to the mempool, it's only required to commit blocks
+	mempool := mempoolv1.NewTxMempool(cmlog.NewNopLogger(), llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
+	executor, err := state.NewBlockExecutor(nil, types.NamespaceID{}, genesis.ChainID, dataHasher, mempool, proxyApp.Consensus(), nil, nil, nil, 0, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{
+		genesis:  genesis,
+		source:   source,
+		executor: executor,
+		logger:   logger,
+	}, nil
+}
+
+// InitChain initializes the application with genesis and returns the initial state of the chain.
+func (r *Replayer) InitChain() (types.State, error) {
+	s, err := types.NewFromGenesisDoc(r.genesis)
+	if err != nil {
+		return types.State{}, err
+	}
+	res, err := r.executor.InitChain(r.genesis)
+	if err != nil {
+		return types.State{}, fmt.Errorf("failed to initialize chain: %w", err)
+	}
+	block.UpdateStateFromInitChain(&s, res)
+	return s, nil
+}
+
+// Replay applies and commits blocks following the state up to (and including) height to.
+//
+// App hash committed in each block is compared with app hash of the application after
+// executing the previous block. It returns the state after the last replayed block.
+func (r *Replayer) Replay(ctx context.Context, s types.State, to uint64) (types.State, error) {
+	height := s.LastBlockHeight + 1
+	if s.LastBlockHeight == 0 {
+		height = s.InitialHeight
+	}
+	for ; height <= to; height++ {
+		b, err := r.source.GetBlock(ctx, height)
+		if err != nil {
+			return s, fmt.Errorf("failed to get block %d: %w", height, err)
+		}
+		if !bytes.Equal(b.SignedHeader.AppHash, s.AppHash) {
+			return s, fmt.Errorf("%w after block %d: committed %X, replayed %X", block.ErrAppHashMismatch, s.LastBlockHeight, b.SignedHeader.AppHash, s.AppHash)
+		}
+
+		newState, responses, err := r.executor.ApplyBlock(ctx, s, b)
+		if err != nil {
+			return s, fmt.Errorf("failed to apply block %d: %w", height, err)
+		}
+		appHash, _, err := r.executor.Commit(ctx, newState, b, responses)
+		if err != nil {
+			return s, fmt.Errorf("failed to commit block %d: %w", height, err)
+		}
+		newState.AppHash = appHash
+		s = newState
+		r.logger.Debug("replayed block", "height", height, "appHash", appHash)
+	}
+	return s, nil
+}
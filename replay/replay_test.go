@@ -0,0 +1,118 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/mempool"
+	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/state"
+	"github.com/rollkit/rollkit/store"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func getReplayApp(t *testing.T) proxy.AppConns {
+	app := &mocks.Application{}
+	app.On("InitChain", mock.Anything).Return(abci.ResponseInitChain{})
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	app.On("BeginBlock", mock.Anything).Return(abci.ResponseBeginBlock{})
+	app.On("DeliverTx", mock.Anything).Return(abci.ResponseDeliverTx{})
+	app.On("EndBlock", mock.Anything).Return(abci.ResponseEndBlock{})
+	app.On("Commit", mock.Anything).Return(abci.ResponseCommit{Data: []byte{1, 2, 3}})
+
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app), proxy.NopMetrics())
+	require.NoError(t, proxyApp.Start())
+	t.Cleanup(func() { _ = proxyApp.Stop() })
+	return proxyApp
+}
+
+func TestReplay(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx := context.Background()
+	logger := test.NewFileLogger(t)
+
+	key := ed25519.GenPrivKey()
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:         "replay",
+		InitialHeight:   1,
+		GenesisTime:     time.Now(),
+		ConsensusParams: cmtypes.DefaultConsensusParams(),
+		Validators:      []cmtypes.GenesisValidator{{Address: key.PubKey().Address(), PubKey: key.PubKey(), Power: 1}},
+	}
+
+	kv, _ := store.NewDefaultInMemoryKVStore()
+	blockStore := store.New(ctx, kv)
+
+	// produce the chain
+	proxyApp := getReplayApp(t)
+	mpool := mempoolv1.NewTxMempool(log.NewNopLogger(), cfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
+	executor, err := state.NewBlockExecutor(key.PubKey().Address(), types.NamespaceID{}, genesis.ChainID, types.DefaultMerkleHasher, mpool, proxyApp.Consensus(), nil, nil, nil, 0, nil, logger)
+	require.NoError(err)
+	s, err := types.NewFromGenesisDoc(genesis)
+	require.NoError(err)
+	res, err := executor.InitChain(genesis)
+	require.NoError(err)
+	block.UpdateStateFromInitChain(&s, res)
+	sign := func(b *types.Block) {
+		b.SignedHeader.DataHash, err = b.Data.Hash()
+		require.NoError(err)
+		b.SignedHeader.Validators = s.Validators
+		headerBytes, _ := b.SignedHeader.Header.MarshalBinary()
+		sig, _ := key.Sign(headerBytes)
+		b.SignedHeader.Commit = types.Commit{Signatures: []types.Signature{sig}}
+	}
+	for height := uint64(1); height <= 3; height++ {
+		require.NoError(mpool.CheckTx(cmtypes.Tx{byte(height)}, func(r *abci.Response) {}, mempool.TxInfo{}))
+		b := executor.CreateBlock(height, &types.Commit{}, []byte{}, s)
+		sign(b)
+		newState, responses, err := executor.ApplyProposedBlock(ctx, s, b)
+		require.NoError(err)
+		sign(b)
+		appHash, _, err := executor.Commit(ctx, newState, b, responses)
+		require.NoError(err)
+		newState.AppHash = appHash
+		require.NoError(blockStore.SaveBlock(b, &b.SignedHeader.Commit))
+		s = newState
+	}
+
+	// replay it against fresh application
+	replayer, err := NewReplayer(genesis, types.DefaultMerkleHasher, NewStoreBlockSource(blockStore), getReplayApp(t), logger)
+	require.NoError(err)
+	initial, err := replayer.InitChain()
+	require.NoError(err)
+	replayed, err := replayer.Replay(ctx, initial, 3)
+	require.NoError(err)
+	assert.Equal(s.LastBlockHeight, replayed.LastBlockHeight)
+	assert.Equal(s.AppHash, replayed.AppHash)
+	assert.Equal(s.LastResultsHash, replayed.LastResultsHash)
+
+	// diverged app hash is detected
+	b, err := blockStore.LoadBlock(2)
+	require.NoError(err)
+	b.SignedHeader.AppHash = []byte{3, 2, 1}
+	require.NoError(blockStore.SaveBlock(b, &b.SignedHeader.Commit))
+
+	replayer, err = NewReplayer(genesis, types.DefaultMerkleHasher, NewStoreBlockSource(blockStore), getReplayApp(t), logger)
+	require.NoError(err)
+	initial, err = replayer.InitChain()
+	require.NoError(err)
+	replayed, err = replayer.Replay(ctx, initial, 3)
+	assert.ErrorIs(err, block.ErrAppHashMismatch)
+	assert.Equal(uint64(1), replayed.LastBlockHeight)
+}
@@ -0,0 +1,189 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/rollkit/rollkit/mempool"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/types"
+	pb "github.com/rollkit/rollkit/types/pb/rpc"
+)
+
+const (
+	// headerSubscriptionBuffer is the capacity of event bus subscriptions of header streams.
+	headerSubscriptionBuffer = 100
+	// daInclusionPollInterval is the interval of checking DA inclusion of blocks by header streams
+	// of DA included headers.
+	daInclusionPollInterval = time.Second
+)
+
+// subscriberID makes names of event bus subscribers of header streams unique.
+var subscriberID atomic.Uint64
+
+// NewServer creates gRPC server exposing NodeService backed by given full node.
+func NewServer(node *node.FullNode, opts ...grpc.ServerOption) *grpc.Server {
+	srv := grpc.NewServer(opts...)
+	pb.RegisterNodeServiceServer(srv, &service{node: node})
+	return srv
+}
+
+// service implements pb.NodeServiceServer.
+type service struct {
+	node *node.FullNode
+}
+
+var _ pb.NodeServiceServer = &service{}
+
+// GetBlock returns block at given height, or the latest block if height is 0.
+func (s *service) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.GetBlockResponse, error) {
+	block, err := s.loadBlock(req.Height)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := block.ToProto()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.GetBlockResponse{Block: bp}, nil
+}
+
+// GetHeader returns signed header at given height, or the latest header if height is 0.
+func (s *service) GetHeader(ctx context.Context, req *pb.GetHeaderRequest) (*pb.GetHeaderResponse, error) {
+	block, err := s.loadBlock(req.Height)
+	if err != nil {
+		return nil, err
+	}
+	hp, err := block.SignedHeader.ToProto()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.GetHeaderResponse{Header: hp}, nil
+}
+
+// GetStatus returns chain ID and information about the latest block.
+func (s *service) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	resp := &pb.GetStatusResponse{ChainId: s.node.GetGenesis().ChainID}
+	height := s.node.Store.Height()
+	if height == 0 {
+		return resp, nil
+	}
+	block, err := s.loadBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	resp.LatestHeight = height
+	resp.LatestBlockHash = block.Hash()
+	resp.LatestBlockTime = uint64(block.Time().UnixNano())
+	return resp, nil
+}
+
+// BroadcastTx submits transaction to the mempool and returns the result of CheckTx.
+func (s *service) BroadcastTx(ctx context.Context, req *pb.BroadcastTxRequest) (*pb.BroadcastTxResponse, error) {
+	if len(req.Tx) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty transaction")
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = node.ContextWithRemoteAddr(ctx, p.Addr.String())
+	}
+	res, err := s.node.GetClient().BroadcastTxSync(ctx, req.Tx)
+	if errors.Is(err, mempool.ErrTxThrottled) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &pb.BroadcastTxResponse{
+		Code:      res.Code,
+		Data:      res.Data,
+		Log:       res.Log,
+		Codespace: res.Codespace,
+		Hash:      res.Hash,
+	}, nil
+}
+
+// SubscribeHeaders streams signed headers of blocks, in order of height, starting from FromHeight (or the next block).
+// Headers are sent as soon as blocks are stored by the node, or after they're included in the DA layer
+// if DaIncluded is set, together with their DA location (if known).
+func (s *service) SubscribeHeaders(req *pb.SubscribeHeadersRequest, stream pb.NodeService_SubscribeHeadersServer) error {
+	ctx := stream.Context()
+	eventBus := s.node.EventBus()
+	subscriber := fmt.Sprintf("grpc-headers-%d", subscriberID.Add(1))
+	sub, err := eventBus.Subscribe(ctx, subscriber, cmtypes.EventQueryNewBlock, headerSubscriptionBuffer)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer func() {
+		_ = eventBus.UnsubscribeAll(context.Background(), subscriber)
+	}()
+
+	next := req.FromHeight
+	if next == 0 {
+		next = s.node.Store.Height() + 1
+	}
+	ticker := time.NewTicker(daInclusionPollInterval)
+	defer ticker.Stop()
+	for {
+		for next <= s.node.Store.Height() {
+			resp, err := s.headerResponse(next)
+			if err != nil {
+				return err
+			}
+			if req.DaIncluded && resp.DaHeight == 0 {
+				break
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			next++
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-sub.Cancelled():
+			return status.Errorf(codes.Unavailable, "subscription canceled: %v", sub.Err())
+		case <-sub.Out():
+		case <-ticker.C:
+		}
+	}
+}
+
+// headerResponse returns signed header of the block at given height, with its DA location (if known).
+func (s *service) headerResponse(height uint64) (*pb.SubscribeHeadersResponse, error) {
+	block, err := s.loadBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	hp, err := block.SignedHeader.ToProto()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &pb.SubscribeHeadersResponse{Header: hp}
+	if loc, err := s.node.Store.LoadDALocation(height); err == nil {
+		resp.DaHeight, resp.DaIndex = loc.DAHeight, loc.Index
+	}
+	return resp, nil
+}
+
+func (s *service) loadBlock(height uint64) (*types.Block, error) {
+	if height == 0 {
+		height = s.node.Store.Height()
+	}
+	if height == 0 || height > s.node.Store.Height() {
+		return nil, status.Errorf(codes.NotFound, "block at height %d not found", height)
+	}
+	block, err := s.node.Store.LoadBlock(height)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return block, nil
+}
@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/test/mocks"
+	pb "github.com/rollkit/rollkit/types/pb/rpc"
+)
+
+func getClient(t *testing.T) (*node.FullNode, pb.NodeServiceClient) {
+	t.Helper()
+	require := require.New(t)
+	app := &mocks.Application{}
+	app.On("InitChain", mock.Anything).Return(abci.ResponseInitChain{})
+	app.On("BeginBlock", mock.Anything).Return(abci.ResponseBeginBlock{})
+	app.On("DeliverTx", mock.Anything).Return(abci.ResponseDeliverTx{})
+	app.On("EndBlock", mock.Anything).Return(abci.ResponseEndBlock{})
+	app.On("Commit", mock.Anything).Return(abci.ResponseCommit{})
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	app.On("Info", mock.Anything).Return(abci.ResponseInfo{})
+
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	validatorKey := ed25519.GenPrivKey()
+	signingKey, _ := node.GetNodeKey(&p2p.NodeKey{PrivKey: validatorKey})
+	pubKey := validatorKey.PubKey()
+	genesisValidators := []cmtypes.GenesisValidator{
+		{Address: pubKey.Address(), PubKey: pubKey, Power: int64(100), Name: "gen #1"},
+	}
+	conf := config.NodeConfig{Aggregator: true, DALayer: "newda", BlockManagerConfig: config.BlockManagerConfig{BlockTime: 100 * time.Millisecond}}
+	n, err := node.NewNode(context.Background(), conf, key, signingKey, proxy.NewLocalClientCreator(app), &cmtypes.GenesisDoc{ChainID: "test", Validators: genesisValidators}, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(n.Start())
+	t.Cleanup(func() { _ = n.Stop() })
+	fullNode := n.(*node.FullNode)
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := NewServer(fullNode)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return fullNode, pb.NewNodeServiceClient(conn)
+}
+
+func TestService(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	fullNode, client := getClient(t)
+
+	require.Eventually(func() bool { return fullNode.Store.Height() >= 2 }, 5*time.Second, 50*time.Millisecond)
+
+	st, err := client.GetStatus(ctx, &pb.GetStatusRequest{})
+	require.NoError(err)
+	assert.Equal("test", st.ChainId)
+	assert.GreaterOrEqual(st.LatestHeight, uint64(2))
+	assert.NotEmpty(st.LatestBlockHash)
+	assert.NotZero(st.LatestBlockTime)
+
+	block, err := client.GetBlock(ctx, &pb.GetBlockRequest{Height: 1})
+	require.NoError(err)
+	assert.Equal(uint64(1), block.Block.SignedHeader.Header.Height)
+
+	header, err := client.GetHeader(ctx, &pb.GetHeaderRequest{Height: 2})
+	require.NoError(err)
+	assert.Equal(uint64(2), header.Header.Header.Height)
+
+	latest, err := client.GetHeader(ctx, &pb.GetHeaderRequest{})
+	require.NoError(err)
+	assert.GreaterOrEqual(latest.Header.Header.Height, uint64(2))
+
+	_, err = client.GetBlock(ctx, &pb.GetBlockRequest{Height: 1000})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	res, err := client.BroadcastTx(ctx, &pb.BroadcastTxRequest{Tx: []byte("tx")})
+	require.NoError(err)
+	assert.Equal(abci.CodeTypeOK, res.Code)
+	assert.Equal(cmtypes.Tx("tx").Hash(), res.Hash)
+
+	_, err = client.BroadcastTx(ctx, &pb.BroadcastTxRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestSubscribeHeaders(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, client := getClient(t)
+
+	stream, err := client.SubscribeHeaders(ctx, &pb.SubscribeHeadersRequest{FromHeight: 1})
+	require.NoError(err)
+	for h := uint64(1); h <= 3; h++ {
+		resp, err := stream.Recv()
+		require.NoError(err)
+		require.Equal(h, resp.Header.Header.Height)
+	}
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(codes.Canceled, status.Code(err))
+}
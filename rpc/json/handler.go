@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	cmjson "github.com/cometbft/cometbft/libs/json"
 
@@ -19,26 +21,31 @@ import (
 )
 
 type handler struct {
-	srv    *service
-	mux    *http.ServeMux
-	codec  rpc.Codec
-	logger log.Logger
+	srv     *service
+	mux     *http.ServeMux
+	codec   rpc.Codec
+	logger  log.Logger
+	metrics *Metrics
 }
 
-func newHandler(s *service, codec rpc.Codec, logger log.Logger) *handler {
+func newHandler(s *service, codec rpc.Codec, logger log.Logger, metrics *Metrics) *handler {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
 	mux := http.NewServeMux()
 	h := &handler{
-		srv:    s,
-		mux:    mux,
-		codec:  codec,
-		logger: logger,
+		srv:     s,
+		mux:     mux,
+		codec:   codec,
+		logger:  logger,
+		metrics: metrics,
 	}
 
 	mux.HandleFunc("/", h.serveJSONRPC)
 	mux.HandleFunc("/websocket", h.wsHandler)
 	for name, method := range s.methods {
 		logger.Debug("registering method", "name", name)
-		mux.HandleFunc("/"+name, h.newHandler(method))
+		mux.HandleFunc("/"+name, h.newHandler(name, method))
 	}
 
 	return h
@@ -87,7 +94,7 @@ func (h *handler) serveJSONRPCforWS(w http.ResponseWriter, r *http.Request, wsCo
 	if methodSpec.ws {
 		callArgs = append(callArgs, reflect.ValueOf(wsConn))
 	}
-	rets := methodSpec.m.Call(callArgs)
+	rets := h.call(method, methodSpec, callArgs)
 
 	// Extract the result to error if needed.
 	var errResult error
@@ -116,7 +123,7 @@ func (h *handler) serveJSONRPCforWS(w http.ResponseWriter, r *http.Request, wsCo
 	}
 }
 
-func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http.Request) {
+func (h *handler) newHandler(name string, methodSpec *method) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		args := reflect.New(methodSpec.argsType)
 		values, err := url.ParseQuery(r.URL.RawQuery)
@@ -126,8 +133,11 @@ func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http
 		}
 		for i := 0; i < methodSpec.argsType.NumField(); i++ {
 			field := methodSpec.argsType.Field(i)
-			name := field.Tag.Get("json")
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
 			if !values.Has(name) {
+				if opts == "omitempty" { // optional param
+					continue
+				}
 				h.encodeAndWriteResponse(w, nil, fmt.Errorf("missing param '%s'", name), int(json2.E_INVALID_REQ))
 				return
 			}
@@ -139,7 +149,7 @@ func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http
 			case reflect.Int, reflect.Int64:
 				err = setIntParam(rawVal, &args, i)
 			case reflect.String:
-				args.Elem().Field(i).SetString(rawVal)
+				setStringParam(rawVal, &args, i)
 			case reflect.Slice:
 				// []byte is a reflect.Slice of reflect.Uint8's
 				if field.Type.Elem().Kind() == reflect.Uint8 {
@@ -154,7 +164,7 @@ func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http
 				return
 			}
 		}
-		rets := methodSpec.m.Call([]reflect.Value{
+		rets := h.call(name, methodSpec, []reflect.Value{
 			reflect.ValueOf(r),
 			args,
 		})
@@ -171,6 +181,18 @@ func (h *handler) newHandler(methodSpec *method) func(http.ResponseWriter, *http
 	}
 }
 
+// call invokes the RPC method and records its metrics.
+func (h *handler) call(name string, methodSpec *method, args []reflect.Value) []reflect.Value {
+	start := time.Now()
+	rets := methodSpec.m.Call(args)
+	h.metrics.Requests.With("method", name).Add(1)
+	h.metrics.RequestDuration.With("method", name).Observe(time.Since(start).Seconds())
+	if rets[1].Interface() != nil {
+		h.metrics.FailedRequests.With("method", name).Add(1)
+	}
+	return rets
+}
+
 func (h *handler) encodeAndWriteResponse(w http.ResponseWriter, result interface{}, errResult error, statusCode int) {
 	// Prevents Internet Explorer from MIME-sniffing a response away
 	// from the declared content-type
@@ -218,6 +240,16 @@ func setIntParam(rawVal string, args *reflect.Value, i int) error {
 	return nil
 }
 
+// setStringParam sets the string param. Like in CometBFT, the value can be quoted (e.g. query="tx.height=5").
+func setStringParam(rawVal string, args *reflect.Value, i int) {
+	if len(rawVal) >= 2 && strings.HasPrefix(rawVal, `"`) {
+		if v, err := strconv.Unquote(rawVal); err == nil {
+			rawVal = v
+		}
+	}
+	args.Elem().Field(i).SetString(rawVal)
+}
+
 func setByteSliceParam(rawVal string, args *reflect.Value, i int) error {
 	b, err := hex.DecodeString(rawVal)
 	if err != nil {
@@ -44,6 +44,8 @@ func getRPC(t *testing.T) (*mocks.Application, rpcclient.Client) {
 		GasWanted: 1000,
 		GasUsed:   1000,
 	})
+	// the first Info call is made by warm-up of the node, when the application has no blocks yet
+	app.On("Info", mock.Anything).Return(abci.ResponseInfo{}).Once()
 	app.On("Info", mock.Anything).Return(abci.ResponseInfo{
 		Data:             "mock",
 		Version:          "mock",
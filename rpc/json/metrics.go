@@ -0,0 +1,68 @@
+package json
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "rpc"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of RPC requests, labeled by method.
+	Requests metrics.Counter
+
+	// Number of RPC requests that returned an error, labeled by method.
+	FailedRequests metrics.Counter
+
+	// Duration of RPC requests, in seconds, labeled by method.
+	RequestDuration metrics.Histogram
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Requests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "requests",
+			Help:      "Number of RPC requests.",
+		}, append(labels, "method")).With(labelsAndValues...),
+
+		FailedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "failed_requests",
+			Help:      "Number of RPC requests that returned an error.",
+		}, append(labels, "method")).With(labelsAndValues...),
+
+		RequestDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "request_duration",
+			Help:      "Duration of RPC requests, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 16),
+		}, append(labels, "method")).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Requests:        discard.NewCounter(),
+		FailedRequests:  discard.NewCounter(),
+		RequestDuration: discard.NewHistogram(),
+	}
+}
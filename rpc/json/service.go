@@ -2,22 +2,33 @@ package json
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"time"
 
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/rollkit/rollkit/da/datacommitment"
+	"github.com/rollkit/rollkit/ibc"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/p2p"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
 )
 
 // GetHTTPHandler returns handler configured to serve Tendermint-compatible RPC.
-func GetHTTPHandler(l rpcclient.Client, logger log.Logger) (http.Handler, error) {
-	return newHandler(newService(l, logger), json2.NewCodec(), logger), nil
+// If metrics is nil, no metrics are recorded.
+func GetHTTPHandler(l rpcclient.Client, logger log.Logger, metrics *Metrics) (http.Handler, error) {
+	return newHandler(newService(l, logger), json2.NewCodec(), logger, metrics), nil
 }
 
 type method struct {
@@ -80,9 +91,136 @@ func newService(c rpcclient.Client, l log.Logger) *service {
 		"abci_info":            newMethod(s.ABCIInfo),
 		"broadcast_evidence":   newMethod(s.BroadcastEvidence),
 	}
+	if _, ok := c.(fraudProofClient); ok {
+		s.methods["fraud_proof"] = newMethod(s.FraudProof)
+	}
+	if _, ok := c.(txProofClient); ok {
+		s.methods["tx_proof"] = newMethod(s.TxProof)
+	}
+	if _, ok := c.(peerScoresClient); ok {
+		s.methods["peer_scores"] = newMethod(s.PeerScores)
+	}
+	if _, ok := c.(netPeersClient); ok {
+		s.methods["net_peers"] = newMethod(s.NetPeers)
+	}
+	if _, ok := c.(preConfirmationClient); ok {
+		s.methods["broadcast_tx_preconfirm"] = newMethod(s.BroadcastTxPreConfirm)
+	}
+	if _, ok := c.(inclusionListClient); ok {
+		s.methods["broadcast_inclusion_list"] = newMethod(s.BroadcastInclusionList)
+		s.methods["inclusion_lists"] = newMethod(s.InclusionLists)
+	}
+	if _, ok := c.(ibcClient); ok {
+		s.methods["signed_header"] = newMethod(s.SignedHeader)
+		s.methods["validators_with_proof"] = newMethod(s.ValidatorsWithProof)
+		s.methods["state_proof"] = newMethod(s.StateProof)
+	}
+	if _, ok := c.(dataCommitmentClient); ok {
+		s.methods["data_commitment"] = newMethod(s.DataCommitment)
+		s.methods["data_root_inclusion_proof"] = newMethod(s.DataRootInclusionProof)
+	}
+	if _, ok := c.(appHashClient); ok {
+		s.methods["app_hash"] = newMethod(s.AppHash)
+	}
+	if _, ok := c.(daIndexClient); ok {
+		s.methods["da_location"] = newMethod(s.DALocation)
+		s.methods["da_block_heights"] = newMethod(s.DABlockHeights)
+	}
+	if _, ok := c.(daCostClient); ok {
+		s.methods["da_costs"] = newMethod(s.DACosts)
+	}
+	if ac, ok := c.(adminClient); ok && ac.AdminToken() != "" {
+		s.methods["admin_rollback"] = newMethod(s.AdminRollback)
+		s.methods["admin_prune_blocks"] = newMethod(s.AdminPruneBlocks)
+		s.methods["admin_resubmit_blocks"] = newMethod(s.AdminResubmitBlocks)
+		s.methods["admin_halt_block_processing"] = newMethod(s.AdminHaltBlockProcessing)
+		s.methods["admin_resume_block_processing"] = newMethod(s.AdminResumeBlockProcessing)
+		s.methods["admin_set_log_level"] = newMethod(s.AdminSetLogLevel)
+		s.methods["admin_dump_state"] = newMethod(s.AdminDumpState)
+		s.methods["admin_clear_peer_scores"] = newMethod(s.AdminClearPeerScores)
+	}
 	return &s
 }
 
+// fraudProofClient is implemented by clients of nodes able to generate state fraud proofs.
+type fraudProofClient interface {
+	FraudProof(ctx context.Context, height *int64) (*types.StateFraudProof, error)
+	HaltProof(ctx context.Context) *types.StateFraudProof
+}
+
+// rollkitStatusClient is implemented by clients of nodes reporting rollkit specific state in status.
+type rollkitStatusClient interface {
+	RollkitStatus(ctx context.Context) (*node.ResultRollkitStatus, error)
+}
+
+// txProofClient is implemented by clients of nodes able to prove inclusion of transactions in blocks.
+type txProofClient interface {
+	TxProof(ctx context.Context, hash []byte) (*node.TxInclusionProof, error)
+}
+
+// peerScoresClient is implemented by clients of nodes keeping scores of P2P peers.
+type peerScoresClient interface {
+	PeerScores(ctx context.Context) ([]p2p.PeerScore, error)
+}
+
+// netPeersClient is implemented by clients of nodes able to describe connections with P2P peers.
+type netPeersClient interface {
+	NetPeers(ctx context.Context) ([]p2p.PeerInfo, error)
+}
+
+// preConfirmationClient is implemented by clients of nodes able to pre-confirm transactions.
+type preConfirmationClient interface {
+	BroadcastTxPreConfirm(ctx context.Context, tx cmtypes.Tx) (*node.ResultBroadcastTxPreConfirm, error)
+}
+
+// inclusionListClient is implemented by clients of nodes tracking compliance of the sequencer with inclusion lists.
+type inclusionListClient interface {
+	BroadcastInclusionList(ctx context.Context, txHashes []cmbytes.HexBytes, deadline uint64) (*node.ResultBroadcastInclusionList, error)
+	InclusionLists(ctx context.Context) (*node.InclusionListStatus, error)
+}
+
+// ibcClient is implemented by clients serving headers, aggregator set proofs and state proofs for IBC light clients.
+type ibcClient interface {
+	SignedHeader(ctx context.Context, height *int64) (*types.SignedHeader, error)
+	ValidatorsWithProof(ctx context.Context, height *int64) (*ibc.AggregatorSetProof, error)
+	StateProof(ctx context.Context, store string, key []byte, height *int64) (*ibc.StateProof, error)
+}
+
+// dataCommitmentClient is implemented by clients serving data commitments and proofs for bridge contracts.
+type dataCommitmentClient interface {
+	DataCommitment(ctx context.Context, from, to uint64) (*datacommitment.DataCommitment, error)
+	DataRootInclusionProof(ctx context.Context, height, from, to uint64) (*datacommitment.DataRootInclusionProof, error)
+}
+
+// appHashClient is implemented by clients of nodes storing historical app hashes.
+type appHashClient interface {
+	AppHash(ctx context.Context, height *int64) (*node.ResultAppHash, error)
+}
+
+// daIndexClient is implemented by clients of nodes indexing locations of blocks on the DA layer.
+type daIndexClient interface {
+	DALocation(ctx context.Context, height *int64) (*node.ResultDALocation, error)
+	DABlockHeights(ctx context.Context, daHeight uint64) (*node.ResultDABlockHeights, error)
+}
+
+// daCostClient is implemented by clients of nodes accounting costs of DA submissions.
+type daCostClient interface {
+	DACosts(ctx context.Context, minHeight, maxHeight int64, days int) (*node.ResultDACosts, error)
+}
+
+// adminClient is implemented by clients of nodes supporting administrative operations.
+type adminClient interface {
+	AdminToken() string
+	Rollback(ctx context.Context) (uint64, error)
+	PruneBlocks(ctx context.Context, retainHeight uint64) (uint64, error)
+	ResubmitBlocks(ctx context.Context, from, to uint64) (int, error)
+	HaltBlockProcessing(ctx context.Context) error
+	ResumeBlockProcessing(ctx context.Context) error
+	SetLogLevel(ctx context.Context, module, level string) error
+	DumpState(ctx context.Context) (*node.DebugState, error)
+	ClearPeerScores(ctx context.Context, id string) (int, error)
+}
+
 func (s *service) Subscribe(req *http.Request, args *subscribeArgs, wsConn *wsConn) (*ctypes.ResultSubscribe, error) {
 	// TODO(tzdybal): pass config and check subscriptions limits
 	// TODO(tzdybal): extract consts or configs
@@ -139,8 +277,25 @@ func (s *service) Health(req *http.Request, args *healthArgs) (*ctypes.ResultHea
 	return s.client.Health(req.Context())
 }
 
-func (s *service) Status(req *http.Request, args *statusArgs) (*ctypes.ResultStatus, error) {
-	return s.client.Status(req.Context())
+func (s *service) Status(req *http.Request, args *statusArgs) (*ResultStatus, error) {
+	status, err := s.client.Status(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	res := &ResultStatus{
+		NodeInfo:      status.NodeInfo,
+		SyncInfo:      status.SyncInfo,
+		ValidatorInfo: status.ValidatorInfo,
+	}
+	if c, ok := s.client.(fraudProofClient); ok {
+		res.ChainFaulty = c.HaltProof(req.Context()) != nil
+	}
+	if c, ok := s.client.(rollkitStatusClient); ok {
+		if res.Rollkit, err = c.RollkitStatus(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
 func (s *service) NetInfo(req *http.Request, args *netInfoArgs) (*ctypes.ResultNetInfo, error) {
@@ -184,11 +339,19 @@ func (s *service) Tx(req *http.Request, args *txArgs) (*ctypes.ResultTx, error)
 }
 
 func (s *service) TxSearch(req *http.Request, args *txSearchArgs) (*ctypes.ResultTxSearch, error) {
-	return s.client.TxSearch(req.Context(), args.Query, args.Prove, (*int)(&args.Page), (*int)(&args.PerPage), args.OrderBy)
+	return s.client.TxSearch(req.Context(), args.Query, args.Prove, optionalPage(args.Page), (*int)(&args.PerPage), args.OrderBy)
 }
 
 func (s *service) BlockSearch(req *http.Request, args *blockSearchArgs) (*ctypes.ResultBlockSearch, error) {
-	return s.client.BlockSearch(req.Context(), args.Query, (*int)(&args.Page), (*int)(&args.PerPage), args.OrderBy)
+	return s.client.BlockSearch(req.Context(), args.Query, optionalPage(args.Page), (*int)(&args.PerPage), args.OrderBy)
+}
+
+// optionalPage returns nil (i.e. the first page) if the page was not given.
+func optionalPage(page StrInt) *int {
+	if page == 0 {
+		return nil
+	}
+	return (*int)(&page)
 }
 
 func (s *service) Validators(req *http.Request, args *validatorsArgs) (*ctypes.ResultValidators, error) {
@@ -207,7 +370,15 @@ func (s *service) ConsensusParams(req *http.Request, args *consensusParamsArgs)
 	return s.client.ConsensusParams(req.Context(), (*int64)(&args.Height))
 }
 
+// unconfirmedTxsPager is implemented by clients supporting pagination of unconfirmed transactions.
+type unconfirmedTxsPager interface {
+	UnconfirmedTxsPage(ctx context.Context, page, perPage *int) (*ctypes.ResultUnconfirmedTxs, error)
+}
+
 func (s *service) UnconfirmedTxs(req *http.Request, args *unconfirmedTxsArgs) (*ctypes.ResultUnconfirmedTxs, error) {
+	if pager, ok := s.client.(unconfirmedTxsPager); ok && (args.Page != 0 || args.PerPage != 0) {
+		return pager.UnconfirmedTxsPage(req.Context(), optionalPage(args.Page), (*int)(&args.PerPage))
+	}
 	return s.client.UnconfirmedTxs(req.Context(), (*int)(&args.Limit))
 }
 
@@ -217,15 +388,15 @@ func (s *service) NumUnconfirmedTxs(req *http.Request, args *numUnconfirmedTxsAr
 
 // tx broadcast API
 func (s *service) BroadcastTxCommit(req *http.Request, args *broadcastTxCommitArgs) (*ctypes.ResultBroadcastTxCommit, error) {
-	return s.client.BroadcastTxCommit(req.Context(), args.Tx)
+	return s.client.BroadcastTxCommit(node.ContextWithRemoteAddr(req.Context(), req.RemoteAddr), args.Tx)
 }
 
 func (s *service) BroadcastTxSync(req *http.Request, args *broadcastTxSyncArgs) (*ctypes.ResultBroadcastTx, error) {
-	return s.client.BroadcastTxSync(req.Context(), args.Tx)
+	return s.client.BroadcastTxSync(node.ContextWithRemoteAddr(req.Context(), req.RemoteAddr), args.Tx)
 }
 
 func (s *service) BroadcastTxAsync(req *http.Request, args *broadcastTxAsyncArgs) (*ctypes.ResultBroadcastTx, error) {
-	return s.client.BroadcastTxAsync(req.Context(), args.Tx)
+	return s.client.BroadcastTxAsync(node.ContextWithRemoteAddr(req.Context(), req.RemoteAddr), args.Tx)
 }
 
 // abci API
@@ -244,3 +415,178 @@ func (s *service) ABCIInfo(req *http.Request, args *ABCIInfoArgs) (*ctypes.Resul
 func (s *service) BroadcastEvidence(req *http.Request, args *broadcastEvidenceArgs) (*ctypes.ResultBroadcastEvidence, error) {
 	return s.client.BroadcastEvidence(req.Context(), args.Evidence)
 }
+
+// rollkit API
+func (s *service) FraudProof(req *http.Request, args *fraudProofArgs) (*types.StateFraudProof, error) {
+	return s.client.(fraudProofClient).FraudProof(req.Context(), (*int64)(&args.Height))
+}
+
+func (s *service) TxProof(req *http.Request, args *txProofArgs) (*node.TxInclusionProof, error) {
+	return s.client.(txProofClient).TxProof(req.Context(), args.Hash)
+}
+
+func (s *service) SignedHeader(req *http.Request, args *signedHeaderArgs) (*types.SignedHeader, error) {
+	return s.client.(ibcClient).SignedHeader(req.Context(), (*int64)(&args.Height))
+}
+
+func (s *service) ValidatorsWithProof(req *http.Request, args *validatorsWithProofArgs) (*ibc.AggregatorSetProof, error) {
+	return s.client.(ibcClient).ValidatorsWithProof(req.Context(), (*int64)(&args.Height))
+}
+
+func (s *service) StateProof(req *http.Request, args *stateProofArgs) (*ibc.StateProof, error) {
+	var height *int64
+	if args.Height != 0 {
+		height = (*int64)(&args.Height)
+	}
+	return s.client.(ibcClient).StateProof(req.Context(), args.Store, args.Key, height)
+}
+
+func (s *service) AppHash(req *http.Request, args *appHashArgs) (*node.ResultAppHash, error) {
+	var height *int64
+	if args.Height != 0 {
+		height = (*int64)(&args.Height)
+	}
+	return s.client.(appHashClient).AppHash(req.Context(), height)
+}
+
+func (s *service) DataCommitment(req *http.Request, args *dataCommitmentArgs) (*datacommitment.DataCommitment, error) {
+	return s.client.(dataCommitmentClient).DataCommitment(req.Context(), uint64(args.From), uint64(args.To))
+}
+
+func (s *service) DataRootInclusionProof(req *http.Request, args *dataRootInclusionProofArgs) (*datacommitment.DataRootInclusionProof, error) {
+	return s.client.(dataCommitmentClient).DataRootInclusionProof(req.Context(), uint64(args.Height), uint64(args.From), uint64(args.To))
+}
+
+func (s *service) DALocation(req *http.Request, args *daLocationArgs) (*node.ResultDALocation, error) {
+	return s.client.(daIndexClient).DALocation(req.Context(), (*int64)(&args.Height))
+}
+
+func (s *service) DABlockHeights(req *http.Request, args *daBlockHeightsArgs) (*node.ResultDABlockHeights, error) {
+	return s.client.(daIndexClient).DABlockHeights(req.Context(), uint64(args.DAHeight))
+}
+
+func (s *service) DACosts(req *http.Request, args *daCostsArgs) (*node.ResultDACosts, error) {
+	return s.client.(daCostClient).DACosts(req.Context(), int64(args.MinHeight), int64(args.MaxHeight), int(args.Days))
+}
+
+func (s *service) BroadcastTxPreConfirm(req *http.Request, args *broadcastTxPreConfirmArgs) (*node.ResultBroadcastTxPreConfirm, error) {
+	return s.client.(preConfirmationClient).BroadcastTxPreConfirm(node.ContextWithRemoteAddr(req.Context(), req.RemoteAddr), args.Tx)
+}
+
+func (s *service) BroadcastInclusionList(req *http.Request, args *broadcastInclusionListArgs) (*node.ResultBroadcastInclusionList, error) {
+	if args.Deadline < 0 {
+		return nil, fmt.Errorf("invalid deadline %d", args.Deadline)
+	}
+	return s.client.(inclusionListClient).BroadcastInclusionList(req.Context(), args.TxHashes, uint64(args.Deadline))
+}
+
+func (s *service) InclusionLists(req *http.Request, args *inclusionListsArgs) (*node.InclusionListStatus, error) {
+	return s.client.(inclusionListClient).InclusionLists(req.Context())
+}
+
+func (s *service) PeerScores(req *http.Request, args *peerScoresArgs) (*ResultPeerScores, error) {
+	scores, err := s.client.(peerScoresClient).PeerScores(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	return &ResultPeerScores{Peers: scores}, nil
+}
+
+func (s *service) NetPeers(req *http.Request, args *netPeersArgs) (*ResultNetPeers, error) {
+	peers, err := s.client.(netPeersClient).NetPeers(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	return &ResultNetPeers{NPeers: len(peers), Peers: peers}, nil
+}
+
+// authorizeAdmin returns admin client if the request carries the admin bearer token.
+func (s *service) authorizeAdmin(req *http.Request) (adminClient, error) {
+	ac := s.client.(adminClient)
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(ac.AdminToken())) != 1 {
+		s.logger.Info("unauthorized admin RPC call", "remote", req.RemoteAddr)
+		return nil, errors.New("unauthorized")
+	}
+	return ac, nil
+}
+
+func (s *service) AdminRollback(req *http.Request, args *adminRollbackArgs) (*ResultRollback, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	height, err := ac.Rollback(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	return &ResultRollback{Height: height}, nil
+}
+
+func (s *service) AdminPruneBlocks(req *http.Request, args *adminPruneBlocksArgs) (*ResultPruneBlocks, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	pruned, err := ac.PruneBlocks(req.Context(), uint64(args.RetainHeight))
+	if err != nil {
+		return nil, err
+	}
+	return &ResultPruneBlocks{Pruned: pruned}, nil
+}
+
+func (s *service) AdminResubmitBlocks(req *http.Request, args *adminResubmitBlocksArgs) (*ResultResubmitBlocks, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	n, err := ac.ResubmitBlocks(req.Context(), uint64(args.From), uint64(args.To))
+	if err != nil {
+		return nil, err
+	}
+	return &ResultResubmitBlocks{Blocks: n}, nil
+}
+
+func (s *service) AdminHaltBlockProcessing(req *http.Request, args *adminHaltBlockProcessingArgs) (*emptyResult, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	return &emptyResult{}, ac.HaltBlockProcessing(req.Context())
+}
+
+func (s *service) AdminResumeBlockProcessing(req *http.Request, args *adminResumeBlockProcessingArgs) (*emptyResult, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	return &emptyResult{}, ac.ResumeBlockProcessing(req.Context())
+}
+
+func (s *service) AdminSetLogLevel(req *http.Request, args *adminSetLogLevelArgs) (*emptyResult, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	return &emptyResult{}, ac.SetLogLevel(req.Context(), args.Module, args.Level)
+}
+
+func (s *service) AdminDumpState(req *http.Request, args *adminDumpStateArgs) (*node.DebugState, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	return ac.DumpState(req.Context())
+}
+
+func (s *service) AdminClearPeerScores(req *http.Request, args *adminClearPeerScoresArgs) (*ResultClearPeerScores, error) {
+	ac, err := s.authorizeAdmin(req)
+	if err != nil {
+		return nil, err
+	}
+	cleared, err := ac.ClearPeerScores(req.Context(), args.Peer)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultClearPeerScores{Cleared: cleared}, nil
+}
@@ -2,6 +2,7 @@ package json
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,7 +15,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cometbft/cometbft/libs/log"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/rollkit/rollkit/node"
 )
 
 func TestHandlerMapping(t *testing.T) {
@@ -22,7 +26,7 @@ func TestHandlerMapping(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t)
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, log.TestingLogger(), NopMetrics())
 	require.NoError(err)
 
 	jsonReq, err := json2.EncodeClientRequest("health", &healthArgs{})
@@ -66,12 +70,15 @@ func TestREST(t *testing.T) {
 		{"invalid/bool int string params",
 			"/tx_search?" + strings.Replace(txSearchParams.Encode(), "true", "blue", 1),
 			http.StatusOK, int(json2.E_PARSE), "failed to parse param 'prove'"},
+		{"valid/quoted string and optional params",
+			"/block_search?query=%22block.height%3E1%22",
+			http.StatusOK, -1, `"total_count":"0"`},
 		{"valid/hex param", "/check_tx?tx=DEADBEEF", http.StatusOK, -1, `"gas_used":"1000"`},
 		{"invalid/hex param", "/check_tx?tx=QWERTY", http.StatusOK, int(json2.E_PARSE), "failed to parse param 'tx'"},
 	}
 
 	_, local := getRPC(t)
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, log.TestingLogger(), NopMetrics())
 	require.NoError(err)
 
 	for _, c := range cases {
@@ -102,7 +109,7 @@ func TestEmptyRequest(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t)
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, log.TestingLogger(), NopMetrics())
 	require.NoError(err)
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -117,7 +124,7 @@ func TestStringyRequest(t *testing.T) {
 	require := require.New(t)
 
 	_, local := getRPC(t)
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, log.TestingLogger(), NopMetrics())
 	require.NoError(err)
 
 	// `starport chain faucet ...` generates broken JSON (ints are "quoted" as strings)
@@ -171,7 +178,7 @@ func TestSubscription(t *testing.T) {
 	require.NotEmpty(unsubscribeAllReq)
 
 	_, local := getRPC(t)
-	handler, err := GetHTTPHandler(local, log.TestingLogger())
+	handler, err := GetHTTPHandler(local, log.TestingLogger(), NopMetrics())
 	require.NoError(err)
 
 	var (
@@ -254,3 +261,80 @@ func TestSubscription(t *testing.T) {
 	require.NotNil(jsonResp.Error)
 	assert.Contains(jsonResp.Error.Message, "subscription not found")
 }
+
+// adminTestClient implements admin API, without a node.
+type adminTestClient struct {
+	rpcclient.Client
+	module string
+	level  string
+}
+
+func (c *adminTestClient) AdminToken() string                          { return "secret" }
+func (c *adminTestClient) Rollback(context.Context) (uint64, error)    { return 41, nil }
+func (c *adminTestClient) HaltBlockProcessing(context.Context) error   { return nil }
+func (c *adminTestClient) ResumeBlockProcessing(context.Context) error { return nil }
+func (c *adminTestClient) PruneBlocks(_ context.Context, retainHeight uint64) (uint64, error) {
+	return retainHeight - 1, nil
+}
+func (c *adminTestClient) ResubmitBlocks(_ context.Context, from, to uint64) (int, error) {
+	return int(to - from + 1), nil
+}
+func (c *adminTestClient) SetLogLevel(_ context.Context, module, level string) error {
+	c.module, c.level = module, level
+	return nil
+}
+func (c *adminTestClient) DumpState(context.Context) (*node.DebugState, error) {
+	return &node.DebugState{StoreHeight: 42}, nil
+}
+func (c *adminTestClient) ClearPeerScores(_ context.Context, id string) (int, error) {
+	if id == "" {
+		return 3, nil
+	}
+	return 1, nil
+}
+
+func TestAdmin(t *testing.T) {
+	client := &adminTestClient{}
+	handler, err := GetHTTPHandler(client, log.TestingLogger(), NopMetrics())
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		uri          string
+		token        string
+		bodyContains string
+	}{
+		{"no token", "/admin_rollback", "", `"unauthorized"`},
+		{"invalid token", "/admin_rollback", "wrong", `"unauthorized"`},
+		{"rollback", "/admin_rollback", "secret", `"height":"41"`},
+		{"prune blocks", "/admin_prune_blocks?retain_height=10", "secret", `"pruned":"9"`},
+		{"resubmit blocks", "/admin_resubmit_blocks?from=3&to=5", "secret", `"blocks":"3"`},
+		{"halt", "/admin_halt_block_processing", "secret", `"result":{}`},
+		{"resume", "/admin_resume_block_processing", "secret", `"result":{}`},
+		{"dump state", "/admin_dump_state", "secret", `"store_height":"42"`},
+		{"clear peer score", "/admin_clear_peer_scores?peer=12D3KooW", "secret", `"cleared":"1"`},
+		{"clear all peer scores", "/admin_clear_peer_scores", "secret", `"cleared":"3"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, c.uri, nil)
+			if c.token != "" {
+				req.Header.Set("Authorization", "Bearer "+c.token)
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Contains(t, resp.Body.String(), c.bodyContains)
+		})
+	}
+
+	jsonReq, err := json2.EncodeClientRequest("admin_set_log_level", &adminSetLogLevelArgs{Module: "p2p", Level: "debug"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(jsonReq))
+	req.Header.Set("Authorization", "Bearer secret")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "p2p", client.module)
+	assert.Equal(t, "debug", client.level)
+}
@@ -6,8 +6,13 @@ import (
 	"strconv"
 
 	"github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/p2p"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cometbft/cometbft/types"
 	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/rollkit/rollkit/node"
+	rollkitp2p "github.com/rollkit/rollkit/p2p"
 )
 
 type subscribeArgs struct {
@@ -56,16 +61,16 @@ type txArgs struct {
 }
 type txSearchArgs struct {
 	Query   string `json:"query"`
-	Prove   bool   `json:"prove"`
-	Page    StrInt `json:"page"`
-	PerPage StrInt `json:"per_page"`
-	OrderBy string `json:"order_by"`
+	Prove   bool   `json:"prove,omitempty"`
+	Page    StrInt `json:"page,omitempty"`
+	PerPage StrInt `json:"per_page,omitempty"`
+	OrderBy string `json:"order_by,omitempty"`
 }
 type blockSearchArgs struct {
 	Query   string `json:"query"`
-	Page    StrInt `json:"page"`
-	PerPage StrInt `json:"per_page"`
-	OrderBy string `json:"order_by"`
+	Page    StrInt `json:"page,omitempty"`
+	PerPage StrInt `json:"per_page,omitempty"`
+	OrderBy string `json:"order_by,omitempty"`
 }
 type validatorsArgs struct {
 	Height  StrInt64 `json:"height"`
@@ -80,7 +85,9 @@ type consensusParamsArgs struct {
 	Height StrInt64 `json:"height"`
 }
 type unconfirmedTxsArgs struct {
-	Limit StrInt `json:"limit"`
+	Limit   StrInt `json:"limit"`
+	Page    StrInt `json:"page"`
+	PerPage StrInt `json:"per_page"`
 }
 type numUnconfirmedTxsArgs struct {
 }
@@ -116,6 +123,131 @@ type broadcastEvidenceArgs struct {
 	Evidence types.Evidence `json:"evidence"`
 }
 
+// rollkit API
+
+type fraudProofArgs struct {
+	Height StrInt64 `json:"height"`
+}
+type broadcastTxPreConfirmArgs struct {
+	Tx types.Tx `json:"tx"`
+}
+type broadcastInclusionListArgs struct {
+	TxHashes []bytes.HexBytes `json:"tx_hashes"`
+	Deadline StrInt64         `json:"deadline,omitempty"`
+}
+type inclusionListsArgs struct {
+}
+type appHashArgs struct {
+	Height StrInt64 `json:"height"`
+}
+type daLocationArgs struct {
+	Height StrInt64 `json:"height"`
+}
+type daBlockHeightsArgs struct {
+	DAHeight StrInt64 `json:"da_height"`
+}
+type daCostsArgs struct {
+	MinHeight StrInt64 `json:"min_height"`
+	MaxHeight StrInt64 `json:"max_height"`
+	Days      StrInt64 `json:"days"`
+}
+type signedHeaderArgs struct {
+	Height StrInt64 `json:"height"`
+}
+type validatorsWithProofArgs struct {
+	Height StrInt64 `json:"height"`
+}
+type stateProofArgs struct {
+	Store  string         `json:"store"`
+	Key    bytes.HexBytes `json:"key"`
+	Height StrInt64       `json:"height"`
+}
+type dataCommitmentArgs struct {
+	From StrInt64 `json:"from"`
+	To   StrInt64 `json:"to"`
+}
+type dataRootInclusionProofArgs struct {
+	Height StrInt64 `json:"height"`
+	From   StrInt64 `json:"from"`
+	To     StrInt64 `json:"to"`
+}
+type txProofArgs struct {
+	Hash []byte `json:"hash"`
+}
+type peerScoresArgs struct {
+}
+type netPeersArgs struct {
+}
+
+// admin API
+
+type adminRollbackArgs struct {
+}
+type adminPruneBlocksArgs struct {
+	RetainHeight StrInt64 `json:"retain_height"`
+}
+type adminResubmitBlocksArgs struct {
+	From StrInt64 `json:"from"`
+	To   StrInt64 `json:"to"`
+}
+type adminHaltBlockProcessingArgs struct {
+}
+type adminResumeBlockProcessingArgs struct {
+}
+type adminSetLogLevelArgs struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
+type adminDumpStateArgs struct {
+}
+type adminClearPeerScoresArgs struct {
+	// Peer is the ID of the peer to forget, or empty to forget all peers.
+	Peer string `json:"peer,omitempty"`
+}
+
+// ResultPeerScores is the result of peer_scores.
+type ResultPeerScores struct {
+	Peers []rollkitp2p.PeerScore `json:"peers"`
+}
+
+// ResultNetPeers is the result of net_peers.
+type ResultNetPeers struct {
+	NPeers int                   `json:"n_peers"`
+	Peers  []rollkitp2p.PeerInfo `json:"peers"`
+}
+
+// ResultRollback is the result of admin_rollback.
+type ResultRollback struct {
+	// Height is the height of the latest block after rollback.
+	Height uint64 `json:"height"`
+}
+
+// ResultPruneBlocks is the result of admin_prune_blocks.
+type ResultPruneBlocks struct {
+	Pruned uint64 `json:"pruned"`
+}
+
+// ResultResubmitBlocks is the result of admin_resubmit_blocks.
+type ResultResubmitBlocks struct {
+	Blocks int `json:"blocks"`
+}
+
+// ResultClearPeerScores is the result of admin_clear_peer_scores.
+type ResultClearPeerScores struct {
+	Cleared int `json:"cleared"`
+}
+
+// ResultStatus extends CometBFT status with rollkit specific information.
+type ResultStatus struct {
+	NodeInfo      p2p.DefaultNodeInfo  `json:"node_info"`
+	SyncInfo      ctypes.SyncInfo      `json:"sync_info"`
+	ValidatorInfo ctypes.ValidatorInfo `json:"validator_info"`
+	// ChainFaulty is set when the node was halted because of a valid state fraud proof.
+	ChainFaulty bool `json:"chain_faulty"`
+	// Rollkit describes rollkit specific state of full nodes.
+	Rollkit *node.ResultRollkitStatus `json:"rollkit,omitempty"`
+}
+
 type emptyResult struct{}
 
 // JSON-deserialization specific types
@@ -3,8 +3,10 @@ package rpc
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,8 +16,11 @@ import (
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	"github.com/rs/cors"
 	"golang.org/x/net/netutil"
+	"google.golang.org/grpc"
 
+	"github.com/rollkit/rollkit/block"
 	"github.com/rollkit/rollkit/node"
+	rpcgrpc "github.com/rollkit/rollkit/rpc/grpc"
 	"github.com/rollkit/rollkit/rpc/json"
 )
 
@@ -24,15 +29,43 @@ type Server struct {
 	*service.BaseService
 
 	config *config.RPCConfig
+	node   node.Node
 	client rpcclient.Client
 
-	server http.Server
+	server     http.Server
+	grpcServer *grpc.Server
+}
+
+// instrumentedNode is implemented by nodes exporting Prometheus metrics.
+type instrumentedNode interface {
+	MetricsConfig() (namespace string, labelsAndValues []string, ok bool)
+}
+
+// readinessNode is implemented by nodes able to report readiness to serve traffic.
+type readinessNode interface {
+	Ready(ctx context.Context) error
+}
+
+// stateExportNode is implemented by nodes able to export their state (see types.StateExportWriter).
+type stateExportNode interface {
+	ExportState(ctx context.Context, w io.Writer, height uint64) error
+	StateExportMaxConcurrent() uint64
+}
+
+// loggingNode is implemented by nodes with log levels of modules adjustable at runtime.
+type loggingNode interface {
+	ModuleLogger(module string) log.Logger
 }
 
 // NewServer creates new instance of Server with given configuration.
+// If the node supports module-scoped logging, the server logs with the "rpc" module logger of the node instead.
 func NewServer(node node.Node, config *config.RPCConfig, logger log.Logger) *Server {
+	if ln, ok := node.(loggingNode); ok {
+		logger = ln.ModuleLogger("rpc")
+	}
 	srv := &Server{
 		config: config,
+		node:   node,
 		client: node.GetClient(),
 	}
 	srv.BaseService = service.NewBaseService(logger, "RPC", srv)
@@ -48,7 +81,10 @@ func (s *Server) Client() rpcclient.Client {
 
 // OnStart is called when Server is started (see service.BaseService for details).
 func (s *Server) OnStart() error {
-	return s.startRPC()
+	if err := s.startRPC(); err != nil {
+		return err
+	}
+	return s.startGRPC()
 }
 
 // OnStop is called when Server is stopped (see service.BaseService for details).
@@ -58,6 +94,9 @@ func (s *Server) OnStop() {
 	if err := s.server.Shutdown(ctx); err != nil {
 		s.Logger.Error("error while shuting down RPC server", "error", err)
 	}
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
 }
 
 func (s *Server) startRPC() error {
@@ -82,10 +121,29 @@ func (s *Server) startRPC() error {
 		listener = netutil.LimitListener(listener, s.config.MaxOpenConnections)
 	}
 
-	handler, err := json.GetHTTPHandler(s.client, s.Logger)
+	metrics := json.NopMetrics()
+	if n, ok := s.node.(instrumentedNode); ok {
+		if namespace, labels, ok := n.MetricsConfig(); ok {
+			metrics = json.PrometheusMetrics(namespace, labels...)
+		}
+	}
+	handler, err := json.GetHTTPHandler(s.client, s.Logger, metrics)
 	if err != nil {
 		return err
 	}
+	rn, isReadinessNode := s.node.(readinessNode)
+	en, isStateExportNode := s.node.(stateExportNode)
+	if isReadinessNode || isStateExportNode {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		if isReadinessNode {
+			mux.HandleFunc("/ready", readyHandler(rn, s.Logger))
+		}
+		if isStateExportNode && en.StateExportMaxConcurrent() > 0 {
+			mux.HandleFunc("/state_export", stateExportHandler(en, en.StateExportMaxConcurrent(), s.Logger))
+		}
+		handler = mux
+	}
 
 	if s.config.IsCorsEnabled() {
 		s.Logger.Debug("CORS enabled",
@@ -111,6 +169,101 @@ func (s *Server) startRPC() error {
 	return nil
 }
 
+func (s *Server) startGRPC() error {
+	if s.config.GRPCListenAddress == "" {
+		return nil
+	}
+	fullNode, ok := s.node.(*node.FullNode)
+	if !ok {
+		s.Logger.Info("gRPC is only supported by full nodes - gRPC will not be exposed")
+		return nil
+	}
+	parts := strings.SplitN(s.config.GRPCListenAddress, "://", 2)
+	if len(parts) != 2 {
+		return errors.New("invalid gRPC listen address: expecting tcp://host:port")
+	}
+	listener, err := net.Listen(parts[0], parts[1])
+	if err != nil {
+		return err
+	}
+	if s.config.GRPCMaxOpenConnections != 0 {
+		s.Logger.Debug("limiting number of gRPC connections", "limit", s.config.GRPCMaxOpenConnections)
+		listener = netutil.LimitListener(listener, s.config.GRPCMaxOpenConnections)
+	}
+
+	s.grpcServer = rpcgrpc.NewServer(fullNode)
+	go func() {
+		s.Logger.Info("serving gRPC", "listen address", listener.Addr())
+		if err := s.grpcServer.Serve(listener); err != nil {
+			s.Logger.Error("error while serving gRPC", "error", err)
+		}
+	}()
+	return nil
+}
+
+// readyHandler responds with 200 if node is ready to serve traffic and 503 with the reason otherwise.
+func readyHandler(n readinessNode, logger log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		if err := n.Ready(ctx); err != nil {
+			logger.Debug("node is not ready", "error", err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// stateExportHandler streams state export of the node, with the application snapshot at height given by
+// the optional height query parameter (the latest snapshot by default). At most limit exports are served
+// concurrently, other requests are rejected with 429.
+func stateExportHandler(n stateExportNode, limit uint64, logger log.Logger) http.HandlerFunc {
+	sem := make(chan struct{}, limit)
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		default:
+			http.Error(w, "too many concurrent state exports", http.StatusTooManyRequests)
+			return
+		}
+		var height uint64
+		if h := r.URL.Query().Get("height"); h != "" {
+			var err error
+			if height, err = strconv.ParseUint(h, 10, 64); err != nil {
+				http.Error(w, "invalid height: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		sw := &startedWriter{Writer: w}
+		if err := n.ExportState(r.Context(), sw, height); err != nil {
+			logger.Error("failed to export state", "height", height, "error", err)
+			// once the stream is started, the status can't be changed; truncated stream is rejected on import
+			if sw.started {
+				return
+			}
+			status := http.StatusInternalServerError
+			if errors.Is(err, block.ErrSnapshotNotFound) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+		}
+	}
+}
+
+// startedWriter records whether anything was written to the underlying writer.
+type startedWriter struct {
+	io.Writer
+	started bool
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.Writer.Write(p)
+}
+
 func (s *Server) serve(listener net.Listener, handler http.Handler) error {
 	s.Logger.Info("serving HTTP", "listen address", listener.Addr())
 	s.server = http.Server{
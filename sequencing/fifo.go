@@ -0,0 +1,85 @@
+package sequencing
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// FIFOSequencer is an in-memory sequencer, ordering transactions of each rollup in order of submission.
+// It's intended for testing and for running a simple shared sequencer (see NewServer).
+type FIFOSequencer struct {
+	mtx     sync.Mutex
+	rollups map[string]*fifoQueue
+}
+
+type fifoQueue struct {
+	txs types.Txs
+	// lastBatch is the last non-empty batch delivered to the rollup
+	lastBatch *Batch
+	// batches contains hashes of all the batches delivered to the rollup
+	batches map[string]struct{}
+}
+
+var _ Sequencer = &FIFOSequencer{}
+
+// NewFIFOSequencer creates an empty in-memory sequencer.
+func NewFIFOSequencer() *FIFOSequencer {
+	return &FIFOSequencer{rollups: make(map[string]*fifoQueue)}
+}
+
+// SubmitRollupTransaction appends the transaction to the queue of the rollup.
+func (s *FIFOSequencer) SubmitRollupTransaction(_ context.Context, rollupID []byte, tx types.Tx) error {
+	if len(rollupID) == 0 {
+		return ErrInvalidRollupID
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	q := s.queue(rollupID)
+	q.txs = append(q.txs, tx)
+	return nil
+}
+
+// GetNextBatch returns all the queued transactions of the rollup as a single batch.
+// If lastBatchHash is set, but doesn't match the last delivered batch, the last batch is delivered again.
+func (s *FIFOSequencer) GetNextBatch(_ context.Context, rollupID []byte, lastBatchHash []byte) (*Batch, error) {
+	if len(rollupID) == 0 {
+		return nil, ErrInvalidRollupID
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	q := s.queue(rollupID)
+	if q.lastBatch != nil && lastBatchHash != nil && !bytes.Equal(lastBatchHash, q.lastBatch.Hash()) {
+		return q.lastBatch, nil
+	}
+	if len(q.txs) == 0 {
+		return &Batch{}, nil
+	}
+	batch := &Batch{Transactions: q.txs}
+	q.txs = nil
+	q.lastBatch = batch
+	q.batches[string(batch.Hash())] = struct{}{}
+	return batch, nil
+}
+
+// VerifyBatch checks if the batch of given hash was delivered to the rollup.
+func (s *FIFOSequencer) VerifyBatch(_ context.Context, rollupID []byte, batchHash []byte) (bool, error) {
+	if len(rollupID) == 0 {
+		return false, ErrInvalidRollupID
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := s.queue(rollupID).batches[string(batchHash)]
+	return ok, nil
+}
+
+func (s *FIFOSequencer) queue(rollupID []byte) *fifoQueue {
+	q, ok := s.rollups[string(rollupID)]
+	if !ok {
+		q = &fifoQueue{batches: make(map[string]struct{})}
+		s.rollups[string(rollupID)] = q
+	}
+	return q
+}
@@ -0,0 +1,72 @@
+package sequencing
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/rollkit/rollkit/types"
+	pb "github.com/rollkit/rollkit/types/pb/sequencing"
+)
+
+// RemoteSequencer is a client of an external (shared) sequencer, exposing SequencerService over gRPC.
+type RemoteSequencer struct {
+	conn   *grpc.ClientConn
+	client pb.SequencerServiceClient
+}
+
+var _ Sequencer = &RemoteSequencer{}
+
+// NewRemoteSequencer creates a client of the sequencer service at given address.
+// Connection is established lazily. If no dial options are given, connection is insecure.
+func NewRemoteSequencer(addr string, opts ...grpc.DialOption) (*RemoteSequencer, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial sequencer: %w", err)
+	}
+	return &RemoteSequencer{
+		conn:   conn,
+		client: pb.NewSequencerServiceClient(conn),
+	}, nil
+}
+
+// SubmitRollupTransaction submits the transaction of the rollup to the sequencer.
+func (s *RemoteSequencer) SubmitRollupTransaction(ctx context.Context, rollupID []byte, tx types.Tx) error {
+	_, err := s.client.SubmitRollupTransaction(ctx, &pb.SubmitRollupTransactionRequest{RollupId: rollupID, Tx: tx})
+	return err
+}
+
+// GetNextBatch requests the next batch of the rollup transactions from the sequencer.
+func (s *RemoteSequencer) GetNextBatch(ctx context.Context, rollupID []byte, lastBatchHash []byte) (*Batch, error) {
+	resp, err := s.client.GetNextBatch(ctx, &pb.GetNextBatchRequest{RollupId: rollupID, LastBatchHash: lastBatchHash})
+	if err != nil {
+		return nil, err
+	}
+	batch := &Batch{}
+	if resp.Batch != nil {
+		batch.Transactions = make(types.Txs, len(resp.Batch.Transactions))
+		for i, tx := range resp.Batch.Transactions {
+			batch.Transactions[i] = tx
+		}
+	}
+	return batch, nil
+}
+
+// VerifyBatch asks the sequencer if the batch of given hash was produced for the rollup.
+func (s *RemoteSequencer) VerifyBatch(ctx context.Context, rollupID []byte, batchHash []byte) (bool, error) {
+	resp, err := s.client.VerifyBatch(ctx, &pb.VerifyBatchRequest{RollupId: rollupID, BatchHash: batchHash})
+	if err != nil {
+		return false, err
+	}
+	return resp.Status, nil
+}
+
+// Close closes connection to the sequencer.
+func (s *RemoteSequencer) Close() error {
+	return s.conn.Close()
+}
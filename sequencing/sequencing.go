@@ -0,0 +1,47 @@
+package sequencing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ErrInvalidRollupID is returned by sequencers when requests are made for an unknown rollup.
+var ErrInvalidRollupID = errors.New("invalid rollup ID")
+
+// Sequencer orders transactions of rollups. Shared sequencer orders transactions of many rollups, and the block
+// manager of each rollup consumes ordered batches of its transactions, instead of reaping its own mempool.
+type Sequencer interface {
+	// SubmitRollupTransaction submits the transaction of the rollup to the sequencer.
+	SubmitRollupTransaction(ctx context.Context, rollupID []byte, tx types.Tx) error
+	// GetNextBatch returns the next batch of the rollup transactions. lastBatchHash is the hash of the last
+	// batch included by the rollup (nil if unknown), so the sequencer can re-deliver a batch that was not included,
+	// e.g. because the block manager was restarted.
+	// Returned batch is empty if there are no transactions to order.
+	GetNextBatch(ctx context.Context, rollupID []byte, lastBatchHash []byte) (*Batch, error)
+	// VerifyBatch checks if the batch of given hash was produced by the sequencer for the rollup.
+	VerifyBatch(ctx context.Context, rollupID []byte, batchHash []byte) (bool, error)
+}
+
+// Batch is an ordered list of rollup transactions.
+type Batch struct {
+	Transactions types.Txs
+}
+
+// Hash returns the Merkle root of the transactions in the batch.
+func (b *Batch) Hash() []byte {
+	return TxsHash(b.Transactions)
+}
+
+// TxsHash returns the hash of a batch of given transactions, e.g. to verify that block transactions were
+// ordered by the sequencer.
+func TxsHash(txs types.Txs) []byte {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		leaves[i] = tx
+	}
+	return merkle.HashFromByteSlices(leaves)
+}
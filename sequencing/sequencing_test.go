@@ -0,0 +1,94 @@
+package sequencing
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestFIFOSequencer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	rollupA, rollupB := []byte("rollup-a"), []byte("rollup-b")
+
+	s := NewFIFOSequencer()
+	require.NoError(s.SubmitRollupTransaction(ctx, rollupA, types.Tx("tx1")))
+	require.NoError(s.SubmitRollupTransaction(ctx, rollupB, types.Tx("other")))
+	require.NoError(s.SubmitRollupTransaction(ctx, rollupA, types.Tx("tx2")))
+	assert.ErrorIs(s.SubmitRollupTransaction(ctx, nil, types.Tx("tx")), ErrInvalidRollupID)
+
+	batch, err := s.GetNextBatch(ctx, rollupA, nil)
+	require.NoError(err)
+	assert.Equal(types.Txs{types.Tx("tx1"), types.Tx("tx2")}, batch.Transactions)
+
+	// batch is delivered again, until it's included by the rollup
+	require.NoError(s.SubmitRollupTransaction(ctx, rollupA, types.Tx("tx3")))
+	again, err := s.GetNextBatch(ctx, rollupA, []byte("stale hash"))
+	require.NoError(err)
+	assert.Equal(batch, again)
+
+	next, err := s.GetNextBatch(ctx, rollupA, batch.Hash())
+	require.NoError(err)
+	assert.Equal(types.Txs{types.Tx("tx3")}, next.Transactions)
+
+	empty, err := s.GetNextBatch(ctx, rollupA, next.Hash())
+	require.NoError(err)
+	assert.Empty(empty.Transactions)
+
+	ok, err := s.VerifyBatch(ctx, rollupA, batch.Hash())
+	require.NoError(err)
+	assert.True(ok)
+	ok, err = s.VerifyBatch(ctx, rollupB, batch.Hash())
+	require.NoError(err)
+	assert.False(ok)
+}
+
+func TestRemoteSequencer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	rollupID := []byte("test")
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := NewServer(NewFIFOSequencer())
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	s, err := NewRemoteSequencer("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	empty, err := s.GetNextBatch(ctx, rollupID, nil)
+	require.NoError(err)
+	assert.Empty(empty.Transactions)
+
+	require.NoError(s.SubmitRollupTransaction(ctx, rollupID, types.Tx("tx1")))
+	require.NoError(s.SubmitRollupTransaction(ctx, rollupID, types.Tx("tx2")))
+	batch, err := s.GetNextBatch(ctx, rollupID, nil)
+	require.NoError(err)
+	assert.Equal(types.Txs{types.Tx("tx1"), types.Tx("tx2")}, batch.Transactions)
+
+	ok, err := s.VerifyBatch(ctx, rollupID, TxsHash(types.Txs{types.Tx("tx1"), types.Tx("tx2")}))
+	require.NoError(err)
+	assert.True(ok)
+	ok, err = s.VerifyBatch(ctx, rollupID, TxsHash(types.Txs{types.Tx("tx2"), types.Tx("tx1")}))
+	require.NoError(err)
+	assert.False(ok)
+
+	_, err = s.GetNextBatch(ctx, nil, nil)
+	assert.Error(err)
+}
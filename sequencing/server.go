@@ -0,0 +1,59 @@
+package sequencing
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/rollkit/rollkit/types/pb/sequencing"
+)
+
+// NewServer returns a gRPC server exposing given sequencer as SequencerService.
+func NewServer(seq Sequencer, opts ...grpc.ServerOption) *grpc.Server {
+	srv := grpc.NewServer(opts...)
+	pb.RegisterSequencerServiceServer(srv, &service{seq: seq})
+	return srv
+}
+
+type service struct {
+	seq Sequencer
+}
+
+var _ pb.SequencerServiceServer = &service{}
+
+func (s *service) SubmitRollupTransaction(ctx context.Context, req *pb.SubmitRollupTransactionRequest) (*pb.SubmitRollupTransactionResponse, error) {
+	if err := s.seq.SubmitRollupTransaction(ctx, req.RollupId, req.Tx); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SubmitRollupTransactionResponse{}, nil
+}
+
+func (s *service) GetNextBatch(ctx context.Context, req *pb.GetNextBatchRequest) (*pb.GetNextBatchResponse, error) {
+	batch, err := s.seq.GetNextBatch(ctx, req.RollupId, req.LastBatchHash)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	txs := make([][]byte, len(batch.Transactions))
+	for i, tx := range batch.Transactions {
+		txs[i] = tx
+	}
+	return &pb.GetNextBatchResponse{Batch: &pb.Batch{Transactions: txs}}, nil
+}
+
+func (s *service) VerifyBatch(ctx context.Context, req *pb.VerifyBatchRequest) (*pb.VerifyBatchResponse, error) {
+	ok, err := s.seq.VerifyBatch(ctx, req.RollupId, req.BatchHash)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.VerifyBatchResponse{Status: ok}, nil
+}
+
+func toStatus(err error) error {
+	if errors.Is(err, ErrInvalidRollupID) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
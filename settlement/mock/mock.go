@@ -0,0 +1,154 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rollkit/rollkit/settlement"
+	"github.com/rollkit/rollkit/third_party/log"
+)
+
+// defaultChallengePeriod is used only if challenge period is not configured.
+const defaultChallengePeriod = 10 * time.Second
+
+type entry struct {
+	commitment  *settlement.Commitment
+	submittedAt time.Time
+	dispute     *settlement.Dispute
+}
+
+// Client is intended only for usage in tests.
+// It keeps commitments in-memory, and finalizes them after challenge period, unless they're disputed.
+type Client struct {
+	logger log.Logger
+
+	challengePeriod time.Duration
+
+	mtx         sync.Mutex
+	commitments map[uint64]*entry
+	// first and last are the heights of the first and the latest submitted commitments
+	first, last uint64
+}
+
+var _ settlement.Client = &Client{}
+
+// Init is called once to allow settlement client to read configuration and initialize resources.
+//
+// Config is the challenge period as duration string, e.g. "10s".
+func (c *Client) Init(config []byte, logger log.Logger) error {
+	c.logger = logger
+	c.commitments = make(map[uint64]*entry)
+	c.challengePeriod = defaultChallengePeriod
+	if len(config) > 0 {
+		period, err := time.ParseDuration(string(config))
+		if err != nil {
+			return err
+		}
+		c.challengePeriod = period
+	}
+	return nil
+}
+
+// Start implements Client interface.
+func (c *Client) Start() error {
+	c.logger.Debug("Mock Settlement Layer Client starting")
+	return nil
+}
+
+// Stop implements Client interface.
+func (c *Client) Stop() error {
+	c.logger.Debug("Mock Settlement Layer Client stopped")
+	return nil
+}
+
+// SubmitCommitments posts the passed in commitments to the settlement layer.
+//
+// Commitments have to be sequential. Commitments that were already submitted are ignored.
+func (c *Client) SubmitCommitments(ctx context.Context, commitments []*settlement.Commitment) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	for _, commitment := range commitments {
+		if c.last != 0 && commitment.Height <= c.last {
+			continue
+		}
+		if c.last != 0 && commitment.Height != c.last+1 {
+			return fmt.Errorf("%w: height %d, latest %d", settlement.ErrNonSequentialCommitment, commitment.Height, c.last)
+		}
+		if c.first == 0 {
+			c.first = commitment.Height
+		}
+		c.commitments[commitment.Height] = &entry{commitment: commitment, submittedAt: now}
+		c.last = commitment.Height
+		c.logger.Debug("Submitting commitment to settlement layer", "height", commitment.Height, "daHeight", commitment.DAHeight)
+	}
+	return nil
+}
+
+// FinalizedHeight returns the height of the latest commitment past its challenge period.
+//
+// Disputed commitment, and all commitments following it, are not finalized.
+func (c *Client) FinalizedHeight(ctx context.Context) (uint64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	finalized := uint64(0)
+	for h := c.first; h != 0 && h <= c.last; h++ {
+		e := c.commitments[h]
+		if e.dispute != nil || time.Since(e.submittedAt) < c.challengePeriod {
+			break
+		}
+		finalized = h
+	}
+	return finalized, nil
+}
+
+// Disputes returns disputes of commitments with heights greater or equal to fromHeight.
+func (c *Client) Disputes(ctx context.Context, fromHeight uint64) ([]settlement.Dispute, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if fromHeight < c.first {
+		fromHeight = c.first
+	}
+	var disputes []settlement.Dispute
+	for h := fromHeight; h != 0 && h <= c.last; h++ {
+		if d := c.commitments[h].dispute; d != nil {
+			disputes = append(disputes, *d)
+		}
+	}
+	return disputes, nil
+}
+
+// Commitment returns commitment submitted for given rollup height.
+func (c *Client) Commitment(height uint64) (*settlement.Commitment, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.commitments[height]
+	if !ok {
+		return nil, settlement.ErrCommitmentNotFound
+	}
+	return e.commitment, nil
+}
+
+// Dispute raises a dispute of commitment for given rollup height. It's used to simulate challenges in tests.
+//
+// Commitments that are already finalized can't be disputed.
+func (c *Client) Dispute(height uint64, challenger, reason string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.commitments[height]
+	if !ok {
+		return settlement.ErrCommitmentNotFound
+	}
+	if time.Since(e.submittedAt) >= c.challengePeriod {
+		return fmt.Errorf("commitment for height %d is past challenge period", height)
+	}
+	e.dispute = &settlement.Dispute{Height: height, Challenger: challenger, Reason: reason}
+	return nil
+}
@@ -0,0 +1,61 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/settlement"
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestFinalityAndDisputes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	c := &Client{}
+	require.NoError(c.Init([]byte("100ms"), test.NewFileLogger(t)))
+	require.NoError(c.Start())
+	defer func() { _ = c.Stop() }()
+
+	commitments := make([]*settlement.Commitment, 4)
+	for i := range commitments {
+		commitments[i] = settlement.NewCommitment(types.GetRandomBlock(uint64(i+1), 1), 7)
+	}
+	require.NoError(c.SubmitCommitments(ctx, commitments[:2]))
+	// already submitted commitments are ignored, gaps are rejected
+	require.NoError(c.SubmitCommitments(ctx, commitments[1:3]))
+	assert.ErrorIs(c.SubmitCommitments(ctx, []*settlement.Commitment{{Height: 5}}), settlement.ErrNonSequentialCommitment)
+
+	saved, err := c.Commitment(2)
+	require.NoError(err)
+	assert.Equal(commitments[1], saved)
+	_, err = c.Commitment(4)
+	assert.ErrorIs(err, settlement.ErrCommitmentNotFound)
+
+	// nothing is finalized during challenge period
+	finalized, err := c.FinalizedHeight(ctx)
+	require.NoError(err)
+	assert.Zero(finalized)
+
+	require.NoError(c.Dispute(2, "challenger", "invalid state transition"))
+	assert.ErrorIs(c.Dispute(4, "challenger", ""), settlement.ErrCommitmentNotFound)
+	disputes, err := c.Disputes(ctx, 1)
+	require.NoError(err)
+	assert.Equal([]settlement.Dispute{{Height: 2, Challenger: "challenger", Reason: "invalid state transition"}}, disputes)
+	disputes, err = c.Disputes(ctx, 3)
+	require.NoError(err)
+	assert.Empty(disputes)
+
+	// disputed commitment stops finalization
+	time.Sleep(150 * time.Millisecond)
+	finalized, err = c.FinalizedHeight(ctx)
+	require.NoError(err)
+	assert.Equal(uint64(1), finalized)
+	assert.Error(c.Dispute(1, "challenger", "too late"))
+}
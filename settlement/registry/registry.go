@@ -0,0 +1,51 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/rollkit/rollkit/settlement"
+	"github.com/rollkit/rollkit/settlement/mock"
+)
+
+// ErrAlreadyRegistered is used when user tries to register settlement layer using a name already used in registry.
+type ErrAlreadyRegistered struct {
+	name string
+}
+
+func (e *ErrAlreadyRegistered) Error() string {
+	return fmt.Sprintf("Settlement Layer '%s' already registered", e.name)
+}
+
+// this is a central registry for all Settlement Layer Clients
+var clients = map[string]func() settlement.Client{
+	"mock": func() settlement.Client { return &mock.Client{} },
+}
+
+// GetClient returns client identified by name.
+func GetClient(name string) settlement.Client {
+	f, ok := clients[name]
+	if !ok {
+		return nil
+	}
+	return f()
+}
+
+// Register adds a Settlement Layer Client to registry.
+//
+// If name was previously used in the registry, error is returned.
+func Register(name string, constructor func() settlement.Client) error {
+	if _, found := clients[name]; !found {
+		clients[name] = constructor
+		return nil
+	}
+	return &ErrAlreadyRegistered{name: name}
+}
+
+// RegisteredClients returns names of all settlement clients in registry.
+func RegisteredClients() []string {
+	registered := make([]string, 0, len(clients))
+	for name := range clients {
+		registered = append(registered, name)
+	}
+	return registered
+}
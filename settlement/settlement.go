@@ -0,0 +1,73 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+var (
+	// ErrCommitmentNotFound is used to indicate that there is no commitment for requested rollup height.
+	ErrCommitmentNotFound = errors.New("commitment not found")
+	// ErrNonSequentialCommitment is used to indicate that commitment doesn't follow the latest submitted commitment.
+	ErrNonSequentialCommitment = errors.New("commitment height doesn't follow the latest commitment")
+)
+
+// Commitment is a commitment to a rollup block and the rollup state, posted to the settlement layer.
+type Commitment struct {
+	// Height is the height of the rollup block.
+	Height uint64
+	// HeaderHash is the hash of the header of the rollup block.
+	HeaderHash types.Hash
+	// AppHash is the state root committed in the header of the rollup block.
+	AppHash types.Hash
+	// DAHeight is the height of DA layer block containing the rollup block.
+	DAHeight uint64
+}
+
+// NewCommitment creates commitment to the block, included in DA layer block at daHeight.
+func NewCommitment(block *types.Block, daHeight uint64) *Commitment {
+	return &Commitment{
+		Height:     block.Height(),
+		HeaderHash: block.Hash(),
+		AppHash:    block.SignedHeader.AppHash,
+		DAHeight:   daHeight,
+	}
+}
+
+// Dispute is a challenge of a commitment, raised on the settlement layer.
+type Dispute struct {
+	// Height is the height of the rollup block of disputed commitment.
+	Height uint64
+	// Challenger identifies the party that raised the dispute on the settlement layer.
+	Challenger string
+	// Reason may contain settlement layer specific details of the dispute (like fraud proof hash).
+	Reason string
+}
+
+// Client defines generic interface for posting commitments to the settlement layer and reading their finality.
+// It also contains life-cycle methods.
+//
+// Sovereign rollups don't use settlement layer at all.
+type Client interface {
+	// Init is called once to allow settlement client to read configuration and initialize resources.
+	Init(config []byte, logger log.Logger) error
+
+	// Start is called once, after Init. It's implementation should start operation of Client.
+	Start() error
+
+	// Stop is called once, when Client is no longer needed.
+	Stop() error
+
+	// SubmitCommitments posts the passed in commitments, ordered by height, to the settlement layer.
+	SubmitCommitments(ctx context.Context, commitments []*Commitment) error
+
+	// FinalizedHeight returns the height of the latest rollup block with finalized commitment.
+	// Zero means that no commitment is finalized yet.
+	FinalizedHeight(ctx context.Context) (uint64, error)
+
+	// Disputes returns unresolved disputes of commitments with heights greater or equal to fromHeight.
+	Disputes(ctx context.Context, fromHeight uint64) ([]Dispute, error)
+}
@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	cmcrypto "github.com/cometbft/cometbft/crypto"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	pb "github.com/rollkit/rollkit/types/pb/signer"
+)
+
+// RemoteSigner signs messages with a key held by a remote signer service (see NewServer), e.g. on a dedicated
+// machine or backed by an HSM.
+type RemoteSigner struct {
+	conn    *grpc.ClientConn
+	client  pb.SignerServiceClient
+	chainID string
+	pubKey  crypto.PubKey
+	// cmPubKey is used to verify signatures returned by the remote signer
+	cmPubKey cmcrypto.PubKey
+}
+
+var _ Signer = &RemoteSigner{}
+
+// NewRemoteSigner connects to the signer service at given address and fetches the public key of the signer.
+// Connection is retried (with backoff) until ctx is done. Connection is secured with mutual TLS, so tlsConf has to
+// contain the client certificate of the node (see ClientTLSConfig).
+func NewRemoteSigner(ctx context.Context, addr string, chainID string, tlsConf *tls.Config, opts ...grpc.DialOption) (*RemoteSigner, error) {
+	if tlsConf == nil || (len(tlsConf.Certificates) == 0 && tlsConf.GetClientCertificate == nil) {
+		return nil, ErrMutualTLSRequired
+	}
+	opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConf)))
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial remote signer: %w", err)
+	}
+	client := pb.NewSignerServiceClient(conn)
+	resp, err := client.GetPubKey(ctx, &pb.GetPubKeyRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to get public key from remote signer: %w", err)
+	}
+	pubKey, err := crypto.UnmarshalPublicKey(resp.PubKey)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to unmarshal public key of remote signer: %w", err)
+	}
+	cmPubKey, err := CometPubKey(pubKey)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unsupported public key of remote signer: %w", err)
+	}
+	return &RemoteSigner{
+		conn:     conn,
+		client:   client,
+		chainID:  chainID,
+		pubKey:   pubKey,
+		cmPubKey: cmPubKey,
+	}, nil
+}
+
+// PubKey returns the public key of the remote signer.
+func (s *RemoteSigner) PubKey() crypto.PubKey {
+	return s.pubKey
+}
+
+// Sign requests signature of the message from the remote signer.
+// If the signer is not reachable, request waits for reconnection until ctx is done.
+func (s *RemoteSigner) Sign(ctx context.Context, height uint64, msg []byte) ([]byte, error) {
+	resp, err := s.client.Sign(ctx, &pb.SignRequest{Message: msg, ChainId: s.chainID, Height: height}, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, err
+	}
+	if !VerifySignature(s.cmPubKey, msg, resp.Signature) {
+		return nil, fmt.Errorf("invalid signature returned by remote signer")
+	}
+	return resp.Signature, nil
+}
+
+// Close closes connection to the remote signer.
+func (s *RemoteSigner) Close() error {
+	return s.conn.Close()
+}
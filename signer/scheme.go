@@ -0,0 +1,215 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	cmcrypto "github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/crypto/secp256k1"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	cryptopb "github.com/libp2p/go-libp2p/core/crypto/pb"
+)
+
+// Signature schemes supported for signing block headers. Scheme of the chain is selected in genesis, as the first
+// public key type allowed by validator consensus parameters (ed25519 if none is given).
+const (
+	SchemeEd25519   = cmtypes.ABCIPubKeyTypeEd25519
+	SchemeSecp256k1 = cmtypes.ABCIPubKeyTypeSecp256k1
+)
+
+// secp256k1SignatureSize is the size of compact secp256k1 signature, allowing recovery of the public key.
+const secp256k1SignatureSize = 65
+
+var (
+	// ErrUnsupportedScheme is returned for keys of unsupported signature schemes.
+	ErrUnsupportedScheme = errors.New("unsupported signature scheme")
+	// ErrNotRecoverable is returned when public key can't be recovered from signatures of the signature scheme.
+	ErrNotRecoverable = errors.New("public key is not recoverable from signature")
+)
+
+// Verifier verifies signatures of block headers made with a signature scheme.
+type Verifier interface {
+	// Verify returns true if sig is a valid signature of msg made by the owner of pubKey.
+	Verify(pubKey cmcrypto.PubKey, msg []byte, sig []byte) bool
+}
+
+// AddressRecoverer is implemented by verifiers of schemes allowing recovery of signer address from a signature.
+type AddressRecoverer interface {
+	// RecoverAddress returns address of the key that made signature sig of msg.
+	RecoverAddress(msg []byte, sig []byte) (cmcrypto.Address, error)
+}
+
+// PubKeyRecoverer is implemented by verifiers of schemes allowing recovery of signer public key from a signature.
+type PubKeyRecoverer interface {
+	// RecoverPubKey returns public key that made signature sig of msg.
+	RecoverPubKey(msg []byte, sig []byte) (cmcrypto.PubKey, error)
+}
+
+// NewVerifier returns verifier of the signature scheme.
+func NewVerifier(scheme string) (Verifier, error) {
+	switch scheme {
+	case SchemeEd25519:
+		return ed25519Verifier{}, nil
+	case SchemeSecp256k1:
+		return secp256k1Verifier{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, scheme)
+	}
+}
+
+// SchemeFromGenesis returns signature scheme of the chain described by genesis.
+func SchemeFromGenesis(genesis *cmtypes.GenesisDoc) string {
+	if genesis.ConsensusParams == nil || len(genesis.ConsensusParams.Validator.PubKeyTypes) == 0 {
+		return SchemeEd25519
+	}
+	return genesis.ConsensusParams.Validator.PubKeyTypes[0]
+}
+
+// VerifySignature verifies signature of msg made by the owner of pubKey, using signature scheme of the key.
+func VerifySignature(pubKey cmcrypto.PubKey, msg []byte, sig []byte) bool {
+	verifier, err := NewVerifier(pubKey.Type())
+	if err != nil {
+		return false
+	}
+	return verifier.Verify(pubKey, msg, sig)
+}
+
+// RecoverPubKey returns public key that made signature sig of msg, if the signature scheme allows recovery of
+// public keys (secp256k1). Otherwise ErrNotRecoverable is returned.
+func RecoverPubKey(scheme string, msg []byte, sig []byte) (cmcrypto.PubKey, error) {
+	verifier, err := NewVerifier(scheme)
+	if err != nil {
+		return nil, err
+	}
+	recoverer, ok := verifier.(PubKeyRecoverer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotRecoverable, scheme)
+	}
+	return recoverer.RecoverPubKey(msg, sig)
+}
+
+// PubKeyFromBytes returns CometBFT public key of the signature scheme, encoded as raw bytes.
+func PubKeyFromBytes(scheme string, raw []byte) (cmcrypto.PubKey, error) {
+	switch scheme {
+	case SchemeEd25519:
+		if len(raw) != ed25519.PubKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key size: expected %d, got %d", ed25519.PubKeySize, len(raw))
+		}
+		return ed25519.PubKey(raw), nil
+	case SchemeSecp256k1:
+		if len(raw) != secp256k1.PubKeySize {
+			return nil, fmt.Errorf("invalid secp256k1 public key size: expected %d, got %d", secp256k1.PubKeySize, len(raw))
+		}
+		return secp256k1.PubKey(raw), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, scheme)
+	}
+}
+
+// AddressFromBytes derives address of the public key of the signature scheme, encoded as raw bytes. Addresses are
+// derived like in CometBFT: SHA-256 truncated to 20 bytes for ed25519, and RIPEMD-160 of SHA-256 of the compressed
+// key for secp256k1.
+func AddressFromBytes(scheme string, raw []byte) (cmcrypto.Address, error) {
+	pubKey, err := PubKeyFromBytes(scheme, raw)
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.Address(), nil
+}
+
+// Address derives address of libp2p public key, used as ProposerAddress of headers signed with the key and as
+// address of the key in aggregator sets.
+func Address(pubKey crypto.PubKey) (cmcrypto.Address, error) {
+	cmPubKey, err := CometPubKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return cmPubKey.Address(), nil
+}
+
+// CometPubKey converts libp2p public key into CometBFT public key, used in aggregator sets.
+func CometPubKey(pubKey crypto.PubKey) (cmcrypto.PubKey, error) {
+	raw, err := pubKey.Raw()
+	if err != nil {
+		return nil, err
+	}
+	switch pubKey.Type() {
+	case cryptopb.KeyType_Ed25519:
+		return ed25519.PubKey(raw), nil
+	case cryptopb.KeyType_Secp256k1:
+		return secp256k1.PubKey(raw), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, pubKey.Type())
+	}
+}
+
+// signMessage signs msg with the key, using signature scheme of the key.
+func signMessage(key crypto.PrivKey, msg []byte) ([]byte, error) {
+	switch key.Type() {
+	case cryptopb.KeyType_Ed25519:
+		return key.Sign(msg)
+	case cryptopb.KeyType_Secp256k1:
+		raw, err := key.Raw()
+		if err != nil {
+			return nil, err
+		}
+		privKey, _ := btcec.PrivKeyFromBytes(raw)
+		hash := sha256.Sum256(msg)
+		return btcecdsa.SignCompact(privKey, hash[:], true)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, key.Type())
+	}
+}
+
+type ed25519Verifier struct{}
+
+func (ed25519Verifier) Verify(pubKey cmcrypto.PubKey, msg []byte, sig []byte) bool {
+	pk, ok := pubKey.(ed25519.PubKey)
+	return ok && pk.VerifySignature(msg, sig)
+}
+
+// secp256k1Verifier verifies compact secp256k1 signatures, by recovering the public key from the signature.
+type secp256k1Verifier struct{}
+
+func (v secp256k1Verifier) Verify(pubKey cmcrypto.PubKey, msg []byte, sig []byte) bool {
+	pk, ok := pubKey.(secp256k1.PubKey)
+	if !ok {
+		return false
+	}
+	recovered, err := v.recoverPubKey(msg, sig)
+	return err == nil && bytes.Equal(recovered, pk)
+}
+
+func (v secp256k1Verifier) RecoverPubKey(msg []byte, sig []byte) (cmcrypto.PubKey, error) {
+	pk, err := v.recoverPubKey(msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+func (v secp256k1Verifier) RecoverAddress(msg []byte, sig []byte) (cmcrypto.Address, error) {
+	pk, err := v.recoverPubKey(msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	return pk.Address(), nil
+}
+
+func (secp256k1Verifier) recoverPubKey(msg []byte, sig []byte) (secp256k1.PubKey, error) {
+	if len(sig) != secp256k1SignatureSize {
+		return nil, fmt.Errorf("invalid signature size: expected %d, got %d", secp256k1SignatureSize, len(sig))
+	}
+	hash := sha256.Sum256(msg)
+	pk, _, err := btcecdsa.RecoverCompact(sig, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return pk.SerializeCompressed(), nil
+}
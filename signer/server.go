@@ -0,0 +1,131 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cometbft/cometbft/libs/tempfile"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	pbrollkit "github.com/rollkit/rollkit/types/pb/rollkit"
+	pb "github.com/rollkit/rollkit/types/pb/signer"
+)
+
+// NewServer returns a gRPC server exposing signer service, signing block headers of given chain with the private key.
+// Clients have to be authenticated with mutual TLS (see ServerTLSConfig).
+//
+// Signed messages are decoded as block headers, and the chain ID and height are taken from the header. To prevent
+// double signing, server refuses to sign a header for a height lower than the last signed height, or a different
+// header for the same height. The last signed height, header and signature are persisted in the state file, before
+// the signature is returned, so the protection survives restarts of the signer.
+func NewServer(key crypto.PrivKey, chainID string, tlsConf *tls.Config, statePath string, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	if tlsConf == nil || tlsConf.ClientAuth != tls.RequireAndVerifyClientCert {
+		return nil, ErrMutualTLSRequired
+	}
+	state, err := loadSignState(statePath)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+	srv := grpc.NewServer(opts...)
+	pb.RegisterSignerServiceServer(srv, &service{key: key, chainID: chainID, statePath: statePath, last: state})
+	return srv, nil
+}
+
+// signState is the last signed header, persisted by the signer service.
+type signState struct {
+	Height    uint64 `json:"height"`
+	Message   []byte `json:"message"`
+	Signature []byte `json:"signature"`
+}
+
+// loadSignState returns the state persisted in the file, or empty state if the file doesn't exist.
+func loadSignState(path string) (signState, error) {
+	var state signState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read signer state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to decode signer state: %w", err)
+	}
+	return state, nil
+}
+
+type service struct {
+	key       crypto.PrivKey
+	chainID   string
+	statePath string
+
+	mtx  sync.Mutex
+	last signState
+}
+
+var _ pb.SignerServiceServer = &service{}
+
+func (s *service) GetPubKey(_ context.Context, _ *pb.GetPubKeyRequest) (*pb.GetPubKeyResponse, error) {
+	pubKey, err := crypto.MarshalPublicKey(s.key.GetPublic())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.GetPubKeyResponse{PubKey: pubKey}, nil
+}
+
+func (s *service) Sign(_ context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	var header pbrollkit.Header
+	if err := header.Unmarshal(req.Message); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "message is not a block header: %s", err)
+	}
+	if header.ChainId != s.chainID {
+		return nil, status.Errorf(codes.InvalidArgument, "unexpected chain ID %q", header.ChainId)
+	}
+	if req.ChainId != header.ChainId || req.Height != header.Height {
+		return nil, status.Errorf(codes.InvalidArgument, "chain ID and height of request don't match the header")
+	}
+	height := header.Height
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	last := s.last
+	if height < last.Height || (height == last.Height && last.Message != nil && !bytes.Equal(req.Message, last.Message)) {
+		return nil, status.Errorf(codes.FailedPrecondition, "refusing to sign at height %d, last signed height is %d", height, last.Height)
+	}
+	// signing the same header again (e.g. after timeout on the client side) is safe
+	if height == last.Height && last.Signature != nil {
+		return &pb.SignResponse{Signature: last.Signature}, nil
+	}
+	sig, err := signMessage(s.key, req.Message)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	state := signState{Height: height, Message: req.Message, Signature: sig}
+	if err := s.saveState(state); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.last = state
+	return &pb.SignResponse{Signature: sig}, nil
+}
+
+// saveState atomically replaces the state file.
+func (s *service) saveState(state signState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode signer state: %w", err)
+	}
+	if err := tempfile.WriteFileAtomic(s.statePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save signer state: %w", err)
+	}
+	return nil
+}
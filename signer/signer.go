@@ -0,0 +1,86 @@
+package signer
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+)
+
+// Signer signs block headers on behalf of the block proposer.
+type Signer interface {
+	// PubKey returns the public key of the signer.
+	PubKey() crypto.PubKey
+	// Sign signs the message (marshaled header of the block at given height).
+	Sign(ctx context.Context, height uint64, msg []byte) ([]byte, error)
+}
+
+// AggregateSigner is implemented by signers able to make BLS signatures, that can be aggregated with signatures
+// of other aggregators into a single commit signature.
+type AggregateSigner interface {
+	Signer
+	// BLSPubKey returns the BLS public key of the signer.
+	BLSPubKey() (bls.PubKey, error)
+	// SignBLS signs the message (marshaled header of the block at given height) with the BLS key.
+	SignBLS(ctx context.Context, height uint64, msg []byte) ([]byte, error)
+}
+
+// LocalSigner signs messages with a private key held in memory.
+type LocalSigner struct {
+	key crypto.PrivKey
+}
+
+var _ AggregateSigner = &LocalSigner{}
+
+// NewLocalSigner creates a signer using given private key.
+func NewLocalSigner(key crypto.PrivKey) *LocalSigner {
+	return &LocalSigner{key: key}
+}
+
+// PubKey returns the public key of the signer.
+func (s *LocalSigner) PubKey() crypto.PubKey {
+	return s.key.GetPublic()
+}
+
+// Sign signs the message with the private key, using signature scheme of the key.
+func (s *LocalSigner) Sign(_ context.Context, _ uint64, msg []byte) ([]byte, error) {
+	return signMessage(s.key, msg)
+}
+
+// BLSPubKey returns the BLS public key of the signer.
+func (s *LocalSigner) BLSPubKey() (bls.PubKey, error) {
+	key, err := s.blsKey()
+	if err != nil {
+		return nil, err
+	}
+	return key.PubKey(), nil
+}
+
+// SignBLS signs the message with the BLS key.
+func (s *LocalSigner) SignBLS(_ context.Context, _ uint64, msg []byte) ([]byte, error) {
+	key, err := s.blsKey()
+	if err != nil {
+		return nil, err
+	}
+	return key.Sign(msg), nil
+}
+
+// BLSProofOfPossession returns the proof of possession of the BLS key, required to configure the BLS public key
+// as an aggregator key.
+func (s *LocalSigner) BLSProofOfPossession() ([]byte, error) {
+	key, err := s.blsKey()
+	if err != nil {
+		return nil, err
+	}
+	return key.ProvePossession(), nil
+}
+
+// blsKey derives the BLS key from the private key, so aggregators don't have to manage additional keys.
+func (s *LocalSigner) blsKey() (bls.PrivKey, error) {
+	raw, err := s.key.Raw()
+	if err != nil {
+		return nil, err
+	}
+	return bls.PrivKeyFromSeed(raw)
+}
@@ -0,0 +1,251 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	pbrollkit "github.com/rollkit/rollkit/types/pb/rollkit"
+)
+
+func TestLocalSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	s := NewLocalSigner(key)
+	assert.True(key.GetPublic().Equals(s.PubKey()))
+
+	sig, err := s.Sign(context.Background(), 1, []byte("header"))
+	require.NoError(err)
+	ok, err := s.PubKey().Verify([]byte("header"), sig)
+	require.NoError(err)
+	assert.True(ok)
+
+	blsPubKey, err := s.BLSPubKey()
+	require.NoError(err)
+	blsSig, err := s.SignBLS(context.Background(), 1, []byte("header"))
+	require.NoError(err)
+	assert.True(blsPubKey.VerifySignature([]byte("header"), blsSig))
+	proof, err := s.BLSProofOfPossession()
+	require.NoError(err)
+	assert.NoError(blsPubKey.VerifyPossession(proof))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(err)
+	dir := t.TempDir()
+	writeTestCerts(t, dir)
+	serverTLS, err := ServerTLSConfig(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(err)
+	clientTLS, err := ClientTLSConfig(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(err)
+	statePath := filepath.Join(dir, "signer_state.json")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	connect := func(chainID string) *RemoteSigner {
+		listener := bufconn.Listen(1024 * 1024)
+		srv, err := NewServer(key, "test", serverTLS, statePath)
+		require.NoError(err)
+		go func() { _ = srv.Serve(listener) }()
+		t.Cleanup(srv.Stop)
+		s, err := NewRemoteSigner(ctx, "bufnet", chainID, clientTLS,
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }))
+		require.NoError(err)
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	}
+	header := func(chainID string, height uint64, appHash byte) []byte {
+		msg, err := (&pbrollkit.Header{Version: &pbrollkit.Version{}, ChainId: chainID, Height: height, AppHash: []byte{appHash}}).Marshal()
+		require.NoError(err)
+		return msg
+	}
+
+	// mutual TLS is required
+	_, err = NewRemoteSigner(ctx, "bufnet", "test", &tls.Config{MinVersion: tls.VersionTLS13})
+	assert.ErrorIs(err, ErrMutualTLSRequired)
+	_, err = NewServer(key, "test", &tls.Config{MinVersion: tls.VersionTLS13}, statePath)
+	assert.ErrorIs(err, ErrMutualTLSRequired)
+
+	s := connect("test")
+	assert.True(key.GetPublic().Equals(s.PubKey()))
+
+	sig, err := s.Sign(ctx, 2, header("test", 2, 1))
+	require.NoError(err)
+	ok, err := key.GetPublic().Verify(header("test", 2, 1), sig)
+	require.NoError(err)
+	assert.True(ok)
+
+	// the same header can be signed again
+	again, err := s.Sign(ctx, 2, header("test", 2, 1))
+	require.NoError(err)
+	assert.Equal(sig, again)
+
+	// double signing and signing at lower height are rejected
+	_, err = s.Sign(ctx, 2, header("test", 2, 2))
+	assert.Error(err)
+	_, err = s.Sign(ctx, 1, header("test", 1, 1))
+	assert.Error(err)
+	// height is taken from the header, not from the request
+	_, err = s.Sign(ctx, 4, header("test", 1, 1))
+	assert.Error(err)
+	_, err = s.Sign(ctx, 3, []byte("not a header"))
+	assert.Error(err)
+
+	_, err = s.Sign(ctx, 3, header("test", 3, 1))
+	assert.NoError(err)
+
+	// last signed header is persisted
+	restarted := connect("test")
+	_, err = restarted.Sign(ctx, 3, header("test", 3, 2))
+	assert.Error(err)
+	_, err = restarted.Sign(ctx, 4, header("test", 4, 1))
+	assert.NoError(err)
+
+	// signer of another chain is rejected
+	other := connect("other")
+	_, err = other.Sign(ctx, 5, header("other", 5, 1))
+	assert.Error(err)
+}
+
+// writeTestCerts writes a CA certificate, and server and client certificates signed by the CA to the directory.
+func writeTestCerts(t *testing.T, dir string) {
+	t.Helper()
+	require := require.New(t)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(err)
+	writePEM := func(name, blockType string, der []byte) {
+		require.NoError(os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	}
+	writePEM("ca.crt", "CERTIFICATE", caDER)
+
+	for i, name := range []string{"server", "client"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"bufnet"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(err)
+		writePEM(name+".crt", "CERTIFICATE", der)
+		writePEM(name+".key", "EC PRIVATE KEY", keyDER)
+	}
+}
+
+func TestSignatureSchemes(t *testing.T) {
+	ed25519Key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	secp256k1Key, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		key         crypto.PrivKey
+		scheme      string
+		recoverable bool
+	}{
+		{"ed25519", ed25519Key, SchemeEd25519, false},
+		{"secp256k1", secp256k1Key, SchemeSecp256k1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			pubKey, err := CometPubKey(c.key.GetPublic())
+			require.NoError(err)
+			assert.Equal(c.scheme, pubKey.Type())
+
+			sig, err := NewLocalSigner(c.key).Sign(context.Background(), 1, []byte("header"))
+			require.NoError(err)
+			assert.True(VerifySignature(pubKey, []byte("header"), sig))
+			assert.False(VerifySignature(pubKey, []byte("other header"), sig))
+
+			verifier, err := NewVerifier(c.scheme)
+			require.NoError(err)
+			assert.True(verifier.Verify(pubKey, []byte("header"), sig))
+
+			if recoverer, ok := verifier.(AddressRecoverer); ok {
+				address, err := recoverer.RecoverAddress([]byte("header"), sig)
+				require.NoError(err)
+				assert.Equal(pubKey.Address(), address)
+			}
+
+			address, err := Address(c.key.GetPublic())
+			require.NoError(err)
+			assert.Equal(pubKey.Address(), address)
+			address, err = AddressFromBytes(c.scheme, pubKey.Bytes())
+			require.NoError(err)
+			assert.Equal(pubKey.Address(), address)
+			_, err = AddressFromBytes(c.scheme, pubKey.Bytes()[1:])
+			assert.Error(err)
+
+			recovered, err := RecoverPubKey(c.scheme, []byte("header"), sig)
+			if !c.recoverable {
+				assert.ErrorIs(err, ErrNotRecoverable)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(pubKey, recovered)
+			recovered, err = RecoverPubKey(c.scheme, []byte("other header"), sig)
+			if err == nil {
+				assert.NotEqual(pubKey, recovered)
+			}
+		})
+	}
+
+	// signatures are not valid across schemes
+	ed25519PubKey, err := CometPubKey(ed25519Key.GetPublic())
+	require.NoError(t, err)
+	sig, err := NewLocalSigner(secp256k1Key).Sign(context.Background(), 1, []byte("header"))
+	require.NoError(t, err)
+	assert.False(t, VerifySignature(ed25519PubKey, []byte("header"), sig))
+
+	_, err = NewVerifier("sr25519")
+	assert.ErrorIs(t, err, ErrUnsupportedScheme)
+}
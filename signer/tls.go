@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrMutualTLSRequired is returned when connection to (or from) the remote signer is not authenticated on both sides.
+var ErrMutualTLSRequired = errors.New("remote signer requires mutual TLS")
+
+// ClientTLSConfig returns TLS configuration of the connection to the remote signer. The node is authenticated with
+// given certificate, and the signer has to present a certificate signed by given CA.
+func ClientTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, pool, err := loadTLSFiles(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// ServerTLSConfig returns TLS configuration of the signer service. The signer is authenticated with given
+// certificate, and clients have to present a certificate signed by given CA.
+func ServerTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, pool, err := loadTLSFiles(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+func loadTLSFiles(certFile, keyFile, caFile string) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return tls.Certificate{}, nil, fmt.Errorf("no CA certificates found in %s", caFile)
+	}
+	return cert, pool, nil
+}
@@ -0,0 +1,61 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+)
+
+// DACostQueryPath is the ABCI query path used to report costs of DA submissions to the application, for example
+// to price transactions in a fee market. Request data is JSON encoded DASubmissionCost.
+const DACostQueryPath = "/rollkit/da_cost"
+
+// DASubmissionCost is the cost of a single submission of blocks to the DA layer.
+type DASubmissionCost struct {
+	// DAHeight is the height of DA block containing the blocks.
+	DAHeight uint64 `json:"da_height"`
+	// Fee is the fee paid for the submission, in the smallest unit of the DA layer token.
+	Fee uint64 `json:"fee"`
+	// BlobSize is the total size of submitted blobs in bytes.
+	BlobSize uint64 `json:"blob_size"`
+	// Heights are heights of submitted blocks.
+	Heights []uint64 `json:"heights"`
+}
+
+// DACostReporter feeds costs of DA submissions back to the application.
+//
+// DACostReporter is invoked by block producer after every successful submission of blocks to the DA layer.
+type DACostReporter interface {
+	// ReportDACost reports the cost of the submission.
+	ReportDACost(cost DASubmissionCost) error
+}
+
+// ABCIDACostReporter reports costs of DA submissions to the application using ABCI Query.
+type ABCIDACostReporter struct {
+	proxyApp proxy.AppConnQuery
+}
+
+var _ DACostReporter = &ABCIDACostReporter{}
+
+// NewABCIDACostReporter creates new instance of ABCIDACostReporter.
+func NewABCIDACostReporter(proxyApp proxy.AppConnQuery) *ABCIDACostReporter {
+	return &ABCIDACostReporter{proxyApp: proxyApp}
+}
+
+// ReportDACost sends the cost of the submission to the application.
+func (r *ABCIDACostReporter) ReportDACost(cost DASubmissionCost) error {
+	data, err := json.Marshal(cost)
+	if err != nil {
+		return err
+	}
+	resp, err := r.proxyApp.QuerySync(abci.RequestQuery{Path: DACostQueryPath, Data: data})
+	if err != nil {
+		return err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return fmt.Errorf("DA cost query failed with code %d: %s", resp.Code, resp.Log)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package state
+
+import (
+	"encoding/json"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/test/mocks"
+)
+
+func TestABCIDACostReporter(t *testing.T) {
+	require := require.New(t)
+
+	cost := DASubmissionCost{DAHeight: 10, Fee: 177, BlobSize: 1000, Heights: []uint64{5, 6}}
+	data, err := json.Marshal(cost)
+	require.NoError(err)
+
+	app := &mocks.Application{}
+	app.On("Query", abci.RequestQuery{Path: DACostQueryPath, Data: data}).Return(abci.ResponseQuery{Code: abci.CodeTypeOK}).Once()
+	app.On("Query", mock.Anything).Return(abci.ResponseQuery{Code: 1, Log: "unsupported"})
+
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(err)
+	reporter := NewABCIDACostReporter(proxy.NewAppConnQuery(client, proxy.NopMetrics()))
+
+	require.NoError(reporter.ReportDACost(cost))
+	assert.ErrorContains(t, reporter.ReportDACost(cost), "unsupported")
+	app.AssertExpectations(t)
+}
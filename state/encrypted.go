@@ -0,0 +1,239 @@
+package state
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/types"
+)
+
+// EncryptedTxsCodespace is the codespace of responses to encrypted and decryption key transactions.
+const EncryptedTxsCodespace = "encrypted"
+
+// Response codes of encrypted and decryption key transactions that were not executed.
+const (
+	CodeInvalidEncryptedTx uint32 = iota + 1
+	CodeInvalidDecryptionKey
+	CodeMisplacedDecryptionKey
+)
+
+// EncryptedTxs configures delayed execution of encrypted transactions (see types.EncryptTx).
+//
+// Encrypted transaction (envelope) targeting height T can be included in blocks in range (T-Window, T]. Only the fee
+// transaction of the envelope is executed in its place. Transactions of envelopes targeting T, with successfully
+// executed fee transactions, are decrypted and executed in the first slot of the block at height T+Delay, which has to
+// be the decryption key transaction of T (see types.DecryptionKeyTx), in order of their inclusion. Decryption key
+// transactions in any other slot are not executed. Blocks and block responses of heights (T-Window, T] have to be
+// available for LoadBlock and LoadBlockResponses. Configuration has to be the same on all nodes.
+type EncryptedTxs struct {
+	// Key is the public key of the decryption committee.
+	Key bls.PubKey
+	// Delay is the number of blocks between the target height of encrypted transactions and their execution.
+	Delay uint64
+	// Window is the number of heights, in which encrypted transactions targeting a height can be included.
+	Window uint64
+	// LoadBlock loads committed blocks.
+	LoadBlock func(height uint64) (*types.Block, error)
+	// LoadBlockResponses loads responses to committed blocks.
+	LoadBlockResponses func(height uint64) (*cmstate.ABCIResponses, error)
+}
+
+// ParseCommitteeKey decodes the hex encoded public key of the decryption committee.
+func ParseCommitteeKey(encoded string) (bls.PubKey, error) {
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key of the decryption committee: %w", err)
+	}
+	if err := bls.PubKey(key).ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid key of the decryption committee: %w", err)
+	}
+	return key, nil
+}
+
+// SetEncryptedTxs enables delayed execution of encrypted transactions.
+func (e *BlockExecutor) SetEncryptedTxs(conf *EncryptedTxs) {
+	e.encryptedTxs = conf
+}
+
+// CheckEncryptedTx returns the transaction that should be checked by the application in place of tx, or the
+// response to CheckTx of transactions that can't be checked by the application. The fee transaction of an
+// encrypted transaction is checked by the application, and decryption key transactions are verified with the
+// committee key. For other transactions, it returns tx and nil response.
+func CheckEncryptedTx(committeeKey bls.PubKey, tx types.Tx) (types.Tx, *abci.ResponseCheckTx) {
+	if types.IsEncryptedTx(tx) {
+		envelope, err := types.ParseEncryptedTx(tx)
+		if err != nil {
+			return nil, &abci.ResponseCheckTx{Code: CodeInvalidEncryptedTx, Codespace: EncryptedTxsCodespace, Log: err.Error()}
+		}
+		if isEncryptedOrKey(envelope.FeeTx) {
+			return nil, &abci.ResponseCheckTx{Code: CodeInvalidEncryptedTx, Codespace: EncryptedTxsCodespace, Log: "nested encrypted transaction"}
+		}
+		return envelope.FeeTx, nil
+	}
+	if types.IsDecryptionKeyTx(tx) {
+		key, err := types.ParseDecryptionKeyTx(tx)
+		if err == nil {
+			err = key.Verify(committeeKey)
+		}
+		if err != nil {
+			return nil, &abci.ResponseCheckTx{Code: CodeInvalidDecryptionKey, Codespace: EncryptedTxsCodespace, Log: err.Error()}
+		}
+		return nil, &abci.ResponseCheckTx{Code: abci.CodeTypeOK}
+	}
+	return tx, nil
+}
+
+// PrepareEncryptedTxs returns transactions of a block being created at given height, with the decryption key of
+// height-Delay moved to the first slot, and other decryption keys and envelopes that can't be included at the height
+// removed. It has to be called before the header of the block is built.
+func (e *BlockExecutor) PrepareEncryptedTxs(height uint64, txs types.Txs) types.Txs {
+	conf := e.encryptedTxs
+	if conf == nil {
+		return txs
+	}
+	prepared := make(types.Txs, 1, len(txs)+1)
+	for _, tx := range txs {
+		switch {
+		case types.IsDecryptionKeyTx(tx):
+			key, err := types.ParseDecryptionKeyTx(tx)
+			if prepared[0] == nil && err == nil && key.Height+conf.Delay == height && key.Verify(conf.Key) == nil {
+				prepared[0] = tx
+			}
+		case types.IsEncryptedTx(tx):
+			envelope, err := types.ParseEncryptedTx(tx)
+			if err == nil && conf.includable(envelope, height) {
+				prepared = append(prepared, tx)
+			}
+		default:
+			prepared = append(prepared, tx)
+		}
+	}
+	if prepared[0] == nil {
+		return prepared[1:]
+	}
+	return prepared
+}
+
+// includable checks if the envelope can be included in the block at given height.
+func (c *EncryptedTxs) includable(envelope *types.EncryptedTx, height uint64) bool {
+	return envelope.Height >= height && envelope.Height < height+c.Window && !isEncryptedOrKey(envelope.FeeTx)
+}
+
+func isEncryptedOrKey(tx types.Tx) bool {
+	return types.IsEncryptedTx(tx) || types.IsDecryptionKeyTx(tx)
+}
+
+// encryptedTxsResolver resolves encrypted and decryption key transactions of a single block.
+type encryptedTxsResolver struct {
+	conf          *EncryptedTxs
+	block         *types.Block
+	initialHeight uint64
+}
+
+func newEncryptedTxsResolver(conf *EncryptedTxs, block *types.Block, initialHeight uint64) *encryptedTxsResolver {
+	return &encryptedTxsResolver{conf: conf, block: block, initialHeight: initialHeight}
+}
+
+// resolve returns the transactions that should be delivered to the application in place of i-th transaction of the
+// block, or the response if nothing should be delivered. Responses to delivered transactions are combined into the
+// response of the slot with combineResponses.
+func (r *encryptedTxsResolver) resolve(i int) (types.Txs, *abci.ResponseDeliverTx, error) {
+	tx := r.block.Data.Txs[i]
+	height := r.block.Height()
+	if types.IsEncryptedTx(tx) {
+		envelope, err := types.ParseEncryptedTx(tx)
+		if err != nil {
+			return nil, encryptedTxResponse(CodeInvalidEncryptedTx, err.Error()), nil
+		}
+		if !r.conf.includable(envelope, height) {
+			return nil, encryptedTxResponse(CodeInvalidEncryptedTx, fmt.Sprintf("encrypted transaction targeting height %d can't be included at height %d", envelope.Height, height)), nil
+		}
+		// fee is charged regardless of the result of decryption
+		return types.Txs{envelope.FeeTx}, nil, nil
+	}
+	if !types.IsDecryptionKeyTx(tx) {
+		return types.Txs{tx}, nil, nil
+	}
+	key, err := types.ParseDecryptionKeyTx(tx)
+	if err != nil {
+		return nil, encryptedTxResponse(CodeInvalidDecryptionKey, err.Error()), nil
+	}
+	if i != 0 || key.Height+r.conf.Delay != height {
+		return nil, encryptedTxResponse(CodeMisplacedDecryptionKey, fmt.Sprintf("decryption key of height %d has to be the first transaction of block %d", key.Height, key.Height+r.conf.Delay)), nil
+	}
+	if err := key.Verify(r.conf.Key); err != nil {
+		return nil, encryptedTxResponse(CodeInvalidDecryptionKey, err.Error()), nil
+	}
+	return r.decrypt(key)
+}
+
+// decrypt returns transactions of envelopes targeting the height of the key, in order of inclusion.
+func (r *encryptedTxsResolver) decrypt(key *types.DecryptionKeyTx) (types.Txs, *abci.ResponseDeliverTx, error) {
+	var decrypted types.Txs
+	from := r.initialHeight
+	if key.Height >= from+r.conf.Window {
+		from = key.Height - r.conf.Window + 1
+	}
+	for height := from; height <= key.Height; height++ {
+		block, err := r.conf.LoadBlock(height)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load block %d with encrypted transactions: %w", height, err)
+		}
+		var responses *cmstate.ABCIResponses
+		for i, tx := range block.Data.Txs {
+			if !types.IsEncryptedTx(tx) {
+				continue
+			}
+			envelope, err := types.ParseEncryptedTx(tx)
+			if err != nil || envelope.Height != key.Height || !r.conf.includable(envelope, height) {
+				continue
+			}
+			if responses == nil {
+				if responses, err = r.conf.LoadBlockResponses(height); err != nil {
+					return nil, nil, fmt.Errorf("failed to load responses of block %d with encrypted transactions: %w", height, err)
+				}
+			}
+			if i >= len(responses.DeliverTxs) || responses.DeliverTxs[i].Code != abci.CodeTypeOK {
+				// fee was not paid
+				continue
+			}
+			tx, err := envelope.Decrypt(key.Key)
+			if err != nil || isEncryptedOrKey(tx) {
+				continue
+			}
+			decrypted = append(decrypted, tx)
+		}
+	}
+	if len(decrypted) == 0 {
+		return nil, &abci.ResponseDeliverTx{Code: abci.CodeTypeOK, Log: "no encrypted transactions"}, nil
+	}
+	return decrypted, nil, nil
+}
+
+// combineResponses combines responses to transactions delivered in place of a single transaction of the block.
+// Events and gas are summed up, and the log reports the number of failed transactions.
+func combineResponses(responses []*abci.ResponseDeliverTx) *abci.ResponseDeliverTx {
+	if len(responses) == 1 {
+		return responses[0]
+	}
+	combined := &abci.ResponseDeliverTx{Code: abci.CodeTypeOK}
+	failed := 0
+	for _, res := range responses {
+		if res.Code != abci.CodeTypeOK {
+			failed++
+		}
+		combined.GasWanted += res.GasWanted
+		combined.GasUsed += res.GasUsed
+		combined.Events = append(combined.Events, res.Events...)
+	}
+	combined.Log = fmt.Sprintf("executed %d decrypted transactions, %d failed", len(responses), failed)
+	return combined
+}
+
+func encryptedTxResponse(code uint32, log string) *abci.ResponseDeliverTx {
+	return &abci.ResponseDeliverTx{Code: code, Codespace: EncryptedTxsCodespace, Log: log}
+}
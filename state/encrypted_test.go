@@ -0,0 +1,147 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestEncryptedTxsResolver(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	committee, err := bls.GenPrivKey()
+	require.NoError(err)
+	encrypt := func(tx string, height uint64) types.Tx {
+		envelope, err := types.EncryptTx(types.Tx(tx), types.Tx("fee "+tx), committee.PubKey(), height)
+		require.NoError(err)
+		return envelope
+	}
+	keyTx := func(height uint64) types.Tx {
+		return (&types.DecryptionKeyTx{Height: height, Key: committee.Sign(types.DecryptionKeyID(height))}).Tx()
+	}
+
+	blocks := map[uint64]*types.Block{}
+	responses := map[uint64]*cmstate.ABCIResponses{}
+	conf := &EncryptedTxs{
+		Key:    committee.PubKey(),
+		Delay:  2,
+		Window: 3,
+		LoadBlock: func(height uint64) (*types.Block, error) {
+			if block, ok := blocks[height]; ok {
+				return block, nil
+			}
+			return nil, errors.New("not found")
+		},
+		LoadBlockResponses: func(height uint64) (*cmstate.ABCIResponses, error) {
+			if res, ok := responses[height]; ok {
+				return res, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+	// resolve executes the block, fee transactions starting with "fee unpaid" fail
+	resolve := func(height uint64, txs ...types.Tx) ([]types.Txs, []uint32) {
+		block := types.GetRandomBlock(height, 0)
+		block.Data.Txs = txs
+		blocks[height] = block
+		r := newEncryptedTxsResolver(conf, block, 1)
+		delivered := make([]types.Txs, len(txs))
+		res := &cmstate.ABCIResponses{DeliverTxs: make([]*abci.ResponseDeliverTx, len(txs))}
+		for i := range txs {
+			resolved, txRes, err := r.resolve(i)
+			require.NoError(err)
+			delivered[i] = resolved
+			if txRes == nil {
+				txRes = &abci.ResponseDeliverTx{Code: abci.CodeTypeOK}
+				if len(resolved) == 1 && string(resolved[0]) == "fee unpaid" {
+					txRes.Code = 1
+				}
+			}
+			res.DeliverTxs[i] = txRes
+		}
+		responses[height] = res
+		codes := make([]uint32, len(txs))
+		for i, txRes := range res.DeliverTxs {
+			codes[i] = txRes.Code
+		}
+		return delivered, codes
+	}
+
+	// only fee transactions of envelopes are executed
+	txs, codes := resolve(1, encrypt("a", 2), types.Tx("plain"), encrypt("x", 5), encrypt("early", 1))
+	assert.Equal([]types.Txs{{types.Tx("fee a")}, {types.Tx("plain")}, nil, {types.Tx("fee early")}}, txs)
+	assert.Equal([]uint32{abci.CodeTypeOK, abci.CodeTypeOK, CodeInvalidEncryptedTx, abci.CodeTypeOK}, codes)
+
+	_, codes = resolve(2, encrypt("b", 2), encrypt("unpaid", 2), types.Tx("rkenc/tx/short"))
+	assert.Equal([]uint32{abci.CodeTypeOK, 1, CodeInvalidEncryptedTx}, codes)
+
+	// decryption key is executed only in the first slot of block 2+Delay
+	_, codes = resolve(3, keyTx(2), keyTx(1))
+	assert.Equal([]uint32{CodeMisplacedDecryptionKey, CodeMisplacedDecryptionKey}, codes)
+
+	wrongKey := (&types.DecryptionKeyTx{Height: 2, Key: committee.Sign(types.DecryptionKeyID(3))}).Tx()
+	_, codes = resolve(4, wrongKey, keyTx(2))
+	assert.Equal([]uint32{CodeInvalidDecryptionKey, CodeMisplacedDecryptionKey}, codes)
+
+	// envelopes targeting height 2 with paid fees are executed in order of inclusion
+	txs, codes = resolve(4, keyTx(2), types.Tx("plain"), types.Tx("rkenc/key/short"))
+	assert.Equal([]types.Txs{{types.Tx("a"), types.Tx("b")}, {types.Tx("plain")}, nil}, txs)
+	assert.Equal([]uint32{abci.CodeTypeOK, abci.CodeTypeOK, CodeInvalidDecryptionKey}, codes)
+
+	// the envelope targeting height 1 was included in block 1
+	txs, _ = resolve(3, keyTx(1))
+	assert.Equal([]types.Txs{{types.Tx("early")}}, txs)
+
+	_, codes = resolve(5, keyTx(3))
+	assert.Equal([]uint32{abci.CodeTypeOK}, codes)
+}
+
+func TestPrepareEncryptedTxs(t *testing.T) {
+	committee, err := bls.GenPrivKey()
+	require.NoError(t, err)
+	e := &BlockExecutor{encryptedTxs: &EncryptedTxs{Key: committee.PubKey(), Delay: 2, Window: 3}}
+	envelope, err := types.EncryptTx(types.Tx("a"), types.Tx("fee"), committee.PubKey(), 12)
+	require.NoError(t, err)
+	expired, err := types.EncryptTx(types.Tx("b"), types.Tx("fee"), committee.PubKey(), 9)
+	require.NoError(t, err)
+	key := (&types.DecryptionKeyTx{Height: 8, Key: committee.Sign(types.DecryptionKeyID(8))}).Tx()
+	otherKey := (&types.DecryptionKeyTx{Height: 7, Key: committee.Sign(types.DecryptionKeyID(7))}).Tx()
+	plain := types.Tx("plain")
+
+	assert.Equal(t, types.Txs{key, plain, envelope}, e.PrepareEncryptedTxs(10, types.Txs{plain, otherKey, envelope, key, expired, key}))
+	assert.Equal(t, types.Txs{plain}, e.PrepareEncryptedTxs(11, types.Txs{plain, key}))
+}
+
+func TestCheckEncryptedTx(t *testing.T) {
+	committee, err := bls.GenPrivKey()
+	require.NoError(t, err)
+	envelope, err := types.EncryptTx(types.Tx("transfer"), types.Tx("fee"), committee.PubKey(), 1)
+	require.NoError(t, err)
+	key := (&types.DecryptionKeyTx{Height: 1, Key: committee.Sign(types.DecryptionKeyID(1))}).Tx()
+	wrongKey := (&types.DecryptionKeyTx{Height: 2, Key: committee.Sign(types.DecryptionKeyID(1))}).Tx()
+
+	tx, res := CheckEncryptedTx(committee.PubKey(), types.Tx("transfer"))
+	assert.Equal(t, types.Tx("transfer"), tx)
+	assert.Nil(t, res)
+	// fee transaction is checked by the application
+	tx, res = CheckEncryptedTx(committee.PubKey(), envelope)
+	assert.Equal(t, types.Tx("fee"), tx)
+	assert.Nil(t, res)
+	_, res = CheckEncryptedTx(committee.PubKey(), envelope[:30])
+	assert.Equal(t, CodeInvalidEncryptedTx, res.Code)
+
+	_, res = CheckEncryptedTx(committee.PubKey(), key)
+	assert.Equal(t, abci.CodeTypeOK, res.Code)
+	_, res = CheckEncryptedTx(committee.PubKey(), wrongKey)
+	assert.Equal(t, CodeInvalidDecryptionKey, res.Code)
+	_, res = CheckEncryptedTx(committee.PubKey(), key[:len(key)-1])
+	assert.Equal(t, CodeInvalidDecryptionKey, res.Code)
+}
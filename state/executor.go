@@ -5,6 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	abci "github.com/cometbft/cometbft/abci/types"
@@ -14,10 +17,13 @@ import (
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/cometbft/cometbft/proxy"
 	cmtypes "github.com/cometbft/cometbft/types"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 
+	"github.com/rollkit/rollkit/clock"
 	"github.com/rollkit/rollkit/mempool"
 	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/tracing"
 	"github.com/rollkit/rollkit/types"
 	abciconv "github.com/rollkit/rollkit/types/abci"
 )
@@ -28,6 +34,52 @@ var ErrEmptyValSetGenerated = errors.New("applying the validator changes would r
 // ErrAddingValidatorToBased is returned when trying to add a validator to an empty validator set.
 var ErrAddingValidatorToBased = errors.New("cannot add validators to empty validator set")
 
+// ErrNextAggregatorsHashMismatch is returned when NextAggregatorsHash of the block doesn't match
+// the aggregator set resulting from applying validator updates of the block.
+var ErrNextAggregatorsHashMismatch = errors.New("NextAggregatorsHash mismatch")
+
+// ErrBlockTooBig is returned when transactions of the block exceed maximum block size from consensus parameters.
+var ErrBlockTooBig = errors.New("block data exceeds max block bytes")
+
+// ErrBlockGasExceeded is returned when total gas wanted by transactions of the block exceeds max gas from consensus parameters.
+var ErrBlockGasExceeded = errors.New("block gas wanted exceeds max block gas")
+
+// ErrABCITimeout is returned when the application doesn't respond to an ABCI call within the configured timeout.
+var ErrABCITimeout = errors.New("ABCI call timed out")
+
+// maxAbandonedCalls limits the number of timed out calls to the application that are still running. ABCI connections
+// of CometBFT can't cancel a call in progress, so goroutines of abandoned calls stay blocked until the application
+// responds. When the limit is reached, new calls fail immediately instead of piling up behind a hung application.
+const maxAbandonedCalls = 16
+
+// States of a call to the application run by callApp.
+const (
+	callRunning int32 = iota
+	callFinished
+	callAbandoned
+)
+
+// txGasProvider is implemented by mempools recording gas wanted by transactions in CheckTx (e.g. TxMempool).
+type txGasProvider interface {
+	GasWanted(tx cmtypes.Tx) (int64, bool)
+}
+
+// ISRMismatchError is returned when intermediate state roots computed during block
+// execution differ from the ones committed in the block.
+type ISRMismatchError struct {
+	// Index is the position of the first mismatching ISR.
+	// ISR 0 is the state after BeginBlock and ISR i (1 <= i <= len(txs)) is the state after tx i-1.
+	Index     int
+	Committed []byte
+	Computed  []byte
+	// FraudProof proves the invalid transition, if it was possible to generate it.
+	FraudProof *types.StateFraudProof
+}
+
+func (e *ISRMismatchError) Error() string {
+	return fmt.Sprintf("intermediate state root mismatch at index %d: committed %X, computed %X", e.Index, e.Committed, e.Computed)
+}
+
 // BlockExecutor creates and applies blocks and maintains state.
 type BlockExecutor struct {
 	proposerAddress []byte
@@ -36,23 +88,65 @@ type BlockExecutor struct {
 	proxyApp        proxy.AppConnConsensus
 	mempool         mempool.Mempool
 
+	// dataHasher computes DataHash of blocks of the chain
+	dataHasher types.MerkleHasher
+
+	isrProvider IntermediateStateRootProvider
+	txValidator TxValidator
+
+	// encryptedTxs is optional, enables delayed execution of encrypted transactions
+	encryptedTxs *EncryptedTxs
+
 	eventBus *cmtypes.EventBus
 
+	// abciTimeout limits duration of every call to the application; zero disables the limit.
+	abciTimeout time.Duration
+	// abandonedCalls is the number of timed out calls to the application that didn't return yet.
+	abandonedCalls atomic.Int32
+
+	metrics *Metrics
+
+	// clock is the source of time of created blocks
+	clock clock.Clock
+
 	logger log.Logger
 }
 
 // NewBlockExecutor creates new instance of BlockExecutor.
-// Proposer address and namespace ID will be used in all newly created blocks.
-func NewBlockExecutor(proposerAddress []byte, namespaceID [8]byte, chainID string, mempool mempool.Mempool, proxyApp proxy.AppConnConsensus, eventBus *cmtypes.EventBus, logger log.Logger) *BlockExecutor {
+// Proposer address and namespace ID will be used in all newly created blocks, and DataHash of blocks is computed
+// with dataHasher (see types.DataHasherFromGenesis).
+// If isrProvider is nil, intermediate state roots are neither computed nor verified.
+// If txValidator is nil, transactions are not pre-validated before execution.
+// If abciTimeout is positive, calls to the application that don't finish in time fail with ErrABCITimeout.
+// If metrics is nil, no metrics are collected.
+// It returns an error if dataHasher has no hash function.
+func NewBlockExecutor(proposerAddress []byte, namespaceID [8]byte, chainID string, dataHasher types.MerkleHasher, mempool mempool.Mempool, proxyApp proxy.AppConnConsensus, isrProvider IntermediateStateRootProvider, txValidator TxValidator, eventBus *cmtypes.EventBus, abciTimeout time.Duration, metrics *Metrics, logger log.Logger) (*BlockExecutor, error) {
+	if err := dataHasher.Validate(); err != nil {
+		return nil, err
+	}
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
 	return &BlockExecutor{
 		proposerAddress: proposerAddress,
 		namespaceID:     namespaceID,
 		chainID:         chainID,
+		dataHasher:      dataHasher,
 		proxyApp:        proxyApp,
 		mempool:         mempool,
+		isrProvider:     isrProvider,
+		txValidator:     txValidator,
 		eventBus:        eventBus,
+		abciTimeout:     abciTimeout,
+		metrics:         metrics,
 		logger:          logger,
-	}
+		clock:           clock.Real,
+	}, nil
+}
+
+// SetClock sets the source of time of created blocks.
+func (e *BlockExecutor) SetClock(c clock.Clock) {
+	e.clock = c
 }
 
 // InitChain calls InitChainSync using consensus connection to app.
@@ -64,7 +158,7 @@ func (e *BlockExecutor) InitChain(genesis *cmtypes.GenesisDoc) (*abci.ResponseIn
 		validators[i] = cmtypes.NewValidator(v.PubKey, v.Power)
 	}
 
-	return e.proxyApp.InitChainSync(abci.RequestInitChain{
+	req := abci.RequestInitChain{
 		Time:    genesis.GenesisTime,
 		ChainId: genesis.ChainID,
 		ConsensusParams: &cmproto.ConsensusParams{
@@ -87,7 +181,14 @@ func (e *BlockExecutor) InitChain(genesis *cmtypes.GenesisDoc) (*abci.ResponseIn
 		Validators:    cmtypes.TM2PB.ValidatorUpdates(cmtypes.NewValidatorSet(validators)),
 		AppStateBytes: genesis.AppState,
 		InitialHeight: genesis.InitialHeight,
+	}
+
+	var res *abci.ResponseInitChain
+	err := e.callApp(context.Background(), "InitChain", func() (err error) {
+		res, err = e.proxyApp.InitChainSync(req)
+		return
 	})
+	return res, err
 }
 
 // CreateBlock reaps transactions from mempool and builds a block.
@@ -97,6 +198,40 @@ func (e *BlockExecutor) CreateBlock(height uint64, lastCommit *types.Commit, las
 
 	mempoolTxs := e.mempool.ReapMaxBytesMaxGas(maxBytes, maxGas)
 
+	return e.CreateBlockWithTxs(height, lastCommit, lastHeaderHash, state, toRollkitTxs(mempoolTxs))
+}
+
+// LimitBlockGas returns transactions of a block being created that fit into max gas of the block, in the same order.
+// Like in reaping of the mempool, gas wanted by transactions is taken from their CheckTx responses, and transactions
+// that would exceed the limit are skipped. Transactions unknown to the mempool (e.g. ordered by a shared sequencer)
+// are included without being accounted. Gas wanted in DeliverTx responses is verified on execution of blocks
+// received from the proposer, but not of the produced block.
+func (e *BlockExecutor) LimitBlockGas(state types.State, txs types.Txs) types.Txs {
+	params := state.ConsensusParams.Block
+	gasProvider, ok := e.mempool.(txGasProvider)
+	if params == nil || params.MaxGas < 0 || !ok {
+		return txs
+	}
+	var totalGas int64
+	limited := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		gas, known := gasProvider.GasWanted(cmtypes.Tx(tx))
+		if known && totalGas+gas > params.MaxGas {
+			e.logger.Debug("skipping tx exceeding max block gas", "gas", gas, "total", totalGas, "max", params.MaxGas)
+			continue
+		}
+		totalGas += gas
+		limited = append(limited, tx)
+	}
+	return limited
+}
+
+// CreateBlockWithTxs creates a block containing given transactions, e.g. a batch ordered by a shared sequencer.
+func (e *BlockExecutor) CreateBlockWithTxs(height uint64, lastCommit *types.Commit, lastHeaderHash types.Hash, state types.State, txs types.Txs) *types.Block {
+	defer func(start time.Time) {
+		e.metrics.BlockCreationTime.Observe(time.Since(start).Seconds())
+	}(time.Now())
+
 	block := &types.Block{
 		SignedHeader: types.SignedHeader{
 			Header: types.Header{
@@ -107,23 +242,22 @@ func (e *BlockExecutor) CreateBlock(height uint64, lastCommit *types.Commit, las
 				BaseHeader: types.BaseHeader{
 					ChainID: e.chainID,
 					Height:  height,
-					Time:    uint64(time.Now().UnixNano()),
+					Time:    uint64(e.clock.Now().UnixNano()),
 				},
 				//LastHeaderHash: lastHeaderHash,
 				//LastCommitHash:  lastCommitHash,
 				DataHash:        make(types.Hash, 32),
-				ConsensusHash:   make(types.Hash, 32),
+				ConsensusHash:   types.ConsensusParamsHash(state.ConsensusParams),
 				AppHash:         state.AppHash,
 				LastResultsHash: state.LastResultsHash,
 				ProposerAddress: e.proposerAddress,
+				EncodingVersion: types.EncodingVersion,
 			},
 			Commit: *lastCommit,
 		},
 		Data: types.Data{
-			Txs:                    toRollkitTxs(mempoolTxs),
+			Txs:                    txs,
 			IntermediateStateRoots: types.IntermediateStateRoots{RawRootsList: nil},
-			// Note: Temporarily remove Evidence #896
-			// Evidence:               types.EvidenceData{Evidence: nil},
 		},
 	}
 	block.SignedHeader.LastCommitHash = lastCommit.GetCommitHash(&block.SignedHeader.Header, e.proposerAddress)
@@ -134,17 +268,97 @@ func (e *BlockExecutor) CreateBlock(height uint64, lastCommit *types.Commit, las
 }
 
 // ApplyBlock validates and executes the block.
+//
+// If transaction pre-validation is enabled, block containing invalid transaction is rejected.
+// If intermediate state roots are enabled, roots committed in the block are verified against
+// the roots computed during execution. Blocks without ISRs are rejected in this case.
+// NextAggregatorsHash of the block has to match the aggregator set after applying validator updates.
 func (e *BlockExecutor) ApplyBlock(ctx context.Context, state types.State, block *types.Block) (types.State, *cmstate.ABCIResponses, error) {
-	err := e.Validate(state, block)
+	return e.applyBlock(ctx, state, block, false)
+}
+
+// ApplyProposedBlock validates and executes block created by this node.
+//
+// If intermediate state roots are enabled and block doesn't contain them yet, ISRs computed
+// during execution are added to block data, and caller is responsible for updating DataHash.
+// Transactions are not pre-validated, as they are filtered with PreValidateTxs when the block is created.
+// NextAggregatorsHash of the block is set according to validator updates returned by the application.
+// The block is validated against the state, but its commit isn't verified, as the block is signed only after
+// it's applied; caller has to Validate the signed block before it's stored.
+func (e *BlockExecutor) ApplyProposedBlock(ctx context.Context, state types.State, block *types.Block) (types.State, *cmstate.ABCIResponses, error) {
+	return e.applyBlock(ctx, state, block, true)
+}
+
+func (e *BlockExecutor) applyBlock(ctx context.Context, state types.State, block *types.Block, proposed bool) (types.State, *cmstate.ABCIResponses, error) {
+	ctx, span := tracing.Start(ctx, "BlockExecutor.ApplyBlock",
+		attribute.Int64("height", int64(block.Height())),
+		attribute.Int("txs", len(block.Data.Txs)),
+		attribute.Bool("proposed", proposed))
+	start := time.Now()
+	newState, resp, err := e.processBlock(ctx, state, block, proposed)
+	tracing.End(span, err)
+	if err != nil {
+		e.metrics.RejectedBlocks.Add(1)
+		return newState, resp, err
+	}
+
+	var gasUsed int64
+	for _, tx := range resp.DeliverTxs {
+		gasUsed += tx.GasUsed
+	}
+	e.metrics.BlockProcessingTime.Observe(time.Since(start).Seconds())
+	e.metrics.BlockTxs.Observe(float64(len(block.Data.Txs)))
+	e.metrics.BlockGasUsed.Observe(float64(gasUsed))
+
+	return newState, resp, nil
+}
+
+func (e *BlockExecutor) processBlock(ctx context.Context, state types.State, block *types.Block, proposed bool) (types.State, *cmstate.ABCIResponses, error) {
+	validate := e.Validate
+	if proposed {
+		validate = validateWithState
+	}
+	err := validate(state, block)
 	if err != nil {
 		return types.State{}, nil, err
 	}
+
+	if e.txValidator != nil && !proposed {
+		for i, err := range e.validateTxs(ctx, block.Data.Txs) {
+			if err != nil {
+				return types.State{}, nil, fmt.Errorf("failed to pre-validate tx %d: %w", i, err)
+			}
+		}
+	}
+
+	// committed ISRs are verified during execution, so the first invalid transition can be proven
+	var committedISRs [][]byte
+	if e.isrProvider != nil && !(proposed && len(block.Data.IntermediateStateRoots.RawRootsList) == 0) {
+		committedISRs = block.Data.IntermediateStateRoots.RawRootsList
+		if err := validateISRsLength(committedISRs, block.Data.Txs); err != nil {
+			return types.State{}, nil, err
+		}
+	}
+
 	// This makes calls to the AppClient
-	resp, err := e.execute(ctx, state, block)
+	resp, isrs, err := e.execute(ctx, state, block, committedISRs)
 	if err != nil {
 		return types.State{}, nil, err
 	}
 
+	if e.isrProvider != nil && committedISRs == nil {
+		block.Data.IntermediateStateRoots.RawRootsList = isrs
+	}
+
+	err = validateBlockGas(resp.DeliverTxs, state.ConsensusParams.Block)
+	if err != nil && proposed {
+		// gas of produced block is limited when it's created (see LimitBlockGas), but transactions unknown to the
+		// mempool can't be accounted; the block can't be rejected, as it would be produced again and stall the chain
+		e.logger.Error("produced block exceeds max block gas", "height", block.Height(), "error", err)
+	} else if err != nil {
+		return types.State{}, nil, err
+	}
+
 	abciValUpdates := resp.EndBlock.ValidatorUpdates
 
 	err = validateValidatorUpdates(abciValUpdates, state.ConsensusParams.Validator)
@@ -168,12 +382,21 @@ func (e *BlockExecutor) ApplyBlock(ctx context.Context, state types.State, block
 		return types.State{}, nil, err
 	}
 
+	nextAggregatorsHash := state.NextValidators.Hash()
+	if proposed {
+		block.SignedHeader.NextAggregatorsHash = nextAggregatorsHash
+	} else if !bytes.Equal(block.SignedHeader.NextAggregatorsHash[:], nextAggregatorsHash) {
+		return types.State{}, nil, fmt.Errorf("%w: block %X, computed %X", ErrNextAggregatorsHashMismatch, block.SignedHeader.NextAggregatorsHash, nextAggregatorsHash)
+	}
+
 	return state, resp, nil
 }
 
 // Commit commits the block
 func (e *BlockExecutor) Commit(ctx context.Context, state types.State, block *types.Block, resp *cmstate.ABCIResponses) ([]byte, uint64, error) {
+	ctx, span := tracing.Start(ctx, "BlockExecutor.Commit", attribute.Int64("height", int64(block.Height())))
 	appHash, retainHeight, err := e.commit(ctx, state, block, resp.DeliverTxs)
+	tracing.End(span, err)
 	if err != nil {
 		return []byte{}, 0, err
 	}
@@ -188,6 +411,28 @@ func (e *BlockExecutor) Commit(ctx context.Context, state types.State, block *ty
 	return appHash, retainHeight, nil
 }
 
+// ReplayBlock executes and commits a block already committed to the chain, to bring up to date the application
+// that lost its latest blocks (e.g. after a crash). Block is not validated, mempool is not updated and events are
+// not published. It returns the app hash after the block.
+func (e *BlockExecutor) ReplayBlock(ctx context.Context, block *types.Block) ([]byte, error) {
+	validators := block.SignedHeader.Validators
+	if validators == nil {
+		validators = cmtypes.NewValidatorSet(nil)
+	}
+	if _, _, err := e.execute(ctx, types.State{Validators: validators}, block, nil); err != nil {
+		return nil, err
+	}
+	var resp *abci.ResponseCommit
+	err := e.callApp(ctx, "Commit", func() (err error) {
+		resp, err = e.proxyApp.CommitSync()
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
 func (e *BlockExecutor) updateState(state types.State, block *types.Block, abciResponses *cmstate.ABCIResponses, validatorUpdates []*cmtypes.Validator) (types.State, error) {
 	nValSet := state.NextValidators.Copy()
 	lastHeightValSetChanged := state.LastHeightValidatorsChanged
@@ -216,8 +461,23 @@ func (e *BlockExecutor) updateState(state types.State, block *types.Block, abciR
 		return state, ErrAddingValidatorToBased
 	}
 
+	nextParams := state.ConsensusParams
+	lastHeightParamsChanged := state.LastHeightConsensusParamsChanged
+	nextVersion := state.Version
+	if paramUpdates := abciResponses.EndBlock.ConsensusParamUpdates; paramUpdates != nil {
+		params := cmtypes.ConsensusParamsFromProto(state.ConsensusParams).Update(paramUpdates)
+		if err := params.ValidateBasic(); err != nil {
+			return state, fmt.Errorf("error updating consensus params: %w", err)
+		}
+		e.logger.Debug("updates to consensus params", "updates", paramUpdates)
+		nextParams = params.ToProto()
+		nextVersion.Consensus.App = params.Version.App
+		// Change results from this height but only applies to the next height.
+		lastHeightParamsChanged = block.Height() + 1
+	}
+
 	s := types.State{
-		Version:         state.Version,
+		Version:         nextVersion,
 		ChainID:         state.ChainID,
 		InitialHeight:   state.InitialHeight,
 		LastBlockHeight: block.Height(),
@@ -230,11 +490,12 @@ func (e *BlockExecutor) updateState(state types.State, block *types.Block, abciR
 		Validators:                       nValSet,
 		LastValidators:                   state.Validators.Copy(),
 		LastHeightValidatorsChanged:      lastHeightValSetChanged,
-		ConsensusParams:                  state.ConsensusParams,
-		LastHeightConsensusParamsChanged: state.LastHeightConsensusParamsChanged,
+		ConsensusParams:                  nextParams,
+		LastHeightConsensusParamsChanged: lastHeightParamsChanged,
 		AppHash:                          make(types.Hash, 32),
 	}
-	copy(s.LastResultsHash[:], cmtypes.NewResults(abciResponses.DeliverTxs).Hash())
+	// LastResultsHash is the Merkle root of deterministic parts of DeliverTx responses, as in CometBFT.
+	s.LastResultsHash = cmtypes.NewResults(abciResponses.DeliverTxs).Hash()
 
 	return s, nil
 }
@@ -243,12 +504,17 @@ func (e *BlockExecutor) commit(ctx context.Context, state types.State, block *ty
 	e.mempool.Lock()
 	defer e.mempool.Unlock()
 
+	// FlushAppConn releases and reacquires the mempool lock, so it's never run by callApp, which may abandon it
 	err := e.mempool.FlushAppConn()
 	if err != nil {
 		return nil, 0, err
 	}
 
-	resp, err := e.proxyApp.CommitSync()
+	var resp *abci.ResponseCommit
+	err = e.callApp(ctx, "Commit", func() (err error) {
+		resp, err = e.proxyApp.CommitSync()
+		return
+	})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -265,10 +531,15 @@ func (e *BlockExecutor) commit(ctx context.Context, state types.State, block *ty
 
 // Validate validates the state and the block for the executor
 func (e *BlockExecutor) Validate(state types.State, block *types.Block) error {
-	err := block.ValidateBasic()
+	err := block.ValidateBasic(e.dataHasher)
 	if err != nil {
 		return err
 	}
+	return validateWithState(state, block)
+}
+
+// validateWithState checks that the block is consistent with the state it's applied to.
+func validateWithState(state types.State, block *types.Block) error {
 	if block.SignedHeader.Version.App != state.Version.Consensus.App ||
 		block.SignedHeader.Version.Block != state.Version.Consensus.Block {
 		return errors.New("block version mismatch")
@@ -291,35 +562,69 @@ func (e *BlockExecutor) Validate(state types.State, block *types.Block) error {
 		return errors.New("AggregatorsHash mismatch")
 	}
 
+	if !bytes.Equal(block.SignedHeader.ConsensusHash[:], types.ConsensusParamsHash(state.ConsensusParams)) {
+		return errors.New("ConsensusHash mismatch")
+	}
+
+	if state.ConsensusParams.Block != nil && state.ConsensusParams.Block.MaxBytes > 0 {
+		size := cmtypes.ComputeProtoSizeForTxs(fromRollkitTxs(block.Data.Txs))
+		if size > state.ConsensusParams.Block.MaxBytes {
+			return fmt.Errorf("%w: %d > %d", ErrBlockTooBig, size, state.ConsensusParams.Block.MaxBytes)
+		}
+	}
+
 	return nil
 }
 
-func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *types.Block) (*cmstate.ABCIResponses, error) {
+// validateBlockGas checks that total gas wanted by transactions doesn't exceed max gas of the block.
+// Negative max gas means that block gas is not limited.
+func validateBlockGas(deliverTxs []*abci.ResponseDeliverTx, params *cmproto.BlockParams) error {
+	if params == nil || params.MaxGas < 0 {
+		return nil
+	}
+	var totalGas int64
+	for _, tx := range deliverTxs {
+		totalGas += tx.GasWanted
+		if totalGas > params.MaxGas {
+			return fmt.Errorf("%w: %d > %d", ErrBlockGasExceeded, totalGas, params.MaxGas)
+		}
+	}
+	return nil
+}
+
+// byzantineValidators converts evidence committed in the block into misbehavior of aggregators reported to
+// the application, so it can slash or eject them.
+func byzantineValidators(evidence types.EvidenceData) []abci.Misbehavior {
+	var misbehavior []abci.Misbehavior
+	for i := range evidence.Evidence {
+		ev := &evidence.Evidence[i]
+		for _, val := range ev.Aggregators() {
+			misbehavior = append(misbehavior, abci.Misbehavior{
+				Type:             abci.MisbehaviorType_DUPLICATE_VOTE,
+				Validator:        abci.Validator{Address: val.Address, Power: val.VotingPower},
+				Height:           int64(ev.Height()),
+				Time:             ev.HeaderA.Time(),
+				TotalVotingPower: ev.HeaderA.Validators.TotalVotingPower(),
+			})
+		}
+	}
+	return misbehavior
+}
+
+func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *types.Block, committedISRs [][]byte) (*cmstate.ABCIResponses, [][]byte, error) {
 	abciResponses := new(cmstate.ABCIResponses)
 	abciResponses.DeliverTxs = make([]*abci.ResponseDeliverTx, len(block.Data.Txs))
 
-	txIdx := 0
 	validTxs := 0
 	invalidTxs := 0
 
-	e.proxyApp.SetResponseCallback(func(req *abci.Request, res *abci.Response) {
-		if r, ok := res.Value.(*abci.Response_DeliverTx); ok {
-			txRes := r.DeliverTx
-			if txRes.Code == abci.CodeTypeOK {
-				validTxs++
-			} else {
-				e.logger.Debug("Invalid tx", "code", txRes.Code, "log", txRes.Log)
-				invalidTxs++
-			}
-			abciResponses.DeliverTxs[txIdx] = txRes
-			txIdx++
-		}
-	})
+	// DeliverTx responses are collected per request (see deliverTx), but ABCI clients require a global callback.
+	e.proxyApp.SetResponseCallback(func(req *abci.Request, res *abci.Response) {})
 
 	hash := block.Hash()
 	abciHeader, err := abciconv.ToABCIHeaderPB(&block.SignedHeader.Header)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	abciHeader.ChainID = e.chainID
 	abciHeader.ValidatorsHash = state.Validators.Hash()
@@ -330,27 +635,244 @@ func (e *BlockExecutor) execute(ctx context.Context, state types.State, block *t
 			Round: 0,
 			Votes: nil,
 		},
-		ByzantineValidators: nil,
+		ByzantineValidators: byzantineValidators(block.Data.Evidence),
 	}
-	abciResponses.BeginBlock, err = e.proxyApp.BeginBlockSync(beginBlockRequest)
+	err = e.callApp(ctx, "BeginBlock", func() (err error) {
+		abciResponses.BeginBlock, err = e.proxyApp.BeginBlockSync(beginBlockRequest)
+		return
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	for _, tx := range block.Data.Txs {
-		res := e.proxyApp.DeliverTxAsync(abci.RequestDeliverTx{Tx: tx})
-		if res.GetException() != nil {
-			return nil, errors.New(res.GetException().GetError())
+	var isrs [][]byte
+	if e.isrProvider != nil {
+		isrs = make([][]byte, 0, len(block.Data.Txs)+1)
+		isrs, err = e.appendISR(ctx, isrs)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := e.checkISR(block, committedISRs, isrs); err != nil {
+			return nil, nil, err
 		}
+	}
 
+	var resolver *encryptedTxsResolver
+	if e.encryptedTxs != nil {
+		resolver = newEncryptedTxsResolver(e.encryptedTxs, block, state.InitialHeight)
 	}
+
+	for i, tx := range block.Data.Txs {
+		txs := types.Txs{tx}
+		var txRes *abci.ResponseDeliverTx
+		if resolver != nil {
+			txs, txRes, err = resolver.resolve(i)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if txRes == nil {
+			responses := make([]*abci.ResponseDeliverTx, len(txs))
+			for j, tx := range txs {
+				responses[j], err = e.deliverTx(ctx, tx)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			txRes = combineResponses(responses)
+		}
+		if txRes.Code == abci.CodeTypeOK {
+			validTxs++
+		} else {
+			e.logger.Debug("Invalid tx", "code", txRes.Code, "log", txRes.Log)
+			invalidTxs++
+		}
+		abciResponses.DeliverTxs[i] = txRes
+
+		if e.isrProvider != nil {
+			isrs, err = e.appendISR(ctx, isrs)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := e.checkISR(block, committedISRs, isrs); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	e.logger.Debug("executed block txs", "height", block.Height(), "valid", validTxs, "invalid", invalidTxs)
+
 	endBlockRequest := abci.RequestEndBlock{Height: int64(block.Height())}
-	abciResponses.EndBlock, err = e.proxyApp.EndBlockSync(endBlockRequest)
+	err = e.callApp(ctx, "EndBlock", func() (err error) {
+		abciResponses.EndBlock, err = e.proxyApp.EndBlockSync(endBlockRequest)
+		return
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	return abciResponses, isrs, nil
+}
+
+// PreValidateTxs returns transactions of a block being created that pass pre-validation, in the same order.
+// Invalid transactions are removed from the mempool. It has to be called before the header of the block is built,
+// as ApplyProposedBlock doesn't pre-validate transactions. If pre-validation is disabled, txs are returned unchanged.
+func (e *BlockExecutor) PreValidateTxs(ctx context.Context, txs types.Txs) (types.Txs, error) {
+	if e.txValidator == nil {
+		return txs, nil
+	}
+	valid := make(types.Txs, 0, len(txs))
+	for i, err := range e.validateTxs(ctx, txs) {
+		switch {
+		case err == nil:
+			valid = append(valid, txs[i])
+		case errors.Is(err, ErrInvalidTx):
+			e.logger.Debug("removing invalid tx from created block", "error", err)
+			if err := e.mempool.RemoveTxByKey(cmtypes.Tx(txs[i]).Key()); err != nil {
+				e.logger.Error("failed to remove invalid tx from mempool", "error", err)
+			}
+		default:
+			return nil, fmt.Errorf("failed to pre-validate tx %d: %w", i, err)
+		}
+	}
+	return valid, nil
+}
+
+// validateTxs validates transactions in parallel, before they are delivered sequentially. It returns
+// the result of validation of every transaction.
+func (e *BlockExecutor) validateTxs(ctx context.Context, txs types.Txs) []error {
+	results := make([]error, len(txs))
+	if len(txs) == 0 {
+		return results
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				tx := txs[i]
+				if e.encryptedTxs != nil && isEncryptedOrKey(tx) {
+					// contents of encrypted transactions are not known before execution
+					continue
+				}
+				results[i] = e.callApp(ctx, "ValidateTx", func() error {
+					return e.txValidator.ValidateTx(tx)
+				})
+			}
+		}()
+	}
+	for i := range txs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return results
+}
+
+// deliverTx executes a single transaction and waits for the response. Transactions are never
+// pipelined, so application state observed after this call reflects exactly the delivered txs.
+func (e *BlockExecutor) deliverTx(ctx context.Context, tx types.Tx) (*abci.ResponseDeliverTx, error) {
+	if e.abciTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.abciTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	defer func() {
+		e.metrics.ABCICallDuration.With("method", "DeliverTx").Observe(time.Since(start).Seconds())
+	}()
+
+	resCh := make(chan *abci.Response, 1)
+	reqRes := e.proxyApp.DeliverTxAsync(abci.RequestDeliverTx{Tx: tx})
+	reqRes.SetCallback(func(res *abci.Response) {
+		resCh <- res
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, e.callError("DeliverTx", ctx.Err())
+	case res := <-resCh:
+		if res.GetException() != nil {
+			return nil, errors.New(res.GetException().GetError())
+		}
+		txRes := res.GetDeliverTx()
+		if txRes == nil {
+			return nil, fmt.Errorf("unexpected response type for DeliverTx: %T", res.Value)
+		}
+		return txRes, nil
 	}
+}
 
-	return abciResponses, nil
+func (e *BlockExecutor) appendISR(ctx context.Context, isrs [][]byte) ([][]byte, error) {
+	var isr []byte
+	err := e.callApp(ctx, "GetIntermediateStateRoot", func() (err error) {
+		isr, err = e.isrProvider.GetIntermediateStateRoot()
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intermediate state root: %w", err)
+	}
+	return append(isrs, isr), nil
+}
+
+// callApp runs a blocking call to the application and waits until it returns, the context is
+// canceled or ABCI timeout expires. Calls that don't finish in time are abandoned, so a hung
+// application can't block the caller forever. An abandoned call keeps running in its goroutine
+// until the application responds (ABCI connections don't support cancellation); at most
+// maxAbandonedCalls such goroutines exist, further calls fail with ErrABCITimeout. Calls touching locks held by
+// the caller must not be run by callApp, as an abandoned call would keep using them. Durations of calls are
+// reported in metrics labeled by method.
+func (e *BlockExecutor) callApp(ctx context.Context, method string, call func() error) error {
+	if n := e.abandonedCalls.Load(); n >= maxAbandonedCalls {
+		return fmt.Errorf("%w: %s not called, %d timed out calls to the application are still running", ErrABCITimeout, method, n)
+	}
+	if e.abciTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.abciTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	defer func() {
+		e.metrics.ABCICallDuration.With("method", method).Observe(time.Since(start).Seconds())
+	}()
+
+	var status atomic.Int32
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- call()
+		if !status.CompareAndSwap(callRunning, callFinished) {
+			e.abandonedCalls.Add(-1)
+			e.logger.Info("abandoned call to the application returned", "method", method, "duration", time.Since(start))
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		if !status.CompareAndSwap(callRunning, callAbandoned) {
+			// the call returned in the meantime
+			return <-errCh
+		}
+		e.abandonedCalls.Add(1)
+		return e.callError(method, ctx.Err())
+	}
+}
+
+func (e *BlockExecutor) callError(method string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		e.logger.Error("application didn't respond in time", "method", method, "timeout", e.abciTimeout)
+		return fmt.Errorf("%w: %s didn't finish in %s", ErrABCITimeout, method, e.abciTimeout)
+	}
+	return fmt.Errorf("ABCI %s interrupted: %w", method, err)
 }
 
 func (e *BlockExecutor) publishEvents(resp *cmstate.ABCIResponses, block *types.Block, state types.State) error {
@@ -410,6 +932,51 @@ func fromRollkitTxs(rollkitTxs types.Txs) cmtypes.Txs {
 	return txs
 }
 
+func validateISRsLength(committed [][]byte, txs types.Txs) error {
+	if len(committed) != len(txs)+1 {
+		return fmt.Errorf("invalid number of intermediate state roots: expected %d, got %d", len(txs)+1, len(committed))
+	}
+	return nil
+}
+
+// checkISR compares the most recently computed ISR with the one committed in the block.
+// If they differ, ISRMismatchError is returned. If the disputed transition is a transaction and
+// ISR provider is able to generate state witnesses, the error contains a state fraud proof.
+func (e *BlockExecutor) checkISR(block *types.Block, committed, computed [][]byte) error {
+	if committed == nil {
+		return nil
+	}
+	i := len(computed) - 1
+	if bytes.Equal(committed[i], computed[i]) {
+		return nil
+	}
+	mismatch := &ISRMismatchError{Index: i, Committed: committed[i], Computed: computed[i]}
+	witnessProvider, ok := e.isrProvider.(StateWitnessProvider)
+	if i == 0 || !ok {
+		return mismatch
+	}
+	witnesses, err := witnessProvider.GetStateWitnesses()
+	if err != nil {
+		e.logger.Error("failed to generate state fraud proof", "height", block.Height(), "index", i, "error", err)
+		return mismatch
+	}
+	proof := &types.StateFraudProof{
+		BlockHeight:            block.Height(),
+		TxIndex:                uint64(i - 1),
+		PreStateRoot:           committed[i-1],
+		Tx:                     block.Data.Txs[i-1],
+		CommittedPostStateRoot: committed[i],
+		ExpectedPostStateRoot:  computed[i],
+		Witnesses:              witnesses,
+	}
+	if err := proof.AddInclusionProofs(&block.Data, e.dataHasher); err != nil {
+		e.logger.Error("failed to generate state fraud proof", "height", block.Height(), "index", i, "error", err)
+		return mismatch
+	}
+	mismatch.FraudProof = proof
+	return mismatch
+}
+
 func validateValidatorUpdates(abciUpdates []abci.ValidatorUpdate, params *cmproto.ValidatorParams) error {
 	for _, valUpdate := range abciUpdates {
 		if valUpdate.GetPower() < 0 {
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	abci "github.com/cometbft/cometbft/abci/types"
 	cfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/crypto/merkle"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/libs/pubsub/query"
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
@@ -24,6 +26,7 @@ import (
 	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
 	"github.com/rollkit/rollkit/test/mocks"
 	"github.com/rollkit/rollkit/types"
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
 )
 
 const (
@@ -53,7 +56,10 @@ func doTestCreateBlock(t *testing.T) {
 	fmt.Println("Made NID")
 	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(client, proxy.NopMetrics()), 0)
 	fmt.Println("Made a NewTxMempool")
-	executor := NewBlockExecutor([]byte("test address"), nsID, "test", mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, logger)
+	_, err = NewBlockExecutor([]byte("test address"), nsID, "test", types.MerkleHasher{}, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, nil, nil, 0, nil, logger)
+	require.ErrorIs(err, types.ErrNoHashFunction)
+	executor, err := NewBlockExecutor([]byte("test address"), nsID, "test", types.DefaultMerkleHasher, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, nil, nil, 0, nil, logger)
+	require.NoError(err)
 	fmt.Println("Made a New Block Executor")
 
 	state := types.State{}
@@ -129,7 +135,8 @@ func doTestApplyBlock(t *testing.T) {
 	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(client, proxy.NopMetrics()), 0)
 	eventBus := cmtypes.NewEventBus()
 	require.NoError(eventBus.Start())
-	executor := NewBlockExecutor([]byte("test address"), nsID, chainID, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), eventBus, logger)
+	executor, err := NewBlockExecutor([]byte("test address"), nsID, chainID, types.DefaultMerkleHasher, mpool, proxy.NewAppConnConsensus(client, proxy.NopMetrics()), nil, nil, eventBus, 0, nil, logger)
+	require.NoError(err)
 
 	txQuery, err := query.New("tm.event='Tx'")
 	require.NoError(err)
@@ -172,6 +179,7 @@ func doTestApplyBlock(t *testing.T) {
 	dataHash, err := block.Data.Hash()
 	assert.NoError(err)
 	block.SignedHeader.DataHash = dataHash
+	block.SignedHeader.NextAggregatorsHash = state.NextValidators.Hash()
 
 	// Update the signature on the block to current from last
 	headerBytes, _ := block.SignedHeader.Header.MarshalBinary()
@@ -186,6 +194,8 @@ func doTestApplyBlock(t *testing.T) {
 	require.NotNil(newState)
 	require.NotNil(resp)
 	assert.Equal(uint64(1), newState.LastBlockHeight)
+	assert.Equal(types.Hash(cmtypes.NewResults(resp.DeliverTxs).Hash()), newState.LastResultsHash)
+	assert.NotEqual(state.LastResultsHash, newState.LastResultsHash)
 	appHash, _, err := executor.Commit(context.Background(), newState, block, resp)
 	require.NoError(err)
 	assert.Equal(mockAppHash, appHash)
@@ -198,9 +208,11 @@ func doTestApplyBlock(t *testing.T) {
 	require.NotNil(block)
 	assert.Equal(uint64(2), block.Height())
 	assert.Len(block.Data.Txs, 3)
+	assert.Equal(newState.LastResultsHash, block.SignedHeader.LastResultsHash)
 	dataHash, err = block.Data.Hash()
 	assert.NoError(err)
 	block.SignedHeader.DataHash = dataHash
+	block.SignedHeader.NextAggregatorsHash = newState.NextValidators.Hash()
 
 	headerBytes, _ = block.SignedHeader.Header.MarshalBinary()
 	sig, _ = vKey.Sign(headerBytes)
@@ -252,3 +264,434 @@ func doTestApplyBlock(t *testing.T) {
 func TestApplyBlockWithFraudProofsDisabled(t *testing.T) {
 	doTestApplyBlock(t)
 }
+
+func TestApplyBlockWithValidatorUpdates(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	newKey := ed25519.GenPrivKey()
+	proxyApp, mpool := newMockApp(t, logger, func(app *mocks.Application) {
+		app.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{
+			ValidatorUpdates: []abci.ValidatorUpdate{abci.UpdateValidator(newKey.PubKey().Bytes(), 100, ed25519.KeyType)},
+		})
+	})
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, "test", types.DefaultMerkleHasher, mpool, proxyApp, nil, nil, nil, 0, nil, logger)
+	require.NoError(err)
+
+	aggregator := newTestAggregator()
+	state := aggregator.state()
+	state.ConsensusParams.Validator = &cmproto.ValidatorParams{PubKeyTypes: []string{ed25519.KeyType}}
+
+	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	aggregator.sign(t, block)
+
+	// proposer commits to the updated aggregator set
+	newState, _, err := executor.ApplyProposedBlock(context.Background(), state, block)
+	require.NoError(err)
+	require.Len(newState.NextValidators.Validators, 2)
+	assert.True(newState.NextValidators.HasAddress(newKey.PubKey().Address()))
+	assert.Equal(types.Hash(newState.NextValidators.Hash()), block.SignedHeader.NextAggregatorsHash)
+	assert.NotEqual(types.Hash(state.NextValidators.Hash()), block.SignedHeader.NextAggregatorsHash)
+
+	// syncing node verifies the transition
+	aggregator.sign(t, block)
+	_, _, err = executor.ApplyBlock(context.Background(), state, block)
+	assert.NoError(err)
+
+	block.SignedHeader.NextAggregatorsHash = state.NextValidators.Hash()
+	aggregator.sign(t, block)
+	_, _, err = executor.ApplyBlock(context.Background(), state, block)
+	assert.ErrorIs(err, ErrNextAggregatorsHashMismatch)
+}
+
+func TestApplyBlockWithConsensusParamUpdates(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	proxyApp, mpool := newMockApp(t, logger, func(app *mocks.Application) {
+		app.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{
+			ConsensusParamUpdates: &cmproto.ConsensusParams{
+				Block: &cmproto.BlockParams{MaxBytes: 200, MaxGas: 5000},
+			},
+		})
+	})
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, "test", types.DefaultMerkleHasher, mpool, proxyApp, nil, nil, nil, 0, nil, logger)
+	require.NoError(err)
+
+	aggregator := newTestAggregator()
+	params := cmtypes.DefaultConsensusParams()
+	params.Block.MaxBytes = 100
+	// evidence can't exceed block size, before or after the update
+	params.Evidence.MaxBytes = 100
+	state := aggregator.state()
+	state.ConsensusParams = params.ToProto()
+
+	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	assert.Equal(types.ConsensusParamsHash(state.ConsensusParams), block.SignedHeader.ConsensusHash)
+	aggregator.sign(t, block)
+
+	newState, _, err := executor.ApplyProposedBlock(context.Background(), state, block)
+	require.NoError(err)
+	assert.Equal(int64(200), newState.ConsensusParams.Block.MaxBytes)
+	assert.Equal(int64(5000), newState.ConsensusParams.Block.MaxGas)
+	assert.Equal(state.ConsensusParams.Evidence, newState.ConsensusParams.Evidence)
+	assert.Equal(uint64(2), newState.LastHeightConsensusParamsChanged)
+
+	// block committing to outdated consensus params is rejected
+	block = executor.CreateBlock(2, &types.Commit{}, []byte{}, newState)
+	block.SignedHeader.ConsensusHash = types.ConsensusParamsHash(state.ConsensusParams)
+	aggregator.sign(t, block)
+	assert.EqualError(executor.Validate(newState, block), "ConsensusHash mismatch")
+
+	// block exceeding max block bytes is rejected
+	block = executor.CreateBlock(2, &types.Commit{}, []byte{}, newState)
+	block.Data.Txs = types.Txs{make(types.Tx, 300)}
+	aggregator.sign(t, block)
+	assert.ErrorIs(executor.Validate(newState, block), ErrBlockTooBig)
+}
+
+func TestApplyBlockGasLimit(t *testing.T) {
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	proxyApp, mpool := newMockApp(t, logger, func(app *mocks.Application) {
+		app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{GasWanted: 40})
+		app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{GasWanted: 40})
+	})
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, "test", types.DefaultMerkleHasher, mpool, proxyApp, nil, nil, nil, 0, nil, logger)
+	require.NoError(err)
+
+	aggregator := newTestAggregator()
+	state := aggregator.state()
+	state.ConsensusParams.Block.MaxGas = 100
+
+	for i := byte(0); i < 3; i++ {
+		require.NoError(mpool.CheckTx([]byte{i}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	}
+
+	// reaping stops at max gas
+	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	require.Len(block.Data.Txs, 2)
+	block.SignedHeader.NextAggregatorsHash = state.NextValidators.Hash()
+	aggregator.sign(t, block)
+	_, _, err = executor.ApplyBlock(context.Background(), state, block)
+	require.NoError(err)
+
+	// transactions added to created block are limited by gas wanted in CheckTx; unknown ones are not accounted
+	txs := executor.LimitBlockGas(state, types.Txs{{0}, {3}, {1}, {2}})
+	require.Equal(types.Txs{{0}, {3}, {1}}, txs)
+
+	// produced block with unknown transaction exceeds max gas in DeliverTx, but it's applied by the proposer
+	block = executor.CreateBlockWithTxs(1, &types.Commit{}, []byte{}, state, txs)
+	_, _, err = executor.ApplyProposedBlock(context.Background(), state, block)
+	require.NoError(err)
+
+	// syncing node rejects block exceeding max gas
+	block = executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	block.Data.Txs = types.Txs{{0}, {1}, {2}}
+	block.SignedHeader.NextAggregatorsHash = state.NextValidators.Hash()
+	aggregator.sign(t, block)
+	_, _, err = executor.ApplyBlock(context.Background(), state, block)
+	require.ErrorIs(err, ErrBlockGasExceeded)
+}
+
+func TestApplyBlockTimeout(t *testing.T) {
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	proxyApp, mpool := newMockApp(t, logger, func(app *mocks.Application) {
+		app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{}).After(time.Second)
+	})
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, "test", types.DefaultMerkleHasher, mpool, proxyApp, nil, nil, nil, 10*time.Millisecond, nil, logger)
+	require.NoError(err)
+
+	aggregator := newTestAggregator()
+	state := aggregator.state()
+
+	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	aggregator.sign(t, block)
+
+	start := time.Now()
+	_, _, err = executor.ApplyProposedBlock(context.Background(), state, block)
+	require.ErrorIs(err, ErrABCITimeout)
+	require.Less(time.Since(start), time.Second)
+
+	// abandoned call is accounted until the application returns
+	require.Equal(int32(1), executor.abandonedCalls.Load())
+	require.Eventually(func() bool { return executor.abandonedCalls.Load() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestApplyBlockWithTxPreValidation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	proxyApp, mpool := newMockApp(t, logger, nil)
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, "test", types.DefaultMerkleHasher, mpool, proxyApp, nil, &mockTxValidator{}, nil, 0, nil, logger)
+	require.NoError(err)
+
+	aggregator := newTestAggregator()
+	state := aggregator.state()
+
+	for _, tx := range []types.Tx{{1, 1}, {0, 1}, {1, 2}, {0, 2}} {
+		require.NoError(mpool.CheckTx(cmtypes.Tx(tx), func(r *abci.Response) {}, mempool.TxInfo{}))
+	}
+
+	// invalid transactions are removed from created block and mempool
+	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	require.Len(block.Data.Txs, 4)
+	block.Data.Txs, err = executor.PreValidateTxs(context.Background(), block.Data.Txs)
+	require.NoError(err)
+	assert.Equal(types.Txs{{1, 1}, {1, 2}}, block.Data.Txs)
+	assert.Equal(2, mpool.Size())
+	aggregator.sign(t, block)
+	_, _, err = executor.ApplyProposedBlock(context.Background(), state, block)
+	require.NoError(err)
+
+	// block with invalid transaction is rejected by syncing node
+	block.Data.Txs = append(block.Data.Txs, types.Tx{0, 3})
+	aggregator.sign(t, block)
+	_, _, err = executor.ApplyBlock(context.Background(), state, block)
+	assert.ErrorIs(err, ErrInvalidTx)
+}
+
+// newMockApp returns connections to the mocked application and the mempool using it. The application responds with
+// empty responses to ABCI calls made while executing blocks; responses set by setup take precedence, as the mock
+// returns the first matching response.
+func newMockApp(t *testing.T, logger log.Logger, setup func(app *mocks.Application)) (proxy.AppConnConsensus, *mempoolv1.TxMempool) {
+	app := &mocks.Application{}
+	if setup != nil {
+		setup(app)
+	}
+	app.On(CheckTx, mock.Anything).Return(abci.ResponseCheckTx{})
+	app.On(BeginBlock, mock.Anything).Return(abci.ResponseBeginBlock{})
+	app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{})
+	app.On(EndBlock, mock.Anything).Return(abci.ResponseEndBlock{})
+
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(t, err)
+	mpool := mempoolv1.NewTxMempool(logger, cfg.DefaultMempoolConfig(), proxy.NewAppConnMempool(client, proxy.NopMetrics()), 0)
+	return proxy.NewAppConnConsensus(client, proxy.NopMetrics()), mpool
+}
+
+// testAggregator is the only aggregator of the chain in executor tests.
+type testAggregator struct {
+	key        ed25519.PrivKey
+	validators []*cmtypes.Validator
+}
+
+func newTestAggregator() *testAggregator {
+	key := ed25519.GenPrivKey()
+	return &testAggregator{key: key, validators: []*cmtypes.Validator{cmtypes.NewValidator(key.PubKey(), 100)}}
+}
+
+// state returns the initial state of the chain.
+func (a *testAggregator) state() types.State {
+	state := types.State{
+		NextValidators: cmtypes.NewValidatorSet(a.validators),
+		Validators:     cmtypes.NewValidatorSet(a.validators),
+		LastValidators: cmtypes.NewValidatorSet(a.validators),
+	}
+	state.InitialHeight = 1
+	state.ConsensusParams.Block = &cmproto.BlockParams{MaxBytes: 100, MaxGas: 100000}
+	return state
+}
+
+// sign sets DataHash of the block and signs its header.
+func (a *testAggregator) sign(t *testing.T, block *types.Block) {
+	var err error
+	block.SignedHeader.DataHash, err = block.Data.Hash()
+	require.NoError(t, err)
+	headerBytes, err := block.SignedHeader.Header.MarshalBinary()
+	require.NoError(t, err)
+	sig, err := a.key.Sign(headerBytes)
+	require.NoError(t, err)
+	block.SignedHeader.Commit = types.Commit{Signatures: []types.Signature{sig}}
+	block.SignedHeader.Validators = cmtypes.NewValidatorSet(a.validators)
+}
+
+type mockTxValidator struct{}
+
+func (v *mockTxValidator) ValidateTx(tx types.Tx) error {
+	if tx[0] == 0 {
+		return ErrInvalidTx
+	}
+	return nil
+}
+
+type mockISRProvider struct {
+	calls int
+	err   error
+}
+
+func (p *mockISRProvider) GetIntermediateStateRoot() ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.calls++
+	return []byte{byte(p.calls)}, nil
+}
+
+type mockWitnessProvider struct {
+	mockISRProvider
+	witnesses []types.StateWitness
+}
+
+func (p *mockWitnessProvider) GetStateWitnesses() ([]types.StateWitness, error) {
+	return p.witnesses, nil
+}
+
+type mockVerifierProvider struct {
+	mockWitnessProvider
+	postStateRoot []byte
+}
+
+func (p *mockVerifierProvider) ExecuteWithWitnesses(proof *types.StateFraudProof) ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.postStateRoot, nil
+}
+
+func TestApplyBlockWithIntermediateStateRoots(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	logger := log.TestingLogger()
+
+	proxyApp, mpool := newMockApp(t, logger, func(app *mocks.Application) {
+		app.On(DeliverTx, mock.Anything).Return(abci.ResponseDeliverTx{Code: abci.CodeTypeOK, Data: []byte("ok")})
+	})
+	witnesses := []types.StateWitness{{Key: []byte("key"), Value: []byte("value"), Proof: []byte("proof")}}
+	isrProvider := &mockWitnessProvider{witnesses: witnesses}
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, "test", types.DefaultMerkleHasher, mpool, proxyApp, isrProvider, nil, nil, 0, nil, logger)
+	require.NoError(err)
+
+	aggregator := newTestAggregator()
+	state := aggregator.state()
+
+	require.NoError(mpool.CheckTx([]byte{1, 2, 3, 4}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	require.NoError(mpool.CheckTx([]byte{5, 6, 7, 8}, func(r *abci.Response) {}, mempool.TxInfo{}))
+	block := executor.CreateBlock(1, &types.Commit{}, []byte{}, state)
+	require.Len(block.Data.Txs, 2)
+	assert.Nil(block.Data.IntermediateStateRoots.RawRootsList)
+
+	apply := func(block *types.Block, proposed bool) error {
+		isrProvider.calls = 0
+		aggregator.sign(t, block)
+		var err error
+		if proposed {
+			_, _, err = executor.ApplyProposedBlock(context.Background(), state, block)
+		} else {
+			_, _, err = executor.ApplyBlock(context.Background(), state, block)
+		}
+		return err
+	}
+	withISRs := func(isrs [][]byte) *types.Block {
+		b := *block
+		b.Data.IntermediateStateRoots.RawRootsList = isrs
+		return &b
+	}
+
+	// syncing node rejects block without ISRs, and doesn't modify it
+	err = apply(block, false)
+	require.Error(err)
+	assert.Nil(block.Data.IntermediateStateRoots.RawRootsList)
+
+	// proposer fills ISRs: after BeginBlock and after each of 2 txs
+	_, resp, err := executor.ApplyProposedBlock(context.Background(), state, block)
+	require.NoError(err)
+	require.Len(resp.DeliverTxs, 2)
+	for _, res := range resp.DeliverTxs {
+		assert.Equal([]byte("ok"), res.Data)
+	}
+	expected := [][]byte{{1}, {2}, {3}}
+	assert.Equal(expected, block.Data.IntermediateStateRoots.RawRootsList)
+	txsWithISRs, err := block.Data.Txs.ToTxsWithISRs(block.Data.IntermediateStateRoots)
+	require.NoError(err)
+	require.Len(txsWithISRs, 2)
+	assert.Equal([]byte{2}, txsWithISRs[1].PreIsr)
+	assert.Equal([]byte{3}, txsWithISRs[1].PostIsr)
+
+	// re-execution yielding the same roots passes
+	assert.NoError(apply(withISRs(expected), false))
+
+	// wrong number of ISRs is rejected, even by proposer
+	err = apply(withISRs([][]byte{{1}, {2}}), false)
+	require.Error(err)
+	assert.Contains(err.Error(), "invalid number of intermediate state roots")
+	assert.Error(apply(withISRs([][]byte{{1}, {2}, {3}, {4}}), true))
+
+	// tampered ISR is detected and proven
+	tampered := withISRs([][]byte{{1}, {2}, {42}})
+	err = apply(tampered, false)
+	var mismatch *ISRMismatchError
+	require.ErrorAs(err, &mismatch)
+	assert.Equal(2, mismatch.Index)
+	assert.Equal([]byte{42}, mismatch.Committed)
+	assert.Equal([]byte{3}, mismatch.Computed)
+	require.NotNil(mismatch.FraudProof)
+	proof := *mismatch.FraudProof
+	assert.Equal(uint64(2), proof.NumTxs)
+	dataHash, err := tampered.Data.Hash()
+	require.NoError(err)
+	assert.NoError(proof.VerifyInclusion(dataHash, types.DefaultMerkleHasher))
+	proof.NumTxs, proof.TxProof, proof.PreStateRootProof, proof.PostStateRootProof = 0, merkle.Proof{}, merkle.Proof{}, merkle.Proof{}
+	assert.Equal(types.StateFraudProof{
+		BlockHeight:            1,
+		TxIndex:                1,
+		PreStateRoot:           []byte{2},
+		Tx:                     block.Data.Txs[1],
+		CommittedPostStateRoot: []byte{42},
+		ExpectedPostStateRoot:  []byte{3},
+		Witnesses:              witnesses,
+	}, proof)
+	assert.NoError(mismatch.FraudProof.ValidateBasic())
+
+	// invalid BeginBlock transition can't be proven with state fraud proof
+	err = apply(withISRs([][]byte{{42}, {2}, {3}}), false)
+	require.ErrorAs(err, &mismatch)
+	assert.Equal(0, mismatch.Index)
+	assert.Nil(mismatch.FraudProof)
+
+	// provider errors are propagated
+	isrProvider.err = errors.New("provider failure")
+	err = apply(withISRs(expected), false)
+	assert.ErrorIs(err, isrProvider.err)
+}
+
+func TestABCIIntermediateStateRootProvider(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("Query", abci.RequestQuery{Path: ISRQueryPath}).Return(abci.ResponseQuery{Code: abci.CodeTypeOK, Value: []byte{1, 2, 3}}).Once()
+	app.On("Query", abci.RequestQuery{Path: ISRQueryPath}).Return(abci.ResponseQuery{Code: 1, Log: "unsupported"}).Once()
+
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(err)
+	provider := NewABCIIntermediateStateRootProvider(proxy.NewAppConnQuery(client, proxy.NopMetrics()))
+
+	isr, err := provider.GetIntermediateStateRoot()
+	require.NoError(err)
+	assert.Equal([]byte{1, 2, 3}, isr)
+
+	_, err = provider.GetIntermediateStateRoot()
+	assert.ErrorContains(err, "unsupported")
+
+	witnesses := []types.StateWitness{{Key: []byte("key"), Value: []byte("value"), Proof: []byte("proof")}}
+	encoded, err := (&pb.StateWitnesses{Witnesses: types.StateWitnessesToProto(witnesses)}).Marshal()
+	require.NoError(err)
+	app.On("Query", abci.RequestQuery{Path: WitnessesQueryPath}).Return(abci.ResponseQuery{Code: abci.CodeTypeOK, Value: encoded})
+
+	actual, err := provider.GetStateWitnesses()
+	require.NoError(err)
+	assert.Equal(witnesses, actual)
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ErrInvalidFraudProof is returned when state fraud proof doesn't prove an invalid state transition.
+var ErrInvalidFraudProof = errors.New("invalid fraud proof")
+
+// ErrFraudProofVerificationUnsupported is returned when the node is not able to re-execute transactions from fraud proofs.
+var ErrFraudProofVerificationUnsupported = errors.New("fraud proof verification is not supported")
+
+// VerifyFraudProof checks if proof is a valid proof of invalid state transition in the given block.
+//
+// Proof is valid, if its pre-state root, transaction and committed post-state root match the block, and
+// execution of the transaction on top of the witnessed state yields a different state root than the committed one.
+// Block has to be validated by the caller.
+func (e *BlockExecutor) VerifyFraudProof(block *types.Block, proof *types.StateFraudProof) error {
+	if err := proof.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFraudProof, err)
+	}
+	if block.Height() != proof.BlockHeight {
+		return fmt.Errorf("%w: block height %d doesn't match proof height %d", ErrInvalidFraudProof, block.Height(), proof.BlockHeight)
+	}
+	isrs := block.Data.IntermediateStateRoots.RawRootsList
+	if err := validateISRsLength(isrs, block.Data.Txs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFraudProof, err)
+	}
+	if proof.TxIndex >= uint64(len(block.Data.Txs)) {
+		return fmt.Errorf("%w: tx index %d out of range", ErrInvalidFraudProof, proof.TxIndex)
+	}
+	if !bytes.Equal(block.Data.Txs[proof.TxIndex], proof.Tx) {
+		return fmt.Errorf("%w: transaction doesn't match block data", ErrInvalidFraudProof)
+	}
+	if !bytes.Equal(isrs[proof.TxIndex], proof.PreStateRoot) || !bytes.Equal(isrs[proof.TxIndex+1], proof.CommittedPostStateRoot) {
+		return fmt.Errorf("%w: state roots don't match block data", ErrInvalidFraudProof)
+	}
+
+	verifier, ok := e.isrProvider.(StateTransitionVerifier)
+	if !ok {
+		return ErrFraudProofVerificationUnsupported
+	}
+	postStateRoot, err := verifier.ExecuteWithWitnesses(proof)
+	if err != nil {
+		return fmt.Errorf("failed to execute transaction with witnesses: %w", err)
+	}
+	if bytes.Equal(postStateRoot, proof.CommittedPostStateRoot) {
+		return fmt.Errorf("%w: committed state transition is valid", ErrInvalidFraudProof)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestVerifyFraudProof(t *testing.T) {
+	block := &types.Block{}
+	block.SignedHeader.Header.BaseHeader.Height = 5
+	block.Data.Txs = types.Txs{[]byte{1}, []byte{2}}
+	block.Data.IntermediateStateRoots.RawRootsList = [][]byte{{1}, {2}, {42}}
+
+	validProof := func() *types.StateFraudProof {
+		return &types.StateFraudProof{
+			BlockHeight:            5,
+			TxIndex:                1,
+			PreStateRoot:           []byte{2},
+			Tx:                     []byte{2},
+			CommittedPostStateRoot: []byte{42},
+			ExpectedPostStateRoot:  []byte{3},
+		}
+	}
+
+	provider := &mockVerifierProvider{postStateRoot: []byte{3}}
+	executor, err := NewBlockExecutor([]byte("test address"), [8]byte{}, "test", types.DefaultMerkleHasher, nil, nil, provider, nil, nil, 0, nil, log.TestingLogger())
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		modify func(*types.StateFraudProof)
+		err    error
+	}{
+		{"valid", func(p *types.StateFraudProof) {}, nil},
+		{"empty tx", func(p *types.StateFraudProof) { p.Tx = nil }, ErrInvalidFraudProof},
+		{"height mismatch", func(p *types.StateFraudProof) { p.BlockHeight = 6 }, ErrInvalidFraudProof},
+		{"tx index out of range", func(p *types.StateFraudProof) { p.TxIndex = 2 }, ErrInvalidFraudProof},
+		{"tx mismatch", func(p *types.StateFraudProof) { p.Tx = []byte{3} }, ErrInvalidFraudProof},
+		{"pre-state root mismatch", func(p *types.StateFraudProof) { p.PreStateRoot = []byte{1} }, ErrInvalidFraudProof},
+		{"post-state root mismatch", func(p *types.StateFraudProof) { p.CommittedPostStateRoot = []byte{3} }, ErrInvalidFraudProof},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			proof := validProof()
+			c.modify(proof)
+			err := executor.VerifyFraudProof(block, proof)
+			if c.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.err)
+			}
+		})
+	}
+
+	// re-execution confirms committed state root
+	provider.postStateRoot = []byte{42}
+	assert.ErrorIs(t, executor.VerifyFraudProof(block, validProof()), ErrInvalidFraudProof)
+
+	// re-execution errors are propagated
+	provider.err = errors.New("execution failure")
+	assert.ErrorIs(t, executor.VerifyFraudProof(block, validProof()), provider.err)
+
+	// node without verifier can't verify proofs
+	executor, err = NewBlockExecutor([]byte("test address"), [8]byte{}, "test", types.DefaultMerkleHasher, nil, nil, &mockISRProvider{}, nil, nil, 0, nil, log.TestingLogger())
+	require.NoError(t, err)
+	assert.ErrorIs(t, executor.VerifyFraudProof(block, validProof()), ErrFraudProofVerificationUnsupported)
+}
@@ -0,0 +1,59 @@
+package state
+
+import (
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// HeaderExtensionsQueryPath is the ABCI query path used to request header extensions of the last applied block.
+// Request data is protobuf encoded block, application is expected to return extensions encoded with
+// types.MarshalHeaderExtensions.
+const HeaderExtensionsQueryPath = "/rollkit/header_extensions"
+
+// HeaderExtender provides extensions of headers of produced blocks, where applications commit to data like
+// bridge roots or custom metadata.
+//
+// HeaderExtender is invoked by block producer after the block is applied, and before it's signed.
+type HeaderExtender interface {
+	// Extensions returns extensions of the header of the block applied on top of the state.
+	Extensions(state types.State, block *types.Block) ([]types.HeaderExtension, error)
+}
+
+// ABCIHeaderExtender fetches header extensions from the application using ABCI Query.
+type ABCIHeaderExtender struct {
+	proxyApp proxy.AppConnQuery
+}
+
+var _ HeaderExtender = &ABCIHeaderExtender{}
+
+// NewABCIHeaderExtender creates new instance of ABCIHeaderExtender.
+func NewABCIHeaderExtender(proxyApp proxy.AppConnQuery) *ABCIHeaderExtender {
+	return &ABCIHeaderExtender{proxyApp: proxyApp}
+}
+
+// Extensions queries the application for header extensions of the applied (but not committed yet) block.
+func (e *ABCIHeaderExtender) Extensions(state types.State, block *types.Block) ([]types.HeaderExtension, error) {
+	data, err := block.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.proxyApp.QuerySync(abci.RequestQuery{Path: HeaderExtensionsQueryPath, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("header extensions query failed with code %d: %s", resp.Code, resp.Log)
+	}
+	extensions, err := types.UnmarshalHeaderExtensions(resp.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode header extensions: %w", err)
+	}
+	if err := types.ValidateHeaderExtensions(extensions); err != nil {
+		return nil, err
+	}
+	return extensions, nil
+}
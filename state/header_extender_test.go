@@ -0,0 +1,49 @@
+package state
+
+import (
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestABCIHeaderExtender(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	block := &types.Block{}
+	block.SignedHeader.Header.BaseHeader.Height = 1
+	data, err := block.MarshalBinary()
+	require.NoError(err)
+
+	extensions := []types.HeaderExtension{{Type: "bridge_root", Data: []byte("root")}}
+	value, err := types.MarshalHeaderExtensions(extensions)
+	require.NoError(err)
+	invalid, err := types.MarshalHeaderExtensions([]types.HeaderExtension{{Type: "a"}, {Type: "a"}})
+	require.NoError(err)
+
+	app := &mocks.Application{}
+	app.On("Query", abci.RequestQuery{Path: HeaderExtensionsQueryPath, Data: data}).Return(abci.ResponseQuery{Code: abci.CodeTypeOK, Value: value}).Once()
+	app.On("Query", abci.RequestQuery{Path: HeaderExtensionsQueryPath, Data: data}).Return(abci.ResponseQuery{Code: abci.CodeTypeOK, Value: invalid}).Once()
+	app.On("Query", mock.Anything).Return(abci.ResponseQuery{Code: 1, Log: "unsupported"})
+
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(err)
+	extender := NewABCIHeaderExtender(proxy.NewAppConnQuery(client, proxy.NopMetrics()))
+
+	got, err := extender.Extensions(types.State{}, block)
+	require.NoError(err)
+	assert.Equal(extensions, got)
+
+	_, err = extender.Extensions(types.State{}, block)
+	assert.ErrorIs(err, types.ErrInvalidHeaderExtension)
+
+	_, err = extender.Extensions(types.State{}, block)
+	assert.ErrorContains(err, "unsupported")
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+
+	"github.com/rollkit/rollkit/types"
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
+)
+
+const (
+	// ISRQueryPath is the ABCI query path used to fetch the current intermediate state root from the application.
+	ISRQueryPath = "/rollkit/isr"
+	// WitnessesQueryPath is the ABCI query path used to fetch witnesses of the state accessed by the last transaction.
+	// Application is expected to return protobuf encoded StateWitnesses.
+	WitnessesQueryPath = "/rollkit/witnesses"
+	// ExecuteWithWitnessesQueryPath is the ABCI query path used to re-execute transaction from a state fraud proof.
+	// Request data is protobuf encoded StateFraudProof, application is expected to return the resulting state root.
+	ExecuteWithWitnessesQueryPath = "/rollkit/execute_with_witnesses"
+)
+
+// IntermediateStateRootProvider returns the application state root at the current point of block execution.
+type IntermediateStateRootProvider interface {
+	GetIntermediateStateRoot() ([]byte, error)
+}
+
+// StateWitnessProvider is implemented by IntermediateStateRootProviders that are able to generate
+// witnesses required for state fraud proofs.
+type StateWitnessProvider interface {
+	// GetStateWitnesses returns witnesses of all the state accessed by the most recently delivered
+	// transaction, proven against the intermediate state root preceding that transaction.
+	GetStateWitnesses() ([]types.StateWitness, error)
+}
+
+// StateTransitionVerifier is implemented by IntermediateStateRootProviders that are able to re-execute
+// transactions without full state, which is required to verify state fraud proofs.
+type StateTransitionVerifier interface {
+	// ExecuteWithWitnesses executes the transaction from the proof on top of the state described by
+	// pre-state root and witnesses of the proof, and returns the resulting state root.
+	ExecuteWithWitnesses(proof *types.StateFraudProof) ([]byte, error)
+}
+
+// ABCIIntermediateStateRootProvider fetches intermediate state roots from the application using ABCI Query.
+type ABCIIntermediateStateRootProvider struct {
+	proxyApp proxy.AppConnQuery
+}
+
+var _ IntermediateStateRootProvider = &ABCIIntermediateStateRootProvider{}
+var _ StateWitnessProvider = &ABCIIntermediateStateRootProvider{}
+var _ StateTransitionVerifier = &ABCIIntermediateStateRootProvider{}
+
+// NewABCIIntermediateStateRootProvider creates new instance of ABCIIntermediateStateRootProvider.
+func NewABCIIntermediateStateRootProvider(proxyApp proxy.AppConnQuery) *ABCIIntermediateStateRootProvider {
+	return &ABCIIntermediateStateRootProvider{proxyApp: proxyApp}
+}
+
+// GetIntermediateStateRoot queries the application for its current (uncommitted) state root.
+func (p *ABCIIntermediateStateRootProvider) GetIntermediateStateRoot() ([]byte, error) {
+	resp, err := p.proxyApp.QuerySync(abci.RequestQuery{Path: ISRQueryPath})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("intermediate state root query failed with code %d: %s", resp.Code, resp.Log)
+	}
+	if len(resp.Value) == 0 {
+		return nil, fmt.Errorf("intermediate state root query returned empty value")
+	}
+	return resp.Value, nil
+}
+
+// GetStateWitnesses queries the application for witnesses of the state accessed by the last transaction.
+func (p *ABCIIntermediateStateRootProvider) GetStateWitnesses() ([]types.StateWitness, error) {
+	resp, err := p.proxyApp.QuerySync(abci.RequestQuery{Path: WitnessesQueryPath})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("state witnesses query failed with code %d: %s", resp.Code, resp.Log)
+	}
+	var witnesses pb.StateWitnesses
+	if err := witnesses.Unmarshal(resp.Value); err != nil {
+		return nil, fmt.Errorf("failed to decode state witnesses: %w", err)
+	}
+	return types.StateWitnessesFromProto(witnesses.Witnesses), nil
+}
+
+// ExecuteWithWitnesses asks the application to re-execute transaction from the proof using only provided witnesses.
+func (p *ABCIIntermediateStateRootProvider) ExecuteWithWitnesses(proof *types.StateFraudProof) ([]byte, error) {
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.proxyApp.QuerySync(abci.RequestQuery{Path: ExecuteWithWitnessesQueryPath, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("execution with witnesses failed with code %d: %s", resp.Code, resp.Log)
+	}
+	return resp.Value, nil
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "state"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of transactions in applied blocks.
+	BlockTxs metrics.Histogram
+
+	// Total gas used by transactions of applied blocks.
+	BlockGasUsed metrics.Histogram
+
+	// Time spent on block creation, in seconds.
+	BlockCreationTime metrics.Histogram
+
+	// Time spent on block validation and execution, in seconds.
+	BlockProcessingTime metrics.Histogram
+
+	// Duration of calls to the application, in seconds, labeled by ABCI method.
+	ABCICallDuration metrics.Histogram
+
+	// Number of blocks that failed validation or execution.
+	RejectedBlocks metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		BlockTxs: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_txs",
+			Help:      "Number of transactions in applied blocks.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 14),
+		}, labels).With(labelsAndValues...),
+
+		BlockGasUsed: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_gas_used",
+			Help:      "Total gas used by transactions of applied blocks.",
+			Buckets:   stdprometheus.ExponentialBuckets(1000, 4, 12),
+		}, labels).With(labelsAndValues...),
+
+		BlockCreationTime: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_creation_time",
+			Help:      "Time spent on block creation, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, labels).With(labelsAndValues...),
+
+		BlockProcessingTime: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_processing_time",
+			Help:      "Time spent on block validation and execution, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, labels).With(labelsAndValues...),
+
+		ABCICallDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "abci_call_duration",
+			Help:      "Duration of calls to the application, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 16),
+		}, append(labels, "method")).With(labelsAndValues...),
+
+		RejectedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rejected_blocks",
+			Help:      "Number of blocks that failed validation or execution.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		BlockTxs:            discard.NewHistogram(),
+		BlockGasUsed:        discard.NewHistogram(),
+		BlockCreationTime:   discard.NewHistogram(),
+		BlockProcessingTime: discard.NewHistogram(),
+		ABCICallDuration:    discard.NewHistogram(),
+		RejectedBlocks:      discard.NewCounter(),
+	}
+}
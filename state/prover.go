@@ -0,0 +1,54 @@
+package state
+
+import (
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// ValidityProofQueryPath is the ABCI query path used to request validity proof of the last applied block.
+// Request data is protobuf encoded block, application is expected to return the proof.
+const ValidityProofQueryPath = "/rollkit/validity_proof"
+
+// Prover generates validity proofs (e.g. zero-knowledge proofs) of state transitions.
+//
+// Prover is invoked by block producer after the block is applied, and before it's signed.
+// Commitment to the proof is included in the block header, and the proof itself is carried in the block commit.
+type Prover interface {
+	// Prove returns validity proof of the state transition caused by applying the block on top of the state.
+	Prove(state types.State, block *types.Block) ([]byte, error)
+}
+
+// ABCIProver fetches validity proofs from the application using ABCI Query.
+type ABCIProver struct {
+	proxyApp proxy.AppConnQuery
+}
+
+var _ Prover = &ABCIProver{}
+
+// NewABCIProver creates new instance of ABCIProver.
+func NewABCIProver(proxyApp proxy.AppConnQuery) *ABCIProver {
+	return &ABCIProver{proxyApp: proxyApp}
+}
+
+// Prove queries the application for validity proof of the applied (but not committed yet) block.
+func (p *ABCIProver) Prove(state types.State, block *types.Block) ([]byte, error) {
+	data, err := block.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.proxyApp.QuerySync(abci.RequestQuery{Path: ValidityProofQueryPath, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("validity proof query failed with code %d: %s", resp.Code, resp.Log)
+	}
+	if len(resp.Value) == 0 {
+		return nil, fmt.Errorf("validity proof query returned empty value")
+	}
+	return resp.Value, nil
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/test/mocks"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestABCIProver(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	block := &types.Block{}
+	block.SignedHeader.Header.BaseHeader.Height = 1
+	data, err := block.MarshalBinary()
+	require.NoError(err)
+
+	app := &mocks.Application{}
+	app.On("Query", abci.RequestQuery{Path: ValidityProofQueryPath, Data: data}).Return(abci.ResponseQuery{Code: abci.CodeTypeOK, Value: []byte("proof")}).Once()
+	app.On("Query", mock.Anything).Return(abci.ResponseQuery{Code: 1, Log: "unsupported"})
+
+	client, err := proxy.NewLocalClientCreator(app).NewABCIClient()
+	require.NoError(err)
+	prover := NewABCIProver(proxy.NewAppConnQuery(client, proxy.NopMetrics()))
+
+	proof, err := prover.Prove(types.State{}, block)
+	require.NoError(err)
+	assert.Equal([]byte("proof"), proof)
+
+	_, err = prover.Prove(types.State{}, block)
+	assert.ErrorContains(err, "unsupported")
+}
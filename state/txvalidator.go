@@ -0,0 +1,52 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/proxy"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// TxValidationQueryPath is the ABCI query path used for stateless validation of a transaction.
+// Request data is the raw transaction, application is expected to return non-zero code for invalid transactions.
+const TxValidationQueryPath = "/rollkit/validate_tx"
+
+// ErrInvalidTx is returned when transaction fails stateless pre-validation.
+var ErrInvalidTx = errors.New("invalid transaction")
+
+// TxValidator performs stateless validation of transactions (decoding, signature checks, etc.)
+// before they are delivered to the application.
+//
+// Transactions of a block are pre-validated in parallel, so implementations must be safe for concurrent use.
+type TxValidator interface {
+	// ValidateTx returns error wrapping ErrInvalidTx if transaction is invalid.
+	// Other errors mean that validation couldn't be performed.
+	ValidateTx(tx types.Tx) error
+}
+
+// ABCITxValidator validates transactions using ABCI Query.
+type ABCITxValidator struct {
+	proxyApp proxy.AppConnQuery
+}
+
+var _ TxValidator = &ABCITxValidator{}
+
+// NewABCITxValidator creates new instance of ABCITxValidator.
+func NewABCITxValidator(proxyApp proxy.AppConnQuery) *ABCITxValidator {
+	return &ABCITxValidator{proxyApp: proxyApp}
+}
+
+// ValidateTx asks the application to validate transaction without executing it.
+func (v *ABCITxValidator) ValidateTx(tx types.Tx) error {
+	resp, err := v.proxyApp.QuerySync(abci.RequestQuery{Path: TxValidationQueryPath, Data: tx})
+	if err != nil {
+		return err
+	}
+	if resp.Code != abci.CodeTypeOK {
+		return fmt.Errorf("%w: code %d: %s", ErrInvalidTx, resp.Code, resp.Log)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// CachedStore is a Store with read-through cache of blocks and commits of the latest heights. It serves hot paths
+// (like RPC requests for the latest blocks) from memory, instead of reading and decoding blocks from the KV store.
+//
+// Blocks and commits are cached when they're loaded, not when they're saved, so cached values are never modified
+// by writers. Cache is invalidated by SaveBlock, PruneBlocks and Rollback.
+type CachedStore struct {
+	Store
+
+	// size is the number of the latest heights, blocks of which are cached
+	size uint64
+
+	mtx     sync.RWMutex
+	blocks  map[uint64]*types.Block
+	commits map[uint64]*types.Commit
+	// heights are heights of cached blocks, by block hash
+	heights map[string]uint64
+	// generation is incremented on every invalidation, so values loaded before invalidation are not cached
+	generation uint64
+}
+
+var _ Store = &CachedStore{}
+
+// NewCachedStore returns Store caching blocks and commits of the latest size heights of s.
+func NewCachedStore(s Store, size uint64) *CachedStore {
+	return &CachedStore{
+		Store:   s,
+		size:    size,
+		blocks:  make(map[uint64]*types.Block),
+		commits: make(map[uint64]*types.Commit),
+		heights: make(map[string]uint64),
+	}
+}
+
+// LoadBlock returns block at given height, from the cache if possible.
+func (s *CachedStore) LoadBlock(height uint64) (*types.Block, error) {
+	s.mtx.RLock()
+	block, ok := s.blocks[height]
+	generation := s.generation
+	s.mtx.RUnlock()
+	if ok {
+		return block, nil
+	}
+	block, err := s.Store.LoadBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	s.addBlock(generation, height, block)
+	return block, nil
+}
+
+// LoadBlockByHash returns block with given block header hash, from the cache if possible.
+func (s *CachedStore) LoadBlockByHash(hash types.Hash) (*types.Block, error) {
+	s.mtx.RLock()
+	height, ok := s.heights[hash.String()]
+	block := s.blocks[height]
+	generation := s.generation
+	s.mtx.RUnlock()
+	if ok {
+		return block, nil
+	}
+	block, err := s.Store.LoadBlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	s.addBlock(generation, uint64(block.Height()), block)
+	return block, nil
+}
+
+// LoadCommit returns commit for a block at given height, from the cache if possible.
+func (s *CachedStore) LoadCommit(height uint64) (*types.Commit, error) {
+	s.mtx.RLock()
+	commit, ok := s.commits[height]
+	generation := s.generation
+	s.mtx.RUnlock()
+	if ok {
+		return commit, nil
+	}
+	commit, err := s.Store.LoadCommit(height)
+	if err != nil {
+		return nil, err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if generation == s.generation && s.cacheable(height) {
+		s.commits[height] = commit
+		s.evict()
+	}
+	return commit, nil
+}
+
+// SaveBlock saves block along with its seen commit, and invalidates cached block and commit at its height.
+func (s *CachedStore) SaveBlock(block *types.Block, commit *types.Commit) error {
+	height := uint64(block.Height())
+	err := s.Store.SaveBlock(block, commit)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.generation++
+	s.remove(height)
+	return err
+}
+
+// PruneBlocks deletes blocks, commits and block responses below retainHeight from Store and from the cache.
+func (s *CachedStore) PruneBlocks(retainHeight uint64) (uint64, error) {
+	pruned, err := s.Store.PruneBlocks(retainHeight)
+	s.removeIf(func(height uint64) bool { return height < retainHeight })
+	return pruned, err
+}
+
+// Rollback deletes blocks, commits and block responses above given height from Store and from the cache.
+func (s *CachedStore) Rollback(height uint64) error {
+	err := s.Store.Rollback(height)
+	s.removeIf(func(h uint64) bool { return h > height })
+	return err
+}
+
+// addBlock caches block loaded at given generation of the cache.
+func (s *CachedStore) addBlock(generation, height uint64, block *types.Block) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if generation != s.generation || !s.cacheable(height) {
+		return
+	}
+	if prev, ok := s.blocks[height]; ok {
+		delete(s.heights, prev.Hash().String())
+	}
+	s.blocks[height] = block
+	s.heights[block.Hash().String()] = height
+	s.evict()
+}
+
+// cacheable returns true if height is one of the latest size heights of the store.
+func (s *CachedStore) cacheable(height uint64) bool {
+	return s.size > 0 && height+s.size > s.Store.Height()
+}
+
+// evict removes blocks and commits that are no longer among the latest heights, and the lowest heights
+// if the cache is still over its size (heights above the store height are cached during sync).
+func (s *CachedStore) evict() {
+	for height := range s.blocks {
+		if !s.cacheable(height) {
+			s.remove(height)
+		}
+	}
+	for height := range s.commits {
+		if !s.cacheable(height) {
+			s.remove(height)
+		}
+	}
+	for uint64(len(s.blocks)) > s.size || uint64(len(s.commits)) > s.size {
+		lowest := ^uint64(0)
+		for height := range s.blocks {
+			if height < lowest {
+				lowest = height
+			}
+		}
+		for height := range s.commits {
+			if height < lowest {
+				lowest = height
+			}
+		}
+		s.remove(lowest)
+	}
+}
+
+func (s *CachedStore) removeIf(filter func(height uint64) bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.generation++
+	for height := range s.blocks {
+		if filter(height) {
+			s.remove(height)
+		}
+	}
+	for height := range s.commits {
+		if filter(height) {
+			s.remove(height)
+		}
+	}
+}
+
+// remove removes block and commit at given height from the cache. It has to be called with mtx locked.
+func (s *CachedStore) remove(height uint64) {
+	if block, ok := s.blocks[height]; ok {
+		delete(s.heights, block.Hash().String())
+		delete(s.blocks, height)
+	}
+	delete(s.commits, height)
+}
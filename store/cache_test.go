@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/types"
+)
+
+// commitAt returns a commit with a signature identifying the height.
+func commitAt(height uint64) *types.Commit {
+	return &types.Commit{Signatures: []types.Signature{{byte(height)}}}
+}
+
+func TestCachedStore(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := NewCachedStore(New(ctx, kv), 3)
+
+	blocks := make(map[uint64]*types.Block)
+	for h := uint64(1); h <= 10; h++ {
+		blocks[h] = types.GetRandomBlock(h, 1)
+		require.NoError(s.SaveBlock(blocks[h], commitAt(h)))
+		s.SetHeight(h)
+	}
+
+	// only the latest heights are cached
+	for h := uint64(1); h <= 10; h++ {
+		block, err := s.LoadBlock(h)
+		require.NoError(err)
+		assert.Equal(blocks[h].Hash(), block.Hash())
+		commit, err := s.LoadCommit(h)
+		require.NoError(err)
+		require.Len(commit.Signatures, 1)
+		assert.Equal(types.Signature{byte(h)}, commit.Signatures[0])
+	}
+	assert.Len(s.blocks, 3)
+	assert.Len(s.commits, 3)
+	for h := uint64(8); h <= 10; h++ {
+		assert.Contains(s.blocks, h)
+		assert.Contains(s.commits, h)
+	}
+	cached, err := s.LoadBlock(10)
+	require.NoError(err)
+	block, err := s.LoadBlockByHash(blocks[10].Hash())
+	require.NoError(err)
+	assert.Same(cached, block)
+
+	// new height evicts the lowest one
+	blocks[11] = types.GetRandomBlock(11, 1)
+	require.NoError(s.SaveBlock(blocks[11], commitAt(11)))
+	s.SetHeight(11)
+	_, err = s.LoadBlockByHash(blocks[11].Hash())
+	require.NoError(err)
+	assert.Len(s.blocks, 3)
+	assert.NotContains(s.blocks, uint64(8))
+
+	// saving block at cached height invalidates it
+	replaced := types.GetRandomBlock(10, 2)
+	require.NoError(s.SaveBlock(replaced, commitAt(10)))
+	assert.NotContains(s.blocks, uint64(10))
+	block, err = s.LoadBlock(10)
+	require.NoError(err)
+	assert.Equal(replaced.Hash(), block.Hash())
+	assert.NotContains(s.heights, blocks[10].Hash().String())
+
+	// rolled back blocks are not served from the cache
+	require.NoError(s.Rollback(9))
+	assert.NotContains(s.blocks, uint64(10))
+	assert.NotContains(s.blocks, uint64(11))
+	_, err = s.LoadBlock(11)
+	assert.Error(err)
+	_, err = s.LoadCommit(10)
+	assert.Error(err)
+
+	// pruned blocks are not served from the cache
+	_, err = s.LoadBlock(8)
+	require.NoError(err)
+	_, err = s.PruneBlocks(9)
+	require.NoError(err)
+	assert.NotContains(s.blocks, uint64(8))
+	assert.Contains(s.blocks, uint64(9))
+	_, err = s.LoadBlock(8)
+	assert.Error(err)
+
+	// zero size disables the cache
+	kv, _ = NewDefaultInMemoryKVStore()
+	disabled := NewCachedStore(New(ctx, kv), 0)
+	require.NoError(disabled.SaveBlock(blocks[1], &types.Commit{}))
+	disabled.SetHeight(1)
+	_, err = disabled.LoadBlock(1)
+	require.NoError(err)
+	assert.Empty(disabled.blocks)
+}
@@ -2,15 +2,20 @@ package store
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync/atomic"
+	"time"
 
 	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
 	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	cmtypes "github.com/cometbft/cometbft/types"
 	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
 	"go.uber.org/multierr"
 
 	"github.com/celestiaorg/go-header"
@@ -20,14 +25,25 @@ import (
 )
 
 var (
-	blockPrefix      = "b"
-	indexPrefix      = "i"
-	commitPrefix     = "c"
-	statePrefix      = "s"
-	responsesPrefix  = "r"
-	validatorsPrefix = "v"
+	blockPrefix       = "b"
+	indexPrefix       = "i"
+	commitPrefix      = "c"
+	statePrefix       = "s"
+	responsesPrefix   = "r"
+	validatorsPrefix  = "v"
+	nextValsPrefix    = "n"
+	fraudProofPrefix  = "f"
+	paramsPrefix      = "p"
+	daLocationPrefix  = "l"
+	daHeightPrefix    = "d"
+	daCostPrefix      = "x"
+	dailyDACostPrefix = "y"
+	appHashPrefix     = "a"
 )
 
+// deleteBatchSize is the maximal number of blocks deleted in a single transaction.
+const deleteBatchSize = 1000
+
 // DefaultStore is a default store implmementation.
 type DefaultStore struct {
 	db ds.TxnDatastore
@@ -59,6 +75,26 @@ func (s *DefaultStore) Height() uint64 {
 	return atomic.LoadUint64(&s.height)
 }
 
+// EarliestHeight returns height of the lowest block saved in the Store, or 0 if the Store is empty.
+// Blocks are kept in a contiguous range of heights ending at the height of the Store, as they are pruned from
+// the bottom, so the lowest block is found with binary search.
+func (s *DefaultStore) EarliestHeight() uint64 {
+	height := s.Height()
+	if height == 0 {
+		return 0
+	}
+	lo, hi := uint64(1), height
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if found, err := s.db.Has(s.ctx, ds.NewKey(getIndexKey(mid))); err == nil && found {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
 // SaveBlock adds block to the store along with corresponding commit.
 // Stored height is updated if block height is greater than stored value.
 func (s *DefaultStore) SaveBlock(block *types.Block, commit *types.Commit) error {
@@ -201,6 +237,33 @@ func (s *DefaultStore) LoadState() (types.State, error) {
 
 // SaveValidators stores validator set for given block height in store.
 func (s *DefaultStore) SaveValidators(height uint64, validatorSet *cmtypes.ValidatorSet) error {
+	return s.saveValidatorSet(getValidatorsKey(height), validatorSet)
+}
+
+// LoadValidators loads validator set at given block height from store.
+func (s *DefaultStore) LoadValidators(height uint64) (*cmtypes.ValidatorSet, error) {
+	valSet, err := s.loadValidatorSet(getValidatorsKey(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Validators for height %v: %w", height, err)
+	}
+	return valSet, nil
+}
+
+// SaveNextValidators stores the next validator set of the state that block at given height was created on.
+func (s *DefaultStore) SaveNextValidators(height uint64, validatorSet *cmtypes.ValidatorSet) error {
+	return s.saveValidatorSet(getNextValidatorsKey(height), validatorSet)
+}
+
+// LoadNextValidators loads the next validator set of the state that block at given height was created on.
+func (s *DefaultStore) LoadNextValidators(height uint64) (*cmtypes.ValidatorSet, error) {
+	valSet, err := s.loadValidatorSet(getNextValidatorsKey(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load next Validators for height %v: %w", height, err)
+	}
+	return valSet, nil
+}
+
+func (s *DefaultStore) saveValidatorSet(key string, validatorSet *cmtypes.ValidatorSet) error {
 	pbValSet, err := validatorSet.ToProto()
 	if err != nil {
 		return fmt.Errorf("failed to marshal ValidatorSet to protobuf: %w", err)
@@ -210,14 +273,13 @@ func (s *DefaultStore) SaveValidators(height uint64, validatorSet *cmtypes.Valid
 		return fmt.Errorf("failed to marshal ValidatorSet: %w", err)
 	}
 
-	return s.db.Put(s.ctx, ds.NewKey(getValidatorsKey(height)), blob)
+	return s.db.Put(s.ctx, ds.NewKey(key), blob)
 }
 
-// LoadValidators loads validator set at given block height from store.
-func (s *DefaultStore) LoadValidators(height uint64) (*cmtypes.ValidatorSet, error) {
-	blob, err := s.db.Get(s.ctx, ds.NewKey(getValidatorsKey(height)))
+func (s *DefaultStore) loadValidatorSet(key string) (*cmtypes.ValidatorSet, error) {
+	blob, err := s.db.Get(s.ctx, ds.NewKey(key))
 	if err != nil {
-		return nil, fmt.Errorf("failed to load Validators for height %v: %w", height, err)
+		return nil, err
 	}
 	var pbValSet cmproto.ValidatorSet
 	err = pbValSet.Unmarshal(blob)
@@ -234,6 +296,348 @@ func (s *DefaultStore) LoadValidators(height uint64) (*cmtypes.ValidatorSet, err
 	return cmtypes.ValidatorSetFromProto(&pbValSet)
 }
 
+// SaveConsensusParams stores consensus parameters in effect for given block height in store.
+func (s *DefaultStore) SaveConsensusParams(height uint64, params cmproto.ConsensusParams) error {
+	blob, err := params.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal ConsensusParams: %w", err)
+	}
+	return s.db.Put(s.ctx, ds.NewKey(getConsensusParamsKey(height)), blob)
+}
+
+// LoadConsensusParams loads consensus parameters in effect for given block height from store.
+func (s *DefaultStore) LoadConsensusParams(height uint64) (cmproto.ConsensusParams, error) {
+	var params cmproto.ConsensusParams
+	blob, err := s.db.Get(s.ctx, ds.NewKey(getConsensusParamsKey(height)))
+	if err != nil {
+		return params, fmt.Errorf("failed to load ConsensusParams for height %v: %w", height, err)
+	}
+	if err := params.Unmarshal(blob); err != nil {
+		return params, fmt.Errorf("failed to unmarshal to protobuf: %w", err)
+	}
+	return params, nil
+}
+
+// SaveFraudProof stores state fraud proof for the block at height given by the proof.
+func (s *DefaultStore) SaveFraudProof(proof *types.StateFraudProof) error {
+	blob, err := proof.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal StateFraudProof: %w", err)
+	}
+	return s.db.Put(s.ctx, ds.NewKey(getFraudProofKey(proof.BlockHeight)), blob)
+}
+
+// LoadFraudProof returns state fraud proof for block at given height, or error if it's not found in Store.
+func (s *DefaultStore) LoadFraudProof(height uint64) (*types.StateFraudProof, error) {
+	blob, err := s.db.Get(s.ctx, ds.NewKey(getFraudProofKey(height)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fraud proof for height %v: %w", height, err)
+	}
+	proof := new(types.StateFraudProof)
+	err = proof.UnmarshalBinary(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fraud proof: %w", err)
+	}
+	return proof, nil
+}
+
+// SaveDALocation saves the location of block at given height on the DA layer, indexed in both directions.
+// Previous location of the block (e.g. before resubmission) is replaced.
+func (s *DefaultStore) SaveDALocation(height uint64, loc DALocation) error {
+	txn, err := s.db.NewTransaction(s.ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	defer txn.Discard(s.ctx)
+
+	blob, err := txn.Get(s.ctx, ds.NewKey(getDALocationKey(height)))
+	if err == nil {
+		prev, err := decodeDALocation(blob)
+		if err != nil {
+			return err
+		}
+		if prev == loc {
+			return nil
+		}
+		if err := txn.Delete(s.ctx, ds.NewKey(getDAHeightKey(prev))); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, ds.ErrNotFound) {
+		return fmt.Errorf("failed to load DA location for height %v: %w", height, err)
+	}
+
+	blob = make([]byte, 16)
+	binary.BigEndian.PutUint64(blob, loc.DAHeight)
+	binary.BigEndian.PutUint64(blob[8:], loc.Index)
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, height)
+	err = multierr.Append(
+		txn.Put(s.ctx, ds.NewKey(getDALocationKey(height)), blob),
+		txn.Put(s.ctx, ds.NewKey(getDAHeightKey(loc)), heightBytes),
+	)
+	if err != nil {
+		return err
+	}
+	return txn.Commit(s.ctx)
+}
+
+// LoadDALocation returns the location of block at given height on the DA layer, or error if it's not found in Store.
+func (s *DefaultStore) LoadDALocation(height uint64) (DALocation, error) {
+	blob, err := s.db.Get(s.ctx, ds.NewKey(getDALocationKey(height)))
+	if err != nil {
+		return DALocation{}, fmt.Errorf("failed to load DA location for height %v: %w", height, err)
+	}
+	return decodeDALocation(blob)
+}
+
+// LoadHeightsByDAHeight returns heights of blocks included in DA block at given height, ordered by blob index.
+func (s *DefaultStore) LoadHeightsByDAHeight(daHeight uint64) ([]uint64, error) {
+	results, err := s.db.Query(s.ctx, dsq.Query{Prefix: GenerateKey([]interface{}{daHeightPrefix, daHeight})})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DA height index: %w", err)
+	}
+	defer results.Close()
+
+	type entry struct{ index, height uint64 }
+	var entries []entry
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		key := ds.RawKey(result.Key)
+		index, err := strconv.ParseUint(key.BaseNamespace(), 10, 64)
+		if err != nil || key.Parent().BaseNamespace() != strconv.FormatUint(daHeight, 10) || len(result.Value) != 8 {
+			continue
+		}
+		entries = append(entries, entry{index, binary.BigEndian.Uint64(result.Value)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].index < entries[j].index })
+	heights := make([]uint64, len(entries))
+	for i, e := range entries {
+		heights[i] = e.height
+	}
+	return heights, nil
+}
+
+// PruneBlocks deletes blocks, commits and block responses below retainHeight from Store.
+// It returns the number of pruned blocks.
+func (s *DefaultStore) PruneBlocks(retainHeight uint64) (uint64, error) {
+	if height := s.Height(); retainHeight > height {
+		return 0, fmt.Errorf("retain height %d is greater than store height %d", retainHeight, height)
+	}
+	return s.deleteBlocks(func(height uint64) bool { return height < retainHeight })
+}
+
+// Rollback deletes blocks, commits, block responses and app hashes above given height from Store,
+// and sets the height saved in the Store to given height.
+func (s *DefaultStore) Rollback(height uint64) error {
+	storeHeight := s.Height()
+	if height > storeHeight {
+		return fmt.Errorf("rollback height %d is greater than store height %d", height, storeHeight)
+	}
+	if _, err := s.deleteBlocks(func(h uint64) bool { return h > height }); err != nil {
+		return err
+	}
+	for h := height + 1; h <= storeHeight; h++ {
+		if err := s.db.Delete(s.ctx, ds.NewKey(getAppHashKey(h))); err != nil {
+			return fmt.Errorf("failed to delete app hash of height %d: %w", h, err)
+		}
+	}
+	atomic.StoreUint64(&s.height, height)
+	return nil
+}
+
+// deleteBlocks deletes blocks, commits and block responses at heights accepted by the filter.
+// Deletions are committed in batches, to keep the size of transactions bounded.
+// It returns the number of deleted blocks.
+func (s *DefaultStore) deleteBlocks(filter func(height uint64) bool) (uint64, error) {
+	results, err := s.db.Query(s.ctx, dsq.Query{Prefix: GenerateKey([]interface{}{indexPrefix})})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query block index: %w", err)
+	}
+	defer results.Close()
+
+	var deleted, batched uint64
+	var txn ds.Txn
+	commit := func() error {
+		if txn == nil {
+			return nil
+		}
+		err := txn.Commit(s.ctx)
+		txn = nil
+		if err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		deleted += batched
+		batched = 0
+		return nil
+	}
+	for result := range results.Next() {
+		if result.Error != nil {
+			if txn != nil {
+				txn.Discard(s.ctx)
+			}
+			return deleted, result.Error
+		}
+		height, err := strconv.ParseUint(ds.RawKey(result.Key).BaseNamespace(), 10, 64)
+		if err != nil || !filter(height) {
+			continue
+		}
+		if txn == nil {
+			if txn, err = s.db.NewTransaction(s.ctx, false); err != nil {
+				return deleted, fmt.Errorf("failed to create a new batch for transaction: %w", err)
+			}
+		}
+		hash := types.Hash(result.Value)
+		err = multierr.Combine(
+			txn.Delete(s.ctx, ds.NewKey(getBlockKey(hash))),
+			txn.Delete(s.ctx, ds.NewKey(getCommitKey(hash))),
+			txn.Delete(s.ctx, ds.NewKey(getResponsesKey(height))),
+			txn.Delete(s.ctx, ds.NewKey(getIndexKey(height))),
+		)
+		if err == nil {
+			err = deleteDALocation(s.ctx, txn, height)
+		}
+		if err == nil {
+			// daily totals are kept, fees were paid regardless of pruning
+			err = txn.Delete(s.ctx, ds.NewKey(getDACostKey(height)))
+		}
+		if err != nil {
+			txn.Discard(s.ctx)
+			return deleted, err
+		}
+		if batched++; batched == deleteBatchSize {
+			if err := commit(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	return deleted, commit()
+}
+
+// deleteDALocation deletes the location of block at given height on the DA layer, if it's known.
+func deleteDALocation(ctx context.Context, txn ds.Txn, height uint64) error {
+	blob, err := txn.Get(ctx, ds.NewKey(getDALocationKey(height)))
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	loc, err := decodeDALocation(blob)
+	if err != nil {
+		return err
+	}
+	return multierr.Append(
+		txn.Delete(ctx, ds.NewKey(getDALocationKey(height))),
+		txn.Delete(ctx, ds.NewKey(getDAHeightKey(loc))),
+	)
+}
+
+func decodeDALocation(blob []byte) (DALocation, error) {
+	if len(blob) != 16 {
+		return DALocation{}, errors.New("invalid DA location length")
+	}
+	return DALocation{DAHeight: binary.BigEndian.Uint64(blob), Index: binary.BigEndian.Uint64(blob[8:])}, nil
+}
+
+// SaveAppHash saves the app hash of the application state after executing block at given height.
+func (s *DefaultStore) SaveAppHash(height uint64, appHash []byte) error {
+	return s.db.Put(s.ctx, ds.NewKey(getAppHashKey(height)), appHash)
+}
+
+// LoadAppHash returns the app hash of the application state after executing block at given height, or error if
+// it's not found in Store.
+func (s *DefaultStore) LoadAppHash(height uint64) ([]byte, error) {
+	appHash, err := s.db.Get(s.ctx, ds.NewKey(getAppHashKey(height)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load app hash for height %v: %w", height, err)
+	}
+	return appHash, nil
+}
+
+// SaveDACost saves the cost of DA submission attributed to block at given height, and adds it to the totals
+// of the (UTC) day of the submission.
+func (s *DefaultStore) SaveDACost(height uint64, cost DACost) error {
+	txn, err := s.db.NewTransaction(s.ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	defer txn.Discard(s.ctx)
+
+	daily, err := loadDailyDACost(s.ctx, txn, cost.Time)
+	if err != nil {
+		return err
+	}
+	daily.Fee += cost.Fee
+	daily.Blocks++
+	daily.BlobSize += cost.BlobSize
+
+	blob := make([]byte, 32)
+	binary.BigEndian.PutUint64(blob, cost.DAHeight)
+	binary.BigEndian.PutUint64(blob[8:], cost.Fee)
+	binary.BigEndian.PutUint64(blob[16:], cost.BlobSize)
+	binary.BigEndian.PutUint64(blob[24:], uint64(cost.Time.UnixNano()))
+	dailyBlob := make([]byte, 24)
+	binary.BigEndian.PutUint64(dailyBlob, daily.Fee)
+	binary.BigEndian.PutUint64(dailyBlob[8:], daily.Blocks)
+	binary.BigEndian.PutUint64(dailyBlob[16:], daily.BlobSize)
+	err = multierr.Append(
+		txn.Put(s.ctx, ds.NewKey(getDACostKey(height)), blob),
+		txn.Put(s.ctx, ds.NewKey(getDailyDACostKey(daily.Date)), dailyBlob),
+	)
+	if err != nil {
+		return err
+	}
+	return txn.Commit(s.ctx)
+}
+
+// LoadDACost returns the cost of DA submission attributed to block at given height, or error if it's not found in Store.
+func (s *DefaultStore) LoadDACost(height uint64) (DACost, error) {
+	blob, err := s.db.Get(s.ctx, ds.NewKey(getDACostKey(height)))
+	if err != nil {
+		return DACost{}, fmt.Errorf("failed to load DA cost for height %v: %w", height, err)
+	}
+	if len(blob) != 32 {
+		return DACost{}, errors.New("invalid DA cost length")
+	}
+	return DACost{
+		DAHeight: binary.BigEndian.Uint64(blob),
+		Fee:      binary.BigEndian.Uint64(blob[8:]),
+		BlobSize: binary.BigEndian.Uint64(blob[16:]),
+		Time:     time.Unix(0, int64(binary.BigEndian.Uint64(blob[24:]))).UTC(),
+	}, nil
+}
+
+// LoadDailyDACost returns total costs of DA submissions in the (UTC) day containing given time.
+// Totals of days without submissions are zero.
+func (s *DefaultStore) LoadDailyDACost(day time.Time) (DailyDACost, error) {
+	txn, err := s.db.NewTransaction(s.ctx, true)
+	if err != nil {
+		return DailyDACost{}, fmt.Errorf("failed to create a new batch for transaction: %w", err)
+	}
+	defer txn.Discard(s.ctx)
+	return loadDailyDACost(s.ctx, txn, day)
+}
+
+func loadDailyDACost(ctx context.Context, txn ds.Txn, day time.Time) (DailyDACost, error) {
+	daily := DailyDACost{Date: day.UTC().Format(time.DateOnly)}
+	blob, err := txn.Get(ctx, ds.NewKey(getDailyDACostKey(daily.Date)))
+	if errors.Is(err, ds.ErrNotFound) {
+		return daily, nil
+	}
+	if err != nil {
+		return daily, fmt.Errorf("failed to load DA cost for day %s: %w", daily.Date, err)
+	}
+	if len(blob) != 24 {
+		return daily, errors.New("invalid daily DA cost length")
+	}
+	daily.Fee = binary.BigEndian.Uint64(blob)
+	daily.Blocks = binary.BigEndian.Uint64(blob[8:])
+	daily.BlobSize = binary.BigEndian.Uint64(blob[16:])
+	return daily, nil
+}
+
 // loadHashFromIndex returns the hash of a block given its height
 func (s *DefaultStore) loadHashFromIndex(height uint64) (header.Hash, error) {
 	blob, err := s.db.Get(s.ctx, ds.NewKey(getIndexKey(height)))
@@ -270,3 +674,35 @@ func getResponsesKey(height uint64) string {
 func getValidatorsKey(height uint64) string {
 	return GenerateKey([]interface{}{validatorsPrefix, height})
 }
+
+func getNextValidatorsKey(height uint64) string {
+	return GenerateKey([]interface{}{nextValsPrefix, height})
+}
+
+func getConsensusParamsKey(height uint64) string {
+	return GenerateKey([]interface{}{paramsPrefix, height})
+}
+
+func getFraudProofKey(height uint64) string {
+	return GenerateKey([]interface{}{fraudProofPrefix, height})
+}
+
+func getDALocationKey(height uint64) string {
+	return GenerateKey([]interface{}{daLocationPrefix, height})
+}
+
+func getDAHeightKey(loc DALocation) string {
+	return GenerateKey([]interface{}{daHeightPrefix, loc.DAHeight, loc.Index})
+}
+
+func getDACostKey(height uint64) string {
+	return GenerateKey([]interface{}{daCostPrefix, height})
+}
+
+func getDailyDACostKey(date string) string {
+	return GenerateKey([]interface{}{dailyDACostPrefix, date})
+}
+
+func getAppHashKey(height uint64) string {
+	return GenerateKey([]interface{}{appHashPrefix, height})
+}
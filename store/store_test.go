@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	abcitypes "github.com/cometbft/cometbft/abci/types"
 	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
@@ -197,3 +198,225 @@ func TestBlockResponses(t *testing.T) {
 	assert.NotNil(resp)
 	assert.Equal(expected, resp)
 }
+
+func TestConsensusParams(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(ctx, kv)
+
+	params := cmproto.ConsensusParams{
+		Block:     &cmproto.BlockParams{MaxBytes: 12345, MaxGas: 678909876},
+		Validator: &cmproto.ValidatorParams{PubKeyTypes: []string{"ed25519"}},
+	}
+	require.NoError(s.SaveConsensusParams(3, params))
+
+	loaded, err := s.LoadConsensusParams(3)
+	require.NoError(err)
+	assert.Equal(params, loaded)
+
+	_, err = s.LoadConsensusParams(4)
+	assert.Error(err)
+}
+
+func TestFraudProofs(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(ctx, kv)
+
+	proof := &types.StateFraudProof{
+		BlockHeight:            7,
+		TxIndex:                1,
+		PreStateRoot:           []byte{1},
+		Tx:                     types.Tx{2},
+		CommittedPostStateRoot: []byte{3},
+		ExpectedPostStateRoot:  []byte{4},
+		Witnesses:              []types.StateWitness{{Key: []byte("key"), Value: []byte("value"), Proof: []byte("proof")}},
+	}
+	require.NoError(s.SaveFraudProof(proof))
+
+	loaded, err := s.LoadFraudProof(7)
+	require.NoError(err)
+	assert.Equal(proof, loaded)
+
+	loaded, err = s.LoadFraudProof(8)
+	assert.Error(err)
+	assert.Nil(loaded)
+}
+
+func TestPruneBlocksAndRollback(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(ctx, kv)
+
+	for h := uint64(1); h <= 10; h++ {
+		require.NoError(s.SaveBlock(types.GetRandomBlock(h, 1), &types.Commit{}))
+		require.NoError(s.SaveBlockResponses(h, &cmstate.ABCIResponses{}))
+		require.NoError(s.SaveAppHash(h, []byte{byte(h)}))
+		s.SetHeight(h)
+	}
+
+	assert.Equal(uint64(1), s.EarliestHeight())
+	_, err := s.PruneBlocks(11)
+	assert.Error(err)
+
+	pruned, err := s.PruneBlocks(4)
+	require.NoError(err)
+	assert.Equal(uint64(3), pruned)
+	assert.Equal(uint64(4), s.EarliestHeight())
+	for h := uint64(1); h < 4; h++ {
+		_, err := s.LoadBlock(h)
+		assert.Error(err)
+		_, err = s.LoadBlockResponses(h)
+		assert.Error(err)
+	}
+	_, err = s.LoadBlock(4)
+	assert.NoError(err)
+	// app hashes are kept for historical queries
+	appHash, err := s.LoadAppHash(1)
+	require.NoError(err)
+	assert.Equal([]byte{1}, appHash)
+
+	// pruning again is a no-op
+	pruned, err = s.PruneBlocks(4)
+	require.NoError(err)
+	assert.Zero(pruned)
+
+	assert.Error(s.Rollback(11))
+	require.NoError(s.Rollback(8))
+	assert.Equal(uint64(8), s.Height())
+	_, err = s.LoadBlock(8)
+	assert.NoError(err)
+	for h := uint64(9); h <= 10; h++ {
+		_, err := s.LoadBlock(h)
+		assert.Error(err)
+		_, err = s.LoadCommit(h)
+		assert.Error(err)
+		_, err = s.LoadAppHash(h)
+		assert.Error(err)
+	}
+	appHash, err = s.LoadAppHash(8)
+	require.NoError(err)
+	assert.Equal([]byte{8}, appHash)
+}
+
+func TestDALocations(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(ctx, kv)
+
+	for h := uint64(1); h <= 4; h++ {
+		require.NoError(s.SaveBlock(types.GetRandomBlock(h, 1), &types.Commit{}))
+		s.SetHeight(h)
+	}
+	require.NoError(s.SaveDALocation(1, DALocation{DAHeight: 10, Index: 0}))
+	require.NoError(s.SaveDALocation(3, DALocation{DAHeight: 10, Index: 2}))
+	require.NoError(s.SaveDALocation(2, DALocation{DAHeight: 10, Index: 1}))
+	require.NoError(s.SaveDALocation(4, DALocation{DAHeight: 100, Index: 0}))
+
+	loc, err := s.LoadDALocation(3)
+	require.NoError(err)
+	assert.Equal(DALocation{DAHeight: 10, Index: 2}, loc)
+	_, err = s.LoadDALocation(5)
+	assert.Error(err)
+
+	heights, err := s.LoadHeightsByDAHeight(10)
+	require.NoError(err)
+	assert.Equal([]uint64{1, 2, 3}, heights)
+	heights, err = s.LoadHeightsByDAHeight(1)
+	require.NoError(err)
+	assert.Empty(heights)
+
+	// resubmitted block is moved to the new DA height
+	require.NoError(s.SaveDALocation(3, DALocation{DAHeight: 11, Index: 0}))
+	heights, err = s.LoadHeightsByDAHeight(10)
+	require.NoError(err)
+	assert.Equal([]uint64{1, 2}, heights)
+	heights, err = s.LoadHeightsByDAHeight(11)
+	require.NoError(err)
+	assert.Equal([]uint64{3}, heights)
+
+	// locations of pruned and rolled back blocks are deleted
+	_, err = s.PruneBlocks(2)
+	require.NoError(err)
+	require.NoError(s.Rollback(3))
+	_, err = s.LoadDALocation(1)
+	assert.Error(err)
+	heights, err = s.LoadHeightsByDAHeight(10)
+	require.NoError(err)
+	assert.Equal([]uint64{2}, heights)
+	heights, err = s.LoadHeightsByDAHeight(100)
+	require.NoError(err)
+	assert.Empty(heights)
+}
+
+func TestDACosts(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, _ := NewDefaultInMemoryKVStore()
+	s := New(ctx, kv)
+
+	for h := uint64(1); h <= 3; h++ {
+		require.NoError(s.SaveBlock(types.GetRandomBlock(h, 1), &types.Commit{}))
+		s.SetHeight(h)
+	}
+	day := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	costs := []DACost{
+		{DAHeight: 10, Fee: 100, BlobSize: 1000, Time: day},
+		{DAHeight: 10, Fee: 200, BlobSize: 2000, Time: day},
+		{DAHeight: 12, Fee: 50, BlobSize: 500, Time: day.Add(2 * time.Hour)},
+	}
+	for i, cost := range costs {
+		require.NoError(s.SaveDACost(uint64(i+1), cost))
+	}
+
+	cost, err := s.LoadDACost(2)
+	require.NoError(err)
+	assert.Equal(costs[1], cost)
+	_, err = s.LoadDACost(4)
+	assert.Error(err)
+
+	daily, err := s.LoadDailyDACost(day.Add(-time.Hour))
+	require.NoError(err)
+	assert.Equal(DailyDACost{Date: "2024-03-01", Fee: 300, Blocks: 2, BlobSize: 3000}, daily)
+	daily, err = s.LoadDailyDACost(day.Add(2 * time.Hour))
+	require.NoError(err)
+	assert.Equal(DailyDACost{Date: "2024-03-02", Fee: 50, Blocks: 1, BlobSize: 500}, daily)
+	daily, err = s.LoadDailyDACost(day.Add(48 * time.Hour))
+	require.NoError(err)
+	assert.Equal(DailyDACost{Date: "2024-03-03"}, daily)
+
+	// costs of pruned blocks are deleted, daily totals are kept
+	_, err = s.PruneBlocks(2)
+	require.NoError(err)
+	_, err = s.LoadDACost(1)
+	assert.Error(err)
+	daily, err = s.LoadDailyDACost(day)
+	require.NoError(err)
+	assert.Equal(uint64(300), daily.Fee)
+}
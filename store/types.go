@@ -1,7 +1,10 @@
 package store
 
 import (
+	"time"
+
 	cmstate "github.com/cometbft/cometbft/proto/tendermint/state"
+	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	cmtypes "github.com/cometbft/cometbft/types"
 
 	"github.com/rollkit/rollkit/types"
@@ -11,6 +14,8 @@ import (
 type Store interface {
 	// Height returns height of the highest block in store.
 	Height() uint64
+	// EarliestHeight returns height of the lowest block in store (blocks below it were pruned), or 0 if store is empty.
+	EarliestHeight() uint64
 
 	// SetHeight sets the height saved in the Store if it is higher than the existing height.
 	SetHeight(height uint64)
@@ -43,4 +48,83 @@ type Store interface {
 	SaveValidators(height uint64, validatorSet *cmtypes.ValidatorSet) error
 
 	LoadValidators(height uint64) (*cmtypes.ValidatorSet, error)
+
+	// SaveNextValidators saves the next validator set of the state that block at given height was created on.
+	SaveNextValidators(height uint64, validatorSet *cmtypes.ValidatorSet) error
+	// LoadNextValidators returns the next validator set of the state that block at given height was created on,
+	// or error if it's not found in Store.
+	LoadNextValidators(height uint64) (*cmtypes.ValidatorSet, error)
+
+	// SaveConsensusParams saves consensus parameters in effect for block at given height.
+	SaveConsensusParams(height uint64, params cmproto.ConsensusParams) error
+	// LoadConsensusParams returns consensus parameters in effect for block at given height, or error if they're not found in Store.
+	LoadConsensusParams(height uint64) (cmproto.ConsensusParams, error)
+
+	// SaveFraudProof saves state fraud proof for the block at height given by the proof.
+	SaveFraudProof(proof *types.StateFraudProof) error
+	// LoadFraudProof returns state fraud proof for block at given height, or error if it's not found in Store.
+	LoadFraudProof(height uint64) (*types.StateFraudProof, error)
+
+	// SaveDALocation saves the location of block at given height on the DA layer, indexed in both directions.
+	// Previous location of the block (e.g. before resubmission) is replaced.
+	SaveDALocation(height uint64, loc DALocation) error
+	// LoadDALocation returns the location of block at given height on the DA layer, or error if it's not found in Store.
+	LoadDALocation(height uint64) (DALocation, error)
+	// LoadHeightsByDAHeight returns heights of blocks included in DA block at given height, ordered by blob index.
+	LoadHeightsByDAHeight(daHeight uint64) ([]uint64, error)
+
+	// SaveAppHash saves the app hash of the application state after executing block at given height. App hashes
+	// are kept when blocks are pruned, so historical state of the application can still be verified.
+	SaveAppHash(height uint64, appHash []byte) error
+	// LoadAppHash returns the app hash of the application state after executing block at given height, or error
+	// if it's not found in Store.
+	LoadAppHash(height uint64) ([]byte, error)
+
+	// SaveDACost saves the cost of DA submission attributed to block at given height, and adds it to the totals
+	// of the (UTC) day of the submission.
+	SaveDACost(height uint64, cost DACost) error
+	// LoadDACost returns the cost of DA submission attributed to block at given height, or error if it's not found in Store.
+	LoadDACost(height uint64) (DACost, error)
+	// LoadDailyDACost returns total costs of DA submissions in the (UTC) day containing given time.
+	LoadDailyDACost(day time.Time) (DailyDACost, error)
+
+	// PruneBlocks deletes blocks, commits and block responses below retainHeight from Store.
+	// It returns the number of pruned blocks.
+	PruneBlocks(retainHeight uint64) (uint64, error)
+	// Rollback deletes blocks, commits, block responses and app hashes above given height from Store,
+	// and sets the height saved in the Store to given height.
+	Rollback(height uint64) error
+}
+
+// DALocation is the location of a block on the DA layer.
+type DALocation struct {
+	// DAHeight is the height of DA block containing the block.
+	DAHeight uint64 `json:"da_height"`
+	// Index is the index of the blob containing the block, among blobs of the chain in the DA block.
+	Index uint64 `json:"index"`
+}
+
+// DACost is the cost of submission of a block to the DA layer. Fee of a submission of multiple blocks is split
+// between blocks proportionally to their size.
+type DACost struct {
+	// DAHeight is the height of DA block containing the block.
+	DAHeight uint64 `json:"da_height"`
+	// Fee is the share of the submission fee attributed to the block, in the smallest unit of the DA layer token.
+	Fee uint64 `json:"fee"`
+	// BlobSize is the size of the block blob in bytes.
+	BlobSize uint64 `json:"blob_size"`
+	// Time is the time of the submission.
+	Time time.Time `json:"time"`
+}
+
+// DailyDACost are total costs of DA submissions in a (UTC) day.
+type DailyDACost struct {
+	// Date is the day of submissions, formatted as 2006-01-02.
+	Date string `json:"date"`
+	// Fee is the total fee paid for submissions, in the smallest unit of the DA layer token.
+	Fee uint64 `json:"fee"`
+	// Blocks is the number of submitted blocks.
+	Blocks uint64 `json:"blocks"`
+	// BlobSize is the total size of submitted blobs in bytes.
+	BlobSize uint64 `json:"blob_size"`
 }
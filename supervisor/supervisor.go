@@ -0,0 +1,388 @@
+// Package supervisor starts and stops services of a node in order of their dependencies, restarts failed services
+// according to their restart policies, and reports lifecycle events of services. It allows embedding Rollkit
+// as a library: applications can run their own services (e.g. RPC servers) next to services of the node,
+// and observe their state.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrInvalidService is returned when service can't be added to the supervisor.
+	ErrInvalidService = errors.New("invalid service")
+	// ErrUnknownDependency is returned when service depends on a service that was not added to the supervisor.
+	ErrUnknownDependency = errors.New("unknown dependency")
+	// ErrDependencyCycle is returned when dependencies of services form a cycle.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrAlreadyStarted is returned when supervisor is started twice, or a service is added after start.
+	ErrAlreadyStarted = errors.New("supervisor already started")
+	// ErrServiceFailed is returned when service failed and exhausted its restart policy.
+	ErrServiceFailed = errors.New("service failed")
+)
+
+// State is the lifecycle state of a service.
+type State int
+
+const (
+	// StateIdle is the state of service that was not started yet.
+	StateIdle State = iota
+	// StateStarting is the state of service that is being started.
+	StateStarting
+	// StateRunning is the state of started service.
+	StateRunning
+	// StateRestarting is the state of service waiting for restart after a failure.
+	StateRestarting
+	// StateStopping is the state of service that is being stopped.
+	StateStopping
+	// StateStopped is the state of stopped service.
+	StateStopped
+	// StateFailed is the state of service that failed to start, or failed and exhausted its restart policy.
+	StateFailed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateRestarting:
+		return "restarting"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Event is a lifecycle event of a service: transition to a new state.
+type Event struct {
+	Service string
+	State   State
+	// Err is the error that caused the transition (for StateRestarting and StateFailed), or the error
+	// returned when stopping the service (for StateStopped).
+	Err  error
+	Time time.Time
+}
+
+// RestartPolicy defines how Run function of a service is restarted after it fails (returns an error or panics).
+// Zero value disables restarts.
+type RestartPolicy struct {
+	// MaxRestarts is the maximal number of restarts. Negative value means no limit.
+	MaxRestarts int
+	// Backoff is the delay before every restart.
+	Backoff time.Duration
+}
+
+// Service is a named component supervised by Supervisor. All functions are optional.
+type Service struct {
+	Name string
+	// DependsOn are names of services that have to be started before this service, and stopped after it.
+	DependsOn []string
+	// Start starts the service. Context is canceled when the supervisor is stopped.
+	Start func(ctx context.Context) error
+	// Run is the main loop of the service, started after Start. It should return when context is canceled.
+	// Returning an error (or panicking) is a failure, handled according to Restart policy. Returning nil
+	// finishes the loop.
+	Run func(ctx context.Context) error
+	// Stop stops the service. It's called after Run loops of all services returned.
+	Stop func() error
+	// Restart is the restart policy of Run function. If restarts are exhausted, the service fails,
+	// and Run loops of all services are stopped.
+	Restart RestartPolicy
+}
+
+// FromService returns service starting and stopping CometBFT service, e.g. RPC server.
+func FromService(name string, svc service.Service, dependsOn ...string) Service {
+	return Service{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start:     func(context.Context) error { return svc.Start() },
+		Stop:      svc.Stop,
+	}
+}
+
+type entry struct {
+	Service
+	state    State
+	started  bool
+	restarts int
+}
+
+// Supervisor manages lifecycle of services.
+type Supervisor struct {
+	logger log.Logger
+
+	mtx      sync.Mutex
+	services []*entry
+	byName   map[string]*entry
+	order    []*entry
+	subs     []chan Event
+	started  bool
+	stopped  bool
+	err      error
+	failed   chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// New creates new Supervisor without services.
+func New(logger log.Logger) *Supervisor {
+	return &Supervisor{
+		logger: logger,
+		byName: make(map[string]*entry),
+		failed: make(chan struct{}),
+	}
+}
+
+// Add adds the service to the supervisor. Services have to be added before the supervisor is started.
+func (s *Supervisor) Add(svc Service) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.started {
+		return ErrAlreadyStarted
+	}
+	if svc.Name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidService)
+	}
+	if _, ok := s.byName[svc.Name]; ok {
+		return fmt.Errorf("%w: duplicate name %s", ErrInvalidService, svc.Name)
+	}
+	e := &entry{Service: svc}
+	s.services = append(s.services, e)
+	s.byName[svc.Name] = e
+	return nil
+}
+
+// Subscribe returns channel of lifecycle events of all services. Events are dropped if the buffer of the channel
+// is full. Channel is closed when the supervisor is stopped.
+func (s *Supervisor) Subscribe(buffer int) <-chan Event {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	ch := make(chan Event, buffer)
+	if s.stopped {
+		close(ch)
+		return ch
+	}
+	s.subs = append(s.subs, ch)
+	return ch
+}
+
+// States returns current states of all services.
+func (s *Supervisor) States() map[string]State {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	states := make(map[string]State, len(s.services))
+	for _, e := range s.services {
+		states[e.Name] = e.state
+	}
+	return states
+}
+
+// Failed returns channel closed when a service fails and exhausts its restart policy.
+func (s *Supervisor) Failed() <-chan struct{} {
+	return s.failed
+}
+
+// Err returns the failure of the first service that exhausted its restart policy, or nil.
+func (s *Supervisor) Err() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.err
+}
+
+// Start starts services in order of their dependencies, and then their Run loops. If any service fails to start,
+// services started so far are stopped (in reverse order) and the error is returned.
+func (s *Supervisor) Start(ctx context.Context) error {
+	s.mtx.Lock()
+	if s.started {
+		s.mtx.Unlock()
+		return ErrAlreadyStarted
+	}
+	order, err := sortServices(s.services, s.byName)
+	if err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+	s.order = order
+	s.started = true
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.mtx.Unlock()
+
+	for _, e := range order {
+		s.setState(e, StateStarting, nil)
+		if e.Start != nil {
+			if err := e.Start(ctx); err != nil {
+				s.setState(e, StateFailed, err)
+				return multierr.Append(fmt.Errorf("failed to start %s: %w", e.Name, err), s.Stop())
+			}
+		}
+		s.mtx.Lock()
+		e.started = true
+		s.mtx.Unlock()
+		s.setState(e, StateRunning, nil)
+		if e.Run != nil {
+			s.wg.Add(1)
+			go s.run(ctx, e)
+		}
+	}
+	return nil
+}
+
+// Stop stops Run loops of all services, waits for them to return, and then stops services in reverse order
+// of their dependencies. Errors of all services are returned.
+func (s *Supervisor) Stop() error {
+	s.mtx.Lock()
+	if !s.started {
+		s.mtx.Unlock()
+		return nil
+	}
+	s.mtx.Unlock()
+
+	s.stopOnce.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+
+		for i := len(s.order) - 1; i >= 0; i-- {
+			e := s.order[i]
+			if !e.started {
+				continue
+			}
+			s.setState(e, StateStopping, nil)
+			var err error
+			if e.Stop != nil {
+				err = e.Stop()
+			}
+			if err != nil {
+				s.stopErr = multierr.Append(s.stopErr, fmt.Errorf("failed to stop %s: %w", e.Name, err))
+			}
+			s.setState(e, StateStopped, err)
+		}
+
+		s.mtx.Lock()
+		s.stopped = true
+		for _, ch := range s.subs {
+			close(ch)
+		}
+		s.subs = nil
+		s.mtx.Unlock()
+	})
+	return s.stopErr
+}
+
+// run executes Run loop of the service, restarting it after failures according to its restart policy.
+func (s *Supervisor) run(ctx context.Context, e *entry) {
+	defer s.wg.Done()
+	for {
+		err := runSafely(ctx, e.Run)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if e.Restart.MaxRestarts >= 0 && e.restarts >= e.Restart.MaxRestarts {
+			s.fail(e, err)
+			return
+		}
+		e.restarts++
+		s.logger.Error("service failed, restarting", "service", e.Name, "restarts", e.restarts, "error", err)
+		s.setState(e, StateRestarting, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.Restart.Backoff):
+		}
+		s.setState(e, StateRunning, nil)
+	}
+}
+
+// fail marks the service as failed, and stops Run loops of all services.
+func (s *Supervisor) fail(e *entry, err error) {
+	s.logger.Error("service failed", "service", e.Name, "error", err)
+	s.setState(e, StateFailed, err)
+	s.mtx.Lock()
+	if s.err == nil {
+		s.err = fmt.Errorf("%w: %s: %w", ErrServiceFailed, e.Name, err)
+		close(s.failed)
+	}
+	s.mtx.Unlock()
+	s.cancel()
+}
+
+func (s *Supervisor) setState(e *entry, state State, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	e.state = state
+	ev := Event{Service: e.Name, State: state, Err: err, Time: time.Now()}
+	for _, ch := range s.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+	s.logger.Debug("service state changed", "service", e.Name, "state", state)
+}
+
+// runSafely calls the function, converting panic into an error.
+func runSafely(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+// sortServices returns services ordered by their dependencies. Order of registration is preserved
+// for independent services.
+func sortServices(services []*entry, byName map[string]*entry) ([]*entry, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	marks := make(map[string]int, len(services))
+	order := make([]*entry, 0, len(services))
+	var visit func(e *entry) error
+	visit = func(e *entry) error {
+		switch marks[e.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, e.Name)
+		}
+		marks[e.Name] = visiting
+		for _, name := range e.DependsOn {
+			dep, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("%w: %s depends on %s", ErrUnknownDependency, e.Name, name)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		marks[e.Name] = visited
+		order = append(order, e)
+		return nil
+	}
+	for _, e := range services {
+		if err := visit(e); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
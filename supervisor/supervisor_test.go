@@ -0,0 +1,160 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records calls of service functions, in order.
+type recorder struct {
+	mtx   sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(call string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) service(name string, dependsOn ...string) Service {
+	return Service{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(context.Context) error {
+			r.record("start " + name)
+			return nil
+		},
+		Stop: func() error {
+			r.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+func TestOrder(t *testing.T) {
+	var r recorder
+	s := New(log.TestingLogger())
+	require.NoError(t, s.Add(r.service("rpc", "block")))
+	require.NoError(t, s.Add(r.service("p2p")))
+	require.NoError(t, s.Add(r.service("block", "p2p", "da")))
+	require.NoError(t, s.Add(r.service("da")))
+	events := s.Subscribe(100)
+
+	require.NoError(t, s.Start(context.Background()))
+	assert.ErrorIs(t, s.Add(r.service("late")), ErrAlreadyStarted)
+	assert.Equal(t, StateRunning, s.States()["rpc"])
+	require.NoError(t, s.Stop())
+
+	assert.Equal(t, []string{
+		"start p2p", "start da", "start block", "start rpc",
+		"stop rpc", "stop block", "stop da", "stop p2p",
+	}, r.calls)
+
+	var states []State
+	for ev := range events {
+		if ev.Service == "p2p" {
+			states = append(states, ev.State)
+		}
+	}
+	assert.Equal(t, []State{StateStarting, StateRunning, StateStopping, StateStopped}, states)
+}
+
+func TestInvalidServices(t *testing.T) {
+	s := New(log.TestingLogger())
+	require.NoError(t, s.Add(Service{Name: "a"}))
+	assert.ErrorIs(t, s.Add(Service{Name: "a"}), ErrInvalidService)
+	assert.ErrorIs(t, s.Add(Service{}), ErrInvalidService)
+
+	s = New(log.TestingLogger())
+	require.NoError(t, s.Add(Service{Name: "a", DependsOn: []string{"b"}}))
+	assert.ErrorIs(t, s.Start(context.Background()), ErrUnknownDependency)
+
+	s = New(log.TestingLogger())
+	require.NoError(t, s.Add(Service{Name: "a", DependsOn: []string{"b"}}))
+	require.NoError(t, s.Add(Service{Name: "b", DependsOn: []string{"a"}}))
+	assert.ErrorIs(t, s.Start(context.Background()), ErrDependencyCycle)
+}
+
+func TestStartFailure(t *testing.T) {
+	var r recorder
+	errStart := errors.New("start failed")
+	s := New(log.TestingLogger())
+	require.NoError(t, s.Add(r.service("p2p")))
+	failing := r.service("da")
+	failing.Start = func(context.Context) error { return errStart }
+	require.NoError(t, s.Add(failing))
+	require.NoError(t, s.Add(r.service("block", "da")))
+
+	assert.ErrorIs(t, s.Start(context.Background()), errStart)
+	assert.Equal(t, []string{"start p2p", "stop p2p"}, r.calls)
+	assert.Equal(t, StateFailed, s.States()["da"])
+	assert.Equal(t, StateIdle, s.States()["block"])
+}
+
+func TestRestart(t *testing.T) {
+	s := New(log.TestingLogger())
+	var mtx sync.Mutex
+	runs := 0
+	require.NoError(t, s.Add(Service{
+		Name: "loop",
+		Run: func(ctx context.Context) error {
+			mtx.Lock()
+			runs++
+			n := runs
+			mtx.Unlock()
+			if n < 3 {
+				panic("loop failed")
+			}
+			<-ctx.Done()
+			return nil
+		},
+		Restart: RestartPolicy{MaxRestarts: 2, Backoff: time.Millisecond},
+	}))
+	require.NoError(t, s.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return runs == 3
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, s.Err())
+	require.NoError(t, s.Stop())
+}
+
+func TestFailure(t *testing.T) {
+	errLoop := errors.New("loop failed")
+	s := New(log.TestingLogger())
+	require.NoError(t, s.Add(Service{
+		Name:    "failing",
+		Run:     func(context.Context) error { return errLoop },
+		Restart: RestartPolicy{MaxRestarts: 1},
+	}))
+	stopped := make(chan struct{})
+	require.NoError(t, s.Add(Service{
+		Name: "other",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			close(stopped)
+			return nil
+		},
+	}))
+	require.NoError(t, s.Start(context.Background()))
+
+	select {
+	case <-s.Failed():
+	case <-time.After(time.Second):
+		t.Fatal("service didn't fail")
+	}
+	<-stopped
+	assert.ErrorIs(t, s.Err(), ErrServiceFailed)
+	assert.ErrorIs(t, s.Err(), errLoop)
+	assert.Equal(t, StateFailed, s.States()["failing"])
+	require.NoError(t, s.Stop())
+}
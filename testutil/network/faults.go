@@ -0,0 +1,122 @@
+package network
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/rollkit/rollkit/da"
+	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/types"
+)
+
+// FaultyDALC is the mock data availability layer client shared by nodes of the network,
+// that can simulate outages of the data availability layer.
+type FaultyDALC struct {
+	*mockda.DataAvailabilityLayerClient
+
+	submitFailing   atomic.Bool
+	retrieveFailing atomic.Bool
+}
+
+var _ da.DataAvailabilityLayerClient = &FaultyDALC{}
+var _ da.BlockRetriever = &FaultyDALC{}
+
+// SetSubmitFailing makes block submissions fail, until it's called with false.
+func (d *FaultyDALC) SetSubmitFailing(failing bool) {
+	d.submitFailing.Store(failing)
+}
+
+// SetRetrieveFailing makes block retrievals fail, until it's called with false.
+func (d *FaultyDALC) SetRetrieveFailing(failing bool) {
+	d.retrieveFailing.Store(failing)
+}
+
+// SetOutage makes both submissions and retrievals fail, until it's called with false.
+func (d *FaultyDALC) SetOutage(outage bool) {
+	d.SetSubmitFailing(outage)
+	d.SetRetrieveFailing(outage)
+}
+
+// SubmitBlocks submits blocks to the mock DA layer, unless submissions are failing.
+func (d *FaultyDALC) SubmitBlocks(ctx context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
+	if d.submitFailing.Load() {
+		return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: "simulated DA layer outage"}}
+	}
+	return d.DataAvailabilityLayerClient.SubmitBlocks(ctx, blocks)
+}
+
+// RetrieveBlocks retrieves blocks from the mock DA layer, unless retrievals are failing.
+func (d *FaultyDALC) RetrieveBlocks(ctx context.Context, daHeight uint64) da.ResultRetrieveBlocks {
+	if d.retrieveFailing.Load() {
+		return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: "simulated DA layer outage"}}
+	}
+	return d.DataAvailabilityLayerClient.RetrieveBlocks(ctx, daHeight)
+}
+
+// Partition disconnects i-th and j-th node, and prevents them from connecting again until Heal is called.
+func (n *Network) Partition(i, j int) error {
+	if err := n.checkNodes(i, j); err != nil {
+		return err
+	}
+	a, b := n.peers[i], n.peers[j]
+	if err := n.mnet.UnlinkPeers(a, b); err != nil {
+		return err
+	}
+	return n.mnet.DisconnectPeers(a, b)
+}
+
+// Heal reconnects i-th and j-th node, disconnected by Partition.
+func (n *Network) Heal(i, j int) error {
+	if err := n.checkNodes(i, j); err != nil {
+		return err
+	}
+	a, b := n.peers[i], n.peers[j]
+	if _, err := n.mnet.LinkPeers(a, b); err != nil {
+		return err
+	}
+	_, err := n.mnet.ConnectPeers(a, b)
+	return err
+}
+
+// Isolate disconnects i-th node from all other nodes.
+func (n *Network) Isolate(i int) error {
+	for j := range n.nodes {
+		if j == i {
+			continue
+		}
+		if err := n.Partition(i, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rejoin reconnects i-th node with all other nodes, after Isolate.
+func (n *Network) Rejoin(i int) error {
+	for j := range n.nodes {
+		if j == i {
+			continue
+		}
+		if err := n.Heal(i, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StopNode stops i-th node, simulating its crash. Stopped node can't be restarted.
+func (n *Network) StopNode(i int) error {
+	if err := n.checkNodes(i); err != nil {
+		return err
+	}
+	return n.nodes[i].Stop()
+}
+
+func (n *Network) checkNodes(indices ...int) error {
+	for _, i := range indices {
+		if i < 0 || i >= len(n.nodes) {
+			return ErrInvalidNode
+		}
+	}
+	return nil
+}
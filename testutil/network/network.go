@@ -0,0 +1,269 @@
+// Package network runs in-process test networks of Rollkit nodes: an aggregator and full nodes syncing from it,
+// sharing a mock data availability layer and connected with an in-memory P2P network.
+//
+// It's intended for integration tests of Rollkit and applications built with it.
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/libs/log"
+	corep2p "github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/config"
+	mockda "github.com/rollkit/rollkit/da/mock"
+	"github.com/rollkit/rollkit/node"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+// waitInterval defines how often conditions are checked by Wait* methods.
+const waitInterval = 100 * time.Millisecond
+
+// ErrInvalidNode is returned when node index is out of range.
+var ErrInvalidNode = errors.New("invalid node index")
+
+// Config configures the test network.
+type Config struct {
+	// ChainID of the network.
+	ChainID string
+	// NumFullNodes is the number of full nodes syncing from the aggregator.
+	NumFullNodes int
+	// BlockManagerConfig is used by all nodes of the network.
+	BlockManagerConfig config.BlockManagerConfig
+	// NewApp creates the ABCI application of i-th node. Aggregator is the node 0.
+	NewApp func(i int) abci.Application
+	// Logger is used by all nodes, with "node" key set to node index.
+	Logger log.Logger
+	// ConfigureNode is called with every node before the network is started, if set (e.g. to make the aggregator
+	// misbehave with SetByzantineBehavior, in tests built with the byzantine tag).
+	ConfigureNode func(i int, n *node.FullNode)
+}
+
+// DefaultConfig returns configuration of the network with the aggregator and a single full node running
+// the kvstore application.
+func DefaultConfig() Config {
+	return Config{
+		ChainID:      "test",
+		NumFullNodes: 1,
+		BlockManagerConfig: config.BlockManagerConfig{
+			// blocks must be at least 1 sec apart for adjacent headers to get verified correctly
+			BlockTime:   1 * time.Second,
+			DABlockTime: 100 * time.Millisecond,
+			NamespaceID: types.NamespaceID{8, 7, 6, 5, 4, 3, 2, 1},
+		},
+		NewApp: func(int) abci.Application {
+			return kvstore.NewApplication()
+		},
+		Logger: log.NewNopLogger(),
+	}
+}
+
+// Network is an in-process network of Rollkit nodes.
+type Network struct {
+	mnet  mocknet.Mocknet
+	peers []peer.ID
+	dalc  *FaultyDALC
+	nodes []*node.FullNode
+	apps  []abci.Application
+}
+
+// New creates the test network. Nodes are not started.
+func New(ctx context.Context, conf Config) (*Network, error) {
+	if conf.NumFullNodes < 0 {
+		return nil, fmt.Errorf("invalid number of full nodes: %d", conf.NumFullNodes)
+	}
+	if conf.NewApp == nil {
+		conf.NewApp = DefaultConfig().NewApp
+	}
+	if conf.Logger == nil {
+		conf.Logger = log.NewNopLogger()
+	}
+
+	dalcKV, err := store.NewDefaultInMemoryKVStore()
+	if err != nil {
+		return nil, err
+	}
+	mock := &mockda.DataAvailabilityLayerClient{}
+	if err := mock.Init(conf.BlockManagerConfig.NamespaceID, []byte(conf.BlockManagerConfig.DABlockTime.String()), dalcKV, conf.Logger.With("module", "dalc")); err != nil {
+		return nil, err
+	}
+
+	validatorKey := ed25519.GenPrivKey()
+	signingKey, err := node.GetNodeKey(&corep2p.NodeKey{PrivKey: validatorKey})
+	if err != nil {
+		return nil, err
+	}
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:       conf.ChainID,
+		InitialHeight: 1,
+		Validators: []cmtypes.GenesisValidator{{
+			Address: validatorKey.PubKey().Address(),
+			PubKey:  validatorKey.PubKey(),
+			Power:   100,
+			Name:    "aggregator",
+		}},
+	}
+	// genesis is completed (e.g. with genesis time) before nodes are created, so all of them compute the same hash
+	if err := genesis.ValidateAndComplete(); err != nil {
+		return nil, err
+	}
+
+	n := &Network{
+		mnet: mocknet.New(),
+		dalc: &FaultyDALC{DataAvailabilityLayerClient: mock},
+	}
+	num := conf.NumFullNodes + 1
+	for i := 0; i < num; i++ {
+		if _, err := n.mnet.GenPeer(); err != nil {
+			return nil, err
+		}
+	}
+	if err := n.mnet.LinkAll(); err != nil {
+		return nil, err
+	}
+
+	hosts := n.mnet.Hosts()
+	// full nodes bootstrap from the aggregator
+	seed := fmt.Sprintf("%s/p2p/%s", hosts[0].Addrs()[0], hosts[0].ID())
+	for i := 0; i < num; i++ {
+		nodeConfig := config.NodeConfig{
+			DALayer:            "newda",
+			Aggregator:         i == 0,
+			BlockManagerConfig: conf.BlockManagerConfig,
+		}
+		if i > 0 {
+			nodeConfig.P2P.Seeds = seed
+		}
+		app := conf.NewApp(i)
+		p2pKey := hosts[i].Peerstore().PrivKey(hosts[i].ID())
+		nd, err := node.NewNode(ctx, nodeConfig, p2pKey, signingKey, proxy.NewLocalClientCreator(app), genesis,
+			conf.Logger.With("node", i))
+		if err != nil {
+			return nil, fmt.Errorf("error while creating node %d: %w", i, err)
+		}
+		fullNode := nd.(*node.FullNode)
+		fullNode.SetDALC(n.dalc)
+		fullNode.SetP2PHost(hosts[i])
+		if conf.ConfigureNode != nil {
+			conf.ConfigureNode(i, fullNode)
+		}
+		n.nodes = append(n.nodes, fullNode)
+		n.peers = append(n.peers, hosts[i].ID())
+		n.apps = append(n.apps, app)
+	}
+	return n, nil
+}
+
+// Start starts the DA layer and all nodes. Full nodes are started after the aggregator produces the first block,
+// as they need it to initialize header exchange.
+func (n *Network) Start(ctx context.Context) error {
+	if err := n.dalc.Start(); err != nil {
+		return err
+	}
+	if err := n.nodes[0].Start(); err != nil {
+		return fmt.Errorf("error while starting aggregator: %w", err)
+	}
+	if err := n.WaitForHeight(ctx, 0, 1); err != nil {
+		return err
+	}
+	for i := 1; i < len(n.nodes); i++ {
+		if err := n.nodes[i].Start(); err != nil {
+			return fmt.Errorf("error while starting node %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Stop stops all running nodes and the DA layer.
+func (n *Network) Stop() error {
+	var err error
+	for i := len(n.nodes) - 1; i >= 0; i-- {
+		if n.nodes[i].IsRunning() {
+			err = multierr.Append(err, n.nodes[i].Stop())
+		}
+	}
+	err = multierr.Append(err, n.dalc.Stop())
+	return multierr.Append(err, n.mnet.Close())
+}
+
+// Aggregator returns the aggregator node.
+func (n *Network) Aggregator() *node.FullNode {
+	return n.nodes[0]
+}
+
+// Nodes returns all nodes of the network. Aggregator is the node 0.
+func (n *Network) Nodes() []*node.FullNode {
+	return n.nodes
+}
+
+// App returns the ABCI application of i-th node.
+func (n *Network) App(i int) abci.Application {
+	return n.apps[i]
+}
+
+// DALC returns the data availability layer client shared by all nodes.
+func (n *Network) DALC() *FaultyDALC {
+	return n.dalc
+}
+
+// Height returns the height of the latest block stored by i-th node.
+func (n *Network) Height(i int) (uint64, error) {
+	if i < 0 || i >= len(n.nodes) {
+		return 0, ErrInvalidNode
+	}
+	return n.nodes[i].Store.Height(), nil
+}
+
+// WaitForHeight waits until i-th node stores the block at given height, or context is done.
+func (n *Network) WaitForHeight(ctx context.Context, i int, height uint64) error {
+	return n.wait(ctx, func() (bool, error) {
+		h, err := n.Height(i)
+		return h >= height, err
+	})
+}
+
+// WaitForAllHeight waits until all running nodes store the block at given height, or context is done.
+func (n *Network) WaitForAllHeight(ctx context.Context, height uint64) error {
+	return n.wait(ctx, func() (bool, error) {
+		for _, nd := range n.nodes {
+			if nd.IsRunning() && nd.Store.Height() < height {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitForSync waits until all running nodes catch up with the aggregator, or context is done.
+func (n *Network) WaitForSync(ctx context.Context) error {
+	h, _ := n.Height(0)
+	return n.WaitForAllHeight(ctx, h)
+}
+
+func (n *Network) wait(ctx context.Context, done func() (bool, error)) error {
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := done()
+		if err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+)
+
+func TestNetwork(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	conf := DefaultConfig()
+	conf.NumFullNodes = 2
+	conf.Logger = test.NewFileLogger(t)
+	n, err := New(ctx, conf)
+	require.NoError(err)
+	require.Len(n.Nodes(), 3)
+	assert.True(n.Aggregator() == n.Nodes()[0])
+
+	require.NoError(n.Start(ctx))
+	defer func() {
+		assert.NoError(n.Stop())
+	}()
+	require.NoError(n.WaitForAllHeight(ctx, 3))
+
+	// isolated node can't sync blocks without DA layer
+	require.NoError(n.Isolate(2))
+	n.DALC().SetRetrieveFailing(true)
+	stalled, err := n.Height(2)
+	require.NoError(err)
+	require.NoError(n.WaitForHeight(ctx, 1, stalled+2))
+	height, err := n.Height(2)
+	require.NoError(err)
+	assert.LessOrEqual(height, stalled+1)
+
+	require.NoError(n.Rejoin(2))
+	n.DALC().SetRetrieveFailing(false)
+	require.NoError(n.WaitForSync(ctx))
+
+	require.NoError(n.StopNode(1))
+	assert.False(n.Nodes()[1].IsRunning())
+	assert.ErrorIs(n.StopNode(3), ErrInvalidNode)
+	_, err = n.Height(-1)
+	assert.ErrorIs(err, ErrInvalidNode)
+}
@@ -0,0 +1,37 @@
+package sim
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rollkit/rollkit/clock"
+)
+
+// VirtualClock is the clock of the simulation. Its time changes only when the simulation advances it.
+type VirtualClock struct {
+	mtx sync.RWMutex
+	now time.Time
+}
+
+var _ clock.Clock = &VirtualClock{}
+
+// NewVirtualClock creates the clock set to given time.
+func NewVirtualClock(now time.Time) *VirtualClock {
+	return &VirtualClock{now: now}
+}
+
+// Now returns the current virtual time.
+func (c *VirtualClock) Now() time.Time {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.now
+}
+
+// set moves the clock to given time; virtual time never goes back.
+func (c *VirtualClock) set(t time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
@@ -0,0 +1,120 @@
+package sim
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/rollkit/rollkit/da"
+	"github.com/rollkit/rollkit/third_party/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+// DA is the simulated data availability layer. DA blocks are produced every DA block time of virtual time,
+// starting with an empty DA block at height 0. Submitted blocks are included in the first DA block produced
+// at least the inclusion latency after submission.
+type DA struct {
+	mtx       sync.Mutex
+	clock     *VirtualClock
+	start     time.Time
+	blockTime time.Duration
+	latency   time.Duration
+
+	height uint64
+	blocks map[uint64][]*types.Block
+}
+
+var _ da.DataAvailabilityLayerClient = &DA{}
+var _ da.BlockRetriever = &DA{}
+
+func newDA(clock *VirtualClock, blockTime, latency time.Duration) *DA {
+	return &DA{
+		clock:     clock,
+		start:     clock.Now(),
+		blockTime: blockTime,
+		latency:   latency,
+		blocks:    make(map[uint64][]*types.Block),
+	}
+}
+
+// Init implements DataAvailabilityLayerClient interface; simulated DA layer is configured by the simulation.
+func (d *DA) Init(types.NamespaceID, []byte, ds.Datastore, log.Logger) error {
+	return nil
+}
+
+// Start implements DataAvailabilityLayerClient interface; DA blocks are produced by the simulation.
+func (d *DA) Start() error {
+	return nil
+}
+
+// Stop implements DataAvailabilityLayerClient interface.
+func (d *DA) Stop() error {
+	return nil
+}
+
+// SubmitBlocks schedules inclusion of blocks in the DA block produced after the inclusion latency.
+func (d *DA) SubmitBlocks(_ context.Context, blocks []*types.Block) da.ResultSubmitBlocks {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	elapsed := d.clock.Now().Add(d.latency).Sub(d.start)
+	daHeight := uint64((elapsed + d.blockTime - 1) / d.blockTime)
+	if daHeight <= d.height {
+		daHeight = d.height + 1
+	}
+	for _, block := range blocks {
+		cp, err := copyBlock(block)
+		if err != nil {
+			return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+		d.blocks[daHeight] = append(d.blocks[daHeight], cp)
+	}
+	return da.ResultSubmitBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, Message: "OK", DAHeight: daHeight}}
+}
+
+// RetrieveBlocks returns blocks included at given DA height, if the DA block is already produced.
+func (d *DA) RetrieveBlocks(_ context.Context, daHeight uint64) da.ResultRetrieveBlocks {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if daHeight > d.height {
+		return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: "block not found"}}
+	}
+	blocks := make([]*types.Block, 0, len(d.blocks[daHeight]))
+	for _, block := range d.blocks[daHeight] {
+		cp, err := copyBlock(block)
+		if err != nil {
+			return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusError, Message: err.Error()}}
+		}
+		blocks = append(blocks, cp)
+	}
+	return da.ResultRetrieveBlocks{BaseResult: da.BaseResult{Code: da.StatusSuccess, DAHeight: daHeight}, Blocks: blocks}
+}
+
+// Height returns the height of the latest produced DA block.
+func (d *DA) Height() uint64 {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.height
+}
+
+func (d *DA) produceBlock() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.height++
+}
+
+// copyBlock makes a deep copy of the block, so nodes never share block instances, like in a real network.
+func copyBlock(block *types.Block) (*types.Block, error) {
+	bz, err := block.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	cp := new(types.Block)
+	if err := cp.UnmarshalBinary(bz); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
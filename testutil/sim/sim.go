@@ -0,0 +1,411 @@
+// Package sim is a deterministic discrete-event simulation of Rollkit networks. Nodes run block managers driven
+// step by step in virtual time, with a simulated data availability layer and scripted P2P and DA latencies,
+// so sync and failover scenarios can be reproduced exactly.
+//
+// Simulation is single-threaded: events are executed one by one, in order of their virtual time, and events
+// scheduled for the same time in order of scheduling.
+package sim
+
+import (
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cometbft/cometbft/abci/example/kvstore"
+	abci "github.com/cometbft/cometbft/abci/types"
+	llcfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"go.uber.org/multierr"
+
+	"github.com/rollkit/rollkit/block"
+	"github.com/rollkit/rollkit/config"
+	"github.com/rollkit/rollkit/mempool"
+	mempoolv1 "github.com/rollkit/rollkit/mempool/v1"
+	"github.com/rollkit/rollkit/signer"
+	"github.com/rollkit/rollkit/store"
+	"github.com/rollkit/rollkit/types"
+)
+
+var (
+	// ErrInvalidNode is returned when node index is out of range.
+	ErrInvalidNode = errors.New("invalid node index")
+	// ErrTimeout is returned when condition is not satisfied within the virtual time limit.
+	ErrTimeout = errors.New("condition not satisfied within time limit")
+)
+
+// Config configures the simulation.
+type Config struct {
+	// Seed determines keys of the network; simulations with equal configuration and seed produce equal blocks.
+	Seed int64
+	// ChainID of the network.
+	ChainID string
+	// GenesisTime is the genesis time of the chain, and the initial virtual time.
+	GenesisTime time.Time
+	// NumFullNodes is the number of full nodes syncing from the aggregator.
+	NumFullNodes int
+	// BlockManagerConfig is used by all nodes. BlockTime and DABlockTime define how often blocks are produced
+	// by the aggregator and the DA layer.
+	BlockManagerConfig config.BlockManagerConfig
+	// DAInclusionLatency is the minimal time between submission of blocks and their inclusion in a DA block.
+	DAInclusionLatency time.Duration
+	// P2PLatency returns the latency of gossip from node to node; negative latency drops the message.
+	// If it's nil, gossip is delivered immediately.
+	P2PLatency func(from, to int) time.Duration
+	// NewApp creates the ABCI application of i-th node. Aggregator is the node 0.
+	NewApp func(i int) abci.Application
+	// Logger is used by all nodes, with "node" key set to node index.
+	Logger log.Logger
+}
+
+// DefaultConfig returns configuration of the simulation with the aggregator and a single full node running
+// the kvstore application.
+func DefaultConfig() Config {
+	return Config{
+		ChainID:      "sim",
+		GenesisTime:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		NumFullNodes: 1,
+		BlockManagerConfig: config.BlockManagerConfig{
+			BlockTime:   1 * time.Second,
+			DABlockTime: 5 * time.Second,
+			NamespaceID: types.NamespaceID{8, 7, 6, 5, 4, 3, 2, 1},
+		},
+		DAInclusionLatency: 1 * time.Second,
+		NewApp: func(int) abci.Application {
+			return kvstore.NewApplication()
+		},
+		Logger: log.NewNopLogger(),
+	}
+}
+
+// Node is a simulated node.
+type Node struct {
+	Manager *block.Manager
+	Store   store.Store
+	Mempool mempool.Mempool
+
+	proxyApp proxy.AppConns
+	offline  bool
+}
+
+// Simulation is a simulated network of Rollkit nodes.
+type Simulation struct {
+	conf  Config
+	clock *VirtualClock
+	da    *DA
+	nodes []*Node
+
+	queue eventQueue
+	seq   uint64
+}
+
+// New creates the simulation. Aggregator starts producing blocks after one block time of virtual time,
+// and the first DA block is produced after one DA block time.
+func New(ctx context.Context, conf Config) (*Simulation, error) {
+	if conf.NumFullNodes < 0 {
+		return nil, fmt.Errorf("invalid number of full nodes: %d", conf.NumFullNodes)
+	}
+	if conf.BlockManagerConfig.BlockTime <= 0 || conf.BlockManagerConfig.DABlockTime <= 0 {
+		return nil, errors.New("block time and DA block time must be positive")
+	}
+	if conf.NewApp == nil {
+		conf.NewApp = DefaultConfig().NewApp
+	}
+	if conf.Logger == nil {
+		conf.Logger = log.NewNopLogger()
+	}
+
+	var secret [8]byte
+	binary.BigEndian.PutUint64(secret[:], uint64(conf.Seed))
+	validatorKey := ed25519.GenPrivKeyFromSecret(secret[:])
+	signingKey, err := crypto.UnmarshalEd25519PrivateKey(validatorKey.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	genesis := &cmtypes.GenesisDoc{
+		ChainID:         conf.ChainID,
+		GenesisTime:     conf.GenesisTime,
+		InitialHeight:   1,
+		ConsensusParams: cmtypes.DefaultConsensusParams(),
+		Validators: []cmtypes.GenesisValidator{{
+			Address: validatorKey.PubKey().Address(),
+			PubKey:  validatorKey.PubKey(),
+			Power:   100,
+			Name:    "aggregator",
+		}},
+	}
+
+	s := &Simulation{
+		conf:  conf,
+		clock: NewVirtualClock(conf.GenesisTime),
+	}
+	s.da = newDA(s.clock, conf.BlockManagerConfig.DABlockTime, conf.DAInclusionLatency)
+	for i := 0; i <= conf.NumFullNodes; i++ {
+		n, err := s.newNode(ctx, i, signingKey, genesis)
+		if n != nil {
+			s.nodes = append(s.nodes, n)
+		}
+		if err != nil {
+			_ = s.Stop()
+			return nil, fmt.Errorf("error while creating node %d: %w", i, err)
+		}
+	}
+
+	// at the same time, DA block is produced first, then the block is produced, submitted and retrieved
+	s.every(conf.BlockManagerConfig.DABlockTime, func(context.Context) error {
+		s.da.produceBlock()
+		return nil
+	})
+	s.every(conf.BlockManagerConfig.BlockTime, s.produceBlock)
+	s.every(conf.BlockManagerConfig.DABlockTime, func(ctx context.Context) error {
+		return s.onNode(0, func(n *Node) error { return n.Manager.SubmitBlocks(ctx) })
+	})
+	for i := range s.nodes {
+		i := i
+		s.every(conf.BlockManagerConfig.DABlockTime, func(ctx context.Context) error {
+			return s.onNode(i, func(n *Node) error {
+				for n.Manager.DAHeight() <= s.da.Height() {
+					if err := n.Manager.RetrieveNextDABlock(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+	}
+	return s, nil
+}
+
+func (s *Simulation) newNode(ctx context.Context, i int, key crypto.PrivKey, genesis *cmtypes.GenesisDoc) (*Node, error) {
+	logger := s.conf.Logger.With("node", i)
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(s.conf.NewApp(i)), proxy.NopMetrics())
+	proxyApp.SetLogger(logger.With("module", "proxy"))
+	if err := proxyApp.Start(); err != nil {
+		return nil, err
+	}
+	n := &Node{proxyApp: proxyApp}
+	kv, err := store.NewDefaultInMemoryKVStore()
+	if err != nil {
+		return n, err
+	}
+	n.Store = store.New(ctx, kv)
+	n.Mempool = mempoolv1.NewTxMempool(logger.With("module", "mempool"), llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
+	n.Manager, err = block.NewManager(signer.NewLocalSigner(key), s.conf.BlockManagerConfig, genesis, types.DefaultMerkleHasher, n.Store, n.Mempool,
+		proxyApp.Consensus(), nil, nil, nil, nil, s.da, nil, nil, nil, logger.With("module", "BlockManager"), nil)
+	if err != nil {
+		return n, err
+	}
+	n.Manager.SetClock(s.clock)
+	return n, nil
+}
+
+// Stop stops applications of all nodes.
+func (s *Simulation) Stop() error {
+	var err error
+	for _, n := range s.nodes {
+		if n.proxyApp != nil {
+			err = multierr.Append(err, n.proxyApp.Stop())
+		}
+	}
+	return err
+}
+
+// Now returns the current virtual time.
+func (s *Simulation) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Clock returns the virtual clock used by all nodes.
+func (s *Simulation) Clock() *VirtualClock {
+	return s.clock
+}
+
+// DA returns the simulated DA layer.
+func (s *Simulation) DA() *DA {
+	return s.da
+}
+
+// Node returns i-th node. Aggregator is the node 0.
+func (s *Simulation) Node(i int) (*Node, error) {
+	if i < 0 || i >= len(s.nodes) {
+		return nil, ErrInvalidNode
+	}
+	return s.nodes[i], nil
+}
+
+// Height returns the height of the latest block stored by i-th node.
+func (s *Simulation) Height(i int) (uint64, error) {
+	n, err := s.Node(i)
+	if err != nil {
+		return 0, err
+	}
+	return n.Store.Height(), nil
+}
+
+// SetOffline makes i-th node offline, or brings it back online. Offline nodes don't produce, gossip, submit
+// nor retrieve blocks, and gossip sent to them is lost.
+func (s *Simulation) SetOffline(i int, offline bool) error {
+	n, err := s.Node(i)
+	if err != nil {
+		return err
+	}
+	n.offline = offline
+	return nil
+}
+
+// SubmitTx adds the transaction to the mempool of i-th node.
+func (s *Simulation) SubmitTx(i int, tx types.Tx) error {
+	n, err := s.Node(i)
+	if err != nil {
+		return err
+	}
+	return n.Mempool.CheckTx(cmtypes.Tx(tx), nil, mempool.TxInfo{})
+}
+
+// Schedule schedules fn to be executed after given delay of virtual time. It's used to script scenarios,
+// e.g. taking nodes offline or submitting transactions at given time.
+func (s *Simulation) Schedule(delay time.Duration, fn func(ctx context.Context) error) {
+	s.seq++
+	s.scheduleSeq(delay, s.seq, fn)
+}
+
+// scheduleSeq schedules fn with given sequence number, which orders events scheduled at the same time.
+func (s *Simulation) scheduleSeq(delay time.Duration, seq uint64, fn func(ctx context.Context) error) {
+	heap.Push(&s.queue, &event{at: s.clock.Now().Add(delay), seq: seq, fn: fn})
+}
+
+// RunFor executes all events scheduled within given duration of virtual time, and advances the clock by it.
+func (s *Simulation) RunFor(ctx context.Context, d time.Duration) error {
+	end := s.clock.Now().Add(d)
+	for len(s.queue) > 0 && !s.queue[0].at.After(end) {
+		if err := s.step(ctx); err != nil {
+			return err
+		}
+	}
+	s.clock.set(end)
+	return nil
+}
+
+// RunUntil executes events until cond is satisfied. It returns ErrTimeout if cond is not satisfied within given
+// duration of virtual time.
+func (s *Simulation) RunUntil(ctx context.Context, cond func() bool, limit time.Duration) error {
+	end := s.clock.Now().Add(limit)
+	for !cond() {
+		if len(s.queue) == 0 || s.queue[0].at.After(end) {
+			s.clock.set(end)
+			return ErrTimeout
+		}
+		if err := s.step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step executes the next event.
+func (s *Simulation) step(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e := heap.Pop(&s.queue).(*event)
+	s.clock.set(e.at)
+	return e.fn(ctx)
+}
+
+// every schedules fn to be executed periodically. All executions keep the sequence number of the first one, so
+// periodic tasks coinciding in time are always executed in the order of registration, whatever their intervals.
+func (s *Simulation) every(interval time.Duration, fn func(ctx context.Context) error) {
+	s.seq++
+	seq := s.seq
+	var periodic func(ctx context.Context) error
+	periodic = func(ctx context.Context) error {
+		s.scheduleSeq(interval, seq, periodic)
+		return fn(ctx)
+	}
+	s.scheduleSeq(interval, seq, periodic)
+}
+
+// onNode executes fn on i-th node, unless the node is offline.
+func (s *Simulation) onNode(i int, fn func(n *Node) error) error {
+	n := s.nodes[i]
+	if n.offline {
+		return nil
+	}
+	if err := fn(n); err != nil {
+		return fmt.Errorf("node %d at %s: %w", i, s.clock.Now().Format(time.RFC3339Nano), err)
+	}
+	return nil
+}
+
+// produceBlock produces a block by the aggregator, and gossips it to other nodes.
+func (s *Simulation) produceBlock(ctx context.Context) error {
+	return s.onNode(0, func(n *Node) error {
+		blocks, err := n.Manager.ProduceBlock(ctx)
+		for _, b := range blocks {
+			s.gossip(0, b)
+		}
+		return err
+	})
+}
+
+// gossip delivers the block to all other nodes, after the P2P latency.
+func (s *Simulation) gossip(from int, b *types.Block) {
+	for to := range s.nodes {
+		if to == from {
+			continue
+		}
+		var latency time.Duration
+		if s.conf.P2PLatency != nil {
+			latency = s.conf.P2PLatency(from, to)
+		}
+		if latency < 0 {
+			continue
+		}
+		to := to
+		s.Schedule(latency, func(ctx context.Context) error {
+			return s.onNode(to, func(n *Node) error {
+				cp, err := copyBlock(b)
+				if err != nil {
+					return err
+				}
+				return n.Manager.ReceiveBlock(ctx, cp)
+			})
+		})
+	}
+}
+
+type event struct {
+	at  time.Time
+	seq uint64
+	fn  func(ctx context.Context) error
+}
+
+// eventQueue is a priority queue of events, ordered by time and sequence number.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
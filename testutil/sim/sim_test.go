@@ -0,0 +1,106 @@
+package sim
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	test "github.com/rollkit/rollkit/test/log"
+	"github.com/rollkit/rollkit/types"
+)
+
+func TestDeterminism(t *testing.T) {
+	require := require.New(t)
+
+	run := func() []types.Hash {
+		conf := DefaultConfig()
+		conf.Seed = 42
+		conf.NumFullNodes = 2
+		conf.Logger = test.NewFileLogger(t)
+		s, err := New(context.Background(), conf)
+		require.NoError(err)
+		defer func() { require.NoError(s.Stop()) }()
+
+		s.Schedule(2500*time.Millisecond, func(context.Context) error {
+			return s.SubmitTx(0, types.Tx("key=value"))
+		})
+		require.NoError(s.RunFor(context.Background(), 30*time.Second))
+
+		n, err := s.Node(2)
+		require.NoError(err)
+		var hashes []types.Hash
+		for h := uint64(1); h <= n.Store.Height(); h++ {
+			block, err := n.Store.LoadBlock(h)
+			require.NoError(err)
+			hashes = append(hashes, block.Hash())
+		}
+		return hashes
+	}
+
+	first := run()
+	require.Len(first, 30)
+	assert.Equal(t, first, run())
+}
+
+func TestSync(t *testing.T) {
+	cases := []struct {
+		name   string
+		p2p    func(from, to int) time.Duration
+		synced uint64
+	}{
+		{"gossip", func(int, int) time.Duration { return 100 * time.Millisecond }, 20},
+		// without gossip, full node syncs from DA: blocks are submitted every 5s and included 1s later
+		{"DA only", func(int, int) time.Duration { return -1 }, 15},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			conf := DefaultConfig()
+			conf.P2PLatency = c.p2p
+			conf.Logger = test.NewFileLogger(t)
+			s, err := New(context.Background(), conf)
+			require.NoError(err)
+			defer func() { require.NoError(s.Stop()) }()
+
+			require.NoError(s.RunFor(context.Background(), 20*time.Second+500*time.Millisecond))
+			h, err := s.Height(0)
+			require.NoError(err)
+			assert.Equal(t, uint64(20), h)
+			h, err = s.Height(1)
+			require.NoError(err)
+			assert.Equal(t, c.synced, h)
+		})
+	}
+}
+
+func TestFailover(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	conf := DefaultConfig()
+	conf.Logger = test.NewFileLogger(t)
+	s, err := New(ctx, conf)
+	require.NoError(err)
+	defer func() { require.NoError(s.Stop()) }()
+
+	require.NoError(s.RunFor(ctx, 5*time.Second))
+	require.NoError(s.SetOffline(1, true))
+	require.NoError(s.RunFor(ctx, 10*time.Second))
+	h, err := s.Height(1)
+	require.NoError(err)
+	require.Equal(uint64(5), h)
+
+	// node catches up from DA, and continues with gossiped blocks
+	require.NoError(s.SetOffline(1, false))
+	err = s.RunUntil(ctx, func() bool {
+		h, _ := s.Height(1)
+		return h >= 15
+	}, 10*time.Second)
+	require.NoError(err)
+
+	err = s.RunUntil(ctx, func() bool { return false }, time.Second)
+	require.ErrorIs(err, ErrTimeout)
+}
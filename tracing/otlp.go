@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// serviceName is reported as service.name resource attribute of exported spans.
+const serviceName = "rollkit"
+
+// Config configures export of spans over OTLP.
+type Config struct {
+	// Endpoint is the host:port of OTLP/gRPC collector. Tracing is disabled if empty.
+	Endpoint string
+	// Insecure disables TLS of the connection to the collector.
+	Insecure bool
+	// SampleRate is the fraction of root spans that are sampled, in range [0, 1]. Child spans follow
+	// sampling decision of their parent.
+	SampleRate float64
+}
+
+// NewOTLPProvider returns a tracer provider exporting spans in batches to the OTLP/gRPC collector at
+// conf.Endpoint. Spans are labeled with chain ID and node type. The provider has to be registered with
+// otel.SetTracerProvider, and shut down to flush the remaining spans. It returns nil if conf.Endpoint is empty.
+func NewOTLPProvider(ctx context.Context, conf Config, chainID string, nodeType string) (*sdktrace.TracerProvider, error) {
+	if conf.Endpoint == "" {
+		return nil, nil
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conf.Endpoint)}
+	if conf.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		attribute.String("chain_id", chainID),
+		attribute.String("node_type", nodeType),
+	)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRate))),
+	), nil
+}
@@ -0,0 +1,33 @@
+// Package tracing contains helpers for OpenTelemetry instrumentation of Rollkit.
+//
+// Spans are created with the global tracer provider. Tracing is disabled (no-op) unless the node
+// is configured with an OTLP collector endpoint (see NewOTLPProvider), or the application embedding
+// the node registers its own tracer provider with otel.SetTracerProvider.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies Rollkit spans.
+const instrumentationName = "github.com/rollkit/rollkit"
+
+// Start creates a span with given name and attributes, as a child of the span in ctx, if any.
+// Returned context contains the new span and should be passed to the subsequent calls.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err in span, if not nil, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/signer"
+)
+
+// verifiedCommit records successful verification of signatures of a commit by VerifyCommits. Signatures are not
+// verified again by ValidateBasic and VerifyCommit, as long as the header, the commit, the aggregator set and
+// the aggregator keys are unchanged.
+type verifiedCommit struct {
+	msg        []byte
+	commit     Commit
+	validators []byte
+	keys       []byte
+	signed     int64
+}
+
+func (vc *verifiedCommit) matches(sh *SignedHeader, msg []byte) bool {
+	if vc == nil || !bytes.Equal(vc.msg, msg) || len(vc.commit.Signatures) != len(sh.Commit.Signatures) ||
+		!bytes.Equal(vc.commit.AggregatedSignature, sh.Commit.AggregatedSignature) ||
+		!bytes.Equal(vc.commit.Signers, sh.Commit.Signers) {
+		return false
+	}
+	for i, signature := range sh.Commit.Signatures {
+		if !bytes.Equal(vc.commit.Signatures[i], signature) {
+			return false
+		}
+	}
+	return bytes.Equal(vc.validators, sh.Validators.Hash()) && bytes.Equal(vc.keys, AggregatorKeysHash(sh.AggregatorKeys))
+}
+
+// VerifyCommits verifies signatures of commits of many headers, e.g. blocks retrieved during catch-up sync.
+// Headers are split across workers (GOMAXPROCS workers if workers is not positive), each one verifying signatures
+// of its headers one by one. Aggregated BLS signatures are verified only against aggregatorKeys,
+// the keys configured for the chain (with verified proofs of possession); keys carried by headers are not trusted,
+// and headers carrying other keys get ErrUnexpectedAggregatorKeys. Headers without aggregator set get
+// ErrNoAggregatorSet. Returned errors are ordered like headers; nil error means that signatures of the commit are
+// valid, and they are not verified again by ValidateBasic and VerifyCommit of the header. Voting power threshold
+// is not checked, it's up to VerifyCommit.
+func VerifyCommits(headers []*SignedHeader, aggregatorKeys []bls.PubKey, workers int) []error {
+	errs := make([]error, len(headers))
+	if len(headers) == 0 {
+		return errs
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(headers) {
+		workers = len(headers)
+	}
+	chunk := (len(headers) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(headers); start += chunk {
+		end := start + chunk
+		if end > len(headers) {
+			end = len(headers)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			verifyCommitsBatch(headers[start:end], aggregatorKeys, errs[start:end])
+		}(start, end)
+	}
+	wg.Wait()
+	return errs
+}
+
+// verifyCommitsBatch verifies signatures of commits of headers, and stores the results in errs.
+func verifyCommitsBatch(headers []*SignedHeader, aggregatorKeys []bls.PubKey, errs []error) {
+	msgs := make([][]byte, len(headers))
+	signed := make([]int64, len(headers))
+	for i, sh := range headers {
+		if sh.Validators == nil || len(sh.Validators.Validators) == 0 {
+			errs[i] = ErrNoAggregatorSet
+			continue
+		}
+		msg, err := sh.Header.MarshalBinary()
+		if err != nil {
+			errs[i] = fmt.Errorf("%w: unable to marshal header", ErrSignatureVerificationFailed)
+			continue
+		}
+		msgs[i] = msg
+		_, weight := sh.votingPower()
+		if len(sh.Commit.AggregatedSignature) > 0 {
+			if !equalKeys(sh.AggregatorKeys, aggregatorKeys) || !bytes.Equal(sh.AggregatorKeysHash, AggregatorKeysHash(aggregatorKeys)) {
+				errs[i] = ErrUnexpectedAggregatorKeys
+				continue
+			}
+			signed[i], errs[i] = sh.verifyAggregatedSignature(msg, weight)
+			continue
+		}
+		if len(sh.Commit.Signatures) != len(sh.Validators.Validators) {
+			errs[i] = fmt.Errorf("%w: got %d, expected %d", ErrCommitSignaturesCount, len(sh.Commit.Signatures), len(sh.Validators.Validators))
+			continue
+		}
+		signatures := 0
+		for j, signature := range sh.Commit.Signatures {
+			if len(signature) == 0 {
+				continue
+			}
+			val := sh.Validators.Validators[j]
+			signed[i] += weight(val)
+			signatures++
+			if errs[i] == nil && !signer.VerifySignature(val.PubKey, msg, signature) {
+				errs[i] = fmt.Errorf("%w: aggregator %s", ErrSignatureVerificationFailed, val.Address)
+			}
+		}
+		if signatures == 0 {
+			errs[i] = fmt.Errorf("%w: no signatures", ErrSignatureVerificationFailed)
+		}
+	}
+
+	for i, sh := range headers {
+		if errs[i] != nil || msgs[i] == nil {
+			continue
+		}
+		commit := Commit{
+			Signatures:          make([]Signature, len(sh.Commit.Signatures)),
+			AggregatedSignature: append(Signature(nil), sh.Commit.AggregatedSignature...),
+			Signers:             append([]byte(nil), sh.Commit.Signers...),
+		}
+		for j, signature := range sh.Commit.Signatures {
+			commit.Signatures[j] = append(Signature(nil), signature...)
+		}
+		sh.verified = &verifiedCommit{
+			msg:        msgs[i],
+			commit:     commit,
+			validators: sh.Validators.Hash(),
+			keys:       AggregatorKeysHash(sh.AggregatorKeys),
+			signed:     signed[i],
+		}
+	}
+}
+
+func equalKeys(a, b []bls.PubKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
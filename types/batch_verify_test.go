@@ -0,0 +1,111 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/signer"
+)
+
+func TestVerifyCommits(t *testing.T) {
+	for _, scheme := range []string{signer.SchemeEd25519, signer.SchemeSecp256k1} {
+		t.Run(scheme, func(t *testing.T) {
+			g := NewGenerator(1, WithNumValidators(3), WithSignatureScheme(scheme))
+			sh, keys, err := g.SignedHeader()
+			require.NoError(t, err)
+			headers := []*SignedHeader{sh}
+			for len(headers) < 10 {
+				sh, err = g.NextSignedHeader(sh, keys)
+				require.NoError(t, err)
+				headers = append(headers, sh)
+			}
+			// signature of another header
+			headers[4].Commit.Signatures[1] = headers[5].Commit.Signatures[1]
+			// based rollup header
+			headers = append(headers, &SignedHeader{Header: g.Header()})
+
+			errs := VerifyCommits(headers, nil, 3)
+			require.Len(t, errs, len(headers))
+			for i, err := range errs {
+				switch i {
+				case 4:
+					assert.ErrorIs(t, err, ErrSignatureVerificationFailed)
+					assert.Nil(t, headers[i].verified)
+				case 10:
+					assert.ErrorIs(t, err, ErrNoAggregatorSet)
+				default:
+					assert.NoError(t, err)
+					assert.NotNil(t, headers[i].verified)
+					assert.NoError(t, headers[i].ValidateBasic())
+					assert.NoError(t, headers[i].VerifyCommit(DefaultCommitThreshold))
+				}
+			}
+			assert.ErrorIs(t, headers[4].VerifyCommit(DefaultCommitThreshold), ErrSignatureVerificationFailed)
+
+			// signatures are verified again if the commit changed after verification
+			headers[0].Commit.Signatures[0] = headers[1].Commit.Signatures[0]
+			assert.ErrorIs(t, headers[0].VerifyCommit(DefaultCommitThreshold), ErrSignatureVerificationFailed)
+		})
+	}
+}
+
+func TestVerifyCommitsAggregated(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	vals := make([]*cmtypes.Validator, 3)
+	for i := range vals {
+		vals[i] = cmtypes.NewValidator(ed25519.GenPrivKey().PubKey(), 1)
+	}
+	valSet := cmtypes.NewValidatorSet(vals)
+	newKeys := func() ([]bls.PrivKey, []bls.PubKey) {
+		privKeys := make([]bls.PrivKey, valSet.Size())
+		pubKeys := make([]bls.PubKey, valSet.Size())
+		for i := range privKeys {
+			key, err := bls.GenPrivKey()
+			require.NoError(err)
+			privKeys[i], pubKeys[i] = key, key.PubKey()
+		}
+		return privKeys, pubKeys
+	}
+	signed := func(privKeys []bls.PrivKey, pubKeys []bls.PubKey) *SignedHeader {
+		sh := &SignedHeader{Header: GetRandomHeader(), Validators: valSet, AggregatorKeys: pubKeys}
+		sh.ProposerAddress = valSet.Proposer.Address
+		sh.AggregatorsHash = valSet.Hash()
+		sh.AggregatorKeysHash = AggregatorKeysHash(pubKeys)
+		msg, err := sh.Header.MarshalBinary()
+		require.NoError(err)
+		signatures := make([]Signature, len(privKeys))
+		for i, key := range privKeys {
+			signatures[i] = key.Sign(msg)
+		}
+		commit, err := AggregateCommit(signatures)
+		require.NoError(err)
+		sh.Commit = *commit
+		return sh
+	}
+
+	privKeys, pubKeys := newKeys()
+	// header signed by rogue keys is valid on its own, but not against the configured keys
+	roguePrivKeys, roguePubKeys := newKeys()
+	headers := []*SignedHeader{signed(privKeys, pubKeys), signed(roguePrivKeys, roguePubKeys)}
+	require.NoError(headers[1].ValidateBasic())
+
+	errs := VerifyCommits(headers, pubKeys, 2)
+	assert.NoError(errs[0])
+	assert.NotNil(headers[0].verified)
+	assert.ErrorIs(errs[1], ErrUnexpectedAggregatorKeys)
+	assert.Nil(headers[1].verified)
+
+	errs = VerifyCommits(headers[:1], nil, 1)
+	assert.ErrorIs(errs[0], ErrUnexpectedAggregatorKeys)
+
+	// verification is not reused after keys are replaced
+	headers[0].AggregatorKeys = roguePubKeys
+	assert.False(headers[0].verified.matches(headers[0], headers[0].verified.msg))
+}
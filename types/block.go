@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"encoding"
 	"errors"
+	"fmt"
 	"time"
 
 	cmbytes "github.com/cometbft/cometbft/libs/bytes"
 
 	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/crypto/bls"
 )
 
 // NamespaceID is a unique identifier of a namespace.
@@ -36,18 +39,25 @@ var _ encoding.BinaryUnmarshaler = &Block{}
 type Data struct {
 	Txs                    Txs
 	IntermediateStateRoots IntermediateStateRoots
-	// Note: Temporarily remove Evidence #896
-	// Evidence               EvidenceData
+	Evidence               EvidenceData
 }
 
 // EvidenceData defines how evidence is stored in block.
 type EvidenceData struct {
-	Evidence []cmtypes.Evidence
+	Evidence []DuplicateHeaderEvidence
 }
 
 // Commit contains evidence of block creation.
 type Commit struct {
 	Signatures []Signature // most of the time this is a single signature
+	// ValidityProof proves the state transition of the block, if validity proofs are enabled.
+	// It's not covered by signatures, but committed to by Header.ValidityProofHash.
+	ValidityProof []byte
+	// AggregatedSignature is BLS signature aggregating signatures of all aggregators marked in Signers.
+	// It's used instead of Signatures if aggregators sign with BLS keys.
+	AggregatedSignature Signature
+	// Signers is a bit array marking aggregators (ordered like in the aggregator set) that signed the header.
+	Signers []byte
 }
 
 // Signature represents signature of block creator.
@@ -77,8 +87,17 @@ func (c *Commit) ToABCICommit(height uint64, hash Hash) *cmtypes.Commit {
 			BlockIDFlag: cmtypes.BlockIDFlagCommit,
 			Signature:   sig,
 		}
+		if len(sig) == 0 {
+			commitSig.BlockIDFlag = cmtypes.BlockIDFlagAbsent
+		}
 		tmCommit.Signatures[i] = commitSig
 	}
+	if len(c.AggregatedSignature) > 0 {
+		tmCommit.Signatures = []cmtypes.CommitSig{{
+			BlockIDFlag: cmtypes.BlockIDFlagCommit,
+			Signature:   c.AggregatedSignature,
+		}}
+	}
 
 	return &tmCommit
 }
@@ -87,7 +106,7 @@ func (c *Commit) ToABCICommit(height uint64, hash Hash) *cmtypes.Commit {
 func (c *Commit) GetCommitHash(header *Header, proposerAddress []byte) []byte {
 	lastABCICommit := c.ToABCICommit(header.Height(), header.Hash())
 	// Rollkit does not support a multi signature scheme so there can only be one signature
-	if len(c.Signatures) == 1 {
+	if len(lastABCICommit.Signatures) == 1 {
 		lastABCICommit.Signatures[0].ValidatorAddress = proposerAddress
 		lastABCICommit.Signatures[0].Timestamp = header.Time()
 	}
@@ -95,37 +114,111 @@ func (c *Commit) GetCommitHash(header *Header, proposerAddress []byte) []byte {
 }
 
 // ValidateBasic performs basic validation of block data.
-// Actually it's a placeholder, because nothing is checked.
 func (d *Data) ValidateBasic() error {
+	for i := range d.Evidence.Evidence {
+		if err := d.Evidence.Evidence[i].ValidateBasic(); err != nil {
+			return fmt.Errorf("evidence %d: %w", i, err)
+		}
+	}
 	return nil
 }
 
 // ValidateBasic performs basic validation of a commit.
 func (c *Commit) ValidateBasic() error {
+	if len(c.AggregatedSignature) > 0 {
+		if len(c.Signatures) > 0 {
+			return errors.New("both signatures and aggregated signature")
+		}
+		return nil
+	}
 	if len(c.Signatures) == 0 {
 		return errors.New("no signatures")
 	}
 	return nil
 }
 
+// AggregateCommit creates commit with BLS signature aggregating given signatures. Signatures are ordered like
+// aggregators in the set; empty signature means that aggregator didn't sign.
+func AggregateCommit(signatures []Signature) (*Commit, error) {
+	signers := make([]byte, (len(signatures)+7)/8)
+	sigs := make([][]byte, 0, len(signatures))
+	for i, sig := range signatures {
+		if len(sig) == 0 {
+			continue
+		}
+		signers[i/8] |= 1 << (i % 8)
+		sigs = append(sigs, sig)
+	}
+	aggregated, err := bls.AggregateSignatures(sigs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	return &Commit{AggregatedSignature: aggregated, Signers: signers}, nil
+}
+
+// Signed returns true if i-th aggregator (in the set) signed the aggregated signature.
+func (c *Commit) Signed(i int) bool {
+	return i >= 0 && i/8 < len(c.Signers) && c.Signers[i/8]&(1<<(i%8)) != 0
+}
+
+// MaxBlockSizeBytes is the maximum size of binary encoding of a block.
+const MaxBlockSizeBytes = cmtypes.MaxBlockSizeBytes
+
+var (
+	// ErrDataHashMismatch is returned when DataHash in the header doesn't match hash of the block's data.
+	ErrDataHashMismatch = errors.New("dataHash from the header does not match with hash of the block's data")
+	// ErrBlockSizeExceeded is returned when binary encoding of the block exceeds MaxBlockSizeBytes.
+	ErrBlockSizeExceeded = errors.New("block size exceeds maximum block size")
+	// ErrNonMonotonicTime is returned when block time is not after the time of the last block.
+	ErrNonMonotonicTime = errors.New("block time is not after time of the last block")
+)
+
 // ValidateBasic performs basic validation of a block.
-func (b *Block) ValidateBasic() error {
+//
+// Signed header is validated (including commit signatures against the aggregator set), DataHash is checked
+// against the data, computed with the hasher of the chain, and size of the block is limited by MaxBlockSizeBytes.
+// Consistency with the previous block is checked by ValidateWithLast.
+func (b *Block) ValidateBasic(hasher MerkleHasher) error {
+	if err := b.validate(); err != nil {
+		return err
+	}
+	dataHash, err := b.DataHash(hasher)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(dataHash[:], b.SignedHeader.DataHash[:]) {
+		return ErrDataHashMismatch
+	}
+	return nil
+}
+
+// validate performs basic validation of a block, except for checking DataHash.
+func (b *Block) validate() error {
 	if err := b.SignedHeader.ValidateBasic(); err != nil {
 		return err
 	}
 	if err := b.Data.ValidateBasic(); err != nil {
 		return err
 	}
-	dataHash, err := b.Data.Hash()
+	pbBlock, err := b.ToProto()
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(dataHash[:], b.SignedHeader.DataHash[:]) {
-		return errors.New("dataHash from the header does not match with hash of the block's data")
+	if size := pbBlock.Size(); size > MaxBlockSizeBytes {
+		return fmt.Errorf("%w: %d > %d", ErrBlockSizeExceeded, size, MaxBlockSizeBytes)
 	}
 	return nil
 }
 
+// ValidateWithLast checks that the block directly follows the last block: chain ID and height are consistent,
+// block time is after the time of the last block, and LastHeaderHash and LastCommitHash link to the last block.
+func (b *Block) ValidateWithLast(last *Block) error {
+	if last == nil {
+		return errors.New("last block cannot be nil")
+	}
+	return b.SignedHeader.ValidateWithLast(&last.SignedHeader)
+}
+
 // New returns a new Block.
 func (b *Block) New() *Block {
 	return new(Block)
@@ -164,7 +257,9 @@ func (b *Block) Verify(untrustedBlock *Block) error {
 	return b.SignedHeader.Verify(&untrustedBlock.SignedHeader)
 }
 
-// Validate performs basic validation of a block.
+// Validate performs basic validation of a block, used by the block sync service. DataHash is not checked, as the
+// hash function of the chain is not known to the service; it's checked with ValidateBasic before the block is
+// applied.
 func (b *Block) Validate() error {
-	return b.ValidateBasic()
+	return b.validate()
 }
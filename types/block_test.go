@@ -0,0 +1,64 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockValidateBasic(t *testing.T) {
+	g := NewGenerator(1)
+	sh, _, err := g.SignedHeader()
+	require.NoError(t, err)
+
+	block := &Block{SignedHeader: *sh}
+	assert.ErrorIs(t, block.ValidateBasic(DefaultMerkleHasher), ErrDataHashMismatch)
+	// block sync service doesn't know the hasher of the chain
+	assert.NoError(t, block.Validate())
+}
+
+func TestBlockValidateWithLast(t *testing.T) {
+	g := NewGenerator(1)
+	sh, keys, err := g.SignedHeader()
+	require.NoError(t, err)
+	next, err := g.NextSignedHeader(sh, keys)
+	require.NoError(t, err)
+
+	last := &Block{SignedHeader: *sh}
+	newBlock := func(modify func(*Block)) *Block {
+		b := &Block{SignedHeader: *next}
+		modify(b)
+		return b
+	}
+
+	tests := []struct {
+		name   string
+		block  *Block
+		errIs  error
+		errMsg string
+	}{
+		{"valid", newBlock(func(*Block) {}), nil, ""},
+		{"wrong chain ID", newBlock(func(b *Block) { b.SignedHeader.BaseHeader.ChainID = "other" }), nil, "chain ID mismatch"},
+		{"non-adjacent", newBlock(func(b *Block) { b.SignedHeader.BaseHeader.Height++ }), ErrNonAdjacentHeaders, ""},
+		{"wrong last header hash", newBlock(func(b *Block) { b.SignedHeader.LastHeaderHash = g.Bytes(32) }), ErrLastHeaderHashMismatch, ""},
+		{"wrong last commit hash", newBlock(func(b *Block) { b.SignedHeader.LastCommitHash = g.Bytes(32) }), ErrLastCommitHashMismatch, ""},
+		{"time not after last", newBlock(func(b *Block) {
+			b.SignedHeader.BaseHeader.Time = uint64(last.Time().Add(-time.Second).UnixNano())
+		}), ErrNonMonotonicTime, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.block.ValidateWithLast(last)
+			switch {
+			case tt.errIs != nil:
+				assert.ErrorIs(t, err, tt.errIs)
+			case tt.errMsg != "":
+				assert.ErrorContains(t, err, tt.errMsg)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
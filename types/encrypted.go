@@ -0,0 +1,170 @@
+package types
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+)
+
+var (
+	encryptedTxPrefix     = []byte("rkenc/tx/")
+	decryptionKeyTxPrefix = []byte("rkenc/key/")
+	decryptionKeyIDPrefix = []byte("rkenc/height/")
+)
+
+var (
+	// ErrInvalidEncryptedTx is returned when encrypted or decryption key transaction is malformed.
+	ErrInvalidEncryptedTx = errors.New("invalid encrypted transaction")
+	// ErrDecryptionFailed is returned when encrypted transaction can't be decrypted with given key.
+	ErrDecryptionFailed = errors.New("failed to decrypt transaction")
+	// ErrInvalidDecryptionKey is returned when decryption key is not signed by the committee.
+	ErrInvalidDecryptionKey = errors.New("invalid decryption key")
+)
+
+// EncryptedTx is a transaction encrypted to the decryption key of a future height (see EncryptTx).
+type EncryptedTx struct {
+	// Height is the height of the decryption key.
+	Height uint64
+	// FeeTx is a plaintext transaction paying for inclusion of the encrypted transaction.
+	FeeTx Tx
+
+	encapsulation []byte
+	nonce         []byte
+	ciphertext    []byte
+	header        []byte
+}
+
+// EncryptTx encrypts the transaction to the decryption key of given height, derived from the public key of the
+// decryption committee (see DecryptionKeyTx). The returned encrypted transaction (envelope) is committed in a block
+// without being executed; only its fee transaction is executed, and always charged. The transaction is decrypted and
+// executed after the committee releases the decryption key for the height, once the block at the height is
+// committed, so the sequencer can't order transactions based on their contents.
+func EncryptTx(tx Tx, feeTx Tx, committeeKey bls.PubKey, height uint64) (Tx, error) {
+	encapsulation, secret, err := bls.Encapsulate(committeeKey, DecryptionKeyID(height))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(secret)
+	if err != nil {
+		return nil, err
+	}
+	envelope := make([]byte, 0, len(encryptedTxPrefix)+12+len(feeTx)+len(encapsulation)+aead.NonceSize()+len(tx)+aead.Overhead())
+	envelope = append(envelope, encryptedTxPrefix...)
+	envelope = binary.BigEndian.AppendUint64(envelope, height)
+	envelope = binary.BigEndian.AppendUint32(envelope, uint32(len(feeTx)))
+	envelope = append(envelope, feeTx...)
+	envelope = append(envelope, encapsulation...)
+	header := envelope
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	envelope = append(envelope, nonce...)
+	// header is authenticated, so the fee transaction and the height can't be replaced
+	return aead.Seal(envelope, nonce, tx, header), nil
+}
+
+// IsEncryptedTx checks if the transaction is an encrypted transaction (it may still be malformed).
+func IsEncryptedTx(tx Tx) bool {
+	return bytes.HasPrefix(tx, encryptedTxPrefix)
+}
+
+// ParseEncryptedTx decodes the encrypted transaction, without decrypting it.
+func ParseEncryptedTx(tx Tx) (*EncryptedTx, error) {
+	if !IsEncryptedTx(tx) {
+		return nil, ErrInvalidEncryptedTx
+	}
+	feeStart := len(encryptedTxPrefix) + 12
+	if len(tx) < feeStart {
+		return nil, ErrInvalidEncryptedTx
+	}
+	feeLen := uint64(binary.BigEndian.Uint32(tx[feeStart-4:]))
+	if feeLen == 0 || uint64(len(tx)-feeStart) < feeLen+bls.EncapsulationSize+chacha20poly1305.NonceSize+chacha20poly1305.Overhead {
+		return nil, ErrInvalidEncryptedTx
+	}
+	feeEnd := feeStart + int(feeLen)
+	headerEnd := feeEnd + bls.EncapsulationSize
+	nonceEnd := headerEnd + chacha20poly1305.NonceSize
+	return &EncryptedTx{
+		Height:        binary.BigEndian.Uint64(tx[len(encryptedTxPrefix):]),
+		FeeTx:         append(Tx{}, tx[feeStart:feeEnd]...),
+		encapsulation: tx[feeEnd:headerEnd],
+		nonce:         tx[headerEnd:nonceEnd],
+		ciphertext:    tx[nonceEnd:],
+		header:        tx[:headerEnd],
+	}, nil
+}
+
+// Decrypt decrypts the transaction with the decryption key of its height. The key has to be verified by the caller
+// (see DecryptionKeyTx.Verify).
+func (e *EncryptedTx) Decrypt(key []byte) (Tx, error) {
+	secret, err := bls.Decapsulate(key, e.encapsulation)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	aead, err := chacha20poly1305.New(secret)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := aead.Open(nil, e.nonce, e.ciphertext, e.header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	return tx, nil
+}
+
+// DecryptionKeyID returns the message signed by the decryption committee to derive the decryption key of
+// transactions encrypted to given height.
+func DecryptionKeyID(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, decryptionKeyIDPrefix...), height)
+}
+
+// DecryptionKeyTx releases the decryption key of transactions encrypted to given height. The key is the BLS signature
+// of DecryptionKeyID(Height) made by the decryption committee: a threshold of its members sign the ID once the block
+// at the height is committed, and their signatures are combined into the key (see bls.CombineSignatures). Anyone
+// can submit the key transaction.
+type DecryptionKeyTx struct {
+	// Height is the height of encrypted transactions decrypted by the key.
+	Height uint64
+	// Key is the decryption key.
+	Key []byte
+}
+
+// Tx encodes the decryption key as a transaction.
+func (k *DecryptionKeyTx) Tx() Tx {
+	tx := make([]byte, 0, len(decryptionKeyTxPrefix)+8+bls.SignatureSize)
+	tx = append(tx, decryptionKeyTxPrefix...)
+	tx = binary.BigEndian.AppendUint64(tx, k.Height)
+	return append(tx, k.Key...)
+}
+
+// Verify checks that the key is the decryption key of the height, signed by the committee.
+func (k *DecryptionKeyTx) Verify(committeeKey bls.PubKey) error {
+	if !committeeKey.VerifySignature(DecryptionKeyID(k.Height), k.Key) {
+		return ErrInvalidDecryptionKey
+	}
+	return nil
+}
+
+// IsDecryptionKeyTx checks if the transaction is a decryption key transaction (it may still be malformed).
+func IsDecryptionKeyTx(tx Tx) bool {
+	return bytes.HasPrefix(tx, decryptionKeyTxPrefix)
+}
+
+// ParseDecryptionKeyTx decodes the decryption key transaction.
+func ParseDecryptionKeyTx(tx Tx) (*DecryptionKeyTx, error) {
+	if !IsDecryptionKeyTx(tx) || len(tx) != len(decryptionKeyTxPrefix)+8+bls.SignatureSize {
+		return nil, ErrInvalidEncryptedTx
+	}
+	data := tx[len(decryptionKeyTxPrefix):]
+	return &DecryptionKeyTx{
+		Height: binary.BigEndian.Uint64(data),
+		Key:    append([]byte{}, data[8:]...),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+)
+
+func TestEncryptedTx(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	committee, err := bls.GenPrivKey()
+	require.NoError(err)
+	shares, err := bls.SplitPrivKey(committee, 2, 3)
+	require.NoError(err)
+
+	tx := GetRandomTx()
+	feeTx := Tx("fee")
+	envelope, err := EncryptTx(tx, feeTx, committee.PubKey(), 42)
+	require.NoError(err)
+	assert.True(IsEncryptedTx(envelope))
+	assert.NotContains(string(envelope), string(tx))
+
+	parsed, err := ParseEncryptedTx(envelope)
+	require.NoError(err)
+	assert.Equal(uint64(42), parsed.Height)
+	assert.Equal(feeTx, parsed.FeeTx)
+
+	// decryption key is combined from signatures of a threshold of the committee
+	key, err := bls.CombineSignatures(map[int][]byte{
+		1: shares[0].Sign(DecryptionKeyID(42)),
+		3: shares[2].Sign(DecryptionKeyID(42)),
+	})
+	require.NoError(err)
+	keyTx := &DecryptionKeyTx{Height: 42, Key: key}
+	require.NoError(keyTx.Verify(committee.PubKey()))
+	decrypted, err := parsed.Decrypt(key)
+	require.NoError(err)
+	assert.Equal(tx, decrypted)
+
+	encoded := keyTx.Tx()
+	assert.True(IsDecryptionKeyTx(encoded))
+	assert.False(IsEncryptedTx(encoded))
+	parsedKey, err := ParseDecryptionKeyTx(encoded)
+	require.NoError(err)
+	assert.Equal(keyTx, parsedKey)
+	_, err = ParseDecryptionKeyTx(encoded[:len(encoded)-1])
+	assert.ErrorIs(err, ErrInvalidEncryptedTx)
+
+	// a single share or key of another height don't decrypt
+	assert.ErrorIs((&DecryptionKeyTx{Height: 42, Key: shares[0].Sign(DecryptionKeyID(42))}).Verify(committee.PubKey()), ErrInvalidDecryptionKey)
+	_, err = parsed.Decrypt(committee.Sign(DecryptionKeyID(43)))
+	assert.ErrorIs(err, ErrDecryptionFailed)
+	assert.ErrorIs((&DecryptionKeyTx{Height: 43, Key: key}).Verify(committee.PubKey()), ErrInvalidDecryptionKey)
+
+	// fee transaction is authenticated
+	tampered := append(Tx{}, envelope...)
+	tampered[len(encryptedTxPrefix)+12] = 'F'
+	parsed, err = ParseEncryptedTx(tampered)
+	require.NoError(err)
+	_, err = parsed.Decrypt(key)
+	assert.ErrorIs(err, ErrDecryptionFailed)
+
+	_, err = ParseEncryptedTx(envelope[:40])
+	assert.ErrorIs(err, ErrInvalidEncryptedTx)
+	_, err = ParseEncryptedTx(tx)
+	assert.ErrorIs(err, ErrInvalidEncryptedTx)
+}
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
+)
+
+// ErrInvalidEvidence is returned when evidence doesn't prove misbehavior of aggregators.
+var ErrInvalidEvidence = errors.New("invalid evidence")
+
+// DuplicateHeaderEvidence proves that aggregators equivocated, i.e. signed two different headers at the same height.
+//
+// Headers are ordered by hash, so evidence of the same equivocation has a single valid form.
+type DuplicateHeaderEvidence struct {
+	HeaderA SignedHeader `json:"header_a"`
+	HeaderB SignedHeader `json:"header_b"`
+}
+
+// NewDuplicateHeaderEvidence creates evidence of equivocation from two conflicting signed headers.
+func NewDuplicateHeaderEvidence(a, b *SignedHeader) (*DuplicateHeaderEvidence, error) {
+	if bytes.Compare(a.Hash(), b.Hash()) > 0 {
+		a, b = b, a
+	}
+	ev := &DuplicateHeaderEvidence{HeaderA: *a, HeaderB: *b}
+	if err := ev.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// Height returns height of the conflicting headers.
+func (ev *DuplicateHeaderEvidence) Height() uint64 {
+	return ev.HeaderA.Height()
+}
+
+// Hash returns hash of the evidence, derived from hashes of the conflicting headers.
+func (ev *DuplicateHeaderEvidence) Hash() Hash {
+	return tmhash.Sum(append(ev.HeaderA.Hash(), ev.HeaderB.Hash()...))
+}
+
+// ValidateBasic checks that both headers are validly signed headers of the same chain, height and aggregator set,
+// and that at least one aggregator signed both of them.
+func (ev *DuplicateHeaderEvidence) ValidateBasic() error {
+	a, b := &ev.HeaderA, &ev.HeaderB
+	if a.ChainID() != b.ChainID() {
+		return fmt.Errorf("%w: chain ID mismatch: %s != %s", ErrInvalidEvidence, a.ChainID(), b.ChainID())
+	}
+	if a.Height() != b.Height() {
+		return fmt.Errorf("%w: height mismatch: %d != %d", ErrInvalidEvidence, a.Height(), b.Height())
+	}
+	if bytes.Compare(a.Hash(), b.Hash()) >= 0 {
+		return fmt.Errorf("%w: headers are equal or not ordered by hash", ErrInvalidEvidence)
+	}
+	if a.Validators == nil || len(a.Validators.Validators) == 0 {
+		return fmt.Errorf("%w: no aggregator set", ErrInvalidEvidence)
+	}
+	if !bytes.Equal(a.AggregatorsHash, b.AggregatorsHash) {
+		return fmt.Errorf("%w: aggregator set mismatch", ErrInvalidEvidence)
+	}
+	if err := a.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: header A: %w", ErrInvalidEvidence, err)
+	}
+	if err := b.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: header B: %w", ErrInvalidEvidence, err)
+	}
+	if len(ev.Aggregators()) == 0 {
+		return fmt.Errorf("%w: no aggregator signed both headers", ErrInvalidEvidence)
+	}
+	return nil
+}
+
+// Aggregators returns aggregators that signed both headers.
+func (ev *DuplicateHeaderEvidence) Aggregators() []*cmtypes.Validator {
+	var vals []*cmtypes.Validator
+	for i, val := range ev.HeaderA.Validators.Validators {
+		if ev.HeaderA.signedBy(i) && ev.HeaderB.signedBy(i) {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// signedBy returns true if the commit contains signature of the i-th aggregator of the set.
+func (sh *SignedHeader) signedBy(i int) bool {
+	if len(sh.Commit.AggregatedSignature) > 0 {
+		return sh.Commit.Signed(i)
+	}
+	return i < len(sh.Commit.Signatures) && len(sh.Commit.Signatures[i]) > 0
+}
+
+// MarshalBinary encodes DuplicateHeaderEvidence into binary form and returns it.
+func (ev *DuplicateHeaderEvidence) MarshalBinary() ([]byte, error) {
+	p, err := ev.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return p.Marshal()
+}
+
+// UnmarshalBinary decodes binary form of DuplicateHeaderEvidence into object.
+func (ev *DuplicateHeaderEvidence) UnmarshalBinary(data []byte) error {
+	if err := checkSize("evidence", data, 2*MaxSignedHeaderSize); err != nil {
+		return err
+	}
+	var pEvidence pb.DuplicateHeaderEvidence
+	err := pEvidence.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return ev.FromProto(&pEvidence)
+}
+
+// ToProto converts DuplicateHeaderEvidence into protobuf representation and returns it.
+func (ev *DuplicateHeaderEvidence) ToProto() (*pb.DuplicateHeaderEvidence, error) {
+	a, err := ev.HeaderA.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	b, err := ev.HeaderB.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DuplicateHeaderEvidence{HeaderA: a, HeaderB: b}, nil
+}
+
+// FromProto fills DuplicateHeaderEvidence with data from its protobuf representation.
+func (ev *DuplicateHeaderEvidence) FromProto(other *pb.DuplicateHeaderEvidence) error {
+	if other == nil || other.HeaderA == nil || other.HeaderB == nil {
+		return fmt.Errorf("%w: missing header", ErrInvalidEvidence)
+	}
+	if err := ev.HeaderA.FromProto(other.HeaderA); err != nil {
+		return err
+	}
+	return ev.HeaderB.FromProto(other.HeaderB)
+}
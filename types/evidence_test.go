@@ -0,0 +1,84 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateHeaderEvidence(t *testing.T) {
+	require := require.New(t)
+
+	signedHeader, privKey, err := GetRandomSignedHeader()
+	require.NoError(err)
+	conflicting := *signedHeader
+	conflicting.AppHash = GetRandomBytes(32)
+	commit, err := getCommit(conflicting.Header, privKey)
+	require.NoError(err)
+	conflicting.Commit = *commit
+
+	t.Run("valid", func(t *testing.T) {
+		assert := assert.New(t)
+		ev, err := NewDuplicateHeaderEvidence(signedHeader, &conflicting)
+		require.NoError(err)
+		assert.Equal(signedHeader.Height(), ev.Height())
+		assert.Len(ev.Aggregators(), 1)
+		assert.Equal(signedHeader.ProposerAddress, []byte(ev.Aggregators()[0].Address))
+
+		// evidence doesn't depend on order of headers
+		reversed, err := NewDuplicateHeaderEvidence(&conflicting, signedHeader)
+		require.NoError(err)
+		assert.Equal(ev.Hash(), reversed.Hash())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		nextHeader, err := GetRandomNextSignedHeader(signedHeader, privKey)
+		require.NoError(err)
+		other, _, err := GetRandomSignedHeader()
+		require.NoError(err)
+		other.BaseHeader = signedHeader.BaseHeader
+		forged := conflicting
+		forged.Commit = Commit{Signatures: []Signature{GetRandomBytes(64)}}
+
+		cases := []struct {
+			name string
+			a, b *SignedHeader
+		}{
+			{"same header", signedHeader, signedHeader},
+			{"different heights", signedHeader, nextHeader},
+			{"different aggregator sets", signedHeader, other},
+			{"invalid signature", signedHeader, &forged},
+		}
+		for _, c := range cases {
+			_, err := NewDuplicateHeaderEvidence(c.a, c.b)
+			assert.ErrorIs(t, err, ErrInvalidEvidence, c.name)
+		}
+	})
+
+	t.Run("block inclusion", func(t *testing.T) {
+		assert := assert.New(t)
+		ev, err := NewDuplicateHeaderEvidence(signedHeader, &conflicting)
+		require.NoError(err)
+
+		data := &Data{Txs: Txs{Tx("tx")}}
+		hashWithoutEvidence, err := data.Hash()
+		require.NoError(err)
+		data.Evidence.Evidence = []DuplicateHeaderEvidence{*ev}
+		require.NoError(data.ValidateBasic())
+		hash, err := data.Hash()
+		require.NoError(err)
+		assert.NotEqual(hashWithoutEvidence, hash)
+
+		encoded, err := data.MarshalBinary()
+		require.NoError(err)
+		var decoded Data
+		require.NoError(decoded.UnmarshalBinary(encoded))
+		require.Len(decoded.Evidence.Evidence, 1)
+		assert.Equal(ev.Hash(), decoded.Evidence.Evidence[0].Hash())
+		assert.NoError(decoded.ValidateBasic())
+
+		data.Evidence.Evidence[0].HeaderB = data.Evidence.Evidence[0].HeaderA
+		assert.ErrorIs(data.ValidateBasic(), ErrInvalidEvidence)
+	})
+}
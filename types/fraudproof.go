@@ -0,0 +1,115 @@
+package types
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+)
+
+// StateWitness proves a single key/value pair accessed by a transaction.
+// Proof is opaque to Rollkit and is interpreted by the application.
+type StateWitness struct {
+	Key   []byte
+	Value []byte
+	Proof []byte
+}
+
+// StateFraudProof proves that the intermediate state root committed after a transaction is invalid.
+//
+// It contains everything required to re-execute the disputed transaction without access to full state:
+// the pre-state root, the transaction itself and witnesses for all the state accessed by the transaction.
+type StateFraudProof struct {
+	BlockHeight uint64
+	// TxIndex is the index of the disputed transaction in block data.
+	// Post-state root of the transaction is at ISR index TxIndex+1.
+	TxIndex                uint64
+	PreStateRoot           []byte
+	Tx                     Tx
+	CommittedPostStateRoot []byte
+	ExpectedPostStateRoot  []byte
+	Witnesses              []StateWitness
+
+	// NumTxs is the number of transactions in the block. Intermediate state roots follow transactions in
+	// the Merkle tree of block data (see Data.Hash), so the pre-state root is the leaf NumTxs+TxIndex.
+	NumTxs uint64
+	// TxProof, PreStateRootProof and PostStateRootProof prove inclusion of Tx, PreStateRoot and
+	// CommittedPostStateRoot in DataHash of the block, so that nodes without block data are able to verify
+	// the proof against a trusted header (see VerifyInclusion).
+	TxProof            merkle.Proof
+	PreStateRootProof  merkle.Proof
+	PostStateRootProof merkle.Proof
+}
+
+var _ encoding.BinaryMarshaler = &StateFraudProof{}
+var _ encoding.BinaryUnmarshaler = &StateFraudProof{}
+
+// ValidateBasic performs basic validation of a state fraud proof.
+func (fp *StateFraudProof) ValidateBasic() error {
+	if fp.BlockHeight == 0 {
+		return errors.New("fraud proof for block at height 0")
+	}
+	if len(fp.Tx) == 0 {
+		return errors.New("fraud proof without transaction")
+	}
+	if len(fp.PreStateRoot) == 0 || len(fp.CommittedPostStateRoot) == 0 {
+		return errors.New("fraud proof without state roots")
+	}
+	return nil
+}
+
+// AddInclusionProofs sets NumTxs and Merkle proofs of inclusion of the disputed transaction and state roots in
+// the data of the block, computed with the hasher of the chain.
+func (fp *StateFraudProof) AddInclusionProofs(data *Data, hasher MerkleHasher) error {
+	if err := hasher.Validate(); err != nil {
+		return err
+	}
+	numTxs := uint64(len(data.Txs))
+	leaves := data.leaves(hasher)
+	if fp.TxIndex >= numTxs || numTxs+fp.TxIndex+1 >= uint64(len(leaves)) {
+		return fmt.Errorf("transaction index %d out of range", fp.TxIndex)
+	}
+	_, proofs := hasher.Proofs(leaves)
+	fp.NumTxs = numTxs
+	fp.TxProof = *proofs[fp.TxIndex]
+	fp.PreStateRootProof = *proofs[numTxs+fp.TxIndex]
+	fp.PostStateRootProof = *proofs[numTxs+fp.TxIndex+1]
+	return nil
+}
+
+// VerifyInclusion returns an error if the disputed transaction and state roots of the proof are not included at
+// their positions in the data of the block with given DataHash, computed with the hasher of the chain.
+func (fp *StateFraudProof) VerifyInclusion(dataHash Hash, hasher MerkleHasher) error {
+	if err := hasher.Validate(); err != nil {
+		return err
+	}
+	if fp.TxIndex >= fp.NumTxs {
+		return fmt.Errorf("transaction index %d out of range [0, %d)", fp.TxIndex, fp.NumTxs)
+	}
+	// every block has an intermediate state root before its first and after every transaction
+	total := fp.TxProof.Total
+	if total < 0 || uint64(total) < 2*fp.NumTxs+1 || fp.PreStateRootProof.Total != total || fp.PostStateRootProof.Total != total {
+		return fmt.Errorf("%w: inconsistent number of leaves", ErrInvalidMerkleProof)
+	}
+	if uint64(fp.TxProof.Index) != fp.TxIndex ||
+		uint64(fp.PreStateRootProof.Index) != fp.NumTxs+fp.TxIndex ||
+		uint64(fp.PostStateRootProof.Index) != fp.NumTxs+fp.TxIndex+1 {
+		return fmt.Errorf("%w: unexpected leaf index", ErrInvalidMerkleProof)
+	}
+	leaves := []struct {
+		name  string
+		proof merkle.Proof
+		data  []byte
+	}{
+		{"transaction", fp.TxProof, fp.Tx},
+		{"pre-state root", fp.PreStateRootProof, fp.PreStateRoot},
+		{"committed post-state root", fp.PostStateRootProof, fp.CommittedPostStateRoot},
+	}
+	for _, l := range leaves {
+		if err := hasher.VerifyProof(l.proof, dataHash, hasher.Sum(l.data)); err != nil {
+			return fmt.Errorf("%s: %w", l.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+)
+
+// GenesisAppStateKey is the key of rollkit section in application state of the genesis document.
+// Applications ignore sections of modules they don't know, so the section can be added to any genesis.
+const GenesisAppStateKey = "rollkit"
+
+// ErrInvalidGenesisDAParams is returned when rollkit section of the genesis document is malformed.
+var ErrInvalidGenesisDAParams = errors.New("invalid DA parameters in genesis")
+
+// GenesisDAParams are parameters of DA layer shared by all nodes of the chain. They are defined in genesis, so that
+// all nodes agree where the data of the chain is published.
+type GenesisDAParams struct {
+	// NamespaceID is the namespace of blocks of the chain on DA layer.
+	NamespaceID NamespaceID
+	// DAChainID is the chain ID of DA layer (for example "mocha-4"). Empty value matches any DA chain.
+	DAChainID string
+}
+
+// genesisDAParams is JSON representation of GenesisDAParams.
+type genesisDAParams struct {
+	NamespaceID string `json:"da_namespace_id"`
+	DAChainID   string `json:"da_chain_id,omitempty"`
+}
+
+// genesisDataHash is JSON representation of the hash function of DataHash.
+type genesisDataHash struct {
+	DataHash string `json:"data_hash"`
+}
+
+// DAParamsFromGenesis reads DA parameters from rollkit section of application state in the genesis document, for
+// example:
+//
+//	"app_state": {"rollkit": {"da_namespace_id": "000000000000ffff", "da_chain_id": "mocha-4"}}
+//
+// It returns nil if genesis doesn't define DA parameters.
+func DAParamsFromGenesis(genesis *cmtypes.GenesisDoc) (*GenesisDAParams, error) {
+	var params genesisDAParams
+	if ok, err := readGenesisSection(genesis, &params); !ok || err != nil {
+		return nil, err
+	}
+	if params == (genesisDAParams{}) {
+		return nil, nil
+	}
+	var namespaceID NamespaceID
+	nsBytes, err := hex.DecodeString(params.NamespaceID)
+	if err != nil || len(nsBytes) != len(namespaceID) {
+		return nil, fmt.Errorf("%w: namespace ID %q is not %d hex encoded bytes", ErrInvalidGenesisDAParams,
+			params.NamespaceID, len(namespaceID))
+	}
+	copy(namespaceID[:], nsBytes)
+	return &GenesisDAParams{NamespaceID: namespaceID, DAChainID: params.DAChainID}, nil
+}
+
+// DataHasherFromGenesis returns MerkleHasher computing DataHash of blocks of the chain, using the hash function
+// named in rollkit section of application state in the genesis document, for example:
+//
+//	"app_state": {"rollkit": {"data_hash": "keccak256"}}
+//
+// It returns DefaultMerkleHasher if genesis doesn't define the hash function. The hasher is passed to components
+// hashing and validating blocks of the chain, so all nodes of the chain use the same one.
+func DataHasherFromGenesis(genesis *cmtypes.GenesisDoc) (MerkleHasher, error) {
+	var params genesisDataHash
+	if _, err := readGenesisSection(genesis, &params); err != nil {
+		return MerkleHasher{}, err
+	}
+	hasher, err := GetDataHasher(params.DataHash)
+	if err != nil {
+		return MerkleHasher{}, fmt.Errorf("%w: %w", ErrInvalidGenesisDAParams, err)
+	}
+	return hasher, nil
+}
+
+// readGenesisSection unmarshals rollkit section of application state into v. It returns false if genesis doesn't
+// have the section.
+func readGenesisSection(genesis *cmtypes.GenesisDoc, v interface{}) (bool, error) {
+	if len(genesis.AppState) == 0 {
+		return false, nil
+	}
+	var appState map[string]json.RawMessage
+	if err := json.Unmarshal(genesis.AppState, &appState); err != nil {
+		// application state is not a JSON object, so it can't have rollkit section
+		return false, nil
+	}
+	raw, ok := appState[GenesisAppStateKey]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("%w: %w", ErrInvalidGenesisDAParams, err)
+	}
+	return true, nil
+}
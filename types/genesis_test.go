@@ -0,0 +1,73 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDAParamsFromGenesis(t *testing.T) {
+	cases := []struct {
+		name     string
+		appState string
+		expected *GenesisDAParams
+		err      error
+	}{
+		{"no app state", "", nil, nil},
+		{"no rollkit section", `{"bank": {}}`, nil, nil},
+		{"not an object", `"state"`, nil, nil},
+		{"namespace only", `{"rollkit": {"da_namespace_id": "000000000000ffff"}}`,
+			&GenesisDAParams{NamespaceID: NamespaceID{0, 0, 0, 0, 0, 0, 0xff, 0xff}}, nil},
+		{"namespace and chain ID", `{"bank": {}, "rollkit": {"da_namespace_id": "0102030405060708", "da_chain_id": "mocha-4"}}`,
+			&GenesisDAParams{NamespaceID: NamespaceID{1, 2, 3, 4, 5, 6, 7, 8}, DAChainID: "mocha-4"}, nil},
+		{"missing namespace", `{"rollkit": {"da_chain_id": "mocha-4"}}`, nil, ErrInvalidGenesisDAParams},
+		{"short namespace", `{"rollkit": {"da_namespace_id": "ffff"}}`, nil, ErrInvalidGenesisDAParams},
+		{"invalid hex", `{"rollkit": {"da_namespace_id": "zz00000000000000"}}`, nil, ErrInvalidGenesisDAParams},
+		{"malformed section", `{"rollkit": []}`, nil, ErrInvalidGenesisDAParams},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			genesis := &cmtypes.GenesisDoc{ChainID: "test", AppState: json.RawMessage(c.appState)}
+			params, err := DAParamsFromGenesis(genesis)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, params)
+		})
+	}
+}
+
+func TestDataHasherFromGenesis(t *testing.T) {
+	cases := []struct {
+		name     string
+		appState string
+		expected string
+		err      error
+	}{
+		{"no app state", "", DataHashSHA256, nil},
+		{"no rollkit section", `{"bank": {}}`, DataHashSHA256, nil},
+		{"no data hash", `{"rollkit": {"da_namespace_id": "000000000000ffff"}}`, DataHashSHA256, nil},
+		{"keccak256", `{"rollkit": {"data_hash": "keccak256"}}`, DataHashKeccak256, nil},
+		{"unknown hash", `{"rollkit": {"data_hash": "md5"}}`, "", ErrUnknownDataHash},
+		{"malformed section", `{"rollkit": {"data_hash": 1}}`, "", ErrInvalidGenesisDAParams},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			genesis := &cmtypes.GenesisDoc{ChainID: "test", AppState: json.RawMessage(c.appState)}
+			hasher, err := DataHasherFromGenesis(genesis)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, hasher.Name)
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package types
 
 import (
+	"fmt"
+
 	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/tmhash"
 	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	cmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	cmversion "github.com/cometbft/cometbft/proto/tendermint/version"
 	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/crypto/bls"
 )
 
 // Hash returns ABCI-compatible hash of a header.
@@ -42,13 +49,96 @@ func (b *Block) Hash() Hash {
 	return b.SignedHeader.Hash()
 }
 
-// Hash returns hash of the Data
+// Hash returns hash of the Data, i.e. Merkle root of transaction hashes followed by intermediate state root hashes
+// and evidence hashes, computed with DefaultMerkleHasher. Without intermediate state roots and evidence, it's equal
+// to the ABCI hash of transactions.
 func (d *Data) Hash() (Hash, error) {
-	dBytes, err := d.MarshalBinary()
-	if err != nil {
+	return d.HashWith(DefaultMerkleHasher)
+}
+
+// HashWith returns hash of the Data computed with the hasher of the chain (see DataHasherFromGenesis).
+func (d *Data) HashWith(hasher MerkleHasher) (Hash, error) {
+	if err := hasher.Validate(); err != nil {
 		return nil, err
 	}
-	return merkle.HashFromByteSlices([][]byte{
-		dBytes,
-	}), nil
+	return hasher.Root(d.leaves(hasher)), nil
+}
+
+// TxProof returns Merkle proof of inclusion of i-th transaction in the Data, verifiable against DataHash of the block
+// computed with DefaultMerkleHasher.
+func (d *Data) TxProof(i int) (TxProof, error) {
+	return d.TxProofWith(i, DefaultMerkleHasher)
+}
+
+// TxProofWith returns Merkle proof of inclusion of i-th transaction in the Data, verifiable against DataHash of
+// the block computed with the hasher.
+func (d *Data) TxProofWith(i int, hasher MerkleHasher) (TxProof, error) {
+	if i < 0 || i >= len(d.Txs) {
+		return TxProof{}, fmt.Errorf("transaction index %d out of range [0, %d)", i, len(d.Txs))
+	}
+	if err := hasher.Validate(); err != nil {
+		return TxProof{}, err
+	}
+	root, proofs := hasher.Proofs(d.leaves(hasher))
+	return TxProof{
+		RootHash: root,
+		Data:     d.Txs[i],
+		Proof:    *proofs[i],
+	}, nil
+}
+
+// leaves returns hashes of transactions, intermediate state roots and evidence, used as leaves of the Merkle tree.
+func (d *Data) leaves(hasher MerkleHasher) [][]byte {
+	leaves := make([][]byte, 0, len(d.Txs)+len(d.IntermediateStateRoots.RawRootsList)+len(d.Evidence.Evidence))
+	for _, tx := range d.Txs {
+		leaves = append(leaves, hasher.Sum(tx))
+	}
+	for _, isr := range d.IntermediateStateRoots.RawRootsList {
+		leaves = append(leaves, hasher.Sum(isr))
+	}
+	for i := range d.Evidence.Evidence {
+		leaves = append(leaves, d.Evidence.Evidence[i].Hash())
+	}
+	return leaves
+}
+
+// DataHash returns hash of the data of the block, computed with the hasher of its chain (see
+// DataHasherFromGenesis).
+func (b *Block) DataHash(hasher MerkleHasher) (Hash, error) {
+	return b.Data.HashWith(hasher)
+}
+
+// ValidityProofHash returns hash of the validity proof, used as commitment in the Header.
+func ValidityProofHash(proof []byte) Hash {
+	return tmhash.Sum(proof)
+}
+
+// AggregatorKeysHash returns hash of BLS public keys of aggregators, used as commitment in the Header.
+func AggregatorKeysHash(keys []bls.PubKey) Hash {
+	leaves := make([][]byte, len(keys))
+	for i, key := range keys {
+		leaves[i] = key
+	}
+	return merkle.HashFromByteSlices(leaves)
+}
+
+// ConsensusParamsHash returns ABCI-compatible hash of consensus parameters, used as ConsensusHash in the Header.
+func ConsensusParamsHash(params cmproto.ConsensusParams) Hash {
+	var hashed cmtypes.ConsensusParams
+	if params.Block != nil {
+		hashed.Block = cmtypes.BlockParams{
+			MaxBytes: params.Block.MaxBytes,
+			MaxGas:   params.Block.MaxGas,
+		}
+	}
+	return hashed.Hash()
+}
+
+// GenesisHash returns hash of the genesis document, identifying the network together with the chain ID.
+func GenesisHash(genesis *cmtypes.GenesisDoc) (Hash, error) {
+	data, err := cmtjson.Marshal(genesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+	}
+	return tmhash.Sum(data), nil
 }
@@ -3,6 +3,7 @@ package types
 import (
 	"bytes"
 	"encoding"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,6 +23,24 @@ type BaseHeader struct {
 	ChainID string
 }
 
+const (
+	// MaxHeaderExtensions is the maximal number of extensions in a header.
+	MaxHeaderExtensions = 16
+	// MaxHeaderExtensionSize is the maximal size of data of a single header extension.
+	MaxHeaderExtensionSize = 1024
+)
+
+// ErrInvalidHeaderExtension is returned when header extensions are malformed or exceed the limits.
+var ErrInvalidHeaderExtension = errors.New("invalid header extension")
+
+// HeaderExtension is a typed entry of application data committed in the header, e.g. a bridge root or
+// a commitment to a validity proof. Extensions are covered by signatures of the header.
+type HeaderExtension struct {
+	// Type identifies the extension, it's unique within the header.
+	Type string
+	Data []byte
+}
+
 // Header defines the structure of Rollkit block header.
 type Header struct {
 	BaseHeader
@@ -53,6 +72,21 @@ type Header struct {
 
 	// Hash of next block aggregator set, at a time of block creation
 	NextAggregatorsHash Hash
+
+	// Hash of the validity proof of the state transition, carried in the Commit.
+	// Empty if validity proofs are not enabled.
+	ValidityProofHash Hash
+
+	// Hash of BLS public keys of aggregators, carried in the SignedHeader.
+	// Empty if aggregators don't use BLS signatures.
+	AggregatorKeysHash Hash
+
+	// Version of binary encoding of the header (see EncodingVersion).
+	// Zero for headers encoded before encoding versioning was introduced.
+	EncodingVersion uint32
+
+	// Extensions committing to application data, provided by the application when the block is produced.
+	Extensions []HeaderExtension
 }
 
 // New creates a new Header.
@@ -98,6 +132,15 @@ func (h *Header) Verify(untrstH *Header) error {
 				),
 			}
 		}
+		// BLS keys of aggregators can't be changed
+		if !bytes.Equal(untrstH.AggregatorKeysHash[:], h.AggregatorKeysHash[:]) {
+			return &header.VerifyError{
+				Reason: fmt.Errorf("expected old header aggregator keys (%X) to match those from new header (%X)",
+					h.AggregatorKeysHash,
+					untrstH.AggregatorKeysHash,
+				),
+			}
+		}
 	}
 
 	// TODO: There must be a way to verify non-adjacent headers
@@ -121,6 +164,37 @@ func (h *Header) ValidateBasic() error {
 		return ErrNoProposerAddress
 	}
 
+	return ValidateHeaderExtensions(h.Extensions)
+}
+
+// Extension returns data of the header extension of given type.
+func (h *Header) Extension(typ string) ([]byte, bool) {
+	for _, ext := range h.Extensions {
+		if ext.Type == typ {
+			return ext.Data, true
+		}
+	}
+	return nil, false
+}
+
+// ValidateHeaderExtensions checks that extensions have unique, non-empty types, and don't exceed the limits.
+func ValidateHeaderExtensions(extensions []HeaderExtension) error {
+	if len(extensions) > MaxHeaderExtensions {
+		return fmt.Errorf("%w: %d extensions, maximum is %d", ErrInvalidHeaderExtension, len(extensions), MaxHeaderExtensions)
+	}
+	types := make(map[string]struct{}, len(extensions))
+	for _, ext := range extensions {
+		if ext.Type == "" {
+			return fmt.Errorf("%w: empty type", ErrInvalidHeaderExtension)
+		}
+		if _, ok := types[ext.Type]; ok {
+			return fmt.Errorf("%w: duplicated type %s", ErrInvalidHeaderExtension, ext.Type)
+		}
+		types[ext.Type] = struct{}{}
+		if len(ext.Data) > MaxHeaderExtensionSize {
+			return fmt.Errorf("%w: %s has %d bytes, maximum is %d", ErrInvalidHeaderExtension, ext.Type, len(ext.Data), MaxHeaderExtensionSize)
+		}
+	}
 	return nil
 }
 
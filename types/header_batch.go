@@ -0,0 +1,87 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxHeaderBatchHeaders is the maximal number of headers in a HeaderBatch.
+	MaxHeaderBatchHeaders = 100
+	// MaxHeaderBatchSize is the maximal size of binary encoding of a HeaderBatch, equal to the default limit of
+	// gossipsub messages.
+	MaxHeaderBatchSize = 1024 * 1024
+)
+
+// ErrInvalidHeaderBatch is returned when headers of the batch are not consecutive, or the batch exceeds its limits.
+var ErrInvalidHeaderBatch = errors.New("invalid header batch")
+
+// HeaderBatch is a sequence of consecutive signed headers, gossiped in a single message when the aggregator
+// produces blocks faster than they are propagated.
+type HeaderBatch []*SignedHeader
+
+// ValidateBasic checks that the batch is not empty, that its headers are consecutive (each one links to the hash
+// of the previous one) and that every header is valid.
+func (b HeaderBatch) ValidateBasic() error {
+	if len(b) == 0 || len(b) > MaxHeaderBatchHeaders {
+		return fmt.Errorf("%w: %d headers, expected 1 to %d", ErrInvalidHeaderBatch, len(b), MaxHeaderBatchHeaders)
+	}
+	for i, sh := range b {
+		if err := sh.ValidateBasic(); err != nil {
+			return fmt.Errorf("%w: header %d: %w", ErrInvalidHeaderBatch, sh.Height(), err)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := b[i-1]
+		if sh.Height() != prev.Height()+1 || sh.ChainID() != prev.ChainID() {
+			return fmt.Errorf("%w: header %d follows header %d", ErrInvalidHeaderBatch, sh.Height(), prev.Height())
+		}
+		if !bytes.Equal(sh.LastHeaderHash, prev.Hash()) {
+			return fmt.Errorf("%w: %w at height %d", ErrInvalidHeaderBatch, ErrLastHeaderHashMismatch, sh.Height())
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the batch as a sequence of binary encodings of headers, each one prefixed with its length.
+func (b HeaderBatch) MarshalBinary() ([]byte, error) {
+	var buf []byte
+	for _, sh := range b {
+		data, err := sh.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes binary form of the batch.
+func (b *HeaderBatch) UnmarshalBinary(data []byte) error {
+	if err := checkSize("header batch", data, MaxHeaderBatchSize); err != nil {
+		return err
+	}
+	var batch HeaderBatch
+	for len(data) > 0 {
+		if err := checkCount("headers in batch", len(batch)+1, MaxHeaderBatchHeaders); err != nil {
+			return err
+		}
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return fmt.Errorf("%w: truncated header batch", ErrMalformedEncoding)
+		}
+		data = data[n:]
+		sh := new(SignedHeader)
+		if err := sh.UnmarshalBinary(data[:size]); err != nil {
+			return err
+		}
+		batch = append(batch, sh)
+		data = data[size:]
+	}
+	*b = batch
+	return nil
+}
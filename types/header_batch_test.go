@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderBatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	g := NewGenerator(1)
+	sh, keys, err := g.SignedHeader()
+	require.NoError(err)
+	batch := HeaderBatch{sh}
+	for i := 0; i < 3; i++ {
+		sh, err = g.NextSignedHeader(sh, keys)
+		require.NoError(err)
+		batch = append(batch, sh)
+	}
+	require.NoError(batch.ValidateBasic())
+
+	data, err := batch.MarshalBinary()
+	require.NoError(err)
+	var decoded HeaderBatch
+	require.NoError(decoded.UnmarshalBinary(data))
+	require.Len(decoded, len(batch))
+	for i := range batch {
+		assert.Equal(batch[i].Hash(), decoded[i].Hash())
+	}
+	assert.NoError(decoded.ValidateBasic())
+
+	assert.ErrorIs(HeaderBatch{}.ValidateBasic(), ErrInvalidHeaderBatch)
+	assert.ErrorIs(HeaderBatch{batch[0], batch[2]}.ValidateBasic(), ErrInvalidHeaderBatch)
+	assert.ErrorIs(HeaderBatch{batch[1], batch[0]}.ValidateBasic(), ErrInvalidHeaderBatch)
+
+	// header 3 is not linked to the fork of header 2
+	fork, err := NewGenerator(2).NextSignedHeader(batch[0], keys)
+	require.NoError(err)
+	require.NoError(HeaderBatch{batch[0], fork}.ValidateBasic())
+	assert.ErrorIs(HeaderBatch{batch[0], fork, batch[2]}.ValidateBasic(), ErrLastHeaderHashMismatch)
+
+	assert.ErrorIs(decoded.UnmarshalBinary(data[:len(data)-1]), ErrMalformedEncoding)
+}
@@ -0,0 +1,237 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cmcrypto "github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/signer"
+)
+
+// inclusionListDomain separates signatures of inclusion lists from signatures of headers made with the same key.
+const inclusionListDomain = "rollkit/inclusion-list/v1"
+
+const (
+	// MaxInclusionListTxs is the maximal number of transactions in an inclusion list.
+	MaxInclusionListTxs = 256
+	// MaxInclusionListDeadline is the maximal number of blocks, within which transactions of an inclusion list
+	// have to be included.
+	MaxInclusionListDeadline = 64
+	// MaxInclusionListSize is the maximal size of binary encoding of an inclusion list.
+	MaxInclusionListSize = 64 * 1024
+)
+
+// ErrInvalidInclusionList is returned when inclusion list is malformed or not signed by the proposer.
+var ErrInvalidInclusionList = errors.New("invalid inclusion list")
+
+// InclusionList requires the sequencer to include transactions with given hashes in one of Deadline blocks
+// following the block at Height. Hashes of transactions are computed with the hash function of DataHash of the chain
+// (see DataHasherFromGenesis), so with the default SHA-256 they are equal to ABCI hashes of transactions.
+//
+// Inclusion lists are submitted by full nodes to detect censorship. The proposer signs inclusion lists it accepts:
+// signed list, together with headers of blocks within the deadline that don't include its transactions, proves
+// that the proposer broke the promise (see InclusionListViolation).
+type InclusionList struct {
+	ChainID         string             `json:"chain_id"`
+	Height          uint64             `json:"height"`
+	Deadline        uint64             `json:"deadline"`
+	TxHashes        []cmbytes.HexBytes `json:"tx_hashes"`
+	ProposerAddress cmbytes.HexBytes   `json:"proposer_address,omitempty"`
+	Signature       Signature          `json:"signature,omitempty"`
+}
+
+// LastHeight returns the height of the last block, that can include transactions of the list.
+func (l *InclusionList) LastHeight() uint64 {
+	return l.Height + l.Deadline
+}
+
+// Signed returns true if the list is signed by the proposer.
+func (l *InclusionList) Signed() bool {
+	return len(l.Signature) > 0
+}
+
+// SignBytes returns the message signed by the proposer.
+func (l *InclusionList) SignBytes() []byte {
+	buf := make([]byte, 0, len(inclusionListDomain)+len(l.ChainID)+24+len(l.TxHashes)*(tmhash.Size+8))
+	buf = append(buf, inclusionListDomain...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(l.ChainID)))
+	buf = append(buf, l.ChainID...)
+	buf = binary.BigEndian.AppendUint64(buf, l.Height)
+	buf = binary.BigEndian.AppendUint64(buf, l.Deadline)
+	for _, hash := range l.TxHashes {
+		buf = binary.BigEndian.AppendUint64(buf, uint64(len(hash)))
+		buf = append(buf, hash...)
+	}
+	return buf
+}
+
+// Hash returns hash of the list, that doesn't depend on the signature of the proposer.
+func (l *InclusionList) Hash() Hash {
+	return tmhash.Sum(l.SignBytes())
+}
+
+// ValidateBasic performs basic validation of the list, without verification of the signature.
+func (l *InclusionList) ValidateBasic() error {
+	if l.ChainID == "" {
+		return fmt.Errorf("%w: empty chain ID", ErrInvalidInclusionList)
+	}
+	if l.Deadline == 0 || l.Deadline > MaxInclusionListDeadline {
+		return fmt.Errorf("%w: deadline %d out of range [1, %d]", ErrInvalidInclusionList, l.Deadline, MaxInclusionListDeadline)
+	}
+	if len(l.TxHashes) == 0 || len(l.TxHashes) > MaxInclusionListTxs {
+		return fmt.Errorf("%w: %d transactions, expected [1, %d]", ErrInvalidInclusionList, len(l.TxHashes), MaxInclusionListTxs)
+	}
+	seen := make(map[string]struct{}, len(l.TxHashes))
+	for _, hash := range l.TxHashes {
+		if len(hash) == 0 || len(hash) > MaxHashSize {
+			return fmt.Errorf("%w: invalid tx hash size %d", ErrInvalidInclusionList, len(hash))
+		}
+		if _, ok := seen[string(hash)]; ok {
+			return fmt.Errorf("%w: duplicate tx hash %X", ErrInvalidInclusionList, []byte(hash))
+		}
+		seen[string(hash)] = struct{}{}
+	}
+	if l.Signed() != (len(l.ProposerAddress) > 0) {
+		return fmt.Errorf("%w: signature without proposer address or vice versa", ErrInvalidInclusionList)
+	}
+	return nil
+}
+
+// Verify checks that the list is signed by the proposer with given public key.
+func (l *InclusionList) Verify(pubKey cmcrypto.PubKey) error {
+	if err := l.ValidateBasic(); err != nil {
+		return err
+	}
+	if !l.Signed() {
+		return fmt.Errorf("%w: no signature", ErrInvalidInclusionList)
+	}
+	if !bytes.Equal(l.ProposerAddress, pubKey.Address()) {
+		return fmt.Errorf("%w: proposer address doesn't match the public key", ErrInvalidInclusionList)
+	}
+	if !signer.VerifySignature(pubKey, l.SignBytes(), l.Signature) {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidInclusionList)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the list into binary form (JSON) and returns it.
+func (l *InclusionList) MarshalBinary() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// UnmarshalBinary decodes binary form of the list.
+func (l *InclusionList) UnmarshalBinary(data []byte) error {
+	if err := checkSize("inclusion list", data, MaxInclusionListSize); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, l)
+}
+
+// InclusionListViolation proves that the proposer broke the promise to include transactions of the signed
+// inclusion list: none of the blocks within the deadline, all proposed by the signer of the list, include some of
+// the transactions. Leaves of DataHash (hashes of transactions, intermediate state roots and evidence) are used
+// instead of data of blocks, to keep the proof small.
+type InclusionListViolation struct {
+	List    InclusionList        `json:"list"`
+	Headers []SignedHeader       `json:"headers"`
+	Leaves  [][]cmbytes.HexBytes `json:"leaves"`
+}
+
+// NewInclusionListViolation creates proof of violation of the signed list, from blocks within its deadline
+// (ordered by height). Hashes are computed with the hasher of DataHash of the chain.
+func NewInclusionListViolation(list *InclusionList, blocks []*Block, hasher MerkleHasher) (*InclusionListViolation, error) {
+	if err := hasher.Validate(); err != nil {
+		return nil, err
+	}
+	v := &InclusionListViolation{List: *list}
+	for _, b := range blocks {
+		v.Headers = append(v.Headers, b.SignedHeader)
+		leaves := b.Data.leaves(hasher)
+		hexLeaves := make([]cmbytes.HexBytes, len(leaves))
+		for i := range leaves {
+			hexLeaves[i] = leaves[i]
+		}
+		v.Leaves = append(v.Leaves, hexLeaves)
+	}
+	if err := v.Verify(hasher); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Missing returns hashes of transactions of the list, that are not included in any of the blocks.
+func (v *InclusionListViolation) Missing() []cmbytes.HexBytes {
+	included := make(map[string]struct{})
+	for _, leaves := range v.Leaves {
+		for _, leaf := range leaves {
+			included[string(leaf)] = struct{}{}
+		}
+	}
+	var missing []cmbytes.HexBytes
+	for _, hash := range v.List.TxHashes {
+		if _, ok := included[string(hash)]; !ok {
+			missing = append(missing, hash)
+		}
+	}
+	return missing
+}
+
+// Verify checks that the list is signed by the proposer of all blocks within its deadline, that headers are
+// validly signed consecutive headers of the chain with DataHash computed from the leaves, and that some of the
+// transactions of the list are missing.
+func (v *InclusionListViolation) Verify(hasher MerkleHasher) error {
+	if err := hasher.Validate(); err != nil {
+		return err
+	}
+	list := &v.List
+	if err := list.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEvidence, err)
+	}
+	if uint64(len(v.Headers)) != list.Deadline || len(v.Leaves) != len(v.Headers) {
+		return fmt.Errorf("%w: %d headers and %d leaves for deadline %d", ErrInvalidEvidence, len(v.Headers), len(v.Leaves), list.Deadline)
+	}
+	for i := range v.Headers {
+		sh := &v.Headers[i]
+		if sh.ChainID() != list.ChainID || sh.Height() != list.Height+uint64(i)+1 {
+			return fmt.Errorf("%w: unexpected header %s/%d", ErrInvalidEvidence, sh.ChainID(), sh.Height())
+		}
+		if i > 0 && !bytes.Equal(sh.LastHeaderHash, v.Headers[i-1].Hash()) {
+			return fmt.Errorf("%w: header %d doesn't link to the previous header", ErrInvalidEvidence, sh.Height())
+		}
+		if err := sh.ValidateBasic(); err != nil {
+			return fmt.Errorf("%w: header %d: %w", ErrInvalidEvidence, sh.Height(), err)
+		}
+		if !bytes.Equal(sh.ProposerAddress, list.ProposerAddress) {
+			return fmt.Errorf("%w: block %d proposed by %X, list signed by %X", ErrInvalidEvidence, sh.Height(),
+				[]byte(sh.ProposerAddress), []byte(list.ProposerAddress))
+		}
+		leaves := make([][]byte, len(v.Leaves[i]))
+		for j := range leaves {
+			leaves[j] = v.Leaves[i][j]
+		}
+		if !bytes.Equal(hasher.Root(leaves), sh.DataHash) {
+			return fmt.Errorf("%w: leaves don't match data hash of block %d", ErrInvalidEvidence, sh.Height())
+		}
+	}
+	var proposer *cmtypes.Validator
+	if vals := v.Headers[0].Validators; vals != nil {
+		_, proposer = vals.GetByAddress(list.ProposerAddress)
+	}
+	if proposer == nil {
+		return fmt.Errorf("%w: list signed by unknown aggregator %X", ErrInvalidEvidence, []byte(list.ProposerAddress))
+	}
+	if err := list.Verify(proposer.PubKey); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEvidence, err)
+	}
+	if len(v.Missing()) == 0 {
+		return fmt.Errorf("%w: all transactions are included", ErrInvalidEvidence)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/signer"
+)
+
+func TestInclusionList(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey := ed25519.GenPrivKey()
+	list := &InclusionList{
+		ChainID:  TestChainID,
+		Height:   5,
+		Deadline: 3,
+		TxHashes: []cmbytes.HexBytes{GetRandomTx().Hash(), GetRandomTx().Hash()},
+	}
+	require.NoError(list.ValidateBasic())
+	assert.False(list.Signed())
+	assert.EqualValues(8, list.LastHeight())
+	assert.ErrorIs(list.Verify(privKey.PubKey()), ErrInvalidInclusionList)
+
+	hash := list.Hash()
+	list.ProposerAddress = privKey.PubKey().Address()
+	var err error
+	list.Signature, err = privKey.Sign(list.SignBytes())
+	require.NoError(err)
+	assert.True(list.Signed())
+	assert.Equal(hash, list.Hash(), "hash doesn't depend on the signature")
+	assert.NoError(list.Verify(privKey.PubKey()))
+	assert.ErrorIs(list.Verify(ed25519.GenPrivKey().PubKey()), ErrInvalidInclusionList)
+
+	data, err := list.MarshalBinary()
+	require.NoError(err)
+	var decoded InclusionList
+	require.NoError(decoded.UnmarshalBinary(data))
+	assert.Equal(*list, decoded)
+	assert.NoError(decoded.Verify(privKey.PubKey()))
+
+	// signature covers the deadline
+	extended := *list
+	extended.Deadline++
+	assert.ErrorIs(extended.Verify(privKey.PubKey()), ErrInvalidInclusionList)
+}
+
+func TestInclusionListValidateBasic(t *testing.T) {
+	valid := func() *InclusionList {
+		return &InclusionList{
+			ChainID:  TestChainID,
+			Height:   1,
+			Deadline: 2,
+			TxHashes: []cmbytes.HexBytes{GetRandomBytes(32), GetRandomBytes(32)},
+		}
+	}
+	tooMany := valid()
+	tooMany.TxHashes = nil
+	for i := 0; i <= MaxInclusionListTxs; i++ {
+		tooMany.TxHashes = append(tooMany.TxHashes, GetRandomBytes(32))
+	}
+
+	cases := []struct {
+		name   string
+		modify func(l *InclusionList)
+	}{
+		{"empty chain ID", func(l *InclusionList) { l.ChainID = "" }},
+		{"zero deadline", func(l *InclusionList) { l.Deadline = 0 }},
+		{"deadline too far", func(l *InclusionList) { l.Deadline = MaxInclusionListDeadline + 1 }},
+		{"no transactions", func(l *InclusionList) { l.TxHashes = nil }},
+		{"too many transactions", func(l *InclusionList) { l.TxHashes = tooMany.TxHashes }},
+		{"empty hash", func(l *InclusionList) { l.TxHashes[0] = nil }},
+		{"duplicate hash", func(l *InclusionList) { l.TxHashes[1] = l.TxHashes[0] }},
+		{"signature without address", func(l *InclusionList) { l.Signature = GetRandomBytes(64) }},
+		{"address without signature", func(l *InclusionList) { l.ProposerAddress = GetRandomBytes(20) }},
+	}
+	require.NoError(t, valid().ValidateBasic())
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			list := valid()
+			c.modify(list)
+			assert.ErrorIs(t, list.ValidateBasic(), ErrInvalidInclusionList)
+		})
+	}
+}
+
+// inclusionListBlocks returns n consecutive blocks following the height, with valid DataHash, signed by the same
+// proposer.
+func inclusionListBlocks(t *testing.T, height uint64, n int) ([]*Block, []crypto.PrivKey) {
+	require := require.New(t)
+	g := NewGenerator(1)
+	var (
+		blocks []*Block
+		prev   *SignedHeader
+		keys   []crypto.PrivKey
+		err    error
+	)
+	for i := 0; i < n; i++ {
+		var sh *SignedHeader
+		if prev == nil {
+			sh, keys, err = g.SignedHeader()
+			require.NoError(err)
+			sh.BaseHeader.Height = height + 1
+		} else {
+			sh, err = g.NextSignedHeader(prev, keys)
+			require.NoError(err)
+		}
+		block := g.Block(sh.Height(), 2)
+		block.SignedHeader = *sh
+		block.SignedHeader.DataHash, err = block.Data.HashWith(DefaultMerkleHasher)
+		require.NoError(err)
+		commit, err := signCommit(block.SignedHeader.Header, keys)
+		require.NoError(err)
+		block.SignedHeader.Commit = *commit
+		blocks = append(blocks, block)
+		prev = &block.SignedHeader
+	}
+	return blocks, keys
+}
+
+func TestInclusionListViolation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	const height, deadline = 10, 3
+	blocks, keys := inclusionListBlocks(t, height, deadline)
+	signList := func(txHashes ...cmbytes.HexBytes) *InclusionList {
+		list := &InclusionList{
+			ChainID:         TestChainID,
+			Height:          height,
+			Deadline:        deadline,
+			TxHashes:        txHashes,
+			ProposerAddress: blocks[0].SignedHeader.ProposerAddress,
+		}
+		var err error
+		list.Signature, err = signer.NewLocalSigner(keys[0]).Sign(context.Background(), height, list.SignBytes())
+		require.NoError(err)
+		return list
+	}
+
+	included := cmbytes.HexBytes(blocks[1].Data.Txs[0].Hash())
+	missing := cmbytes.HexBytes(GetRandomTx().Hash())
+	list := signList(included, missing)
+
+	v, err := NewInclusionListViolation(list, blocks, DefaultMerkleHasher)
+	require.NoError(err)
+	assert.Equal([]cmbytes.HexBytes{missing}, v.Missing())
+	assert.NoError(v.Verify(DefaultMerkleHasher))
+
+	keccak, err := GetDataHasher(DataHashKeccak256)
+	require.NoError(err)
+	assert.ErrorIs(v.Verify(keccak), ErrInvalidEvidence)
+
+	// all transactions included
+	_, err = NewInclusionListViolation(signList(included), blocks, DefaultMerkleHasher)
+	assert.ErrorIs(err, ErrInvalidEvidence)
+
+	// blocks don't cover the deadline
+	_, err = NewInclusionListViolation(list, blocks[:deadline-1], DefaultMerkleHasher)
+	assert.ErrorIs(err, ErrInvalidEvidence)
+
+	// unsigned list doesn't prove anything
+	unsigned := *list
+	unsigned.ProposerAddress, unsigned.Signature = nil, nil
+	_, err = NewInclusionListViolation(&unsigned, blocks, DefaultMerkleHasher)
+	assert.ErrorIs(err, ErrInvalidEvidence)
+
+	// transaction hidden from the leaves
+	tampered := *v
+	tampered.Leaves = append([][]cmbytes.HexBytes{}, v.Leaves...)
+	tampered.Leaves[1] = tampered.Leaves[1][1:]
+	assert.ErrorIs(tampered.Verify(DefaultMerkleHasher), ErrInvalidEvidence)
+
+	// headers not linked
+	tampered = *v
+	tampered.Headers = append([]SignedHeader{}, v.Headers...)
+	tampered.Headers[1], tampered.Headers[2] = tampered.Headers[2], tampered.Headers[1]
+	assert.ErrorIs(tampered.Verify(DefaultMerkleHasher), ErrInvalidEvidence)
+}
@@ -0,0 +1,396 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmjson "github.com/cometbft/cometbft/libs/json"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	pb "github.com/rollkit/rollkit/types/pb/rollkit"
+)
+
+// Canonical JSON encoding of core types.
+//
+// Field names are snake_case and always present, in a fixed order. Following CometBFT conventions, 64-bit integers
+// are encoded as decimal strings, hashes and addresses as upper-case hex strings, time as RFC 3339 string (UTC,
+// nanosecond precision), and other binary data (transactions, signatures, proofs) as base64 strings.
+// Aggregator set is encoded with CometBFT JSON encoding (including types of public keys).
+
+type versionJSON struct {
+	Block string `json:"block"`
+	App   string `json:"app"`
+}
+
+type headerJSON struct {
+	Version             versionJSON      `json:"version"`
+	ChainID             string           `json:"chain_id"`
+	Height              string           `json:"height"`
+	Time                time.Time        `json:"time"`
+	LastHeaderHash      Hash             `json:"last_header_hash"`
+	LastCommitHash      Hash             `json:"last_commit_hash"`
+	DataHash            Hash             `json:"data_hash"`
+	ConsensusHash       Hash             `json:"consensus_hash"`
+	AppHash             Hash             `json:"app_hash"`
+	LastResultsHash     Hash             `json:"last_results_hash"`
+	ProposerAddress     cmbytes.HexBytes `json:"proposer_address"`
+	AggregatorsHash     Hash             `json:"aggregators_hash"`
+	NextAggregatorsHash Hash             `json:"next_aggregators_hash"`
+	ValidityProofHash   Hash             `json:"validity_proof_hash"`
+	AggregatorKeysHash  Hash             `json:"aggregator_keys_hash"`
+	EncodingVersion     uint32           `json:"encoding_version"`
+	Extensions          []extensionJSON  `json:"extensions"`
+}
+
+type extensionJSON struct {
+	Type string `json:"type"`
+	Data []byte `json:"data"`
+}
+
+type commitJSON struct {
+	Signatures          [][]byte         `json:"signatures"`
+	AggregatedSignature []byte           `json:"aggregated_signature"`
+	Signers             cmbytes.HexBytes `json:"signers"`
+	ValidityProof       []byte           `json:"validity_proof"`
+}
+
+type signedHeaderJSON struct {
+	Header         Header             `json:"header"`
+	Commit         Commit             `json:"commit"`
+	Validators     json.RawMessage    `json:"validators"`
+	AggregatorKeys []cmbytes.HexBytes `json:"aggregator_keys"`
+}
+
+type dataJSON struct {
+	Txs                    [][]byte                  `json:"txs"`
+	IntermediateStateRoots [][]byte                  `json:"intermediate_state_roots"`
+	Evidence               []DuplicateHeaderEvidence `json:"evidence"`
+}
+
+type blockJSON struct {
+	SignedHeader SignedHeader `json:"signed_header"`
+	Data         Data         `json:"data"`
+}
+
+// MarshalJSON encodes Header into canonical JSON.
+func (h Header) MarshalJSON() ([]byte, error) {
+	extensions := make([]extensionJSON, len(h.Extensions))
+	for i, ext := range h.Extensions {
+		extensions[i] = extensionJSON{Type: ext.Type, Data: nonNilBytes(ext.Data)}
+	}
+	return json.Marshal(headerJSON{
+		Version: versionJSON{
+			Block: strconv.FormatUint(h.Version.Block, 10),
+			App:   strconv.FormatUint(h.Version.App, 10),
+		},
+		ChainID:             h.ChainID(),
+		Height:              strconv.FormatUint(h.Height(), 10),
+		Time:                h.Time().UTC(),
+		LastHeaderHash:      nonNilHash(h.LastHeaderHash),
+		LastCommitHash:      nonNilHash(h.LastCommitHash),
+		DataHash:            nonNilHash(h.DataHash),
+		ConsensusHash:       nonNilHash(h.ConsensusHash),
+		AppHash:             nonNilHash(h.AppHash),
+		LastResultsHash:     nonNilHash(h.LastResultsHash),
+		ProposerAddress:     nonNilBytes(h.ProposerAddress),
+		AggregatorsHash:     nonNilHash(h.AggregatorsHash),
+		NextAggregatorsHash: nonNilHash(h.NextAggregatorsHash),
+		ValidityProofHash:   nonNilHash(h.ValidityProofHash),
+		AggregatorKeysHash:  nonNilHash(h.AggregatorKeysHash),
+		EncodingVersion:     h.EncodingVersion,
+		Extensions:          extensions,
+	})
+}
+
+// UnmarshalJSON decodes Header from canonical JSON.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	var hj headerJSON
+	if err := json.Unmarshal(data, &hj); err != nil {
+		return err
+	}
+	var err error
+	if h.Version.Block, err = parseUint("version.block", hj.Version.Block); err != nil {
+		return err
+	}
+	if h.Version.App, err = parseUint("version.app", hj.Version.App); err != nil {
+		return err
+	}
+	if h.BaseHeader.Height, err = parseUint("height", hj.Height); err != nil {
+		return err
+	}
+	h.BaseHeader.Time = uint64(hj.Time.UnixNano())
+	h.BaseHeader.ChainID = hj.ChainID
+	h.LastHeaderHash = hj.LastHeaderHash
+	h.LastCommitHash = hj.LastCommitHash
+	h.DataHash = hj.DataHash
+	h.ConsensusHash = hj.ConsensusHash
+	h.AppHash = hj.AppHash
+	h.LastResultsHash = hj.LastResultsHash
+	h.ProposerAddress = hj.ProposerAddress
+	h.AggregatorsHash = hj.AggregatorsHash
+	h.NextAggregatorsHash = hj.NextAggregatorsHash
+	h.ValidityProofHash = hj.ValidityProofHash
+	h.AggregatorKeysHash = hj.AggregatorKeysHash
+	h.EncodingVersion = hj.EncodingVersion
+	h.Extensions = nil
+	for _, ext := range hj.Extensions {
+		h.Extensions = append(h.Extensions, HeaderExtension{Type: ext.Type, Data: ext.Data})
+	}
+	return nil
+}
+
+// MarshalJSON encodes Commit into canonical JSON.
+func (c Commit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(commitJSON{
+		Signatures:          nonNilSlices(signaturesToByteSlices(c.Signatures)),
+		AggregatedSignature: nonNilBytes(c.AggregatedSignature),
+		Signers:             nonNilBytes(c.Signers),
+		ValidityProof:       nonNilBytes(c.ValidityProof),
+	})
+}
+
+// UnmarshalJSON decodes Commit from canonical JSON.
+func (c *Commit) UnmarshalJSON(data []byte) error {
+	var cj commitJSON
+	if err := json.Unmarshal(data, &cj); err != nil {
+		return err
+	}
+	c.Signatures = byteSlicesToSignatures(cj.Signatures)
+	c.AggregatedSignature = cj.AggregatedSignature
+	c.Signers = cj.Signers
+	c.ValidityProof = cj.ValidityProof
+	return nil
+}
+
+// MarshalJSON encodes SignedHeader into canonical JSON.
+func (sh SignedHeader) MarshalJSON() ([]byte, error) {
+	validators := json.RawMessage("null")
+	if sh.Validators != nil {
+		var err error
+		if validators, err = cmjson.Marshal(sh.Validators); err != nil {
+			return nil, fmt.Errorf("failed to encode aggregator set: %w", err)
+		}
+	}
+	keys := make([]cmbytes.HexBytes, len(sh.AggregatorKeys))
+	for i, key := range sh.AggregatorKeys {
+		keys[i] = cmbytes.HexBytes(key)
+	}
+	return json.Marshal(signedHeaderJSON{
+		Header:         sh.Header,
+		Commit:         sh.Commit,
+		Validators:     validators,
+		AggregatorKeys: keys,
+	})
+}
+
+// UnmarshalJSON decodes SignedHeader from canonical JSON.
+func (sh *SignedHeader) UnmarshalJSON(data []byte) error {
+	var shj signedHeaderJSON
+	if err := json.Unmarshal(data, &shj); err != nil {
+		return err
+	}
+	sh.Header = shj.Header
+	sh.Commit = shj.Commit
+	sh.Validators = nil
+	if len(shj.Validators) > 0 && string(shj.Validators) != "null" {
+		validators := new(cmtypes.ValidatorSet)
+		if err := cmjson.Unmarshal(shj.Validators, validators); err != nil {
+			return fmt.Errorf("failed to decode aggregator set: %w", err)
+		}
+		sh.Validators = validators
+	}
+	sh.AggregatorKeys = nil
+	if len(shj.AggregatorKeys) > 0 {
+		sh.AggregatorKeys = make([]bls.PubKey, len(shj.AggregatorKeys))
+		for i, key := range shj.AggregatorKeys {
+			sh.AggregatorKeys[i] = bls.PubKey(key)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes Data into canonical JSON.
+func (d Data) MarshalJSON() ([]byte, error) {
+	txs := make([][]byte, len(d.Txs))
+	for i, tx := range d.Txs {
+		txs[i] = tx
+	}
+	return json.Marshal(dataJSON{
+		Txs:                    txs,
+		IntermediateStateRoots: nonNilSlices(d.IntermediateStateRoots.RawRootsList),
+		Evidence:               nonNilEvidence(d.Evidence.Evidence),
+	})
+}
+
+// UnmarshalJSON decodes Data from canonical JSON.
+func (d *Data) UnmarshalJSON(data []byte) error {
+	var dj dataJSON
+	if err := json.Unmarshal(data, &dj); err != nil {
+		return err
+	}
+	d.Txs = byteSlicesToTxs(dj.Txs)
+	d.IntermediateStateRoots.RawRootsList = nil
+	if len(dj.IntermediateStateRoots) > 0 {
+		d.IntermediateStateRoots.RawRootsList = dj.IntermediateStateRoots
+	}
+	d.Evidence.Evidence = nil
+	if len(dj.Evidence) > 0 {
+		d.Evidence.Evidence = dj.Evidence
+	}
+	return nil
+}
+
+// MarshalJSON encodes Block into canonical JSON.
+func (b Block) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blockJSON(b))
+}
+
+// UnmarshalJSON decodes Block from canonical JSON.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var bj blockJSON
+	if err := json.Unmarshal(data, &bj); err != nil {
+		return err
+	}
+	*b = Block(bj)
+	return nil
+}
+
+// MarshalProtoJSON encodes Header into JSON representation of its protobuf message.
+func (h *Header) MarshalProtoJSON() ([]byte, error) {
+	return marshalProtoJSON(h.ToProto())
+}
+
+// UnmarshalProtoJSON decodes Header from JSON representation of its protobuf message.
+func (h *Header) UnmarshalProtoJSON(data []byte) error {
+	var ph pb.Header
+	if err := unmarshalProtoJSON(data, &ph); err != nil {
+		return err
+	}
+	return h.FromProto(&ph)
+}
+
+// MarshalProtoJSON encodes Commit into JSON representation of its protobuf message.
+func (c *Commit) MarshalProtoJSON() ([]byte, error) {
+	return marshalProtoJSON(c.ToProto())
+}
+
+// UnmarshalProtoJSON decodes Commit from JSON representation of its protobuf message.
+func (c *Commit) UnmarshalProtoJSON(data []byte) error {
+	var pc pb.Commit
+	if err := unmarshalProtoJSON(data, &pc); err != nil {
+		return err
+	}
+	return c.FromProto(&pc)
+}
+
+// MarshalProtoJSON encodes SignedHeader into JSON representation of its protobuf message.
+func (sh *SignedHeader) MarshalProtoJSON() ([]byte, error) {
+	psh, err := sh.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return marshalProtoJSON(psh)
+}
+
+// UnmarshalProtoJSON decodes SignedHeader from JSON representation of its protobuf message.
+func (sh *SignedHeader) UnmarshalProtoJSON(data []byte) error {
+	var psh pb.SignedHeader
+	if err := unmarshalProtoJSON(data, &psh); err != nil {
+		return err
+	}
+	return sh.FromProto(&psh)
+}
+
+// MarshalProtoJSON encodes Data into JSON representation of its protobuf message.
+func (d *Data) MarshalProtoJSON() ([]byte, error) {
+	dp, err := d.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return marshalProtoJSON(dp)
+}
+
+// UnmarshalProtoJSON decodes Data from JSON representation of its protobuf message.
+func (d *Data) UnmarshalProtoJSON(data []byte) error {
+	var pd pb.Data
+	if err := unmarshalProtoJSON(data, &pd); err != nil {
+		return err
+	}
+	return d.FromProto(&pd)
+}
+
+// MarshalProtoJSON encodes Block into JSON representation of its protobuf message.
+func (b *Block) MarshalProtoJSON() ([]byte, error) {
+	pbb, err := b.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return marshalProtoJSON(pbb)
+}
+
+// UnmarshalProtoJSON decodes Block from JSON representation of its protobuf message.
+func (b *Block) UnmarshalProtoJSON(data []byte) error {
+	var pbb pb.Block
+	if err := unmarshalProtoJSON(data, &pbb); err != nil {
+		return err
+	}
+	return b.FromProto(&pbb)
+}
+
+// marshalProtoJSON encodes protobuf message into JSON with original (snake_case) field names and default values.
+func marshalProtoJSON(msg proto.Message) ([]byte, error) {
+	m := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	s, err := m.MarshalToString(msg)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+func unmarshalProtoJSON(data []byte, msg proto.Message) error {
+	return jsonpb.UnmarshalString(string(data), msg)
+}
+
+func parseUint(field string, s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return v, nil
+}
+
+// nonNilHash, nonNilBytes and nonNilSlices ensure that empty values are encoded consistently (as "" or []),
+// regardless of being nil or empty.
+func nonNilHash(h Hash) Hash {
+	if h == nil {
+		return Hash{}
+	}
+	return h
+}
+
+func nonNilBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+func nonNilSlices(s [][]byte) [][]byte {
+	if s == nil {
+		return [][]byte{}
+	}
+	return s
+}
+
+func nonNilEvidence(e []DuplicateHeaderEvidence) []DuplicateHeaderEvidence {
+	if e == nil {
+		return []DuplicateHeaderEvidence{}
+	}
+	return e
+}
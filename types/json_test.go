@@ -0,0 +1,130 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonTestBlock returns block with fixed content, used to check canonical encoding against test vectors.
+func jsonTestBlock() *Block {
+	valSet := cmtypes.NewValidatorSet([]*cmtypes.Validator{
+		cmtypes.NewValidator(ed25519.GenPrivKeyFromSecret([]byte("aggregator")).PubKey(), 1),
+	})
+	return &Block{
+		SignedHeader: SignedHeader{
+			Header: Header{
+				BaseHeader: BaseHeader{
+					Height:  3,
+					Time:    1700000000123456789,
+					ChainID: "test-chain",
+				},
+				Version:         Version{Block: 11, App: 1},
+				LastHeaderHash:  Hash{0x01, 0x02},
+				LastCommitHash:  Hash{0x03},
+				DataHash:        Hash{0x04},
+				AppHash:         Hash{0xab, 0xcd},
+				ProposerAddress: valSet.Proposer.Address,
+				AggregatorsHash: valSet.Hash(),
+				EncodingVersion: 2,
+				Extensions:      []HeaderExtension{{Type: "bridge_root", Data: []byte{0xbe, 0xef}}},
+			},
+			Commit: Commit{
+				Signatures: []Signature{{0x0a, 0x0b, 0x0c}},
+			},
+			Validators: valSet,
+		},
+		Data: Data{
+			Txs: Txs{Tx("tx1"), Tx("tx2")},
+		},
+	}
+}
+
+// test vectors of canonical JSON encoding of jsonTestBlock
+const (
+	headerJSONVector = `{"version":{"block":"11","app":"1"},"chain_id":"test-chain","height":"3","time":"2023-11-14T22:13:20.123456789Z",` +
+		`"last_header_hash":"0102","last_commit_hash":"03","data_hash":"04","consensus_hash":"","app_hash":"ABCD","last_results_hash":"",` +
+		`"proposer_address":"B981038BF50D782DB9988436D352E9378754D460",` +
+		`"aggregators_hash":"E21C7BDFB9BE0FFB080475B2B2600C69F6B57E6DBA5953409CD07AD39B8626D8",` +
+		`"next_aggregators_hash":"","validity_proof_hash":"","aggregator_keys_hash":"","encoding_version":2,` +
+		`"extensions":[{"type":"bridge_root","data":"vu8="}]}`
+	commitJSONVector       = `{"signatures":["CgsM"],"aggregated_signature":"","signers":"","validity_proof":""}`
+	dataJSONVector         = `{"txs":["dHgx","dHgy"],"intermediate_state_roots":[],"evidence":[]}`
+	validatorJSONVector    = `{"address":"B981038BF50D782DB9988436D352E9378754D460","pub_key":{"type":"tendermint/PubKeyEd25519","value":"8YRciFn0A3ravNBKsEk0uRYUWCz4esnGM8hjBZVSrDU="},"voting_power":"1","proposer_priority":"0"}`
+	validatorsJSONVector   = `{"validators":[` + validatorJSONVector + `],"proposer":` + validatorJSONVector + `}`
+	signedHeaderJSONVector = `{"header":` + headerJSONVector + `,"commit":` + commitJSONVector +
+		`,"validators":` + validatorsJSONVector + `,"aggregator_keys":[]}`
+	blockJSONVector = `{"signed_header":` + signedHeaderJSONVector + `,"data":` + dataJSONVector + `}`
+
+	headerProtoJSONVector = `{"version":{"block":"11","app":"1"},"height":"3","time":"1700000000123456789",` +
+		`"last_header_hash":"AQI=","last_commit_hash":"Aw==","data_hash":"BA==","consensus_hash":null,"app_hash":"q80=",` +
+		`"last_results_hash":null,"proposer_address":"uYEDi/UNeC25mIQ201LpN4dU1GA=",` +
+		`"aggregators_hash":"4hx737m+D/sIBHWysmAMafa1fm26WVNAnNB605uGJtg=","next_aggregators_hash":null,` +
+		`"chain_id":"test-chain","validity_proof_hash":null,"aggregator_keys_hash":null,"encoding_version":2,` +
+		`"extensions":[{"type":"bridge_root","data":"vu8="}]}`
+	commitProtoJSONVector = `{"signatures":["CgsM"],"validity_proof":null,"aggregated_signature":null,"signers":null}`
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	block := jsonTestBlock()
+
+	cases := []struct {
+		name     string
+		value    json.Marshaler
+		decoded  json.Unmarshaler
+		expected string
+	}{
+		{"header", block.SignedHeader.Header, new(Header), headerJSONVector},
+		{"commit", block.SignedHeader.Commit, new(Commit), commitJSONVector},
+		{"data", block.Data, new(Data), dataJSONVector},
+		{"signed header", block.SignedHeader, new(SignedHeader), signedHeaderJSONVector},
+		{"block", *block, new(Block), blockJSONVector},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			encoded, err := json.Marshal(c.value)
+			require.NoError(err)
+			assert.Equal(c.expected, string(encoded))
+
+			// decoding and encoding again gives the same representation
+			require.NoError(json.Unmarshal(encoded, c.decoded))
+			reencoded, err := json.Marshal(c.decoded)
+			require.NoError(err)
+			assert.Equal(c.expected, string(reencoded))
+		})
+	}
+
+	var decoded Block
+	require.NoError(t, json.Unmarshal([]byte(blockJSONVector), &decoded))
+	assert.Equal(t, block.Hash(), decoded.Hash())
+	assert.Equal(t, block.SignedHeader.Validators.Hash(), decoded.SignedHeader.Validators.Hash())
+}
+
+func TestProtoJSON(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	block := jsonTestBlock()
+	encoded, err := block.SignedHeader.Header.MarshalProtoJSON()
+	require.NoError(err)
+	assert.Equal(headerProtoJSONVector, string(encoded))
+	encoded, err = block.SignedHeader.Commit.MarshalProtoJSON()
+	require.NoError(err)
+	assert.Equal(commitProtoJSONVector, string(encoded))
+
+	encoded, err = block.MarshalProtoJSON()
+	require.NoError(err)
+	var decoded Block
+	require.NoError(decoded.UnmarshalProtoJSON(encoded))
+	assert.Equal(block.Hash(), decoded.Hash())
+	reencoded, err := decoded.MarshalProtoJSON()
+	require.NoError(err)
+	assert.Equal(string(encoded), string(reencoded))
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	cmtypes "github.com/cometbft/cometbft/types"
+)
+
+// Limits of decoded objects. Binary encodings come from untrusted sources (DA layer blobs, P2P gossip), so sizes
+// are checked before unmarshaling, and contents are sanity checked afterwards.
+const (
+	// MaxHeaderSize is the maximal size of binary encoding of a header.
+	MaxHeaderSize = 64 * 1024
+	// MaxCommitSize is the maximal size of binary encoding of a commit.
+	MaxCommitSize = 1024 * 1024
+	// MaxSignedHeaderSize is the maximal size of binary encoding of a signed header (including commit and
+	// aggregator set).
+	MaxSignedHeaderSize = 4 * 1024 * 1024
+	// MaxBlobSize is the maximal size of binary encoding of a block or block data.
+	MaxBlobSize = MaxBlockSizeBytes
+	// MaxTxs is the maximal number of transactions in a block.
+	MaxTxs = 1024 * 1024
+	// MaxIntermediateStateRoots is the maximal number of intermediate state roots in a block: one after
+	// BeginBlock and one after every transaction (see validateISRsLength of the block executor).
+	MaxIntermediateStateRoots = MaxTxs + 1
+	// MaxEvidence is the maximal number of evidence in a block.
+	MaxEvidence = 64
+	// MaxHashSize is the maximal size of hashes and state roots.
+	MaxHashSize = 64
+	// MaxSignatures is the maximal number of signatures in a commit, and size of the aggregator set.
+	MaxSignatures = cmtypes.MaxVotesCount
+	// MaxSignatureSize is the maximal size of a single signature.
+	MaxSignatureSize = 128
+)
+
+var (
+	// ErrSizeLimitExceeded is returned when binary encoding of an object exceeds its size limit.
+	ErrSizeLimitExceeded = errors.New("size limit exceeded")
+	// ErrMalformedEncoding is returned when decoded object is incomplete or exceeds limits of its contents.
+	ErrMalformedEncoding = errors.New("malformed encoding")
+)
+
+// checkSize returns error if binary encoding of the object exceeds the limit.
+func checkSize(object string, data []byte, limit int) error {
+	if len(data) > limit {
+		return fmt.Errorf("%w: %s has %d bytes, maximum is %d", ErrSizeLimitExceeded, object, len(data), limit)
+	}
+	return nil
+}
+
+// checkCount returns error if the number of elements of the object exceeds the limit.
+func checkCount(object string, n int, limit int) error {
+	if n > limit {
+		return fmt.Errorf("%w: %d %s, maximum is %d", ErrMalformedEncoding, n, object, limit)
+	}
+	return nil
+}
+
+// checkHashes returns error if any of the hashes exceeds MaxHashSize.
+func checkHashes(object string, hashes ...[]byte) error {
+	for _, h := range hashes {
+		if len(h) > MaxHashSize {
+			return fmt.Errorf("%w: %s hash has %d bytes, maximum is %d", ErrMalformedEncoding, object, len(h), MaxHashSize)
+		}
+	}
+	return nil
+}
+
+// checkSignatures returns error if any of the signatures exceeds MaxSignatureSize.
+func checkSignatures(sigs ...[]byte) error {
+	for _, sig := range sigs {
+		if len(sig) > MaxSignatureSize {
+			return fmt.Errorf("%w: signature has %d bytes, maximum is %d", ErrMalformedEncoding, len(sig), MaxSignatureSize)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"math/bits"
+	"sync"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// Names of hash functions of DataHash.
+const (
+	// DataHashSHA256 is the default hash function, compatible with ABCI hash of transactions.
+	DataHashSHA256    = "sha256"
+	DataHashKeccak256 = "keccak256"
+	DataHashSHA3      = "sha3-256"
+	DataHashBLAKE2b   = "blake2b-256"
+)
+
+// Prefixes of leaf and inner nodes of the Merkle tree, as defined by RFC 6962.
+const (
+	leafPrefix  = 0
+	innerPrefix = 1
+)
+
+var (
+	// ErrUnknownDataHash is returned when the hash function of DataHash is not registered.
+	ErrUnknownDataHash = errors.New("unknown data hash function")
+	// ErrInvalidMerkleProof is returned when Merkle proof doesn't prove inclusion of the leaf.
+	ErrInvalidMerkleProof = errors.New("invalid Merkle proof")
+	// ErrNoHashFunction is returned when MerkleHasher has no hash function, e.g. zero value of MerkleHasher.
+	ErrNoHashFunction = errors.New("Merkle hasher without hash function")
+)
+
+// MerkleHasher computes Merkle trees as defined by RFC 6962 (the same as CometBFT), using the hash function New.
+// Leaf nodes are hashed as H(0x00 || leaf) and inner nodes as H(0x01 || left || right). A tree of n > 1 leaves is
+// split into a complete left subtree of k leaves, where k is the largest power of two smaller than n, and the right
+// subtree of n-k leaves. The root of an empty tree is H("").
+type MerkleHasher struct {
+	// Name of the hash function, e.g. DataHashSHA256.
+	Name string
+	New  func() hash.Hash
+}
+
+// DefaultMerkleHasher uses SHA-256. Merkle roots and proofs are compatible with CometBFT.
+var DefaultMerkleHasher = MerkleHasher{Name: DataHashSHA256, New: sha256.New}
+
+var (
+	hashersMtx sync.RWMutex
+	// dataHashes are hash functions of DataHash, by name
+	dataHashes = map[string]func() hash.Hash{
+		DataHashSHA256:    sha256.New,
+		DataHashKeccak256: sha3.NewLegacyKeccak256,
+		DataHashSHA3:      sha3.New256,
+		DataHashBLAKE2b:   newBLAKE2b256,
+	}
+)
+
+func newBLAKE2b256() hash.Hash {
+	h, _ := blake2b.New256(nil) // error is returned only for invalid keys
+	return h
+}
+
+// RegisterDataHash registers a hash function of DataHash under the name, so that chains can use it.
+func RegisterDataHash(name string, newHash func() hash.Hash) {
+	hashersMtx.Lock()
+	defer hashersMtx.Unlock()
+	dataHashes[name] = newHash
+}
+
+// GetDataHasher returns MerkleHasher using the hash function registered under the name. Empty name means
+// DataHashSHA256.
+func GetDataHasher(name string) (MerkleHasher, error) {
+	if name == "" {
+		return DefaultMerkleHasher, nil
+	}
+	hashersMtx.RLock()
+	defer hashersMtx.RUnlock()
+	newHash, ok := dataHashes[name]
+	if !ok {
+		return MerkleHasher{}, fmt.Errorf("%w: %q", ErrUnknownDataHash, name)
+	}
+	return MerkleHasher{Name: name, New: newHash}, nil
+}
+
+// Validate returns ErrNoHashFunction if the hasher has no hash function. Hasher can't be used without it, so
+// components taking the hasher check it when they're created.
+func (m MerkleHasher) Validate() error {
+	if m.New == nil {
+		return ErrNoHashFunction
+	}
+	return nil
+}
+
+// Sum returns hash of the data.
+func (m MerkleHasher) Sum(data []byte) []byte {
+	h := m.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// LeafHash returns hash of the leaf node.
+func (m MerkleHasher) LeafHash(leaf []byte) []byte {
+	h := m.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(leaf)
+	return h.Sum(nil)
+}
+
+// InnerHash returns hash of the inner node with given children.
+func (m MerkleHasher) InnerHash(left, right []byte) []byte {
+	h := m.New()
+	h.Write([]byte{innerPrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// Root returns the Merkle root of the leaves.
+func (m MerkleHasher) Root(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		return m.Sum(nil)
+	case 1:
+		return m.LeafHash(leaves[0])
+	}
+	k := splitPoint(int64(len(leaves)))
+	return m.InnerHash(m.Root(leaves[:k]), m.Root(leaves[k:]))
+}
+
+// Proofs returns the Merkle root of the leaves, and proofs of inclusion of every leaf. Proofs have the same format
+// as CometBFT proofs (aunts are ordered from the leaf to the root).
+func (m MerkleHasher) Proofs(leaves [][]byte) ([]byte, []*merkle.Proof) {
+	root, aunts := m.subtree(leaves)
+	proofs := make([]*merkle.Proof, len(leaves))
+	for i, leaf := range leaves {
+		proofs[i] = &merkle.Proof{
+			Total:    int64(len(leaves)),
+			Index:    int64(i),
+			LeafHash: m.LeafHash(leaf),
+			Aunts:    aunts[i],
+		}
+	}
+	return root, proofs
+}
+
+// subtree returns the root of the tree of leaves, and aunts of every leaf within the tree.
+func (m MerkleHasher) subtree(leaves [][]byte) ([]byte, [][][]byte) {
+	switch len(leaves) {
+	case 0:
+		return m.Sum(nil), nil
+	case 1:
+		return m.LeafHash(leaves[0]), [][][]byte{{}}
+	}
+	k := splitPoint(int64(len(leaves)))
+	left, leftAunts := m.subtree(leaves[:k])
+	right, rightAunts := m.subtree(leaves[k:])
+	for i := range leftAunts {
+		leftAunts[i] = append(leftAunts[i], right)
+	}
+	for i := range rightAunts {
+		rightAunts[i] = append(rightAunts[i], left)
+	}
+	return m.InnerHash(left, right), append(leftAunts, rightAunts...)
+}
+
+// VerifyProof returns an error if the proof doesn't prove inclusion of the leaf in the tree with given root.
+func (m MerkleHasher) VerifyProof(proof merkle.Proof, root, leaf []byte) error {
+	if proof.Total <= 0 || proof.Index < 0 || proof.Index >= proof.Total {
+		return fmt.Errorf("%w: index %d out of range [0, %d)", ErrInvalidMerkleProof, proof.Index, proof.Total)
+	}
+	leafHash := m.LeafHash(leaf)
+	if !bytes.Equal(proof.LeafHash, leafHash) {
+		return fmt.Errorf("%w: leaf hash mismatch", ErrInvalidMerkleProof)
+	}
+	computed := m.rootFromAunts(proof.Index, proof.Total, leafHash, proof.Aunts)
+	if computed == nil || !bytes.Equal(computed, root) {
+		return fmt.Errorf("%w: root hash mismatch", ErrInvalidMerkleProof)
+	}
+	return nil
+}
+
+// rootFromAunts returns the root of the tree, computed from the leaf hash and its aunts, or nil if the number of
+// aunts doesn't match the size of the tree.
+func (m MerkleHasher) rootFromAunts(index, total int64, leafHash []byte, aunts [][]byte) []byte {
+	if total == 1 {
+		if len(aunts) != 0 {
+			return nil
+		}
+		return leafHash
+	}
+	if len(aunts) == 0 {
+		return nil
+	}
+	last := aunts[len(aunts)-1]
+	k := splitPoint(total)
+	if index < k {
+		left := m.rootFromAunts(index, k, leafHash, aunts[:len(aunts)-1])
+		if left == nil {
+			return nil
+		}
+		return m.InnerHash(left, last)
+	}
+	right := m.rootFromAunts(index-k, total-k, leafHash, aunts[:len(aunts)-1])
+	if right == nil {
+		return nil
+	}
+	return m.InnerHash(last, right)
+}
+
+// splitPoint returns the largest power of two smaller than n (n > 1).
+func splitPoint(n int64) int64 {
+	return 1 << (bits.Len64(uint64(n-1)) - 1)
+}
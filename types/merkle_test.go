@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func merkleLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("leaf%d", i))
+	}
+	return leaves
+}
+
+func TestMerkleHasherCompatibility(t *testing.T) {
+	for n := 0; n <= 10; n++ {
+		leaves := merkleLeaves(n)
+		root, proofs := DefaultMerkleHasher.Proofs(leaves)
+		assert.Equal(t, merkle.HashFromByteSlices(leaves), root, "n=%d", n)
+		assert.Equal(t, root, DefaultMerkleHasher.Root(leaves), "n=%d", n)
+
+		cmtRoot, cmtProofs := merkle.ProofsFromByteSlices(leaves)
+		require.Equal(t, cmtRoot, root)
+		for i := range leaves {
+			assert.Equal(t, cmtProofs[i].Aunts, proofs[i].Aunts, "n=%d, i=%d", n, i)
+			assert.NoError(t, proofs[i].Verify(root, leaves[i]))
+		}
+	}
+}
+
+func TestMerkleHasherProofs(t *testing.T) {
+	hasher, err := GetDataHasher(DataHashKeccak256)
+	require.NoError(t, err)
+
+	for n := 1; n <= 10; n++ {
+		leaves := merkleLeaves(n)
+		root, proofs := hasher.Proofs(leaves)
+		assert.NotEqual(t, merkle.HashFromByteSlices(leaves), root)
+		for i, proof := range proofs {
+			assert.NoError(t, hasher.VerifyProof(*proof, root, leaves[i]))
+			assert.ErrorIs(t, hasher.VerifyProof(*proof, root, []byte("other")), ErrInvalidMerkleProof)
+			assert.ErrorIs(t, DefaultMerkleHasher.VerifyProof(*proof, root, leaves[i]), ErrInvalidMerkleProof)
+
+			tampered := *proof
+			tampered.Index = (tampered.Index + 1) % tampered.Total
+			if n > 1 {
+				assert.ErrorIs(t, hasher.VerifyProof(tampered, root, leaves[i]), ErrInvalidMerkleProof)
+			}
+			tampered = *proof
+			tampered.Aunts = append(tampered.Aunts, root)
+			assert.ErrorIs(t, hasher.VerifyProof(tampered, root, leaves[i]), ErrInvalidMerkleProof)
+		}
+	}
+}
+
+func TestBlockDataHash(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, err := GetDataHasher("md5")
+	assert.ErrorIs(err, ErrUnknownDataHash)
+	defaultHasher, err := GetDataHasher("")
+	require.NoError(err)
+	assert.Equal(DataHashSHA256, defaultHasher.Name)
+
+	block := &Block{
+		SignedHeader: SignedHeader{Header: Header{BaseHeader: BaseHeader{ChainID: "keccak-chain"}}},
+		Data:         Data{Txs: Txs{Tx("tx1"), Tx("tx2"), Tx("tx3")}},
+	}
+	sha256Hash, err := block.DataHash(defaultHasher)
+	require.NoError(err)
+
+	hasher, err := GetDataHasher(DataHashKeccak256)
+	require.NoError(err)
+	keccakHash, err := block.DataHash(hasher)
+	require.NoError(err)
+	assert.NotEqual(sha256Hash, keccakHash)
+
+	proof, err := block.Data.TxProofWith(1, hasher)
+	require.NoError(err)
+	assert.NoError(proof.ValidateWith(keccakHash, hasher))
+	assert.Error(proof.Validate(keccakHash))
+
+	// zero value of hasher can't be used
+	_, err = block.DataHash(MerkleHasher{})
+	assert.ErrorIs(err, ErrNoHashFunction)
+	assert.ErrorIs(proof.ValidateWith(keccakHash, MerkleHasher{}), ErrNoHashFunction)
+}
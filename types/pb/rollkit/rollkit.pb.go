@@ -5,6 +5,7 @@ package rollkit
 
 import (
 	fmt "fmt"
+	crypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
 	types "github.com/cometbft/cometbft/proto/tendermint/types"
 	proto "github.com/gogo/protobuf/proto"
 	io "io"
@@ -112,6 +113,14 @@ type Header struct {
 	NextAggregatorsHash []byte `protobuf:"bytes,12,opt,name=next_aggregators_hash,json=nextAggregatorsHash,proto3" json:"next_aggregators_hash,omitempty"`
 	// Chain ID the block belongs to
 	ChainId string `protobuf:"bytes,13,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// Hash of the validity proof of the state transition, if validity proofs are enabled
+	ValidityProofHash []byte `protobuf:"bytes,14,opt,name=validity_proof_hash,json=validityProofHash,proto3" json:"validity_proof_hash,omitempty"`
+	// Hash of BLS public keys of aggregators, if aggregators use BLS signatures
+	AggregatorKeysHash []byte `protobuf:"bytes,15,opt,name=aggregator_keys_hash,json=aggregatorKeysHash,proto3" json:"aggregator_keys_hash,omitempty"`
+	// Version of the binary encoding of the header, 0 if encoded before versioning was introduced
+	EncodingVersion uint32 `protobuf:"varint,16,opt,name=encoding_version,json=encodingVersion,proto3" json:"encoding_version,omitempty"`
+	// Extensions committing to application data (e.g. bridge roots), covered by the signature
+	Extensions []*HeaderExtension `protobuf:"bytes,17,rep,name=extensions,proto3" json:"extensions,omitempty"`
 }
 
 func (m *Header) Reset()         { *m = Header{} }
@@ -238,15 +247,148 @@ func (m *Header) GetChainId() string {
 	return ""
 }
 
+func (m *Header) GetValidityProofHash() []byte {
+	if m != nil {
+		return m.ValidityProofHash
+	}
+	return nil
+}
+
+func (m *Header) GetAggregatorKeysHash() []byte {
+	if m != nil {
+		return m.AggregatorKeysHash
+	}
+	return nil
+}
+
+func (m *Header) GetEncodingVersion() uint32 {
+	if m != nil {
+		return m.EncodingVersion
+	}
+	return 0
+}
+
+func (m *Header) GetExtensions() []*HeaderExtension {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
+// HeaderExtension is a typed entry of application data committed in the header.
+type HeaderExtension struct {
+	// Type identifies the extension, e.g. "bridge_root"
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *HeaderExtension) Reset()         { *m = HeaderExtension{} }
+func (m *HeaderExtension) String() string { return proto.CompactTextString(m) }
+func (*HeaderExtension) ProtoMessage()    {}
+func (*HeaderExtension) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed489fb7f4d78b3f, []int{2}
+}
+func (m *HeaderExtension) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HeaderExtension) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HeaderExtension.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HeaderExtension) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeaderExtension.Merge(m, src)
+}
+func (m *HeaderExtension) XXX_Size() int {
+	return m.Size()
+}
+func (m *HeaderExtension) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeaderExtension.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeaderExtension proto.InternalMessageInfo
+
+func (m *HeaderExtension) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *HeaderExtension) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// HeaderExtensions is a list of header extensions, returned by the application.
+type HeaderExtensions struct {
+	Extensions []*HeaderExtension `protobuf:"bytes,1,rep,name=extensions,proto3" json:"extensions,omitempty"`
+}
+
+func (m *HeaderExtensions) Reset()         { *m = HeaderExtensions{} }
+func (m *HeaderExtensions) String() string { return proto.CompactTextString(m) }
+func (*HeaderExtensions) ProtoMessage()    {}
+func (*HeaderExtensions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed489fb7f4d78b3f, []int{3}
+}
+func (m *HeaderExtensions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HeaderExtensions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HeaderExtensions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HeaderExtensions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeaderExtensions.Merge(m, src)
+}
+func (m *HeaderExtensions) XXX_Size() int {
+	return m.Size()
+}
+func (m *HeaderExtensions) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeaderExtensions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeaderExtensions proto.InternalMessageInfo
+
+func (m *HeaderExtensions) GetExtensions() []*HeaderExtension {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
 type Commit struct {
 	Signatures [][]byte `protobuf:"bytes,1,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	// Validity proof (e.g. zero-knowledge proof) of the state transition
+	ValidityProof []byte `protobuf:"bytes,2,opt,name=validity_proof,json=validityProof,proto3" json:"validity_proof,omitempty"`
+	// BLS signature aggregating signatures of aggregators marked in signers
+	AggregatedSignature []byte `protobuf:"bytes,3,opt,name=aggregated_signature,json=aggregatedSignature,proto3" json:"aggregated_signature,omitempty"`
+	// Bit array of aggregators that signed the header, ordered like in the aggregator set
+	Signers []byte `protobuf:"bytes,4,opt,name=signers,proto3" json:"signers,omitempty"`
 }
 
 func (m *Commit) Reset()         { *m = Commit{} }
 func (m *Commit) String() string { return proto.CompactTextString(m) }
 func (*Commit) ProtoMessage()    {}
 func (*Commit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed489fb7f4d78b3f, []int{2}
+	return fileDescriptor_ed489fb7f4d78b3f, []int{4}
 }
 func (m *Commit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -282,17 +424,40 @@ func (m *Commit) GetSignatures() [][]byte {
 	return nil
 }
 
+func (m *Commit) GetValidityProof() []byte {
+	if m != nil {
+		return m.ValidityProof
+	}
+	return nil
+}
+
+func (m *Commit) GetAggregatedSignature() []byte {
+	if m != nil {
+		return m.AggregatedSignature
+	}
+	return nil
+}
+
+func (m *Commit) GetSigners() []byte {
+	if m != nil {
+		return m.Signers
+	}
+	return nil
+}
+
 type SignedHeader struct {
 	Header     *Header             `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Commit     *Commit             `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
 	Validators *types.ValidatorSet `protobuf:"bytes,3,opt,name=validators,proto3" json:"validators,omitempty"`
+	// BLS public keys of aggregators, ordered like in the aggregator set
+	AggregatorKeys [][]byte `protobuf:"bytes,4,rep,name=aggregator_keys,json=aggregatorKeys,proto3" json:"aggregator_keys,omitempty"`
 }
 
 func (m *SignedHeader) Reset()         { *m = SignedHeader{} }
 func (m *SignedHeader) String() string { return proto.CompactTextString(m) }
 func (*SignedHeader) ProtoMessage()    {}
 func (*SignedHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed489fb7f4d78b3f, []int{3}
+	return fileDescriptor_ed489fb7f4d78b3f, []int{5}
 }
 func (m *SignedHeader) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -342,16 +507,27 @@ func (m *SignedHeader) GetValidators() *types.ValidatorSet {
 	return nil
 }
 
+func (m *SignedHeader) GetAggregatorKeys() [][]byte {
+	if m != nil {
+		return m.AggregatorKeys
+	}
+	return nil
+}
+
 type Data struct {
 	Txs                    [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
 	IntermediateStateRoots [][]byte `protobuf:"bytes,2,rep,name=intermediate_state_roots,json=intermediateStateRoots,proto3" json:"intermediate_state_roots,omitempty"`
+	// Version of the binary encoding of the data, 0 if encoded before versioning was introduced
+	EncodingVersion uint32 `protobuf:"varint,3,opt,name=encoding_version,json=encodingVersion,proto3" json:"encoding_version,omitempty"`
+	// Evidence of misbehavior of aggregators, committed in the block
+	Evidence []*DuplicateHeaderEvidence `protobuf:"bytes,4,rep,name=evidence,proto3" json:"evidence,omitempty"`
 }
 
 func (m *Data) Reset()         { *m = Data{} }
 func (m *Data) String() string { return proto.CompactTextString(m) }
 func (*Data) ProtoMessage()    {}
 func (*Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed489fb7f4d78b3f, []int{4}
+	return fileDescriptor_ed489fb7f4d78b3f, []int{6}
 }
 func (m *Data) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -394,16 +570,85 @@ func (m *Data) GetIntermediateStateRoots() [][]byte {
 	return nil
 }
 
+func (m *Data) GetEncodingVersion() uint32 {
+	if m != nil {
+		return m.EncodingVersion
+	}
+	return 0
+}
+
+func (m *Data) GetEvidence() []*DuplicateHeaderEvidence {
+	if m != nil {
+		return m.Evidence
+	}
+	return nil
+}
+
+// DuplicateHeaderEvidence proves that aggregators signed two different headers at the same height.
+type DuplicateHeaderEvidence struct {
+	HeaderA *SignedHeader `protobuf:"bytes,1,opt,name=header_a,json=headerA,proto3" json:"header_a,omitempty"`
+	HeaderB *SignedHeader `protobuf:"bytes,2,opt,name=header_b,json=headerB,proto3" json:"header_b,omitempty"`
+}
+
+func (m *DuplicateHeaderEvidence) Reset()         { *m = DuplicateHeaderEvidence{} }
+func (m *DuplicateHeaderEvidence) String() string { return proto.CompactTextString(m) }
+func (*DuplicateHeaderEvidence) ProtoMessage()    {}
+func (*DuplicateHeaderEvidence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed489fb7f4d78b3f, []int{7}
+}
+func (m *DuplicateHeaderEvidence) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DuplicateHeaderEvidence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DuplicateHeaderEvidence.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DuplicateHeaderEvidence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DuplicateHeaderEvidence.Merge(m, src)
+}
+func (m *DuplicateHeaderEvidence) XXX_Size() int {
+	return m.Size()
+}
+func (m *DuplicateHeaderEvidence) XXX_DiscardUnknown() {
+	xxx_messageInfo_DuplicateHeaderEvidence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DuplicateHeaderEvidence proto.InternalMessageInfo
+
+func (m *DuplicateHeaderEvidence) GetHeaderA() *SignedHeader {
+	if m != nil {
+		return m.HeaderA
+	}
+	return nil
+}
+
+func (m *DuplicateHeaderEvidence) GetHeaderB() *SignedHeader {
+	if m != nil {
+		return m.HeaderB
+	}
+	return nil
+}
+
 type Block struct {
 	SignedHeader *SignedHeader `protobuf:"bytes,1,opt,name=signed_header,json=signedHeader,proto3" json:"signed_header,omitempty"`
 	Data         *Data         `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// Version of the binary encoding of the block, 0 if encoded before versioning was introduced
+	EncodingVersion uint32 `protobuf:"varint,3,opt,name=encoding_version,json=encodingVersion,proto3" json:"encoding_version,omitempty"`
 }
 
 func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed489fb7f4d78b3f, []int{5}
+	return fileDescriptor_ed489fb7f4d78b3f, []int{8}
 }
 func (m *Block) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -446,6 +691,13 @@ func (m *Block) GetData() *Data {
 	return nil
 }
 
+func (m *Block) GetEncodingVersion() uint32 {
+	if m != nil {
+		return m.EncodingVersion
+	}
+	return 0
+}
+
 type TxWithISRs struct {
 	PreIsr  []byte `protobuf:"bytes,1,opt,name=pre_isr,json=preIsr,proto3" json:"pre_isr,omitempty"`
 	Tx      []byte `protobuf:"bytes,2,opt,name=tx,proto3" json:"tx,omitempty"`
@@ -456,7 +708,7 @@ func (m *TxWithISRs) Reset()         { *m = TxWithISRs{} }
 func (m *TxWithISRs) String() string { return proto.CompactTextString(m) }
 func (*TxWithISRs) ProtoMessage()    {}
 func (*TxWithISRs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed489fb7f4d78b3f, []int{6}
+	return fileDescriptor_ed489fb7f4d78b3f, []int{9}
 }
 func (m *TxWithISRs) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -506,122 +758,433 @@ func (m *TxWithISRs) GetPostIsr() []byte {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Version)(nil), "rollkit.Version")
-	proto.RegisterType((*Header)(nil), "rollkit.Header")
-	proto.RegisterType((*Commit)(nil), "rollkit.Commit")
-	proto.RegisterType((*SignedHeader)(nil), "rollkit.SignedHeader")
-	proto.RegisterType((*Data)(nil), "rollkit.Data")
-	proto.RegisterType((*Block)(nil), "rollkit.Block")
-	proto.RegisterType((*TxWithISRs)(nil), "rollkit.TxWithISRs")
+// StateWitness proves a single key/value pair accessed by a transaction against the pre-state root.
+type StateWitness struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Proof []byte `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
 }
 
-func init() { proto.RegisterFile("rollkit/rollkit.proto", fileDescriptor_ed489fb7f4d78b3f) }
-
-var fileDescriptor_ed489fb7f4d78b3f = []byte{
-	// 641 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x94, 0xdf, 0x6e, 0xd3, 0x3c,
-	0x18, 0xc6, 0x97, 0xb6, 0x6b, 0xba, 0x77, 0xe9, 0xd6, 0x2f, 0xdf, 0xb7, 0x7d, 0x19, 0x48, 0x51,
-	0x89, 0x84, 0x28, 0x43, 0x6a, 0xc5, 0x38, 0x41, 0x1c, 0x20, 0x6d, 0x80, 0xb4, 0x9e, 0x21, 0x17,
-	0x0d, 0x89, 0x93, 0xc8, 0x6d, 0x4c, 0x62, 0xad, 0x8d, 0x2d, 0xdb, 0x9d, 0xca, 0x5d, 0x70, 0x09,
-	0x5c, 0x0e, 0x87, 0x3b, 0xe4, 0x10, 0x6d, 0x5c, 0x08, 0xf2, 0xeb, 0xb4, 0x0b, 0xe3, 0xa4, 0xb5,
-	0x9f, 0xe7, 0xe7, 0xd7, 0xff, 0x9e, 0x18, 0x0e, 0x94, 0x98, 0xcf, 0x2f, 0xb9, 0x19, 0x55, 0xff,
-	0x43, 0xa9, 0x84, 0x11, 0xa1, 0x5f, 0x75, 0x1f, 0xf4, 0x0d, 0x2b, 0x33, 0xa6, 0x16, 0xbc, 0x34,
-	0x23, 0xf3, 0x45, 0x32, 0x3d, 0xba, 0xa2, 0x73, 0x9e, 0x51, 0x23, 0x94, 0x43, 0x93, 0xe7, 0xe0,
-	0x5f, 0x30, 0xa5, 0xb9, 0x28, 0xc3, 0xff, 0x60, 0x7b, 0x3a, 0x17, 0xb3, 0xcb, 0xc8, 0xeb, 0x7b,
-	0x83, 0x16, 0x71, 0x9d, 0xb0, 0x07, 0x4d, 0x2a, 0x65, 0xd4, 0x40, 0xcd, 0x36, 0x93, 0x5f, 0x4d,
-	0x68, 0x9f, 0x33, 0x9a, 0x31, 0x15, 0x1e, 0x83, 0x7f, 0xe5, 0x46, 0xe3, 0xa0, 0xdd, 0x93, 0xde,
-	0x70, 0xbd, 0x92, 0xaa, 0x2a, 0x59, 0x03, 0xe1, 0x21, 0xb4, 0x0b, 0xc6, 0xf3, 0xc2, 0x54, 0xb5,
-	0xaa, 0x5e, 0x18, 0x42, 0xcb, 0xf0, 0x05, 0x8b, 0x9a, 0xa8, 0x62, 0x3b, 0x1c, 0x40, 0x6f, 0x4e,
-	0xb5, 0x49, 0x0b, 0x9c, 0x26, 0x2d, 0xa8, 0x2e, 0xa2, 0x56, 0xdf, 0x1b, 0x04, 0x64, 0xcf, 0xea,
-	0x6e, 0xf6, 0x73, 0xaa, 0x8b, 0x0d, 0x39, 0x13, 0x8b, 0x05, 0x37, 0x8e, 0xdc, 0xbe, 0x23, 0xdf,
-	0xa0, 0x8c, 0xe4, 0x43, 0xd8, 0xc9, 0xa8, 0xa1, 0x0e, 0x69, 0x23, 0xd2, 0xb1, 0x02, 0x9a, 0x8f,
-	0x61, 0x6f, 0x26, 0x4a, 0xcd, 0x4a, 0xbd, 0xd4, 0x8e, 0xf0, 0x91, 0xe8, 0x6e, 0x54, 0xc4, 0x8e,
-	0xa0, 0x43, 0xa5, 0x74, 0x40, 0x07, 0x01, 0x9f, 0x4a, 0x89, 0xd6, 0x31, 0xfc, 0x83, 0x0b, 0x51,
-	0x4c, 0x2f, 0xe7, 0xa6, 0x2a, 0xb2, 0x83, 0xcc, 0xbe, 0x35, 0x88, 0xd3, 0x91, 0x7d, 0x0a, 0x3d,
-	0xa9, 0x84, 0x14, 0x9a, 0xa9, 0x94, 0x66, 0x99, 0x62, 0x5a, 0x47, 0xe0, 0xd0, 0xb5, 0x7e, 0xea,
-	0x64, 0x8b, 0xd2, 0x3c, 0x57, 0x2c, 0xb7, 0x77, 0x56, 0x55, 0xdd, 0x75, 0x68, 0x4d, 0xc7, 0xaa,
-	0x27, 0x70, 0x50, 0xb2, 0x95, 0x49, 0xff, 0xe2, 0x03, 0xe4, 0xff, 0xb5, 0xe6, 0xe9, 0xbd, 0x31,
-	0x47, 0xd0, 0x99, 0x15, 0x94, 0x97, 0x29, 0xcf, 0xa2, 0x6e, 0xdf, 0x1b, 0xec, 0x10, 0x1f, 0xfb,
-	0xe3, 0x2c, 0x19, 0x40, 0xdb, 0x9d, 0x5e, 0x18, 0x03, 0x68, 0x9e, 0x97, 0xd4, 0x2c, 0x15, 0xd3,
-	0x91, 0xd7, 0x6f, 0x0e, 0x02, 0x52, 0x53, 0x92, 0x6f, 0x1e, 0x04, 0x13, 0x9e, 0x97, 0x2c, 0xab,
-	0x62, 0xf1, 0xc4, 0x5e, 0xb5, 0x6d, 0x55, 0xa9, 0xd8, 0xdf, 0xa4, 0xc2, 0x01, 0xa4, 0xb2, 0x2d,
-	0xe8, 0x2e, 0x0e, 0x33, 0x51, 0x07, 0xdd, 0xd4, 0xa4, 0xb2, 0xc3, 0xd7, 0x00, 0x9b, 0xe4, 0x6a,
-	0x8c, 0xca, 0xee, 0x49, 0x3c, 0xbc, 0x4b, 0xf7, 0x10, 0xd3, 0x3d, 0xbc, 0x58, 0x33, 0x13, 0x66,
-	0x48, 0x6d, 0x44, 0x42, 0xa0, 0xf5, 0x96, 0x1a, 0x6a, 0xd3, 0x6c, 0x56, 0xeb, 0x3d, 0xd8, 0x66,
-	0xf8, 0x12, 0x22, 0x5e, 0x1a, 0xa6, 0x16, 0x2c, 0xe3, 0xd4, 0xb0, 0x54, 0x1b, 0xfb, 0xab, 0x84,
-	0x30, 0x3a, 0x6a, 0x20, 0x76, 0x58, 0xf7, 0x27, 0xd6, 0x26, 0xd6, 0x4d, 0x3e, 0xc3, 0xf6, 0x19,
-	0x7e, 0x22, 0xaf, 0xa0, 0xab, 0x71, 0xfb, 0xe9, 0x1f, 0xbb, 0x3e, 0xd8, 0x6c, 0xa6, 0x7e, 0x38,
-	0x24, 0xd0, 0xf5, 0xa3, 0x7a, 0x04, 0x2d, 0x1b, 0xc2, 0x6a, 0xff, 0xdd, 0xcd, 0x10, 0xbb, 0x5a,
-	0x82, 0x56, 0xf2, 0x1e, 0xe0, 0xc3, 0xea, 0x23, 0x37, 0xc5, 0x78, 0x42, 0x74, 0xf8, 0x3f, 0xf8,
-	0x52, 0xb1, 0x94, 0x6b, 0x37, 0x4d, 0x40, 0xda, 0x52, 0xb1, 0xb1, 0x56, 0xe1, 0x1e, 0x34, 0xcc,
-	0x0a, 0xeb, 0x04, 0xa4, 0x61, 0x56, 0xf6, 0x6a, 0xa5, 0xd0, 0x06, 0xc9, 0xa6, 0xcb, 0xaa, 0xed,
-	0x8f, 0xb5, 0x3a, 0x7b, 0xf7, 0xfd, 0x26, 0xf6, 0xae, 0x6f, 0x62, 0xef, 0xe7, 0x4d, 0xec, 0x7d,
-	0xbd, 0x8d, 0xb7, 0xae, 0x6f, 0xe3, 0xad, 0x1f, 0xb7, 0xf1, 0xd6, 0xa7, 0x67, 0x39, 0x37, 0xc5,
-	0x72, 0x3a, 0x9c, 0x89, 0xc5, 0xe8, 0xde, 0xdb, 0x52, 0x3d, 0x20, 0x72, 0xba, 0x16, 0xa6, 0x6d,
-	0x7c, 0x42, 0x5e, 0xfc, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xc1, 0x88, 0xef, 0xaa, 0x86, 0x04, 0x00,
-	0x00,
+func (m *StateWitness) Reset()         { *m = StateWitness{} }
+func (m *StateWitness) String() string { return proto.CompactTextString(m) }
+func (*StateWitness) ProtoMessage()    {}
+func (*StateWitness) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed489fb7f4d78b3f, []int{10}
+}
+func (m *StateWitness) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateWitness) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StateWitness.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StateWitness) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateWitness.Merge(m, src)
+}
+func (m *StateWitness) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateWitness) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateWitness.DiscardUnknown(m)
 }
 
-func (m *Version) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_StateWitness proto.InternalMessageInfo
+
+func (m *StateWitness) GetKey() []byte {
+	if m != nil {
+		return m.Key
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Version) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *StateWitness) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
 }
 
-func (m *Version) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.App != 0 {
-		i = encodeVarintRollkit(dAtA, i, uint64(m.App))
-		i--
-		dAtA[i] = 0x10
+func (m *StateWitness) GetProof() []byte {
+	if m != nil {
+		return m.Proof
 	}
-	if m.Block != 0 {
-		i = encodeVarintRollkit(dAtA, i, uint64(m.Block))
-		i--
-		dAtA[i] = 0x8
+	return nil
+}
+
+type StateWitnesses struct {
+	Witnesses []*StateWitness `protobuf:"bytes,1,rep,name=witnesses,proto3" json:"witnesses,omitempty"`
+}
+
+func (m *StateWitnesses) Reset()         { *m = StateWitnesses{} }
+func (m *StateWitnesses) String() string { return proto.CompactTextString(m) }
+func (*StateWitnesses) ProtoMessage()    {}
+func (*StateWitnesses) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed489fb7f4d78b3f, []int{11}
+}
+func (m *StateWitnesses) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateWitnesses) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StateWitnesses.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *StateWitnesses) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateWitnesses.Merge(m, src)
+}
+func (m *StateWitnesses) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateWitnesses) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateWitnesses.DiscardUnknown(m)
 }
 
-func (m *Header) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_StateWitnesses proto.InternalMessageInfo
+
+func (m *StateWitnesses) GetWitnesses() []*StateWitness {
+	if m != nil {
+		return m.Witnesses
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Header) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// StateFraudProof proves that a state transition committed in a block is invalid.
+type StateFraudProof struct {
+	// Height of the block containing the disputed transition
+	BlockHeight uint64 `protobuf:"varint,1,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	// Index of the disputed transaction in block data
+	TxIndex uint64 `protobuf:"varint,2,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	// Intermediate state root before the disputed transaction
+	PreStateRoot []byte `protobuf:"bytes,3,opt,name=pre_state_root,json=preStateRoot,proto3" json:"pre_state_root,omitempty"`
+	// The disputed transaction
+	Tx []byte `protobuf:"bytes,4,opt,name=tx,proto3" json:"tx,omitempty"`
+	// Intermediate state root committed by the block proposer after the transaction
+	CommittedPostStateRoot []byte `protobuf:"bytes,5,opt,name=committed_post_state_root,json=committedPostStateRoot,proto3" json:"committed_post_state_root,omitempty"`
+	// Intermediate state root computed by the honest node after the transaction
+	ExpectedPostStateRoot []byte `protobuf:"bytes,6,opt,name=expected_post_state_root,json=expectedPostStateRoot,proto3" json:"expected_post_state_root,omitempty"`
+	// Witnesses of all the state accessed by the transaction
+	Witnesses []*StateWitness `protobuf:"bytes,7,rep,name=witnesses,proto3" json:"witnesses,omitempty"`
+	// Number of transactions in the block, locating intermediate state roots in the Merkle tree of block data
+	NumTxs uint64 `protobuf:"varint,8,opt,name=num_txs,json=numTxs,proto3" json:"num_txs,omitempty"`
+	// Merkle proofs of inclusion of the transaction, pre-state root and committed post-state root in the data hash
+	TxProof            *crypto.Proof `protobuf:"bytes,9,opt,name=tx_proof,json=txProof,proto3" json:"tx_proof,omitempty"`
+	PreStateRootProof  *crypto.Proof `protobuf:"bytes,10,opt,name=pre_state_root_proof,json=preStateRootProof,proto3" json:"pre_state_root_proof,omitempty"`
+	PostStateRootProof *crypto.Proof `protobuf:"bytes,11,opt,name=post_state_root_proof,json=postStateRootProof,proto3" json:"post_state_root_proof,omitempty"`
 }
 
-func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ChainId) > 0 {
-		i -= len(m.ChainId)
-		copy(dAtA[i:], m.ChainId)
-		i = encodeVarintRollkit(dAtA, i, uint64(len(m.ChainId)))
-		i--
-		dAtA[i] = 0x6a
+func (m *StateFraudProof) Reset()         { *m = StateFraudProof{} }
+func (m *StateFraudProof) String() string { return proto.CompactTextString(m) }
+func (*StateFraudProof) ProtoMessage()    {}
+func (*StateFraudProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed489fb7f4d78b3f, []int{12}
+}
+func (m *StateFraudProof) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateFraudProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StateFraudProof.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StateFraudProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateFraudProof.Merge(m, src)
+}
+func (m *StateFraudProof) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateFraudProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateFraudProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateFraudProof proto.InternalMessageInfo
+
+func (m *StateFraudProof) GetBlockHeight() uint64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *StateFraudProof) GetTxIndex() uint64 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+func (m *StateFraudProof) GetPreStateRoot() []byte {
+	if m != nil {
+		return m.PreStateRoot
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetTx() []byte {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetCommittedPostStateRoot() []byte {
+	if m != nil {
+		return m.CommittedPostStateRoot
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetExpectedPostStateRoot() []byte {
+	if m != nil {
+		return m.ExpectedPostStateRoot
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetWitnesses() []*StateWitness {
+	if m != nil {
+		return m.Witnesses
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetNumTxs() uint64 {
+	if m != nil {
+		return m.NumTxs
+	}
+	return 0
+}
+
+func (m *StateFraudProof) GetTxProof() *crypto.Proof {
+	if m != nil {
+		return m.TxProof
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetPreStateRootProof() *crypto.Proof {
+	if m != nil {
+		return m.PreStateRootProof
+	}
+	return nil
+}
+
+func (m *StateFraudProof) GetPostStateRootProof() *crypto.Proof {
+	if m != nil {
+		return m.PostStateRootProof
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Version)(nil), "rollkit.Version")
+	proto.RegisterType((*Header)(nil), "rollkit.Header")
+	proto.RegisterType((*HeaderExtension)(nil), "rollkit.HeaderExtension")
+	proto.RegisterType((*HeaderExtensions)(nil), "rollkit.HeaderExtensions")
+	proto.RegisterType((*Commit)(nil), "rollkit.Commit")
+	proto.RegisterType((*SignedHeader)(nil), "rollkit.SignedHeader")
+	proto.RegisterType((*Data)(nil), "rollkit.Data")
+	proto.RegisterType((*DuplicateHeaderEvidence)(nil), "rollkit.DuplicateHeaderEvidence")
+	proto.RegisterType((*Block)(nil), "rollkit.Block")
+	proto.RegisterType((*TxWithISRs)(nil), "rollkit.TxWithISRs")
+	proto.RegisterType((*StateWitness)(nil), "rollkit.StateWitness")
+	proto.RegisterType((*StateWitnesses)(nil), "rollkit.StateWitnesses")
+	proto.RegisterType((*StateFraudProof)(nil), "rollkit.StateFraudProof")
+}
+
+func init() { proto.RegisterFile("rollkit/rollkit.proto", fileDescriptor_ed489fb7f4d78b3f) }
+
+var fileDescriptor_ed489fb7f4d78b3f = []byte{
+	// 1109 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x95, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xc6, 0x8d, 0xe3, 0xb5, 0x8f, 0xd7, 0x3f, 0xd9, 0xc6, 0xe9, 0xb6, 0x88, 0x28, 0x5d, 0x81,
+	0x28, 0x45, 0xb2, 0xa9, 0x7b, 0x01, 0x45, 0x08, 0x29, 0xa1, 0x41, 0xb1, 0xca, 0x45, 0xb4, 0xa9,
+	0x8a, 0xc4, 0xcd, 0x6a, 0xed, 0x1d, 0xec, 0x55, 0xec, 0xdd, 0xd5, 0xce, 0x38, 0x6c, 0x2e, 0x78,
+	0x07, 0x90, 0xb8, 0xe4, 0x0d, 0x78, 0x04, 0x5e, 0x00, 0xee, 0x7a, 0xc9, 0x25, 0x82, 0x17, 0xe1,
+	0xcc, 0x99, 0xd9, 0x1f, 0x9b, 0x94, 0xd2, 0x0b, 0x27, 0x73, 0xce, 0xf9, 0xce, 0x99, 0x99, 0x6f,
+	0xbe, 0x33, 0xb3, 0x30, 0x48, 0xe3, 0xe5, 0xf2, 0x32, 0x14, 0x23, 0xfd, 0x7f, 0x98, 0xa4, 0xb1,
+	0x88, 0x2d, 0x43, 0x9b, 0xf7, 0x8e, 0x04, 0x8b, 0x02, 0x96, 0xae, 0xc2, 0x48, 0x8c, 0xc4, 0x75,
+	0xc2, 0xf8, 0xe8, 0xca, 0x5f, 0x86, 0x81, 0x2f, 0xe2, 0x54, 0x41, 0xef, 0xbd, 0x53, 0x41, 0xcc,
+	0xd2, 0xeb, 0x44, 0xc4, 0x23, 0x0c, 0xc4, 0xdf, 0xaa, 0xb0, 0xf3, 0x08, 0x8c, 0x17, 0x2c, 0xe5,
+	0x61, 0x1c, 0x59, 0xfb, 0xb0, 0x3b, 0x5d, 0xc6, 0xb3, 0x4b, 0xbb, 0x76, 0x54, 0x7b, 0x50, 0x77,
+	0x95, 0x61, 0xf5, 0x61, 0xc7, 0x4f, 0x12, 0xfb, 0x16, 0xf9, 0xe4, 0xd0, 0xf9, 0x65, 0x17, 0x1a,
+	0x67, 0xcc, 0xc7, 0xa2, 0xd6, 0x43, 0x30, 0xae, 0x54, 0x36, 0x25, 0xb5, 0xc7, 0xfd, 0x61, 0xbe,
+	0x50, 0x5d, 0xd5, 0xcd, 0x01, 0xd6, 0x01, 0x34, 0x16, 0x2c, 0x9c, 0x2f, 0x84, 0xae, 0xa5, 0x2d,
+	0xcb, 0x82, 0xba, 0x08, 0x57, 0xcc, 0xde, 0x21, 0x2f, 0x8d, 0xad, 0x07, 0xd0, 0x5f, 0xfa, 0x5c,
+	0x78, 0x0b, 0x9a, 0xc6, 0x5b, 0xf8, 0x7c, 0x61, 0xd7, 0x31, 0x6e, 0xba, 0x5d, 0xe9, 0x57, 0xb3,
+	0x9f, 0xa1, 0xb7, 0x40, 0xce, 0xe2, 0xd5, 0x2a, 0x14, 0x0a, 0xb9, 0x5b, 0x22, 0xbf, 0x20, 0x37,
+	0x21, 0xdf, 0x86, 0x16, 0xf2, 0xe2, 0x2b, 0x48, 0x83, 0x20, 0x4d, 0xe9, 0xa0, 0xe0, 0x7b, 0xd0,
+	0x9d, 0xc5, 0x11, 0x67, 0x11, 0x5f, 0x73, 0x85, 0x30, 0x08, 0xd1, 0x29, 0xbc, 0x04, 0xbb, 0x0b,
+	0x4d, 0x64, 0x40, 0x01, 0x9a, 0x04, 0x30, 0xd0, 0xa6, 0xd0, 0x43, 0xd8, 0xa3, 0x85, 0xa4, 0x8c,
+	0xaf, 0x97, 0x42, 0x17, 0x69, 0x11, 0xa6, 0x27, 0x03, 0xae, 0xf2, 0x13, 0xf6, 0x03, 0xe8, 0x23,
+	0xfb, 0x49, 0xcc, 0x71, 0x6f, 0x7e, 0x10, 0x60, 0x0a, 0xb7, 0x41, 0x41, 0x73, 0xff, 0xb1, 0x72,
+	0x4b, 0xa8, 0x3f, 0x9f, 0xa7, 0x6c, 0x2e, 0x8f, 0x54, 0x57, 0x6d, 0x2b, 0x68, 0xc5, 0x4f, 0x55,
+	0xc7, 0x30, 0x88, 0x58, 0x26, 0xbc, 0x7f, 0xe1, 0x4d, 0xc2, 0xdf, 0x96, 0xc1, 0xe3, 0xad, 0x1c,
+	0xdc, 0xd0, 0x6c, 0xe1, 0x87, 0x91, 0x17, 0x06, 0x76, 0x07, 0x61, 0x2d, 0xd7, 0x20, 0x7b, 0x12,
+	0x58, 0x43, 0xb8, 0x4d, 0x5a, 0x0a, 0xc5, 0xb5, 0x47, 0x8a, 0x51, 0xc5, 0xba, 0x54, 0x6c, 0x2f,
+	0x0f, 0x9d, 0xcb, 0x08, 0x95, 0xfa, 0x08, 0xf6, 0xcb, 0x99, 0xbd, 0x4b, 0x76, 0xad, 0x67, 0xef,
+	0x51, 0x82, 0x55, 0xc6, 0x9e, 0x61, 0x28, 0xa7, 0x81, 0x45, 0xb3, 0x38, 0x08, 0xa3, 0xb9, 0x97,
+	0xcb, 0xa8, 0x8f, 0xe8, 0x8e, 0xdb, 0xcb, 0xfd, 0xb9, 0x36, 0x3f, 0x01, 0xc0, 0xd5, 0xe3, 0x41,
+	0xa0, 0xc1, 0xed, 0xbd, 0xa3, 0x1d, 0xd4, 0x9a, 0x5d, 0x68, 0x4d, 0xe9, 0xe1, 0x34, 0x07, 0xb8,
+	0x15, 0xac, 0xf3, 0x04, 0x7a, 0x5b, 0x61, 0x52, 0x1c, 0xf6, 0x0a, 0x49, 0xb6, 0xe5, 0xd2, 0x58,
+	0xfa, 0xa4, 0x18, 0x48, 0x9b, 0xa6, 0x4b, 0x63, 0xe7, 0x2b, 0xe8, 0x6f, 0xa5, 0xf2, 0xad, 0x85,
+	0xd4, 0xde, 0x60, 0x21, 0x3f, 0xd7, 0xa0, 0xa1, 0xe4, 0x68, 0x1d, 0x02, 0xf0, 0x70, 0x1e, 0xf9,
+	0x62, 0x8d, 0x67, 0x4c, 0x45, 0x4c, 0xb7, 0xe2, 0x91, 0x6a, 0xdc, 0xa4, 0x5e, 0x2f, 0xab, 0xb3,
+	0xc1, 0xba, 0xf5, 0xa8, 0x64, 0x9c, 0x05, 0x5e, 0x91, 0x4f, 0x9d, 0x84, 0xe7, 0x5d, 0xc6, 0x2e,
+	0xf2, 0x90, 0x65, 0x83, 0x21, 0x71, 0xc8, 0xaa, 0xee, 0xa7, 0xdc, 0x74, 0x7e, 0xaf, 0x81, 0x29,
+	0x71, 0x2c, 0xd0, 0xbd, 0xfd, 0xbe, 0xec, 0x57, 0x39, 0xd2, 0xad, 0xdd, 0xdb, 0xda, 0xa5, 0xab,
+	0xc3, 0x12, 0xa8, 0xba, 0x8f, 0x56, 0x59, 0x05, 0xaa, 0xed, 0xba, 0x3a, 0x6c, 0x7d, 0x0e, 0x50,
+	0xdc, 0x4e, 0x9c, 0x56, 0xd9, 0x1e, 0x1f, 0x0e, 0xcb, 0xfb, 0x69, 0x48, 0x37, 0xd8, 0xf0, 0x45,
+	0x8e, 0xb9, 0x60, 0xc2, 0xad, 0x64, 0xe0, 0x44, 0xbd, 0x2d, 0x85, 0xe1, 0x26, 0x24, 0x77, 0xdd,
+	0x4d, 0x71, 0x39, 0xbf, 0xd6, 0xa0, 0xfe, 0x14, 0x4f, 0x50, 0x5e, 0x5e, 0x22, 0xcb, 0x19, 0x96,
+	0x43, 0x3c, 0x3f, 0x1b, 0x67, 0xc2, 0xf9, 0x58, 0x10, 0x22, 0x35, 0x1e, 0x17, 0xf2, 0x2f, 0xd2,
+	0x29, 0x38, 0x2e, 0x5f, 0xc2, 0x0e, 0xaa, 0xf1, 0x0b, 0x19, 0x76, 0x65, 0xf4, 0x46, 0xb5, 0xee,
+	0xdc, 0xac, 0xd6, 0xcf, 0xa0, 0xc9, 0xae, 0xc2, 0x00, 0xdd, 0x8c, 0x56, 0xd8, 0x1e, 0x1f, 0x15,
+	0x9c, 0x3c, 0x5d, 0x27, 0xcb, 0x70, 0x86, 0x55, 0xb5, 0x56, 0x34, 0xce, 0x2d, 0x32, 0x9c, 0xef,
+	0xe1, 0xce, 0x2b, 0x40, 0xd8, 0x63, 0x4d, 0x7d, 0x25, 0xfa, 0xfa, 0x54, 0x06, 0x45, 0xe1, 0xea,
+	0xe1, 0xb9, 0x86, 0x82, 0x1d, 0x57, 0x32, 0xa6, 0xfa, 0x78, 0xfe, 0x3b, 0xe3, 0xc4, 0xf9, 0xb1,
+	0x06, 0xbb, 0x27, 0x74, 0xf5, 0x7f, 0x0a, 0x1d, 0x52, 0x47, 0xe0, 0x6d, 0x08, 0xe1, 0x15, 0x05,
+	0x4c, 0x5e, 0x55, 0xcf, 0xfd, 0x4a, 0x3f, 0xb5, 0xc7, 0x9d, 0x72, 0xfb, 0xe8, 0x54, 0xed, 0xf5,
+	0x06, 0x84, 0x3a, 0xe7, 0x00, 0xcf, 0xb3, 0xaf, 0x43, 0xb1, 0x98, 0x5c, 0xb8, 0xdc, 0xba, 0x03,
+	0x46, 0x92, 0x32, 0x2f, 0xe4, 0x6a, 0x45, 0xa6, 0xdb, 0x40, 0x73, 0xc2, 0x53, 0xab, 0x0b, 0xb7,
+	0x44, 0xa6, 0x7b, 0x05, 0x47, 0xf2, 0x76, 0xc3, 0xcb, 0x54, 0x10, 0x52, 0x35, 0x85, 0x21, 0x6d,
+	0x84, 0x62, 0x6f, 0x9b, 0x74, 0xb6, 0x58, 0x34, 0x92, 0xf7, 0x2c, 0x2a, 0x05, 0x05, 0xa5, 0xeb,
+	0xc9, 0xa1, 0x7c, 0x0e, 0x51, 0x7b, 0x6b, 0xa6, 0xeb, 0x29, 0x43, 0x7a, 0x55, 0x47, 0xaa, 0x7a,
+	0xca, 0x70, 0x4e, 0xa1, 0x5b, 0xad, 0x86, 0x2d, 0xfc, 0x18, 0x5a, 0xdf, 0xe5, 0x86, 0xbe, 0x26,
+	0x2a, 0xbc, 0x55, 0xb0, 0x6e, 0x89, 0x73, 0x7e, 0xaa, 0x43, 0x8f, 0x62, 0x5f, 0xa6, 0xfe, 0x3a,
+	0x50, 0x4d, 0x7e, 0x1f, 0x4c, 0x7a, 0x88, 0x3d, 0xfd, 0x78, 0xaa, 0xc7, 0xb9, 0x4d, 0xbe, 0x33,
+	0xf5, 0x82, 0xe2, 0x36, 0x45, 0xe6, 0x85, 0xd8, 0x46, 0x99, 0x7e, 0x5b, 0x0d, 0x91, 0x4d, 0xa4,
+	0x69, 0xbd, 0x0b, 0x5d, 0x49, 0x55, 0xa9, 0x72, 0xbd, 0x6e, 0x13, 0xbd, 0x85, 0xb6, 0x35, 0x6f,
+	0xf5, 0x82, 0xb7, 0x27, 0x70, 0x57, 0xb5, 0xac, 0xbc, 0x57, 0x88, 0xc1, 0x4a, 0x01, 0xf5, 0xba,
+	0x1e, 0x14, 0x80, 0x73, 0x8c, 0x97, 0xa5, 0x3e, 0x06, 0x9b, 0x65, 0x09, 0x9b, 0xdd, 0x94, 0xa9,
+	0x1e, 0xdd, 0x41, 0x1e, 0xdf, 0x4c, 0xdc, 0x20, 0xcc, 0xf8, 0x7f, 0x84, 0x49, 0x25, 0x44, 0xeb,
+	0x95, 0x27, 0x7b, 0xbc, 0xa9, 0x3e, 0x2a, 0xd0, 0x7c, 0x9e, 0x49, 0xfa, 0x25, 0x25, 0xea, 0xa4,
+	0x5a, 0x24, 0x41, 0xbb, 0x7a, 0xd1, 0xa8, 0x0f, 0xa1, 0x21, 0x31, 0x2c, 0xc9, 0x52, 0x54, 0x4f,
+	0x60, 0x7f, 0x93, 0x2c, 0x5d, 0x00, 0x5e, 0x53, 0x60, 0xaf, 0x4a, 0xa6, 0x2a, 0xf5, 0x0c, 0x06,
+	0x5b, 0xbb, 0xd7, 0xb5, 0xda, 0xaf, 0xa9, 0x65, 0x25, 0x55, 0x56, 0xc8, 0x77, 0x72, 0xfa, 0xdb,
+	0x5f, 0x87, 0xb5, 0x97, 0xf8, 0xfb, 0x13, 0x7f, 0x3f, 0xfc, 0x7d, 0xf8, 0xd6, 0x4b, 0xfc, 0xfd,
+	0x81, 0xbf, 0x6f, 0x3e, 0x9c, 0x63, 0x4f, 0xac, 0xa7, 0x43, 0x3c, 0x8c, 0xd1, 0xd6, 0x97, 0xa2,
+	0xfe, 0x1c, 0x4c, 0xa6, 0xb9, 0x63, 0xda, 0xa0, 0x2f, 0xbe, 0xc7, 0xff, 0x00, 0x31, 0xc0, 0x40,
+	0x78, 0x54, 0x0a, 0x00, 0x00,
+}
+
+func (m *Version) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Version) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Version) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.App != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.App))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Block != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.Block))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Header) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Header) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Extensions) > 0 {
+		for iNdEx := len(m.Extensions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Extensions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollkit(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0x8a
+		}
+	}
+	if m.EncodingVersion != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.EncodingVersion))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if len(m.AggregatorKeysHash) > 0 {
+		i -= len(m.AggregatorKeysHash)
+		copy(dAtA[i:], m.AggregatorKeysHash)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.AggregatorKeysHash)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.ValidityProofHash) > 0 {
+		i -= len(m.ValidityProofHash)
+		copy(dAtA[i:], m.ValidityProofHash)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.ValidityProofHash)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if len(m.ChainId) > 0 {
+		i -= len(m.ChainId)
+		copy(dAtA[i:], m.ChainId)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.ChainId)))
+		i--
+		dAtA[i] = 0x6a
 	}
 	if len(m.NextAggregatorsHash) > 0 {
 		i -= len(m.NextAggregatorsHash)
@@ -711,6 +1274,80 @@ func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *HeaderExtension) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeaderExtension) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeaderExtension) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *HeaderExtensions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeaderExtensions) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HeaderExtensions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Extensions) > 0 {
+		for iNdEx := len(m.Extensions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Extensions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollkit(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Commit) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -731,6 +1368,27 @@ func (m *Commit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Signers) > 0 {
+		i -= len(m.Signers)
+		copy(dAtA[i:], m.Signers)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Signers)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.AggregatedSignature) > 0 {
+		i -= len(m.AggregatedSignature)
+		copy(dAtA[i:], m.AggregatedSignature)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.AggregatedSignature)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ValidityProof) > 0 {
+		i -= len(m.ValidityProof)
+		copy(dAtA[i:], m.ValidityProof)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.ValidityProof)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Signatures) > 0 {
 		for iNdEx := len(m.Signatures) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Signatures[iNdEx])
@@ -763,6 +1421,15 @@ func (m *SignedHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.AggregatorKeys) > 0 {
+		for iNdEx := len(m.AggregatorKeys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AggregatorKeys[iNdEx])
+			copy(dAtA[i:], m.AggregatorKeys[iNdEx])
+			i = encodeVarintRollkit(dAtA, i, uint64(len(m.AggregatorKeys[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if m.Validators != nil {
 		{
 			size, err := m.Validators.MarshalToSizedBuffer(dAtA[:i])
@@ -822,6 +1489,25 @@ func (m *Data) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Evidence) > 0 {
+		for iNdEx := len(m.Evidence) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Evidence[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollkit(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.EncodingVersion != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.EncodingVersion))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.IntermediateStateRoots) > 0 {
 		for iNdEx := len(m.IntermediateStateRoots) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.IntermediateStateRoots[iNdEx])
@@ -843,7 +1529,7 @@ func (m *Data) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *Block) Marshal() (dAtA []byte, err error) {
+func (m *DuplicateHeaderEvidence) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -853,19 +1539,19 @@ func (m *Block) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Block) MarshalTo(dAtA []byte) (int, error) {
+func (m *DuplicateHeaderEvidence) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Block) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *DuplicateHeaderEvidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Data != nil {
+	if m.HeaderB != nil {
 		{
-			size, err := m.Data.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.HeaderB.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -875,9 +1561,9 @@ func (m *Block) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x12
 	}
-	if m.SignedHeader != nil {
+	if m.HeaderA != nil {
 		{
-			size, err := m.SignedHeader.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.HeaderA.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -890,7 +1576,7 @@ func (m *Block) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *TxWithISRs) Marshal() (dAtA []byte, err error) {
+func (m *Block) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -900,26 +1586,78 @@ func (m *TxWithISRs) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TxWithISRs) MarshalTo(dAtA []byte) (int, error) {
+func (m *Block) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TxWithISRs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Block) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.PostIsr) > 0 {
-		i -= len(m.PostIsr)
-		copy(dAtA[i:], m.PostIsr)
-		i = encodeVarintRollkit(dAtA, i, uint64(len(m.PostIsr)))
+	if m.EncodingVersion != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.EncodingVersion))
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x18
 	}
-	if len(m.Tx) > 0 {
-		i -= len(m.Tx)
-		copy(dAtA[i:], m.Tx)
+	if m.Data != nil {
+		{
+			size, err := m.Data.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRollkit(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.SignedHeader != nil {
+		{
+			size, err := m.SignedHeader.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRollkit(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TxWithISRs) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxWithISRs) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxWithISRs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PostIsr) > 0 {
+		i -= len(m.PostIsr)
+		copy(dAtA[i:], m.PostIsr)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.PostIsr)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Tx) > 0 {
+		i -= len(m.Tx)
+		copy(dAtA[i:], m.Tx)
 		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Tx)))
 		i--
 		dAtA[i] = 0x12
@@ -934,6 +1672,203 @@ func (m *TxWithISRs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *StateWitness) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StateWitness) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateWitness) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Proof) > 0 {
+		i -= len(m.Proof)
+		copy(dAtA[i:], m.Proof)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Proof)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StateWitnesses) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StateWitnesses) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateWitnesses) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Witnesses) > 0 {
+		for iNdEx := len(m.Witnesses) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Witnesses[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollkit(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StateFraudProof) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StateFraudProof) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateFraudProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.PostStateRootProof != nil {
+		{
+			size, err := m.PostStateRootProof.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRollkit(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.PreStateRootProof != nil {
+		{
+			size, err := m.PreStateRootProof.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRollkit(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.TxProof != nil {
+		{
+			size, err := m.TxProof.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRollkit(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.NumTxs != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.NumTxs))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Witnesses) > 0 {
+		for iNdEx := len(m.Witnesses) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Witnesses[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRollkit(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.ExpectedPostStateRoot) > 0 {
+		i -= len(m.ExpectedPostStateRoot)
+		copy(dAtA[i:], m.ExpectedPostStateRoot)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.ExpectedPostStateRoot)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.CommittedPostStateRoot) > 0 {
+		i -= len(m.CommittedPostStateRoot)
+		copy(dAtA[i:], m.CommittedPostStateRoot)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.CommittedPostStateRoot)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Tx) > 0 {
+		i -= len(m.Tx)
+		copy(dAtA[i:], m.Tx)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.Tx)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.PreStateRoot) > 0 {
+		i -= len(m.PreStateRoot)
+		copy(dAtA[i:], m.PreStateRoot)
+		i = encodeVarintRollkit(dAtA, i, uint64(len(m.PreStateRoot)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.TxIndex != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.TxIndex))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.BlockHeight != 0 {
+		i = encodeVarintRollkit(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintRollkit(dAtA []byte, offset int, v uint64) int {
 	offset -= sovRollkit(v)
 	base := offset
@@ -1016,6 +1951,55 @@ func (m *Header) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRollkit(uint64(l))
 	}
+	l = len(m.ValidityProofHash)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.AggregatorKeysHash)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	if m.EncodingVersion != 0 {
+		n += 2 + sovRollkit(uint64(m.EncodingVersion))
+	}
+	if len(m.Extensions) > 0 {
+		for _, e := range m.Extensions {
+			l = e.Size()
+			n += 2 + l + sovRollkit(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *HeaderExtension) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	return n
+}
+
+func (m *HeaderExtensions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Extensions) > 0 {
+		for _, e := range m.Extensions {
+			l = e.Size()
+			n += 1 + l + sovRollkit(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1031,6 +2015,18 @@ func (m *Commit) Size() (n int) {
 			n += 1 + l + sovRollkit(uint64(l))
 		}
 	}
+	l = len(m.ValidityProof)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.AggregatedSignature)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.Signers)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
 	return n
 }
 
@@ -1052,6 +2048,12 @@ func (m *SignedHeader) Size() (n int) {
 		l = m.Validators.Size()
 		n += 1 + l + sovRollkit(uint64(l))
 	}
+	if len(m.AggregatorKeys) > 0 {
+		for _, b := range m.AggregatorKeys {
+			l = len(b)
+			n += 1 + l + sovRollkit(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1073,6 +2075,32 @@ func (m *Data) Size() (n int) {
 			n += 1 + l + sovRollkit(uint64(l))
 		}
 	}
+	if m.EncodingVersion != 0 {
+		n += 1 + sovRollkit(uint64(m.EncodingVersion))
+	}
+	if len(m.Evidence) > 0 {
+		for _, e := range m.Evidence {
+			l = e.Size()
+			n += 1 + l + sovRollkit(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DuplicateHeaderEvidence) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.HeaderA != nil {
+		l = m.HeaderA.Size()
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	if m.HeaderB != nil {
+		l = m.HeaderB.Size()
+		n += 1 + l + sovRollkit(uint64(l))
+	}
 	return n
 }
 
@@ -1090,6 +2118,9 @@ func (m *Block) Size() (n int) {
 		l = m.Data.Size()
 		n += 1 + l + sovRollkit(uint64(l))
 	}
+	if m.EncodingVersion != 0 {
+		n += 1 + sovRollkit(uint64(m.EncodingVersion))
+	}
 	return n
 }
 
@@ -1114,7 +2145,95 @@ func (m *TxWithISRs) Size() (n int) {
 	return n
 }
 
-func sovRollkit(x uint64) (n int) {
+func (m *StateWitness) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.Proof)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	return n
+}
+
+func (m *StateWitnesses) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Witnesses) > 0 {
+		for _, e := range m.Witnesses {
+			l = e.Size()
+			n += 1 + l + sovRollkit(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StateFraudProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlockHeight != 0 {
+		n += 1 + sovRollkit(uint64(m.BlockHeight))
+	}
+	if m.TxIndex != 0 {
+		n += 1 + sovRollkit(uint64(m.TxIndex))
+	}
+	l = len(m.PreStateRoot)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.Tx)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.CommittedPostStateRoot)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	l = len(m.ExpectedPostStateRoot)
+	if l > 0 {
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	if len(m.Witnesses) > 0 {
+		for _, e := range m.Witnesses {
+			l = e.Size()
+			n += 1 + l + sovRollkit(uint64(l))
+		}
+	}
+	if m.NumTxs != 0 {
+		n += 1 + sovRollkit(uint64(m.NumTxs))
+	}
+	if m.TxProof != nil {
+		l = m.TxProof.Size()
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	if m.PreStateRootProof != nil {
+		l = m.PreStateRootProof.Size()
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	if m.PostStateRootProof != nil {
+		l = m.PostStateRootProof.Size()
+		n += 1 + l + sovRollkit(uint64(l))
+	}
+	return n
+}
+
+func sovRollkit(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozRollkit(x uint64) (n int) {
@@ -1153,7 +2272,1170 @@ func (m *Version) Unmarshal(dAtA []byte) error {
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
 			}
-			m.Block = 0
+			m.Block = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Block |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+			}
+			m.App = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.App |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Header) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Header: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Header: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Version == nil {
+				m.Version = &Version{}
+			}
+			if err := m.Version.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Time |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastHeaderHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LastHeaderHash = append(m.LastHeaderHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.LastHeaderHash == nil {
+				m.LastHeaderHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastCommitHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LastCommitHash = append(m.LastCommitHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.LastCommitHash == nil {
+				m.LastCommitHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataHash = append(m.DataHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.DataHash == nil {
+				m.DataHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsensusHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConsensusHash = append(m.ConsensusHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.ConsensusHash == nil {
+				m.ConsensusHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppHash = append(m.AppHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.AppHash == nil {
+				m.AppHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastResultsHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LastResultsHash = append(m.LastResultsHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.LastResultsHash == nil {
+				m.LastResultsHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProposerAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ProposerAddress = append(m.ProposerAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.ProposerAddress == nil {
+				m.ProposerAddress = []byte{}
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregatorsHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AggregatorsHash = append(m.AggregatorsHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.AggregatorsHash == nil {
+				m.AggregatorsHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextAggregatorsHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextAggregatorsHash = append(m.NextAggregatorsHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.NextAggregatorsHash == nil {
+				m.NextAggregatorsHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidityProofHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ValidityProofHash = append(m.ValidityProofHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.ValidityProofHash == nil {
+				m.ValidityProofHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregatorKeysHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AggregatorKeysHash = append(m.AggregatorKeysHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.AggregatorKeysHash == nil {
+				m.AggregatorKeysHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodingVersion", wireType)
+			}
+			m.EncodingVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncodingVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extensions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Extensions = append(m.Extensions, &HeaderExtension{})
+			if err := m.Extensions[len(m.Extensions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeaderExtension) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeaderExtension: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeaderExtension: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HeaderExtensions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HeaderExtensions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HeaderExtensions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extensions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Extensions = append(m.Extensions, &HeaderExtension{})
+			if err := m.Extensions[len(m.Extensions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Commit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Commit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Commit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signatures = append(m.Signatures, make([]byte, postIndex-iNdEx))
+			copy(m.Signatures[len(m.Signatures)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidityProof", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ValidityProof = append(m.ValidityProof[:0], dAtA[iNdEx:postIndex]...)
+			if m.ValidityProof == nil {
+				m.ValidityProof = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregatedSignature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AggregatedSignature = append(m.AggregatedSignature[:0], dAtA[iNdEx:postIndex]...)
+			if m.AggregatedSignature == nil {
+				m.AggregatedSignature = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signers", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signers = append(m.Signers[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signers == nil {
+				m.Signers = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SignedHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SignedHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SignedHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &Header{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Commit == nil {
+				m.Commit = &Commit{}
+			}
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Validators", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Validators == nil {
+				m.Validators = &types.ValidatorSet{}
+			}
+			if err := m.Validators.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregatorKeys", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1163,30 +3445,24 @@ func (m *Version) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Block |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
 			}
-			m.App = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowRollkit
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.App |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.AggregatorKeys = append(m.AggregatorKeys, make([]byte, postIndex-iNdEx))
+			copy(m.AggregatorKeys[len(m.AggregatorKeys)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRollkit(dAtA[iNdEx:])
@@ -1208,7 +3484,7 @@ func (m *Version) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Header) Unmarshal(dAtA []byte) error {
+func (m *Data) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1231,17 +3507,17 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Header: wiretype end group for non-group")
+			return fmt.Errorf("proto: Data: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Header: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Data: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1251,33 +3527,29 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Version == nil {
-				m.Version = &Version{}
-			}
-			if err := m.Version.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
+			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IntermediateStateRoots", wireType)
 			}
-			m.Height = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1287,16 +3559,29 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Height |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IntermediateStateRoots = append(m.IntermediateStateRoots, make([]byte, postIndex-iNdEx))
+			copy(m.IntermediateStateRoots[len(m.IntermediateStateRoots)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodingVersion", wireType)
 			}
-			m.Time = 0
+			m.EncodingVersion = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1306,16 +3591,16 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Time |= uint64(b&0x7F) << shift
+				m.EncodingVersion |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastHeaderHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Evidence", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1325,65 +3610,81 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LastHeaderHash = append(m.LastHeaderHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.LastHeaderHash == nil {
-				m.LastHeaderHash = []byte{}
+			m.Evidence = append(m.Evidence, &DuplicateHeaderEvidence{})
+			if err := m.Evidence[len(m.Evidence)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastCommitHash", wireType)
-			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowRollkit
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if byteLen < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthRollkit
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if postIndex > l {
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DuplicateHeaderEvidence) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LastCommitHash = append(m.LastCommitHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.LastCommitHash == nil {
-				m.LastCommitHash = []byte{}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 6:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DuplicateHeaderEvidence: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DuplicateHeaderEvidence: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderA", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1393,31 +3694,33 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DataHash = append(m.DataHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.DataHash == nil {
-				m.DataHash = []byte{}
+			if m.HeaderA == nil {
+				m.HeaderA = &SignedHeader{}
+			}
+			if err := m.HeaderA.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 7:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ConsensusHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderB", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1427,31 +3730,83 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ConsensusHash = append(m.ConsensusHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.ConsensusHash == nil {
-				m.ConsensusHash = []byte{}
+			if m.HeaderB == nil {
+				m.HeaderB = &SignedHeader{}
+			}
+			if err := m.HeaderB.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 8:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Block) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Block: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Block: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SignedHeader", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1461,31 +3816,33 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AppHash = append(m.AppHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.AppHash == nil {
-				m.AppHash = []byte{}
+			if m.SignedHeader == nil {
+				m.SignedHeader = &SignedHeader{}
+			}
+			if err := m.SignedHeader.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 9:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastResultsHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1495,31 +3852,33 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LastResultsHash = append(m.LastResultsHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.LastResultsHash == nil {
-				m.LastResultsHash = []byte{}
+			if m.Data == nil {
+				m.Data = &Data{}
+			}
+			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ProposerAddress", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodingVersion", wireType)
 			}
-			var byteLen int
+			m.EncodingVersion = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1529,29 +3888,64 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.EncodingVersion |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthRollkit
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ProposerAddress = append(m.ProposerAddress[:0], dAtA[iNdEx:postIndex]...)
-			if m.ProposerAddress == nil {
-				m.ProposerAddress = []byte{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxWithISRs) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
 			}
-			iNdEx = postIndex
-		case 11:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxWithISRs: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxWithISRs: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AggregatorsHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PreIsr", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1578,14 +3972,14 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AggregatorsHash = append(m.AggregatorsHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.AggregatorsHash == nil {
-				m.AggregatorsHash = []byte{}
+			m.PreIsr = append(m.PreIsr[:0], dAtA[iNdEx:postIndex]...)
+			if m.PreIsr == nil {
+				m.PreIsr = []byte{}
 			}
 			iNdEx = postIndex
-		case 12:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NextAggregatorsHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tx", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1612,16 +4006,16 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.NextAggregatorsHash = append(m.NextAggregatorsHash[:0], dAtA[iNdEx:postIndex]...)
-			if m.NextAggregatorsHash == nil {
-				m.NextAggregatorsHash = []byte{}
+			m.Tx = append(m.Tx[:0], dAtA[iNdEx:postIndex]...)
+			if m.Tx == nil {
+				m.Tx = []byte{}
 			}
 			iNdEx = postIndex
-		case 13:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ChainId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PostIsr", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1631,23 +4025,25 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ChainId = string(dAtA[iNdEx:postIndex])
+			m.PostIsr = append(m.PostIsr[:0], dAtA[iNdEx:postIndex]...)
+			if m.PostIsr == nil {
+				m.PostIsr = []byte{}
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1670,7 +4066,7 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Commit) Unmarshal(dAtA []byte) error {
+func (m *StateWitness) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1693,15 +4089,15 @@ func (m *Commit) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Commit: wiretype end group for non-group")
+			return fmt.Errorf("proto: StateWitness: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Commit: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StateWitness: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1728,64 +4124,16 @@ func (m *Commit) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Signatures = append(m.Signatures, make([]byte, postIndex-iNdEx))
-			copy(m.Signatures[len(m.Signatures)-1], dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRollkit(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthRollkit
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SignedHeader) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowRollkit
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SignedHeader: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SignedHeader: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1795,33 +4143,31 @@ func (m *SignedHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Header == nil {
-				m.Header = &Header{}
-			}
-			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Proof", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -1831,31 +4177,79 @@ func (m *SignedHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Commit == nil {
-				m.Commit = &Commit{}
+			m.Proof = append(m.Proof[:0], dAtA[iNdEx:postIndex]...)
+			if m.Proof == nil {
+				m.Proof = []byte{}
 			}
-			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRollkit(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 3:
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRollkit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StateWitnesses) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRollkit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StateWitnesses: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StateWitnesses: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Validators", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Witnesses", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1882,10 +4276,8 @@ func (m *SignedHeader) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Validators == nil {
-				m.Validators = &types.ValidatorSet{}
-			}
-			if err := m.Validators.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Witnesses = append(m.Witnesses, &StateWitness{})
+			if err := m.Witnesses[len(m.Witnesses)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1910,7 +4302,7 @@ func (m *SignedHeader) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Data) Unmarshal(dAtA []byte) error {
+func (m *StateFraudProof) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1933,15 +4325,53 @@ func (m *Data) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Data: wiretype end group for non-group")
+			return fmt.Errorf("proto: StateFraudProof: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Data: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StateFraudProof: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxIndex", wireType)
+			}
+			m.TxIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TxIndex |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PreStateRoot", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1968,12 +4398,14 @@ func (m *Data) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
-			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
+			m.PreStateRoot = append(m.PreStateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.PreStateRoot == nil {
+				m.PreStateRoot = []byte{}
+			}
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IntermediateStateRoots", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tx", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -2000,64 +4432,50 @@ func (m *Data) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.IntermediateStateRoots = append(m.IntermediateStateRoots, make([]byte, postIndex-iNdEx))
-			copy(m.IntermediateStateRoots[len(m.IntermediateStateRoots)-1], dAtA[iNdEx:postIndex])
+			m.Tx = append(m.Tx[:0], dAtA[iNdEx:postIndex]...)
+			if m.Tx == nil {
+				m.Tx = []byte{}
+			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRollkit(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommittedPostStateRoot", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthRollkit
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			if byteLen < 0 {
+				return ErrInvalidLengthRollkit
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Block) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowRollkit
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRollkit
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.CommittedPostStateRoot = append(m.CommittedPostStateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.CommittedPostStateRoot == nil {
+				m.CommittedPostStateRoot = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Block: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Block: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SignedHeader", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpectedPostStateRoot", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -2067,31 +4485,29 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SignedHeader == nil {
-				m.SignedHeader = &SignedHeader{}
-			}
-			if err := m.SignedHeader.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.ExpectedPostStateRoot = append(m.ExpectedPostStateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.ExpectedPostStateRoot == nil {
+				m.ExpectedPostStateRoot = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Witnesses", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2118,68 +4534,35 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Data == nil {
-				m.Data = &Data{}
-			}
-			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Witnesses = append(m.Witnesses, &StateWitness{})
+			if err := m.Witnesses[len(m.Witnesses)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRollkit(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthRollkit
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *TxWithISRs) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowRollkit
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumTxs", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.NumTxs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRollkit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumTxs |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: TxWithISRs: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TxWithISRs: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PreIsr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TxProof", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -2189,31 +4572,33 @@ func (m *TxWithISRs) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PreIsr = append(m.PreIsr[:0], dAtA[iNdEx:postIndex]...)
-			if m.PreIsr == nil {
-				m.PreIsr = []byte{}
+			if m.TxProof == nil {
+				m.TxProof = &crypto.Proof{}
+			}
+			if err := m.TxProof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tx", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PreStateRootProof", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -2223,31 +4608,33 @@ func (m *TxWithISRs) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Tx = append(m.Tx[:0], dAtA[iNdEx:postIndex]...)
-			if m.Tx == nil {
-				m.Tx = []byte{}
+			if m.PreStateRootProof == nil {
+				m.PreStateRootProof = &crypto.Proof{}
+			}
+			if err := m.PreStateRootProof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PostIsr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PostStateRootProof", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRollkit
@@ -2257,24 +4644,26 @@ func (m *TxWithISRs) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthRollkit
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthRollkit
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PostIsr = append(m.PostIsr[:0], dAtA[iNdEx:postIndex]...)
-			if m.PostIsr == nil {
-				m.PostIsr = []byte{}
+			if m.PostStateRootProof == nil {
+				m.PostStateRootProof = &crypto.Proof{}
+			}
+			if err := m.PostStateRootProof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	cmcrypto "github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmbytes "github.com/cometbft/cometbft/libs/bytes"
+
+	"github.com/rollkit/rollkit/signer"
+)
+
+// preConfirmationDomain separates signatures of pre-confirmations from signatures of headers made with the same key.
+const preConfirmationDomain = "rollkit/preconfirmation/v1"
+
+var (
+	// ErrInvalidPreConfirmation is returned when pre-confirmation is malformed or not signed by the proposer.
+	ErrInvalidPreConfirmation = errors.New("invalid pre-confirmation")
+	// ErrPreConfirmationViolated is returned when the block doesn't contain pre-confirmed transaction at promised position.
+	ErrPreConfirmationViolated = errors.New("pre-confirmation violated")
+)
+
+// PreConfirmation is a promise of the block proposer to include the transaction at given position of the block
+// at given height. Signed pre-confirmation and a block signed by the same proposer, without the transaction at
+// promised position, prove that the proposer broke the promise.
+type PreConfirmation struct {
+	ChainID         string           `json:"chain_id"`
+	Height          uint64           `json:"height"`
+	TxHash          cmbytes.HexBytes `json:"tx_hash"`
+	Position        uint64           `json:"position"`
+	ProposerAddress cmbytes.HexBytes `json:"proposer_address"`
+	Signature       Signature        `json:"signature"`
+}
+
+// SignBytes returns the message signed by the proposer.
+func (pc *PreConfirmation) SignBytes() []byte {
+	buf := make([]byte, 0, len(preConfirmationDomain)+len(pc.ChainID)+len(pc.TxHash)+24)
+	buf = append(buf, preConfirmationDomain...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(pc.ChainID)))
+	buf = append(buf, pc.ChainID...)
+	buf = binary.BigEndian.AppendUint64(buf, pc.Height)
+	buf = binary.BigEndian.AppendUint64(buf, pc.Position)
+	buf = append(buf, pc.TxHash...)
+	return buf
+}
+
+// ValidateBasic performs basic validation of the pre-confirmation, without verification of the signature.
+func (pc *PreConfirmation) ValidateBasic() error {
+	if pc.ChainID == "" {
+		return fmt.Errorf("%w: empty chain ID", ErrInvalidPreConfirmation)
+	}
+	if pc.Height == 0 {
+		return fmt.Errorf("%w: zero height", ErrInvalidPreConfirmation)
+	}
+	if len(pc.TxHash) != tmhash.Size {
+		return fmt.Errorf("%w: invalid tx hash size %d", ErrInvalidPreConfirmation, len(pc.TxHash))
+	}
+	if len(pc.Signature) == 0 {
+		return fmt.Errorf("%w: no signature", ErrInvalidPreConfirmation)
+	}
+	return nil
+}
+
+// Verify checks that the pre-confirmation is signed by the proposer with given public key.
+func (pc *PreConfirmation) Verify(pubKey cmcrypto.PubKey) error {
+	if err := pc.ValidateBasic(); err != nil {
+		return err
+	}
+	if !bytes.Equal(pc.ProposerAddress, pubKey.Address()) {
+		return fmt.Errorf("%w: proposer address doesn't match the public key", ErrInvalidPreConfirmation)
+	}
+	if !signer.VerifySignature(pubKey, pc.SignBytes(), pc.Signature) {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidPreConfirmation)
+	}
+	return nil
+}
+
+// VerifyInclusion checks that the block contains the pre-confirmed transaction at promised position.
+// ErrPreConfirmationViolated is returned if the block of the pre-confirming proposer breaks the promise.
+func (pc *PreConfirmation) VerifyInclusion(block *Block) error {
+	if block.SignedHeader.ChainID() != pc.ChainID || block.Height() != pc.Height {
+		return fmt.Errorf("block %s/%d doesn't match pre-confirmation %s/%d", block.SignedHeader.ChainID(), block.Height(), pc.ChainID, pc.Height)
+	}
+	if !bytes.Equal(block.SignedHeader.ProposerAddress, pc.ProposerAddress) {
+		return fmt.Errorf("block proposed by %X, pre-confirmation signed by %X", block.SignedHeader.ProposerAddress, pc.ProposerAddress)
+	}
+	if pc.Position >= uint64(len(block.Data.Txs)) {
+		return fmt.Errorf("%w: block has %d transactions, promised position %d", ErrPreConfirmationViolated, len(block.Data.Txs), pc.Position)
+	}
+	if txHash := block.Data.Txs[pc.Position].Hash(); !bytes.Equal(txHash, pc.TxHash) {
+		return fmt.Errorf("%w: transaction %X at position %d, promised %X", ErrPreConfirmationViolated, txHash, pc.Position, pc.TxHash)
+	}
+	return nil
+}
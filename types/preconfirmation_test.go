@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreConfirmation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	block := GetRandomBlock(3, 4)
+	privKey := ed25519.GenPrivKey()
+	block.SignedHeader.ProposerAddress = privKey.PubKey().Address()
+
+	pc := &PreConfirmation{
+		ChainID:         block.SignedHeader.ChainID(),
+		Height:          block.Height(),
+		TxHash:          block.Data.Txs[2].Hash(),
+		Position:        2,
+		ProposerAddress: privKey.PubKey().Address(),
+	}
+	var err error
+	pc.Signature, err = privKey.Sign(pc.SignBytes())
+	require.NoError(err)
+
+	assert.NoError(pc.Verify(privKey.PubKey()))
+	assert.ErrorIs(pc.Verify(ed25519.GenPrivKey().PubKey()), ErrInvalidPreConfirmation)
+	assert.NoError(pc.VerifyInclusion(block))
+
+	// signature covers position of the transaction
+	moved := *pc
+	moved.Position = 1
+	assert.ErrorIs(moved.Verify(privKey.PubKey()), ErrInvalidPreConfirmation)
+	assert.ErrorIs(moved.VerifyInclusion(block), ErrPreConfirmationViolated)
+
+	// transaction missing in the block
+	block.Data.Txs = block.Data.Txs[:2]
+	assert.ErrorIs(pc.VerifyInclusion(block), ErrPreConfirmationViolated)
+
+	// block at different height doesn't violate the pre-confirmation
+	other := GetRandomBlock(4, 0)
+	err = pc.VerifyInclusion(other)
+	assert.Error(err)
+	assert.NotErrorIs(err, ErrPreConfirmationViolated)
+}
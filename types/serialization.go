@@ -1,11 +1,41 @@
 package types
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
 	"github.com/cometbft/cometbft/types"
 
+	"github.com/rollkit/rollkit/crypto/bls"
 	pb "github.com/rollkit/rollkit/types/pb/rollkit"
 )
 
+// EncodingVersion is the version of binary encoding of headers, data and blocks produced by this node.
+//
+// Encodings of older versions (including version 0, used before encoding versioning was introduced) are decoded;
+// encodings of newer versions are rejected with ErrUnsupportedEncodingVersion. Header keeps version of its encoding,
+// so that it's re-encoded to the same bytes and signatures of headers of older versions remain valid.
+//
+// Version 2 introduced header extensions.
+const EncodingVersion uint32 = 2
+
+// headerExtensionsVersion is the first encoding version supporting header extensions.
+const headerExtensionsVersion uint32 = 2
+
+// ErrUnsupportedEncodingVersion is returned when decoding object encoded with a newer version of binary encoding.
+var ErrUnsupportedEncodingVersion = errors.New("unsupported encoding version")
+
+// checkEncodingVersion returns error if the object was encoded with a version newer than EncodingVersion.
+func checkEncodingVersion(object string, version uint32) error {
+	if version > EncodingVersion {
+		return fmt.Errorf("%w: %s encoded with version %d, newest supported version is %d (upgrade the node)",
+			ErrUnsupportedEncodingVersion, object, version, EncodingVersion)
+	}
+	return nil
+}
+
 // MarshalBinary encodes Block into binary form and returns it.
 func (b *Block) MarshalBinary() ([]byte, error) {
 	bp, err := b.ToProto()
@@ -17,6 +47,9 @@ func (b *Block) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary decodes binary form of Block into object.
 func (b *Block) UnmarshalBinary(data []byte) error {
+	if err := checkSize("block", data, MaxBlobSize); err != nil {
+		return err
+	}
 	var pBlock pb.Block
 	err := pBlock.Unmarshal(data)
 	if err != nil {
@@ -33,6 +66,9 @@ func (h *Header) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary decodes binary form of Header into object.
 func (h *Header) UnmarshalBinary(data []byte) error {
+	if err := checkSize("header", data, MaxHeaderSize); err != nil {
+		return err
+	}
 	var pHeader pb.Header
 	err := pHeader.Unmarshal(data)
 	if err != nil {
@@ -44,11 +80,18 @@ func (h *Header) UnmarshalBinary(data []byte) error {
 
 // MarshalBinary encodes Data into binary form and returns it.
 func (d *Data) MarshalBinary() ([]byte, error) {
-	return d.ToProto().Marshal()
+	dp, err := d.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return dp.Marshal()
 }
 
 // UnmarshalBinary decodes binary form of Data into object.
 func (d *Data) UnmarshalBinary(data []byte) error {
+	if err := checkSize("data", data, MaxBlobSize); err != nil {
+		return err
+	}
 	var pData pb.Data
 	err := pData.Unmarshal(data)
 	if err != nil {
@@ -65,6 +108,9 @@ func (c *Commit) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary decodes binary form of Commit into object.
 func (c *Commit) UnmarshalBinary(data []byte) error {
+	if err := checkSize("commit", data, MaxCommitSize); err != nil {
+		return err
+	}
 	var pCommit pb.Commit
 	err := pCommit.Unmarshal(data)
 	if err != nil {
@@ -81,24 +127,37 @@ func (sh *SignedHeader) ToProto() (*pb.SignedHeader, error) {
 		return nil, err
 	}
 	return &pb.SignedHeader{
-		Header:     sh.Header.ToProto(),
-		Commit:     sh.Commit.ToProto(),
-		Validators: vSet,
+		Header:         sh.Header.ToProto(),
+		Commit:         sh.Commit.ToProto(),
+		Validators:     vSet,
+		AggregatorKeys: pubKeysToByteSlices(sh.AggregatorKeys),
 	}, nil
 }
 
 // FromProto fills SignedHeader with data from protobuf representation.
 func (sh *SignedHeader) FromProto(other *pb.SignedHeader) error {
+	if other == nil || other.Header == nil {
+		return fmt.Errorf("%w: missing header", ErrMalformedEncoding)
+	}
 	err := sh.Header.FromProto(other.Header)
 	if err != nil {
 		return err
 	}
-	err = sh.Commit.FromProto(other.Commit)
-	if err != nil {
-		return err
+	sh.Commit = Commit{}
+	if other.Commit != nil {
+		err = sh.Commit.FromProto(other.Commit)
+		if err != nil {
+			return err
+		}
 	}
 
+	if err := checkCount("aggregator keys", len(other.AggregatorKeys), MaxSignatures); err != nil {
+		return err
+	}
 	if other.Validators != nil && other.Validators.GetProposer() != nil {
+		if err := checkCount("aggregators", len(other.Validators.Validators), MaxSignatures); err != nil {
+			return err
+		}
 		validators, err := types.ValidatorSetFromProto(other.Validators)
 		if err != nil {
 			return err
@@ -106,6 +165,7 @@ func (sh *SignedHeader) FromProto(other *pb.SignedHeader) error {
 
 		sh.Validators = validators
 	}
+	sh.AggregatorKeys = byteSlicesToPubKeys(other.AggregatorKeys)
 	return nil
 }
 
@@ -120,6 +180,9 @@ func (sh *SignedHeader) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary decodes binary form of SignedHeader into object.
 func (sh *SignedHeader) UnmarshalBinary(data []byte) error {
+	if err := checkSize("signed header", data, MaxSignedHeaderSize); err != nil {
+		return err
+	}
 	var pHeader pb.SignedHeader
 	err := pHeader.Unmarshal(data)
 	if err != nil {
@@ -151,11 +214,34 @@ func (h *Header) ToProto() *pb.Header {
 		AggregatorsHash:     h.AggregatorsHash[:],
 		NextAggregatorsHash: h.NextAggregatorsHash[:],
 		ChainId:             h.BaseHeader.ChainID,
+		ValidityProofHash:   h.ValidityProofHash[:],
+		AggregatorKeysHash:  h.AggregatorKeysHash[:],
+		EncodingVersion:     h.EncodingVersion,
+		Extensions:          headerExtensionsToProto(h.Extensions),
 	}
 }
 
 // FromProto fills Header with data from its protobuf representation.
 func (h *Header) FromProto(other *pb.Header) error {
+	if other == nil || other.Version == nil {
+		return fmt.Errorf("%w: missing header or its version", ErrMalformedEncoding)
+	}
+	if err := checkEncodingVersion("header", other.EncodingVersion); err != nil {
+		return err
+	}
+	if err := checkHashes("header", other.LastHeaderHash, other.LastCommitHash, other.DataHash, other.ConsensusHash,
+		other.LastResultsHash, other.ProposerAddress, other.AggregatorsHash, other.NextAggregatorsHash,
+		other.ValidityProofHash, other.AggregatorKeysHash); err != nil {
+		return err
+	}
+	if len(other.ChainId) > types.MaxChainIDLen {
+		return fmt.Errorf("%w: chain ID has %d bytes, maximum is %d", ErrMalformedEncoding, len(other.ChainId), types.MaxChainIDLen)
+	}
+	if len(other.Extensions) > 0 && other.EncodingVersion < headerExtensionsVersion {
+		return fmt.Errorf("%w: extensions in header encoded with version %d", ErrInvalidHeaderExtension, other.EncodingVersion)
+	}
+	h.EncodingVersion = other.EncodingVersion
+	h.Extensions = headerExtensionsFromProto(other.Extensions)
 	h.Version.Block = other.Version.Block
 	h.Version.App = other.Version.App
 	h.BaseHeader.ChainID = other.ChainId
@@ -169,6 +255,8 @@ func (h *Header) FromProto(other *pb.Header) error {
 	h.LastResultsHash = other.LastResultsHash
 	h.AggregatorsHash = other.AggregatorsHash
 	h.NextAggregatorsHash = other.NextAggregatorsHash
+	h.ValidityProofHash = other.ValidityProofHash
+	h.AggregatorKeysHash = other.AggregatorKeysHash
 	if len(other.ProposerAddress) > 0 {
 		h.ProposerAddress = make([]byte, len(other.ProposerAddress))
 		copy(h.ProposerAddress, other.ProposerAddress)
@@ -183,42 +271,82 @@ func (b *Block) ToProto() (*pb.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	dp, err := b.Data.ToProto()
+	if err != nil {
+		return nil, err
+	}
 	return &pb.Block{
-		SignedHeader: sp,
-		Data:         b.Data.ToProto(),
+		SignedHeader:    sp,
+		Data:            dp,
+		EncodingVersion: EncodingVersion,
 	}, nil
 }
 
 // ToProto converts Data into protobuf representation and returns it.
-func (d *Data) ToProto() *pb.Data {
+func (d *Data) ToProto() (*pb.Data, error) {
+	evidence, err := evidenceToProto(d.Evidence)
+	if err != nil {
+		return nil, err
+	}
 	return &pb.Data{
 		Txs:                    txsToByteSlices(d.Txs),
 		IntermediateStateRoots: d.IntermediateStateRoots.RawRootsList,
-		// Note: Temporarily remove Evidence #896
-		// Evidence:               evidenceToProto(d.Evidence),
-	}
+		EncodingVersion:        EncodingVersion,
+		Evidence:               evidence,
+	}, nil
 }
 
 // FromProto fills Block with data from its protobuf representation.
 func (b *Block) FromProto(other *pb.Block) error {
-	err := b.SignedHeader.FromProto(other.SignedHeader)
+	if other == nil {
+		return fmt.Errorf("%w: missing block", ErrMalformedEncoding)
+	}
+	err := checkEncodingVersion("block", other.EncodingVersion)
 	if err != nil {
 		return err
 	}
-	err = b.Data.FromProto(other.Data)
+	err = b.SignedHeader.FromProto(other.SignedHeader)
 	if err != nil {
 		return err
 	}
+	b.Data = Data{}
+	if other.Data != nil {
+		err = b.Data.FromProto(other.Data)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 // FromProto fills the Data with data from its protobuf representation
 func (d *Data) FromProto(other *pb.Data) error {
+	if other == nil {
+		return fmt.Errorf("%w: missing data", ErrMalformedEncoding)
+	}
+	if err := checkEncodingVersion("data", other.EncodingVersion); err != nil {
+		return err
+	}
+	if err := checkCount("transactions", len(other.Txs), MaxTxs); err != nil {
+		return err
+	}
+	if err := checkCount("intermediate state roots", len(other.IntermediateStateRoots), MaxIntermediateStateRoots); err != nil {
+		return err
+	}
+	if err := checkHashes("intermediate state root", other.IntermediateStateRoots...); err != nil {
+		return err
+	}
+	if err := checkCount("evidence", len(other.Evidence), MaxEvidence); err != nil {
+		return err
+	}
 	d.Txs = byteSlicesToTxs(other.Txs)
 	d.IntermediateStateRoots.RawRootsList = other.IntermediateStateRoots
-	// Note: Temporarily remove Evidence #896
-	// d.Evidence = evidenceFromProto(other.Evidence)
+	evidence, err := evidenceFromProto(other.Evidence)
+	if err != nil {
+		return err
+	}
+	d.Evidence = evidence
 
 	return nil
 }
@@ -226,13 +354,34 @@ func (d *Data) FromProto(other *pb.Data) error {
 // ToProto converts Commit into protobuf representation and returns it.
 func (c *Commit) ToProto() *pb.Commit {
 	return &pb.Commit{
-		Signatures: signaturesToByteSlices(c.Signatures),
+		Signatures:          signaturesToByteSlices(c.Signatures),
+		ValidityProof:       c.ValidityProof,
+		AggregatedSignature: c.AggregatedSignature,
+		Signers:             c.Signers,
 	}
 }
 
 // FromProto fills Commit with data from its protobuf representation.
 func (c *Commit) FromProto(other *pb.Commit) error {
+	if other == nil {
+		return fmt.Errorf("%w: missing commit", ErrMalformedEncoding)
+	}
+	if err := checkCount("signatures", len(other.Signatures), MaxSignatures); err != nil {
+		return err
+	}
+	if err := checkSignatures(other.AggregatedSignature); err != nil {
+		return err
+	}
+	if err := checkSignatures(other.Signatures...); err != nil {
+		return err
+	}
+	if err := checkCount("signer bitmap bytes", len(other.Signers), (MaxSignatures+7)/8); err != nil {
+		return err
+	}
 	c.Signatures = byteSlicesToSignatures(other.Signatures)
+	c.ValidityProof = other.ValidityProof
+	c.AggregatedSignature = other.AggregatedSignature
+	c.Signers = other.Signers
 
 	return nil
 }
@@ -328,24 +477,70 @@ func byteSlicesToTxs(bytes [][]byte) Txs {
 	return txs
 }
 
-// Note: Temporarily remove Evidence #896
+// MarshalHeaderExtensions encodes header extensions into binary form, e.g. to return them from the application.
+func MarshalHeaderExtensions(extensions []HeaderExtension) ([]byte, error) {
+	return (&pb.HeaderExtensions{Extensions: headerExtensionsToProto(extensions)}).Marshal()
+}
+
+// UnmarshalHeaderExtensions decodes binary form of header extensions.
+func UnmarshalHeaderExtensions(data []byte) ([]HeaderExtension, error) {
+	var pExtensions pb.HeaderExtensions
+	if err := pExtensions.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return headerExtensionsFromProto(pExtensions.Extensions), nil
+}
 
-// func evidenceToProto(evidence EvidenceData) []*abci.Evidence {
-// 	var ret []*abci.Evidence
-// 	for _, e := range evidence.Evidence {
-// 		for i := range e.ABCI() {
-// 			ae := e.ABCI()[i]
-// 			ret = append(ret, &ae)
-// 		}
-// 	}
-// 	return ret
-// }
+func headerExtensionsToProto(extensions []HeaderExtension) []*pb.HeaderExtension {
+	if len(extensions) == 0 {
+		return nil
+	}
+	ret := make([]*pb.HeaderExtension, len(extensions))
+	for i, ext := range extensions {
+		ret[i] = &pb.HeaderExtension{Type: ext.Type, Data: ext.Data}
+	}
+	return ret
+}
 
-// func evidenceFromProto(evidence []*abci.Evidence) EvidenceData {
-// 	var ret EvidenceData
-// 	// TODO(tzdybal): right now Evidence is just an interface without implementations
-// 	return ret
-// }
+func headerExtensionsFromProto(extensions []*pb.HeaderExtension) []HeaderExtension {
+	if len(extensions) == 0 {
+		return nil
+	}
+	ret := make([]HeaderExtension, len(extensions))
+	for i, ext := range extensions {
+		ret[i] = HeaderExtension{Type: ext.Type, Data: ext.Data}
+	}
+	return ret
+}
+
+func evidenceToProto(evidence EvidenceData) ([]*pb.DuplicateHeaderEvidence, error) {
+	if len(evidence.Evidence) == 0 {
+		return nil, nil
+	}
+	ret := make([]*pb.DuplicateHeaderEvidence, len(evidence.Evidence))
+	for i := range evidence.Evidence {
+		ev, err := evidence.Evidence[i].ToProto()
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = ev
+	}
+	return ret, nil
+}
+
+func evidenceFromProto(evidence []*pb.DuplicateHeaderEvidence) (EvidenceData, error) {
+	var ret EvidenceData
+	if len(evidence) == 0 {
+		return ret, nil
+	}
+	ret.Evidence = make([]DuplicateHeaderEvidence, len(evidence))
+	for i := range evidence {
+		if err := ret.Evidence[i].FromProto(evidence[i]); err != nil {
+			return ret, err
+		}
+	}
+	return ret, nil
+}
 
 func signaturesToByteSlices(sigs []Signature) [][]byte {
 	if sigs == nil {
@@ -368,3 +563,124 @@ func byteSlicesToSignatures(bytes [][]byte) []Signature {
 	}
 	return sigs
 }
+
+func pubKeysToByteSlices(keys []bls.PubKey) [][]byte {
+	if keys == nil {
+		return nil
+	}
+	bytes := make([][]byte, len(keys))
+	for i := range keys {
+		bytes[i] = keys[i]
+	}
+	return bytes
+}
+
+func byteSlicesToPubKeys(bytes [][]byte) []bls.PubKey {
+	if bytes == nil {
+		return nil
+	}
+	keys := make([]bls.PubKey, len(bytes))
+	for i := range bytes {
+		keys[i] = bytes[i]
+	}
+	return keys
+}
+
+// MarshalBinary encodes StateFraudProof into binary form and returns it.
+func (fp *StateFraudProof) MarshalBinary() ([]byte, error) {
+	return fp.ToProto().Marshal()
+}
+
+// UnmarshalBinary decodes binary form of StateFraudProof into object.
+func (fp *StateFraudProof) UnmarshalBinary(data []byte) error {
+	var pProof pb.StateFraudProof
+	err := pProof.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return fp.FromProto(&pProof)
+}
+
+// ToProto converts StateFraudProof into protobuf representation and returns it.
+func (fp *StateFraudProof) ToProto() *pb.StateFraudProof {
+	return &pb.StateFraudProof{
+		BlockHeight:            fp.BlockHeight,
+		TxIndex:                fp.TxIndex,
+		PreStateRoot:           fp.PreStateRoot,
+		Tx:                     fp.Tx,
+		CommittedPostStateRoot: fp.CommittedPostStateRoot,
+		ExpectedPostStateRoot:  fp.ExpectedPostStateRoot,
+		Witnesses:              StateWitnessesToProto(fp.Witnesses),
+		NumTxs:                 fp.NumTxs,
+		TxProof:                inclusionProofToProto(fp.TxProof),
+		PreStateRootProof:      inclusionProofToProto(fp.PreStateRootProof),
+		PostStateRootProof:     inclusionProofToProto(fp.PostStateRootProof),
+	}
+}
+
+// FromProto fills StateFraudProof with data from its protobuf representation.
+func (fp *StateFraudProof) FromProto(other *pb.StateFraudProof) error {
+	fp.BlockHeight = other.BlockHeight
+	fp.TxIndex = other.TxIndex
+	fp.PreStateRoot = other.PreStateRoot
+	fp.Tx = other.Tx
+	fp.CommittedPostStateRoot = other.CommittedPostStateRoot
+	fp.ExpectedPostStateRoot = other.ExpectedPostStateRoot
+	fp.Witnesses = StateWitnessesFromProto(other.Witnesses)
+	fp.NumTxs = other.NumTxs
+	var err error
+	if fp.TxProof, err = inclusionProofFromProto(other.TxProof); err != nil {
+		return fmt.Errorf("invalid transaction proof: %w", err)
+	}
+	if fp.PreStateRootProof, err = inclusionProofFromProto(other.PreStateRootProof); err != nil {
+		return fmt.Errorf("invalid pre-state root proof: %w", err)
+	}
+	if fp.PostStateRootProof, err = inclusionProofFromProto(other.PostStateRootProof); err != nil {
+		return fmt.Errorf("invalid post-state root proof: %w", err)
+	}
+	return nil
+}
+
+// inclusionProofToProto converts Merkle proof into protobuf representation, or nil if the proof is empty.
+func inclusionProofToProto(proof merkle.Proof) *cmcrypto.Proof {
+	if proof.Total == 0 {
+		return nil
+	}
+	return proof.ToProto()
+}
+
+// inclusionProofFromProto converts protobuf representation of Merkle proof into object. Missing proof is empty.
+func inclusionProofFromProto(pProof *cmcrypto.Proof) (merkle.Proof, error) {
+	if pProof == nil {
+		return merkle.Proof{}, nil
+	}
+	proof, err := merkle.ProofFromProto(pProof)
+	if err != nil {
+		return merkle.Proof{}, err
+	}
+	return *proof, nil
+}
+
+// StateWitnessesToProto converts state witnesses into protobuf representation.
+func StateWitnessesToProto(witnesses []StateWitness) []*pb.StateWitness {
+	if witnesses == nil {
+		return nil
+	}
+	pWitnesses := make([]*pb.StateWitness, len(witnesses))
+	for i, w := range witnesses {
+		pWitnesses[i] = &pb.StateWitness{Key: w.Key, Value: w.Value, Proof: w.Proof}
+	}
+	return pWitnesses
+}
+
+// StateWitnessesFromProto converts protobuf representation of state witnesses into objects.
+func StateWitnessesFromProto(pWitnesses []*pb.StateWitness) []StateWitness {
+	if pWitnesses == nil {
+		return nil
+	}
+	witnesses := make([]StateWitness, len(pWitnesses))
+	for i, w := range pWitnesses {
+		witnesses[i] = StateWitness{Key: w.Key, Value: w.Value, Proof: w.Proof}
+	}
+	return witnesses
+}
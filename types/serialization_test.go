@@ -24,7 +24,7 @@ func TestBlockSerializationRoundTrip(t *testing.T) {
 
 	// create random hashes
 	h := []Hash{}
-	for i := 0; i < 8; i++ {
+	for i := 0; i < 9; i++ {
 		h1 := make(Hash, 32)
 		n, err := rand.Read(h1[:])
 		require.Equal(32, n)
@@ -50,6 +50,7 @@ func TestBlockSerializationRoundTrip(t *testing.T) {
 		ProposerAddress:     []byte{4, 3, 2, 1},
 		AggregatorsHash:     h[6],
 		NextAggregatorsHash: h[7],
+		ValidityProofHash:   h[8],
 	}
 
 	pubKey1 := ed25519.GenPrivKey().PubKey()
@@ -66,7 +67,8 @@ func TestBlockSerializationRoundTrip(t *testing.T) {
 			SignedHeader: SignedHeader{
 				Header: h1,
 				Commit: Commit{
-					Signatures: []Signature{Signature([]byte{1, 1, 1}), Signature([]byte{2, 2, 2})},
+					Signatures:    []Signature{Signature([]byte{1, 1, 1}), Signature([]byte{2, 2, 2})},
+					ValidityProof: []byte{3, 3, 3},
 				},
 				Validators: &cmtypes.ValidatorSet{
 					Validators: []*cmtypes.Validator{
@@ -200,6 +202,132 @@ func TestStateRoundTrip(t *testing.T) {
 	}
 }
 
+func TestEncodingVersion(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// header encoded before versioning keeps its encoding, so its signature remains valid
+	legacy, _, err := GetRandomSignedHeader()
+	require.NoError(err)
+	require.Zero(legacy.Header.EncodingVersion)
+	legacyBytes, err := legacy.MarshalBinary()
+	require.NoError(err)
+	var decoded SignedHeader
+	require.NoError(decoded.UnmarshalBinary(legacyBytes))
+	assert.Zero(decoded.Header.EncodingVersion)
+	assert.NoError(decoded.ValidateBasic())
+	reencoded, err := decoded.MarshalBinary()
+	require.NoError(err)
+	assert.Equal(legacyBytes, reencoded)
+
+	// data and blocks are always encoded with the current version
+	block := GetRandomBlock(1, 2)
+	pBlock, err := block.ToProto()
+	require.NoError(err)
+	assert.Equal(EncodingVersion, pBlock.EncodingVersion)
+	assert.Equal(EncodingVersion, pBlock.Data.EncodingVersion)
+	pBlock.EncodingVersion, pBlock.Data.EncodingVersion = 0, 0
+	legacyBytes, err = pBlock.Marshal()
+	require.NoError(err)
+	var decodedBlock Block
+	assert.NoError(decodedBlock.UnmarshalBinary(legacyBytes))
+
+	// newer versions are rejected
+	newer := EncodingVersion + 1
+	cases := []struct {
+		name    string
+		encode  func() ([]byte, error)
+		decoded interface{ UnmarshalBinary([]byte) error }
+	}{
+		{"header", func() ([]byte, error) {
+			pHeader := block.SignedHeader.Header.ToProto()
+			pHeader.EncodingVersion = newer
+			return pHeader.Marshal()
+		}, new(Header)},
+		{"data", func() ([]byte, error) {
+			pData, err := block.Data.ToProto()
+			if err != nil {
+				return nil, err
+			}
+			pData.EncodingVersion = newer
+			return pData.Marshal()
+		}, new(Data)},
+		{"block", func() ([]byte, error) {
+			pBlock, err := block.ToProto()
+			if err != nil {
+				return nil, err
+			}
+			pBlock.EncodingVersion = newer
+			return pBlock.Marshal()
+		}, new(Block)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			bytes, err := c.encode()
+			require.NoError(err)
+			err = c.decoded.UnmarshalBinary(bytes)
+			assert.ErrorIs(err, ErrUnsupportedEncodingVersion)
+		})
+	}
+}
+
+func TestHeaderExtensions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	signedHeader, privKey, err := GetRandomSignedHeader()
+	require.NoError(err)
+	signedHeader.EncodingVersion = EncodingVersion
+	signedHeader.Extensions = []HeaderExtension{
+		{Type: "bridge_root", Data: GetRandomBytes(32)},
+		{Type: "metadata", Data: []byte("app metadata")},
+	}
+	commit, err := getCommit(signedHeader.Header, privKey)
+	require.NoError(err)
+	signedHeader.Commit = *commit
+
+	encoded, err := signedHeader.MarshalBinary()
+	require.NoError(err)
+	var decoded SignedHeader
+	require.NoError(decoded.UnmarshalBinary(encoded))
+	assert.Equal(signedHeader.Extensions, decoded.Extensions)
+	assert.NoError(decoded.ValidateBasic())
+	data, ok := decoded.Extension("metadata")
+	assert.True(ok)
+	assert.Equal([]byte("app metadata"), data)
+
+	// extensions are covered by the signature
+	decoded.Extensions[0].Data = GetRandomBytes(32)
+	assert.ErrorIs(decoded.ValidateBasic(), ErrSignatureVerificationFailed)
+
+	// extensions are not supported by older encoding versions
+	pHeader := signedHeader.Header.ToProto()
+	pHeader.EncodingVersion = headerExtensionsVersion - 1
+	encoded, err = pHeader.Marshal()
+	require.NoError(err)
+	assert.ErrorIs(new(Header).UnmarshalBinary(encoded), ErrInvalidHeaderExtension)
+
+	// application encoding of extensions
+	encoded, err = MarshalHeaderExtensions(signedHeader.Extensions)
+	require.NoError(err)
+	extensions, err := UnmarshalHeaderExtensions(encoded)
+	require.NoError(err)
+	assert.Equal(signedHeader.Extensions, extensions)
+
+	cases := []struct {
+		name       string
+		extensions []HeaderExtension
+	}{
+		{"empty type", []HeaderExtension{{Type: "", Data: []byte{1}}}},
+		{"duplicated type", []HeaderExtension{{Type: "a"}, {Type: "a"}}},
+		{"too large", []HeaderExtension{{Type: "a", Data: make([]byte, MaxHeaderExtensionSize+1)}}},
+		{"too many", make([]HeaderExtension, MaxHeaderExtensions+1)},
+	}
+	for _, c := range cases {
+		assert.ErrorIs(ValidateHeaderExtensions(c.extensions), ErrInvalidHeaderExtension, c.name)
+	}
+}
+
 func TestTxsRoundtrip(t *testing.T) {
 	// Test the nil case
 	var txs Txs
@@ -247,3 +375,133 @@ func TestSignaturesRoundtrip(t *testing.T) {
 		assert.Equal(t, newSigs[i], sigs[i])
 	}
 }
+
+func TestStateFraudProofRoundTrip(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	proof := &StateFraudProof{
+		BlockHeight:            12,
+		TxIndex:                3,
+		PreStateRoot:           []byte{1, 2, 3},
+		Tx:                     Tx{4, 5, 6},
+		CommittedPostStateRoot: []byte{7, 8, 9},
+		ExpectedPostStateRoot:  []byte{10, 11, 12},
+		Witnesses: []StateWitness{
+			{Key: []byte("k1"), Value: []byte("v1"), Proof: []byte("p1")},
+			{Key: []byte("k2"), Value: []byte("v2"), Proof: []byte("p2")},
+		},
+	}
+	require.NoError(proof.ValidateBasic())
+
+	bytes, err := proof.MarshalBinary()
+	require.NoError(err)
+
+	var decoded StateFraudProof
+	require.NoError(decoded.UnmarshalBinary(bytes))
+	require.Equal(proof, &decoded)
+
+	require.Error((&StateFraudProof{}).ValidateBasic())
+}
+
+func TestStateFraudProofInclusion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	data := &Data{
+		Txs: Txs{{1}, {2}, {3}},
+		IntermediateStateRoots: IntermediateStateRoots{
+			RawRootsList: [][]byte{{10}, {11}, {12}, {13}},
+		},
+	}
+	dataHash, err := data.Hash()
+	require.NoError(err)
+
+	proof := &StateFraudProof{
+		BlockHeight:            5,
+		TxIndex:                1,
+		PreStateRoot:           []byte{11},
+		Tx:                     Tx{2},
+		CommittedPostStateRoot: []byte{12},
+		ExpectedPostStateRoot:  []byte{42},
+	}
+	require.NoError(proof.AddInclusionProofs(data, DefaultMerkleHasher))
+	require.Equal(uint64(3), proof.NumTxs)
+	require.NoError(proof.VerifyInclusion(dataHash, DefaultMerkleHasher))
+
+	bytes, err := proof.MarshalBinary()
+	require.NoError(err)
+	var decoded StateFraudProof
+	require.NoError(decoded.UnmarshalBinary(bytes))
+	require.Equal(proof, &decoded)
+	require.NoError(decoded.VerifyInclusion(dataHash, DefaultMerkleHasher))
+
+	// state root not committed at the position of the pre-state root
+	decoded.PreStateRoot = []byte{10}
+	require.ErrorIs(decoded.VerifyInclusion(dataHash, DefaultMerkleHasher), ErrInvalidMerkleProof)
+
+	// positions of state roots inconsistent with the number of transactions
+	decoded.PreStateRoot = proof.PreStateRoot
+	decoded.NumTxs = 2
+	require.ErrorIs(decoded.VerifyInclusion(dataHash, DefaultMerkleHasher), ErrInvalidMerkleProof)
+
+	// proof without inclusion proofs
+	require.Error((&StateFraudProof{BlockHeight: 5, NumTxs: 3}).VerifyInclusion(dataHash, DefaultMerkleHasher))
+}
+
+func TestUnmarshalLimits(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	block := GetRandomBlock(1, 2)
+	marshal := func(m interface{ Marshal() ([]byte, error) }) []byte {
+		bz, err := m.Marshal()
+		require.NoError(err)
+		return bz
+	}
+
+	oversized := []struct {
+		name    string
+		limit   int
+		decoded interface{ UnmarshalBinary([]byte) error }
+	}{
+		{"header", MaxHeaderSize, new(Header)},
+		{"signed header", MaxSignedHeaderSize, new(SignedHeader)},
+		{"commit", MaxCommitSize, new(Commit)},
+		{"data", MaxBlobSize, new(Data)},
+		{"block", MaxBlobSize, new(Block)},
+	}
+	for _, c := range oversized {
+		assert.ErrorIs(c.decoded.UnmarshalBinary(make([]byte, c.limit+1)), ErrSizeLimitExceeded, c.name)
+	}
+
+	pHeader := block.SignedHeader.Header.ToProto()
+	pHeader.Version = nil
+	pLongHash := block.SignedHeader.Header.ToProto()
+	pLongHash.DataHash = make([]byte, MaxHashSize+1)
+	pData, err := block.Data.ToProto()
+	require.NoError(err)
+	pData.IntermediateStateRoots = [][]byte{make([]byte, MaxHashSize+1)}
+	malformed := []struct {
+		name    string
+		encoded []byte
+		decoded interface{ UnmarshalBinary([]byte) error }
+	}{
+		{"block without header", marshal(&pb.Block{}), new(Block)},
+		{"signed header without header", marshal(&pb.SignedHeader{}), new(SignedHeader)},
+		{"header without version", marshal(pHeader), new(Header)},
+		{"header with long hash", marshal(pLongHash), new(Header)},
+		{"long intermediate state root", marshal(pData), new(Data)},
+		{"long signature", marshal(&pb.Commit{Signatures: [][]byte{make([]byte, MaxSignatureSize+1)}}), new(Commit)},
+	}
+	for _, c := range malformed {
+		assert.ErrorIs(c.decoded.UnmarshalBinary(c.encoded), ErrMalformedEncoding, c.name)
+	}
+
+	// truncated encodings never cause panics
+	encoded, err := block.MarshalBinary()
+	require.NoError(err)
+	for i := range encoded {
+		assert.NotPanics(func() { _ = new(Block).UnmarshalBinary(encoded[:i]) })
+	}
+}
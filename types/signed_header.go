@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/celestiaorg/go-header"
-	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmcrypto "github.com/cometbft/cometbft/crypto"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
 	cmtypes "github.com/cometbft/cometbft/types"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/signer"
 )
 
 // SignedHeader combines Header and its Commit.
@@ -17,6 +22,12 @@ type SignedHeader struct {
 	Header
 	Commit     Commit
 	Validators *cmtypes.ValidatorSet
+	// AggregatorKeys are BLS public keys of aggregators, ordered like in the aggregator set.
+	// They are required to verify aggregated signature of the commit.
+	AggregatorKeys []bls.PubKey
+
+	// verified is set when signatures of the commit were verified by VerifyCommits.
+	verified *verifiedCommit
 }
 
 // New creates a new SignedHeader.
@@ -29,6 +40,10 @@ func (sh *SignedHeader) IsZero() bool {
 	return sh == nil
 }
 
+// DefaultCommitThreshold is the default fraction of the total voting power of the aggregator set, that has to be
+// exceeded by aggregators signing a commit.
+var DefaultCommitThreshold = cmtmath.Fraction{Numerator: 2, Denominator: 3}
+
 var (
 	// ErrNonAdjacentHeaders is returned when the headers are not adjacent.
 	ErrNonAdjacentHeaders = errors.New("non-adjacent headers")
@@ -84,6 +99,34 @@ func (sh *SignedHeader) Verify(untrstH *SignedHeader) error {
 	return nil
 }
 
+// ValidateWithLast checks that the header directly follows the last header: chain ID and height are consistent,
+// header time is after the time of the last header, and LastHeaderHash and LastCommitHash link to the last header.
+//
+// Unlike Verify, it doesn't tolerate gaps between the headers, and it also checks chain ID and time.
+func (sh *SignedHeader) ValidateWithLast(last *SignedHeader) error {
+	if last == nil {
+		return errors.New("last header cannot be nil")
+	}
+	if sh.ChainID() != last.ChainID() {
+		return fmt.Errorf("chain ID mismatch: expected %q, got %q", last.ChainID(), sh.ChainID())
+	}
+	if sh.Height() != last.Height()+1 {
+		return fmt.Errorf("%w: header %d, last header %d", ErrNonAdjacentHeaders, sh.Height(), last.Height())
+	}
+	if !sh.Time().After(last.Time()) {
+		return fmt.Errorf("%w: %v <= %v", ErrNonMonotonicTime, sh.Time(), last.Time())
+	}
+	lastHash := last.Hash()
+	if !bytes.Equal(sh.LastHeaderHash[:], lastHash) {
+		return fmt.Errorf("%w: expected %v, got %v", ErrLastHeaderHashMismatch, lastHash, sh.LastHeaderHash)
+	}
+	lastCommitHash := last.Commit.GetCommitHash(&sh.Header, sh.ProposerAddress)
+	if !bytes.Equal(sh.LastCommitHash[:], lastCommitHash) {
+		return fmt.Errorf("%w: expected %X, got %X", ErrLastCommitHashMismatch, lastCommitHash, sh.LastCommitHash)
+	}
+	return nil
+}
+
 var (
 	// ErrAggregatorSetHashMismatch is returned when the aggregator set hash
 	// in the signed header doesn't match the hash of the validator set.
@@ -91,6 +134,27 @@ var (
 	// ErrSignatureVerificationFailed is returned when the signature
 	// verification fails
 	ErrSignatureVerificationFailed = errors.New("signature verification failed")
+	// ErrCommitSignaturesCount is returned when the number of commit signatures
+	// doesn't match the size of the aggregator set.
+	ErrCommitSignaturesCount = errors.New("number of commit signatures doesn't match aggregator set size")
+	// ErrInsufficientVotingPower is returned when aggregators that signed the commit
+	// don't have enough voting power.
+	ErrInsufficientVotingPower = errors.New("insufficient voting power signed the commit")
+	// ErrAggregatorKeysHashMismatch is returned when the aggregator keys hash
+	// in the signed header doesn't match the hash of aggregator keys.
+	ErrAggregatorKeysHashMismatch = errors.New("aggregator keys hash in signed header and hash of aggregator keys do not match")
+	// ErrValidityProofHashMismatch is returned when the validity proof hash
+	// in the header doesn't match the hash of the validity proof in the commit.
+	ErrValidityProofHashMismatch = errors.New("validity proof hash in header and hash of validity proof do not match")
+	// ErrUnauthorizedProposer is returned when the proposer of the header is not a member
+	// of the aggregator set, or didn't sign the header.
+	ErrUnauthorizedProposer = errors.New("proposer is not an aggregator that signed the header")
+	// ErrNoAggregatorSet is returned by VerifyCommits for headers without aggregator set
+	// (based rollups), which have no signatures to verify.
+	ErrNoAggregatorSet = errors.New("header has no aggregator set")
+	// ErrUnexpectedAggregatorKeys is returned by VerifyCommits when the header carries
+	// BLS keys of aggregators different from the expected ones.
+	ErrUnexpectedAggregatorKeys = errors.New("unexpected aggregator keys")
 )
 
 // ValidateBasic performs basic validation of a signed header.
@@ -103,6 +167,10 @@ func (sh *SignedHeader) ValidateBasic() error {
 		return err
 	}
 
+	if err := sh.validateValidityProof(); err != nil {
+		return err
+	}
+
 	// Handle Based Rollup case
 	if sh.Validators == nil || len(sh.Validators.Validators) == 0 {
 		return nil
@@ -116,23 +184,189 @@ func (sh *SignedHeader) ValidateBasic() error {
 		return ErrAggregatorSetHashMismatch
 	}
 
-	// Make sure there is exactly one signature
-	if len(sh.Commit.Signatures) != 1 {
-		return errors.New("expected exactly one signature")
+	if (len(sh.AggregatorKeys) > 0 || len(sh.AggregatorKeysHash) > 0) &&
+		!bytes.Equal(AggregatorKeysHash(sh.AggregatorKeys), sh.AggregatorKeysHash[:]) {
+		return ErrAggregatorKeysHashMismatch
 	}
 
-	signature := sh.Commit.Signatures[0]
-	proposer := sh.Validators.GetProposer()
-	var pubKey ed25519.PubKey = proposer.PubKey.Bytes()
-	msg, err := sh.Header.MarshalBinary()
+	_, _, err := sh.verifySignatures()
+	return err
+}
+
+// VerifyCommit checks that aggregators with more than threshold of the total voting power of the aggregator set
+// signed the header. Zero threshold means DefaultCommitThreshold.
+func (sh *SignedHeader) VerifyCommit(threshold cmtmath.Fraction) error {
+	// Handle Based Rollup case
+	if sh.Validators == nil || len(sh.Validators.Validators) == 0 {
+		return nil
+	}
+	if threshold.Denominator == 0 {
+		threshold = DefaultCommitThreshold
+	}
+	signed, total, err := sh.verifySignatures()
 	if err != nil {
-		return errors.New("signature verification failed, unable to marshal header")
+		return err
 	}
-	if !pubKey.VerifySignature(msg, signature) {
-		return ErrSignatureVerificationFailed
+	// signed / total > numerator / denominator
+	lhs := new(big.Int).Mul(big.NewInt(signed), new(big.Int).SetUint64(threshold.Denominator))
+	rhs := new(big.Int).Mul(big.NewInt(total), new(big.Int).SetUint64(threshold.Numerator))
+	if lhs.Cmp(rhs) <= 0 {
+		return fmt.Errorf("%w: signed %d of %d, threshold %s", ErrInsufficientVotingPower, signed, total, threshold)
 	}
+	return nil
+}
 
+// VerifyProposer checks that the proposer of the header is a member of the aggregator set and signed the header.
+// Signatures themselves are verified by ValidateBasic. Headers without aggregator set (based rollups) have no
+// authorized proposer, so they are not checked.
+func (sh *SignedHeader) VerifyProposer() error {
+	if sh.Validators == nil || len(sh.Validators.Validators) == 0 {
+		return nil
+	}
+	idx, _ := sh.Validators.GetByAddress(sh.ProposerAddress)
+	if idx < 0 {
+		return fmt.Errorf("%w: %X not in aggregator set", ErrUnauthorizedProposer, sh.ProposerAddress)
+	}
+	var signed bool
+	if len(sh.Commit.AggregatedSignature) > 0 {
+		signed = sh.Commit.Signed(int(idx))
+	} else {
+		signed = int(idx) < len(sh.Commit.Signatures) && len(sh.Commit.Signatures[idx]) > 0
+	}
+	if !signed {
+		return fmt.Errorf("%w: %X didn't sign", ErrUnauthorizedProposer, sh.ProposerAddress)
+	}
 	return nil
 }
 
+// RecoverProposer recovers public key of the proposer from its signature of the header, and checks that it matches
+// ProposerAddress. It allows verification of ProposerAddress without the aggregator set (e.g. in based rollups),
+// but only with signature schemes allowing recovery of public keys (secp256k1). For other schemes, and for
+// aggregated BLS signatures, signer.ErrNotRecoverable is returned.
+func (sh *SignedHeader) RecoverProposer(scheme string) (cmcrypto.PubKey, error) {
+	if len(sh.Commit.AggregatedSignature) > 0 {
+		return nil, fmt.Errorf("%w: aggregated signature", signer.ErrNotRecoverable)
+	}
+	// scheme is checked first, so that a missing signature isn't reported for schemes that can't be recovered
+	verifier, err := signer.NewVerifier(scheme)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := verifier.(signer.PubKeyRecoverer); !ok {
+		return nil, fmt.Errorf("%w: %s", signer.ErrNotRecoverable, scheme)
+	}
+	// without aggregator set, the only signature is made by the proposer
+	idx := 0
+	if sh.Validators != nil && len(sh.Validators.Validators) > 0 {
+		i, _ := sh.Validators.GetByAddress(sh.ProposerAddress)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %X not in aggregator set", ErrUnauthorizedProposer, sh.ProposerAddress)
+		}
+		idx = int(i)
+	}
+	if idx >= len(sh.Commit.Signatures) || len(sh.Commit.Signatures[idx]) == 0 {
+		return nil, fmt.Errorf("%w: %X didn't sign", ErrUnauthorizedProposer, sh.ProposerAddress)
+	}
+	msg, err := sh.Header.MarshalBinary()
+	if err != nil {
+		return nil, errors.New("signature verification failed, unable to marshal header")
+	}
+	pubKey, err := signer.RecoverPubKey(scheme, msg, sh.Commit.Signatures[idx])
+	if errors.Is(err, signer.ErrNotRecoverable) || errors.Is(err, signer.ErrUnsupportedScheme) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSignatureVerificationFailed, err)
+	}
+	if address := pubKey.Address(); !bytes.Equal(address, sh.ProposerAddress) {
+		return nil, fmt.Errorf("%w: signed by %X instead of %X", ErrUnauthorizedProposer, address, sh.ProposerAddress)
+	}
+	return pubKey, nil
+}
+
+// verifySignatures verifies all signatures of the commit and returns the voting power of aggregators that
+// signed the header, and the total voting power of the set. Signatures are ordered like aggregators in the set;
+// empty signature means that aggregator didn't sign. If aggregators have no voting power, every aggregator
+// has the same weight.
+func (sh *SignedHeader) verifySignatures() (signed int64, total int64, err error) {
+	msg, err := sh.Header.MarshalBinary()
+	if err != nil {
+		return 0, 0, errors.New("signature verification failed, unable to marshal header")
+	}
+	total, weight := sh.votingPower()
+	if sh.verified.matches(sh, msg) {
+		return sh.verified.signed, total, nil
+	}
+	if len(sh.Commit.AggregatedSignature) > 0 {
+		signed, err = sh.verifyAggregatedSignature(msg, weight)
+		return signed, total, err
+	}
+	if len(sh.Commit.Signatures) != len(sh.Validators.Validators) {
+		return 0, 0, fmt.Errorf("%w: got %d, expected %d", ErrCommitSignaturesCount, len(sh.Commit.Signatures), len(sh.Validators.Validators))
+	}
+	signatures := 0
+	for i, signature := range sh.Commit.Signatures {
+		if len(signature) == 0 {
+			continue
+		}
+		val := sh.Validators.Validators[i]
+		if !signer.VerifySignature(val.PubKey, msg, signature) {
+			return 0, 0, fmt.Errorf("%w: aggregator %s", ErrSignatureVerificationFailed, val.Address)
+		}
+		signed += weight(val)
+		signatures++
+	}
+	if signatures == 0 {
+		return 0, 0, fmt.Errorf("%w: no signatures", ErrSignatureVerificationFailed)
+	}
+	return signed, total, nil
+}
+
+// votingPower returns the total voting power of the aggregator set, and the weight of an aggregator. If aggregators
+// have no voting power, every aggregator has the same weight.
+func (sh *SignedHeader) votingPower() (int64, func(*cmtypes.Validator) int64) {
+	total := sh.Validators.TotalVotingPower()
+	if total == 0 {
+		return int64(len(sh.Validators.Validators)), func(*cmtypes.Validator) int64 { return 1 }
+	}
+	return total, func(val *cmtypes.Validator) int64 { return val.VotingPower }
+}
+
+// verifyAggregatedSignature verifies aggregated BLS signature of the commit against aggregated keys of
+// aggregators marked as signers, and returns their voting power.
+func (sh *SignedHeader) verifyAggregatedSignature(msg []byte, weight func(*cmtypes.Validator) int64) (int64, error) {
+	if len(sh.AggregatorKeys) != len(sh.Validators.Validators) {
+		return 0, fmt.Errorf("%w: got %d aggregator keys, expected %d", ErrSignatureVerificationFailed, len(sh.AggregatorKeys), len(sh.Validators.Validators))
+	}
+	if len(sh.Commit.Signers) != (len(sh.Validators.Validators)+7)/8 {
+		return 0, fmt.Errorf("%w: invalid size of signers bit array", ErrSignatureVerificationFailed)
+	}
+	var signed int64
+	keys := make([]bls.PubKey, 0, len(sh.AggregatorKeys))
+	for i, val := range sh.Validators.Validators {
+		if sh.Commit.Signed(i) {
+			keys = append(keys, sh.AggregatorKeys[i])
+			signed += weight(val)
+		}
+	}
+	if len(keys) == 0 {
+		return 0, fmt.Errorf("%w: no signatures", ErrSignatureVerificationFailed)
+	}
+	if !bls.VerifyAggregateSignature(keys, msg, sh.Commit.AggregatedSignature) {
+		return 0, fmt.Errorf("%w: invalid aggregated signature", ErrSignatureVerificationFailed)
+	}
+	return signed, nil
+}
+
 var _ header.Header[*SignedHeader] = &SignedHeader{}
+
+// validateValidityProof checks that the validity proof in the commit matches the commitment in the header.
+func (sh *SignedHeader) validateValidityProof() error {
+	if len(sh.ValidityProofHash) == 0 && len(sh.Commit.ValidityProof) == 0 {
+		return nil
+	}
+	if !bytes.Equal(ValidityProofHash(sh.Commit.ValidityProof), sh.ValidityProofHash[:]) {
+		return ErrValidityProofHashMismatch
+	}
+	return nil
+}
@@ -6,8 +6,13 @@ import (
 
 	"github.com/celestiaorg/go-header"
 	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/crypto/bls"
+	"github.com/rollkit/rollkit/signer"
 )
 
 func TestSignedHeader(t *testing.T) {
@@ -138,6 +143,27 @@ func testValidateBasic(t *testing.T, untrustedAdj *SignedHeader, privKey ed25519
 			},
 			err: ErrNoProposerAddress,
 		},
+		{
+			prepare: func() (*SignedHeader, bool) {
+				untrusted := *untrustedAdj
+				proof := []byte("validity proof")
+				untrusted.ValidityProofHash = ValidityProofHash(proof)
+				commit, err := getCommit(untrusted.Header, privKey)
+				require.NoError(t, err)
+				commit.ValidityProof = proof
+				untrusted.Commit = *commit
+				return &untrusted, false
+			},
+			err: nil,
+		},
+		{
+			prepare: func() (*SignedHeader, bool) {
+				untrusted := *untrustedAdj
+				untrusted.Commit.ValidityProof = []byte("validity proof")
+				return &untrusted, false
+			},
+			err: ErrValidityProofHashMismatch,
+		},
 	}
 
 	for testIndex, test := range tests {
@@ -166,3 +192,176 @@ func testValidateBasic(t *testing.T, untrustedAdj *SignedHeader, privKey ed25519
 		})
 	}
 }
+
+func TestVerifyCommit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	keys := make([]ed25519.PrivKey, 3)
+	vals := make([]*cmtypes.Validator, len(keys))
+	for i := range keys {
+		keys[i] = ed25519.GenPrivKey()
+		vals[i] = cmtypes.NewValidator(keys[i].PubKey(), 1)
+	}
+	valSet := cmtypes.NewValidatorSet(vals)
+
+	sh := &SignedHeader{Header: GetRandomHeader(), Validators: valSet}
+	sh.ProposerAddress = valSet.Proposer.Address
+	sh.AggregatorsHash = valSet.Hash()
+	msg, err := sh.Header.MarshalBinary()
+	require.NoError(err)
+
+	// signatures are ordered like aggregators in the set
+	sign := func(signers ...int) {
+		sh.Commit.Signatures = make([]Signature, valSet.Size())
+		for _, i := range signers {
+			idx, _ := valSet.GetByAddress(keys[i].PubKey().Address())
+			sig, err := keys[i].Sign(msg)
+			require.NoError(err)
+			sh.Commit.Signatures[idx] = sig
+		}
+	}
+
+	sign(0, 1, 2)
+	assert.NoError(sh.ValidateBasic())
+	assert.NoError(sh.VerifyCommit(DefaultCommitThreshold))
+
+	// 2/3 of voting power is not more than 2/3
+	sign(0, 1)
+	assert.NoError(sh.ValidateBasic())
+	assert.ErrorIs(sh.VerifyCommit(DefaultCommitThreshold), ErrInsufficientVotingPower)
+	assert.NoError(sh.VerifyCommit(cmtmath.Fraction{Numerator: 1, Denominator: 2}))
+
+	sign()
+	assert.ErrorIs(sh.ValidateBasic(), ErrSignatureVerificationFailed)
+
+	sign(0, 1, 2)
+	sh.Commit.Signatures = sh.Commit.Signatures[:2]
+	assert.ErrorIs(sh.ValidateBasic(), ErrCommitSignaturesCount)
+
+	sign(0, 1, 2)
+	sh.Commit.Signatures[0], sh.Commit.Signatures[1] = sh.Commit.Signatures[1], sh.Commit.Signatures[0]
+	assert.ErrorIs(sh.VerifyCommit(DefaultCommitThreshold), ErrSignatureVerificationFailed)
+}
+
+func TestVerifyProposer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sh, _, err := GetRandomSignedHeader()
+	require.NoError(err)
+	assert.NoError(sh.VerifyProposer())
+
+	outsider := *sh
+	outsider.ProposerAddress = ed25519.GenPrivKey().PubKey().Address()
+	assert.ErrorIs(outsider.VerifyProposer(), ErrUnauthorizedProposer)
+
+	unsigned := *sh
+	unsigned.Commit = Commit{Signatures: []Signature{nil}}
+	assert.ErrorIs(unsigned.VerifyProposer(), ErrUnauthorizedProposer)
+
+	based := *sh
+	based.Validators = nil
+	assert.NoError(based.VerifyProposer())
+}
+
+func TestRecoverProposer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sh, _, err := NewGenerator(1, WithSignatureScheme(signer.SchemeSecp256k1), WithNumValidators(3)).SignedHeader()
+	require.NoError(err)
+	pubKey, err := sh.RecoverProposer(signer.SchemeSecp256k1)
+	require.NoError(err)
+	assert.Equal(sh.ProposerAddress, []byte(pubKey.Address()))
+	_, val := sh.Validators.GetByAddress(sh.ProposerAddress)
+	assert.Equal(val.PubKey, pubKey)
+
+	outsider := *sh
+	outsider.ProposerAddress = ed25519.GenPrivKey().PubKey().Address()
+	_, err = outsider.RecoverProposer(signer.SchemeSecp256k1)
+	assert.ErrorIs(err, ErrUnauthorizedProposer)
+
+	// without aggregator set, proposer is verified only against the signature
+	based, _, err := NewGenerator(2, WithSignatureScheme(signer.SchemeSecp256k1)).SignedHeader()
+	require.NoError(err)
+	based.Validators = nil
+	_, err = based.RecoverProposer(signer.SchemeSecp256k1)
+	assert.NoError(err)
+	based.ProposerAddress = outsider.ProposerAddress
+	_, err = based.RecoverProposer(signer.SchemeSecp256k1)
+	assert.ErrorIs(err, ErrUnauthorizedProposer)
+
+	unsigned := *sh
+	unsigned.Commit = Commit{Signatures: make([]Signature, len(sh.Commit.Signatures))}
+	_, err = unsigned.RecoverProposer(signer.SchemeSecp256k1)
+	assert.ErrorIs(err, ErrUnauthorizedProposer)
+
+	ed25519Header, _, err := GetRandomSignedHeader()
+	require.NoError(err)
+	_, err = ed25519Header.RecoverProposer(signer.SchemeEd25519)
+	assert.ErrorIs(err, signer.ErrNotRecoverable)
+}
+
+func TestVerifyAggregatedCommit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	vals := make([]*cmtypes.Validator, 4)
+	for i := range vals {
+		vals[i] = cmtypes.NewValidator(ed25519.GenPrivKey().PubKey(), 1)
+	}
+	valSet := cmtypes.NewValidatorSet(vals)
+	blsKeys := make([]bls.PrivKey, valSet.Size())
+	aggregatorKeys := make([]bls.PubKey, valSet.Size())
+	var err error
+	for i := range blsKeys {
+		blsKeys[i], err = bls.GenPrivKey()
+		require.NoError(err)
+		aggregatorKeys[i] = blsKeys[i].PubKey()
+	}
+
+	sh := &SignedHeader{Header: GetRandomHeader(), Validators: valSet, AggregatorKeys: aggregatorKeys}
+	sh.ProposerAddress = valSet.Proposer.Address
+	sh.AggregatorsHash = valSet.Hash()
+	sh.AggregatorKeysHash = AggregatorKeysHash(aggregatorKeys)
+	msg, err := sh.Header.MarshalBinary()
+	require.NoError(err)
+
+	sign := func(signers ...int) {
+		signatures := make([]Signature, valSet.Size())
+		for _, i := range signers {
+			signatures[i] = blsKeys[i].Sign(msg)
+		}
+		commit, err := AggregateCommit(signatures)
+		require.NoError(err)
+		sh.Commit = *commit
+	}
+
+	sign(0, 1, 2, 3)
+	assert.NoError(sh.ValidateBasic())
+	assert.NoError(sh.VerifyCommit(DefaultCommitThreshold))
+
+	sign(0, 2)
+	assert.NoError(sh.ValidateBasic())
+	assert.ErrorIs(sh.VerifyCommit(DefaultCommitThreshold), ErrInsufficientVotingPower)
+
+	// signers don't match the aggregated signature
+	sh.Commit.Signers[0] |= 1 << 1
+	assert.ErrorIs(sh.ValidateBasic(), ErrSignatureVerificationFailed)
+
+	// serialization preserves aggregated signature and keys
+	sign(0, 1, 3)
+	bin, err := sh.MarshalBinary()
+	require.NoError(err)
+	var decoded SignedHeader
+	require.NoError(decoded.UnmarshalBinary(bin))
+	assert.NoError(decoded.ValidateBasic())
+	assert.NoError(decoded.VerifyCommit(DefaultCommitThreshold))
+
+	sh.AggregatorKeys = append([]bls.PubKey{}, aggregatorKeys...)
+	rogue, err := bls.GenPrivKey()
+	require.NoError(err)
+	sh.AggregatorKeys[0] = rogue.PubKey()
+	assert.ErrorIs(sh.ValidateBasic(), ErrAggregatorKeysHashMismatch)
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxStateExportChunkSize is the maximal size of a single chunk of state export stream. Application snapshot
+// chunks are usually a few megabytes.
+const MaxStateExportChunkSize = 64 * 1024 * 1024
+
+// ErrInvalidStateExport is returned when state export stream is truncated, or a chunk doesn't match its hash.
+var ErrInvalidStateExport = errors.New("invalid state export")
+
+// StateExportChunkKind identifies contents of a chunk of state export stream.
+type StateExportChunkKind byte
+
+// Kinds of chunks of state export stream. The stream starts with a manifest, followed by application snapshot
+// chunks, and ends with the end marker.
+const (
+	StateExportManifest StateExportChunkKind = iota + 1
+	StateExportAppChunk
+	stateExportEnd
+)
+
+// StateExportWriter writes state export stream: a sequence of chunks, each one encoded as its kind, length
+// (uvarint), data and SHA-256 hash of the data. The stream ends with a chunk holding the hash of hashes of all
+// chunks, so truncated or reordered streams are detected. The format is agnostic to the storage of the
+// application (e.g. IAVL), as application state is carried in opaque ABCI snapshot chunks.
+type StateExportWriter struct {
+	w     io.Writer
+	total [sha256.Size]byte
+}
+
+// NewStateExportWriter returns a writer of state export stream to w.
+func NewStateExportWriter(w io.Writer) *StateExportWriter {
+	return &StateExportWriter{w: w}
+}
+
+// WriteChunk writes a chunk of given kind.
+func (sw *StateExportWriter) WriteChunk(kind StateExportChunkKind, data []byte) error {
+	if kind == stateExportEnd {
+		return fmt.Errorf("%w: end marker is written by Close", ErrInvalidStateExport)
+	}
+	return sw.writeChunk(kind, data)
+}
+
+// Close writes the end marker of the stream. It doesn't close the underlying writer.
+func (sw *StateExportWriter) Close() error {
+	return sw.writeChunk(stateExportEnd, sw.total[:])
+}
+
+func (sw *StateExportWriter) writeChunk(kind StateExportChunkKind, data []byte) error {
+	if len(data) > MaxStateExportChunkSize {
+		return fmt.Errorf("%w: chunk has %d bytes, maximum is %d", ErrSizeLimitExceeded, len(data), MaxStateExportChunkSize)
+	}
+	hash := sha256.Sum256(data)
+	buf := binary.AppendUvarint([]byte{byte(kind)}, uint64(len(data)))
+	for _, b := range [][]byte{buf, data, hash[:]} {
+		if _, err := sw.w.Write(b); err != nil {
+			return err
+		}
+	}
+	sw.total = sha256.Sum256(append(sw.total[:], hash[:]...))
+	return nil
+}
+
+// StateExportReader reads and verifies state export stream.
+type StateExportReader struct {
+	r     *bufio.Reader
+	total [sha256.Size]byte
+	done  bool
+}
+
+// NewStateExportReader returns a reader of state export stream from r.
+func NewStateExportReader(r io.Reader) *StateExportReader {
+	return &StateExportReader{r: bufio.NewReader(r)}
+}
+
+// ReadChunk returns the next chunk of the stream, after verifying its hash. It returns io.EOF after the end
+// marker, and ErrInvalidStateExport if the stream ends without the marker, or is corrupted.
+func (sr *StateExportReader) ReadChunk() (StateExportChunkKind, []byte, error) {
+	if sr.done {
+		return 0, nil, io.EOF
+	}
+	kind, err := sr.r.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: stream ended without end marker: %v", ErrInvalidStateExport, err)
+	}
+	size, err := binary.ReadUvarint(sr.r)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: failed to read chunk size: %v", ErrInvalidStateExport, err)
+	}
+	if size > MaxStateExportChunkSize {
+		return 0, nil, fmt.Errorf("%w: chunk has %d bytes, maximum is %d", ErrSizeLimitExceeded, size, MaxStateExportChunkSize)
+	}
+	data := make([]byte, size+sha256.Size)
+	if _, err := io.ReadFull(sr.r, data); err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated chunk: %v", ErrInvalidStateExport, err)
+	}
+	data, expected := data[:size], data[size:]
+	hash := sha256.Sum256(data)
+	if !bytes.Equal(hash[:], expected) {
+		return 0, nil, fmt.Errorf("%w: hash of chunk doesn't match: %X != %X", ErrInvalidStateExport, hash, expected)
+	}
+
+	switch StateExportChunkKind(kind) {
+	case stateExportEnd:
+		if !bytes.Equal(data, sr.total[:]) {
+			return 0, nil, fmt.Errorf("%w: hash of chunks doesn't match: %X != %X", ErrInvalidStateExport, sr.total, data)
+		}
+		sr.done = true
+		return 0, nil, io.EOF
+	case StateExportManifest, StateExportAppChunk:
+		sr.total = sha256.Sum256(append(sr.total[:], hash[:]...))
+		return StateExportChunkKind(kind), data, nil
+	default:
+		return 0, nil, fmt.Errorf("%w: unknown chunk kind %d", ErrInvalidStateExport, kind)
+	}
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateExportStream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	w := NewStateExportWriter(&buf)
+	require.NoError(w.WriteChunk(StateExportManifest, []byte("manifest")))
+	require.NoError(w.WriteChunk(StateExportAppChunk, []byte("chunk 0")))
+	require.NoError(w.WriteChunk(StateExportAppChunk, nil))
+	assert.ErrorIs(w.WriteChunk(stateExportEnd, nil), ErrInvalidStateExport)
+	require.NoError(w.Close())
+	stream := buf.Bytes()
+
+	r := NewStateExportReader(bytes.NewReader(stream))
+	expected := []struct {
+		kind StateExportChunkKind
+		data []byte
+	}{
+		{StateExportManifest, []byte("manifest")},
+		{StateExportAppChunk, []byte("chunk 0")},
+		{StateExportAppChunk, []byte{}},
+	}
+	for _, e := range expected {
+		kind, data, err := r.ReadChunk()
+		require.NoError(err)
+		assert.Equal(e.kind, kind)
+		assert.Equal(e.data, data)
+	}
+	_, _, err := r.ReadChunk()
+	assert.ErrorIs(err, io.EOF)
+	_, _, err = r.ReadChunk()
+	assert.ErrorIs(err, io.EOF)
+
+	readAll := func(stream []byte) error {
+		r := NewStateExportReader(bytes.NewReader(stream))
+		for {
+			if _, _, err := r.ReadChunk(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	// corrupted chunk
+	corrupted := bytes.Clone(stream)
+	corrupted[3] ^= 1
+	assert.ErrorIs(readAll(corrupted), ErrInvalidStateExport)
+
+	// stream without end marker
+	end := len(stream) - (1 + 1 + 32 + 32)
+	assert.ErrorIs(readAll(stream[:end]), ErrInvalidStateExport)
+	assert.ErrorIs(readAll(stream[:len(stream)-1]), ErrInvalidStateExport)
+
+	// chunk missing in the stream
+	manifest := 1 + 1 + len("manifest") + 32
+	withoutManifest := stream[manifest:]
+	assert.ErrorIs(readAll(withoutManifest), ErrInvalidStateExport)
+}
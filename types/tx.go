@@ -1,6 +1,8 @@
 package types
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/cometbft/cometbft/crypto/merkle"
@@ -49,6 +51,32 @@ type TxProof struct {
 	Proof    merkle.Proof     `json:"proof"`
 }
 
+// TxInclusionProof is a proof of inclusion of a transaction in the block at Height, verifiable against DataHash
+// of the header of the block. It's served by full nodes (see `tx_proof` RPC method) and verified by light clients.
+type TxInclusionProof struct {
+	Hash     cmbytes.HexBytes `json:"hash"`
+	Height   int64            `json:"height"`
+	Index    uint32           `json:"index"`
+	DataHash cmbytes.HexBytes `json:"data_hash"`
+	Proof    TxProof          `json:"proof"`
+}
+
+// Validate verifies that the proof is valid and its root is equal to dataHash computed with DefaultMerkleHasher.
+func (tp TxProof) Validate(dataHash []byte) error {
+	return tp.ValidateWith(dataHash, DefaultMerkleHasher)
+}
+
+// ValidateWith verifies that the proof is valid and its root is equal to dataHash computed with the hasher.
+func (tp TxProof) ValidateWith(dataHash []byte, hasher MerkleHasher) error {
+	if err := hasher.Validate(); err != nil {
+		return err
+	}
+	if !bytes.Equal(dataHash, tp.RootHash) {
+		return errors.New("proof root hash doesn't match data hash")
+	}
+	return hasher.VerifyProof(tp.Proof, tp.RootHash, hasher.Sum(tp.Data))
+}
+
 // ToTxsWithISRs converts a slice of transactions and a list of intermediate state roots
 // to a slice of TxWithISRs. Note that the length of intermediateStateRoots is
 // equal to the length of txs + 1.
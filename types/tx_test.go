@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"testing"
 
+	cmtypes "github.com/cometbft/cometbft/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -111,3 +112,30 @@ func checkSubArray(txList []rollkit.TxWithISRs, subTxList []rollkit.TxWithISRs)
 	}
 	return false, nil
 }
+
+func TestDataTxProof(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	data := &Data{Txs: Txs{Tx("tx1"), Tx("tx2"), Tx("tx3")}}
+	hash, err := data.Hash()
+	require.NoError(err)
+	// without ISRs, data hash is compatible with ABCI
+	abciTxs := cmtypes.Txs{cmtypes.Tx("tx1"), cmtypes.Tx("tx2"), cmtypes.Tx("tx3")}
+	assert.EqualValues(abciTxs.Hash(), hash)
+
+	data.IntermediateStateRoots.RawRootsList = [][]byte{GetRandomBytes(32), GetRandomBytes(32), GetRandomBytes(32), GetRandomBytes(32)}
+	hash, err = data.Hash()
+	require.NoError(err)
+	for i := range data.Txs {
+		proof, err := data.TxProof(i)
+		require.NoError(err)
+		assert.NoError(proof.Validate(hash))
+		assert.Error(proof.Validate(GetRandomBytes(32)))
+
+		proof.Data = Tx("other")
+		assert.Error(proof.Validate(hash))
+	}
+	_, err = data.TxProof(len(data.Txs))
+	assert.Error(err)
+}
@@ -1,11 +1,17 @@
 package types
 
 import (
+	"context"
+	stded25519 "crypto/ed25519"
+	"fmt"
 	"math/rand"
 	"time"
 
 	"github.com/cometbft/cometbft/crypto/ed25519"
 	cmtypes "github.com/cometbft/cometbft/types"
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	"github.com/rollkit/rollkit/signer"
 )
 
 // TestChainID is a constant used for testing purposes. It represents a mock chain ID.
@@ -33,69 +39,19 @@ func GetRandomValidatorSetWithPrivKey() (*cmtypes.ValidatorSet, ed25519.PrivKey)
 
 // GetRandomBlock returns a block with random data
 func GetRandomBlock(height uint64, nTxs int) *Block {
-	header := GetRandomHeader()
-	header.BaseHeader.Height = height
-	block := &Block{
-		SignedHeader: SignedHeader{
-			Header: header,
-		},
-		Data: Data{
-			Txs: make(Txs, nTxs),
-			IntermediateStateRoots: IntermediateStateRoots{
-				RawRootsList: make([][]byte, nTxs),
-			},
-		},
-	}
-
-	block.SignedHeader.AppHash = GetRandomBytes(32)
-
-	for i := 0; i < nTxs; i++ {
-		block.Data.Txs[i] = GetRandomTx()
-		block.Data.IntermediateStateRoots.RawRootsList[i] = GetRandomBytes(32)
-	}
-
-	// TODO(tzdybal): see https://github.com/rollkit/rollkit/issues/143
-	if nTxs == 0 {
-		block.Data.Txs = nil
-		block.Data.IntermediateStateRoots.RawRootsList = nil
-	}
-
-	return block
+	return randomGenerator().Block(height, nTxs)
 }
 
 // GetRandomHeader returns a header with random fields and current time
 func GetRandomHeader() Header {
-	return Header{
-		BaseHeader: BaseHeader{
-			Height:  uint64(rand.Int63()), //nolint:gosec,
-			Time:    uint64(time.Now().UnixNano()),
-			ChainID: TestChainID,
-		},
-		Version: Version{
-			Block: InitStateVersion.Consensus.Block,
-			App:   InitStateVersion.Consensus.App,
-		},
-		LastHeaderHash:  GetRandomBytes(32),
-		LastCommitHash:  GetRandomBytes(32),
-		DataHash:        GetRandomBytes(32),
-		ConsensusHash:   GetRandomBytes(32),
-		AppHash:         GetRandomBytes(32),
-		LastResultsHash: GetRandomBytes(32),
-		ProposerAddress: GetRandomBytes(32),
-		AggregatorsHash: GetRandomBytes(32),
-	}
+	return randomGenerator().Header()
 }
 
 // GetRandomNextHeader returns a header with random data and height of +1 from
 // the provided Header
 func GetRandomNextHeader(header Header) Header {
-	nextHeader := GetRandomHeader()
-	nextHeader.BaseHeader.Height = header.Height() + 1
+	nextHeader := randomGenerator().NextHeader(header)
 	nextHeader.BaseHeader.Time = uint64(time.Now().Add(1 * time.Second).UnixNano())
-	nextHeader.LastHeaderHash = header.Hash()
-	nextHeader.ProposerAddress = header.ProposerAddress
-	nextHeader.AggregatorsHash = header.AggregatorsHash
-	nextHeader.NextAggregatorsHash = header.NextAggregatorsHash
 	return nextHeader
 }
 
@@ -136,18 +92,31 @@ func GetRandomNextSignedHeader(signedHeader *SignedHeader, privKey ed25519.PrivK
 	return newSignedHeader, nil
 }
 
+// GetRandomDuplicateHeaderEvidence returns evidence of equivocation of the aggregator of a random signed header.
+func GetRandomDuplicateHeaderEvidence() (*DuplicateHeaderEvidence, error) {
+	signedHeader, privKey, err := GetRandomSignedHeader()
+	if err != nil {
+		return nil, err
+	}
+	conflicting := *signedHeader
+	conflicting.AppHash = GetRandomBytes(32)
+	commit, err := getCommit(conflicting.Header, privKey)
+	if err != nil {
+		return nil, err
+	}
+	conflicting.Commit = *commit
+	return NewDuplicateHeaderEvidence(signedHeader, &conflicting)
+}
+
 // GetRandomTx returns a transaction with a random size between 100 and 200
 // bytes.
 func GetRandomTx() Tx {
-	size := rand.Int()%100 + 100 //nolint:gosec
-	return Tx(GetRandomBytes(size))
+	return randomGenerator().Tx()
 }
 
 // GetRandomBytes returns a byte slice of random bytes of length n.
 func GetRandomBytes(n int) []byte {
-	data := make([]byte, n)
-	_, _ = rand.Read(data) //nolint:gosec,staticcheck
-	return data
+	return randomGenerator().Bytes(n)
 }
 
 func getCommit(header Header, privKey ed25519.PrivKey) (*Commit, error) {
@@ -163,3 +132,244 @@ func getCommit(header Header, privKey ed25519.PrivKey) (*Commit, error) {
 		Signatures: []Signature{sign},
 	}, nil
 }
+
+// Generator generates test data deterministically from a source of randomness, so property tests and fuzzing
+// corpora are reproducible from a seed. Generator is not safe for concurrent use.
+type Generator struct {
+	rand          *rand.Rand
+	chainID       string
+	numValidators int
+	scheme        string
+	time          time.Time
+}
+
+// GeneratorOption configures data generated by Generator.
+type GeneratorOption func(*Generator)
+
+// WithChainID sets chain ID of generated headers (TestChainID by default).
+func WithChainID(chainID string) GeneratorOption {
+	return func(g *Generator) {
+		g.chainID = chainID
+	}
+}
+
+// WithNumValidators sets the number of aggregators in generated validator sets (1 by default).
+func WithNumValidators(n int) GeneratorOption {
+	return func(g *Generator) {
+		g.numValidators = n
+	}
+}
+
+// WithSignatureScheme sets signature scheme of generated keys (signer.SchemeEd25519 by default).
+func WithSignatureScheme(scheme string) GeneratorOption {
+	return func(g *Generator) {
+		g.scheme = scheme
+	}
+}
+
+// WithTime sets time of generated headers (GeneratorTime by default).
+func WithTime(t time.Time) GeneratorOption {
+	return func(g *Generator) {
+		g.time = t
+	}
+}
+
+// GeneratorTime is the default time of headers generated by Generator.
+var GeneratorTime = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewGenerator creates a Generator seeded with the seed.
+func NewGenerator(seed int64, opts ...GeneratorOption) *Generator {
+	return NewGeneratorWithRand(rand.New(rand.NewSource(seed)), opts...) //nolint:gosec
+}
+
+// NewGeneratorWithRand creates a Generator using the source of randomness.
+func NewGeneratorWithRand(r *rand.Rand, opts ...GeneratorOption) *Generator {
+	g := &Generator{
+		rand:          r,
+		chainID:       TestChainID,
+		numValidators: 1,
+		scheme:        signer.SchemeEd25519,
+		time:          GeneratorTime,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// randomGenerator returns a randomly seeded Generator of headers with current time, used by GetRandom* functions.
+func randomGenerator() *Generator {
+	return NewGenerator(rand.Int63(), WithTime(time.Now())) //nolint:gosec
+}
+
+// Bytes returns a byte slice of random bytes of length n.
+func (g *Generator) Bytes(n int) []byte {
+	data := make([]byte, n)
+	_, _ = g.rand.Read(data)
+	return data
+}
+
+// Tx returns a transaction with a random size between 100 and 200 bytes.
+func (g *Generator) Tx() Tx {
+	return Tx(g.Bytes(g.rand.Intn(100) + 100))
+}
+
+// Header returns a header with random fields and random height.
+func (g *Generator) Header() Header {
+	return Header{
+		BaseHeader: BaseHeader{
+			Height:  uint64(g.rand.Int63()),
+			Time:    uint64(g.time.UnixNano()),
+			ChainID: g.chainID,
+		},
+		Version: Version{
+			Block: InitStateVersion.Consensus.Block,
+			App:   InitStateVersion.Consensus.App,
+		},
+		LastHeaderHash:  g.Bytes(32),
+		LastCommitHash:  g.Bytes(32),
+		DataHash:        g.Bytes(32),
+		ConsensusHash:   g.Bytes(32),
+		AppHash:         g.Bytes(32),
+		LastResultsHash: g.Bytes(32),
+		ProposerAddress: g.Bytes(32),
+		AggregatorsHash: g.Bytes(32),
+	}
+}
+
+// NextHeader returns a header with random data, height of +1 and time of +1 second from the provided header.
+func (g *Generator) NextHeader(header Header) Header {
+	nextHeader := g.Header()
+	nextHeader.BaseHeader.Height = header.Height() + 1
+	nextHeader.BaseHeader.Time = uint64(header.Time().Add(1 * time.Second).UnixNano())
+	nextHeader.LastHeaderHash = header.Hash()
+	nextHeader.ProposerAddress = header.ProposerAddress
+	nextHeader.AggregatorsHash = header.AggregatorsHash
+	nextHeader.NextAggregatorsHash = header.NextAggregatorsHash
+	return nextHeader
+}
+
+// Block returns a block with random data, at given height.
+func (g *Generator) Block(height uint64, nTxs int) *Block {
+	header := g.Header()
+	header.BaseHeader.Height = height
+	block := &Block{
+		SignedHeader: SignedHeader{
+			Header: header,
+		},
+		Data: Data{
+			Txs: make(Txs, nTxs),
+			IntermediateStateRoots: IntermediateStateRoots{
+				RawRootsList: make([][]byte, nTxs),
+			},
+		},
+	}
+
+	block.SignedHeader.AppHash = g.Bytes(32)
+
+	for i := 0; i < nTxs; i++ {
+		block.Data.Txs[i] = g.Tx()
+		block.Data.IntermediateStateRoots.RawRootsList[i] = g.Bytes(32)
+	}
+
+	// TODO(tzdybal): see https://github.com/rollkit/rollkit/issues/143
+	if nTxs == 0 {
+		block.Data.Txs = nil
+		block.Data.IntermediateStateRoots.RawRootsList = nil
+	}
+
+	return block
+}
+
+// PrivKey returns a private key of the configured signature scheme.
+func (g *Generator) PrivKey() (crypto.PrivKey, error) {
+	switch g.scheme {
+	case signer.SchemeEd25519:
+		return crypto.UnmarshalEd25519PrivateKey(stded25519.NewKeyFromSeed(g.Bytes(stded25519.SeedSize)))
+	case signer.SchemeSecp256k1:
+		return crypto.UnmarshalSecp256k1PrivateKey(g.Bytes(32))
+	default:
+		return nil, fmt.Errorf("%w: %s", signer.ErrUnsupportedScheme, g.scheme)
+	}
+}
+
+// ValidatorSet returns a set of the configured number of aggregators with equal voting power, and their private
+// keys, ordered like aggregators in the set.
+func (g *Generator) ValidatorSet() (*cmtypes.ValidatorSet, []crypto.PrivKey, error) {
+	keys := make(map[string]crypto.PrivKey, g.numValidators)
+	vals := make([]*cmtypes.Validator, g.numValidators)
+	for i := range vals {
+		key, err := g.PrivKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		pubKey, err := signer.CometPubKey(key.GetPublic())
+		if err != nil {
+			return nil, nil, err
+		}
+		vals[i] = cmtypes.NewValidator(pubKey, 1)
+		keys[string(pubKey.Address())] = key
+	}
+	valSet := cmtypes.NewValidatorSet(vals)
+	privKeys := make([]crypto.PrivKey, len(valSet.Validators))
+	for i, val := range valSet.Validators {
+		privKeys[i] = keys[string(val.Address)]
+	}
+	return valSet, privKeys, nil
+}
+
+// SignedHeader returns a header with random data, signed by all aggregators of a generated validator set, and
+// private keys of the aggregators.
+func (g *Generator) SignedHeader() (*SignedHeader, []crypto.PrivKey, error) {
+	valSet, keys, err := g.ValidatorSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	signedHeader := &SignedHeader{
+		Header:     g.Header(),
+		Validators: valSet,
+	}
+	signedHeader.Header.ProposerAddress = valSet.Proposer.Address
+	signedHeader.Header.AggregatorsHash = valSet.Hash()
+	signedHeader.Header.NextAggregatorsHash = valSet.Hash()
+	commit, err := signCommit(signedHeader.Header, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	signedHeader.Commit = *commit
+	return signedHeader, keys, nil
+}
+
+// NextSignedHeader returns a header with random data and height of +1 from the provided signed header, signed by
+// the same aggregators.
+func (g *Generator) NextSignedHeader(signedHeader *SignedHeader, keys []crypto.PrivKey) (*SignedHeader, error) {
+	newSignedHeader := &SignedHeader{
+		Header:     g.NextHeader(signedHeader.Header),
+		Validators: signedHeader.Validators,
+	}
+	newSignedHeader.LastCommitHash = signedHeader.Commit.GetCommitHash(
+		&newSignedHeader.Header, signedHeader.ProposerAddress,
+	)
+	commit, err := signCommit(newSignedHeader.Header, keys)
+	if err != nil {
+		return nil, err
+	}
+	newSignedHeader.Commit = *commit
+	return newSignedHeader, nil
+}
+
+// signCommit creates commit with signatures of the header made by all keys.
+func signCommit(header Header, keys []crypto.PrivKey) (*Commit, error) {
+	headerBytes, err := header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	commit := &Commit{Signatures: make([]Signature, len(keys))}
+	for i, key := range keys {
+		commit.Signatures[i], err = signer.NewLocalSigner(key).Sign(context.Background(), header.Height(), headerBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return commit, nil
+}
@@ -2,6 +2,11 @@ package types
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rollkit/rollkit/signer"
 )
 
 func TestGetRandomTx(t *testing.T) {
@@ -55,3 +60,28 @@ func TestGetRandomHeader(t *testing.T) {
 		headerSet[headerHash] = true
 	}
 }
+
+func TestGeneratorDeterministic(t *testing.T) {
+	for _, scheme := range []string{signer.SchemeEd25519, signer.SchemeSecp256k1} {
+		t.Run(scheme, func(t *testing.T) {
+			opts := []GeneratorOption{WithNumValidators(3), WithSignatureScheme(scheme), WithChainID("gen")}
+			g1, g2 := NewGenerator(42, opts...), NewGenerator(42, opts...)
+
+			assert.Equal(t, g1.Block(10, 5), g2.Block(10, 5))
+
+			sh1, keys1, err := g1.SignedHeader()
+			require.NoError(t, err)
+			sh2, _, err := g2.SignedHeader()
+			require.NoError(t, err)
+			assert.Equal(t, sh1.Hash(), sh2.Hash())
+			assert.Equal(t, sh1.Commit, sh2.Commit)
+			assert.Equal(t, "gen", sh1.ChainID())
+			assert.Len(t, sh1.Validators.Validators, 3)
+			require.NoError(t, sh1.ValidateBasic())
+
+			next, err := g1.NextSignedHeader(sh1, keys1)
+			require.NoError(t, err)
+			require.NoError(t, sh1.Verify(next))
+		})
+	}
+}